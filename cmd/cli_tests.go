@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/tests"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CLITests covers the command line contract: exit codes and configuration loading
+type CLITests struct {
+	*tests.BaseTestSuite
+}
+
+// NewCLITests creates a new CLI test suite
+func NewCLITests(client *registry.Client, logger *logrus.Logger) tests.TestSuite {
+	suite := &CLITests{
+		BaseTestSuite: tests.NewBaseTestSuite("CLI", client, logger),
+	}
+
+	suite.setupTests()
+	return suite
+}
+
+func init() {
+	tests.Register("CLI", NewCLITests)
+}
+
+func (s *CLITests) setupTests() {
+	s.AddTest("Exit Codes", "Test persistent registry failures exit with the code of their status", s.testExitCodes)
+	s.AddTest("Config Paths", "Test relative paths in a config file resolve against its directory", s.testConfigPaths)
+}
+
+func (s *CLITests) testExitCodes(ctx context.Context) error {
+	cases := []struct {
+		status int
+		want   int
+	}{
+		{http.StatusTooManyRequests, exitRateLimited},
+		{http.StatusServiceUnavailable, exitError},
+		{http.StatusBadGateway, exitError},
+		{http.StatusNotFound, exitNotFound},
+	}
+
+	for _, tc := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		}))
+
+		client, err := registry.NewClient(
+			registry.WithBaseURL(server.URL),
+			registry.WithLogger(s.Logger()),
+			func(c *registry.ClientConfig) {
+				c.MaxRetries = 2
+				c.RetryWaitMin = time.Millisecond
+				c.RetryWaitMax = time.Millisecond
+			},
+		)
+		if err != nil {
+			server.Close()
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		_, err = client.Providers.GetLatest(ctx, "hashicorp", "random")
+		server.Close()
+		if err == nil {
+			return fmt.Errorf("status %d: expected an error", tc.status)
+		}
+		if code := exitCodeFor(err); code != tc.want {
+			return fmt.Errorf("status %d: expected exit code %d, got %d for %v", tc.status, tc.want, code, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *CLITests) testConfigPaths(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "terralense-config-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	project := filepath.Join(dir, "project")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		return err
+	}
+	configFile := filepath.Join(project, configFileName)
+	content := "pins_file: pins.yaml\ncache_dir: ../shared-cache\ndemo:\n  scenario: scenarios/vpc.yaml\n"
+	if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+		return err
+	}
+
+	// The config file is loaded from outside its directory
+	config := &Config{ConfigFile: configFile}
+	if err := applyConfigSources(config); err != nil {
+		return fmt.Errorf("failed to apply config file: %w", err)
+	}
+
+	for name, got := range map[string][2]string{
+		"pins_file":     {filepath.Join(project, "pins.yaml"), config.PinsFile},
+		"demo.scenario": {filepath.Join(project, "scenarios", "vpc.yaml"), config.ScenarioFile},
+		"cache_dir":     {filepath.Join(dir, "shared-cache"), config.CacheDir},
+	} {
+		if got[0] != got[1] {
+			return fmt.Errorf("%s: expected %s, got %s", name, got[0], got[1])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileName is the name of the user and project config files
+const configFileName = ".terralense.yaml"
+
+// FileConfig is the structure of a .terralense.yaml config file
+type FileConfig struct {
+	BaseURL  string `yaml:"base_url"`
+	LogLevel string `yaml:"log_level"`
+	Timeout  string `yaml:"timeout"`
+
+	// Tokens maps registry hostnames to API tokens
+	Tokens map[string]string `yaml:"tokens"`
+
+	RateLimit struct {
+		Requests int    `yaml:"requests"`
+		Period   string `yaml:"period"`
+	} `yaml:"rate_limit"`
+
+	Output struct {
+		Format string `yaml:"format"`
+	} `yaml:"output"`
+
+	Tests struct {
+		Suite string `yaml:"suite"`
+		Case  string `yaml:"case"`
+	} `yaml:"tests"`
+
+	PinsFile string `yaml:"pins_file"`
+
+	// CacheDir is the directory of the disk response cache
+	CacheDir string `yaml:"cache_dir"`
+
+	// CacheURL is the s3:// or gs:// bucket of a response cache shared between machines
+	CacheURL string `yaml:"cache_url"`
+
+	Demo struct {
+		Scenario string `yaml:"scenario"`
+	} `yaml:"demo"`
+}
+
+// Environment variables overriding config file values
+const (
+	envBaseURL    = "TERRALENSE_BASE_URL"
+	envLogLevel   = "TERRALENSE_LOG_LEVEL"
+	envTimeout    = "TERRALENSE_TIMEOUT"
+	envToken      = "TERRALENSE_TOKEN"
+	envRateLimit  = "TERRALENSE_RATE_LIMIT"
+	envRatePeriod = "TERRALENSE_RATE_PERIOD"
+	envOutput     = "TERRALENSE_OUTPUT"
+	envCacheDir   = "TERRALENSE_CACHE_DIR"
+	envCacheURL   = "TERRALENSE_CACHE_URL"
+)
+
+// configFilePaths returns the config files to load, lowest precedence first:
+// ~/.terralense.yaml, then the nearest .terralense.yaml in the working directory or its parents
+func configFilePaths() []string {
+	var paths []string
+
+	home, err := os.UserHomeDir()
+	userPath := ""
+	if err == nil {
+		userPath = filepath.Join(home, configFileName)
+		paths = append(paths, userPath)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return paths
+	}
+	for {
+		candidate := filepath.Join(dir, configFileName)
+		if candidate != userPath {
+			if _, err := os.Stat(candidate); err == nil {
+				paths = append(paths, candidate)
+				break
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return paths
+}
+
+// loadFileConfig reads a config file; a missing file returns nil without error
+func loadFileConfig(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fileConfig FileConfig
+	if err := yaml.Unmarshal(data, &fileConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return &fileConfig, nil
+}
+
+// applyConfigSources layers config files and environment variables under the flags.
+// Precedence, highest first: explicitly set flags, environment variables, the project
+// config file, the user config file, built-in defaults. An explicit -config file
+// replaces the user and project files.
+func applyConfigSources(config *Config) error {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	paths := configFilePaths()
+	if config.ConfigFile != "" {
+		paths = []string{config.ConfigFile}
+	}
+
+	for _, path := range paths {
+		fileConfig, err := loadFileConfig(path)
+		if err != nil {
+			return err
+		}
+		if fileConfig == nil {
+			if path == config.ConfigFile {
+				return fmt.Errorf("config file %s not found", path)
+			}
+			continue
+		}
+		if err := applyFileConfig(config, fileConfig, filepath.Dir(path), explicit); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnvConfig(config, explicit); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// applyFileConfig applies the values set in a config file to fields not set by flags.
+// Relative paths in the file are relative to dir, the directory of the file.
+func applyFileConfig(config *Config, fileConfig *FileConfig, dir string, explicit map[string]bool) error {
+	setString(&config.BaseURL, fileConfig.BaseURL, !explicit["base-url"])
+	setString(&config.LogLevel, fileConfig.LogLevel, !explicit["log-level"])
+	setString(&config.OutputFormat, fileConfig.Output.Format, !explicit["output"])
+	setString(&config.TestSuite, fileConfig.Tests.Suite, !explicit["suite"])
+	setString(&config.TestCase, fileConfig.Tests.Case, !explicit["test"])
+	setString(&config.PinsFile, resolvePath(fileConfig.PinsFile, dir), !explicit["pins-file"])
+	setString(&config.ScenarioFile, resolvePath(fileConfig.Demo.Scenario, dir), !explicit["scenario"])
+	setString(&config.CacheDir, resolvePath(fileConfig.CacheDir, dir), !explicit["cache-dir"])
+	setString(&config.CacheURL, fileConfig.CacheURL, !explicit["cache-url"])
+
+	if fileConfig.Timeout != "" && !explicit["timeout"] {
+		timeout, err := time.ParseDuration(fileConfig.Timeout)
+		if err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		config.Timeout = timeout
+	}
+
+	if fileConfig.RateLimit.Requests > 0 && !explicit["rate-limit"] {
+		config.RateLimit = fileConfig.RateLimit.Requests
+	}
+
+	if fileConfig.RateLimit.Period != "" && !explicit["rate-period"] {
+		period, err := time.ParseDuration(fileConfig.RateLimit.Period)
+		if err != nil {
+			return fmt.Errorf("rate_limit.period: %w", err)
+		}
+		config.RatePeriod = period
+	}
+
+	for host, token := range fileConfig.Tokens {
+		if config.Tokens == nil {
+			config.Tokens = make(map[string]string)
+		}
+		config.Tokens[host] = token
+	}
+
+	return nil
+}
+
+// applyEnvConfig applies TERRALENSE_* environment variables to fields not set by flags
+func applyEnvConfig(config *Config, explicit map[string]bool) error {
+	setString(&config.BaseURL, os.Getenv(envBaseURL), !explicit["base-url"])
+	setString(&config.LogLevel, os.Getenv(envLogLevel), !explicit["log-level"])
+	setString(&config.OutputFormat, os.Getenv(envOutput), !explicit["output"])
+	setString(&config.Token, os.Getenv(envToken), true)
+	setString(&config.CacheDir, expandHome(os.Getenv(envCacheDir)), !explicit["cache-dir"])
+	setString(&config.CacheURL, os.Getenv(envCacheURL), !explicit["cache-url"])
+
+	if value := os.Getenv(envTimeout); value != "" && !explicit["timeout"] {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", envTimeout, err)
+		}
+		config.Timeout = timeout
+	}
+
+	if value := os.Getenv(envRateLimit); value != "" && !explicit["rate-limit"] {
+		requests, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", envRateLimit, err)
+		}
+		config.RateLimit = requests
+	}
+
+	if value := os.Getenv(envRatePeriod); value != "" && !explicit["rate-period"] {
+		period, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", envRatePeriod, err)
+		}
+		config.RatePeriod = period
+	}
+
+	return nil
+}
+
+// tokenForBaseURL returns the API token for the base URL's host. An explicit
+// TERRALENSE_TOKEN takes precedence over host-keyed tokens from config files.
+func tokenForBaseURL(config *Config) string {
+	if config.Token != "" {
+		return config.Token
+	}
+
+	u, err := url.Parse(config.BaseURL)
+	if err != nil {
+		return ""
+	}
+
+	return config.Tokens[u.Host]
+}
+
+// setString sets *dst to value when value is non-empty and ok is true
+func setString(dst *string, value string, ok bool) {
+	if ok && value != "" {
+		*dst = value
+	}
+}
+
+// expandHome expands a leading ~/ in path to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
+
+// resolvePath expands a leading ~/ in path and makes a relative path relative to dir
+func resolvePath(path, dir string) string {
+	path = expandHome(path)
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/tests"
+)
+
+// Exit codes are a stable contract for automation wrapping the CLI
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitValidation   = 2
+	exitNotFound     = 3
+	exitRateLimited  = 4
+	exitNetwork      = 5
+	exitMaintenance  = 6
+	exitTestFailures = 10
+	exitChecksFailed = 11
+)
+
+// Error kinds reported in the JSON error on stderr, one per exit code
+var exitKinds = map[int]string{
+	exitError:        "error",
+	exitValidation:   "validation",
+	exitNotFound:     "not_found",
+	exitRateLimited:  "rate_limited",
+	exitNetwork:      "network",
+	exitMaintenance:  "maintenance",
+	exitTestFailures: "test_failures",
+	exitChecksFailed: "checks_failed",
+}
+
+// errUsage marks invalid command line usage or configuration
+var errUsage = errors.New("invalid usage")
+
+// errNotFound marks a requested item, such as a test suite, that does not exist
+var errNotFound = errors.New("not found")
+
+// errChecksFailed marks a doctor run in which a check failed
+var errChecksFailed = errors.New("doctor checks failed")
+
+// usageErrorf returns an error classified as invalid usage
+func usageErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// cliError is the machine-readable error written to stderr before a non-zero exit
+type cliError struct {
+	Code       int    `json:"code"`
+	Kind       string `json:"kind"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Field      string `json:"field,omitempty"`
+	RetryAfter int    `json:"retry_after_seconds,omitempty"`
+}
+
+// exitCodeFor maps an error to its exit code
+func exitCodeFor(err error) int {
+	var multi *registry.MultiError
+	if errors.As(err, &multi) && len(multi.Errors) > 0 {
+		// A multi-error is as specific as its errors when they all agree
+		code := exitCodeFor(multi.Errors[0])
+		for _, e := range multi.Errors[1:] {
+			if exitCodeFor(e) != code {
+				return exitError
+			}
+		}
+		return code
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errChecksFailed):
+		return exitChecksFailed
+	case errors.Is(err, errUsage), errors.Is(err, registry.ErrInvalidConfiguration), registry.IsValidationError(err):
+		return exitValidation
+	case errors.Is(err, errNotFound), registry.IsNotFound(err):
+		return exitNotFound
+	case registry.IsRateLimited(err):
+		return exitRateLimited
+	case registry.IsMaintenance(err):
+		return exitMaintenance
+	case registry.IsTimeout(err), errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return exitNetwork
+	}
+
+	var requestErr *registry.RequestError
+	if errors.As(err, &requestErr) {
+		return exitNetwork
+	}
+
+	return exitError
+}
+
+// exitWithError writes err as JSON to stderr and exits with its exit code.
+// It does nothing when err is nil.
+func exitWithError(err error) {
+	if err == nil {
+		return
+	}
+	exit(exitCodeFor(err), err)
+}
+
+// exitOnTestFailures exits with exitTestFailures when any test failed
+func exitOnTestFailures(results *tests.TestResults) {
+	if results.Failed > 0 {
+		exit(exitTestFailures, fmt.Errorf("%d of %d tests failed", results.Failed, results.Total))
+	}
+}
+
+// exit writes the error report for err to stderr and exits with code
+func exit(code int, err error) {
+	report := cliError{
+		Code:    code,
+		Kind:    exitKinds[code],
+		Message: err.Error(),
+	}
+
+	var apiErr *registry.APIError
+	if errors.As(err, &apiErr) {
+		report.StatusCode = apiErr.StatusCode
+	}
+	var validationErr *registry.ValidationError
+	if errors.As(err, &validationErr) {
+		report.Field = validationErr.Field
+	}
+	var maintenanceErr *registry.MaintenanceError
+	if errors.As(err, &maintenanceErr) {
+		report.StatusCode = maintenanceErr.StatusCode
+		report.RetryAfter = int(maintenanceErr.RetryAfter.Seconds())
+	}
+
+	encoder := json.NewEncoder(os.Stderr)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(report)
+	os.Exit(code)
+}
@@ -4,11 +4,11 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/TahirRiaz/terralens-registry-client/output"
 	"github.com/TahirRiaz/terralens-registry-client/registry"
 	"github.com/TahirRiaz/terralens-registry-client/tests"
 
@@ -17,6 +17,7 @@ import (
 
 // Config holds the application configuration
 type Config struct {
+	ConfigFile   string
 	Mode         string
 	LogLevel     string
 	Timeout      time.Duration
@@ -24,10 +25,24 @@ type Config struct {
 	RateLimit    int
 	RatePeriod   time.Duration
 	OutputFormat string
+	// Token is the API token from TERRALENSE_TOKEN; Tokens are host-keyed tokens from config files
+	Token  string
+	Tokens map[string]string
 	// Test-specific configurations
 	TestSuite string
 	TestCase  string
 	ListTests bool
+	// Pin list configurations
+	PinsFile      string
+	PinNote       string
+	PinConstraint string
+	PinVersion    string
+	// CacheDir is the directory of the disk response cache; empty disables it
+	CacheDir string
+	// CacheURL is the s3:// or gs:// bucket of a shared response cache; empty disables it
+	CacheURL string
+	// ScenarioFile is the demo scenario file; empty runs the built-in scenario
+	ScenarioFile string
 }
 
 func main() {
@@ -36,9 +51,15 @@ func main() {
 	// Setup logger
 	logger := setupLogger(config.LogLevel)
 
+	// Results go to stdout in the requested format; logs and errors go to stderr
+	out, err := output.New(config.OutputFormat, os.Stdout)
+	if err != nil {
+		exitWithError(usageErrorf("%v", err))
+	}
+
 	// Handle list tests request
 	if config.ListTests {
-		listAvailableTests()
+		exitWithError(listAvailableTests(out))
 		return
 	}
 
@@ -46,48 +67,75 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
 
+	// Doctor runs without a client so that it can report configuration the client rejects
+	if config.Mode == "doctor" {
+		exitWithError(runDoctor(ctx, config, out))
+		return
+	}
+
 	// Create client
 	client, err := createClient(config, logger)
 	if err != nil {
-		log.Fatalf("Failed to create registry client: %v", err)
+		exitWithError(fmt.Errorf("failed to create registry client: %w", err))
 	}
 
 	// Run based on mode
 	switch config.Mode {
 	case "demo":
-		runDemo(ctx, client, logger)
+		runDemo(ctx, client, logger, config, out)
 	case "test":
-		runTests(ctx, client, logger, config)
+		runTests(ctx, client, logger, config, out)
+	case "pins":
+		exitWithError(runPins(ctx, client, logger, config, out, flag.Args()))
+	case "api":
+		// Regenerates tests/testdata/api.snapshot
+		fmt.Print(registry.InterfaceSnapshot())
 	case "all":
-		runDemo(ctx, client, logger)
-		fmt.Println("\n" + strings.Repeat("=", 80) + "\n")
-		runTests(ctx, client, logger, config)
+		runDemo(ctx, client, logger, config, out)
+		out.Text("\n%s\n\n", strings.Repeat("=", 80))
+		runTests(ctx, client, logger, config, out)
 	default:
-		log.Fatalf("Unknown mode: %s", config.Mode)
+		exitWithError(usageErrorf("unknown mode %q (expected demo, test, pins, doctor, api or all)", config.Mode))
 	}
 }
 
 func parseFlags() *Config {
 	config := &Config{}
 
-	flag.StringVar(&config.Mode, "mode", "demo", "Run mode: demo, test, or all")
+	flag.StringVar(&config.ConfigFile, "config", "", "Config file (default ~/.terralense.yaml and the nearest project .terralense.yaml)")
+	flag.StringVar(&config.Mode, "mode", "demo", "Run mode: demo, test, pins, doctor, api, or all")
 	flag.StringVar(&config.LogLevel, "log-level", "info", "Log level: debug, info, warn, error")
 	flag.DurationVar(&config.Timeout, "timeout", 5*time.Minute, "Request timeout")
 	flag.StringVar(&config.BaseURL, "base-url", registry.DefaultBaseURL, "Registry base URL")
 	flag.IntVar(&config.RateLimit, "rate-limit", 100, "Rate limit requests per period")
 	flag.DurationVar(&config.RatePeriod, "rate-period", time.Minute, "Rate limit period")
-	flag.StringVar(&config.OutputFormat, "output", "table", "Output format: table, json, yaml")
+	flag.StringVar(&config.CacheDir, "cache-dir", "", "Disk cache directory for registry responses (default no disk cache)")
+	flag.StringVar(&config.CacheURL, "cache-url", "", "Shared response cache bucket, s3://bucket/prefix or gs://bucket/prefix (default none)")
+	flag.StringVar(&config.OutputFormat, "output", "table", "Output format: table, json, yaml, csv")
 
 	// Test-specific flags
 	flag.StringVar(&config.TestSuite, "suite", "", "Run specific test suite (e.g., 'Modules', 'Providers')")
 	flag.StringVar(&config.TestCase, "test", "", "Run specific test case (requires -suite)")
 	flag.BoolVar(&config.ListTests, "list-tests", false, "List all available test suites and cases")
 
+	// Pin list flags
+	flag.StringVar(&config.PinsFile, "pins-file", "", "Pins file (.json or .yaml, default ~/.terralense/pins.yaml)")
+	flag.StringVar(&config.PinNote, "pin-note", "", "Note for 'pins add'")
+	flag.StringVar(&config.PinConstraint, "pin-constraint", "", "Desired version constraint for 'pins add'")
+	flag.StringVar(&config.PinVersion, "pin-version", "", "Known version for 'pins add'")
+
+	// Demo flags
+	flag.StringVar(&config.ScenarioFile, "scenario", "", "Demo scenario file (.yaml, default built-in Azure VNet scenario)")
+
 	flag.Parse()
 
+	if err := applyConfigSources(config); err != nil {
+		exitWithError(fmt.Errorf("%w: %v", errUsage, err))
+	}
+
 	// Validate test-specific flags
 	if config.TestCase != "" && config.TestSuite == "" {
-		log.Fatal("Error: -test flag requires -suite flag to be specified")
+		exitWithError(usageErrorf("-test flag requires -suite flag (or tests.suite in the config file) to be specified"))
 	}
 
 	return config
@@ -115,70 +163,85 @@ func setupLogger(level string) *logrus.Logger {
 }
 
 func createClient(config *Config, logger *logrus.Logger) (*registry.Client, error) {
-	return registry.NewClient(
+	opts := []registry.ClientOption{
 		registry.WithBaseURL(config.BaseURL),
 		registry.WithLogger(logger),
-		registry.WithTimeout(30*time.Second),
+		registry.WithTimeout(30 * time.Second),
 		registry.WithRateLimit(config.RateLimit, config.RatePeriod),
 		registry.WithUserAgent("terralens-registry-client/1.0"),
-	)
+		registry.WithAPIToken(tokenForBaseURL(config)),
+		registry.WithNegativeCache(0, 0),
+	}
+
+	switch {
+	case config.CacheURL != "" && config.CacheDir != "":
+		return nil, fmt.Errorf("%w: cache_dir and cache_url cannot both be set", errUsage)
+	case config.CacheURL != "":
+		store, prefix, err := registry.OpenBlobStore(config.CacheURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cache_url: %v", errUsage, err)
+		}
+		opts = append(opts, registry.WithCache(registry.NewBlobCache(store, &registry.BlobCacheOptions{Prefix: prefix}), 0))
+	case config.CacheDir != "":
+		cache, err := registry.NewDiskCache(config.CacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cache_dir: %v", errUsage, err)
+		}
+		opts = append(opts, registry.WithCache(cache, 0))
+	}
+
+	return registry.NewClient(opts...)
 }
 
-func runDemo(ctx context.Context, client *registry.Client, logger *logrus.Logger) {
-	fmt.Println("=== Terraform Registry Client Demo ===")
-	fmt.Println("Running Azure VNet Resources Demo")
-	fmt.Println(strings.Repeat("=", 50) + "\n")
+func runDemo(ctx context.Context, client *registry.Client, logger *logrus.Logger, config *Config, out output.Renderer) {
+	scenario, err := loadScenario(config.ScenarioFile)
+	if err != nil {
+		exitWithError(err)
+	}
+
+	out.Text("=== Terraform Registry Client Demo ===\n")
+	out.Text("Running %s\n", scenario.Name)
+	out.Text("%s\n\n", strings.Repeat("=", 50))
 
-	demo := NewAzureVNetDemo(client, logger)
+	demo := NewScenarioDemo(client, logger, scenario, out)
 
 	if err := demo.Run(ctx); err != nil {
-		logger.Errorf("Demo failed: %v", err)
-		os.Exit(1)
+		exitWithError(fmt.Errorf("demo failed: %w", err))
 	}
 }
 
-func runTests(ctx context.Context, client *registry.Client, logger *logrus.Logger, config *Config) {
-	fmt.Println("=== Terraform Registry Client Test Suite ===")
+func runTests(ctx context.Context, client *registry.Client, logger *logrus.Logger, config *Config, out output.Renderer) {
+	out.Text("=== Terraform Registry Client Test Suite ===\n")
 
 	// Create test runner
 	runner := tests.NewTestRunner(client, logger)
+	runner.SetRenderer(out)
 
 	// Register all test suites
 	allSuites := registerAllTestSuites(runner, client, logger)
 
 	// Check if specific suite/test requested
 	if config.TestSuite != "" {
-		runSpecificTests(ctx, runner, allSuites, config)
+		runSpecificTests(ctx, runner, allSuites, config, out)
 		return
 	}
 
 	// Run all tests
-	fmt.Println("Running comprehensive tests")
-	fmt.Println(strings.Repeat("=", 50) + "\n")
+	out.Text("Running comprehensive tests\n")
+	out.Text("%s\n\n", strings.Repeat("=", 50))
 
 	results := runner.RunAll(ctx)
 
 	// Print results
-	runner.PrintResults(results)
+	exitWithError(runner.PrintResults(results))
 
 	// Exit with error if tests failed
-	if results.Failed > 0 {
-		os.Exit(1)
-	}
+	exitOnTestFailures(results)
 }
 
 func registerAllTestSuites(runner *tests.TestRunner, client *registry.Client, logger *logrus.Logger) map[string]tests.TestSuite {
-	suites := make(map[string]tests.TestSuite)
-
-	// Create all test suites
-	suites["Modules"] = tests.NewModuleTests(client, logger)
-	suites["Providers"] = tests.NewProviderTests(client, logger)
-	suites["Policies"] = tests.NewPolicyTests(client, logger)
-	suites["Search"] = tests.NewSearchTests(client, logger)
-	suites["Validation"] = tests.NewValidationTests(client, logger)
-	suites["Error Handling"] = tests.NewErrorTests(client, logger)
-	suites["Performance"] = tests.NewPerformanceTests(client, logger)
-	suites["Subcategory"] = tests.NewSubcategoryTests(client, logger)
+	// Create every suite registered with tests.Register, including downstream suites
+	suites := tests.NewSuites(client, logger)
 
 	// Register with runner
 	for name, suite := range suites {
@@ -188,37 +251,35 @@ func registerAllTestSuites(runner *tests.TestRunner, client *registry.Client, lo
 	return suites
 }
 
-func runSpecificTests(ctx context.Context, runner *tests.TestRunner, allSuites map[string]tests.TestSuite, config *Config) {
+func runSpecificTests(ctx context.Context, runner *tests.TestRunner, allSuites map[string]tests.TestSuite, config *Config, out output.Renderer) {
 	// Find the requested suite
 	suite, exists := allSuites[config.TestSuite]
 	if !exists {
-		fmt.Printf("Error: Test suite '%s' not found\n\n", config.TestSuite)
-		fmt.Println("Available test suites:")
-		for name := range allSuites {
-			fmt.Printf("  - %s\n", name)
+		out.Text("Error: Test suite '%s' not found\n\n", config.TestSuite)
+		out.Text("Available test suites:\n")
+		for _, name := range tests.RegisteredSuites() {
+			out.Text("  - %s\n", name)
 		}
-		os.Exit(1)
+		exitWithError(fmt.Errorf("test suite %q %w", config.TestSuite, errNotFound))
 	}
 
 	// If specific test case requested
 	if config.TestCase != "" {
-		runSingleTest(ctx, runner, suite, config.TestSuite, config.TestCase)
+		runSingleTest(ctx, runner, suite, config.TestSuite, config.TestCase, out)
 		return
 	}
 
 	// Run all tests in the suite
-	fmt.Printf("Running all tests in suite: %s\n", config.TestSuite)
-	fmt.Println(strings.Repeat("=", 50) + "\n")
+	out.Text("Running all tests in suite: %s\n", config.TestSuite)
+	out.Text("%s\n\n", strings.Repeat("=", 50))
 
 	results := runner.RunSuite(ctx, config.TestSuite, suite)
-	runner.PrintResults(results)
+	exitWithError(runner.PrintResults(results))
 
-	if results.Failed > 0 {
-		os.Exit(1)
-	}
+	exitOnTestFailures(results)
 }
 
-func runSingleTest(ctx context.Context, runner *tests.TestRunner, suite tests.TestSuite, suiteName, testName string) {
+func runSingleTest(ctx context.Context, runner *tests.TestRunner, suite tests.TestSuite, suiteName, testName string, out output.Renderer) {
 	// Find the specific test
 	var targetTest *tests.TestCase
 	for _, test := range suite.Tests() {
@@ -229,29 +290,27 @@ func runSingleTest(ctx context.Context, runner *tests.TestRunner, suite tests.Te
 	}
 
 	if targetTest == nil {
-		fmt.Printf("Error: Test case '%s' not found in suite '%s'\n\n", testName, suiteName)
-		fmt.Printf("Available tests in %s suite:\n", suiteName)
+		out.Text("Error: Test case '%s' not found in suite '%s'\n\n", testName, suiteName)
+		out.Text("Available tests in %s suite:\n", suiteName)
 		for _, test := range suite.Tests() {
-			fmt.Printf("  - %s\n", test.Name)
+			out.Text("  - %s\n", test.Name)
 		}
-		os.Exit(1)
+		exitWithError(fmt.Errorf("test case %q in suite %q %w", testName, suiteName, errNotFound))
 	}
 
 	// Run the single test
-	fmt.Printf("Running single test: %s/%s\n", suiteName, testName)
-	fmt.Println(strings.Repeat("=", 50) + "\n")
+	out.Text("Running single test: %s/%s\n", suiteName, testName)
+	out.Text("%s\n\n", strings.Repeat("=", 50))
 
 	results := runner.RunSingleTest(ctx, suiteName, *targetTest)
-	runner.PrintResults(results)
+	exitWithError(runner.PrintResults(results))
 
-	if results.Failed > 0 {
-		os.Exit(1)
-	}
+	exitOnTestFailures(results)
 }
 
-func listAvailableTests() {
-	fmt.Println("=== Available Test Suites and Cases ===")
-	fmt.Println()
+// listAvailableTests prints every registered suite and test case
+func listAvailableTests(out output.Renderer) error {
+	out.Text("=== Available Test Suites and Cases ===\n")
 
 	// Create a dummy client and logger just to get test suite info
 	logger := logrus.New()
@@ -261,29 +320,30 @@ func listAvailableTests() {
 	allSuites := registerAllTestSuites(runner, client, logger)
 
 	// List all suites and their tests
-	for suiteName, suite := range allSuites {
-		fmt.Printf("%s:\n", suiteName)
-		for _, test := range suite.Tests() {
-			fmt.Printf("  - %s", test.Name)
-			if test.Description != "" {
-				fmt.Printf(" (%s)", test.Description)
-			}
-			fmt.Println()
+	table := output.Table{
+		Name:    "tests",
+		Columns: []string{"suite", "test", "description"},
+	}
+	for _, suiteName := range tests.RegisteredSuites() {
+		for _, test := range allSuites[suiteName].Tests() {
+			table.Rows = append(table.Rows, []any{suiteName, test.Name, test.Description})
 		}
-		fmt.Println()
+	}
+	if err := out.Table(table); err != nil {
+		return err
 	}
 
 	// Print usage examples
-	fmt.Println("Usage Examples:")
-	fmt.Println("  # Run all tests")
-	fmt.Println("  go run . -mode=test")
-	fmt.Println()
-	fmt.Println("  # Run all tests in a specific suite")
-	fmt.Println("  go run . -mode=test -suite=\"Modules\"")
-	fmt.Println()
-	fmt.Println("  # Run a specific test")
-	fmt.Println("  go run . -mode=test -suite=\"Modules\" -test=\"List Modules\"")
-	fmt.Println()
-	fmt.Println("  # Run with debug logging")
-	fmt.Println("  go run . -mode=test -suite=\"Providers\" -log-level=debug")
+	out.Text("\nUsage Examples:\n")
+	out.Text("  # Run all tests\n")
+	out.Text("  go run . -mode=test\n\n")
+	out.Text("  # Run all tests in a specific suite\n")
+	out.Text("  go run . -mode=test -suite=\"Modules\"\n\n")
+	out.Text("  # Run a specific test\n")
+	out.Text("  go run . -mode=test -suite=\"Modules\" -test=\"List Modules\"\n\n")
+	out.Text("  # Run with debug logging\n")
+	out.Text("  go run . -mode=test -suite=\"Providers\" -log-level=debug\n\n")
+	out.Text("  # Print results as JSON\n")
+	out.Text("  go run . -mode=test -suite=\"Providers\" -output=json\n")
+	return nil
 }
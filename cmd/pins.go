@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TahirRiaz/terralens-registry-client/output"
+	"github.com/TahirRiaz/terralens-registry-client/pins"
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runPins manages the pin list: pins [list|add|remove|seen|check] ...
+func runPins(ctx context.Context, client *registry.Client, logger *logrus.Logger, config *Config, out output.Renderer, args []string) error {
+	path := config.PinsFile
+	if path == "" {
+		defaultPath, err := pins.DefaultPath()
+		if err != nil {
+			return err
+		}
+		path = defaultPath
+	}
+
+	store, err := pins.Open(path)
+	if err != nil {
+		return err
+	}
+
+	command := "list"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "list":
+		return printPins(out, store.List())
+
+	case "add":
+		if len(args) != 2 {
+			return usageErrorf("pins add <provider|module> <address>")
+		}
+		pin := pins.Pin{
+			Kind:         pins.Kind(args[0]),
+			Address:      args[1],
+			Constraint:   config.PinConstraint,
+			Note:         config.PinNote,
+			KnownVersion: config.PinVersion,
+		}
+		if err := store.Add(pin); err != nil {
+			return err
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		out.Text("Pinned %s %s\n", pin.Kind, pin.Address)
+		return nil
+
+	case "remove":
+		if len(args) != 2 {
+			return usageErrorf("pins remove <provider|module> <address>")
+		}
+		if !store.Remove(pins.Kind(args[0]), args[1]) {
+			return fmt.Errorf("%s %s is not pinned: %w", args[0], args[1], errNotFound)
+		}
+		if err := store.Save(); err != nil {
+			return err
+		}
+		out.Text("Unpinned %s %s\n", args[0], args[1])
+		return nil
+
+	case "seen":
+		if len(args) != 3 {
+			return usageErrorf("pins seen <provider|module> <address> <version>")
+		}
+		if !store.MarkSeen(pins.Kind(args[0]), args[1], args[2]) {
+			return fmt.Errorf("%s %s is not pinned: %w", args[0], args[1], errNotFound)
+		}
+		return store.Save()
+
+	case "check":
+		table := output.Table{
+			Name:    "updates",
+			Columns: []string{"status", "kind", "address", "known"},
+			Empty:   "No pins",
+		}
+		for _, update := range store.CheckUpdates(ctx, client) {
+			status := "current"
+			switch {
+			case update.Err != nil:
+				logger.Warnf("Failed to check %s %s: %v", update.Pin.Kind, update.Pin.Address, update.Err)
+				status = "unknown"
+			case update.Changed:
+				status = "changed"
+			}
+			table.Rows = append(table.Rows, []any{status, string(update.Pin.Kind), update.Pin.Address, update.Pin.KnownVersion})
+		}
+		return out.Table(table)
+
+	default:
+		return usageErrorf("unknown pins command %q (expected list, add, remove, seen or check)", command)
+	}
+}
+
+// printPins prints the pin list as a table
+func printPins(out output.Renderer, list []pins.Pin) error {
+	table := output.Table{
+		Name:    "pins",
+		Columns: []string{"kind", "address", "constraint", "known", "note"},
+		Empty:   "No pins",
+	}
+	for _, pin := range list {
+		table.Rows = append(table.Rows, []any{string(pin.Kind), pin.Address, pin.Constraint, pin.KnownVersion, pin.Note})
+	}
+	return out.Table(table)
+}
@@ -0,0 +1,609 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/output"
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultScenario is the scenario run by -mode=demo when no -scenario file is given
+//
+//go:embed scenarios/azure-vnet.yaml
+var defaultScenario []byte
+
+// Scenario describes a demo run: module searches, providers to inspect and modules to show
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	// Keywords highlight module inputs and outputs whose names contain one of them
+	Keywords []string `yaml:"keywords"`
+
+	Search    *SearchStep    `yaml:"search"`
+	Providers []ProviderStep `yaml:"providers"`
+	Modules   *ModuleStep    `yaml:"modules"`
+}
+
+// SearchStep searches modules with several queries and shows the top results
+type SearchStep struct {
+	Queries []string `yaml:"queries"`
+
+	// Top is the number of results to show (default 5)
+	Top int `yaml:"top"`
+}
+
+// ProviderStep inspects a provider version and its resource documentation
+type ProviderStep struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+
+	// Version is the provider version (default latest)
+	Version string `yaml:"version"`
+
+	// Resources are resource slugs to check for documentation
+	Resources []string `yaml:"resources"`
+
+	// Examples are resource slugs whose configuration examples are shown
+	Examples []string `yaml:"examples"`
+}
+
+// ModuleStep shows the first candidate module that exists, falling back to a search
+type ModuleStep struct {
+	// Candidates are namespace/name/provider addresses tried in order
+	Candidates []string `yaml:"candidates"`
+
+	// FallbackQuery is searched when no candidate exists
+	FallbackQuery string `yaml:"fallback_query"`
+}
+
+// loadScenario reads a scenario file, or the built-in scenario when path is empty
+func loadScenario(path string) (*Scenario, error) {
+	data := defaultScenario
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("scenario file %s %w", path, errNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scenario file: %w", err)
+		}
+	}
+
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, usageErrorf("failed to parse scenario file %s: %v", path, err)
+	}
+
+	if err := scenario.validate(); err != nil {
+		return nil, usageErrorf("invalid scenario %q: %v", scenario.Name, err)
+	}
+
+	return &scenario, nil
+}
+
+// validate checks that the scenario has at least one step and well-formed addresses
+func (s *Scenario) validate() error {
+	if s.Search == nil && len(s.Providers) == 0 && s.Modules == nil {
+		return fmt.Errorf("scenario has no search, providers or modules steps")
+	}
+
+	if s.Search != nil && len(s.Search.Queries) == 0 {
+		return fmt.Errorf("search step has no queries")
+	}
+
+	for i, provider := range s.Providers {
+		if provider.Namespace == "" || provider.Name == "" {
+			return fmt.Errorf("providers[%d] needs a namespace and name", i)
+		}
+	}
+
+	if s.Modules != nil {
+		if len(s.Modules.Candidates) == 0 && s.Modules.FallbackQuery == "" {
+			return fmt.Errorf("modules step needs candidates or a fallback_query")
+		}
+		for _, candidate := range s.Modules.Candidates {
+			if _, _, _, ok := parseModuleAddress(candidate); !ok {
+				return fmt.Errorf("module candidate %q is not namespace/name/provider", candidate)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseModuleAddress splits a namespace/name/provider module address
+func parseModuleAddress(address string) (namespace, name, provider string, ok bool) {
+	parts := strings.Split(address, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// ScenarioDemo runs a demo scenario against the registry
+type ScenarioDemo struct {
+	client   *registry.Client
+	logger   *logrus.Logger
+	scenario *Scenario
+	out      output.Renderer
+}
+
+// NewScenarioDemo creates a demo for a scenario that prints with out
+func NewScenarioDemo(client *registry.Client, logger *logrus.Logger, scenario *Scenario, out output.Renderer) *ScenarioDemo {
+	return &ScenarioDemo{
+		client:   client,
+		logger:   logger,
+		scenario: scenario,
+		out:      out,
+	}
+}
+
+// Run executes the scenario steps in order
+func (d *ScenarioDemo) Run(ctx context.Context) error {
+	if d.scenario.Description != "" {
+		d.out.Text("%s\n", d.scenario.Description)
+	}
+
+	step := 0
+	heading := func(title string) {
+		step++
+		d.out.Text("\n%d. %s\n%s\n", step, title, strings.Repeat("-", 50))
+	}
+
+	if search := d.scenario.Search; search != nil {
+		heading("Searching for Modules")
+
+		modules, err := d.searchModules(ctx, search.Queries)
+		if err != nil {
+			return fmt.Errorf("module search failed: %w", err)
+		}
+
+		top := search.Top
+		if top <= 0 {
+			top = 5
+		}
+
+		if err := d.displayModuleResults(ctx, modules, top); err != nil {
+			return fmt.Errorf("failed to display module results: %w", err)
+		}
+	}
+
+	for _, provider := range d.scenario.Providers {
+		heading(fmt.Sprintf("Getting %s/%s Provider Documentation", provider.Namespace, provider.Name))
+
+		if err := d.getProviderDocs(ctx, provider); err != nil {
+			return fmt.Errorf("provider docs failed: %w", err)
+		}
+	}
+
+	if modules := d.scenario.Modules; modules != nil {
+		heading("Getting Module Example")
+
+		if err := d.getModule(ctx, modules); err != nil {
+			return fmt.Errorf("module example failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (d *ScenarioDemo) searchModules(ctx context.Context, queries []string) ([]registry.ModuleSearchResult, error) {
+	weighted := make([]registry.WeightedQuery, 0, len(queries))
+	for _, query := range queries {
+		d.logger.Infof("Searching for: %s", query)
+		weighted = append(weighted, registry.WeightedQuery{Query: query})
+	}
+
+	results, err := d.client.Modules.SearchExpanded(ctx, weighted)
+	if err != nil {
+		if results == nil {
+			return nil, err
+		}
+		d.logger.Warnf("Some searches failed: %v", err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no modules found")
+	}
+
+	return results, nil
+}
+
+func (d *ScenarioDemo) displayModuleResults(ctx context.Context, results []registry.ModuleSearchResult, top int) error {
+	d.out.Text("\nFound %d unique modules. Top %d results:\n\n", len(results), top)
+
+	table := output.Table{
+		Name:    "modules",
+		Columns: []string{"module", "version", "downloads", "verified", "relevance"},
+	}
+	for _, result := range results[:min(top, len(results))] {
+		table.Rows = append(table.Rows, []any{
+			fmt.Sprintf("%s/%s/%s", result.Namespace, result.Name, result.Provider),
+			result.Version, result.Downloads, result.Verified, result.Relevance,
+		})
+	}
+	if err := d.out.Table(table); err != nil {
+		return err
+	}
+
+	// Get detailed configuration for the top result
+	if len(results) > 0 {
+		d.out.Text("\nGetting configuration details for top module...\n")
+		module, err := d.client.Modules.GetByID(ctx, results[0].ID)
+		if err != nil {
+			d.logger.Warnf("Failed to get module details: %v", err)
+			return nil
+		}
+
+		return d.displayModuleConfiguration(module)
+	}
+
+	return nil
+}
+
+func (d *ScenarioDemo) displayModuleConfiguration(module *registry.ModuleDetails) error {
+	d.out.Text("\nModule Configuration:\n%s\n", strings.Repeat("-", 40))
+
+	// Display example configuration if available
+	if len(module.Examples) > 0 && module.Examples[0].Readme != "" {
+		examples := registry.ExtractTerraformExamples(module.Examples[0].Readme)
+		if len(examples) > 0 {
+			d.out.Text("Example Usage:\n```hcl\n%s\n```\n", examples[0])
+		}
+	}
+
+	// Display key inputs
+	if len(module.Root.Inputs) > 0 {
+		return d.displayKeyInputs(module.Root.Inputs)
+	}
+	return nil
+}
+
+// matchesKeyword reports whether name contains one of the scenario keywords
+func (d *ScenarioDemo) matchesKeyword(name string) bool {
+	nameLower := strings.ToLower(name)
+	for _, keyword := range d.scenario.Keywords {
+		if strings.Contains(nameLower, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *ScenarioDemo) displayKeyInputs(inputs []registry.ModuleInput) error {
+	// Filter required and keyword inputs
+	var keyInputs []registry.ModuleInput
+	for _, input := range inputs {
+		if input.Required || input.Name == "name" || d.matchesKeyword(input.Name) {
+			keyInputs = append(keyInputs, input)
+		}
+	}
+
+	// Sort by required first, then by name
+	sort.Slice(keyInputs, func(i, j int) bool {
+		if keyInputs[i].Required != keyInputs[j].Required {
+			return keyInputs[i].Required
+		}
+		return keyInputs[i].Name < keyInputs[j].Name
+	})
+
+	table := output.Table{
+		Name:    "key_inputs",
+		Title:   "Key Inputs",
+		Columns: []string{"name", "type", "required", "description"},
+	}
+
+	maxInputs := 10
+	for _, input := range keyInputs[:min(maxInputs, len(keyInputs))] {
+		table.Rows = append(table.Rows, []any{input.Name, input.Type, input.Required, truncate(input.Description, 50)})
+	}
+	if len(keyInputs) > maxInputs {
+		table.Footer = fmt.Sprintf("... and %d more inputs", len(keyInputs)-maxInputs)
+	}
+
+	return d.out.Table(table)
+}
+
+func (d *ScenarioDemo) getProviderDocs(ctx context.Context, step ProviderStep) error {
+	provider, err := d.client.Providers.Get(ctx, step.Namespace, step.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider %s/%s: %w", step.Namespace, step.Name, err)
+	}
+
+	// Get the version with its ID and published artifacts
+	version, err := d.client.Providers.GetVersionDetails(ctx, registry.ProviderRef{Namespace: step.Namespace, Name: step.Name}, step.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get provider version: %w", err)
+	}
+
+	err = d.out.Record("provider", []output.Field{
+		{Name: "provider", Value: provider.Attributes.FullName},
+		{Name: "namespace", Value: provider.Attributes.Namespace},
+		{Name: "downloads", Value: provider.Attributes.Downloads},
+		{Name: "tier", Value: provider.Attributes.Tier},
+		{Name: "version", Value: version.Provider.Version},
+		{Name: "published", Value: version.PublishedAt.Format("2006-01-02")},
+		{Name: "protocols", Value: version.Protocols},
+		{Name: "platforms", Value: len(version.Platforms)},
+		{Name: "documentation_pages", Value: version.DocsCount},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(step.Resources) == 0 && len(step.Examples) == 0 {
+		return nil
+	}
+
+	d.out.Text("\nFetching resource documentation...\n")
+
+	slugs, err := d.client.Providers.GetSlugIndex(ctx, version.VersionID)
+	if err != nil {
+		return fmt.Errorf("failed to get slug index: %w", err)
+	}
+
+	resources := append([]string(nil), step.Resources...)
+	for _, slug := range step.Examples {
+		if !contains(resources, slug) {
+			resources = append(resources, slug)
+		}
+	}
+
+	table := output.Table{
+		Name:    "resource_docs",
+		Columns: []string{"resource", "documented"},
+	}
+	docIDs := make(map[string]string, len(resources))
+	for _, slug := range resources {
+		docID, ok := slugs.Lookup("resources", slug)
+		if ok {
+			docIDs[slug] = docID
+		}
+		table.Rows = append(table.Rows, []any{step.Name + "_" + slug, ok})
+	}
+	if err := d.out.Table(table); err != nil {
+		return err
+	}
+
+	for _, slug := range step.Examples {
+		docID, ok := docIDs[slug]
+		if !ok {
+			continue
+		}
+
+		details, err := d.client.Providers.GetDoc(ctx, docID)
+		if err != nil {
+			d.logger.Warnf("Failed to get doc details: %v", err)
+			continue
+		}
+
+		d.displayProviderDocumentation(details)
+	}
+
+	return nil
+}
+
+func (d *ScenarioDemo) displayProviderDocumentation(details *registry.ProviderDocDetails) {
+	d.out.Text("\n%s Resource Documentation:\n%s\n", details.Data.Attributes.Title, strings.Repeat("-", 40))
+
+	// Extract configuration examples
+	examples := registry.ExtractTerraformExamples(details.Data.Attributes.Content)
+	if len(examples) > 0 {
+		// Limit example length for display
+		example := examples[0]
+		if len(example) > 500 {
+			example = example[:500] + "\n... (truncated)"
+		}
+		d.out.Text("Configuration Example:\n```hcl\n%s\n```\n", example)
+	}
+}
+
+func (d *ScenarioDemo) getModule(ctx context.Context, step *ModuleStep) error {
+	var module *registry.ModuleDetails
+
+	for _, candidate := range step.Candidates {
+		namespace, name, provider, _ := parseModuleAddress(candidate)
+		d.logger.Debugf("Checking module: %s", candidate)
+
+		found, err := d.client.Modules.GetLatest(ctx, namespace, name, provider)
+		if err == nil {
+			d.out.Text("✓ Found module: %s\n", candidate)
+			module = found
+			break
+		}
+
+		if registry.IsNotFound(err) {
+			d.out.Text("✗ Module not found: %s\n", candidate)
+		} else {
+			d.out.Text("✗ Error: %v\n", err)
+		}
+	}
+
+	if module == nil && step.FallbackQuery != "" {
+		d.out.Text("\nSearching for %q...\n", step.FallbackQuery)
+		results, err := d.client.Modules.SearchWithOptions(ctx, step.FallbackQuery, nil)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+
+		// Prefer the first verified result, then the first one
+		for _, result := range results {
+			if result.Verified {
+				if found, err := d.client.Modules.GetByID(ctx, result.ID); err == nil {
+					module = found
+					break
+				}
+			}
+		}
+		if module == nil && len(results) > 0 {
+			if found, err := d.client.Modules.GetByID(ctx, results[0].ID); err == nil {
+				module = found
+			}
+		}
+	}
+
+	if module == nil {
+		return fmt.Errorf("could not find any module for scenario %q", d.scenario.Name)
+	}
+
+	return d.displayModuleDetails(module)
+}
+
+func (d *ScenarioDemo) displayModuleDetails(module *registry.ModuleDetails) error {
+	d.out.Text("\n")
+	err := d.out.Record("module", []output.Field{
+		{Name: "module", Value: module.ID},
+		{Name: "source", Value: module.Source},
+		{Name: "version", Value: module.Version},
+		{Name: "downloads", Value: module.Downloads},
+		{Name: "verified", Value: module.Verified},
+		{Name: "description", Value: module.Description},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Display basic usage
+	d.out.Text("\nBasic Usage:\n%s\n", strings.Repeat("-", 50))
+	d.out.Text(`module %q {
+  source  = "%s"
+  version = "%s"
+
+  # Add your configuration here
+  # See module inputs below for required and optional variables
+}
+`, module.Name, module.Source, module.Version)
+
+	// Display inputs
+	if len(module.Root.Inputs) > 0 {
+		if err := d.displayModuleInputs(module.Root.Inputs); err != nil {
+			return err
+		}
+	}
+
+	// Display outputs
+	if len(module.Root.Outputs) > 0 {
+		return d.displayModuleOutputs(module.Root.Outputs)
+	}
+	return nil
+}
+
+func (d *ScenarioDemo) displayModuleInputs(inputs []registry.ModuleInput) error {
+	// Separate required and optional inputs
+	var requiredInputs, optionalInputs []registry.ModuleInput
+
+	for _, input := range inputs {
+		if input.Required {
+			requiredInputs = append(requiredInputs, input)
+		} else {
+			optionalInputs = append(optionalInputs, input)
+		}
+	}
+
+	// Sort by name
+	sort.Slice(requiredInputs, func(i, j int) bool {
+		return requiredInputs[i].Name < requiredInputs[j].Name
+	})
+	sort.Slice(optionalInputs, func(i, j int) bool {
+		return optionalInputs[i].Name < optionalInputs[j].Name
+	})
+
+	// Display required inputs
+	if len(requiredInputs) > 0 {
+		if err := d.out.Table(inputTable("required_inputs", "Required Inputs", requiredInputs, 5)); err != nil {
+			return err
+		}
+	}
+
+	// Display optional inputs (limited)
+	if len(optionalInputs) > 0 {
+		table := inputTable("optional_inputs", "Optional Inputs (showing first 5)", optionalInputs, 5)
+		if len(optionalInputs) > 5 {
+			table.Footer = fmt.Sprintf("... and %d more optional inputs", len(optionalInputs)-5)
+		}
+		return d.out.Table(table)
+	}
+
+	return nil
+}
+
+// inputTable lists up to limit module inputs
+func inputTable(name, title string, inputs []registry.ModuleInput, limit int) output.Table {
+	table := output.Table{
+		Name:    name,
+		Title:   title,
+		Columns: []string{"name", "type", "description"},
+	}
+	for _, input := range inputs[:min(limit, len(inputs))] {
+		table.Rows = append(table.Rows, []any{input.Name, input.Type, truncate(input.Description, 50)})
+	}
+	return table
+}
+
+func (d *ScenarioDemo) displayModuleOutputs(outputs []registry.ModuleOutput) error {
+	// Filter for outputs matching the scenario keywords or common identifiers
+	var importantOutputs []registry.ModuleOutput
+
+	for _, moduleOutput := range outputs {
+		nameLower := strings.ToLower(moduleOutput.Name)
+		if d.matchesKeyword(moduleOutput.Name) ||
+			strings.Contains(nameLower, "id") ||
+			strings.Contains(nameLower, "name") {
+			importantOutputs = append(importantOutputs, moduleOutput)
+		}
+	}
+
+	if len(importantOutputs) == 0 {
+		importantOutputs = outputs
+	}
+
+	// Sort by name
+	sort.Slice(importantOutputs, func(i, j int) bool {
+		return importantOutputs[i].Name < importantOutputs[j].Name
+	})
+
+	table := output.Table{
+		Name:    "outputs",
+		Title:   "Module Outputs",
+		Columns: []string{"name", "description"},
+	}
+
+	maxOutputs := 10
+	for _, moduleOutput := range importantOutputs[:min(maxOutputs, len(importantOutputs))] {
+		table.Rows = append(table.Rows, []any{moduleOutput.Name, truncate(moduleOutput.Description, 60)})
+	}
+	if len(importantOutputs) > maxOutputs {
+		table.Footer = fmt.Sprintf("... and %d more outputs", len(importantOutputs)-maxOutputs)
+	}
+
+	return d.out.Table(table)
+}
+
+// truncate shortens s to at most n bytes, ending with "..." when cut
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
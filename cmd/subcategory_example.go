@@ -207,7 +207,7 @@ func (e *SubcategoryExample) exampleCompareProviders(ctx context.Context) error
 		{"hashicorp", "google"},
 	}
 
-	fmt.Println("Networking resources count comparison:\n")
+	fmt.Print("Networking resources count comparison:\n\n")
 	fmt.Printf("%-20s | %-10s | %s\n", "Provider", "Version", "Resources")
 	fmt.Println(strings.Repeat("-", 70))
 
@@ -0,0 +1,253 @@
+// Package migrate plans namespace migrations of Terraform code, for example after a
+// registry organization is renamed. It scans Terraform files for module and provider
+// sources in the old namespaces, verifies that the renamed targets exist in the registry
+// and produces rewritten files and unified diff patches.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Mapping maps old namespaces to new namespaces
+type Mapping map[string]string
+
+// Validate validates the mapping
+func (m Mapping) Validate() error {
+	if len(m) == 0 {
+		return &registry.ValidationError{
+			Field:   "mapping",
+			Value:   m,
+			Message: "mapping cannot be empty",
+		}
+	}
+
+	for from, to := range m {
+		if from == "" || to == "" || strings.Contains(from, "/") || strings.Contains(to, "/") {
+			return &registry.ValidationError{
+				Field:   "mapping",
+				Value:   from + " -> " + to,
+				Message: "namespaces must be non-empty and cannot contain '/'",
+			}
+		}
+		if from == to {
+			return &registry.ValidationError{
+				Field:   "mapping",
+				Value:   from,
+				Message: "namespace is mapped to itself",
+			}
+		}
+	}
+
+	return nil
+}
+
+// Change is a source rewrite for one reference
+type Change struct {
+	Reference
+
+	// NewNamespace is the namespace the reference is migrated to
+	NewNamespace string
+
+	// NewSource is the rewritten source address
+	NewSource string
+
+	// Verified reports whether the target exists in the registry
+	Verified bool
+
+	// Err explains why the target could not be verified; nil when Verified or when the
+	// registry reported the target as not found
+	Err error
+}
+
+// Patch is the rewrite of one file
+type Patch struct {
+	Path string
+
+	// Changes are the verified changes applied to the file, ordered by line
+	Changes []Change
+
+	// Updated is the rewritten file content
+	Updated []byte
+
+	// Diff is a unified diff of the rewrite
+	Diff string
+}
+
+// Plan is a namespace migration plan
+type Plan struct {
+	Mapping Mapping
+
+	// Changes are all references in mapped namespaces, ordered by file and line
+	Changes []Change
+
+	// Patches rewrite the verified changes, one per affected file, ordered by path
+	Patches []Patch
+}
+
+// Ready reports whether every change was verified, so applying the patches migrates
+// all references
+func (p *Plan) Ready() bool {
+	for _, change := range p.Changes {
+		if !change.Verified {
+			return false
+		}
+	}
+	return true
+}
+
+// Unverified returns the changes whose target is missing or could not be checked
+func (p *Plan) Unverified() []Change {
+	var unverified []Change
+	for _, change := range p.Changes {
+		if !change.Verified {
+			unverified = append(unverified, change)
+		}
+	}
+	return unverified
+}
+
+// Diff returns the unified diffs of all patches
+func (p *Plan) Diff() string {
+	var b strings.Builder
+	for _, patch := range p.Patches {
+		b.WriteString(patch.Diff)
+	}
+	return b.String()
+}
+
+// BuildPlan scans files for module and provider sources in the mapped namespaces,
+// checks each renamed target once against the client's registry and builds patches
+// for the verified changes. Host-qualified sources for other registries are planned
+// but left unverified. A missing target is not an error; it is reported as an
+// unverified change.
+func BuildPlan(ctx context.Context, client *registry.Client, files []File, mapping Mapping) (*Plan, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Mapping: mapping}
+
+	for _, ref := range Scan(files) {
+		to, ok := mapping[ref.Namespace]
+		if !ok {
+			continue
+		}
+		plan.Changes = append(plan.Changes, Change{Reference: ref, NewNamespace: to, NewSource: ref.withNamespace(to)})
+	}
+
+	host := registryHost(client)
+	type verification struct {
+		exists bool
+		err    error
+	}
+	verified := make(map[string]verification)
+
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		if change.Host != "" && !strings.EqualFold(change.Host, host) {
+			change.Err = fmt.Errorf("cannot verify %s: host %s is not the client registry %s", change.NewSource, change.Host, host)
+			continue
+		}
+
+		target := string(change.Kind) + ":" + change.NewNamespace + "/" + change.Name + "/" + change.Provider
+		result, ok := verified[target]
+		if !ok {
+			result.exists, result.err = targetExists(ctx, client, change)
+			verified[target] = result
+		}
+
+		change.Verified = result.exists
+		change.Err = result.err
+	}
+
+	plan.Patches = buildPatches(files, plan.Changes)
+
+	return plan, nil
+}
+
+// targetExists checks a renamed module or provider in the registry
+func targetExists(ctx context.Context, client *registry.Client, change *Change) (bool, error) {
+	var err error
+	if change.Kind == KindModule {
+		_, err = client.Modules.ListVersions(ctx, change.NewNamespace, change.Name, change.Provider)
+	} else {
+		_, err = client.Providers.Get(ctx, change.NewNamespace, change.Name)
+	}
+
+	switch {
+	case err == nil:
+		return true, nil
+	case registry.IsNotFound(err):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to verify %s: %w", change.NewSource, err)
+	}
+}
+
+// registryHost returns the hostname of the client's registry
+func registryHost(client *registry.Client) string {
+	u, err := url.Parse(client.GetBaseURL())
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// buildPatches rewrites the verified changes in their files
+func buildPatches(files []File, changes []Change) []Patch {
+	byFile := make(map[string][]Change)
+	for _, change := range changes {
+		if change.Verified {
+			byFile[change.File] = append(byFile[change.File], change)
+		}
+	}
+
+	var patches []Patch
+	for _, file := range files {
+		fileChanges, ok := byFile[file.Path]
+		if !ok {
+			continue
+		}
+		delete(byFile, file.Path)
+
+		updated, diff := rewrite(file, fileChanges)
+		patches = append(patches, Patch{
+			Path:    file.Path,
+			Changes: fileChanges,
+			Updated: updated,
+			Diff:    diff,
+		})
+	}
+
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].Path < patches[j].Path
+	})
+
+	return patches
+}
+
+// rewrite applies changes to a file and returns the new content and a unified diff
+// with one hunk per changed line
+func rewrite(file File, changes []Change) ([]byte, string) {
+	lines := strings.Split(string(file.Data), "\n")
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- a/%s\n+++ b/%s\n", file.Path, file.Path)
+
+	for _, change := range changes {
+		index := change.Line - 1
+		old := lines[index]
+		lines[index] = strings.Replace(old, `"`+change.Source+`"`, `"`+change.NewSource+`"`, 1)
+
+		fmt.Fprintf(&diff, "@@ -%d +%d @@\n-%s\n+%s\n", change.Line, change.Line,
+			strings.TrimSuffix(old, "\r"), strings.TrimSuffix(lines[index], "\r"))
+	}
+
+	return []byte(strings.Join(lines, "\n")), diff.String()
+}
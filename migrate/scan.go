@@ -0,0 +1,294 @@
+package migrate
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kind is the kind of a registry reference found in Terraform code
+type Kind string
+
+const (
+	// KindModule is a module block source, addressed as [host/]namespace/name/provider
+	KindModule Kind = "module"
+
+	// KindProvider is a required_providers source, addressed as [host/]namespace/name
+	KindProvider Kind = "provider"
+)
+
+// File is a Terraform configuration file to scan
+type File struct {
+	// Path identifies the file in plans and patches; use slash-separated relative paths
+	Path string
+
+	Data []byte
+}
+
+// Reference is a registry module or provider source found in a Terraform file
+type Reference struct {
+	Kind Kind
+
+	// File and Line locate the source attribute (1-based line)
+	File string
+	Line int
+
+	// Source is the source address as written
+	Source string
+
+	// Host is the registry hostname when the source is host-qualified
+	Host string
+
+	Namespace string
+	Name      string
+
+	// Provider is the module's target system; empty for provider references
+	Provider string
+
+	// Subdir is the module subdirectory after "//", if any
+	Subdir string
+}
+
+// Address returns the namespace-qualified address without host and subdirectory
+func (r Reference) Address() string {
+	if r.Kind == KindModule {
+		return r.Namespace + "/" + r.Name + "/" + r.Provider
+	}
+	return r.Namespace + "/" + r.Name
+}
+
+// withNamespace returns the source address with the namespace replaced
+func (r Reference) withNamespace(namespace string) string {
+	var b strings.Builder
+	if r.Host != "" {
+		b.WriteString(r.Host + "/")
+	}
+	b.WriteString(namespace + "/" + r.Name)
+	if r.Kind == KindModule {
+		b.WriteString("/" + r.Provider)
+		if r.Subdir != "" {
+			b.WriteString("//" + r.Subdir)
+		}
+	}
+	return b.String()
+}
+
+var (
+	sourcePattern      = regexp.MustCompile(`\bsource\s*=\s*"([^"]*)"`)
+	moduleBlockPattern = regexp.MustCompile(`(?:^|\s)module\s+"[^"]*"\s*$`)
+	requiredPattern    = regexp.MustCompile(`(?:^|\s)required_providers\s*$`)
+	requirementPattern = regexp.MustCompile(`(?:^|\s)[A-Za-z0-9_-]+\s*=\s*$`)
+)
+
+// block kinds tracked while scanning
+const (
+	blockOther               = ""
+	blockModule              = "module"
+	blockRequiredProviders   = "required_providers"
+	blockProviderRequirement = "provider_requirement"
+)
+
+// Scan finds the registry module and provider sources in Terraform files. It is a
+// lightweight line scanner rather than a full HCL parser: it follows block nesting to
+// find source attributes of module blocks and required_providers entries, and skips
+// local paths, VCS and archive sources, and legacy provider names without a namespace.
+// References are returned ordered by file and line.
+func Scan(files []File) []Reference {
+	var refs []Reference
+	for _, file := range files {
+		refs = append(refs, scanFile(file)...)
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].File != refs[j].File {
+			return refs[i].File < refs[j].File
+		}
+		return refs[i].Line < refs[j].Line
+	})
+
+	return refs
+}
+
+// scanFile finds the registry sources in one file
+func scanFile(file File) []Reference {
+	var refs []Reference
+	var stack []string
+	inComment := false
+
+	for i, line := range strings.Split(string(file.Data), "\n") {
+		code := stripComments(line, &inComment)
+
+		// Attribute the source to the innermost block open at its position
+		sourceAt := -1
+		if match := sourcePattern.FindStringSubmatchIndex(code); match != nil {
+			sourceAt = match[0]
+		}
+
+		inString := false
+		for pos := 0; pos < len(code); pos++ {
+			if pos == sourceAt {
+				if ref, ok := parseSource(innermost(stack), code[sourceAt:]); ok {
+					ref.File = file.Path
+					ref.Line = i + 1
+					refs = append(refs, ref)
+				}
+			}
+
+			switch c := code[pos]; {
+			case c == '\\' && inString:
+				pos++
+			case c == '"':
+				inString = !inString
+			case c == '{' && !inString:
+				stack = append(stack, blockKind(innermost(stack), code[:pos]))
+			case c == '}' && !inString && len(stack) > 0:
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	return refs
+}
+
+// innermost returns the innermost open block kind
+func innermost(stack []string) string {
+	if len(stack) == 0 {
+		return blockOther
+	}
+	return stack[len(stack)-1]
+}
+
+// blockKind classifies a block from the text before its opening brace
+func blockKind(parent, before string) string {
+	switch {
+	case moduleBlockPattern.MatchString(before):
+		return blockModule
+	case requiredPattern.MatchString(before):
+		return blockRequiredProviders
+	case parent == blockRequiredProviders && requirementPattern.MatchString(before):
+		return blockProviderRequirement
+	default:
+		return blockOther
+	}
+}
+
+// stripComments removes #, // and /* */ comments outside strings
+func stripComments(line string, inComment *bool) string {
+	var b strings.Builder
+	inString := false
+
+	for pos := 0; pos < len(line); pos++ {
+		if *inComment {
+			if strings.HasPrefix(line[pos:], "*/") {
+				*inComment = false
+				pos++
+			}
+			continue
+		}
+
+		c := line[pos]
+		switch {
+		case inString && c == '\\' && pos+1 < len(line):
+			b.WriteByte(c)
+			pos++
+			c = line[pos]
+		case c == '"':
+			inString = !inString
+		case !inString && (c == '#' || strings.HasPrefix(line[pos:], "//")):
+			return b.String()
+		case !inString && strings.HasPrefix(line[pos:], "/*"):
+			*inComment = true
+			pos++
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// parseSource parses a source attribute in a block of the given kind
+func parseSource(block, attribute string) (Reference, bool) {
+	match := sourcePattern.FindStringSubmatch(attribute)
+	if match == nil {
+		return Reference{}, false
+	}
+	source := match[1]
+
+	switch block {
+	case blockModule:
+		return parseModuleSource(source)
+	case blockProviderRequirement:
+		return parseProviderSource(source)
+	default:
+		return Reference{}, false
+	}
+}
+
+// parseModuleSource parses a [host/]namespace/name/provider[//subdir] module source
+func parseModuleSource(source string) (Reference, bool) {
+	if !isRegistrySource(source) {
+		return Reference{}, false
+	}
+
+	address, subdir, _ := strings.Cut(source, "//")
+	parts := strings.Split(address, "/")
+
+	ref := Reference{Kind: KindModule, Source: source, Subdir: subdir}
+	if len(parts) == 4 && isHostname(parts[0]) {
+		ref.Host, parts = parts[0], parts[1:]
+	}
+	// github.com/org/repo and similar are VCS shorthands, not registry addresses
+	if len(parts) != 3 || !nonEmpty(parts) || isHostname(parts[0]) {
+		return Reference{}, false
+	}
+
+	ref.Namespace, ref.Name, ref.Provider = parts[0], parts[1], parts[2]
+	return ref, true
+}
+
+// parseProviderSource parses a [host/]namespace/name provider source
+func parseProviderSource(source string) (Reference, bool) {
+	if !isRegistrySource(source) {
+		return Reference{}, false
+	}
+
+	parts := strings.Split(source, "/")
+
+	ref := Reference{Kind: KindProvider, Source: source}
+	if len(parts) == 3 && isHostname(parts[0]) {
+		ref.Host, parts = parts[0], parts[1:]
+	}
+	if len(parts) != 2 || !nonEmpty(parts) || isHostname(parts[0]) {
+		return Reference{}, false
+	}
+
+	ref.Namespace, ref.Name = parts[0], parts[1]
+	return ref, true
+}
+
+// isRegistrySource reports whether a source can be a registry address rather than a
+// local path, URL or VCS/archive source
+func isRegistrySource(source string) bool {
+	return source != "" &&
+		!strings.HasPrefix(source, ".") &&
+		!strings.HasPrefix(source, "/") &&
+		!strings.Contains(source, "::") &&
+		!strings.Contains(source, "?") &&
+		!strings.Contains(source, ":")
+}
+
+// isHostname reports whether a source segment is a registry hostname
+func isHostname(segment string) bool {
+	return strings.Contains(segment, ".")
+}
+
+// nonEmpty reports whether all parts are non-empty
+func nonEmpty(parts []string) bool {
+	for _, part := range parts {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
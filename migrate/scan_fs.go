@@ -0,0 +1,69 @@
+//go:build !js && !wasip1 && !tinygo
+
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadDir reads the Terraform files (*.tf) under root, skipping hidden directories such
+// as .terraform. File paths are relative to root and slash-separated.
+func LoadDir(root string) ([]File, error) {
+	var files []File
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".tf" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, File{Path: filepath.ToSlash(relative), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	return files, nil
+}
+
+// WritePatches writes the updated content of each patch to its path under root
+func WritePatches(root string, patches []Patch) error {
+	for _, patch := range patches {
+		path := filepath.Join(root, filepath.FromSlash(patch.Path))
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", patch.Path, err)
+		}
+
+		if err := os.WriteFile(path, patch.Updated, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", patch.Path, err)
+		}
+	}
+	return nil
+}
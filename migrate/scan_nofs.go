@@ -0,0 +1,16 @@
+//go:build js || wasip1 || tinygo
+
+package migrate
+
+import "github.com/TahirRiaz/terralens-registry-client/registry"
+
+// LoadDir reports that directory scans are unavailable in this build; use Scan or
+// BuildPlan with in-memory files instead
+func LoadDir(root string) ([]File, error) {
+	return nil, registry.ErrFilesystemUnsupported
+}
+
+// WritePatches reports that writing files is unavailable in this build
+func WritePatches(root string, patches []Patch) error {
+	return registry.ErrFilesystemUnsupported
+}
@@ -0,0 +1,271 @@
+// Package output renders CLI results as human-readable tables or as JSON, YAML or CSV
+// for scripts. Commands describe their results as tables and records and narrate
+// progress as text; the table renderer prints everything, while the machine-readable
+// renderers drop the narration so that standard output only carries data.
+//
+// JSON output is a stream of one object per table or record, YAML output is a stream
+// of documents with the same shape, and CSV output writes each table as a header and
+// rows, separated from the next by a blank line.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatCSV   = "csv"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatTable, FormatJSON, FormatYAML, FormatCSV}
+
+// Table is tabular data
+type Table struct {
+	// Name identifies the table in machine-readable output, e.g. "modules"
+	Name string
+
+	// Title is shown above the table by the table renderer
+	Title string
+
+	// Columns are lowercase snake_case keys; the table renderer shows them in upper case
+	Columns []string
+
+	// Rows hold one value per column. Values are encoded as-is in JSON and YAML and
+	// formatted for display in table and CSV output.
+	Rows [][]any
+
+	// Footer is shown below the table by the table renderer, e.g. "... and 3 more"
+	Footer string
+
+	// Empty is shown instead of the table by the table renderer when there are no rows
+	Empty string
+}
+
+// Field is a named value of a record
+type Field struct {
+	// Name is a lowercase snake_case key, shown capitalized by the table renderer
+	Name  string
+	Value any
+}
+
+// Renderer writes command results in one output format
+type Renderer interface {
+	// Text writes human-readable narration such as headings and progress. Renderers
+	// for machine-readable formats discard it.
+	Text(format string, args ...any)
+
+	// Table writes tabular data
+	Table(t Table) error
+
+	// Record writes a single named set of fields, such as the details of one item
+	Record(name string, fields []Field) error
+}
+
+// New creates a renderer for a format writing to w
+func New(format string, w io.Writer) (Renderer, error) {
+	switch strings.ToLower(format) {
+	case FormatTable, "":
+		return &tableRenderer{w: w}, nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &encodingRenderer{encode: encoder.Encode}, nil
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		return &encodingRenderer{encode: encoder.Encode}, nil
+	case FormatCSV:
+		return &csvRenderer{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (expected %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// tableRenderer prints aligned tables and narration for people
+type tableRenderer struct {
+	w io.Writer
+}
+
+func (r *tableRenderer) Text(format string, args ...any) {
+	fmt.Fprintf(r.w, format, args...)
+}
+
+func (r *tableRenderer) Table(t Table) error {
+	if t.Title != "" {
+		fmt.Fprintf(r.w, "\n%s:\n", t.Title)
+	}
+	if len(t.Rows) == 0 && t.Empty != "" {
+		_, err := fmt.Fprintln(r.w, t.Empty)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(r.w, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(t.Columns))
+	rules := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		headers[i] = strings.ToUpper(column)
+		rules[i] = strings.Repeat("-", len(column))
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	fmt.Fprintln(tw, strings.Join(rules, "\t"))
+
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = displayValue(value)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if t.Footer != "" {
+		fmt.Fprintln(r.w, t.Footer)
+	}
+	return nil
+}
+
+func (r *tableRenderer) Record(name string, fields []Field) error {
+	tw := tabwriter.NewWriter(r.w, 0, 0, 1, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", label(field.Name), displayValue(field.Value))
+	}
+	return tw.Flush()
+}
+
+// encodingRenderer writes every table and record as one document with an encoder
+type encodingRenderer struct {
+	encode func(v any) error
+}
+
+func (r *encodingRenderer) Text(format string, args ...any) {}
+
+func (r *encodingRenderer) Table(t Table) error {
+	rows := make([]map[string]any, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = make(map[string]any, len(t.Columns))
+		for j, column := range t.Columns {
+			if j < len(row) {
+				rows[i][column] = row[j]
+			}
+		}
+	}
+	return r.encode(map[string]any{"name": t.Name, "rows": rows})
+}
+
+func (r *encodingRenderer) Record(name string, fields []Field) error {
+	values := make(map[string]any, len(fields))
+	for _, field := range fields {
+		values[field.Name] = field.Value
+	}
+	return r.encode(map[string]any{"name": name, "fields": values})
+}
+
+// csvRenderer writes tables as CSV and records as two-column field,value tables
+type csvRenderer struct {
+	w       io.Writer
+	written bool
+}
+
+func (r *csvRenderer) Text(format string, args ...any) {}
+
+func (r *csvRenderer) Table(t Table) error {
+	records := make([][]string, 0, len(t.Rows)+1)
+	records = append(records, t.Columns)
+	for _, row := range t.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = csvValue(value)
+		}
+		records = append(records, cells)
+	}
+	return r.write(records)
+}
+
+func (r *csvRenderer) Record(name string, fields []Field) error {
+	records := make([][]string, 0, len(fields)+1)
+	records = append(records, []string{"field", "value"})
+	for _, field := range fields {
+		records = append(records, []string{field.Name, csvValue(field.Value)})
+	}
+	return r.write(records)
+}
+
+// write writes one table, separated from the previous one by a blank line
+func (r *csvRenderer) write(records [][]string) error {
+	if r.written {
+		if _, err := io.WriteString(r.w, "\n"); err != nil {
+			return err
+		}
+	}
+	r.written = true
+	return csv.NewWriter(r.w).WriteAll(records)
+}
+
+// displayValue formats a value for the table renderer
+func displayValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case bool:
+		if v {
+			return "Yes"
+		}
+		return "No"
+	case float64:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// csvValue formats a value for CSV
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, ";")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// acronyms are field name words shown in upper case
+var acronyms = map[string]bool{"api": true, "id": true, "url": true}
+
+// label turns a field name such as "api_calls" into "API calls"
+func label(name string) string {
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		switch {
+		case acronyms[word]:
+			words[i] = strings.ToUpper(word)
+		case i == 0 && word != "":
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,82 @@
+//go:build !js && !wasip1 && !tinygo
+
+package pins
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// docPinsFile is the serialized doc pin map
+type docPinsFile struct {
+	Version   int               `json:"version" yaml:"version"`
+	Providers map[string]string `json:"providers" yaml:"providers"`
+}
+
+// LoadDocPins loads a team doc pin map, mapping providers as namespace/name to the
+// version whose docs are shown, from a JSON or YAML file. A missing file yields an empty
+// map. Attach the map to requests with registry.WithDocPins.
+func LoadDocPins(path string) (registry.DocPins, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry.DocPins{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read doc pins file: %w", err)
+	}
+
+	var file docPinsFile
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse doc pins file %s: %w", path, err)
+	}
+
+	pins := registry.DocPins(file.Providers)
+	if pins == nil {
+		pins = registry.DocPins{}
+	}
+	if err := pins.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid doc pins in %s: %w", path, err)
+	}
+
+	return pins, nil
+}
+
+// SaveDocPins writes a doc pin map to a JSON or YAML file, replacing it atomically
+func SaveDocPins(path string, pins registry.DocPins) error {
+	if err := pins.Validate(); err != nil {
+		return err
+	}
+
+	file := docPinsFile{
+		Version:   FileFormatVersion,
+		Providers: pins,
+	}
+	if file.Providers == nil {
+		file.Providers = registry.DocPins{}
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if isYAML(path) {
+		data, err = yaml.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode doc pins: %w", err)
+	}
+
+	return writeFile(path, "doc pins", data)
+}
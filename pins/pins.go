@@ -0,0 +1,341 @@
+//go:build !js && !wasip1 && !tinygo
+
+// Package pins manages a local list of pinned (favorite) registry modules and providers,
+// with notes, desired version constraints and the last version seen. The list is
+// persisted to a JSON or YAML file, chosen by the file extension.
+package pins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// FileFormatVersion is the version of the pins file format
+const FileFormatVersion = 1
+
+// Kind is the kind of a pinned artifact
+type Kind string
+
+const (
+	// KindProvider is a pinned provider, addressed as namespace/name
+	KindProvider Kind = "provider"
+
+	// KindModule is a pinned module, addressed as namespace/name/provider
+	KindModule Kind = "module"
+)
+
+// Pin is a pinned module or provider
+type Pin struct {
+	Kind    Kind   `json:"kind" yaml:"kind"`
+	Address string `json:"address" yaml:"address"`
+
+	// Constraint is the desired version constraint, e.g. "~> 5.0"
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+
+	// Note is a free-form user note
+	Note string `json:"note,omitempty" yaml:"note,omitempty"`
+
+	// KnownVersion is the last version the user has seen; used for change detection
+	KnownVersion string `json:"known_version,omitempty" yaml:"known_version,omitempty"`
+
+	AddedAt time.Time `json:"added_at" yaml:"added_at"`
+}
+
+// ProviderRef returns the provider ref for a provider pin
+func (p Pin) ProviderRef() (registry.ProviderRef, error) {
+	parts := strings.Split(p.Address, "/")
+	if p.Kind != KindProvider || len(parts) != 2 {
+		return registry.ProviderRef{}, &registry.ValidationError{
+			Field:   "address",
+			Value:   p.Address,
+			Message: "not a provider pin in namespace/name form",
+		}
+	}
+	return registry.ProviderRef{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// ModuleRef returns the module ref for a module pin
+func (p Pin) ModuleRef() (registry.ModuleRef, error) {
+	parts := strings.Split(p.Address, "/")
+	if p.Kind != KindModule || len(parts) != 3 {
+		return registry.ModuleRef{}, &registry.ValidationError{
+			Field:   "address",
+			Value:   p.Address,
+			Message: "not a module pin in namespace/name/provider form",
+		}
+	}
+	return registry.ModuleRef{Namespace: parts[0], Name: parts[1], Provider: parts[2]}, nil
+}
+
+// Validate validates the pin kind and address
+func (p Pin) Validate() error {
+	switch p.Kind {
+	case KindProvider:
+		_, err := p.ProviderRef()
+		return err
+	case KindModule:
+		_, err := p.ModuleRef()
+		return err
+	default:
+		return &registry.ValidationError{
+			Field:   "kind",
+			Value:   p.Kind,
+			Message: "kind must be provider or module",
+		}
+	}
+}
+
+// pinsFile is the serialized pins file
+type pinsFile struct {
+	Version int   `json:"version" yaml:"version"`
+	Pins    []Pin `json:"pins" yaml:"pins"`
+}
+
+// Store is a pin list backed by a file
+type Store struct {
+	path string
+
+	mu   sync.RWMutex
+	pins map[string]Pin
+}
+
+// DefaultPath returns the default pins file location, ~/.terralense/pins.yaml
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".terralense", "pins.yaml"), nil
+}
+
+// Open loads the pins file at path. A missing file yields an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		pins: make(map[string]Pin),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pins file: %w", err)
+	}
+
+	var file pinsFile
+	if isYAML(path) {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pins file %s: %w", path, err)
+	}
+
+	for _, pin := range file.Pins {
+		if err := pin.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid pin in %s: %w", path, err)
+		}
+		s.pins[key(pin.Kind, pin.Address)] = pin
+	}
+
+	return s, nil
+}
+
+// Path returns the file backing the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// List returns all pins sorted by kind and address
+func (s *Store) List() []Pin {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pins := make([]Pin, 0, len(s.pins))
+	for _, pin := range s.pins {
+		pins = append(pins, pin)
+	}
+
+	sort.Slice(pins, func(i, j int) bool {
+		if pins[i].Kind != pins[j].Kind {
+			return pins[i].Kind < pins[j].Kind
+		}
+		return pins[i].Address < pins[j].Address
+	})
+
+	return pins
+}
+
+// Get returns a pin by kind and address
+func (s *Store) Get(kind Kind, address string) (Pin, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pin, ok := s.pins[key(kind, address)]
+	return pin, ok
+}
+
+// Add adds a pin or replaces an existing pin with the same kind and address,
+// keeping the original AddedAt
+func (s *Store) Add(pin Pin) error {
+	if err := pin.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(pin.Kind, pin.Address)
+	if existing, ok := s.pins[k]; ok && !existing.AddedAt.IsZero() {
+		pin.AddedAt = existing.AddedAt
+	}
+	if pin.AddedAt.IsZero() {
+		pin.AddedAt = time.Now().UTC()
+	}
+
+	s.pins[k] = pin
+	return nil
+}
+
+// Remove removes a pin and reports whether it existed
+func (s *Store) Remove(kind Kind, address string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(kind, address)
+	_, ok := s.pins[k]
+	delete(s.pins, k)
+	return ok
+}
+
+// MarkSeen records version as the last version seen for a pin
+func (s *Store) MarkSeen(kind Kind, address, version string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(kind, address)
+	pin, ok := s.pins[k]
+	if !ok {
+		return false
+	}
+	pin.KnownVersion = version
+	s.pins[k] = pin
+	return true
+}
+
+// Save writes the store to its file, creating parent directories as needed.
+// The file is replaced atomically.
+func (s *Store) Save() error {
+	file := pinsFile{
+		Version: FileFormatVersion,
+		Pins:    s.List(),
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if isYAML(s.path) {
+		data, err = yaml.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode pins: %w", err)
+	}
+
+	return writeFile(s.path, "pins", data)
+}
+
+// Update is the change detection result for a pin
+type Update struct {
+	Pin Pin
+
+	// Changed is true when the latest version differs from the pin's known version
+	Changed bool
+
+	// Err is set when the check failed
+	Err error
+}
+
+// CheckUpdates reports, for every pin, whether a newer version than the known version
+// has been published. Each pin costs at most a single request via HasChanged.
+func (s *Store) CheckUpdates(ctx context.Context, client *registry.Client) []Update {
+	pins := s.List()
+	updates := make([]Update, 0, len(pins))
+
+	for _, pin := range pins {
+		update := Update{Pin: pin}
+
+		switch pin.Kind {
+		case KindProvider:
+			ref, err := pin.ProviderRef()
+			if err == nil {
+				update.Changed, err = client.Providers.HasChanged(ctx, ref, pin.KnownVersion)
+			}
+			update.Err = err
+		case KindModule:
+			ref, err := pin.ModuleRef()
+			if err == nil {
+				update.Changed, err = client.Modules.HasChanged(ctx, ref, pin.KnownVersion)
+			}
+			update.Err = err
+		}
+
+		updates = append(updates, update)
+	}
+
+	return updates
+}
+
+// writeFile replaces the file at path with data atomically, creating parent
+// directories as needed; what names the file in errors
+func writeFile(path, what string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", what, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pins-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s file: %w", what, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s file: %w", what, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", what, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", what, err)
+	}
+
+	return nil
+}
+
+// key returns the map key of a pin
+func key(kind Kind, address string) string {
+	return string(kind) + ":" + address
+}
+
+// isYAML reports whether path has a YAML extension
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,198 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// headerRateLimitRemaining is the response header holding the number of requests
+	// left in the registry's current rate limit window
+	headerRateLimitRemaining = "X-Ratelimit-Remaining"
+
+	// headerRateLimitReset is the response header holding when the registry's current
+	// rate limit window ends, in seconds from now or as a Unix time
+	headerRateLimitReset = "X-Ratelimit-Reset"
+)
+
+// AdaptiveRateLimiter is a RateLimiter that follows the budget announced by the
+// registry in the X-Ratelimit-Remaining and X-Ratelimit-Reset response headers. Until
+// a response carries them, and after the announced window ends, it behaves like its
+// token bucket; within a window, requests beyond the announced remaining budget wait
+// for the window to end. The token bucket stays in effect as an upper bound.
+type AdaptiveRateLimiter struct {
+	bucket *TokenBucket
+
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+// NewAdaptiveRateLimiter creates an adaptive rate limiter on top of a token bucket of
+// maxRequests per period
+func NewAdaptiveRateLimiter(maxRequests int, period time.Duration) *AdaptiveRateLimiter {
+	return &AdaptiveRateLimiter{bucket: NewTokenBucket(maxRequests, period)}
+}
+
+// Wait blocks until a token is available or the context is cancelled
+func (a *AdaptiveRateLimiter) Wait(ctx context.Context) error {
+	for {
+		if a.TryAcquire() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(max(a.TimeUntilNextToken(), time.Millisecond)):
+		}
+	}
+}
+
+// TryAcquire attempts to acquire a token without blocking
+func (a *AdaptiveRateLimiter) TryAcquire() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	limited := a.limitedLocked(time.Now())
+	if limited && a.remaining <= 0 {
+		return false
+	}
+	if !a.bucket.TryAcquire() {
+		return false
+	}
+	if limited {
+		a.remaining--
+	}
+	return true
+}
+
+// WaitForTokens blocks until at least n tokens are available without consuming them,
+// or the context is cancelled. n is capped at the limiter capacity.
+func (a *AdaptiveRateLimiter) WaitForTokens(ctx context.Context, n int) error {
+	n = min(n, a.Capacity())
+
+	for {
+		a.mu.Lock()
+		now := time.Now()
+		var wait time.Duration
+		if a.limitedLocked(now) && a.remaining < n {
+			wait = a.resetAt.Sub(now)
+		}
+		a.mu.Unlock()
+
+		if wait <= 0 {
+			return a.bucket.WaitForTokens(ctx, n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// TimeUntilNextToken returns how long until a token is available, which is the end of
+// the registry's window when its announced budget is used up
+func (a *AdaptiveRateLimiter) TimeUntilNextToken() time.Duration {
+	a.mu.Lock()
+	now := time.Now()
+	exhausted := a.limitedLocked(now) && a.remaining <= 0
+	resetAt := a.resetAt
+	a.mu.Unlock()
+
+	if exhausted {
+		return resetAt.Sub(now)
+	}
+	return a.bucket.TimeUntilNextToken()
+}
+
+// TokensRemaining returns the number of tokens currently available
+func (a *AdaptiveRateLimiter) TokensRemaining() int {
+	tokens := a.bucket.TokensRemaining()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.limitedLocked(time.Now()) {
+		return max(0, min(tokens, a.remaining))
+	}
+	return tokens
+}
+
+// Capacity returns the capacity of the token bucket
+func (a *AdaptiveRateLimiter) Capacity() int {
+	return a.bucket.Capacity()
+}
+
+// Acquired returns the total number of tokens handed out since the limiter was created
+func (a *AdaptiveRateLimiter) Acquired() uint64 {
+	return a.bucket.Acquired()
+}
+
+// Reset resets the token bucket to full capacity and forgets the registry's budget
+func (a *AdaptiveRateLimiter) Reset() {
+	a.bucket.Reset()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.remaining = 0
+	a.resetAt = time.Time{}
+}
+
+// Observe adopts the budget announced by the X-Ratelimit-Remaining and
+// X-Ratelimit-Reset headers. Responses without both headers are ignored.
+func (a *AdaptiveRateLimiter) Observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get(headerRateLimitRemaining))
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	resetAt, ok := parseRateLimitReset(header.Get(headerRateLimitReset), now)
+	if !ok || !resetAt.After(now) {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.remaining = max(0, remaining)
+	a.resetAt = resetAt
+}
+
+// limitedLocked reports whether a budget announced by the registry is in effect
+func (a *AdaptiveRateLimiter) limitedLocked(now time.Time) bool {
+	return now.Before(a.resetAt)
+}
+
+// exportState implements stateSection by persisting the token bucket; the registry's
+// budget is announced again by the next response
+func (a *AdaptiveRateLimiter) exportState() (json.RawMessage, error) {
+	return a.bucket.exportState()
+}
+
+// importState implements stateSection
+func (a *AdaptiveRateLimiter) importState(data json.RawMessage) error {
+	return a.bucket.importState(data)
+}
+
+// parseRateLimitReset parses an X-Ratelimit-Reset value, which registries send either
+// as seconds until the reset or as the Unix time of the reset
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+
+	// Values beyond a year of seconds can only be Unix times
+	if seconds > 365*24*60*60 {
+		return time.Unix(0, int64(seconds*float64(time.Second))), true
+	}
+	return now.Add(time.Duration(seconds * float64(time.Second))), true
+}
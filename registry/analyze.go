@@ -0,0 +1,279 @@
+package registry
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultDuplicateSimilarity is the minimum description similarity, from 0 to 1, at which
+	// two modules with the same name and provider are considered copies of each other
+	DefaultDuplicateSimilarity = 0.5
+
+	// maxDuplicateSearchPages bounds the number of search pages scanned by FindDuplicates
+	maxDuplicateSearchPages = 5
+)
+
+// AnalyzeService provides analyses that combine several registry lookups
+type AnalyzeService struct {
+	client *Client
+}
+
+// DuplicateCluster is a group of modules that appear to be forks or copies of the same module
+type DuplicateCluster struct {
+	Name     string
+	Provider string
+
+	// Canonical is the highest ranked member, most likely the original
+	Canonical Module
+
+	// Members holds every module of the cluster, ranked best first; it includes Canonical
+	Members []DuplicateCandidate
+}
+
+// DuplicateCandidate is a module in a duplicate cluster
+type DuplicateCandidate struct {
+	Module
+
+	// Score is the canonical ranking score; higher is more likely the original
+	Score float64
+
+	// Similarity is the description similarity to the canonical module, from 0 to 1
+	Similarity float64
+}
+
+// FindDuplicates searches for modules and clusters results that appear to be forks or
+// copies of the same module: the same name and provider with a similar description or
+// the same source repository name. Each cluster is ranked so that the verified, most
+// downloaded module hosted under its own namespace comes first. Clusters are ordered
+// by the downloads of their canonical module.
+func (s *AnalyzeService) FindDuplicates(ctx context.Context, query string) ([]DuplicateCluster, error) {
+	modules, err := s.searchAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]Module)
+	var keys []string
+	for _, mod := range modules {
+		key := strings.ToLower(mod.Name) + "/" + strings.ToLower(mod.Provider)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], mod)
+	}
+
+	var clusters []DuplicateCluster
+	for _, key := range keys {
+		for _, members := range clusterDuplicates(groups[key]) {
+			if len(members) > 1 {
+				clusters = append(clusters, rankDuplicateCluster(members))
+			}
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Canonical.Downloads > clusters[j].Canonical.Downloads
+	})
+
+	return clusters, nil
+}
+
+// searchAll collects the search results of a query across pages, skipping repeated module IDs
+func (s *AnalyzeService) searchAll(ctx context.Context, query string) ([]Module, error) {
+	var modules []Module
+	seen := make(map[string]bool)
+
+	offset := 0
+	for page := 0; page < maxDuplicateSearchPages; page++ {
+		result, err := s.client.Modules.Search(ctx, query, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mod := range result.Modules {
+			key := mod.Namespace + "/" + mod.Name + "/" + mod.Provider
+			if !seen[key] {
+				seen[key] = true
+				modules = append(modules, mod)
+			}
+		}
+
+		next := nextOffset(result.Meta)
+		if next <= offset || len(result.Modules) == 0 {
+			break
+		}
+		offset = next
+	}
+
+	return modules, nil
+}
+
+// clusterDuplicates splits modules sharing a name and provider into groups of likely copies
+func clusterDuplicates(modules []Module) [][]Module {
+	parent := make([]int, len(modules))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range modules {
+		for j := i + 1; j < len(modules); j++ {
+			if areDuplicates(modules[i], modules[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	byRoot := make(map[int][]Module)
+	var roots []int
+	for i, mod := range modules {
+		root := find(i)
+		if _, ok := byRoot[root]; !ok {
+			roots = append(roots, root)
+		}
+		byRoot[root] = append(byRoot[root], mod)
+	}
+
+	clusters := make([][]Module, 0, len(roots))
+	for _, root := range roots {
+		clusters = append(clusters, byRoot[root])
+	}
+	return clusters
+}
+
+// areDuplicates reports whether two modules with the same name and provider look like copies
+func areDuplicates(a, b Module) bool {
+	if repoA, repoB := sourceRepoName(a.Source), sourceRepoName(b.Source); repoA != "" && repoA == repoB {
+		return true
+	}
+	return descriptionSimilarity(a.Description, b.Description) >= DefaultDuplicateSimilarity
+}
+
+// rankDuplicateCluster orders cluster members by canonical score
+func rankDuplicateCluster(modules []Module) DuplicateCluster {
+	members := make([]DuplicateCandidate, 0, len(modules))
+	for _, mod := range modules {
+		members = append(members, DuplicateCandidate{
+			Module: mod,
+			Score:  canonicalScore(mod),
+		})
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score > members[j].Score
+		}
+		return members[i].PublishedAt.Before(members[j].PublishedAt)
+	})
+
+	canonical := members[0].Module
+	for i := range members {
+		members[i].Similarity = descriptionSimilarity(canonical.Description, members[i].Description)
+	}
+
+	return DuplicateCluster{
+		Name:      canonical.Name,
+		Provider:  canonical.Provider,
+		Canonical: canonical,
+		Members:   members,
+	}
+}
+
+// canonicalScore scores how likely a module is the original of its copies
+func canonicalScore(mod Module) float64 {
+	score := 0.0
+
+	if mod.Verified {
+		score += 10.0
+	}
+
+	if mod.Downloads > 0 {
+		score += logScale(float64(mod.Downloads), 1, 10000000, 0, 5)
+	}
+
+	// Forks usually keep the upstream repository but publish under another namespace
+	if owner := sourceRepoOwner(mod.Source); owner != "" && strings.EqualFold(owner, mod.Namespace) {
+		score += 2.0
+	}
+
+	return score
+}
+
+// descriptionSimilarity returns the Jaccard similarity of the words of two descriptions
+func descriptionSimilarity(a, b string) float64 {
+	wordsA := descriptionWords(a)
+	wordsB := descriptionWords(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1.0
+	}
+
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+
+	union := len(wordsA) + len(wordsB) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// descriptionWords returns the distinct lowercase words of a description
+func descriptionWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !isLowerAlpha(r) && !isDigit(r)
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// sourceRepoPath returns the path segments of a module source URL, without a .git suffix
+func sourceRepoPath(source string) []string {
+	if source == "" {
+		return nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil
+	}
+
+	path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// sourceRepoName returns the lowercase repository name of a module source URL
+func sourceRepoName(source string) string {
+	segments := sourceRepoPath(source)
+	if len(segments) == 0 {
+		return ""
+	}
+	return strings.ToLower(segments[len(segments)-1])
+}
+
+// sourceRepoOwner returns the owner of a module source URL, e.g. the GitHub organization
+func sourceRepoOwner(source string) string {
+	segments := sourceRepoPath(source)
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[len(segments)-2]
+}
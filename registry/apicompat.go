@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// APIVersion is the semantic version of the client's public API. The minor version is
+// bumped when methods are added to a service interface and the major version when
+// methods are removed or change signature. InterfaceSnapshot records it alongside the
+// method sets; the test suite compares that with a committed snapshot and fails when a
+// method was removed or changed without a major bump.
+//
+// Methods are deprecated before they are removed: a deprecated method keeps working,
+// logs a warning the first time a client calls it, and is removed no earlier than the
+// next major version.
+const APIVersion = "1.9.0"
+
+// Compile-time checks that the services implement their interfaces
+var (
+	_ ProvidersServiceInterface = (*ProvidersService)(nil)
+	_ ModulesServiceInterface   = (*ModulesService)(nil)
+	_ PoliciesServiceInterface  = (*PoliciesService)(nil)
+	_ SecurityServiceInterface  = (*SecurityService)(nil)
+	_ AnalyzeServiceInterface   = (*AnalyzeService)(nil)
+	_ AuditServiceInterface     = (*AuditService)(nil)
+)
+
+// serviceInterfaces are the interfaces covered by InterfaceSnapshot, in snapshot order
+var serviceInterfaces = []reflect.Type{
+	reflect.TypeOf((*ProvidersServiceInterface)(nil)).Elem(),
+	reflect.TypeOf((*ModulesServiceInterface)(nil)).Elem(),
+	reflect.TypeOf((*PoliciesServiceInterface)(nil)).Elem(),
+	reflect.TypeOf((*SecurityServiceInterface)(nil)).Elem(),
+	reflect.TypeOf((*AnalyzeServiceInterface)(nil)).Elem(),
+	reflect.TypeOf((*AuditServiceInterface)(nil)).Elem(),
+}
+
+// InterfaceSnapshot renders the method signatures of the service interfaces, sorted by
+// name, under a header naming APIVersion. Comparing it with a committed copy detects
+// changes to the public API.
+func InterfaceSnapshot() string {
+	module := reflect.TypeOf(Client{}).PkgPath()
+	module = module[:strings.LastIndex(module, "/")+1]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "api %s\n", APIVersion)
+	for _, iface := range serviceInterfaces {
+		fmt.Fprintf(&b, "\n%s\n", iface.Name())
+		for i := 0; i < iface.NumMethod(); i++ {
+			method := iface.Method(i)
+			signature := strings.TrimPrefix(method.Type.String(), "func")
+			fmt.Fprintf(&b, "\t%s%s\n", method.Name, strings.ReplaceAll(signature, module, ""))
+		}
+	}
+
+	return b.String()
+}
+
+// deprecations remembers the deprecated methods a client has warned about
+type deprecations struct {
+	mu     sync.Mutex
+	warned map[string]bool
+}
+
+// warnDeprecated logs that a deprecated method was called, once per client and method
+func (c *Client) warnDeprecated(method, replacement string) {
+	c.deprecations.mu.Lock()
+	if c.deprecations.warned == nil {
+		c.deprecations.warned = make(map[string]bool)
+	}
+	warned := c.deprecations.warned[method]
+	c.deprecations.warned[method] = true
+	c.deprecations.mu.Unlock()
+
+	if warned {
+		return
+	}
+	c.logger.WithFields(logrus.Fields{
+		"method":      method,
+		"replacement": replacement,
+	}).Warnf("%s is deprecated and will be removed in the next major version; use %s instead", method, replacement)
+}
@@ -0,0 +1,641 @@
+package registry
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultMaxArchiveSize is the default size limit of downloaded and extracted module archives
+const DefaultMaxArchiveSize = 100 << 20
+
+// Module archive formats
+const (
+	ArchiveTarGz = "tar.gz"
+	ArchiveTar   = "tar"
+	ArchiveZip   = "zip"
+)
+
+var (
+	// ErrUnsupportedSource is returned when a module's download source is not an HTTP(S)
+	// archive or a GitHub repository
+	ErrUnsupportedSource = errors.New("unsupported module source")
+
+	// ErrChecksumMismatch is returned when a downloaded archive does not match the
+	// checksum published with its source
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrArchiveTooLarge is returned when an archive exceeds the size limit
+	ErrArchiveTooLarge = errors.New("archive exceeds size limit")
+
+	// ErrUnsafeArchivePath is returned for archive entries with absolute paths or ".."
+	// elements that would be extracted outside the destination
+	ErrUnsafeArchivePath = errors.New("unsafe path in archive")
+)
+
+// WithMaxArchiveSize sets the size limit of module archives in bytes. It applies both to
+// the downloaded archive and to the total size of the extracted files.
+func WithMaxArchiveSize(bytes int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxArchiveSize = bytes
+	}
+}
+
+// ModuleArchive is a module archive being downloaded. Reading it yields the raw archive;
+// the checksum is verified when the end is reached. Close it when done.
+type ModuleArchive struct {
+	// Source is the X-Terraform-Get address returned by the registry
+	Source string
+
+	// URL is the archive URL being downloaded
+	URL string
+
+	// Format is ArchiveTarGz, ArchiveTar or ArchiveZip
+	Format string
+
+	// Subdir is the module directory within the archive, from a "//" in the source;
+	// empty for the archive root
+	Subdir string
+
+	// Checksum is the checksum published with the source, such as "sha256:...";
+	// empty when none is available
+	Checksum string
+
+	body   io.ReadCloser
+	reader io.Reader
+	limit  int64
+
+	// strip is the number of leading path elements removed from entries, for archives
+	// that wrap the module in a top-level directory
+	strip int
+}
+
+// Read implements io.Reader
+func (a *ModuleArchive) Read(p []byte) (int, error) {
+	return a.reader.Read(p)
+}
+
+// Close implements io.Closer
+func (a *ModuleArchive) Close() error {
+	return a.body.Close()
+}
+
+// SavedArchive is a module archive saved to a file by SaveArchive
+type SavedArchive struct {
+	// Source, URL, Format, Subdir and Checksum are as in ModuleArchive; Format is empty
+	// when it cannot be detected
+	Source   string
+	URL      string
+	Format   string
+	Subdir   string
+	Checksum string
+
+	// Path is the file the archive was saved to
+	Path string
+
+	Download *DownloadResult
+}
+
+// ArchiveFile is a regular file read from a module archive
+type ArchiveFile struct {
+	// Path is the slash-separated path relative to the archive root
+	Path string
+
+	Mode fs.FileMode
+	Data []byte
+}
+
+// Files reads the archive into memory and returns its regular files, checking entry
+// paths like DownloadArchive does. Directories and links are left out.
+func (a *ModuleArchive) Files() ([]ArchiveFile, error) {
+	var files []ArchiveFile
+	err := a.walk(func(name string, mode fs.FileMode, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		files = append(files, ArchiveFile{Path: name, Mode: mode, Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// OpenArchive starts downloading a module version's archive. It asks the registry for
+// the download source (the X-Terraform-Get header), which may be relative to the
+// download endpoint, and opens it. HTTP(S) archive sources and GitHub repository sources
+// are supported; a "checksum" query parameter (md5, sha1, sha256 or sha512) is
+// verified and an "archive" parameter overrides the format detected from the file
+// extension or content.
+func (s *ModulesService) OpenArchive(ctx context.Context, namespace, name, provider, version string) (*ModuleArchive, error) {
+	if err := validateModuleParams(namespace, name, provider, version); err != nil {
+		return nil, err
+	}
+
+	ref := ModuleRef{Namespace: namespace, Name: name, Provider: provider, Version: version}
+	if err := s.checkModuleDownload(ctx, "Modules.OpenArchive", ref); err != nil {
+		return nil, err
+	}
+	return s.openArchive(ctx, namespace, name, provider, version)
+}
+
+// openArchive opens a module version's archive like OpenArchive, after its parameters
+// and the download policy were checked
+func (s *ModulesService) openArchive(ctx context.Context, namespace, name, provider, version string) (*ModuleArchive, error) {
+	downloadPath := fmt.Sprintf("modules/%s/%s/%s/%s/download", namespace, name, provider, version)
+	req, err := s.client.newRequest(ctx, http.MethodGet, downloadPath, "v1", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.client.downloadSource(req)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := parseArchiveSource(req.URL, source)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.client.openArchive(ctx, req.URL, archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// downloadSource requests a module download endpoint and returns its X-Terraform-Get
+// address. The response carries no body, so it bypasses the response cache.
+func (c *Client) downloadSource(req *http.Request) (string, error) {
+	if err := c.waitForToken(req); err != nil {
+		return "", fmt.Errorf("rate limit error: %w", timeoutError(err))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", &RequestError{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+	defer resp.Body.Close()
+	c.observeRateLimit(resp)
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error reading response body: %w", timeoutError(err)),
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", newAPIError(resp, body)
+	}
+
+	source := resp.Header.Get("X-Terraform-Get")
+	if source == "" {
+		return "", &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        errors.New("download response has no X-Terraform-Get header"),
+		}
+	}
+	return source, nil
+}
+
+// parseArchiveSource resolves an X-Terraform-Get address into the archive to download
+func parseArchiveSource(base *url.URL, source string) (*ModuleArchive, error) {
+	archive := &ModuleArchive{Source: source}
+
+	address := source
+	getter, rest, forced := strings.Cut(address, "::")
+	if forced && !strings.Contains(getter, "/") {
+		address = rest
+	} else {
+		getter = ""
+	}
+
+	// A "//" after the scheme separates the module subdirectory
+	scheme, remainder, hasScheme := strings.Cut(address, "://")
+	if !hasScheme {
+		scheme, remainder = "", address
+	}
+	if i := strings.Index(remainder, "//"); i > 0 {
+		subdir := remainder[i+2:]
+		remainder = remainder[:i]
+		if query := strings.Index(subdir, "?"); query >= 0 {
+			remainder += subdir[query:]
+			subdir = subdir[:query]
+		}
+		archive.Subdir = strings.Trim(subdir, "/")
+	}
+	if hasScheme {
+		address = scheme + "://" + remainder
+	} else {
+		address = remainder
+	}
+
+	ref, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrUnsupportedSource, source, err)
+	}
+	resolved := base.ResolveReference(ref)
+
+	switch {
+	case getter == "git" && resolved.Host == "github.com":
+		if err := githubArchive(archive, resolved); err != nil {
+			return nil, err
+		}
+		return archive, nil
+	case getter != "" && getter != "http" && getter != "https":
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSource, source)
+	case resolved.Scheme != "http" && resolved.Scheme != "https":
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSource, source)
+	}
+
+	query := resolved.Query()
+	archive.Checksum = query.Get("checksum")
+	archive.Format = query.Get("archive")
+	query.Del("checksum")
+	query.Del("archive")
+	resolved.RawQuery = query.Encode()
+
+	if archive.Format == "" {
+		archive.Format = archiveFormat(resolved.Path)
+	}
+	if archive.Format == "tgz" {
+		archive.Format = ArchiveTarGz
+	}
+	if archive.Format != "" && archive.Format != ArchiveTarGz && archive.Format != ArchiveTar && archive.Format != ArchiveZip {
+		return nil, fmt.Errorf("%w: archive format %q", ErrUnsupportedSource, archive.Format)
+	}
+
+	archive.URL = resolved.String()
+	return archive, nil
+}
+
+// githubArchive downloads a git::https://github.com/owner/repo?ref=x source as the
+// GitHub tarball of the ref, which wraps the repository in a top-level directory
+func githubArchive(archive *ModuleArchive, repo *url.URL) error {
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(repo.Path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("%w: %q", ErrUnsupportedSource, archive.Source)
+	}
+
+	ref := repo.Query().Get("ref")
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	archive.URL = fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", parts[0], parts[1], url.PathEscape(ref))
+	archive.Format = ArchiveTarGz
+	archive.strip = 1
+	return nil
+}
+
+// archiveFormat returns the archive format of a path by extension, or "" if unknown
+func archiveFormat(p string) string {
+	lower := strings.ToLower(p)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(lower, ".tar"):
+		return ArchiveTar
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip
+	}
+	return ""
+}
+
+// openArchive starts the archive download. Credentials are only sent to the registry host.
+func (c *Client) openArchive(ctx context.Context, registryURL *url.URL, archive *ModuleArchive) error {
+	verifier, err := newChecksumVerifier(archive.Checksum)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archive.URL, nil)
+	if err != nil {
+		return &RequestError{
+			Method: http.MethodGet,
+			URL:    archive.URL,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if auth := c.archiveAuthorization(registryURL, req.URL); auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &RequestError{
+			Method: http.MethodGet,
+			URL:    archive.URL,
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return newAPIError(resp, body)
+	}
+
+	limit := c.config.MaxArchiveSize
+	if limit <= 0 {
+		limit = DefaultMaxArchiveSize
+	}
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrArchiveTooLarge, archive.URL, resp.ContentLength, limit)
+	}
+
+	verifier.r = resp.Body
+	verifier.limit = limit
+	verifier.url = archive.URL
+
+	buffered := bufio.NewReader(verifier)
+	if archive.Format == "" {
+		archive.Format = sniffArchiveFormat(buffered)
+		if archive.Format == "" {
+			resp.Body.Close()
+			return fmt.Errorf("%w: cannot detect the archive format of %s", ErrUnsupportedSource, archive.URL)
+		}
+	}
+
+	archive.body = resp.Body
+	archive.reader = buffered
+	archive.limit = limit
+	return nil
+}
+
+// archiveAuthorization returns the Authorization header of an archive request, which is
+// empty unless the archive is served by the registry host
+func (c *Client) archiveAuthorization(registryURL, archiveURL *url.URL) string {
+	c.mu.RLock()
+	token := c.apiToken
+	c.mu.RUnlock()
+	if token == "" || !strings.EqualFold(archiveURL.Host, registryURL.Host) {
+		return ""
+	}
+	return fmt.Sprintf("Bearer %s", token)
+}
+
+// sniffArchiveFormat detects an archive format from its leading bytes
+func sniffArchiveFormat(r *bufio.Reader) string {
+	header, _ := r.Peek(512)
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return ArchiveZip
+	case len(header) >= 262 && string(header[257:262]) == "ustar":
+		return ArchiveTar
+	}
+	return ""
+}
+
+// checksumVerifier hashes the archive while it is read, enforcing the size limit and
+// comparing the checksum at the end
+type checksumVerifier struct {
+	r     io.Reader
+	url   string
+	limit int64
+	read  int64
+
+	checksum string
+	kind     string
+	hash     hash.Hash
+	want     []byte
+}
+
+// newChecksumVerifier parses a go-getter style "type:hex" checksum; the type may be
+// omitted and is then inferred from the length
+func newChecksumVerifier(checksum string) (*checksumVerifier, error) {
+	v := &checksumVerifier{checksum: checksum}
+	if checksum == "" {
+		return v, nil
+	}
+
+	kind, value, ok := strings.Cut(checksum, ":")
+	if !ok {
+		kind, value = "", checksum
+	}
+	want, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid checksum %q", ErrUnsupportedSource, checksum)
+	}
+
+	if kind == "" {
+		switch len(want) {
+		case md5.Size:
+			kind = "md5"
+		case sha1.Size:
+			kind = "sha1"
+		case sha256.Size:
+			kind = "sha256"
+		case sha512.Size:
+			kind = "sha512"
+		}
+	}
+
+	switch kind {
+	case "md5":
+		v.hash = md5.New()
+	case "sha1":
+		v.hash = sha1.New()
+	case "sha256":
+		v.hash = sha256.New()
+	case "sha512":
+		v.hash = sha512.New()
+	default:
+		return nil, fmt.Errorf("%w: unsupported checksum type in %q", ErrUnsupportedSource, checksum)
+	}
+	if len(want) != v.hash.Size() {
+		return nil, fmt.Errorf("%w: invalid %s checksum length in %q", ErrUnsupportedSource, kind, checksum)
+	}
+
+	v.kind = kind
+	v.want = want
+	return v, nil
+}
+
+// Read implements io.Reader
+func (v *checksumVerifier) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.read += int64(n)
+	if v.read > v.limit {
+		return n, fmt.Errorf("%w: %s is larger than %d bytes", ErrArchiveTooLarge, v.url, v.limit)
+	}
+	if v.hash != nil {
+		v.hash.Write(p[:n])
+	}
+	if err == io.EOF && v.hash != nil {
+		if got := v.hash.Sum(nil); !bytes.Equal(got, v.want) {
+			return n, fmt.Errorf("%w: %s has checksum %s, expected %s", ErrChecksumMismatch, v.url, hex.EncodeToString(got), v.checksum)
+		}
+	}
+	return n, timeoutError(err)
+}
+
+// walk calls fn for each regular file in the archive with its checked relative path,
+// then reads the archive to the end so that the checksum is verified. Directories and
+// links are skipped.
+func (a *ModuleArchive) walk(fn func(name string, mode fs.FileMode, r io.Reader) error) error {
+	extracted := &extractLimit{limit: a.limit}
+
+	switch a.Format {
+	case ArchiveTarGz, ArchiveTar:
+		var r io.Reader = a
+		if a.Format == ArchiveTarGz {
+			gz, err := gzip.NewReader(a)
+			if err != nil {
+				return fmt.Errorf("error reading archive: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("error reading archive: %w", err)
+			}
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+
+			name, ok, err := archiveEntryPath(header.Name, a.strip)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if err := fn(name, header.FileInfo().Mode().Perm(), extracted.reader(tr)); err != nil {
+				return err
+			}
+		}
+
+	case ArchiveZip:
+		// Zip archives need random access, so they are read into memory first
+		data, err := io.ReadAll(a)
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("error reading archive: %w", err)
+		}
+
+		for _, f := range zr.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+
+			name, ok, err := archiveEntryPath(f.Name, a.strip)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("error reading %s from archive: %w", f.Name, err)
+			}
+			err = fn(name, f.Mode().Perm(), extracted.reader(rc))
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("%w: archive format %q", ErrUnsupportedSource, a.Format)
+	}
+
+	// Read any trailing data so the checksum covers the whole archive
+	if _, err := io.Copy(io.Discard, a); err != nil {
+		return err
+	}
+	return nil
+}
+
+// archiveEntryPath checks an archive entry name and returns it relative to the archive
+// root after stripping leading elements. Entries with absolute paths or ".." elements
+// are rejected; ok is false for entries that are stripped entirely.
+func archiveEntryPath(name string, strip int) (string, bool, error) {
+	normalized := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(normalized, "/") || (len(normalized) >= 2 && normalized[1] == ':') {
+		return "", false, fmt.Errorf("%w: %q", ErrUnsafeArchivePath, name)
+	}
+
+	var parts []string
+	for _, part := range strings.Split(normalized, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			return "", false, fmt.Errorf("%w: %q", ErrUnsafeArchivePath, name)
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) <= strip {
+		return "", false, nil
+	}
+	return path.Join(parts[strip:]...), true, nil
+}
+
+// extractLimit bounds the total size of extracted files
+type extractLimit struct {
+	limit int64
+	total int64
+}
+
+// reader counts the bytes read from r against the limit
+func (e *extractLimit) reader(r io.Reader) io.Reader {
+	return &extractLimitReader{e: e, r: r}
+}
+
+type extractLimitReader struct {
+	e *extractLimit
+	r io.Reader
+}
+
+// Read implements io.Reader
+func (l *extractLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.e.total += int64(n)
+	if l.e.total > l.e.limit {
+		return n, fmt.Errorf("%w: extracted files are larger than %d bytes", ErrArchiveTooLarge, l.e.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,232 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadArchive downloads a module version's archive like OpenArchive and extracts it
+// into destDir, which is created if needed and must be empty. Entries with absolute
+// paths or ".." elements fail the extraction with ErrUnsafeArchivePath; links are
+// skipped. It returns the module directory, which is destDir joined with the source's
+// subdirectory, if any. Entries are extracted into a temporary directory next to destDir
+// and moved into it once the whole archive has been read and its checksum verified, so
+// destDir stays empty when extraction fails.
+func (s *ModulesService) DownloadArchive(ctx context.Context, namespace, name, provider, version, destDir string) (string, error) {
+	if err := validateModuleParams(namespace, name, provider, version); err != nil {
+		return "", err
+	}
+
+	// The policy is checked before destDir is created
+	ref := ModuleRef{Namespace: namespace, Name: name, Provider: provider, Version: version}
+	if err := s.checkModuleDownload(ctx, "Modules.DownloadArchive", ref); err != nil {
+		return "", err
+	}
+
+	if err := prepareArchiveDir(destDir); err != nil {
+		return "", err
+	}
+
+	archive, err := s.openArchive(ctx, namespace, name, provider, version)
+	if err != nil {
+		return "", err
+	}
+	defer archive.Close()
+
+	// Tar entries are streamed before the checksum at the end of the archive is checked,
+	// so they are staged until the archive has been verified
+	clean := filepath.Clean(destDir)
+	staging, err := os.MkdirTemp(filepath.Dir(clean), "."+filepath.Base(clean)+".extract-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create extraction directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	err = archive.walk(func(name string, mode fs.FileMode, r io.Reader) error {
+		return writeArchiveFile(staging, name, mode, r)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", archive.URL, err)
+	}
+	if err := moveArchiveFiles(staging, destDir); err != nil {
+		return "", err
+	}
+
+	moduleDir := destDir
+	if archive.Subdir != "" {
+		subdir, ok, err := archiveEntryPath(archive.Subdir, 0)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			moduleDir = filepath.Join(destDir, filepath.FromSlash(subdir))
+		}
+	}
+	return moduleDir, nil
+}
+
+// SaveArchive downloads a module version's archive to path without extracting it,
+// following the download source like OpenArchive and verifying its published checksum.
+// It uses Client.DownloadFile, so large archives are downloaded in parallel ranges and
+// an interrupted download is resumed by the next call. Archives larger than the archive
+// size limit fail with ErrArchiveTooLarge.
+func (s *ModulesService) SaveArchive(ctx context.Context, namespace, name, provider, version, path string) (*SavedArchive, error) {
+	if err := validateModuleParams(namespace, name, provider, version); err != nil {
+		return nil, err
+	}
+
+	ref := ModuleRef{Namespace: namespace, Name: name, Provider: provider, Version: version}
+	if err := s.checkModuleDownload(ctx, "Modules.SaveArchive", ref); err != nil {
+		return nil, err
+	}
+
+	downloadPath := fmt.Sprintf("modules/%s/%s/%s/%s/download", namespace, name, provider, version)
+	req, err := s.client.newRequest(ctx, http.MethodGet, downloadPath, "v1", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.client.downloadSource(req)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := parseArchiveSource(req.URL, source)
+	if err != nil {
+		return nil, err
+	}
+	archiveURL, err := url.Parse(archive.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrUnsupportedSource, archive.URL, err)
+	}
+
+	download := DownloadRequest{
+		URL:      archive.URL,
+		Checksum: archive.Checksum,
+		MaxSize:  s.client.config.MaxArchiveSize,
+		Header:   http.Header{},
+	}
+	if download.MaxSize <= 0 {
+		download.MaxSize = DefaultMaxArchiveSize
+	}
+	if auth := s.client.archiveAuthorization(req.URL, archiveURL); auth != "" {
+		download.Header.Set("Authorization", auth)
+	}
+
+	result, err := s.client.DownloadFile(ctx, download, path)
+	if errors.Is(err, ErrDownloadTooLarge) {
+		return nil, fmt.Errorf("%w: %v", ErrArchiveTooLarge, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if archive.Format == "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		archive.Format = sniffArchiveFormat(bufio.NewReader(f))
+		f.Close()
+	}
+
+	return &SavedArchive{
+		Source:   archive.Source,
+		URL:      archive.URL,
+		Format:   archive.Format,
+		Subdir:   archive.Subdir,
+		Checksum: archive.Checksum,
+		Path:     path,
+		Download: result,
+	}, nil
+}
+
+// prepareArchiveDir creates the extraction directory and checks that it is empty
+func prepareArchiveDir(dir string) error {
+	if dir == "" {
+		return &ValidationError{
+			Field:   "destDir",
+			Value:   dir,
+			Message: "destination directory cannot be empty",
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open destination directory: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Readdirnames(1); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return fmt.Errorf("failed to read destination directory: %w", err)
+		}
+		return &ValidationError{
+			Field:   "destDir",
+			Value:   dir,
+			Message: "destination directory is not empty",
+		}
+	}
+	return nil
+}
+
+// moveArchiveFiles moves the extracted entries from staging into dir. When a move fails,
+// the entries already moved are removed again so that dir stays empty.
+func moveArchiveFiles(staging, dir string) error {
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("failed to read extraction directory: %w", err)
+	}
+
+	for i, entry := range entries {
+		if err := os.Rename(filepath.Join(staging, entry.Name()), filepath.Join(dir, entry.Name())); err != nil {
+			for _, moved := range entries[:i] {
+				os.RemoveAll(filepath.Join(dir, moved.Name()))
+			}
+			return fmt.Errorf("failed to move %s into %s: %w", entry.Name(), dir, err)
+		}
+	}
+	return nil
+}
+
+// writeArchiveFile writes an extracted file below dir. The relative name has already
+// been checked; the final path is checked again as a safeguard.
+func writeArchiveFile(dir, relative string, mode fs.FileMode, r io.Reader) error {
+	path := filepath.Join(dir, filepath.FromSlash(relative))
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %q", ErrUnsafeArchivePath, relative)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", relative, err)
+	}
+
+	if mode == 0 {
+		mode = 0o644
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode|0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", relative, err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", relative, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,17 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+import "context"
+
+// DownloadArchive reports that extracting archives is unavailable in this build; use
+// OpenArchive and ModuleArchive.Files instead
+func (s *ModulesService) DownloadArchive(ctx context.Context, namespace, name, provider, version, destDir string) (string, error) {
+	return "", ErrFilesystemUnsupported
+}
+
+// SaveArchive reports that saving archives is unavailable in this build; use OpenArchive
+// instead
+func (s *ModulesService) SaveArchive(ctx context.Context, namespace, name, provider, version, path string) (*SavedArchive, error) {
+	return nil, ErrFilesystemUnsupported
+}
@@ -0,0 +1,245 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTFCAPIURL is the Terraform Cloud API used for audit trail lookups
+const DefaultTFCAPIURL = "https://app.terraform.io/api/v2"
+
+// maxAuditPages bounds the number of audit trail pages read by one fetch
+const maxAuditPages = 100
+
+// AuditService reads the Terraform Cloud organization audit trail for events
+// about private registry modules and providers. It requires an organization
+// token, configured with WithAuditTrail.
+type AuditService struct {
+	client *Client
+}
+
+// AuditEvent is an audit trail event
+type AuditEvent struct {
+	ID        string        `json:"id"`
+	Version   string        `json:"version"`
+	Type      string        `json:"type"`
+	Timestamp time.Time     `json:"timestamp"`
+	Auth      AuditAuth     `json:"auth"`
+	Request   AuditRequest  `json:"request"`
+	Resource  AuditResource `json:"resource"`
+}
+
+// AuditAuth identifies who performed an audited action
+type AuditAuth struct {
+	AccessorID     string `json:"accessor_id"`
+	Description    string `json:"description"`
+	Type           string `json:"type"`
+	ImpersonatorID string `json:"impersonator_id,omitempty"`
+	OrganizationID string `json:"organization_id"`
+}
+
+// AuditRequest identifies the API request that caused an event
+type AuditRequest struct {
+	ID string `json:"id"`
+}
+
+// AuditResource is the resource an event applies to
+type AuditResource struct {
+	ID     string                 `json:"id"`
+	Type   string                 `json:"type"`
+	Action string                 `json:"action"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// IsRegistryEvent reports whether the event concerns a registry module or provider
+func (e AuditEvent) IsRegistryEvent() bool {
+	return strings.HasPrefix(e.Resource.Type, "registry_")
+}
+
+// AuditCursor marks the position reached in the audit trail. The zero cursor starts
+// at the oldest retained event. Cursors are JSON-serializable so they can be persisted
+// between runs.
+type AuditCursor struct {
+	// Since is the timestamp of the newest event returned so far
+	Since time.Time `json:"since"`
+
+	// SeenIDs are the IDs of returned events with the Since timestamp; the audit trail
+	// filter is inclusive, so these are skipped on the next fetch
+	SeenIDs []string `json:"seen_ids,omitempty"`
+}
+
+// AuditPage is the result of an incremental audit trail fetch
+type AuditPage struct {
+	// Events are the new registry events, oldest first
+	Events []AuditEvent
+
+	// Cursor is the position to pass to the next fetch
+	Cursor AuditCursor
+}
+
+// WithAuditTrail sets the Terraform Cloud API base URL and the organization token used
+// to read the audit trail. An empty baseURL uses DefaultTFCAPIURL.
+func WithAuditTrail(baseURL, token string) ClientOption {
+	return func(c *ClientConfig) {
+		c.AuditAPIURL = baseURL
+		c.AuditToken = token
+	}
+}
+
+// FetchRegistryEvents returns the registry module and provider events recorded after
+// cursor, such as publishes and deletions, and the cursor to resume from
+func (s *AuditService) FetchRegistryEvents(ctx context.Context, cursor AuditCursor) (*AuditPage, error) {
+	if s.client.config.AuditToken == "" {
+		return nil, fmt.Errorf("%w: audit trail requires an organization token, see WithAuditTrail", ErrInvalidConfiguration)
+	}
+
+	seen := make(map[string]bool, len(cursor.SeenIDs))
+	for _, id := range cursor.SeenIDs {
+		seen[id] = true
+	}
+
+	var events []AuditEvent
+	for page := 1; page <= maxAuditPages; page++ {
+		result, err := s.fetchPage(ctx, cursor.Since, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch audit trail: %w", err)
+		}
+
+		for _, event := range result.Data {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				events = append(events, event)
+			}
+		}
+
+		if result.Pagination.NextPage == nil || *result.Pagination.NextPage <= page {
+			break
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	// Advance past every event read so unrelated events are not fetched again
+	result := &AuditPage{Cursor: advanceAuditCursor(cursor, events)}
+	for _, event := range events {
+		if event.IsRegistryEvent() {
+			result.Events = append(result.Events, event)
+		}
+	}
+
+	return result, nil
+}
+
+// Tail polls the audit trail every interval and calls fn with each non-empty batch of
+// new registry events and the cursor after it. It returns when ctx is done or fn or a
+// fetch fails. Persisting the cursor passed to fn allows resuming after a restart.
+func (s *AuditService) Tail(ctx context.Context, cursor AuditCursor, interval time.Duration, fn func(events []AuditEvent, cursor AuditCursor) error) error {
+	if interval <= 0 {
+		return &ValidationError{
+			Field:   "interval",
+			Value:   interval,
+			Message: "interval must be positive",
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		page, err := s.FetchRegistryEvents(ctx, cursor)
+		if err != nil {
+			return err
+		}
+
+		cursor = page.Cursor
+		if len(page.Events) > 0 {
+			if err := fn(page.Events, cursor); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// auditTrailPage is a page of the audit trail API
+type auditTrailPage struct {
+	Data       []AuditEvent `json:"data"`
+	Pagination struct {
+		CurrentPage int  `json:"current_page"`
+		NextPage    *int `json:"next_page"`
+		TotalPages  int  `json:"total_pages"`
+		TotalCount  int  `json:"total_count"`
+	} `json:"pagination"`
+}
+
+// fetchPage requests one page of the audit trail
+func (s *AuditService) fetchPage(ctx context.Context, since time.Time, page int) (*auditTrailPage, error) {
+	config := s.client.config
+
+	baseURL := config.AuditAPIURL
+	if baseURL == "" {
+		baseURL = DefaultTFCAPIURL
+	}
+
+	values := url.Values{}
+	if !since.IsZero() {
+		values.Add("since", since.UTC().Format(time.RFC3339Nano))
+	}
+	values.Add("page[number]", strconv.Itoa(page))
+	values.Add("page[size]", "100")
+
+	endpoint := fmt.Sprintf("%s/organization/audit-trail?%s", strings.TrimSuffix(baseURL, "/"), values.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    endpoint,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", s.client.userAgent)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.AuditToken))
+
+	var result auditTrailPage
+	if err := s.client.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// advanceAuditCursor returns the cursor following events, which must be sorted oldest first
+func advanceAuditCursor(cursor AuditCursor, events []AuditEvent) AuditCursor {
+	if len(events) == 0 {
+		return cursor
+	}
+
+	newest := events[len(events)-1].Timestamp
+
+	next := AuditCursor{Since: newest}
+	if newest.Equal(cursor.Since) {
+		next.SeenIDs = append(next.SeenIDs, cursor.SeenIDs...)
+	}
+	for _, event := range events {
+		if event.Timestamp.Equal(newest) {
+			next.SeenIDs = append(next.SeenIDs, event.ID)
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the default number of concurrent requests made by batch methods
+const DefaultBatchConcurrency = 5
+
+// BatchResult holds the outcome for a single key of a batch operation
+type BatchResult[T any] struct {
+	// Value is the fetched item; the zero value when Err is set
+	Value T
+
+	// Err is the error for this key, if any
+	Err error
+}
+
+// runBatch calls fn for every distinct key using at most concurrency workers and
+// collects a result per key. Keys not started before ctx is cancelled get ctx.Err().
+func runBatch[T any](ctx context.Context, keys []string, concurrency int, fn func(ctx context.Context, key string) (T, error)) map[string]BatchResult[T] {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	unique := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	results := make(map[string]BatchResult[T], len(unique))
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for _, key := range unique {
+		select {
+		case <-ctx.Done():
+			mu.Lock()
+			results[key] = BatchResult[T]{Err: ctx.Err()}
+			mu.Unlock()
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			value, err := fn(ctx, key)
+
+			mu.Lock()
+			results[key] = BatchResult[T]{Value: value, Err: err}
+			mu.Unlock()
+		}(key)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// flightGroup coalesces concurrent calls with the same key into one call, whose result
+// is shared with every caller
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a call in flight
+type flightCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// coalesce calls fn, or waits for the call in flight for the same key and returns its
+// result. A caller whose context is cancelled stops waiting; the call itself runs with the
+// context of the caller that started it, so when that context ends the call, a waiting
+// caller whose own context is still live makes the call again.
+func coalesce[T any](ctx context.Context, g *flightGroup, key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		if isContextError(call.err) && ctx.Err() == nil {
+			return coalesce(ctx, g, key, fn)
+		}
+		value, _ := call.value.(T)
+		return value, call.err
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	value, err := fn()
+	call.value, call.err = value, err
+	return value, err
+}
+
+// isContextError reports whether err comes from a cancelled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"context"
+	"strings"
+)
+
+// GetMany fetches several modules concurrently. IDs are in namespace/name/provider/version
+// form, or namespace/name/provider for the latest version. Failures, including malformed
+// IDs, are reported per ID rather than failing the whole call. Identical requests in
+// flight, from this or concurrent calls, are made once and share their result, which
+// callers must not modify.
+func (s *ModulesService) GetMany(ctx context.Context, ids []string) (map[string]BatchResult[*ModuleDetails], error) {
+	if len(ids) == 0 {
+		return nil, &ValidationError{
+			Field:   "ids",
+			Message: "at least one module ID is required",
+		}
+	}
+
+	return runBatch(ctx, ids, DefaultBatchConcurrency, func(ctx context.Context, id string) (*ModuleDetails, error) {
+		if strings.Count(id, "/") == 2 {
+			parts := strings.Split(id, "/")
+			if err := validateModuleParams(parts[0], parts[1], parts[2], ""); err != nil {
+				return nil, err
+			}
+			return coalesce(ctx, &s.flights, id, func() (*ModuleDetails, error) {
+				return s.GetLatest(ctx, parts[0], parts[1], parts[2])
+			})
+		}
+
+		namespace, name, provider, version, err := ParseModuleID(id)
+		if err != nil {
+			return nil, &ValidationError{Field: "id", Value: id, Message: err.Error()}
+		}
+		return coalesce(ctx, &s.flights, id, func() (*ModuleDetails, error) {
+			return s.Get(ctx, namespace, name, provider, version)
+		})
+	}), nil
+}
+
+// GetMany fetches several providers concurrently, each at its ref's version or the
+// latest version. Failures are reported per ref rather than failing the whole call.
+// Identical requests in flight, from this or concurrent calls, are made once and share
+// their result, which callers must not modify.
+func (s *ProvidersService) GetMany(ctx context.Context, refs []ProviderRef) (map[ProviderRef]BatchResult[*ProviderInfo], error) {
+	if len(refs) == 0 {
+		return nil, &ValidationError{
+			Field:   "refs",
+			Message: "at least one provider is required",
+		}
+	}
+
+	byKey := make(map[string]ProviderRef, len(refs))
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		key := ref.String()
+		byKey[key] = ref
+		keys = append(keys, key)
+	}
+
+	results := runBatch(ctx, keys, DefaultBatchConcurrency, func(ctx context.Context, key string) (*ProviderInfo, error) {
+		return coalesce(ctx, &s.flights, key, func() (*ProviderInfo, error) {
+			return s.GetInfo(ctx, byKey[key])
+		})
+	})
+
+	batch := make(map[ProviderRef]BatchResult[*ProviderInfo], len(results))
+	for key, result := range results {
+		batch[byKey[key]] = result
+	}
+	return batch, nil
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPartialResult is matched by PartialResultError
+var ErrPartialResult = errors.New("partial result")
+
+// errBudgetExpired is the cancellation cause of a best effort budget
+var errBudgetExpired = errors.New("best effort budget expired")
+
+// WithBestEffort gives long composite operations a time budget. When it expires they
+// stop fetching and return what they gathered so far together with a
+// *PartialResultError, so interactive callers can show a partial result quickly and
+// refine it later. It applies to Providers.GetProviderResourceSummary (which resumes
+// from its checkpoint store, if any, on the next call), Providers.GetSchema and
+// Modules.SearchExpanded. The caller's own context deadline still fails the operation
+// as usual.
+func WithBestEffort(maxDuration time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.BestEffort = maxDuration
+	}
+}
+
+// PartialResultError is returned alongside a partial result when the best effort budget
+// of a composite operation expires
+type PartialResultError struct {
+	// Operation names the composite operation, e.g. "provider resource summary"
+	Operation string
+
+	// Budget is the configured best effort duration
+	Budget time.Duration
+
+	// Completed and Total count the items the operation fetched and needed
+	Completed int
+	Total     int
+
+	// Err holds failures of fetched items unrelated to the budget, if any
+	Err error
+}
+
+// Error implements the error interface
+func (e *PartialResultError) Error() string {
+	msg := fmt.Sprintf("%s: partial result after %s (%d of %d items)", e.Operation, e.Budget, e.Completed, e.Total)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the failures unrelated to the budget
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
+}
+
+// Is implements error matching
+func (e *PartialResultError) Is(target error) bool {
+	return target == ErrPartialResult
+}
+
+// IsPartialResult returns true if the error reports a partial best effort result
+func IsPartialResult(err error) bool {
+	return errors.Is(err, ErrPartialResult)
+}
+
+// bestEffortContext derives the context of a composite operation from the best effort
+// budget; without a budget it returns ctx with a no-op cancel
+func (c *Client) bestEffortContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.BestEffort <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, c.config.BestEffort, errBudgetExpired)
+}
+
+// budgetExpired reports whether ctx was ended by the best effort budget rather than by
+// the caller
+func budgetExpired(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errBudgetExpired)
+}
+
+// partialResult builds the error of an operation whose budget expired
+func (c *Client) partialResult(operation string, completed, total int, errs *MultiError) *PartialResultError {
+	partial := &PartialResultError{
+		Operation: operation,
+		Budget:    c.config.BestEffort,
+		Completed: completed,
+		Total:     total,
+	}
+	if errs != nil {
+		partial.Err = errs.ErrorOrNil()
+	}
+	return partial
+}
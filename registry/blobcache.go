@@ -0,0 +1,215 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBlobCacheTimeout is the default time limit of one blob store operation
+const DefaultBlobCacheTimeout = 10 * time.Second
+
+// ErrBlobNotFound is returned by BlobStore.Get for missing objects
+var ErrBlobNotFound = errors.New("blob not found")
+
+// IsBlobNotFound returns true if a blob store reported a missing object
+func IsBlobNotFound(err error) bool {
+	return errors.Is(err, ErrBlobNotFound)
+}
+
+// BlobStore is an object store holding the entries of a BlobCache, such as an S3 or GCS
+// bucket. Implementations must be safe for concurrent use.
+type BlobStore interface {
+	// Get returns the object stored under key, or ErrBlobNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores an object under key, replacing any previous one
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the object stored under key; missing objects are not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobCacheOptions controls NewBlobCache
+type BlobCacheOptions struct {
+	// Prefix is prepended to object keys, such as "terralense/cache/"
+	Prefix string
+
+	// LocalSize is the number of entries kept in a local memory cache in front of the
+	// store (DefaultMemoryCacheSize when zero); negative disables the local cache
+	LocalSize int
+
+	// Timeout limits each store operation (DefaultBlobCacheTimeout when zero)
+	Timeout time.Duration
+}
+
+// BlobCache is a read-through Cache over a BlobStore, so that CI runners and service
+// replicas share one registry cache. Entries are looked up in a local memory cache
+// first, then in the store; entries read from the store are kept locally. Writes go to
+// both. Store errors are treated as misses and lost writes; the response is fetched
+// again later.
+type BlobCache struct {
+	store   BlobStore
+	prefix  string
+	timeout time.Duration
+	local   *MemoryCache
+}
+
+// blobCacheObject is the content of a BlobCache object
+type blobCacheObject struct {
+	Key         string      `json:"key"`
+	Entry       *CacheEntry `json:"entry"`
+	RetainUntil time.Time   `json:"retain_until,omitempty"`
+}
+
+// NewBlobCache creates a cache storing entries in store
+func NewBlobCache(store BlobStore, opts *BlobCacheOptions) *BlobCache {
+	if opts == nil {
+		opts = &BlobCacheOptions{}
+	}
+
+	cache := &BlobCache{store: store, prefix: opts.Prefix, timeout: opts.Timeout}
+	if cache.timeout <= 0 {
+		cache.timeout = DefaultBlobCacheTimeout
+	}
+	if opts.LocalSize >= 0 {
+		cache.local = NewMemoryCache(opts.LocalSize)
+	}
+	return cache
+}
+
+// Get implements Cache
+func (b *BlobCache) Get(key string) (*CacheEntry, bool) {
+	if b.local != nil {
+		if entry, ok := b.local.Get(key); ok {
+			return entry, true
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	data, err := b.store.Get(ctx, b.objectKey(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var object blobCacheObject
+	if err := json.Unmarshal(data, &object); err != nil || object.Key != key || object.Entry == nil {
+		return nil, false
+	}
+	if !object.RetainUntil.IsZero() && time.Now().After(object.RetainUntil) {
+		b.Invalidate(key)
+		return nil, false
+	}
+
+	if b.local != nil {
+		b.local.mu.Lock()
+		b.local.setLocked(key, object.Entry, object.RetainUntil)
+		b.local.mu.Unlock()
+	}
+	return object.Entry, true
+}
+
+// Set implements Cache
+func (b *BlobCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	if b.local != nil {
+		b.local.Set(key, entry, ttl)
+	}
+
+	data, err := json.Marshal(blobCacheObject{Key: key, Entry: entry, RetainUntil: retainUntil(ttl)})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	_ = b.store.Put(ctx, b.objectKey(key), data)
+}
+
+// Invalidate implements Cache
+func (b *BlobCache) Invalidate(key string) {
+	if b.local != nil {
+		b.local.Invalidate(key)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+	_ = b.store.Delete(ctx, b.objectKey(key))
+}
+
+// objectKey returns the object key of a cache key: the prefix and a hash of the key,
+// which may contain characters object stores handle differently
+func (b *BlobCache) objectKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return b.prefix + hex.EncodeToString(sum[:]) + ".json"
+}
+
+// OpenBlobStore opens the bucket of a storage URL, returning it with the key prefix
+// given by the URL path:
+//
+//   - s3://bucket/prefix uses NewS3BlobStore with credentials, region and endpoint from
+//     the AWS_* environment variables (see S3Options)
+//   - gs://bucket/prefix uses NewGCSBlobStore with the access token in
+//     GOOGLE_OAUTH_ACCESS_TOKEN
+func OpenBlobStore(rawURL string) (BlobStore, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, "", &ValidationError{
+			Field:   "url",
+			Value:   rawURL,
+			Message: "must be s3://bucket/prefix or gs://bucket/prefix",
+		}
+	}
+
+	prefix := strings.TrimPrefix(u.Path, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	switch u.Scheme {
+	case "s3":
+		store, err := NewS3BlobStore(S3OptionsFromEnv(u.Host))
+		return store, prefix, err
+	case "gs":
+		store, err := NewGCSBlobStore(GCSOptionsFromEnv(u.Host))
+		return store, prefix, err
+	default:
+		return nil, "", &ValidationError{
+			Field:   "url",
+			Value:   rawURL,
+			Message: fmt.Sprintf("unsupported blob store scheme %q, use s3 or gs", u.Scheme),
+		}
+	}
+}
+
+// doBlobRequest sends an object store request, returning the response body of a
+// successful request and ErrBlobNotFound for a 404
+func doBlobRequest(client *http.Client, req *http.Request, store, key string) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s: %w", store, req.Method, key, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s: error reading response body: %w", store, req.Method, key, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s %s %s: %w", store, req.Method, key, ErrBlobNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s %s: %w", store, req.Method, key, newAPIError(resp, body))
+	}
+	return body, nil
+}
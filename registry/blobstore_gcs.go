@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultGCSEndpoint is the Google Cloud Storage JSON API URL
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// GCSOptions configures NewGCSBlobStore
+type GCSOptions struct {
+	Bucket string
+
+	// Endpoint is the GCS JSON API URL, DefaultGCSEndpoint when empty
+	Endpoint string
+
+	// Token returns the OAuth 2.0 access token requests are authorized with, such as the
+	// output of "gcloud auth print-access-token"; requests are sent unauthorized when nil
+	Token func(ctx context.Context) (string, error)
+
+	// HTTPClient sends the requests; http.DefaultClient when nil. A client that adds its
+	// own credentials, such as one from golang.org/x/oauth2/google, can replace Token.
+	HTTPClient *http.Client
+}
+
+// GCSOptionsFromEnv returns the options of a bucket authorized with the access token in
+// GOOGLE_OAUTH_ACCESS_TOKEN, and the endpoint in STORAGE_EMULATOR_HOST when set
+func GCSOptionsFromEnv(bucket string) GCSOptions {
+	opts := GCSOptions{Bucket: bucket}
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		opts.Token = func(context.Context) (string, error) {
+			return token, nil
+		}
+	}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+		opts.Endpoint = host
+	}
+	return opts
+}
+
+// GCSBlobStore is a BlobStore over a Google Cloud Storage bucket, using the JSON API
+// directly
+type GCSBlobStore struct {
+	opts   GCSOptions
+	client *http.Client
+}
+
+// NewGCSBlobStore creates a store for a GCS bucket
+func NewGCSBlobStore(opts GCSOptions) (*GCSBlobStore, error) {
+	if opts.Bucket == "" {
+		return nil, &ValidationError{
+			Field:   "bucket",
+			Value:   opts.Bucket,
+			Message: "bucket cannot be empty",
+		}
+	}
+
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultGCSEndpoint
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GCSBlobStore{opts: opts, client: client}, nil
+}
+
+// Get implements BlobStore
+func (g *GCSBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return g.do(ctx, http.MethodGet, g.objectURL(key)+"?alt=media", key, nil)
+}
+
+// Put implements BlobStore
+func (g *GCSBlobStore) Put(ctx context.Context, key string, data []byte) error {
+	upload := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		g.opts.Endpoint, url.PathEscape(g.opts.Bucket), url.QueryEscape(key))
+	_, err := g.do(ctx, http.MethodPost, upload, key, data)
+	return err
+}
+
+// Delete implements BlobStore
+func (g *GCSBlobStore) Delete(ctx context.Context, key string) error {
+	_, err := g.do(ctx, http.MethodDelete, g.objectURL(key), key, nil)
+	if IsBlobNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// objectURL returns the JSON API URL of an object
+func (g *GCSBlobStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.opts.Endpoint, url.PathEscape(g.opts.Bucket), url.PathEscape(key))
+}
+
+// do sends an authorized object request
+func (g *GCSBlobStore) do(ctx context.Context, method, target, key string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS request: %w", err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if g.opts.Token != nil {
+		token, err := g.opts.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("gcs %s %s: failed to get access token: %w", method, key, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return doBlobRequest(g.client, req, "gcs", key)
+}
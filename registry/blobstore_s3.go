@@ -0,0 +1,219 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Options configures NewS3BlobStore
+type S3Options struct {
+	Bucket string
+
+	// Region is the bucket region, "us-east-1" when empty
+	Region string
+
+	// Endpoint is the S3 API URL, https://s3.<region>.amazonaws.com when empty. Set it for
+	// S3-compatible stores such as MinIO, or https://storage.googleapis.com for GCS with
+	// HMAC keys. Buckets are addressed path-style.
+	Endpoint string
+
+	// AccessKeyID, SecretAccessKey and SessionToken are the credentials requests are
+	// signed with (Signature Version 4); requests are sent unsigned without them
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// HTTPClient sends the requests; http.DefaultClient when nil
+	HTTPClient *http.Client
+}
+
+// S3OptionsFromEnv returns the options of a bucket with the credentials, region and
+// endpoint of the environment: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN, AWS_REGION (or AWS_DEFAULT_REGION) and AWS_ENDPOINT_URL_S3 (or
+// AWS_ENDPOINT_URL)
+func S3OptionsFromEnv(bucket string) S3Options {
+	firstEnv := func(names ...string) string {
+		for _, name := range names {
+			if value := os.Getenv(name); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	return S3Options{
+		Bucket:          bucket,
+		Region:          firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		Endpoint:        firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// S3BlobStore is a BlobStore over an S3 bucket, using the S3 REST API directly
+type S3BlobStore struct {
+	opts   S3Options
+	client *http.Client
+}
+
+// NewS3BlobStore creates a store for an S3 bucket
+func NewS3BlobStore(opts S3Options) (*S3BlobStore, error) {
+	if opts.Bucket == "" {
+		return nil, &ValidationError{
+			Field:   "bucket",
+			Value:   opts.Bucket,
+			Message: "bucket cannot be empty",
+		}
+	}
+	if opts.AccessKeyID != "" && opts.SecretAccessKey == "" {
+		return nil, fmt.Errorf("%w: S3 access key %s has no secret access key", ErrInvalidConfiguration, opts.AccessKeyID)
+	}
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", opts.Region)
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &S3BlobStore{opts: opts, client: client}, nil
+}
+
+// Get implements BlobStore
+func (s *S3BlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, key, nil)
+}
+
+// Put implements BlobStore
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, key, data)
+	return err
+}
+
+// Delete implements BlobStore
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	_, err := s.do(ctx, http.MethodDelete, key, nil)
+	if IsBlobNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// do sends a signed object request
+func (s *S3BlobStore) do(ctx context.Context, method, key string, body []byte) ([]byte, error) {
+	path := "/" + escapeObjectPath(s.opts.Bucket) + "/" + escapeObjectPath(key)
+	req, err := http.NewRequestWithContext(ctx, method, s.opts.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	if method == http.MethodPut {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.opts.AccessKeyID != "" {
+		signV4(req, body, s.opts, time.Now())
+	}
+
+	return doBlobRequest(s.client, req, "s3", key)
+}
+
+// signV4 signs a request with AWS Signature Version 4, covering the host and every
+// header set on the request
+func signV4(req *http.Request, body []byte, opts S3Options, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if opts.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", opts.SessionToken)
+	}
+
+	values := map[string]string{"host": req.URL.Host}
+	for name, value := range req.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(value, ","))
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var headers strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&headers, "%s:%s\n", name, values[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + opts.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + opts.SecretAccessKey)
+	for _, part := range []string{date, opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sha256Hex returns the hex-encoded SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapeObjectPath percent-encodes an object key as S3 expects in paths: every byte
+// except unreserved characters and slashes
+func escapeObjectPath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
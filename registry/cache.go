@@ -0,0 +1,439 @@
+package registry
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+const (
+	// DefaultCacheTTL is the default time cached responses are served without revalidation
+	DefaultCacheTTL = 5 * time.Minute
+
+	// DefaultMemoryCacheSize is the default number of responses kept by NewMemoryCache
+	DefaultMemoryCacheSize = 1000
+
+	// cacheRevalidateWindow is how long stale responses with an ETag or Last-Modified
+	// validator are kept for conditional requests
+	cacheRevalidateWindow = 24 * time.Hour
+)
+
+// Cache stores API responses. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored under key, fresh or stale
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores an entry under key for at most ttl; zero keeps it until evicted
+	Set(key string, entry *CacheEntry, ttl time.Duration)
+
+	// Invalidate removes the entry stored under key
+	Invalidate(key string)
+}
+
+// CacheEntry is a cached API response
+type CacheEntry struct {
+	// Body is the raw response body
+	Body []byte `json:"body"`
+
+	// ETag and LastModified are the response validators used for conditional requests
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// Expires is when the entry stops being fresh; stale entries with a validator are
+	// revalidated with a conditional request, others are fetched again
+	Expires time.Time `json:"expires"`
+
+	// StatusCode, Header and RequestHeader are only set by HTTPCacheMiddleware, which
+	// replays whole responses. RequestHeader holds the request headers named by the
+	// response's Vary header.
+	StatusCode    int         `json:"status_code,omitempty"`
+	Header        http.Header `json:"header,omitempty"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+}
+
+// Fresh reports whether the entry can be served without contacting the registry
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// hasValidator reports whether the entry can be revalidated
+func (e *CacheEntry) hasValidator() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// WithCache caches GET responses in cache. Responses are served from the cache for ttl
+// (DefaultCacheTTL when zero) unless the response set a Cache-Control max-age; stale
+// responses with an ETag or Last-Modified header are revalidated with a conditional
+// request. Responses marked Cache-Control no-store are not cached.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.Cache = cache
+		c.CacheTTL = ttl
+	}
+}
+
+// WithCacheRevalidation revalidates cached responses on every request instead of serving
+// them while fresh, so that the cache only saves transferring unchanged bodies: requests
+// carry the stored ETag or Last-Modified validator and a 304 Not Modified reply is served
+// from the cache. It applies to caches set with WithCache.
+func WithCacheRevalidation() ClientOption {
+	return func(c *ClientConfig) {
+		c.CacheRevalidate = true
+	}
+}
+
+var bypassCacheKey = registryctx.NewKey[bool]("bypass-cache")
+
+// BypassCache returns a context whose requests skip fresh cached responses, forcing
+// revalidation for one call. Responses are still revalidated and stored.
+func BypassCache(ctx context.Context) context.Context {
+	return bypassCacheKey.With(ctx, true)
+}
+
+// InvalidateCache removes the cached response for an API path, such as
+// InvalidateCache("v2", "providers/hashicorp/aws")
+func (c *Client) InvalidateCache(version, path string) {
+	if c.config.Cache == nil {
+		return
+	}
+
+	req, err := c.newRequest(context.Background(), http.MethodGet, path, version, nil)
+	if err != nil {
+		return
+	}
+	c.config.Cache.Invalidate(responseCacheKey(req))
+}
+
+// responseCacheKey returns the cache key of a request: its URL, plus a fingerprint of
+// the credentials so that clients with different tokens can share a cache
+func responseCacheKey(req *http.Request) string {
+	key := req.URL.String()
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		key += "#" + hex.EncodeToString(sum[:8])
+	}
+	return key
+}
+
+// cachedResponse returns the cache key and cached entry of a cacheable request
+func (c *Client) cachedResponse(req *http.Request) (string, *CacheEntry) {
+	if c.config.Cache == nil || req.Method != http.MethodGet {
+		return "", nil
+	}
+
+	key := responseCacheKey(req)
+	entry, ok := c.config.Cache.Get(key)
+	if !ok {
+		return key, nil
+	}
+	return key, entry
+}
+
+// hasFreshResponse reports whether a request will be served from the cache
+func (c *Client) hasFreshResponse(req *http.Request) bool {
+	if bypass, _ := bypassCacheKey.Get(req.Context()); bypass || c.config.CacheRevalidate {
+		return false
+	}
+	_, entry := c.cachedResponse(req)
+	return entry != nil && entry.Fresh(time.Now())
+}
+
+// storeResponse caches a successful response according to its Cache-Control header
+func (c *Client) storeResponse(key string, header http.Header, body []byte) {
+	if key == "" {
+		return
+	}
+
+	freshness, store := c.cacheFreshness(header)
+	entry := &CacheEntry{
+		Body:         body,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Expires:      time.Now().Add(freshness),
+	}
+
+	// Entries that are never fresh are only useful for conditional requests
+	if !store || (freshness <= 0 && !entry.hasValidator()) {
+		c.config.Cache.Invalidate(key)
+		return
+	}
+
+	retention := freshness
+	if entry.hasValidator() {
+		retention += cacheRevalidateWindow
+	}
+	c.config.Cache.Set(key, entry, retention)
+}
+
+// refreshResponse extends a revalidated entry after a 304 Not Modified response
+func (c *Client) refreshResponse(key string, entry *CacheEntry, header http.Header) {
+	refreshed := *entry
+	if etag := header.Get("ETag"); etag != "" {
+		refreshed.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		refreshed.LastModified = lastModified
+	}
+	c.storeResponse(key, http.Header{
+		"Cache-Control": header.Values("Cache-Control"),
+		"Etag":          []string{refreshed.ETag},
+		"Last-Modified": []string{refreshed.LastModified},
+	}, refreshed.Body)
+}
+
+// cacheFreshness returns how long a response is fresh and whether it may be stored
+func (c *Client) cacheFreshness(header http.Header) (time.Duration, bool) {
+	freshness := c.config.CacheTTL
+	if freshness <= 0 {
+		freshness = DefaultCacheTTL
+	}
+
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+			switch name {
+			case "no-store":
+				return 0, false
+			case "no-cache":
+				freshness = 0
+			case "max-age":
+				if seconds, err := strconv.Atoi(arg); err == nil && freshness > 0 {
+					freshness = time.Duration(seconds) * time.Second
+				}
+			}
+		}
+	}
+
+	return freshness, true
+}
+
+// CacheStats counts the requests answered by the response cache since the client was
+// created or ResetCacheStats was called
+type CacheStats struct {
+	// Hits is the number of requests served from fresh cached responses
+	Hits int64
+
+	// Revalidated is the number of conditional requests answered with 304 Not Modified,
+	// served from the cache without transferring the body
+	Revalidated int64
+
+	// Misses is the number of cacheable requests answered with a full response, including
+	// conditional requests whose cached response had changed
+	Misses int64
+}
+
+// HitRate returns the share of cacheable requests served from the cache, counting
+// revalidated responses as hits; zero before any request
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Revalidated + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.Revalidated) / float64(total)
+}
+
+// CacheStats returns the response cache counters; all zero without WithCache
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:        c.cacheStats.hits.Load(),
+		Revalidated: c.cacheStats.revalidated.Load(),
+		Misses:      c.cacheStats.misses.Load(),
+	}
+}
+
+// ResetCacheStats sets the response cache counters to zero
+func (c *Client) ResetCacheStats() {
+	c.cacheStats.hits.Store(0)
+	c.cacheStats.revalidated.Store(0)
+	c.cacheStats.misses.Store(0)
+}
+
+// cacheCounters holds the counters of CacheStats
+type cacheCounters struct {
+	hits        atomic.Int64
+	revalidated atomic.Int64
+	misses      atomic.Int64
+}
+
+// record counts the response to a request sent past the cache; requests that are not
+// cacheable have no key and are not counted
+func (c *cacheCounters) record(key string, cached *CacheEntry, statusCode int) {
+	switch {
+	case key == "":
+	case statusCode == http.StatusNotModified && cached != nil:
+		c.revalidated.Add(1)
+	default:
+		c.misses.Add(1)
+	}
+}
+
+// setConditionalHeaders adds the validators of a stale entry to a request
+func setConditionalHeaders(req *http.Request, entry *CacheEntry) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// MemoryCache is an in-memory least-recently-used Cache
+type MemoryCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// memoryCacheItem is a MemoryCache list element
+type memoryCacheItem struct {
+	key         string
+	entry       *CacheEntry
+	retainUntil time.Time
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding at most capacity responses
+// (DefaultMemoryCacheSize when capacity is not positive)
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCacheSize
+	}
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := element.Value.(*memoryCacheItem)
+	if !item.retainUntil.IsZero() && time.Now().After(item.retainUntil) {
+		m.removeLocked(element)
+		return nil, false
+	}
+
+	m.order.MoveToFront(element)
+	return item.entry, true
+}
+
+// Set implements Cache
+func (m *MemoryCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setLocked(key, entry, retainUntil(ttl))
+}
+
+// Invalidate implements Cache
+func (m *MemoryCache) Invalidate(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.removeLocked(element)
+	}
+}
+
+// Len returns the number of cached responses
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+// setLocked stores an entry, evicting the least recently used entries over capacity
+func (m *MemoryCache) setLocked(key string, entry *CacheEntry, until time.Time) {
+	if element, ok := m.entries[key]; ok {
+		item := element.Value.(*memoryCacheItem)
+		item.entry = entry
+		item.retainUntil = until
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry, retainUntil: until})
+
+	for m.order.Len() > m.capacity {
+		m.removeLocked(m.order.Back())
+	}
+}
+
+// removeLocked removes a list element
+func (m *MemoryCache) removeLocked(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*memoryCacheItem).key)
+}
+
+// memoryCacheState is the serialized form of a MemoryCache entry
+type memoryCacheState struct {
+	Key         string      `json:"key"`
+	Entry       *CacheEntry `json:"entry"`
+	RetainUntil time.Time   `json:"retain_until,omitempty"`
+}
+
+// exportState implements stateSection, most recently used entries first
+func (m *MemoryCache) exportState() (json.RawMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]memoryCacheState, 0, m.order.Len())
+	for element := m.order.Front(); element != nil; element = element.Next() {
+		item := element.Value.(*memoryCacheItem)
+		entries = append(entries, memoryCacheState{Key: item.key, Entry: item.entry, RetainUntil: item.retainUntil})
+	}
+	return json.Marshal(entries)
+}
+
+// importState implements stateSection, keeping entries already in the cache
+func (m *MemoryCache) importState(data json.RawMessage) error {
+	var entries []memoryCacheState
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	// Insert least recently used first so the import preserves the order
+	for i := len(entries) - 1; i >= 0; i-- {
+		state := entries[i]
+		if state.Entry == nil || (!state.RetainUntil.IsZero() && now.After(state.RetainUntil)) {
+			continue
+		}
+		if _, ok := m.entries[state.Key]; ok {
+			continue
+		}
+		m.setLocked(state.Key, state.Entry, state.RetainUntil)
+	}
+	return nil
+}
+
+// retainUntil returns the retention deadline for a ttl; zero means no deadline
+func retainUntil(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
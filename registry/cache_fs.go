@@ -0,0 +1,113 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DiskCache is a Cache that stores one JSON file per response in a directory, so cached
+// responses survive process restarts
+type DiskCache struct {
+	dir string
+}
+
+// diskCacheFile is the content of a DiskCache file
+type diskCacheFile struct {
+	Key         string      `json:"key"`
+	Entry       *CacheEntry `json:"entry"`
+	RetainUntil time.Time   `json:"retain_until,omitempty"`
+}
+
+// NewDiskCache creates a disk-backed cache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if dir == "" {
+		return nil, &ValidationError{
+			Field:   "dir",
+			Value:   dir,
+			Message: "cache directory cannot be empty",
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get implements Cache
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var file diskCacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Key != key || file.Entry == nil {
+		return nil, false
+	}
+
+	if !file.RetainUntil.IsZero() && time.Now().After(file.RetainUntil) {
+		d.Invalidate(key)
+		return nil, false
+	}
+
+	return file.Entry, true
+}
+
+// Set implements Cache. Write errors are ignored; the response is fetched again later.
+func (d *DiskCache) Set(key string, entry *CacheEntry, ttl time.Duration) {
+	data, err := json.Marshal(diskCacheFile{Key: key, Entry: entry, RetainUntil: retainUntil(ttl)})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so concurrent readers never see partial files
+	tmp, err := os.CreateTemp(d.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+
+	_ = os.Rename(tmp.Name(), d.path(key))
+}
+
+// Invalidate implements Cache
+func (d *DiskCache) Invalidate(key string) {
+	_ = os.Remove(d.path(key))
+}
+
+// Clear removes all cached responses
+func (d *DiskCache) Clear() error {
+	files, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// path returns the file holding the entry for key
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
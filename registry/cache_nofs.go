@@ -0,0 +1,25 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+import "time"
+
+// DiskCache is not available in this build; use MemoryCache or your own Cache
+type DiskCache struct{}
+
+// NewDiskCache reports that disk caching is unavailable in this build
+func NewDiskCache(dir string) (*DiskCache, error) {
+	return nil, ErrFilesystemUnsupported
+}
+
+// Get implements Cache
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) { return nil, false }
+
+// Set implements Cache
+func (d *DiskCache) Set(key string, entry *CacheEntry, ttl time.Duration) {}
+
+// Invalidate implements Cache
+func (d *DiskCache) Invalidate(key string) {}
+
+// Clear implements DiskCache.Clear
+func (d *DiskCache) Clear() error { return ErrFilesystemUnsupported }
@@ -0,0 +1,251 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Capability probe names, also used to report probe failures
+const (
+	capabilityV2Docs           = "v2 docs"
+	capabilityPolicies         = "policies"
+	capabilitySearch           = "search"
+	capabilityDownloadsSummary = "downloads summary"
+)
+
+// Capabilities lists the features the connected registry supports, so that callers can
+// hide functionality an environment does not offer. The public registry supports all
+// of them except publishing; private registries often support a subset.
+type Capabilities struct {
+	// BaseURL is the registry the capabilities were detected for
+	BaseURL string `json:"base_url"`
+
+	// Services are the service URLs advertised by the registry's discovery document,
+	// keyed by service ID such as "modules.v1"; empty when it publishes none
+	Services map[string]string `json:"services,omitempty"`
+
+	// V2Docs reports whether provider docs can be listed through the v2 API
+	V2Docs bool `json:"v2_docs"`
+
+	// Policies reports whether Sentinel and OPA policies are available
+	Policies bool `json:"policies"`
+
+	// Search reports whether modules can be searched
+	Search bool `json:"search"`
+
+	// DownloadsSummary reports whether module download statistics are available
+	DownloadsSummary bool `json:"downloads_summary"`
+
+	// Publishing reports whether the registry advertises a publishing API, as Terraform
+	// Cloud and Enterprise registries do
+	Publishing bool `json:"publishing"`
+
+	// DetectedAt is when the capabilities were detected
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Supports reports whether the registry advertises a discovery service, such as
+// "modules.v1" or "providers.v1"
+func (c *Capabilities) Supports(service string) bool {
+	_, ok := c.Services[service]
+	return ok
+}
+
+// JSON returns the capabilities as indented JSON
+func (c *Capabilities) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// clone returns a copy that callers can modify without affecting the cache
+func (c *Capabilities) clone() *Capabilities {
+	result := *c
+	result.Services = maps.Clone(c.Services)
+	return &result
+}
+
+// publishingServices are discovery service IDs of registries that accept publishing
+var publishingServices = []string{"tfe.v2", "tfe.v2.1", "tfe.v2.2"}
+
+// capabilitiesCache holds the capabilities detected for the client's registry
+type capabilitiesCache struct {
+	mu           sync.Mutex
+	capabilities *Capabilities
+}
+
+// Capabilities reports which features the connected registry supports, based on its
+// service discovery document and on probing one cheap request per feature. A feature
+// is unsupported when its probe is answered with 404 Not Found, 405 Method Not Allowed
+// or 501 Not Implemented. The result is cached until the base URL changes or
+// ClearCapabilities is called; when a probe fails for another reason, the partial
+// result is returned with the failures in a MultiError and is not cached.
+func (c *Client) Capabilities(ctx context.Context) (*Capabilities, error) {
+	baseURL := c.GetBaseURL()
+
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	if cached := c.capabilities.capabilities; cached != nil && cached.BaseURL == baseURL {
+		return cached.clone(), nil
+	}
+
+	caps := &Capabilities{BaseURL: baseURL}
+	var errs MultiError
+
+	services, err := c.discoverServices(ctx, baseURL)
+	if err != nil {
+		errs.Add(fmt.Errorf("failed to read service discovery: %w", err))
+	}
+	caps.Services = services
+	for _, service := range publishingServices {
+		caps.Publishing = caps.Publishing || caps.Supports(service)
+	}
+
+	probes := map[string]func(ctx context.Context) error{
+		capabilityV2Docs: func(ctx context.Context) error {
+			return c.get(ctx, "provider-docs?page%5Bsize%5D=1", "v2", nil)
+		},
+		capabilityPolicies: func(ctx context.Context) error {
+			return c.get(ctx, "policies?page%5Bsize%5D=1", "v2", nil)
+		},
+		capabilitySearch: func(ctx context.Context) error {
+			return c.get(ctx, "modules/search?q=terraform&limit=1", "v1", nil)
+		},
+		capabilityDownloadsSummary: c.probeDownloadsSummary,
+	}
+
+	names := make([]string, 0, len(probes))
+	for name := range probes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := runBatch(ctx, names, len(names), func(ctx context.Context, name string) (bool, error) {
+		err := probes[name](ctx)
+		if err == nil {
+			return true, nil
+		}
+		if isUnsupported(err) {
+			return false, nil
+		}
+		return false, err
+	})
+
+	for _, name := range names {
+		result := results[name]
+		if result.Err != nil {
+			errs.Add(fmt.Errorf("failed to probe %s: %w", name, result.Err))
+			continue
+		}
+		switch name {
+		case capabilityV2Docs:
+			caps.V2Docs = result.Value
+		case capabilityPolicies:
+			caps.Policies = result.Value
+		case capabilitySearch:
+			caps.Search = result.Value
+		case capabilityDownloadsSummary:
+			caps.DownloadsSummary = result.Value
+		}
+	}
+	caps.DetectedAt = time.Now()
+
+	if errs.HasErrors() {
+		return caps, errs.ErrorOrNil()
+	}
+
+	c.capabilities.capabilities = caps
+	return caps.clone(), nil
+}
+
+// ClearCapabilities drops the cached capabilities so that the next Capabilities call
+// detects them again
+func (c *Client) ClearCapabilities() {
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+	c.capabilities.capabilities = nil
+}
+
+// discoverServices fetches the registry's service discovery document and resolves the
+// advertised service URLs against the base URL. A registry without one has no services.
+func (c *Client) discoverServices(ctx context.Context, baseURL string) (map[string]string, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	data, err := c.fetchSmall(ctx, base.ResolveReference(&url.URL{Path: "/.well-known/terraform.json"}).String())
+	if err != nil {
+		if isUnsupported(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, &ResponseError{
+			StatusCode: http.StatusOK,
+			Err:        fmt.Errorf("error decoding service discovery: %w", err),
+		}
+	}
+
+	services := make(map[string]string, len(document))
+	for id, value := range document {
+		// Some services, such as login.v1, are described by objects rather than URLs
+		target, ok := value.(string)
+		if !ok {
+			services[id] = ""
+			continue
+		}
+		ref, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+		services[id] = base.ResolveReference(ref).String()
+	}
+	return services, nil
+}
+
+// probeDownloadsSummary requests the download statistics of the first listed module.
+// A registry without modules cannot show download statistics either.
+func (c *Client) probeDownloadsSummary(ctx context.Context) error {
+	var list struct {
+		Modules []struct {
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+			Provider  string `json:"provider"`
+		} `json:"modules"`
+	}
+	if err := c.get(ctx, "modules?limit=1", "v1", &list); err != nil {
+		return err
+	}
+	if len(list.Modules) == 0 {
+		return &APIError{StatusCode: http.StatusNotFound, Message: "no modules published"}
+	}
+
+	module := list.Modules[0]
+	path := fmt.Sprintf("modules/%s/%s/%s/downloads/summary",
+		url.PathEscape(module.Namespace), url.PathEscape(module.Name), url.PathEscape(module.Provider))
+	return c.get(ctx, path, "v1", nil)
+}
+
+// isUnsupported reports whether err is a response saying an endpoint does not exist
+func isUnsupported(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCheckpointNotFound is returned by checkpoint stores when no checkpoint exists for a key
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// CheckpointStore persists progress of long-running operations so they can resume
+// after interruption. Keys are slash-separated identifiers chosen by the operation.
+type CheckpointStore interface {
+	// Load returns the checkpoint for key, or ErrCheckpointNotFound
+	Load(ctx context.Context, key string) ([]byte, error)
+
+	// Save stores the checkpoint for key, replacing any previous one
+	Save(ctx context.Context, key string, data []byte) error
+
+	// Delete removes the checkpoint for key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryCheckpointStore is an in-memory CheckpointStore, useful for tests and for
+// resuming within a single process
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string][]byte
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string][]byte)}
+}
+
+// Load implements CheckpointStore
+func (m *MemoryCheckpointStore) Load(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.checkpoints[key]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Save implements CheckpointStore
+func (m *MemoryCheckpointStore) Save(ctx context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checkpoints[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Delete implements CheckpointStore
+func (m *MemoryCheckpointStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.checkpoints, key)
+	return nil
+}
@@ -0,0 +1,77 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileCheckpointStore is a CheckpointStore keeping one JSON file per key under a directory
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore creates a checkpoint store rooted at dir, creating it if needed
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if dir == "" {
+		return nil, &ValidationError{
+			Field:   "dir",
+			Message: "checkpoint directory cannot be empty",
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Load implements CheckpointStore
+func (f *FileCheckpointStore) Load(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCheckpointNotFound
+	}
+	return data, err
+}
+
+// Save implements CheckpointStore. The file is replaced atomically so an interrupted
+// save never leaves a truncated checkpoint.
+func (f *FileCheckpointStore) Save(ctx context.Context, key string, data []byte) error {
+	tmp, err := os.CreateTemp(f.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Delete implements CheckpointStore
+func (f *FileCheckpointStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path maps a key to a file name within the store directory
+func (f *FileCheckpointStore) path(key string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "@", "_").Replace(key)
+	return filepath.Join(f.dir, name+".json")
+}
@@ -3,6 +3,7 @@ package registry
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,8 +13,8 @@ import (
 	"sync"
 	"time"
 
-	"github.com/hashicorp/go-cleanhttp"
-	"github.com/hashicorp/go-retryablehttp"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -47,17 +48,58 @@ type Client struct {
 	userAgent  string
 	apiToken   string // For future private registry support
 
-	// Rate limiting
-	rateLimiter *RateLimiter
+	// Rate limiting; rateLimiter budgets rateLimiterHost, the host of the initial base
+	// URL, and hostLimiters the other hosts when per-host limits are enabled
+	rateLimiter     RateLimiter
+	rateLimiterHost string
+	hostLimiters    hostRateLimiters
+	scheduler       *Scheduler
+
+	// negativeCache holds recent not-found lookups; nil when disabled
+	negativeCache *negativeCache
+
+	// logos caches downloaded logo images by URL
+	logos logoCache
+
+	// capabilities caches the features detected for the registry
+	capabilities capabilitiesCache
+
+	// latestVersions keeps the latest versions seen by HasChanged with the validators
+	// of their version listings
+	latestVersions *MemoryCache
+
+	// fingerprints aggregates request fingerprints; nil when disabled
+	fingerprints *fingerprintRecorder
+
+	// cacheStats counts the requests answered by the response cache
+	cacheStats cacheCounters
+
+	// deprecations records the deprecated methods already warned about
+	deprecations deprecations
+
+	// offline is the snapshot requests are served from; nil when online
+	offline *Snapshot
+
+	// maintenance tracks hosts in an announced maintenance window
+	maintenance maintenanceWindows
+
+	// historyMu serializes updates of the version history store
+	historyMu sync.Mutex
 
 	// Service clients
 	Providers ProvidersServiceInterface
 	Modules   ModulesServiceInterface
 	Policies  PoliciesServiceInterface
+	Security  SecurityServiceInterface
+	Analyze   AnalyzeServiceInterface
+	Audit     AuditServiceInterface
 
 	// Configuration
 	config *ClientConfig
 
+	// stateSections are the components persisted by ExportState/ImportState
+	stateSections map[string]stateSection
+
 	// Ensure thread safety
 	mu sync.RWMutex
 }
@@ -75,13 +117,118 @@ type ClientConfig struct {
 	RateLimitRequests int
 	RateLimitPeriod   time.Duration
 
+	// RateLimiter replaces the token bucket built from RateLimitRequests and
+	// RateLimitPeriod when set
+	RateLimiter RateLimiter
+
+	// NewHostRateLimiter creates the rate limiters of hosts other than the initial base
+	// URL host; nil shares one limiter between all hosts
+	NewHostRateLimiter func(host string) RateLimiter
+
+	// Priority scheduling configuration; a zero threshold uses DefaultSchedulerThreshold
+	EnableScheduler    bool
+	SchedulerThreshold int
+
 	// HTTP client configuration
 	HTTPClient *http.Client
 
+	// Middleware wraps the transport of the default HTTP client
+	Middleware []Middleware
+
+	// Proxy and TLS configuration of the default HTTP client. ClientCertFile,
+	// ClientKeyFile and CAFile are PEM files applied to every host unless HostTLSConfigs,
+	// keyed by lowercase hostname or hostname and port, has an entry for it. An empty
+	// ProxyURL uses the proxy environment variables.
+	ProxyURL       string
+	ClientCertFile string
+	ClientKeyFile  string
+	CAFile         string
+	HostTLSConfigs map[string]*tls.Config
+
+	// RetryMiddleware builds the retry layer of the default HTTP client; nil uses RetryMiddleware
+	RetryMiddleware RetryMiddlewareFunc
+
 	// Retry configuration
 	RetryWaitMin time.Duration
 	RetryWaitMax time.Duration
 
+	// OnWait is notified of rate limit and retry delays
+	OnWait WaitHandler
+
+	// Scraper is the optional website scraping backend; nil disables scraping
+	Scraper Scraper
+
+	// IntegrityChecks enables response invariant checks reported as warnings
+	IntegrityChecks bool
+
+	// RequestFingerprints enables recording request fingerprints for FingerprintReport
+	RequestFingerprints bool
+
+	// Negative caching of not-found lookups; a zero TTL disables it
+	NegativeCacheTTL    time.Duration
+	NegativeCacheMaxTTL time.Duration
+
+	// MaxLogoSize limits downloaded logo images in bytes; zero uses DefaultMaxLogoSize
+	MaxLogoSize int64
+
+	// MaxResponseSize limits API response bodies in bytes; zero uses
+	// DefaultMaxResponseSize
+	MaxResponseSize int64
+
+	// StreamingDecode decodes JSON responses as they are read when they need not be kept
+	StreamingDecode bool
+
+	// MaxArchiveSize limits downloaded and extracted module archives in bytes; zero uses
+	// DefaultMaxArchiveSize
+	MaxArchiveSize int64
+
+	// Parallel downloads of large artifacts; zero values use DefaultDownloadChunkSize and
+	// DefaultDownloadConcurrency
+	DownloadChunkSize   int64
+	DownloadConcurrency int
+
+	// DownloadPolicy restricts the modules and providers that may be downloaded; nil
+	// allows every download. OnDownloadDenied is notified of rejected downloads.
+	DownloadPolicy   *DownloadPolicy
+	OnDownloadDenied DownloadAuditHandler
+
+	// BestEffort is the time budget of composite operations, after which they return
+	// what they gathered with a PartialResultError; zero disables it
+	BestEffort time.Duration
+
+	// Response caching; a nil Cache disables it. CacheRevalidate revalidates cached
+	// responses on every request instead of serving them while fresh.
+	Cache           Cache
+	CacheTTL        time.Duration
+	CacheRevalidate bool
+
+	// OfflineSnapshot is the path of a snapshot to serve requests from instead of the
+	// network; empty disables offline mode
+	OfflineSnapshot string
+
+	// HistoryStore keeps the version metadata snapshots of TrendReport; nil disables it
+	HistoryStore CheckpointStore
+
+	// Security advisory configuration
+	GitHubAPIURL string
+	GitHubToken  string
+
+	// Backend is the registry protocol spoken by BaseURL; empty means BackendTerraform.
+	// OpenTofuDocsURL is the OpenTofu docs API (DefaultOpenTofuDocsURL when empty).
+	Backend         Backend
+	OpenTofuDocsURL string
+
+	// Private module registry configuration: module requests for the Organization
+	// namespace go to TFEAddress (DefaultTFEAddress when empty) with TFEToken, or the
+	// API token when empty
+	Organization string
+	TFEAddress   string
+	TFEToken     string
+
+	// Audit trail configuration
+	AuditAPIURL string
+	AuditToken  string
+
 	// Circuit breaker configuration
 	CircuitBreakerThreshold   int
 	CircuitBreakerTimeout     time.Duration
@@ -181,8 +328,15 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 		config:    config,
 	}
 
-	// Create HTTP client if not provided
-	if config.HTTPClient == nil {
+	// Create HTTP client if not provided; an offline snapshot replaces any HTTP client
+	if config.OfflineSnapshot != "" {
+		snapshot, err := ReadSnapshot(config.OfflineSnapshot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load offline snapshot: %w", err)
+		}
+		client.offline = snapshot
+		client.httpClient = newOfflineHTTPClient(snapshot, client.GetBaseURL)
+	} else if config.HTTPClient == nil {
 		httpClient, err := newDefaultHTTPClient(config)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create HTTP client: %w", err)
@@ -193,12 +347,47 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	}
 
 	// Initialize rate limiter
-	client.rateLimiter = NewRateLimiter(config.RateLimitRequests, config.RateLimitPeriod)
+	client.rateLimiter = config.RateLimiter
+	if client.rateLimiter == nil {
+		client.rateLimiter = NewTokenBucket(config.RateLimitRequests, config.RateLimitPeriod)
+	}
+	if u, err := url.Parse(config.BaseURL); err == nil {
+		client.rateLimiterHost = u.Host
+	}
+	if config.EnableScheduler {
+		threshold := config.SchedulerThreshold
+		if threshold == 0 {
+			threshold = DefaultSchedulerThreshold
+		}
+		client.scheduler = NewScheduler(client.rateLimiter, threshold)
+	}
+	client.negativeCache = newNegativeCache(config.NegativeCacheTTL, config.NegativeCacheMaxTTL)
+	client.latestVersions = NewMemoryCache(0)
+	if config.RequestFingerprints {
+		client.fingerprints = newFingerprintRecorder()
+	}
 
 	// Initialize service clients
-	client.Providers = &ProvidersService{client: client}
+	providers := &ProvidersService{client: client}
+	policies := &PoliciesService{client: client}
+
+	client.Providers = providers
 	client.Modules = &ModulesService{client: client}
-	client.Policies = &PoliciesService{client: client}
+	client.Policies = policies
+	client.Security = &SecurityService{client: client}
+	client.Analyze = &AnalyzeService{client: client}
+	client.Audit = &AuditService{client: client}
+
+	client.stateSections = map[string]stateSection{
+		stateSectionProviderVersions: &providers.versions,
+		stateSectionPolicyContent:    policies,
+	}
+	if section, ok := client.rateLimiter.(stateSection); ok {
+		client.stateSections[stateSectionRateLimiter] = section
+	}
+	if section, ok := config.Cache.(stateSection); ok {
+		client.stateSections[stateSectionResponseCache] = section
+	}
 
 	return client, nil
 }
@@ -229,64 +418,71 @@ func validateConfig(config *ClientConfig) error {
 		return errors.New("rate limit period must be positive")
 	}
 
-	return nil
-}
+	if (config.ClientCertFile == "") != (config.ClientKeyFile == "") {
+		return errors.New("client certificate and key must be set together")
+	}
 
-// newDefaultHTTPClient creates a default HTTP client with retry logic
-func newDefaultHTTPClient(config *ClientConfig) (*http.Client, error) {
-	retryClient := retryablehttp.NewClient()
-	retryClient.Logger = config.Logger
-
-	transport := cleanhttp.DefaultPooledTransport()
-	transport.Proxy = http.ProxyFromEnvironment
-	transport.MaxIdleConns = 100
-	transport.MaxIdleConnsPerHost = 10
-
-	retryClient.HTTPClient = &http.Client{
-		Timeout:   config.Timeout,
-		Transport: transport,
-	}
-	retryClient.RetryMax = config.MaxRetries
-	retryClient.RetryWaitMin = config.RetryWaitMin
-	retryClient.RetryWaitMax = config.RetryWaitMax
-
-	// Custom backoff for rate limiting
-	retryClient.Backoff = func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
-		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
-			if resetAfter := resp.Header.Get("x-ratelimit-reset"); resetAfter != "" {
-				var resetTime int64
-				if _, err := fmt.Sscanf(resetAfter, "%d", &resetTime); err == nil {
-					waitTime := time.Until(time.Unix(resetTime, 0))
-					config.Logger.Debugf("Rate limited, waiting %v until reset", waitTime)
-					return waitTime
-				}
-			}
+	if config.ProxyURL != "" {
+		if _, err := url.Parse(config.ProxyURL); err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
 		}
-		return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
 	}
 
-	// Custom retry policy
-	retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
-		if err != nil {
-			// Always retry on network errors
-			return true, nil
+	if config.Organization != "" && !validate.Namespace(config.Organization) {
+		return fmt.Errorf("invalid organization name %q", config.Organization)
+	}
+
+	if err := config.Backend.Validate(); err != nil {
+		return err
+	}
+
+	if config.DownloadPolicy != nil {
+		if err := config.DownloadPolicy.Validate(); err != nil {
+			return err
 		}
+	}
 
-		if resp != nil {
-			if resp.StatusCode == http.StatusTooManyRequests {
-				return true, nil
-			}
+	if config.OpenTofuDocsURL != "" {
+		if u, err := url.Parse(config.OpenTofuDocsURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid OpenTofu docs URL %q", config.OpenTofuDocsURL)
+		}
+	}
 
-			// Retry on 5xx errors
-			if resp.StatusCode >= 500 {
-				return true, nil
-			}
+	if config.TFEAddress != "" {
+		if u, err := url.Parse(config.TFEAddress); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid TFE address %q", config.TFEAddress)
 		}
+	}
+
+	if config.NegativeCacheTTL < 0 || config.NegativeCacheMaxTTL < 0 {
+		return errors.New("negative cache TTLs cannot be negative")
+	}
+
+	if config.CacheTTL < 0 {
+		return errors.New("cache TTL cannot be negative")
+	}
+
+	if config.MaxLogoSize < 0 {
+		return errors.New("max logo size cannot be negative")
+	}
+
+	if config.MaxResponseSize < 0 {
+		return errors.New("max response size cannot be negative")
+	}
+
+	if config.MaxArchiveSize < 0 {
+		return errors.New("max archive size cannot be negative")
+	}
 
-		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	if config.DownloadChunkSize < 0 || config.DownloadConcurrency < 0 {
+		return errors.New("download chunk size and concurrency cannot be negative")
 	}
 
-	return retryClient.StandardClient(), nil
+	if config.BestEffort < 0 {
+		return errors.New("best effort duration cannot be negative")
+	}
+
+	return nil
 }
 
 // get performs a GET request to the specified path
@@ -294,11 +490,52 @@ func (c *Client) get(ctx context.Context, path string, version string, result in
 	return c.request(ctx, "GET", path, version, nil, result)
 }
 
+// getURL performs a GET request to an absolute URL outside the base URL, such as a
+// private registry or a docs API, authenticated with token when it is not empty
+func (c *Client) getURL(ctx context.Context, endpoint, token string, result interface{}) error {
+	req, err := c.newURLRequest(ctx, endpoint, token)
+	if err != nil {
+		return err
+	}
+
+	if c.offline == nil && !c.hasFreshResponse(req) {
+		if err := c.waitForToken(req); err != nil {
+			return fmt.Errorf("rate limit error: %w", timeoutError(err))
+		}
+	}
+
+	return c.do(req, result)
+}
+
+// newURLRequest creates a GET request for an absolute URL, authenticated with token
+// when it is not empty
+func (c *Client) newURLRequest(ctx context.Context, endpoint, token string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    endpoint,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	return req, nil
+}
+
 // request performs an HTTP request
 func (c *Client) request(ctx context.Context, method, path, version string, body io.Reader, result interface{}) error {
-	// Check rate limit
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit error: %w", err)
+	start := time.Now()
+
+	// Serve known-missing lookups from the negative cache
+	cacheKey := version + "/" + path
+	if apiErr, ok := c.cachedNotFound(ctx, method, cacheKey, path); ok {
+		c.logger.WithField("path", cacheKey).Debug("Serving cached not-found response")
+		c.recordFingerprint(method, version, path, start, true, apiErr)
+		return apiErr
 	}
 
 	req, err := c.newRequest(ctx, method, path, version, body)
@@ -306,7 +543,68 @@ func (c *Client) request(ctx context.Context, method, path, version string, body
 		return err
 	}
 
-	return c.do(req, result)
+	// Check rate limit; fresh cached and offline responses do not use any budget
+	fresh := c.offline != nil || c.hasFreshResponse(req)
+	if !fresh {
+		if err := c.waitForToken(req); err != nil {
+			return fmt.Errorf("rate limit error: %w", timeoutError(err))
+		}
+	}
+
+	err = c.do(req, result)
+	c.updateNegativeCache(method, cacheKey, path, err)
+	c.recordFingerprint(method, version, path, start, fresh, err)
+
+	return err
+}
+
+// waitForToken waits for rate limit budget, honoring request priority when scheduling is enabled
+func (c *Client) waitForToken(req *http.Request) error {
+	ctx := req.Context()
+	limiter, shared := c.limiterFor(req.URL.Host)
+
+	if wait := limiter.TimeUntilNextToken(); wait > 0 {
+		c.config.notifyWait(WaitEvent{
+			Reason:     WaitRateLimit,
+			MaxRetries: c.config.MaxRetries,
+			Wait:       wait,
+			Method:     req.Method,
+			URL:        req.URL.String(),
+		})
+	}
+
+	if c.scheduler != nil && shared {
+		return c.scheduler.Wait(ctx)
+	}
+	return limiter.Wait(ctx)
+}
+
+// limiterFor returns the rate limiter of a host and whether it is the limiter of the
+// initial base URL host, which the priority scheduler hands out
+func (c *Client) limiterFor(host string) (RateLimiter, bool) {
+	newLimiter := c.config.NewHostRateLimiter
+	if newLimiter == nil || host == "" || host == c.rateLimiterHost {
+		return c.rateLimiter, true
+	}
+	if limiter := c.hostLimiters.get(host, newLimiter); limiter != nil {
+		return limiter, false
+	}
+	return c.rateLimiter, true
+}
+
+// observeRateLimit passes the headers of a response to the rate limiter of its host.
+// Responses replayed by the HTTP cache carry outdated budgets and are skipped.
+func (c *Client) observeRateLimit(resp *http.Response) {
+	if resp.Header.Get(HTTPCacheHeader) != "" {
+		return
+	}
+
+	host := ""
+	if resp.Request != nil {
+		host = resp.Request.URL.Host
+	}
+	limiter, _ := c.limiterFor(host)
+	limiter.Observe(resp.Header)
 }
 
 // newRequest creates a new HTTP request
@@ -348,8 +646,24 @@ func (c *Client) newRequest(ctx context.Context, method, path, version string, b
 	return req, nil
 }
 
-// do performs the HTTP request and decodes the response
+// do performs the HTTP request and decodes the response, using the response cache
+// for GET requests when one is configured
 func (c *Client) do(req *http.Request, result interface{}) error {
+	cacheKey, cached := c.cachedResponse(req)
+	if cached != nil {
+		if c.hasFreshResponse(req) {
+			c.logger.WithField("url", req.URL.String()).Debug("Serving cached response")
+			c.cacheStats.hits.Add(1)
+			return c.decodeResponse(req, http.StatusOK, cached.Body, result)
+		}
+		setConditionalHeaders(req, cached)
+	}
+
+	// Hosts in an announced maintenance window fail fast until it ends
+	if err := c.maintenance.active(req.URL.Host); err != nil {
+		return err
+	}
+
 	c.logger.WithFields(logrus.Fields{
 		"method": req.Method,
 		"url":    req.URL.String(),
@@ -360,17 +674,36 @@ func (c *Client) do(req *http.Request, result interface{}) error {
 		return &RequestError{
 			Method: req.Method,
 			URL:    req.URL.String(),
-			Err:    fmt.Errorf("error performing request: %w", err),
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
 		}
 	}
 	defer resp.Body.Close()
+	c.observeRateLimit(resp)
+	c.cacheStats.record(cacheKey, cached, resp.StatusCode)
+
+	reader, err := c.limitBody(resp)
+	if err != nil {
+		return err
+	}
+
+	// Decode successful responses that need not be kept as they are read
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && c.canStream(req, cacheKey, result) {
+		c.logger.WithFields(logrus.Fields{
+			"status": resp.StatusCode,
+			"length": resp.ContentLength,
+		}).Debug("Streaming response")
+		return streamResponse(resp, reader, result)
+	}
 
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(reader)
+	if IsResponseTooLarge(err) {
+		return &ResponseError{StatusCode: resp.StatusCode, Err: err}
+	}
 	if err != nil {
 		return &ResponseError{
 			StatusCode: resp.StatusCode,
-			Err:        fmt.Errorf("error reading response body: %w", err),
+			Err:        fmt.Errorf("error reading response body: %w", timeoutError(err)),
 		}
 	}
 
@@ -379,40 +712,75 @@ func (c *Client) do(req *http.Request, result interface{}) error {
 		"length": len(body),
 	}).Debug("Received response")
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.refreshResponse(cacheKey, cached, resp.Header)
+		return c.decodeResponse(req, http.StatusOK, cached.Body, result)
+	}
+
 	// Check for errors
+	if maintenance := MaintenanceFromResponse(resp, body); maintenance != nil {
+		c.maintenance.record(maintenance)
+		return maintenance
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		apiErr := &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    string(body),
-			Headers:    resp.Header,
-		}
+		return newAPIError(resp, body)
+	}
 
-		// Try to parse error response
-		var errResp struct {
+	c.storeResponse(cacheKey, resp.Header, body)
+
+	return c.decodeResponse(req, resp.StatusCode, body, result)
+}
+
+// newAPIError builds an APIError from an error response, using the message of a JSON
+// error body when there is one
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(body),
+		Headers:    resp.Header,
+	}
+
+	// Try to parse error response
+	var errResp struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Code    string `json:"code"`
 			Message string `json:"message"`
-			Errors  []struct {
-				Code    string `json:"code"`
-				Message string `json:"message"`
-			} `json:"errors"`
-		}
+		} `json:"errors"`
+	}
 
-		if err := json.Unmarshal(body, &errResp); err == nil {
-			if errResp.Message != "" {
-				apiErr.Message = errResp.Message
-			}
-			if len(errResp.Errors) > 0 {
-				apiErr.Message = errResp.Errors[0].Message
-			}
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		if errResp.Message != "" {
+			apiErr.Message = errResp.Message
+		}
+		if len(errResp.Errors) > 0 {
+			apiErr.Message = errResp.Errors[0].Message
 		}
+	}
 
-		return apiErr
+	return apiErr
+}
+
+// decodeResponse captures a successful response body and decodes it into result
+func (c *Client) decodeResponse(req *http.Request, statusCode int, body []byte, result interface{}) error {
+	captureRaw(req.Context(), RawResponse{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: statusCode,
+		Body:       body,
+	})
+
+	// Raw bodies, such as Markdown docs, are returned as they are
+	if raw, ok := result.(*rawBody); ok {
+		*raw = append((*raw)[:0], body...)
+		return nil
 	}
 
 	// Decode response if result is provided
 	if result != nil && len(body) > 0 {
 		if err := json.Unmarshal(body, result); err != nil {
 			return &ResponseError{
-				StatusCode: resp.StatusCode,
+				StatusCode: statusCode,
 				Err:        fmt.Errorf("error decoding response: %w", err),
 			}
 		}
@@ -441,9 +809,23 @@ func (c *Client) GetBaseURL() string {
 	return c.baseURL
 }
 
-// GetRateLimiter returns the client's rate limiter
-func (c *Client) GetRateLimiter() *RateLimiter {
+// GetScheduler returns the client's priority scheduler, or nil if scheduling is disabled
+func (c *Client) GetScheduler() *Scheduler {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.rateLimiter
+	return c.scheduler
+}
+
+// GetRateLimiter returns the rate limiter of the current base URL host
+func (c *Client) GetRateLimiter() RateLimiter {
+	c.mu.RLock()
+	baseURL := c.baseURL
+	c.mu.RUnlock()
+
+	host := ""
+	if u, err := url.Parse(baseURL); err == nil {
+		host = u.Host
+	}
+	limiter, _ := c.limiterFor(host)
+	return limiter
 }
@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/semver"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+)
+
+// constraintVersionPattern matches a possibly partial version in a constraint, such as "5" or "5.1"
+var constraintVersionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(-[a-zA-Z0-9\-\.]+)?(\+[a-zA-Z0-9\-\.]+)?$`)
+
+// VersionConstraint is a parsed Terraform version constraint such as ">= 5.0, < 6.0" or
+// "~> 5.1". An empty constraint allows every release.
+type VersionConstraint struct {
+	raw   string
+	terms []constraintTerm
+}
+
+// constraintTerm is one comma-separated part of a constraint
+type constraintTerm struct {
+	op      string
+	version string
+
+	// segments is the number of version segments written, for "~>"
+	segments int
+}
+
+// ParseVersionConstraint parses a Terraform version constraint. The operators =, !=, >,
+// >=, <, <= and ~> are supported; a version without an operator means =.
+func ParseVersionConstraint(constraint string) (VersionConstraint, error) {
+	c := VersionConstraint{raw: strings.TrimSpace(constraint)}
+	if c.raw == "" {
+		return c, nil
+	}
+
+	for _, part := range strings.Split(c.raw, ",") {
+		part = strings.TrimSpace(part)
+
+		op := "="
+		for _, candidate := range []string{"~>", ">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				part = strings.TrimSpace(part[len(candidate):])
+				break
+			}
+		}
+
+		match := constraintVersionPattern.FindStringSubmatch(part)
+		if match == nil {
+			return VersionConstraint{}, &ValidationError{
+				Field:   "constraint",
+				Value:   constraint,
+				Message: fmt.Sprintf("invalid version %q in constraint", part),
+			}
+		}
+
+		segments := 1
+		version := [3]string{match[1], "0", "0"}
+		for i, segment := range match[2:4] {
+			if segment != "" {
+				version[i+1] = segment
+				segments = i + 2
+			}
+		}
+
+		c.terms = append(c.terms, constraintTerm{
+			op:       op,
+			version:  strings.Join(version[:], ".") + match[4],
+			segments: segments,
+		})
+	}
+
+	return c, nil
+}
+
+// String returns the constraint as written
+func (c VersionConstraint) String() string {
+	return c.raw
+}
+
+// Check reports whether a version satisfies every part of the constraint. As in
+// Terraform, pre-releases only match an exact = part naming them.
+func (c VersionConstraint) Check(version string) bool {
+	if !validate.Version(version) {
+		return false
+	}
+
+	if semver.IsPrerelease(version) {
+		exact := false
+		for _, term := range c.terms {
+			if term.op == "=" && CompareVersions(version, term.version) == 0 {
+				exact = true
+			}
+		}
+		if !exact {
+			return false
+		}
+	}
+
+	for _, term := range c.terms {
+		if !term.check(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// check reports whether a version satisfies the term
+func (t constraintTerm) check(version string) bool {
+	cmp := CompareVersions(version, t.version)
+	switch t.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "~>":
+		return cmp >= 0 && CompareVersions(version, t.pessimisticUpper()) < 0
+	}
+	return false
+}
+
+// pessimisticUpper returns the exclusive upper bound of a "~>" term: "~> 1.2" allows
+// up to 2.0.0 and "~> 1.2.3" up to 1.3.0
+func (t constraintTerm) pessimisticUpper() string {
+	v, err := semver.Parse(t.version)
+	if err != nil {
+		return t.version
+	}
+	parts := [3]uint64{v.Major, v.Minor, v.Patch}
+
+	bump := t.segments - 2
+	if bump < 0 {
+		bump = 0
+	}
+	parts[bump]++
+	for i := bump + 1; i < 3; i++ {
+		parts[i] = 0
+	}
+
+	return semver.Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}.String()
+}
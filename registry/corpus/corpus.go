@@ -0,0 +1,500 @@
+// Package corpus builds datasets of Terraform usage examples from registry modules and
+// provider docs. HCL code blocks are extracted from module READMEs and provider
+// resource docs, normalized so that formatting differences do not matter, and
+// deduplicated by content while keeping the provenance of every occurrence. Examples that
+// do not parse, or that a validator such as terraform validate rejects, can be left out
+// so that the dataset only holds usable code. The dataset can be written as JSON Lines.
+package corpus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Kind is the kind of artifact an example was found in
+type Kind string
+
+// Artifact kinds
+const (
+	KindModule   Kind = "module"
+	KindProvider Kind = "provider"
+)
+
+// Provenance records where an example was found
+type Provenance struct {
+	Kind Kind `json:"kind"`
+
+	// Address is namespace/name/provider for modules and namespace/name for providers
+	Address string `json:"address"`
+
+	// Version is the resolved artifact version
+	Version string `json:"version"`
+
+	// File is the README path within the module, e.g. "examples/basic/README.md", or the
+	// doc path within the provider repository
+	File string `json:"file"`
+}
+
+// Example is a normalized HCL example with every place it was found
+type Example struct {
+	// ID is the hex SHA-256 of Code
+	ID string `json:"id"`
+
+	Code string `json:"code"`
+
+	// Sources lists the provenance of each occurrence, in the order they were added
+	Sources []Provenance `json:"sources"`
+}
+
+// Options configures Build
+type Options struct {
+	// Categories are the provider doc categories to extract examples from; resources and
+	// data-sources when empty
+	Categories []string
+
+	// Concurrency bounds concurrent provider doc downloads;
+	// registry.DefaultBatchConcurrency when zero
+	Concurrency int
+
+	// MinLines drops examples with fewer lines after normalization
+	MinLines int
+
+	// ValidOnly drops examples that do not parse as HCL, so that every example in the
+	// corpus is parseable; dropped examples are listed by Rejected
+	ValidOnly bool
+
+	// Validator further checks the parseable examples once collected, such as with
+	// registry.TerraformValidator; examples it finds errors in are dropped as well
+	Validator registry.HCLValidator
+}
+
+// Validate validates the options
+func (o *Options) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Concurrency < 0 {
+		return &registry.ValidationError{
+			Field:   "concurrency",
+			Value:   o.Concurrency,
+			Message: "concurrency cannot be negative",
+		}
+	}
+	if o.MinLines < 0 {
+		return &registry.ValidationError{
+			Field:   "min_lines",
+			Value:   o.MinLines,
+			Message: "min lines cannot be negative",
+		}
+	}
+	return nil
+}
+
+// Rejection is an example left out of a corpus because it is invalid
+type Rejection struct {
+	Example Example `json:"example"`
+
+	// Errors are the syntax or validation errors, positioned in Example.Code
+	Errors []registry.HCLDiagnostic `json:"errors"`
+}
+
+// Corpus is a deduplicated set of examples. It is safe for concurrent use.
+type Corpus struct {
+	mu       sync.Mutex
+	examples []*Example
+	byID     map[string]*Example
+
+	rejected     []*Rejection
+	rejectedByID map[string]*Rejection
+
+	// minLines drops short examples in Add
+	minLines int
+
+	// validOnly rejects examples that do not parse in Add
+	validOnly bool
+}
+
+// New creates an empty corpus
+func New() *Corpus {
+	return &Corpus{byID: make(map[string]*Example), rejectedByID: make(map[string]*Rejection)}
+}
+
+// NewValidOnly creates an empty corpus that rejects examples that do not parse as HCL
+func NewValidOnly() *Corpus {
+	corpus := New()
+	corpus.validOnly = true
+	return corpus
+}
+
+// Add normalizes code and adds it with its provenance. It reports whether the example
+// is new; a duplicate only gains the provenance, once per distinct source. Blank
+// examples and examples shorter than the corpus minimum are ignored.
+func (c *Corpus) Add(code string, source Provenance) bool {
+	normalized := Normalize(code)
+	if normalized == "" || strings.Count(normalized, "\n")+1 < c.minLines {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+	id := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := c.byID[id]; ok {
+		existing.Sources = addSource(existing.Sources, source)
+		return false
+	}
+	if rejection, ok := c.rejectedByID[id]; ok {
+		rejection.Example.Sources = addSource(rejection.Example.Sources, source)
+		return false
+	}
+
+	example := &Example{ID: id, Code: normalized, Sources: []Provenance{source}}
+	if c.validOnly {
+		if errors := registry.CheckHCL(normalized); len(errors) > 0 {
+			c.reject(example, errors)
+			return false
+		}
+	}
+
+	c.examples = append(c.examples, example)
+	c.byID[id] = example
+	return true
+}
+
+// addSource adds a provenance once per distinct source
+func addSource(sources []Provenance, source Provenance) []Provenance {
+	for _, known := range sources {
+		if known == source {
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
+// reject records an invalid example; the caller holds c.mu
+func (c *Corpus) reject(example *Example, errors []registry.HCLDiagnostic) {
+	rejection := &Rejection{Example: *example, Errors: errors}
+	c.rejected = append(c.rejected, rejection)
+	c.rejectedByID[example.ID] = rejection
+}
+
+// Rejected returns copies of the examples left out because they are invalid, in the
+// order they were rejected
+func (c *Corpus) Rejected() []Rejection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rejected := make([]Rejection, len(c.rejected))
+	for i, rejection := range c.rejected {
+		rejected[i] = *rejection
+		rejected[i].Example.Sources = append([]Provenance(nil), rejection.Example.Sources...)
+		rejected[i].Errors = append([]registry.HCLDiagnostic(nil), rejection.Errors...)
+	}
+	return rejected
+}
+
+// Check runs a validator on every example and moves those it finds errors in to
+// Rejected. Checking stops at the first example the validator fails to run on.
+func (c *Corpus) Check(ctx context.Context, validator registry.HCLValidator) error {
+	for _, example := range c.Examples() {
+		errors, err := validator(ctx, example.Code)
+		if err != nil {
+			return fmt.Errorf("failed to validate example %s: %w", example.ID, err)
+		}
+		if len(errors) == 0 {
+			continue
+		}
+
+		c.mu.Lock()
+		if current, ok := c.byID[example.ID]; ok {
+			delete(c.byID, example.ID)
+			c.examples = slices.DeleteFunc(c.examples, func(e *Example) bool { return e == current })
+			c.reject(current, errors)
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// Len returns the number of distinct examples
+func (c *Corpus) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.examples)
+}
+
+// Examples returns copies of the examples in the order they were first added
+func (c *Corpus) Examples() []Example {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	examples := make([]Example, len(c.examples))
+	for i, example := range c.examples {
+		examples[i] = *example
+		examples[i].Sources = append([]Provenance(nil), example.Sources...)
+	}
+	return examples
+}
+
+// WriteJSONL writes one JSON object per example, in the order they were first added
+func (c *Corpus) WriteJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, example := range c.Examples() {
+		if err := encoder.Encode(example); err != nil {
+			return fmt.Errorf("failed to write example %s: %w", example.ID, err)
+		}
+	}
+	return nil
+}
+
+// AddModuleDetails adds the README examples of already-fetched module details: the root
+// README, then submodules and examples. It returns the number of new examples.
+func (c *Corpus) AddModuleDetails(ref registry.ModuleRef, details *registry.ModuleDetails) int {
+	if details == nil {
+		return 0
+	}
+
+	address := registry.ModuleRef{Namespace: ref.Namespace, Name: ref.Name, Provider: ref.Provider}.String()
+	version := details.Version
+	if version == "" {
+		version = ref.Version
+	}
+
+	parts := append([]registry.ModulePart{details.Root}, details.Submodules...)
+	parts = append(parts, details.Examples...)
+
+	added := 0
+	for i, part := range parts {
+		file := "README.md"
+		if i > 0 {
+			if part.Path == "" {
+				continue
+			}
+			file = strings.Trim(part.Path, "/") + "/README.md"
+		}
+
+		source := Provenance{Kind: KindModule, Address: address, Version: version, File: file}
+		for _, code := range registry.ExtractTerraformExamples(part.Readme) {
+			if c.Add(code, source) {
+				added++
+			}
+		}
+	}
+	return added
+}
+
+// AddProviderDoc adds the examples of an already-fetched provider doc and returns the
+// number of new examples
+func (c *Corpus) AddProviderDoc(ref registry.ProviderRef, doc registry.ProviderDocData) int {
+	file := doc.Attributes.Path
+	if file == "" {
+		file = doc.Attributes.Category + "/" + doc.Attributes.Slug
+	}
+
+	source := Provenance{
+		Kind:    KindProvider,
+		Address: registry.ProviderRef{Namespace: ref.Namespace, Name: ref.Name}.String(),
+		Version: ref.Version,
+		File:    file,
+	}
+
+	added := 0
+	for _, code := range registry.ExtractTerraformExamples(doc.Attributes.Content) {
+		if c.Add(code, source) {
+			added++
+		}
+	}
+	return added
+}
+
+// Build fetches the given modules and provider docs and collects their examples into a
+// new corpus. Modules contribute README examples of the root module, submodules and
+// examples; providers contribute the code blocks of their docs, which takes one request
+// per doc. An empty or "latest" version uses the latest release. Sources that fail are
+// reported in the returned MultiError alongside the corpus built from the others.
+func Build(ctx context.Context, client *registry.Client, modules []registry.ModuleRef, providers []registry.ProviderRef, opts *Options) (*Corpus, error) {
+	if client == nil {
+		return nil, &registry.ValidationError{
+			Field:   "client",
+			Message: "client cannot be nil",
+		}
+	}
+	if len(modules) == 0 && len(providers) == 0 {
+		return nil, &registry.ValidationError{
+			Field:   "sources",
+			Message: "at least one module or provider is required",
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	corpus := New()
+	corpus.minLines = opts.MinLines
+	corpus.validOnly = opts.ValidOnly
+
+	var errs registry.MultiError
+	for _, ref := range modules {
+		details, err := getModule(ctx, client, ref)
+		if err != nil {
+			errs.Add(fmt.Errorf("module %s: %w", ref, err))
+			continue
+		}
+		corpus.AddModuleDetails(ref, details)
+	}
+
+	for _, ref := range providers {
+		if err := corpus.addProvider(ctx, client, ref, opts, &errs); err != nil {
+			errs.Add(fmt.Errorf("provider %s: %w", ref, err))
+		}
+	}
+
+	if opts.Validator != nil {
+		if err := corpus.Check(ctx, opts.Validator); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	return corpus, errs.ErrorOrNil()
+}
+
+// getModule fetches a module version, or the latest one
+func getModule(ctx context.Context, client *registry.Client, ref registry.ModuleRef) (*registry.ModuleDetails, error) {
+	if ref.Version == "" || ref.Version == "latest" {
+		return client.Modules.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	}
+	return client.Modules.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+}
+
+// addProvider lists a provider version's docs and adds their examples. Listing errors
+// are returned; failed doc downloads are added to errs.
+func (c *Corpus) addProvider(ctx context.Context, client *registry.Client, ref registry.ProviderRef, opts *Options, errs *registry.MultiError) error {
+	if ref.Version == "" || ref.Version == "latest" {
+		latest, err := client.Providers.GetLatest(ctx, ref.Namespace, ref.Name)
+		if err != nil {
+			return err
+		}
+		ref.Version = latest.Version
+	}
+
+	versionID, err := client.Providers.GetVersionID(ctx, ref.Namespace, ref.Name, ref.Version)
+	if err != nil {
+		return err
+	}
+
+	categories := opts.Categories
+	if len(categories) == 0 {
+		categories = []string{"resources", "data-sources"}
+	}
+
+	var ids []string
+	for _, category := range categories {
+		docs, err := client.Providers.ListDocsV2(ctx, &registry.ProviderDocListOptions{
+			ProviderVersionID: versionID,
+			Category:          category,
+		})
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = registry.DefaultBatchConcurrency
+	}
+
+	// Docs are fetched concurrently but added in listing order, so the corpus order
+	// does not depend on timing
+	docs := make([]*registry.ProviderDocDetails, len(ids))
+	failures := make([]error, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				failures[i] = ctx.Err()
+				return
+			}
+			docs[i], failures[i] = client.Providers.GetDoc(ctx, id)
+		}()
+	}
+	wg.Wait()
+
+	for i, doc := range docs {
+		if failures[i] != nil {
+			errs.Add(fmt.Errorf("provider %s doc %s: %w", ref, ids[i], failures[i]))
+			continue
+		}
+		c.AddProviderDoc(ref, doc.Data)
+	}
+	return nil
+}
+
+// Normalize canonicalizes the formatting of an HCL example: line endings become "\n",
+// tabs become two spaces, trailing whitespace and the indentation shared by all lines
+// are removed, runs of blank lines collapse to one and leading and trailing blank lines
+// are dropped. Examples that differ only in these respects normalize to the same code.
+func Normalize(code string) string {
+	code = strings.ReplaceAll(code, "\r\n", "\n")
+	code = strings.ReplaceAll(code, "\r", "\n")
+	code = strings.ReplaceAll(code, "\t", "  ")
+
+	lines := strings.Split(code, "\n")
+	indent := -1
+	for i, line := range lines {
+		line = strings.TrimRight(line, " ")
+		lines[i] = line
+		// Extracted blocks have their leading whitespace trimmed, so an unindented first
+		// line says nothing about the indentation of the rest
+		if line == "" || (i == 0 && !strings.HasPrefix(line, " ") && len(lines) > 1) {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " "))
+		if indent < 0 || width < indent {
+			indent = width
+		}
+	}
+
+	var out []string
+	for _, line := range lines {
+		if line == "" {
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+		trim := indent
+		if width := len(line) - len(strings.TrimLeft(line, " ")); width < trim {
+			trim = width
+		}
+		out = append(out, line[trim:])
+	}
+	if len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+
+	return strings.Join(out, "\n")
+}
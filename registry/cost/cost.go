@@ -0,0 +1,208 @@
+// Package cost produces rough monthly cost reports for registry modules. It walks the
+// resources a module declares and asks a pluggable Estimator to price each one; the
+// package ships the traversal and aggregation, while pricing data comes from the
+// estimator. Estimates are indicative only: resource counts and sizes usually depend on
+// inputs that static module metadata cannot capture.
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// DefaultCurrency is the currency assumed when an estimate does not specify one
+const DefaultCurrency = "USD"
+
+// ErrNoPrice is returned by estimators that have no pricing data for a resource type
+var ErrNoPrice = errors.New("no pricing data for resource")
+
+// ResourceRequest describes a resource to price
+type ResourceRequest struct {
+	// Module is the module being estimated
+	Module registry.ModuleRef
+
+	// Path is the module part containing the resource; empty for the root module
+	Path string
+
+	// Resource is the resource as declared by the module
+	Resource registry.ModuleResource
+
+	// Inputs are the module input values supplied by the caller
+	Inputs map[string]any
+}
+
+// ResourceEstimate is the estimated cost of a single resource
+type ResourceEstimate struct {
+	// MonthlyCost is the estimated monthly cost
+	MonthlyCost float64
+
+	// Currency is the ISO currency code; DefaultCurrency when empty
+	Currency string
+
+	// Note explains assumptions made by the estimator
+	Note string
+}
+
+// Estimator prices individual resources. Implementations return ErrNoPrice for
+// resource types they do not know.
+type Estimator interface {
+	EstimateResource(ctx context.Context, req ResourceRequest) (*ResourceEstimate, error)
+}
+
+// EstimatorFunc adapts a function to the Estimator interface
+type EstimatorFunc func(ctx context.Context, req ResourceRequest) (*ResourceEstimate, error)
+
+// EstimateResource implements Estimator
+func (f EstimatorFunc) EstimateResource(ctx context.Context, req ResourceRequest) (*ResourceEstimate, error) {
+	return f(ctx, req)
+}
+
+// StaticEstimator prices resources by type from a fixed monthly price table
+type StaticEstimator struct {
+	// Prices maps resource type, e.g. "aws_nat_gateway", to monthly cost
+	Prices map[string]float64
+
+	// Currency of the prices; DefaultCurrency when empty
+	Currency string
+}
+
+// EstimateResource implements Estimator
+func (e *StaticEstimator) EstimateResource(ctx context.Context, req ResourceRequest) (*ResourceEstimate, error) {
+	price, ok := e.Prices[req.Resource.Type]
+	if !ok {
+		return nil, ErrNoPrice
+	}
+	return &ResourceEstimate{MonthlyCost: price, Currency: e.Currency}, nil
+}
+
+// LineItem is a priced resource in a report
+type LineItem struct {
+	Path     string
+	Resource registry.ModuleResource
+	ResourceEstimate
+}
+
+// UnpricedResource is a resource the estimator could not price
+type UnpricedResource struct {
+	Path     string
+	Resource registry.ModuleResource
+
+	// Err is the estimator error; ErrNoPrice when pricing data is missing
+	Err error
+}
+
+// Report is the cost report of a module
+type Report struct {
+	Module   registry.ModuleRef
+	Currency string
+
+	// MonthlyTotal is the sum of all line items
+	MonthlyTotal float64
+
+	// ByResourceType sums line items per resource type
+	ByResourceType map[string]float64
+
+	// ByPath sums line items per module part; the root module uses an empty path
+	ByPath map[string]float64
+
+	Items    []LineItem
+	Unpriced []UnpricedResource
+}
+
+// EstimateModuleCost fetches a module and estimates its monthly cost from the resources
+// declared by the root module and its submodules. An empty or "latest" version estimates
+// the latest release.
+func EstimateModuleCost(ctx context.Context, client *registry.Client, estimator Estimator, ref registry.ModuleRef, inputs map[string]any) (*Report, error) {
+	var (
+		details *registry.ModuleDetails
+		err     error
+	)
+	if ref.Version == "" || ref.Version == "latest" {
+		details, err = client.Modules.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	} else {
+		details, err = client.Modules.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module %s: %w", ref, err)
+	}
+
+	ref.Version = details.Version
+	return EstimateModuleDetails(ctx, estimator, ref, details, inputs)
+}
+
+// EstimateModuleDetails estimates the monthly cost of already-fetched module details.
+// Resources the estimator cannot price are listed in Unpriced rather than failing the
+// report; estimates in a currency other than the report's are an error.
+func EstimateModuleDetails(ctx context.Context, estimator Estimator, ref registry.ModuleRef, details *registry.ModuleDetails, inputs map[string]any) (*Report, error) {
+	if estimator == nil {
+		return nil, &registry.ValidationError{
+			Field:   "estimator",
+			Message: "estimator cannot be nil",
+		}
+	}
+	if details == nil {
+		return nil, &registry.ValidationError{
+			Field:   "details",
+			Message: "module details cannot be nil",
+		}
+	}
+
+	report := &Report{
+		Module:         ref,
+		ByResourceType: make(map[string]float64),
+		ByPath:         make(map[string]float64),
+	}
+
+	parts := append([]registry.ModulePart{details.Root}, details.Submodules...)
+	for i, part := range parts {
+		path := part.Path
+		if i == 0 {
+			path = ""
+		}
+
+		for _, resource := range part.Resources {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			estimate, err := estimator.EstimateResource(ctx, ResourceRequest{
+				Module:   ref,
+				Path:     path,
+				Resource: resource,
+				Inputs:   inputs,
+			})
+			if err != nil {
+				report.Unpriced = append(report.Unpriced, UnpricedResource{Path: path, Resource: resource, Err: err})
+				continue
+			}
+
+			if estimate.Currency == "" {
+				estimate.Currency = DefaultCurrency
+			}
+			if report.Currency == "" {
+				report.Currency = estimate.Currency
+			} else if estimate.Currency != report.Currency {
+				return nil, fmt.Errorf("estimate for %s is in %s, report is in %s", resource.Type, estimate.Currency, report.Currency)
+			}
+
+			report.Items = append(report.Items, LineItem{Path: path, Resource: resource, ResourceEstimate: *estimate})
+			report.MonthlyTotal += estimate.MonthlyCost
+			report.ByResourceType[resource.Type] += estimate.MonthlyCost
+			report.ByPath[path] += estimate.MonthlyCost
+		}
+	}
+
+	if report.Currency == "" {
+		report.Currency = DefaultCurrency
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].MonthlyCost > report.Items[j].MonthlyCost
+	})
+
+	return report, nil
+}
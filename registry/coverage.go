@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CoverageReport compares the provider resources a module manages with those available
+// in a provider subcategory
+type CoverageReport struct {
+	Module      ModuleRef
+	Provider    ProviderRef
+	Subcategory string
+
+	// Covered lists the subcategory resources the module manages
+	Covered []CoveredResource
+
+	// Missing lists the subcategory resource types the module does not manage
+	Missing []string
+
+	// Outside lists resource types of the provider the module manages outside the subcategory
+	Outside []string
+
+	// Ratio is the share of subcategory resources the module manages, from 0 to 1
+	Ratio float64
+}
+
+// CoveredResource is a provider resource managed by a module
+type CoveredResource struct {
+	// Type is the resource type, e.g. aws_vpc
+	Type string
+
+	// Paths are the module parts managing the resource; "" is the root module
+	Paths []string
+}
+
+// Coverage reports which resources of a provider subcategory a module manages, across
+// its root module and submodules, to find gaps in golden modules. The provider is the
+// module's provider at its latest version; its namespace is taken from the module's
+// provider dependencies, defaulting to hashicorp. An empty ref.Version uses the latest
+// module version.
+func (s *AnalyzeService) Coverage(ctx context.Context, ref ModuleRef, subcategory string) (*CoverageReport, error) {
+	if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ""); err != nil {
+		return nil, err
+	}
+
+	if subcategory == "" {
+		return nil, &ValidationError{
+			Field:   "subcategory",
+			Value:   subcategory,
+			Message: "subcategory cannot be empty",
+		}
+	}
+
+	var (
+		details *ModuleDetails
+		err     error
+	)
+	if ref.Version == "" || ref.Version == "latest" {
+		details, err = s.client.Modules.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	} else {
+		details, err = s.client.Modules.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ref.Version = details.Version
+
+	provider := ProviderRef{Namespace: moduleProviderNamespace(details), Name: details.Provider}
+
+	latest, err := s.client.Providers.GetLatest(ctx, provider.Namespace, provider.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest version of %s: %w", provider, err)
+	}
+	provider.Version = latest.Version
+
+	versionID, err := s.client.Providers.GetVersionID(ctx, provider.Namespace, provider.Name, provider.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version ID: %w", err)
+	}
+
+	docs, err := s.client.Providers.ListDocsV2(ctx, &ProviderDocListOptions{
+		ProviderVersionID: versionID,
+		Category:          "resources",
+		Subcategory:       subcategory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s resources: %w", provider, err)
+	}
+
+	// Doc listings carry only IDs, so map them to slugs through the slug index
+	index, err := s.client.Providers.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	slugs := make(map[string]string, len(index["resources"]))
+	for slug, id := range index["resources"] {
+		slugs[id] = slug
+	}
+
+	available := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		if slug := slugs[doc.ID]; slug != "" {
+			available[provider.Name+"_"+slug] = true
+		}
+	}
+
+	return buildCoverageReport(ref, provider, subcategory, details, available), nil
+}
+
+// buildCoverageReport compares module resources with the available resource types
+func buildCoverageReport(ref ModuleRef, provider ProviderRef, subcategory string, details *ModuleDetails, available map[string]bool) *CoverageReport {
+	report := &CoverageReport{
+		Module:      ref,
+		Provider:    provider,
+		Subcategory: subcategory,
+	}
+
+	managed := make(map[string][]string)
+	parts := append([]ModulePart{details.Root}, details.Submodules...)
+	for _, part := range parts {
+		seen := make(map[string]bool)
+		for _, resource := range part.Resources {
+			if !seen[resource.Type] {
+				seen[resource.Type] = true
+				managed[resource.Type] = append(managed[resource.Type], part.Path)
+			}
+		}
+	}
+
+	prefix := provider.Name + "_"
+	for resourceType, paths := range managed {
+		switch {
+		case available[resourceType]:
+			report.Covered = append(report.Covered, CoveredResource{Type: resourceType, Paths: paths})
+		case strings.HasPrefix(resourceType, prefix):
+			report.Outside = append(report.Outside, resourceType)
+		}
+	}
+
+	for resourceType := range available {
+		if _, ok := managed[resourceType]; !ok {
+			report.Missing = append(report.Missing, resourceType)
+		}
+	}
+
+	sort.Slice(report.Covered, func(i, j int) bool { return report.Covered[i].Type < report.Covered[j].Type })
+	sort.Strings(report.Missing)
+	sort.Strings(report.Outside)
+
+	if len(available) > 0 {
+		report.Ratio = float64(len(report.Covered)) / float64(len(available))
+	}
+
+	return report
+}
+
+// moduleProviderNamespace returns the namespace of a module's own provider
+func moduleProviderNamespace(details *ModuleDetails) string {
+	for _, dep := range details.Root.ProviderDependencies {
+		if dep.Name == details.Provider && dep.Namespace != "" {
+			return dep.Namespace
+		}
+	}
+	return "hashicorp"
+}
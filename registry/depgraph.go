@@ -0,0 +1,535 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultDependencyDepth is the number of module levels BuildDependencyGraph resolves
+// when given a depth of zero
+const DefaultDependencyDepth = 5
+
+// DependencyNodeKind is the kind of a dependency graph node
+type DependencyNodeKind string
+
+const (
+	// DependencyNodeModule is a registry module version
+	DependencyNodeModule DependencyNodeKind = "module"
+
+	// DependencyNodeProvider is a provider required by a module
+	DependencyNodeProvider DependencyNodeKind = "provider"
+
+	// DependencyNodeExternal is a module source outside the registry, such as a Git
+	// repository, an archive URL or a local path that is not a submodule
+	DependencyNodeExternal DependencyNodeKind = "external"
+)
+
+// DependencyNode is a module, provider or external source in a dependency graph
+type DependencyNode struct {
+	// ID is unique within the graph: the kind, a colon and the address, plus "@version"
+	// for resolved modules
+	ID   string             `json:"id"`
+	Kind DependencyNodeKind `json:"kind"`
+
+	// Address is namespace/name/provider for modules, namespace/name for providers and
+	// the source for external nodes
+	Address string `json:"address"`
+
+	// Version is the resolved version of a module
+	Version string `json:"version,omitempty"`
+
+	// Depth is the number of module dependencies between the root and the node
+	Depth int `json:"depth"`
+
+	// Truncated is set on modules whose dependencies were not resolved because they are
+	// at the depth limit
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Error is set on modules that could not be resolved; their dependencies are unknown
+	Error string `json:"error,omitempty"`
+}
+
+// DependencyEdge is a dependency of one node on another
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Name is the module call or provider name in the dependent module
+	Name string `json:"name"`
+
+	// Constraint is the version constraint the dependent module declares
+	Constraint string `json:"constraint,omitempty"`
+}
+
+// DependencyGraph is the transitive dependency graph of a module version
+type DependencyGraph struct {
+	// Root is the ID of the module the graph was built for
+	Root string `json:"root"`
+
+	// Nodes are ordered by depth, then by ID
+	Nodes []DependencyNode `json:"nodes"`
+
+	// Edges are ordered by their From and To IDs
+	Edges []DependencyEdge `json:"edges"`
+
+	// Cycles lists every dependency cycle as the node IDs along it, starting and
+	// ending with the same node
+	Cycles [][]string `json:"cycles,omitempty"`
+}
+
+// Node returns the node with the given ID
+func (g *DependencyGraph) Node(id string) (DependencyNode, bool) {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node, true
+		}
+	}
+	return DependencyNode{}, false
+}
+
+// JSON returns the graph as indented JSON
+func (g *DependencyGraph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// DOT returns the graph in Graphviz DOT format. Modules are boxes, providers ellipses
+// and external sources notes; unresolved modules and edges on cycles are red.
+func (g *DependencyGraph) DOT() string {
+	onCycle := make(map[[2]string]bool)
+	for _, cycle := range g.Cycles {
+		for i := 0; i+1 < len(cycle); i++ {
+			onCycle[[2]string{cycle[i], cycle[i+1]}] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, node := range g.Nodes {
+		label := node.Address
+		if node.Version != "" {
+			label += "\\n" + node.Version
+		}
+
+		attrs := []string{fmt.Sprintf("label=%q", label)}
+		switch node.Kind {
+		case DependencyNodeModule:
+			attrs = append(attrs, "shape=box")
+		case DependencyNodeProvider:
+			attrs = append(attrs, "shape=ellipse")
+		default:
+			attrs = append(attrs, "shape=note")
+		}
+		if node.Error != "" {
+			attrs = append(attrs, "color=red")
+		}
+		if node.Truncated {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attrs := []string{}
+		if edge.Constraint != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", edge.Constraint))
+		}
+		if onCycle[[2]string{edge.From, edge.To}] {
+			attrs = append(attrs, "color=red")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+			continue
+		}
+		fmt.Fprintf(&b, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// BuildDependencyGraph resolves the module and provider dependencies of a module version
+// into a graph, following registry module dependencies up to depth levels
+// (DefaultDependencyDepth when zero). Each module dependency is resolved to the newest
+// version matching its constraint. Calls of local submodules are followed within the
+// module, so their dependencies count as the module's own; other sources outside the
+// registry become external nodes. An empty or "latest" version uses the latest release.
+// Dependencies that cannot be resolved are marked on their nodes and reported in the
+// returned MultiError alongside the graph.
+func (s *ModulesService) BuildDependencyGraph(ctx context.Context, namespace, name, provider, version string, depth int) (*DependencyGraph, error) {
+	latest := version == "" || version == "latest"
+	if latest {
+		version = ""
+	}
+	if err := validateModuleParams(namespace, name, provider, version); err != nil {
+		return nil, err
+	}
+	if depth < 0 {
+		return nil, &ValidationError{
+			Field:   "depth",
+			Value:   depth,
+			Message: "depth cannot be negative",
+		}
+	}
+	if depth == 0 {
+		depth = DefaultDependencyDepth
+	}
+
+	var root *ModuleDetails
+	var err error
+	if latest {
+		root, err = s.GetLatest(ctx, namespace, name, provider)
+	} else {
+		root, err = s.Get(ctx, namespace, name, provider, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	builder := &dependencyGraphBuilder{
+		service: s,
+		host:    registryHost(s.client.GetBaseURL()),
+		nodes:   make(map[string]*DependencyNode),
+		edges:   make(map[DependencyEdge]bool),
+	}
+
+	rootRef := ModuleRef{Namespace: namespace, Name: name, Provider: provider, Version: root.Version}
+	rootNode := builder.addModule(rootRef, 0)
+
+	level := []resolvedModule{{id: rootNode.ID, details: root}}
+	for d := 0; len(level) > 0; d++ {
+		level = builder.expand(ctx, level, d, d+1 >= depth)
+	}
+
+	return builder.graph(rootNode.ID), builder.errs.ErrorOrNil()
+}
+
+// resolvedModule is a module node whose details have been fetched
+type resolvedModule struct {
+	id      string
+	details *ModuleDetails
+}
+
+// dependencyGraphBuilder accumulates the nodes and edges of a dependency graph
+type dependencyGraphBuilder struct {
+	service *ModulesService
+
+	// host is the registry host; module sources on other hosts are external
+	host string
+
+	nodes map[string]*DependencyNode
+	edges map[DependencyEdge]bool
+	errs  MultiError
+}
+
+// moduleResolution is the outcome of resolving a module dependency constraint
+type moduleResolution struct {
+	ref     ModuleRef
+	details *ModuleDetails
+}
+
+// expand adds the dependencies of the modules at depth d and returns the newly found
+// modules to expand next; at the last level they are only marked as truncated
+func (b *dependencyGraphBuilder) expand(ctx context.Context, level []resolvedModule, d int, last bool) []resolvedModule {
+	type call struct {
+		from string
+		dep  ModuleDependency
+		ref  ModuleRef
+	}
+
+	var calls []call
+	var keys []string
+	for _, module := range level {
+		modules, providers := collectDependencies(module.details)
+
+		for _, dep := range providers {
+			address := providerDependencyAddress(dep)
+			id := b.addNode(DependencyNodeProvider, address, "", d+1)
+			b.addEdge(module.id, id, dep.Name, dep.Version)
+		}
+
+		for _, dep := range modules {
+			ref, ok := parseRegistryModuleSource(dep.Source, b.host)
+			if !ok {
+				id := b.addNode(DependencyNodeExternal, dep.Source, "", d+1)
+				b.addEdge(module.id, id, dep.Name, dep.Version)
+				continue
+			}
+			calls = append(calls, call{from: module.id, dep: dep, ref: ref})
+			keys = append(keys, ref.String()+" "+dep.Version)
+		}
+	}
+
+	results := runBatch(ctx, keys, DefaultBatchConcurrency, func(ctx context.Context, key string) (moduleResolution, error) {
+		address, constraint, _ := strings.Cut(key, " ")
+		parts := strings.Split(address, "/")
+		ref := ModuleRef{Namespace: parts[0], Name: parts[1], Provider: parts[2]}
+		return b.resolve(ctx, ref, constraint, last)
+	})
+
+	var next []resolvedModule
+	for _, c := range calls {
+		key := c.ref.String() + " " + c.dep.Version
+		result := results[key]
+		if result.Err != nil {
+			id := b.addNode(DependencyNodeModule, c.ref.String(), "", d+1)
+			if node := b.nodes[id]; node.Error == "" {
+				node.Error = result.Err.Error()
+				b.errs.Add(fmt.Errorf("failed to resolve module %s %q: %w", c.ref, c.dep.Version, result.Err))
+			}
+			b.addEdge(c.from, id, c.dep.Name, c.dep.Version)
+			continue
+		}
+
+		ref := result.Value.ref
+		id := moduleNodeID(ref)
+		_, seen := b.nodes[id]
+		b.addModule(ref, d+1)
+		b.addEdge(c.from, id, c.dep.Name, c.dep.Version)
+
+		if seen {
+			continue
+		}
+		if last {
+			b.nodes[id].Truncated = true
+			continue
+		}
+		next = append(next, resolvedModule{id: id, details: result.Value.details})
+	}
+
+	return next
+}
+
+// resolve picks the newest version of a module matching constraint and, unless only the
+// version is needed, fetches its details
+func (b *dependencyGraphBuilder) resolve(ctx context.Context, ref ModuleRef, constraint string, versionOnly bool) (moduleResolution, error) {
+	parsed, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return moduleResolution{}, err
+	}
+
+	versions, err := b.service.ListVersions(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return moduleResolution{}, err
+	}
+
+	for _, version := range versions {
+		if parsed.Check(version) && (ref.Version == "" || CompareVersions(version, ref.Version) > 0) {
+			ref.Version = version
+		}
+	}
+	if ref.Version == "" {
+		return moduleResolution{}, fmt.Errorf("no version of %s matches %q", ref, constraint)
+	}
+
+	if versionOnly {
+		return moduleResolution{ref: ref}, nil
+	}
+
+	details, err := b.service.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	if err != nil {
+		return moduleResolution{}, err
+	}
+	return moduleResolution{ref: ref, details: details}, nil
+}
+
+// addModule adds a resolved module node and returns it
+func (b *dependencyGraphBuilder) addModule(ref ModuleRef, depth int) *DependencyNode {
+	address := ModuleRef{Namespace: ref.Namespace, Name: ref.Name, Provider: ref.Provider}.String()
+	return b.nodes[b.addNode(DependencyNodeModule, address, ref.Version, depth)]
+}
+
+// addNode adds a node unless it exists and returns its ID
+func (b *dependencyGraphBuilder) addNode(kind DependencyNodeKind, address, version string, depth int) string {
+	id := string(kind) + ":" + address
+	if version != "" {
+		id += "@" + version
+	}
+	if _, ok := b.nodes[id]; !ok {
+		b.nodes[id] = &DependencyNode{ID: id, Kind: kind, Address: address, Version: version, Depth: depth}
+	}
+	return id
+}
+
+// addEdge adds an edge unless it exists
+func (b *dependencyGraphBuilder) addEdge(from, to, name, constraint string) {
+	b.edges[DependencyEdge{From: from, To: to, Name: name, Constraint: constraint}] = true
+}
+
+// graph returns the accumulated graph in a stable order with its cycles
+func (b *dependencyGraphBuilder) graph(root string) *DependencyGraph {
+	graph := &DependencyGraph{Root: root}
+
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, *node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Depth != graph.Nodes[j].Depth {
+			return graph.Nodes[i].Depth < graph.Nodes[j].Depth
+		}
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+
+	for edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, c := graph.Edges[i], graph.Edges[j]
+		if a.From != c.From {
+			return a.From < c.From
+		}
+		if a.To != c.To {
+			return a.To < c.To
+		}
+		return a.Name < c.Name
+	})
+
+	graph.Cycles = findCycles(graph.Nodes, graph.Edges)
+	return graph
+}
+
+// findCycles returns a cycle for every back edge found by a depth-first search
+func findCycles(nodes []DependencyNode, edges []DependencyEdge) [][]string {
+	adjacent := make(map[string][]string)
+	for _, edge := range edges {
+		if n := adjacent[edge.From]; len(n) == 0 || n[len(n)-1] != edge.To {
+			adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+		}
+	}
+
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onStack
+		stack = append(stack, id)
+		for _, next := range adjacent[id] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case onStack:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == next {
+						cycle := append(append([]string(nil), stack[i:]...), next)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	for _, node := range nodes {
+		if state[node.ID] == unvisited {
+			visit(node.ID)
+		}
+	}
+	return cycles
+}
+
+// collectDependencies returns the module and provider dependencies of a module version:
+// those of the root module and of the submodules it calls by local path, transitively.
+// Local calls that do not match a submodule are returned as module dependencies.
+func collectDependencies(details *ModuleDetails) ([]ModuleDependency, []ModuleProviderDependency) {
+	submodules := make(map[string]*ModulePart, len(details.Submodules))
+	for i := range details.Submodules {
+		submodules[strings.Trim(details.Submodules[i].Path, "/")] = &details.Submodules[i]
+	}
+
+	var modules []ModuleDependency
+	var providers []ModuleProviderDependency
+	visited := map[string]bool{"": true}
+
+	var walk func(part *ModulePart, dir string)
+	walk = func(part *ModulePart, dir string) {
+		providers = append(providers, part.ProviderDependencies...)
+		for _, dep := range part.Dependencies {
+			if !strings.HasPrefix(dep.Source, "./") && !strings.HasPrefix(dep.Source, "../") {
+				modules = append(modules, dep)
+				continue
+			}
+
+			target := path.Join(dir, dep.Source)
+			submodule, ok := submodules[target]
+			if !ok || strings.HasPrefix(target, "..") {
+				modules = append(modules, dep)
+				continue
+			}
+			if !visited[target] {
+				visited[target] = true
+				walk(submodule, target)
+			}
+		}
+	}
+	walk(&details.Root, "")
+
+	return modules, providers
+}
+
+// parseRegistryModuleSource parses a [host/]namespace/name/provider[//subdir] module
+// source on the given registry host. Sources with another host, local paths, URLs and
+// VCS shorthands such as github.com/org/repo are not registry modules.
+func parseRegistryModuleSource(source, host string) (ModuleRef, bool) {
+	if source == "" || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") ||
+		strings.ContainsAny(source, ":?") {
+		return ModuleRef{}, false
+	}
+
+	address, _, _ := strings.Cut(source, "//")
+	parts := strings.Split(address, "/")
+	if len(parts) == 4 && strings.Contains(parts[0], ".") {
+		if !strings.EqualFold(parts[0], host) {
+			return ModuleRef{}, false
+		}
+		parts = parts[1:]
+	}
+	if len(parts) != 3 || strings.Contains(parts[0], ".") || validateModuleParams(parts[0], parts[1], parts[2], "") != nil {
+		return ModuleRef{}, false
+	}
+
+	return ModuleRef{Namespace: parts[0], Name: parts[1], Provider: parts[2]}, true
+}
+
+// providerDependencyAddress returns the namespace/name of a provider dependency, without
+// the default registry host
+func providerDependencyAddress(dep ModuleProviderDependency) string {
+	if source := strings.TrimPrefix(strings.ToLower(dep.Source), "registry.terraform.io/"); source != "" {
+		return source
+	}
+
+	// Terraform assumes the hashicorp namespace when a provider has no source
+	namespace := dep.Namespace
+	if namespace == "" {
+		namespace = "hashicorp"
+	}
+	return strings.ToLower(namespace + "/" + dep.Name)
+}
+
+// moduleNodeID returns the node ID of a resolved module
+func moduleNodeID(ref ModuleRef) string {
+	return string(DependencyNodeModule) + ":" + ModuleRef{Namespace: ref.Namespace, Name: ref.Name, Provider: ref.Provider}.String() + "@" + ref.Version
+}
+
+// registryHost returns the host of a registry base URL
+func registryHost(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
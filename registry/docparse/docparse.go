@@ -0,0 +1,405 @@
+// Package docparse parses the Markdown of provider resource and data source docs into a
+// structured model: the argument and attribute references, import instructions and
+// example snippets. It understands the "## Schema" layout generated by tfplugindocs and
+// the classic "Argument Reference" / "Attributes Reference" layout. Sections it does not
+// recognize are skipped, so docs following neither layout produce a model holding only
+// the title, description and examples.
+package docparse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Doc is the structured form of a provider doc
+type Doc struct {
+	// Title is the text of the "# " heading, e.g. "aws_instance (Resource)"
+	Title string
+
+	// Description is the first paragraph below the title
+	Description string
+
+	// Arguments are the configurable arguments; arguments of nested blocks are prefixed
+	// with the block path, e.g. "root_block_device.volume_size"
+	Arguments []Argument
+
+	// Attributes are the exported read-only attributes, prefixed like Arguments
+	Attributes []Attribute
+
+	// Imports are the import instructions of the "## Import" section
+	Imports []Import
+
+	// Examples are the code blocks outside the "## Import" section
+	Examples []Example
+}
+
+// Argument is an entry of the argument reference
+type Argument struct {
+	Name string
+
+	// Type is the documented type, e.g. "String" or "Block List, Max: 1"; empty when the
+	// docs do not state it
+	Type string
+
+	Description string
+
+	// Required reports a required argument; other arguments are optional
+	Required bool
+}
+
+// Attribute is an entry of the attribute reference
+type Attribute struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// ImportStyle is the way an Import is written
+type ImportStyle string
+
+const (
+	// ImportBlock is an import block in configuration (Terraform 1.5+)
+	ImportBlock ImportStyle = "block"
+
+	// ImportCommand is a terraform import command
+	ImportCommand ImportStyle = "command"
+)
+
+// Import is an import instruction
+type Import struct {
+	Style ImportStyle
+
+	// Address is the resource address imported to, e.g. "aws_instance.web"
+	Address string
+
+	// ID is the example import ID, e.g. "i-12345678"
+	ID string
+
+	// Code is the code block holding the instruction
+	Code string
+}
+
+// Example is a fenced code block
+type Example struct {
+	// Section is the title of the heading above the block, e.g. "Example Usage"
+	Section string
+
+	// Language is the info string of the fence, e.g. "terraform"; empty when the fence
+	// has none
+	Language string
+
+	Code string
+}
+
+// Argument returns the argument with a name
+func (d *Doc) Argument(name string) (Argument, bool) {
+	for _, argument := range d.Arguments {
+		if argument.Name == name {
+			return argument, true
+		}
+	}
+	return Argument{}, false
+}
+
+// RequiredArguments returns the required arguments
+func (d *Doc) RequiredArguments() []Argument {
+	var required []Argument
+	for _, argument := range d.Arguments {
+		if argument.Required {
+			required = append(required, argument)
+		}
+	}
+	return required
+}
+
+// doc sections
+const (
+	sectionNone = iota
+	sectionSchema
+	sectionArguments
+	sectionAttributes
+	sectionImport
+)
+
+var (
+	titlePattern        = regexp.MustCompile(`^#\s+(.+?)\s*#*\s*$`)
+	headingPattern      = regexp.MustCompile(`^(#{2,4})\s+(.+?)\s*#*\s*$`)
+	fencePattern        = regexp.MustCompile("^(```+|~~~+)\\s*([A-Za-z0-9_+-]*)")
+	nestedSchemaPattern = regexp.MustCompile("^Nested Schema for `([^`]+)`")
+	schemaBullet        = regexp.MustCompile("^[-*]\\s+`([^`]+)`\\s+\\(([^)]+)\\)\\s*(.*)$")
+	schemaGroup         = regexp.MustCompile(`^(Required|Optional|Read-Only):?\s*$`)
+	classicBullet       = regexp.MustCompile("^[-*]\\s+`([^`]+)`\\s*(?:[-–—:]\\s*)?(.*)$")
+	classicFlags        = regexp.MustCompile(`^\(([^)]*)\)\s*(.*)$`)
+	blockIntroPattern   = regexp.MustCompile("^(?:The|An?|Each)?\\s*`([^`]+)`\\s+(?:configuration\\s+|nested\\s+)?(?:block|object)s?\\b")
+	identifierPattern   = regexp.MustCompile("^`?([a-z0-9_]+)`?$")
+	seeBelowPattern     = regexp.MustCompile(`^\(see \[below for nested schema\]\([^)]*\)\)\s*`)
+	importToPattern     = regexp.MustCompile(`^\s*to\s*=\s*(\S+)`)
+	importIDPattern     = regexp.MustCompile(`^\s*id\s*=\s*"([^"]*)"`)
+	importCmdPattern    = regexp.MustCompile(`^(?:[$%>]\s*)?terraform\s+import\s+(?:-\S+\s+)*(\S+)\s+(.+?)\s*$`)
+)
+
+// classicTypes are the type words recognized in "(Optional, String)" annotations
+var classicTypes = map[string]bool{
+	"string": true, "number": true, "bool": true, "boolean": true, "int": true, "float": true,
+	"list": true, "set": true, "map": true, "block": true, "object": true,
+}
+
+// parser holds the state of a Parse
+type parser struct {
+	doc *Doc
+
+	section int
+	group   string
+	prefix  string
+	heading string
+
+	arguments  map[string]int
+	attributes map[string]int
+}
+
+// Parse parses the Markdown content of a provider doc. YAML front matter is skipped.
+// Entries listed more than once are merged, keeping the first type and description.
+func Parse(content string) *Doc {
+	p := &parser{
+		doc:        &Doc{},
+		arguments:  make(map[string]int),
+		attributes: make(map[string]int),
+	}
+
+	var (
+		fence       string
+		lang        string
+		code        []string
+		description []string
+		inIntro     bool
+	)
+
+	for _, raw := range strings.Split(stripFrontMatter(content), "\n") {
+		line := strings.TrimSpace(raw)
+
+		if fence != "" {
+			if strings.HasPrefix(line, fence) && strings.Trim(line, fence[:1]) == "" {
+				p.codeBlock(lang, strings.Join(code, "\n"))
+				fence, code = "", nil
+			} else {
+				code = append(code, strings.TrimRight(raw, " \t"))
+			}
+			continue
+		}
+		if match := fencePattern.FindStringSubmatch(line); match != nil {
+			fence, lang = match[1], match[2]
+			inIntro = false
+			continue
+		}
+
+		if match := titlePattern.FindStringSubmatch(line); match != nil && p.doc.Title == "" && p.heading == "" {
+			p.doc.Title = match[1]
+			inIntro = true
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			p.enterHeading(len(match[1]), match[2])
+			inIntro = false
+			continue
+		}
+
+		if inIntro {
+			switch {
+			case line != "":
+				description = append(description, line)
+			case len(description) > 0:
+				inIntro = false
+			}
+			continue
+		}
+		if line != "" {
+			p.line(line)
+		}
+	}
+	// An unterminated code block runs to the end of the doc
+	if fence != "" {
+		p.codeBlock(lang, strings.Join(code, "\n"))
+	}
+
+	p.doc.Description = strings.Join(description, " ")
+	return p.doc
+}
+
+// enterHeading switches sections on a "##" or deeper heading
+func (p *parser) enterHeading(level int, title string) {
+	lower := strings.ToLower(title)
+	if level == 2 {
+		p.heading = title
+	}
+
+	switch {
+	case level == 2 && lower == "schema":
+		p.section, p.group, p.prefix = sectionSchema, "", ""
+	case level == 2 && strings.HasPrefix(lower, "import"):
+		p.section, p.group, p.prefix = sectionImport, "", ""
+	case level == 2 && strings.Contains(lower, "argument"):
+		p.section, p.group, p.prefix = sectionArguments, "", ""
+	case level == 2 && strings.Contains(lower, "attribute"):
+		p.section, p.group, p.prefix = sectionAttributes, "", ""
+	case nestedSchemaPattern.MatchString(title) && (p.section == sectionSchema || level == 2):
+		// Nested schemas follow "## Schema" but some docs use level 2 headings
+		p.section, p.group = sectionSchema, ""
+		p.prefix = nestedSchemaPattern.FindStringSubmatch(title)[1] + "."
+	case p.section == sectionSchema && schemaGroup.MatchString(title):
+		p.group = schemaGroup.FindStringSubmatch(title)[1]
+	case level == 2:
+		p.section, p.group, p.prefix = sectionNone, "", ""
+	case p.section == sectionArguments || p.section == sectionAttributes:
+		// "### root_block_device" introduces a nested block
+		if match := identifierPattern.FindStringSubmatch(title); match != nil {
+			p.prefix = match[1] + "."
+		}
+	}
+
+	if level > 2 && p.section == sectionNone {
+		p.heading = title
+	}
+}
+
+// line reads a non-empty text line of the current section
+func (p *parser) line(line string) {
+	switch p.section {
+	case sectionSchema:
+		if match := schemaGroup.FindStringSubmatch(line); match != nil {
+			p.group = match[1]
+			return
+		}
+		match := schemaBullet.FindStringSubmatch(line)
+		if match == nil || p.group == "" {
+			return
+		}
+		name, typ := p.prefix+match[1], match[2]
+		description := seeBelowPattern.ReplaceAllString(match[3], "")
+		if p.group == "Read-Only" {
+			p.addAttribute(Attribute{Name: name, Type: typ, Description: description})
+		} else {
+			p.addArgument(Argument{Name: name, Type: typ, Description: description, Required: p.group == "Required"})
+		}
+
+	case sectionArguments, sectionAttributes:
+		if match := blockIntroPattern.FindStringSubmatch(line); match != nil && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "*") {
+			p.prefix = match[1] + "."
+			return
+		}
+		if strings.HasPrefix(strings.ToLower(line), "the following") {
+			p.prefix = ""
+			return
+		}
+		match := classicBullet.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+		if p.section == sectionAttributes {
+			p.addAttribute(Attribute{Name: p.prefix + match[1], Description: match[2]})
+			return
+		}
+		argument := Argument{Name: p.prefix + match[1], Description: match[2]}
+		parseClassicFlags(&argument)
+		p.addArgument(argument)
+
+	case sectionImport:
+		if match := importCmdPattern.FindStringSubmatch(strings.Trim(line, "`")); match != nil {
+			p.doc.Imports = append(p.doc.Imports, Import{Style: ImportCommand, Address: match[1], ID: match[2], Code: line})
+		}
+	}
+}
+
+// codeBlock records a code block as import instructions or an example
+func (p *parser) codeBlock(lang, code string) {
+	if p.section != sectionImport {
+		p.doc.Examples = append(p.doc.Examples, Example{Section: p.heading, Language: lang, Code: code})
+		return
+	}
+
+	var current *Import
+	for _, line := range strings.Split(code, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "import {") || trimmed == "import":
+			p.doc.Imports = append(p.doc.Imports, Import{Style: ImportBlock, Code: code})
+			current = &p.doc.Imports[len(p.doc.Imports)-1]
+		case current != nil && importToPattern.MatchString(line):
+			current.Address = importToPattern.FindStringSubmatch(line)[1]
+		case current != nil && importIDPattern.MatchString(line):
+			current.ID = importIDPattern.FindStringSubmatch(line)[1]
+		default:
+			if match := importCmdPattern.FindStringSubmatch(trimmed); match != nil {
+				p.doc.Imports = append(p.doc.Imports, Import{Style: ImportCommand, Address: match[1], ID: match[2], Code: code})
+				current = nil
+			}
+		}
+	}
+}
+
+// addArgument adds an argument, merging it into an earlier entry of the same name
+func (p *parser) addArgument(argument Argument) {
+	if i, ok := p.arguments[argument.Name]; ok {
+		existing := &p.doc.Arguments[i]
+		existing.Required = existing.Required || argument.Required
+		if existing.Type == "" {
+			existing.Type = argument.Type
+		}
+		if existing.Description == "" {
+			existing.Description = argument.Description
+		}
+		return
+	}
+	p.arguments[argument.Name] = len(p.doc.Arguments)
+	p.doc.Arguments = append(p.doc.Arguments, argument)
+}
+
+// addAttribute adds an attribute, merging it into an earlier entry of the same name
+func (p *parser) addAttribute(attribute Attribute) {
+	if i, ok := p.attributes[attribute.Name]; ok {
+		existing := &p.doc.Attributes[i]
+		if existing.Type == "" {
+			existing.Type = attribute.Type
+		}
+		if existing.Description == "" {
+			existing.Description = attribute.Description
+		}
+		return
+	}
+	p.attributes[attribute.Name] = len(p.doc.Attributes)
+	p.doc.Attributes = append(p.doc.Attributes, attribute)
+}
+
+// parseClassicFlags reads a leading "(Required)" / "(Optional, String)" annotation
+func parseClassicFlags(argument *Argument) {
+	match := classicFlags.FindStringSubmatch(argument.Description)
+	if match == nil {
+		return
+	}
+	for _, flag := range strings.Split(match[1], ",") {
+		flag = strings.TrimSpace(flag)
+		lower := strings.ToLower(flag)
+		switch {
+		case lower == "required":
+			argument.Required = true
+		case classicTypes[strings.Fields(lower + " x")[0]]:
+			argument.Type = flag
+		}
+	}
+	argument.Description = match[2]
+}
+
+// stripFrontMatter removes a leading YAML front matter block
+func stripFrontMatter(content string) string {
+	if !strings.HasPrefix(content, "---") {
+		return content
+	}
+	rest := content[3:]
+	if end := strings.Index(rest, "\n---"); end >= 0 {
+		rest = rest[end+4:]
+		if newline := strings.IndexByte(rest, '\n'); newline >= 0 {
+			return rest[newline+1:]
+		}
+		return ""
+	}
+	return content
+}
@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+// DocPins maps providers, addressed as namespace/name, to the version whose docs should
+// be shown, typically the version a team runs. Attached to a context with WithDocPins,
+// the pins make doc retrieval resolve an empty or "latest" version to the pinned version,
+// so that documentation matches deployed providers rather than the newest release.
+// Explicit versions are used as given. The pins package persists pin maps to files.
+type DocPins map[string]string
+
+// Validate validates the provider addresses and versions of the pins
+func (p DocPins) Validate() error {
+	var errs MultiError
+
+	addresses := make([]string, 0, len(p))
+	for address := range p {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		namespace, name, ok := strings.Cut(address, "/")
+		if !ok || strings.Contains(name, "/") {
+			errs.Add(&ValidationError{
+				Field:   "address",
+				Value:   address,
+				Message: "doc pin address must be in namespace/name form",
+			})
+			continue
+		}
+		if err := validateProviderParams(namespace, name); err != nil {
+			errs.Add(fmt.Errorf("doc pin %s: %w", address, err))
+			continue
+		}
+
+		version := p[address]
+		if version == "" || version == "latest" {
+			errs.Add(&ValidationError{
+				Field:   "version",
+				Value:   version,
+				Message: fmt.Sprintf("doc pin %s must name a version", address),
+			})
+		} else if err := ValidateProviderVersion(version); err != nil {
+			errs.Add(&ValidationError{
+				Field:   "version",
+				Value:   version,
+				Message: fmt.Sprintf("doc pin %s: %v", address, err),
+			})
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// Version returns the pinned version of a provider. Addresses are matched without
+// regard to case, as the registry does.
+func (p DocPins) Version(namespace, name string) (string, bool) {
+	address := namespace + "/" + name
+	if version, ok := p[address]; ok {
+		return version, true
+	}
+	for pinned, version := range p {
+		if strings.EqualFold(pinned, address) {
+			return version, true
+		}
+	}
+	return "", false
+}
+
+var docPinsKey = registryctx.NewKey[DocPins]("doc-pins")
+
+// WithDocPins returns a context whose doc retrieval resolves unversioned requests for
+// pinned providers to the pinned version. This covers the methods that take a provider
+// version, such as GetSchema, GetResourceSchema, GetProviderResourceSummary, ExportDocs
+// and GetVersionID, whose version IDs in turn select the docs of ListDocsV2, GetGuide,
+// ListFunctions and the other version ID based methods. GetLatest and HasChanged still
+// report the newest release.
+func WithDocPins(ctx context.Context, pins DocPins) context.Context {
+	return docPinsKey.With(ctx, pins)
+}
+
+// DocPinsFromContext returns the doc pins attached to ctx, or nil
+func DocPinsFromContext(ctx context.Context) DocPins {
+	pins, _ := docPinsKey.Get(ctx)
+	return pins
+}
+
+// pinnedDocVersion returns the version pinned for a provider in ctx
+func pinnedDocVersion(ctx context.Context, namespace, name string) (string, bool) {
+	return DocPinsFromContext(ctx).Version(namespace, name)
+}
@@ -0,0 +1,327 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+)
+
+// maxShasumsSize limits downloaded SHA256SUMS files and their signatures
+const maxShasumsSize = 1 << 20
+
+var (
+	// ErrInvalidSignature is returned when a SHA256SUMS signature does not verify
+	// against the provider's signing keys
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrNoSignatureVerifier is returned when a package download needs its signature
+	// checked but no SignatureVerifier was given
+	ErrNoSignatureVerifier = errors.New("no signature verifier")
+)
+
+// ProviderDownload describes the package of a provider version for one platform, as
+// returned by the v1 provider registry protocol
+type ProviderDownload struct {
+	Protocols []string `json:"protocols"`
+	OS        string   `json:"os"`
+	Arch      string   `json:"arch"`
+	Filename  string   `json:"filename"`
+
+	// DownloadURL, SHASumsURL and SHASumsSignatureURL are absolute URLs
+	DownloadURL         string `json:"download_url"`
+	SHASumsURL          string `json:"shasums_url"`
+	SHASumsSignatureURL string `json:"shasums_signature_url"`
+
+	// SHASum is the hex SHA-256 checksum of the package
+	SHASum string `json:"shasum"`
+
+	SigningKeys SigningKeys `json:"signing_keys"`
+}
+
+// SigningKeys holds the keys that may sign a provider's SHA256SUMS file
+type SigningKeys struct {
+	GPGPublicKeys []GPGPublicKey `json:"gpg_public_keys"`
+}
+
+// GPGPublicKey is a provider signing key
+type GPGPublicKey struct {
+	KeyID          string `json:"key_id"`
+	ASCIIArmor     string `json:"ascii_armor"`
+	TrustSignature string `json:"trust_signature,omitempty"`
+	Source         string `json:"source,omitempty"`
+	SourceURL      string `json:"source_url,omitempty"`
+}
+
+// SignatureVerifier verifies detached signatures of SHA256SUMS files. The registry/gpg
+// package provides an OpenPGP implementation.
+type SignatureVerifier interface {
+	// VerifySignature checks a detached signature of data against the keys and returns
+	// the ID of the signing key. Failures should wrap ErrInvalidSignature.
+	VerifySignature(data, signature []byte, keys []GPGPublicKey) (string, error)
+}
+
+// PackageVerification reports how a downloaded provider package was verified
+type PackageVerification struct {
+	// SHA256 is the hex checksum of the downloaded package, which matched both the
+	// registry's shasum and the SHA256SUMS file
+	SHA256 string
+
+	// KeyID is the signing key whose signature of SHA256SUMS verified
+	KeyID string
+}
+
+// GetDownload returns the download URL, checksums and signing keys of a provider version
+// package for one platform. Relative URLs in the response are made absolute.
+func (s *ProvidersService) GetDownload(ctx context.Context, namespace, name, version, os, arch string) (*ProviderDownload, error) {
+	if err := validateProviderParams(namespace, name); err != nil {
+		return nil, err
+	}
+	if version == "" || version == "latest" {
+		return nil, &ValidationError{
+			Field:   "version",
+			Value:   version,
+			Message: "a specific version is required",
+		}
+	}
+	if err := ValidateProviderVersion(version); err != nil {
+		return nil, &ValidationError{
+			Field:   "version",
+			Value:   version,
+			Message: err.Error(),
+		}
+	}
+	if os == "" || arch == "" {
+		return nil, &ValidationError{
+			Field:   "platform",
+			Value:   os + "_" + arch,
+			Message: "os and arch cannot be empty",
+		}
+	}
+
+	ref := ProviderRef{Namespace: namespace, Name: name, Version: version}
+	if err := s.checkProviderDownload(ctx, "Providers.GetDownload", ref); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("providers/%s/%s/%s/download/%s/%s",
+		url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(version), url.PathEscape(os), url.PathEscape(arch))
+
+	var download ProviderDownload
+	if err := s.client.get(ctx, path, "v1", &download); err != nil {
+		return nil, fmt.Errorf("failed to get provider download %s/%s %s %s_%s: %w", namespace, name, version, os, arch, err)
+	}
+
+	base, err := url.Parse(fmt.Sprintf("%s/v1/%s", s.client.GetBaseURL(), path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	for _, u := range []*string{&download.DownloadURL, &download.SHASumsURL, &download.SHASumsSignatureURL} {
+		if *u == "" {
+			continue
+		}
+		ref, err := url.Parse(*u)
+		if err != nil {
+			return nil, &ResponseError{StatusCode: http.StatusOK, Err: fmt.Errorf("invalid URL %q: %w", *u, err)}
+		}
+		*u = base.ResolveReference(ref).String()
+	}
+
+	return &download, nil
+}
+
+// GetSigningKeys returns the GPG keys the registry holds for a namespace, as listed by
+// the v2 gpg-keys endpoint. These are the keys provider downloads of the namespace
+// carry, so signatures can be checked against keys fetched separately.
+func (s *ProvidersService) GetSigningKeys(ctx context.Context, namespace string) ([]GPGPublicKey, error) {
+	if !validate.Namespace(namespace) {
+		return nil, &ValidationError{
+			Field:   "namespace",
+			Value:   namespace,
+			Message: "invalid namespace format",
+		}
+	}
+
+	if s.client.isOpenTofu() {
+		return nil, s.client.unsupported("listing signing keys")
+	}
+
+	path := "gpg-keys?filter[namespace]=" + url.QueryEscape(namespace)
+
+	var result struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				KeyID          string `json:"key-id"`
+				ASCIIArmor     string `json:"ascii-armor"`
+				TrustSignature string `json:"trust-signature"`
+				Source         string `json:"source"`
+				SourceURL      string `json:"source-url"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := s.client.get(ctx, path, "v2", &result); err != nil {
+		return nil, fmt.Errorf("failed to get signing keys for %s: %w", namespace, err)
+	}
+
+	keys := make([]GPGPublicKey, 0, len(result.Data))
+	for _, key := range result.Data {
+		keys = append(keys, GPGPublicKey{
+			KeyID:          key.Attributes.KeyID,
+			ASCIIArmor:     key.Attributes.ASCIIArmor,
+			TrustSignature: key.Attributes.TrustSignature,
+			Source:         key.Attributes.Source,
+			SourceURL:      key.Attributes.SourceURL,
+		})
+	}
+
+	return keys, nil
+}
+
+// DownloadPackage streams a provider package to w and verifies it: the SHA256SUMS
+// signature must verify against the download's signing keys, SHA256SUMS must list the
+// package with the registry's shasum, and the streamed content must match it. Checks
+// that fail before the download starts leave w untouched; a checksum mismatch is only
+// known at the end, so discard what was written when an error is returned.
+func (s *ProvidersService) DownloadPackage(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier, w io.Writer) (*PackageVerification, error) {
+	listed, keyID, err := s.verifyShasums(ctx, download, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.openURL(ctx, download.DownloadURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), resp.Body); err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", download.Filename, timeoutError(err))
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if !strings.EqualFold(sum, listed) {
+		return nil, fmt.Errorf("%w: %s has checksum %s, expected %s", ErrChecksumMismatch, download.Filename, sum, listed)
+	}
+
+	return &PackageVerification{SHA256: sum, KeyID: keyID}, nil
+}
+
+// verifyShasums checks the signature of a download's SHA256SUMS file and that it lists
+// the package with the registry's shasum, returning the listed checksum and the ID of
+// the signing key
+func (s *ProvidersService) verifyShasums(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier) (string, string, error) {
+	if download == nil || download.DownloadURL == "" || download.Filename == "" {
+		return "", "", &ValidationError{
+			Field:   "download",
+			Value:   download,
+			Message: "download URL and filename are required",
+		}
+	}
+	if verifier == nil {
+		return "", "", ErrNoSignatureVerifier
+	}
+
+	shasums, err := s.client.fetchSmall(ctx, download.SHASumsURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download SHA256SUMS: %w", err)
+	}
+	signature, err := s.client.fetchSmall(ctx, download.SHASumsSignatureURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to download SHA256SUMS signature: %w", err)
+	}
+
+	keyID, err := verifier.VerifySignature(shasums, signature, download.SigningKeys.GPGPublicKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	listed, err := shasumFor(shasums, download.Filename)
+	if err != nil {
+		return "", "", err
+	}
+	if !strings.EqualFold(listed, download.SHASum) {
+		return "", "", fmt.Errorf("%w: SHA256SUMS lists %s for %s, registry reports %s", ErrChecksumMismatch, listed, download.Filename, download.SHASum)
+	}
+	return listed, keyID, nil
+}
+
+// shasumFor returns the checksum listed for a file in a SHA256SUMS file
+func shasumFor(shasums []byte, filename string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(shasums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == filename {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not listed in SHA256SUMS", ErrChecksumMismatch, filename)
+}
+
+// openURL performs a GET request for an artifact outside the registry API
+func (c *Client) openURL(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    rawURL,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    rawURL,
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, newAPIError(resp, body)
+	}
+	return resp, nil
+}
+
+// fetchSmall downloads a small artifact such as a SHA256SUMS file into memory
+func (c *Client) fetchSmall(ctx context.Context, rawURL string) ([]byte, error) {
+	if rawURL == "" {
+		return nil, errors.New("no URL published")
+	}
+
+	resp, err := c.openURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxShasumsSize+1))
+	if err != nil {
+		return nil, &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error reading %s: %w", rawURL, timeoutError(err)),
+		}
+	}
+	if len(data) > maxShasumsSize {
+		return nil, &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%s is larger than %d bytes", rawURL, maxShasumsSize),
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,574 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+const (
+	// DefaultDownloadChunkSize is the size of the ranges downloaded in parallel
+	DefaultDownloadChunkSize = 8 << 20
+
+	// DefaultDownloadConcurrency is the number of ranges downloaded at once
+	DefaultDownloadConcurrency = 4
+)
+
+var (
+	// ErrDownloadChanged is returned when an artifact changes on the server while it is
+	// downloaded in ranges
+	ErrDownloadChanged = errors.New("download changed on the server")
+
+	// ErrDownloadTooLarge is returned when a download exceeds its size limit
+	ErrDownloadTooLarge = errors.New("download exceeds size limit")
+)
+
+// WithParallelDownloads sets how large artifacts such as provider packages and module
+// archives are downloaded: servers supporting range requests serve artifacts larger than
+// chunkSize bytes in chunks, concurrency at a time. A concurrency of 1 downloads
+// sequentially; zero values use DefaultDownloadChunkSize and DefaultDownloadConcurrency.
+func WithParallelDownloads(chunkSize int64, concurrency int) ClientOption {
+	return func(c *ClientConfig) {
+		c.DownloadChunkSize = chunkSize
+		c.DownloadConcurrency = concurrency
+	}
+}
+
+// DownloadRequest describes an artifact to download with Client.Download
+type DownloadRequest struct {
+	URL string
+
+	// Checksum is a go-getter style "type:hex" checksum (md5, sha1, sha256 or sha512)
+	// verified while the download streams; when empty, the SHA-256 is computed only
+	Checksum string
+
+	// Offset is the number of bytes a previous attempt already wrote to the target.
+	// They are kept and only the rest is downloaded when the server supports range
+	// requests; otherwise the download starts over.
+	Offset int64
+
+	// IfRange is the validator, a strong ETag or a Last-Modified date, of the artifact
+	// the Offset bytes came from. It is sent as If-Range, so that an artifact replaced
+	// on the server since is downloaded from the start instead of being mixed with them.
+	IfRange string
+
+	// MaxSize limits the size of the artifact in bytes; zero means no limit
+	MaxSize int64
+
+	// Header holds additional request headers, such as credentials
+	Header http.Header
+
+	// begin is called before data is written with the offset the download continues
+	// from and the validator of the artifact, which is empty when the server sends none
+	begin func(offset int64, validator string) error
+}
+
+// DownloadTarget is where a download is written. Downloaded ranges arrive out of order
+// and are read back to compute the checksum; *os.File implements it.
+type DownloadTarget interface {
+	io.WriterAt
+	io.ReaderAt
+}
+
+// DownloadProgress reports the progress of a download
+type DownloadProgress struct {
+	URL string
+
+	// Written is the number of bytes in the target, including resumed ones
+	Written int64
+
+	// Total is the size of the artifact, or -1 when the server does not report it
+	Total int64
+}
+
+// DownloadResult describes a completed download
+type DownloadResult struct {
+	URL  string
+	Size int64
+
+	// Resumed is the number of bytes kept from a previous attempt
+	Resumed int64
+
+	// Chunks is the number of ranges downloaded, 1 for sequential downloads
+	Chunks int
+
+	// Checksum is the verified or computed checksum as "type:hex"
+	Checksum string
+}
+
+var downloadProgressKey = registryctx.NewKey[func(DownloadProgress)]("download-progress")
+
+// WithDownloadProgress returns a context that reports the progress of downloads made
+// with it to fn. Calls are not concurrent.
+func WithDownloadProgress(ctx context.Context, fn func(DownloadProgress)) context.Context {
+	return downloadProgressKey.With(ctx, fn)
+}
+
+// Download downloads an artifact outside the registry API into target. The first
+// request asks for the range starting at req.Offset; when the server supports ranges and
+// the artifact is larger than the configured chunk size, the rest is downloaded in
+// parallel ranges. Ranges interrupted by network errors are resumed where they stopped,
+// up to the configured number of retries, and later ranges are requested with If-Range
+// so that an artifact replaced on the server fails with ErrDownloadChanged instead of
+// being mixed; a resumed download is checked the same way with req.IfRange. The
+// checksum is computed while the data arrives and compared at the end; on
+// ErrChecksumMismatch the target holds the mismatching data.
+func (c *Client) Download(ctx context.Context, req DownloadRequest, target DownloadTarget) (*DownloadResult, error) {
+	if req.URL == "" {
+		return nil, &ValidationError{Field: "URL", Value: req.URL, Message: "download URL cannot be empty"}
+	}
+	if req.Offset < 0 {
+		return nil, &ValidationError{Field: "Offset", Value: req.Offset, Message: "offset cannot be negative"}
+	}
+
+	verifier, err := newChecksumVerifier(req.Checksum)
+	if err != nil {
+		return nil, err
+	}
+	if verifier.hash == nil {
+		verifier.kind, verifier.hash = "sha256", sha256.New()
+	}
+
+	ifRange := ""
+	if req.Offset > 0 {
+		ifRange = req.IfRange
+	}
+	resp, err := c.openRange(ctx, req, req.Offset, -1, ifRange)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := req.Offset
+	total := int64(-1)
+	ranged := false
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, _, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil || start != offset {
+			resp.Body.Close()
+			return nil, &ResponseError{StatusCode: resp.StatusCode, Err: fmt.Errorf("unexpected range %q for offset %d", resp.Header.Get("Content-Range"), offset)}
+		}
+		if ifRange != "" && rangeValidator(resp.Header) != ifRange {
+			// The server ignored If-Range and serves another artifact
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s no longer matches %s", ErrDownloadChanged, req.URL, ifRange)
+		}
+		ranged, total = true, size
+	case http.StatusRequestedRangeNotSatisfiable:
+		resp.Body.Close()
+		_, _, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		switch {
+		case offset == 0:
+			return nil, newAPIError(resp, nil)
+		case err != nil || size != offset:
+			// The artifact is shorter than what a previous attempt downloaded
+			return nil, fmt.Errorf("%w: %s cannot be resumed at byte %d (Content-Range %q)", ErrDownloadChanged, req.URL, offset, resp.Header.Get("Content-Range"))
+		}
+		// A previous attempt already downloaded everything
+		ranged, total = true, offset
+		resp = nil
+	default:
+		// The server ignored the range, so the download starts over
+		offset = 0
+		total = resp.ContentLength
+	}
+
+	if req.MaxSize > 0 && total > req.MaxSize {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrDownloadTooLarge, req.URL, total, req.MaxSize)
+	}
+
+	validator := ""
+	if resp != nil {
+		validator = rangeValidator(resp.Header)
+		if req.begin != nil {
+			if err := req.begin(offset, validator); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		}
+	}
+
+	progress, _ := downloadProgressKey.Get(ctx)
+	state := &downloadState{
+		target:   target,
+		hash:     verifier.hash,
+		total:    total,
+		maxSize:  req.MaxSize,
+		url:      req.URL,
+		progress: progress,
+	}
+	if offset > 0 {
+		state.chunks = append(state.chunks, &downloadChunk{start: 0, end: offset, written: offset})
+		state.written = offset
+	}
+
+	chunkSize := c.config.DownloadChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultDownloadChunkSize
+	}
+	concurrency := c.config.DownloadConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDownloadConcurrency
+	}
+
+	var chunks []*downloadChunk
+	switch {
+	case resp == nil:
+	case ranged && total >= 0 && concurrency > 1 && total-offset > chunkSize:
+		for start := offset; start < total; start += chunkSize {
+			chunks = append(chunks, &downloadChunk{start: start, end: min64(start+chunkSize, total)})
+		}
+	default:
+		chunks = []*downloadChunk{{start: offset, end: total}}
+	}
+	state.chunks = append(state.chunks, chunks...)
+
+	if err := state.advance(); err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	if len(chunks) > 0 {
+		if err := c.downloadChunks(ctx, req, state, chunks, resp.Body, ranged, validator, concurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	size, sum, err := state.finish()
+	if err != nil {
+		return nil, err
+	}
+	if verifier.want != nil && !bytes.Equal(sum, verifier.want) {
+		return nil, fmt.Errorf("%w: %s has checksum %s, expected %s", ErrChecksumMismatch, req.URL, hex.EncodeToString(sum), req.Checksum)
+	}
+
+	return &DownloadResult{
+		URL:      req.URL,
+		Size:     size,
+		Resumed:  offset,
+		Chunks:   max(len(chunks), 1),
+		Checksum: verifier.kind + ":" + hex.EncodeToString(sum),
+	}, nil
+}
+
+// downloadChunks downloads the chunks, concurrency at a time. The first chunk is read
+// from the body of the first response, which is closed once the chunk is complete. The
+// first failure cancels the other chunks and is returned.
+func (c *Client) downloadChunks(ctx context.Context, req DownloadRequest, state *downloadState, chunks []*downloadChunk, body io.ReadCloser, ranged bool, validator string, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for i, chunk := range chunks {
+		var first io.ReadCloser
+		if i == 0 {
+			first = body
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			if first != nil {
+				first.Close()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(chunk *downloadChunk, first io.ReadCloser) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.downloadChunk(ctx, req, state, chunk, first, ranged, validator); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}(chunk, first)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// downloadChunk copies one chunk into the target, resuming it with a range request when
+// reading fails. body is the response to continue from, or nil to request the chunk.
+func (c *Client) downloadChunk(ctx context.Context, req DownloadRequest, state *downloadState, chunk *downloadChunk, body io.ReadCloser, ranged bool, validator string) error {
+	for attempt := 0; ; attempt++ {
+		if body == nil {
+			resp, err := c.openRange(ctx, req, chunk.start+chunk.written, chunk.end, validator)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode != http.StatusPartialContent {
+				resp.Body.Close()
+				return fmt.Errorf("%w: %s no longer serves ranges", ErrDownloadChanged, req.URL)
+			}
+			body = resp.Body
+		}
+
+		readErr, err := state.copy(chunk, body)
+		body.Close()
+		body = nil
+		if err == nil {
+			return nil
+		}
+		if !readErr || !ranged || attempt >= c.config.MaxRetries || ctx.Err() != nil {
+			return fmt.Errorf("failed to download %s: %w", req.URL, timeoutError(err))
+		}
+		c.logger.WithField("url", req.URL).Debugf("Resuming download at byte %d: %v", chunk.start+chunk.written, err)
+	}
+}
+
+// openRange requests the bytes of an artifact from start up to end (exclusive), or to
+// the end of the artifact when end is negative. ifRange, when set, is sent as If-Range.
+func (c *Client) openRange(ctx context.Context, req DownloadRequest, start, end int64, ifRange string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, req.URL, nil)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    req.URL,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	for name, values := range req.Header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if end >= 0 {
+		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+	} else {
+		httpReq.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+	}
+	if ifRange != "" {
+		httpReq.Header.Set("If-Range", ifRange)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    req.URL,
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return resp, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return nil, newAPIError(resp, body)
+	}
+	return resp, nil
+}
+
+// rangeValidator returns the If-Range value for later range requests: a strong ETag,
+// or else the Last-Modified date
+func rangeValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// parseContentRange parses a "bytes start-end/size" or "bytes */size" Content-Range
+// header. end is exclusive; -1 stands for unknown values.
+func parseContentRange(value string) (start, end, size int64, err error) {
+	spec, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	rangePart, sizePart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+
+	size = -1
+	if sizePart != "*" {
+		if size, err = strconv.ParseInt(sizePart, 10, 64); err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+		}
+	}
+	if rangePart == "*" {
+		return -1, -1, size, nil
+	}
+
+	first, last, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	if end, err = strconv.ParseInt(last, 10, 64); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+	}
+	return start, end + 1, size, nil
+}
+
+// downloadChunk is a range of the artifact written sequentially
+type downloadChunk struct {
+	start int64
+
+	// end is exclusive, or -1 when the size of the artifact is unknown
+	end int64
+
+	written int64
+}
+
+// downloadState tracks the chunks written to the target and hashes the artifact in
+// order: bytes written at the hashed position are hashed directly, and chunks completed
+// out of order are read back from the target once the chunks before them are done
+type downloadState struct {
+	mu       sync.Mutex
+	target   DownloadTarget
+	hash     hash.Hash
+	chunks   []*downloadChunk
+	next     int
+	hashed   int64
+	written  int64
+	total    int64
+	maxSize  int64
+	url      string
+	progress func(DownloadProgress)
+}
+
+// copy reads a chunk from body into the target. readErr reports whether err came from
+// reading the body, in which case the chunk can be resumed.
+func (s *downloadState) copy(chunk *downloadChunk, body io.Reader) (readErr bool, err error) {
+	buf := make([]byte, 32<<10)
+	for {
+		p := buf
+		if chunk.end >= 0 {
+			remaining := chunk.end - chunk.start - chunk.written
+			if remaining <= 0 {
+				return false, nil
+			}
+			p = buf[:min64(remaining, int64(len(buf)))]
+		}
+
+		n, err := body.Read(p)
+		if n > 0 {
+			if err := s.write(chunk, p[:n]); err != nil {
+				return false, err
+			}
+		}
+		if err == io.EOF {
+			if chunk.end >= 0 && chunk.start+chunk.written < chunk.end {
+				return true, io.ErrUnexpectedEOF
+			}
+			return false, nil
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+}
+
+// write writes data at the end of a chunk and hashes what became contiguous
+func (s *downloadState) write(chunk *downloadChunk, p []byte) error {
+	offset := chunk.start + chunk.written
+	if _, err := s.target.WriteAt(p, offset); err != nil {
+		return fmt.Errorf("failed to write download: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := int64(len(p))
+	chunk.written += n
+	s.written += n
+	if s.maxSize > 0 && s.written > s.maxSize {
+		return fmt.Errorf("%w: %s is larger than %d bytes", ErrDownloadTooLarge, s.url, s.maxSize)
+	}
+
+	if offset == s.hashed {
+		s.hash.Write(p)
+		s.hashed += n
+	}
+	if err := s.advanceLocked(); err != nil {
+		return err
+	}
+
+	if s.progress != nil {
+		s.progress(DownloadProgress{URL: s.url, Written: s.written, Total: s.total})
+	}
+	return nil
+}
+
+// advance hashes the data that became contiguous
+func (s *downloadState) advance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.advanceLocked()
+}
+
+// advanceLocked hashes written data following the hashed position, reading it back from
+// the target
+func (s *downloadState) advanceLocked() error {
+	for s.next < len(s.chunks) {
+		chunk := s.chunks[s.next]
+		if end := chunk.start + chunk.written; s.hashed < end {
+			section := io.NewSectionReader(s.target, s.hashed, end-s.hashed)
+			if _, err := io.Copy(s.hash, section); err != nil {
+				return fmt.Errorf("failed to read back download: %w", err)
+			}
+			s.hashed = end
+		}
+		if chunk.end < 0 || chunk.start+chunk.written < chunk.end {
+			return nil
+		}
+		s.next++
+	}
+	return nil
+}
+
+// finish returns the size and checksum of the completed download
+func (s *downloadState) finish() (int64, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hashed != s.written || (s.total >= 0 && s.written != s.total) {
+		return 0, nil, fmt.Errorf("failed to download %s: %w", s.url, io.ErrUnexpectedEOF)
+	}
+	return s.written, s.hash.Sum(nil), nil
+}
+
+// min64 returns the smaller of two int64 values
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
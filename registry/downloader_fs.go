@@ -0,0 +1,108 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// partialDownloadSuffix is appended to the path of a download in progress
+	partialDownloadSuffix = ".part"
+
+	// partialValidatorSuffix is appended to the path of a download in progress for the
+	// file holding the validator of the artifact being downloaded
+	partialValidatorSuffix = ".part.validator"
+)
+
+// DownloadFile downloads an artifact to path like Download. Data is written to path
+// with a ".part" suffix, which is renamed to path once the checksum verified. The ETag or
+// Last-Modified date of the artifact is stored next to it in a ".part.validator" file,
+// and a ".part" file left by an interrupted attempt is resumed with it as If-Range, so
+// that an artifact replaced since starts over; without a validator the download starts
+// over too, and req.Offset and req.IfRange are ignored. The partial file is removed
+// when its content turns out to be wrong, so that the next attempt starts over.
+func (c *Client) DownloadFile(ctx context.Context, req DownloadRequest, path string) (*DownloadResult, error) {
+	if path == "" {
+		return nil, &ValidationError{Field: "path", Value: path, Message: "destination path cannot be empty"}
+	}
+
+	part := path + partialDownloadSuffix
+	validatorPath := path + partialValidatorSuffix
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", part, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat %s: %w", part, err)
+	}
+	req.Offset, req.IfRange = 0, ""
+	if validator, err := os.ReadFile(validatorPath); err == nil && len(validator) > 0 {
+		req.Offset, req.IfRange = info.Size(), string(validator)
+	}
+	req.begin = func(offset int64, validator string) error {
+		// A restarted download must not keep bytes of the previous attempt
+		if err := f.Truncate(offset); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", part, err)
+		}
+		if validator == "" {
+			if err := os.Remove(validatorPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", validatorPath, err)
+			}
+			return nil
+		}
+		if err := os.WriteFile(validatorPath, []byte(validator), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", validatorPath, err)
+		}
+		return nil
+	}
+
+	result, err := c.Download(ctx, req, f)
+	if err != nil {
+		f.Close()
+		if errors.Is(err, ErrChecksumMismatch) || errors.Is(err, ErrDownloadChanged) || errors.Is(err, ErrDownloadTooLarge) {
+			os.Remove(part)
+			os.Remove(validatorPath)
+		}
+		return nil, err
+	}
+
+	// A restarted download may be shorter than the partial file it overwrote
+	if err := f.Truncate(result.Size); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to truncate %s: %w", part, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", part, err)
+	}
+	if err := os.Rename(part, path); err != nil {
+		return nil, fmt.Errorf("failed to rename %s: %w", part, err)
+	}
+	os.Remove(validatorPath)
+	return result, nil
+}
+
+// DownloadPackageFile downloads a provider package to path after the same SHA256SUMS
+// checks as DownloadPackage, using DownloadFile: large packages are downloaded in
+// parallel ranges, an interrupted download is resumed by the next call, and path only
+// appears once the package matched the signed checksum.
+func (s *ProvidersService) DownloadPackageFile(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier, path string) (*PackageVerification, error) {
+	listed, keyID, err := s.verifyShasums(ctx, download, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.DownloadFile(ctx, DownloadRequest{URL: download.DownloadURL, Checksum: "sha256:" + listed}, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", download.Filename, err)
+	}
+
+	return &PackageVerification{SHA256: strings.TrimPrefix(result.Checksum, "sha256:"), KeyID: keyID}, nil
+}
@@ -0,0 +1,17 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+import "context"
+
+// DownloadFile reports that downloading to files is unavailable in this build; use
+// Download instead
+func (c *Client) DownloadFile(ctx context.Context, req DownloadRequest, path string) (*DownloadResult, error) {
+	return nil, ErrFilesystemUnsupported
+}
+
+// DownloadPackageFile reports that downloading to files is unavailable in this build;
+// use DownloadPackage instead
+func (s *ProvidersService) DownloadPackageFile(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier, path string) (*PackageVerification, error) {
+	return nil, ErrFilesystemUnsupported
+}
@@ -0,0 +1,230 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrDownloadDenied is returned when the download policy rejects a module or provider
+var ErrDownloadDenied = errors.New("download denied by policy")
+
+// Download policy rules, reported by DownloadDeniedError and DownloadAuditEvent
+const (
+	DownloadRuleDeniedNamespace  = "denied-namespace"
+	DownloadRuleAllowedNamespace = "allowed-namespace"
+	DownloadRuleProviderTier     = "provider-tier"
+	DownloadRuleVerifiedModule   = "verified-module"
+)
+
+// DownloadPolicy restricts which modules and providers may be downloaded. It is checked
+// by every download and archive operation — Modules.Download, OpenArchive,
+// DownloadArchive and SaveArchive, and Providers.GetDownload, whose result the package
+// downloads take — independently of how search and list results are filtered.
+type DownloadPolicy struct {
+	// AllowedNamespaces lists the namespaces downloads may come from, matched
+	// case-insensitively as path.Match patterns such as "acme-*"; empty allows every
+	// namespace
+	AllowedNamespaces []string
+
+	// DeniedNamespaces lists namespaces downloads may never come from, matched like
+	// AllowedNamespaces; a denied namespace is rejected even when it is also allowed
+	DeniedNamespaces []string
+
+	// ProviderTiers lists the tiers provider packages may have (official, partner,
+	// community); empty allows every tier. Checking it looks the provider up.
+	ProviderTiers []string
+
+	// VerifiedModules only allows downloads of verified modules. Checking it looks the
+	// module version up.
+	VerifiedModules bool
+}
+
+// Validate checks the namespace patterns and tiers of the policy
+func (p *DownloadPolicy) Validate() error {
+	for _, patterns := range [][]string{p.AllowedNamespaces, p.DeniedNamespaces} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return &ValidationError{
+					Field:   "namespace",
+					Value:   pattern,
+					Message: "invalid download policy namespace pattern",
+				}
+			}
+		}
+	}
+	for _, tier := range p.ProviderTiers {
+		if !validate.Tier(tier) {
+			return &ValidationError{
+				Field:   "tier",
+				Value:   tier,
+				Message: "tier must be one of: " + strings.Join(validate.Tiers, ", "),
+			}
+		}
+	}
+	return nil
+}
+
+// DownloadAuditEvent records a download rejected by the download policy
+type DownloadAuditEvent struct {
+	Time time.Time
+
+	// Operation is the rejected method, e.g. "Modules.OpenArchive"
+	Operation string
+
+	// Module is set for module downloads and Provider for provider downloads
+	Module   *ModuleRef
+	Provider *ProviderRef
+
+	// Rule is the violated rule, one of the DownloadRule constants
+	Rule string
+
+	// Reason describes the violation
+	Reason string
+}
+
+// DownloadAuditHandler is called for every download the policy rejects. Handlers are
+// called synchronously from the requesting goroutine and should return quickly.
+type DownloadAuditHandler func(DownloadAuditEvent)
+
+// DownloadDeniedError reports a download rejected by the download policy
+type DownloadDeniedError struct {
+	Operation string
+	Ref       string
+	Rule      string
+	Reason    string
+}
+
+// Error implements the error interface
+func (e *DownloadDeniedError) Error() string {
+	return fmt.Sprintf("%s: download of %s denied by policy (%s): %s", e.Operation, e.Ref, e.Rule, e.Reason)
+}
+
+// Unwrap returns ErrDownloadDenied
+func (e *DownloadDeniedError) Unwrap() error {
+	return ErrDownloadDenied
+}
+
+// IsDownloadDenied returns true if the download policy rejected a download
+func IsDownloadDenied(err error) bool {
+	return errors.Is(err, ErrDownloadDenied)
+}
+
+// WithDownloadPolicy enforces a download policy on every download and archive
+// operation; rejected downloads fail with a DownloadDeniedError before anything is
+// downloaded. handler, which may be nil, is notified of every rejection.
+func WithDownloadPolicy(policy DownloadPolicy, handler DownloadAuditHandler) ClientOption {
+	return func(c *ClientConfig) {
+		c.DownloadPolicy = &policy
+		c.OnDownloadDenied = handler
+	}
+}
+
+// checkModuleDownload enforces the download policy on a module version download
+func (s *ModulesService) checkModuleDownload(ctx context.Context, operation string, ref ModuleRef) error {
+	policy := s.client.config.DownloadPolicy
+	if policy == nil {
+		return nil
+	}
+
+	rule, reason := policy.checkNamespace(ref.Namespace)
+	if rule == "" && policy.VerifiedModules {
+		module, err := s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+		if err != nil {
+			return fmt.Errorf("failed to check download policy for %s: %w", ref, err)
+		}
+		if !module.Verified {
+			rule, reason = DownloadRuleVerifiedModule, "module is not verified"
+		}
+	}
+	if rule == "" {
+		return nil
+	}
+
+	return s.client.denyDownload(DownloadAuditEvent{Operation: operation, Module: &ref, Rule: rule, Reason: reason})
+}
+
+// checkProviderDownload enforces the download policy on a provider version download
+func (s *ProvidersService) checkProviderDownload(ctx context.Context, operation string, ref ProviderRef) error {
+	policy := s.client.config.DownloadPolicy
+	if policy == nil {
+		return nil
+	}
+
+	rule, reason := policy.checkNamespace(ref.Namespace)
+	if rule == "" && len(policy.ProviderTiers) > 0 {
+		provider, err := s.Get(ctx, ref.Namespace, ref.Name)
+		if err != nil {
+			return fmt.Errorf("failed to check download policy for %s: %w", ref, err)
+		}
+		tier := provider.Attributes.Tier
+		if !slices.ContainsFunc(policy.ProviderTiers, func(allowed string) bool {
+			return strings.EqualFold(allowed, tier)
+		}) {
+			rule = DownloadRuleProviderTier
+			reason = fmt.Sprintf("tier %q is not one of: %s", tier, strings.Join(policy.ProviderTiers, ", "))
+		}
+	}
+	if rule == "" {
+		return nil
+	}
+
+	return s.client.denyDownload(DownloadAuditEvent{Operation: operation, Provider: &ref, Rule: rule, Reason: reason})
+}
+
+// checkNamespace returns the violated namespace rule and why, or an empty rule when the
+// namespace may be downloaded from
+func (p *DownloadPolicy) checkNamespace(namespace string) (string, string) {
+	if pattern, ok := matchNamespace(p.DeniedNamespaces, namespace); ok {
+		return DownloadRuleDeniedNamespace, fmt.Sprintf("namespace %q matches denied namespace %q", namespace, pattern)
+	}
+	if len(p.AllowedNamespaces) > 0 {
+		if _, ok := matchNamespace(p.AllowedNamespaces, namespace); !ok {
+			return DownloadRuleAllowedNamespace, fmt.Sprintf("namespace %q is not in the allowed namespaces", namespace)
+		}
+	}
+	return "", ""
+}
+
+// denyDownload logs and reports a rejected download and returns its error
+func (c *Client) denyDownload(event DownloadAuditEvent) error {
+	event.Time = time.Now()
+
+	ref := ""
+	if event.Module != nil {
+		ref = event.Module.String()
+	} else if event.Provider != nil {
+		ref = event.Provider.String()
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"operation": event.Operation,
+		"ref":       ref,
+		"rule":      event.Rule,
+	}).Warnf("Download denied by policy: %s", event.Reason)
+
+	if c.config.OnDownloadDenied != nil {
+		c.config.OnDownloadDenied(event)
+	}
+
+	return &DownloadDeniedError{Operation: event.Operation, Ref: ref, Rule: event.Rule, Reason: event.Reason}
+}
+
+// matchNamespace returns the first pattern matching a namespace, ignoring case
+func matchNamespace(patterns []string, namespace string) (string, bool) {
+	namespace = strings.ToLower(namespace)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(pattern), namespace); ok && err == nil {
+			return pattern, true
+		}
+	}
+	return "", false
+}
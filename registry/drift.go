@@ -0,0 +1,289 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/semver"
+)
+
+// DriftKind identifies how a pinned module version drifted from the registry
+type DriftKind string
+
+const (
+	// DriftMissing is reported when the pinned module or version is no longer published
+	DriftMissing DriftKind = "missing"
+
+	// DriftSecurityPatch is reported when a newer patch release of the pinned minor
+	// version fixes a security advisory affecting the pinned version
+	DriftSecurityPatch DriftKind = "security_patch"
+
+	// DriftSourceMoved is reported when the module's source repository differs from the
+	// one recorded with the pin or used by the pinned version
+	DriftSourceMoved DriftKind = "source_moved"
+)
+
+// WarningSkippedAdvisoryCheck is reported when the security advisories of a pinned
+// module cannot be checked, for example because its source is not on GitHub
+const WarningSkippedAdvisoryCheck WarningCode = "skipped_advisory_check"
+
+// patchedVersionRegex finds the version in a patched versions field such as ">= 1.2.4"
+var patchedVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+[0-9A-Za-z.\-+]*`)
+
+// ModulePin is a pinned module version, for example from a lock file or a catalog
+type ModulePin struct {
+	// Module identifies the module; Version is required
+	Module ModuleRef
+
+	// Source is the source repository recorded with the pin; when empty, the source of
+	// the pinned version is compared with the latest version's instead
+	Source string
+}
+
+// ModuleDrift is a drift finding for a pin
+type ModuleDrift struct {
+	Pin  ModulePin
+	Kind DriftKind
+
+	// Replacement is the suggested version: the latest version for a missing version and
+	// the first patch release fixing every advisory for a security patch; empty when
+	// there is none
+	Replacement string
+
+	// Source is the current source repository, for DriftSourceMoved
+	Source string
+
+	// Advisories are the advisories affecting the pinned version, for DriftSecurityPatch
+	Advisories []Advisory
+
+	Message string
+}
+
+// DriftReport lists the drift findings for a set of pins
+type DriftReport struct {
+	// Checked is the number of pins checked successfully
+	Checked int
+
+	// Drifts are ordered like the pins; a pin can drift in several ways
+	Drifts []ModuleDrift
+}
+
+// DetectDrift compares pinned module versions with the registry and reports pins whose
+// module or version no longer exists, pins superseded by a patch release that fixes a
+// security advisory affecting them, and pins whose source repository moved. Advisories
+// are the published security advisories of the module's GitHub repository; pins of
+// modules hosted elsewhere are reported with a WarningSkippedAdvisoryCheck warning.
+// Pins that cannot be checked are reported in the returned MultiError alongside the
+// findings for the others.
+func (s *ModulesService) DetectDrift(ctx context.Context, pins []ModulePin) (*DriftReport, error) {
+	keys := make([]string, 0, len(pins))
+	byKey := make(map[string]ModulePin, len(pins))
+	for _, pin := range pins {
+		ref := pin.Module
+		if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ref.Version); err != nil {
+			return nil, err
+		}
+		if ref.Version == "" || ref.Version == "latest" {
+			return nil, &ValidationError{
+				Field:   "version",
+				Value:   ref.String(),
+				Message: "pins need a specific version",
+			}
+		}
+
+		key := ref.String() + " " + pin.Source
+		byKey[key] = pin
+		keys = append(keys, key)
+	}
+
+	results := runBatch(ctx, keys, DefaultBatchConcurrency, func(ctx context.Context, key string) ([]ModuleDrift, error) {
+		return s.detectPinDrift(ctx, byKey[key])
+	})
+
+	report := &DriftReport{}
+	var errs MultiError
+	for _, key := range keys {
+		result := results[key]
+		if result.Err != nil {
+			errs.Add(fmt.Errorf("failed to check pin %s: %w", byKey[key].Module, result.Err))
+			continue
+		}
+		report.Checked++
+		report.Drifts = append(report.Drifts, result.Value...)
+	}
+
+	return report, errs.ErrorOrNil()
+}
+
+// detectPinDrift checks a single pin
+func (s *ModulesService) detectPinDrift(ctx context.Context, pin ModulePin) ([]ModuleDrift, error) {
+	ref := pin.Module
+
+	versions, err := s.ListVersions(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if IsNotFound(err) {
+		return []ModuleDrift{{
+			Pin:     pin,
+			Kind:    DriftMissing,
+			Message: fmt.Sprintf("module %s/%s/%s is no longer published", ref.Namespace, ref.Name, ref.Provider),
+		}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	latest := ""
+	published := false
+	var patches []string
+	for _, version := range versions {
+		if CompareVersions(version, ref.Version) == 0 {
+			published = true
+		}
+		if semver.IsPrerelease(version) {
+			continue
+		}
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+		if samePatchLine(version, ref.Version) && CompareVersions(version, ref.Version) > 0 {
+			patches = append(patches, version)
+		}
+	}
+
+	if !published {
+		return []ModuleDrift{{
+			Pin:         pin,
+			Kind:        DriftMissing,
+			Replacement: latest,
+			Message:     fmt.Sprintf("version %s of %s is no longer published", ref.Version, ref),
+		}}, nil
+	}
+
+	pinned, err := s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []ModuleDrift
+
+	recorded, current := pin.Source, pinned.Source
+	if recorded == "" && latest != "" && CompareVersions(latest, ref.Version) != 0 {
+		latestDetails, err := s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, latest)
+		if err != nil {
+			return nil, err
+		}
+		recorded, current = pinned.Source, latestDetails.Source
+	}
+	if recorded != "" && current != "" && !sameSourceRepo(recorded, current) {
+		drifts = append(drifts, ModuleDrift{
+			Pin:     pin,
+			Kind:    DriftSourceMoved,
+			Source:  current,
+			Message: fmt.Sprintf("source of %s moved from %s to %s", ref, recorded, current),
+		})
+	}
+
+	if len(patches) > 0 {
+		drift, err := s.securityPatchDrift(ctx, pin, pinned.Source, patches)
+		if err != nil {
+			return nil, err
+		}
+		if drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+
+	return drifts, nil
+}
+
+// securityPatchDrift checks whether newer patch releases fix advisories affecting the
+// pinned version. Lookup failures are reported as warnings.
+func (s *ModulesService) securityPatchDrift(ctx context.Context, pin ModulePin, source string, patches []string) (*ModuleDrift, error) {
+	ref := pin.Module
+
+	u, err := url.Parse(source)
+	segments := sourceRepoPath(source)
+	if err != nil || !strings.EqualFold(u.Host, "github.com") || len(segments) < 2 {
+		warn(ctx, Warning{
+			Code:     WarningSkippedAdvisoryCheck,
+			Resource: ref.String(),
+			Message:  fmt.Sprintf("advisories of %s cannot be checked: source %q is not a GitHub repository", ref, source),
+		})
+		return nil, nil
+	}
+
+	security := &SecurityService{client: s.client}
+	advisories, err := security.listRepositoryAdvisories(ctx, segments[0], segments[1])
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		warn(ctx, Warning{
+			Code:     WarningSkippedAdvisoryCheck,
+			Resource: ref.String(),
+			Message:  fmt.Sprintf("advisories of %s could not be fetched", ref),
+			Err:      err,
+		})
+		return nil, nil
+	}
+
+	drift := &ModuleDrift{Pin: pin, Kind: DriftSecurityPatch}
+	for _, advisory := range advisories {
+		if !advisory.Affects(ref.Version) {
+			continue
+		}
+		fix := firstFixingPatch(advisory, patches)
+		if fix == "" {
+			continue
+		}
+		drift.Advisories = append(drift.Advisories, advisory)
+		if drift.Replacement == "" || CompareVersions(fix, drift.Replacement) > 0 {
+			drift.Replacement = fix
+		}
+	}
+
+	if len(drift.Advisories) == 0 {
+		return nil, nil
+	}
+	drift.Message = fmt.Sprintf("%s is affected by %d advisories fixed in %s", ref, len(drift.Advisories), drift.Replacement)
+	return drift, nil
+}
+
+// firstFixingPatch returns the lowest patch release that the advisory does not affect
+// and that is at least its patched version, or ""
+func firstFixingPatch(advisory Advisory, patches []string) string {
+	patched := patchedVersionRegex.FindString(advisory.PatchedVersion)
+
+	fix := ""
+	for _, patch := range patches {
+		if advisory.Affects(patch) || (patched != "" && CompareVersions(patch, patched) < 0) {
+			continue
+		}
+		if fix == "" || CompareVersions(patch, fix) < 0 {
+			fix = patch
+		}
+	}
+	return fix
+}
+
+// samePatchLine reports whether two versions share their major and minor version
+func samePatchLine(a, b string) bool {
+	va, errA := semver.Parse(a)
+	vb, errB := semver.Parse(b)
+	return errA == nil && errB == nil && va.Major == vb.Major && va.Minor == vb.Minor
+}
+
+// sameSourceRepo reports whether two source URLs point to the same repository,
+// ignoring the scheme, case and a .git suffix
+func sameSourceRepo(a, b string) bool {
+	normalize := func(source string) string {
+		u, err := url.Parse(source)
+		if err != nil {
+			return strings.ToLower(source)
+		}
+		return strings.ToLower(u.Host + "/" + strings.Join(sourceRepoPath(source), "/"))
+	}
+	return normalize(a) == normalize(b)
+}
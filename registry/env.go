@@ -0,0 +1,100 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	// EnvRegistryAddr is the registry base URL
+	EnvRegistryAddr = "TF_REGISTRY_ADDR"
+
+	// EnvRegistryClientTimeout is the HTTP client timeout in seconds, as used by Terraform
+	EnvRegistryClientTimeout = "TF_REGISTRY_CLIENT_TIMEOUT"
+
+	// EnvRegistryRateLimit is the number of requests allowed per rate limit period
+	EnvRegistryRateLimit = "TF_REGISTRY_RATE_LIMIT"
+
+	// EnvRegistryRatePeriod is the rate limit period as a Go duration, e.g. "1m"
+	EnvRegistryRatePeriod = "TF_REGISTRY_RATE_PERIOD"
+
+	// EnvTokenPrefix prefixes Terraform-style host-keyed API tokens, e.g. TF_TOKEN_app_terraform_io
+	EnvTokenPrefix = "TF_TOKEN_"
+)
+
+// NewClientFromEnv creates a client configured from environment variables:
+// TF_REGISTRY_ADDR, TF_TOKEN_<host>, TF_REGISTRY_CLIENT_TIMEOUT, TF_REGISTRY_RATE_LIMIT
+// and TF_REGISTRY_RATE_PERIOD. HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honored by the
+// default HTTP client. Options passed in are applied after the environment and win.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	envOpts, err := optionsFromEnv(os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfiguration, err)
+	}
+
+	return NewClient(append(envOpts, opts...)...)
+}
+
+// optionsFromEnv builds client options from environment variables read with getenv
+func optionsFromEnv(getenv func(string) string) ([]ClientOption, error) {
+	var opts []ClientOption
+
+	baseURL := DefaultBaseURL
+	if addr := getenv(EnvRegistryAddr); addr != "" {
+		baseURL = strings.TrimSuffix(addr, "/")
+		opts = append(opts, WithBaseURL(baseURL))
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid URL: %v", EnvRegistryAddr, err)
+	}
+	if token := getenv(TokenEnvName(u.Hostname())); token != "" {
+		opts = append(opts, WithAPIToken(token))
+	}
+
+	if value := getenv(EnvRegistryClientTimeout); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("%s: must be a positive number of seconds, got %q", EnvRegistryClientTimeout, value)
+		}
+		opts = append(opts, WithTimeout(time.Duration(seconds)*time.Second))
+	}
+
+	rateLimit := getenv(EnvRegistryRateLimit)
+	ratePeriod := getenv(EnvRegistryRatePeriod)
+	if rateLimit != "" || ratePeriod != "" {
+		defaults := DefaultClientConfig()
+		requests, period := defaults.RateLimitRequests, defaults.RateLimitPeriod
+
+		if rateLimit != "" {
+			requests, err = strconv.Atoi(rateLimit)
+			if err != nil || requests <= 0 {
+				return nil, fmt.Errorf("%s: must be a positive integer, got %q", EnvRegistryRateLimit, rateLimit)
+			}
+		}
+		if ratePeriod != "" {
+			period, err = time.ParseDuration(ratePeriod)
+			if err != nil || period <= 0 {
+				return nil, fmt.Errorf("%s: must be a positive duration, got %q", EnvRegistryRatePeriod, ratePeriod)
+			}
+		}
+
+		opts = append(opts, WithRateLimit(requests, period))
+	}
+
+	return opts, nil
+}
+
+// TokenEnvName returns the Terraform-style token variable name for a host: dots become
+// underscores and hyphens become double underscores, e.g. TF_TOKEN_app_terraform_io
+func TokenEnvName(host string) string {
+	name := strings.ReplaceAll(host, "-", "__")
+	name = strings.ReplaceAll(name, ".", "_")
+	return EnvTokenPrefix + name
+}
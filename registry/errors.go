@@ -1,9 +1,12 @@
 package registry
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 )
 
 // Common errors
@@ -28,6 +31,16 @@ var (
 
 	// ErrServerError is returned for server-side errors
 	ErrServerError = errors.New("server error")
+
+	// ErrNoVersions is returned when a registry lists no versions for a module or provider
+	ErrNoVersions = errors.New("no versions found")
+
+	// ErrUnsupportedByBackend is returned for features the registry backend does not offer,
+	// such as listing all providers on the OpenTofu registry
+	ErrUnsupportedByBackend = errors.New("not supported by the registry backend")
+
+	// ErrFilesystemUnsupported is returned by filesystem features in WASM and TinyGo builds
+	ErrFilesystemUnsupported = errors.New("filesystem access is not supported in this build")
 )
 
 // APIError represents an error returned by the Terraform Registry API
@@ -200,6 +213,36 @@ func IsTimeout(err error) bool {
 	return errors.Is(err, ErrTimeout)
 }
 
+// timeoutError marks context deadline and network timeout errors with ErrTimeout so
+// that IsTimeout matches them. The original error stays in the chain, so
+// errors.Is(err, context.DeadlineExceeded) keeps working.
+func timeoutError(err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return err
+}
+
+// unwrapURLError drops the *url.Error added by http.Client, whose method and URL the
+// wrapping RequestError already reports
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// IsSuperseded returns true if the request was cancelled in favor of higher-priority work
+func IsSuperseded(err error) bool {
+	return errors.Is(err, ErrSuperseded)
+}
+
 // IsValidationError returns true if the error is a validation error
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrInvalidInput)
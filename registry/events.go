@@ -0,0 +1,85 @@
+package registry
+
+import (
+	"net/http"
+	"time"
+)
+
+// WaitReason describes why a request is being delayed
+type WaitReason string
+
+const (
+	// WaitRateLimit is a delay imposed by the client-side rate limiter
+	WaitRateLimit WaitReason = "rate_limit"
+
+	// WaitRateLimitReset is a delay until the registry's rate limit window resets after a 429
+	WaitRateLimitReset WaitReason = "rate_limit_reset"
+
+	// WaitRetry is a backoff delay before retrying a failed request
+	WaitRetry WaitReason = "retry"
+)
+
+// WaitEvent describes a delay before a request is sent or retried
+type WaitEvent struct {
+	// Reason is why the request is waiting
+	Reason WaitReason
+
+	// Attempt is the retry attempt about to be made, starting at 1; zero for rate limiter waits
+	Attempt int
+
+	// MaxRetries is the configured maximum number of retries
+	MaxRetries int
+
+	// Wait is the expected duration of the delay
+	Wait time.Duration
+
+	// Method and URL identify the request, when known
+	Method string
+	URL    string
+
+	// StatusCode is the status of the response that triggered a retry; zero for network errors
+	StatusCode int
+}
+
+// WaitHandler is called when a request is delayed. Handlers are called synchronously
+// from the requesting goroutine and should return quickly.
+type WaitHandler func(WaitEvent)
+
+// WithWaitHandler registers a handler notified of rate limit and retry delays, so that
+// callers can report progress instead of appearing frozen. Retry events are only
+// emitted by the default HTTP client.
+func WithWaitHandler(handler WaitHandler) ClientOption {
+	return func(c *ClientConfig) {
+		c.OnWait = handler
+	}
+}
+
+// notifyWait calls the configured wait handler, if any
+func (config *ClientConfig) notifyWait(event WaitEvent) {
+	if config.OnWait != nil {
+		config.OnWait(event)
+	}
+}
+
+// retryWaitEvent builds the event for a retry backoff computed by the HTTP client
+func retryWaitEvent(config *ClientConfig, attemptNum int, resp *http.Response, wait time.Duration) WaitEvent {
+	event := WaitEvent{
+		Reason:     WaitRetry,
+		Attempt:    attemptNum + 1,
+		MaxRetries: config.MaxRetries,
+		Wait:       wait,
+	}
+
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+		if resp.StatusCode == http.StatusTooManyRequests {
+			event.Reason = WaitRateLimitReset
+		}
+		if resp.Request != nil {
+			event.Method = resp.Request.Method
+			event.URL = resp.Request.URL.String()
+		}
+	}
+
+	return event
+}
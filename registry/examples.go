@@ -0,0 +1,202 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExampleOptions controls ExtractTerraformExamplesWithOptions
+type ExampleOptions struct {
+	// Validate parses each example with the HCL parser and fills in its blocks and
+	// provider requirements
+	Validate bool
+
+	// ValidOnly leaves out examples that do not parse; it implies Validate
+	ValidOnly bool
+}
+
+// TerraformExample is a Terraform snippet extracted from documentation
+type TerraformExample struct {
+	Code string
+
+	// Validated reports whether the example was parsed; the fields below are only set
+	// for validated examples
+	Validated bool
+
+	// Valid reports whether the example parses as HCL without errors
+	Valid bool
+
+	// Diagnostics holds the parse errors of an invalid example
+	Diagnostics []string
+
+	// Errors holds the same errors with their positions in Code
+	Errors []HCLDiagnostic
+
+	// Resources and DataSources are the addresses of the resource and data blocks, e.g.
+	// "aws_instance.web" and "aws_ami.ubuntu"
+	Resources   []string
+	DataSources []string
+
+	// Modules are the names of the module blocks
+	Modules []string
+
+	// Providers are the providers the example requires, sorted by name
+	Providers []ExampleProvider
+}
+
+// ExampleProvider is a provider required by an example
+type ExampleProvider struct {
+	// Name is the local name, e.g. "aws"
+	Name string
+
+	// Source is the provider source address; when the example does not declare it, it is
+	// "hashicorp/<name>" as Terraform assumes
+	Source string
+
+	// Version is the declared version constraint, if any
+	Version string
+
+	// Declared reports whether the provider is listed in a required_providers block;
+	// other providers are implied by provider blocks or resource type prefixes
+	Declared bool
+}
+
+// ExtractTerraformExamplesWithOptions extracts the same snippets as
+// ExtractTerraformExamples and, when validation is requested, parses each of them with
+// the HCL parser so that callers can filter to usable examples
+func ExtractTerraformExamplesWithOptions(content string, opts *ExampleOptions) []TerraformExample {
+	if opts == nil {
+		opts = &ExampleOptions{}
+	}
+
+	var examples []TerraformExample
+	for _, code := range ExtractTerraformExamples(content) {
+		if !opts.Validate && !opts.ValidOnly {
+			examples = append(examples, TerraformExample{Code: code})
+			continue
+		}
+
+		example := ParseTerraformExample(code)
+		if opts.ValidOnly && !example.Valid {
+			continue
+		}
+		examples = append(examples, example)
+	}
+
+	return examples
+}
+
+// ParseTerraformExample parses a Terraform snippet with the HCL parser, returning its
+// resource, data and module blocks and the providers it requires. Blocks are read from
+// the parts that parse even when the snippet as a whole is invalid.
+func ParseTerraformExample(code string) TerraformExample {
+	example := TerraformExample{Code: code, Validated: true}
+
+	file, diags := hclsyntax.ParseConfig([]byte(code), "example.tf", hcl.InitialPos)
+	example.Valid = !diags.HasErrors()
+	for _, diag := range diags {
+		if diag.Severity == hcl.DiagError {
+			example.Diagnostics = append(example.Diagnostics, diag.Error())
+		}
+	}
+	example.Errors = hclErrors(diags)
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return example
+	}
+
+	providers := make(map[string]*ExampleProvider)
+	require := func(name string) *ExampleProvider {
+		if providers[name] == nil {
+			providers[name] = &ExampleProvider{Name: name, Source: "hashicorp/" + name}
+		}
+		return providers[name]
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "resource", "data":
+			if len(block.Labels) != 2 {
+				continue
+			}
+			address := block.Labels[0] + "." + block.Labels[1]
+			if block.Type == "resource" {
+				example.Resources = append(example.Resources, address)
+			} else {
+				example.DataSources = append(example.DataSources, address)
+			}
+			if name := providerFromBlock(block); name != "" {
+				require(name)
+			}
+		case "module":
+			if len(block.Labels) == 1 {
+				example.Modules = append(example.Modules, block.Labels[0])
+			}
+		case "provider":
+			if len(block.Labels) == 1 {
+				require(block.Labels[0])
+			}
+		case "terraform":
+			for _, inner := range block.Body.Blocks {
+				if inner.Type != "required_providers" {
+					continue
+				}
+				for name, attr := range inner.Body.Attributes {
+					provider := require(name)
+					provider.Declared = true
+					readProviderRequirement(attr.Expr, provider)
+				}
+			}
+		}
+	}
+
+	for _, provider := range providers {
+		example.Providers = append(example.Providers, *provider)
+	}
+	sort.Slice(example.Providers, func(i, j int) bool {
+		return example.Providers[i].Name < example.Providers[j].Name
+	})
+
+	return example
+}
+
+// providerFromBlock returns the local name of the provider of a resource or data block:
+// the provider meta-argument when set, otherwise the prefix of the resource type
+func providerFromBlock(block *hclsyntax.Block) string {
+	if attr, ok := block.Body.Attributes["provider"]; ok {
+		if traversal, diags := hcl.AbsTraversalForExpr(attr.Expr); !diags.HasErrors() && len(traversal) > 0 {
+			return traversal.RootName()
+		}
+	}
+	name, _, _ := strings.Cut(block.Labels[0], "_")
+	return name
+}
+
+// readProviderRequirement reads a required_providers entry, either an object with
+// source and version or a legacy version string
+func readProviderRequirement(expr hclsyntax.Expression, provider *ExampleProvider) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return
+	}
+
+	if value.Type() == cty.String {
+		provider.Version = value.AsString()
+		return
+	}
+	if !value.Type().IsObjectType() {
+		return
+	}
+	for attr, field := range map[string]*string{"source": &provider.Source, "version": &provider.Version} {
+		if value.Type().HasAttribute(attr) {
+			if v := value.GetAttr(attr); v.Type() == cty.String && !v.IsNull() {
+				*field = v.AsString()
+			}
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+)
+
+// ExportManifestFile is the name of the index manifest written by exports
+const ExportManifestFile = "index.json"
+
+// DocExportOptions specifies optional parameters for ExportDocs
+type DocExportOptions struct {
+	// Categories limits the export to these doc categories; all categories when empty
+	Categories []string
+
+	// Concurrency is the number of docs fetched at once; 0 means DefaultBatchConcurrency
+	Concurrency int
+}
+
+// Validate validates the doc export options
+func (o *DocExportOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	for _, category := range o.Categories {
+		if !validate.DocCategory(category) {
+			return &ValidationError{
+				Field:   "Categories",
+				Value:   category,
+				Message: "invalid category, must be one of: " + strings.Join(validate.DocCategories, ", "),
+			}
+		}
+	}
+
+	if o.Concurrency < 0 {
+		return &ValidationError{
+			Field:   "Concurrency",
+			Value:   o.Concurrency,
+			Message: "concurrency cannot be negative",
+		}
+	}
+
+	return nil
+}
+
+// DocExportManifest describes an exported provider doc bundle
+type DocExportManifest struct {
+	Provider          ProviderRef   `json:"provider"`
+	ProviderVersionID string        `json:"provider_version_id"`
+	ExportedAt        time.Time     `json:"exported_at"`
+	Docs              []ExportedDoc `json:"docs"`
+}
+
+// ExportedDoc is a doc entry of an export manifest
+type ExportedDoc struct {
+	ID          string `json:"id"`
+	Category    string `json:"category"`
+	Subcategory string `json:"subcategory,omitempty"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+
+	// File is the markdown file path relative to the export directory
+	File string `json:"file"`
+
+	// Truncated is set when the registry returned truncated content
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ExportDocs writes every doc of a provider version to dir as markdown files laid out as
+// <category>/<subcategory>/<slug>.md, plus an index.json manifest, for offline use.
+// Docs that fail to download are left out of the manifest and reported in the returned
+// error alongside the partial manifest.
+func (s *ProvidersService) ExportDocs(ctx context.Context, ref ProviderRef, dir string, opts *DocExportOptions) (*DocExportManifest, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return nil, &ValidationError{
+			Field:   "dir",
+			Message: "export directory cannot be empty",
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &DocExportOptions{}
+	}
+
+	if err := prepareExportDir(dir); err != nil {
+		return nil, err
+	}
+
+	version, versionID, err := s.resolveVersion(ctx, ref.Namespace, ref.Name, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+	ref.Version = version
+
+	var docs []ProviderDocData
+	if len(opts.Categories) == 0 {
+		docs, err = s.listDocData(ctx, versionID, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list docs: %w", err)
+		}
+	} else {
+		for _, category := range opts.Categories {
+			categoryDocs, err := s.listDocData(ctx, versionID, category)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s docs: %w", category, err)
+			}
+			docs = append(docs, categoryDocs...)
+		}
+	}
+
+	entries := make(map[string]ExportedDoc, len(docs))
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		attrs := doc.Attributes
+		entries[doc.ID] = ExportedDoc{
+			ID:          doc.ID,
+			Category:    attrs.Category,
+			Subcategory: attrs.Subcategory,
+			Slug:        attrs.Slug,
+			Title:       attrs.Title,
+			File:        docExportPath(attrs),
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	results := runBatch(ctx, ids, opts.Concurrency, func(ctx context.Context, id string) (bool, error) {
+		doc, err := s.GetDoc(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		if err := writeExportFile(dir, entries[id].File, []byte(doc.Data.Attributes.Content)); err != nil {
+			return false, err
+		}
+		return doc.Data.Attributes.Truncated, nil
+	})
+
+	manifest := &DocExportManifest{
+		Provider:          ref,
+		ProviderVersionID: versionID,
+		ExportedAt:        time.Now().UTC(),
+		Docs:              make([]ExportedDoc, 0, len(ids)),
+	}
+
+	var errs MultiError
+	for _, id := range ids {
+		result := results[id]
+		if result.Err != nil {
+			errs.Add(fmt.Errorf("doc %s (%s): %w", id, entries[id].File, result.Err))
+			continue
+		}
+
+		entry := entries[id]
+		entry.Truncated = result.Value
+		manifest.Docs = append(manifest.Docs, entry)
+	}
+
+	sort.Slice(manifest.Docs, func(i, j int) bool {
+		return manifest.Docs[i].File < manifest.Docs[j].File
+	})
+
+	if err := writeExportManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, errs.ErrorOrNil()
+}
+
+// docExportPath returns the relative markdown path of a doc
+func docExportPath(attrs DocAttributes) string {
+	subcategory := exportPathSegment(strings.ToLower(attrs.Subcategory))
+	if subcategory == "" {
+		subcategory = "other"
+	}
+
+	category := exportPathSegment(attrs.Category)
+	if category == "" {
+		category = "other"
+	}
+
+	slug := exportPathSegment(attrs.Slug)
+	if slug == "" {
+		slug = "index"
+	}
+
+	return filepath.ToSlash(filepath.Join(category, subcategory, slug+".md"))
+}
+
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// exportPathSegment makes a value safe to use as a single path segment
+func exportPathSegment(value string) string {
+	segment := unsafePathChars.ReplaceAllString(value, "-")
+	segment = strings.Trim(segment, "-.")
+	return segment
+}
+
+// writeExportManifest writes an index manifest to dir
+func writeExportManifest(dir string, manifest interface{}) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return writeExportFile(dir, ExportManifestFile, data)
+}
+
+// ModuleExportManifest describes an exported module bundle
+type ModuleExportManifest struct {
+	Module     ModuleRef `json:"module"`
+	Source     string    `json:"source,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	// Files lists the written files relative to the export directory
+	Files []string `json:"files"`
+}
+
+// Export writes a module version to dir for archival and offline review: the full
+// metadata as module.json, the root README, and a directory per submodule and example
+// holding its README, its metadata and any Terraform code found in the README.
+// An empty ref.Version exports the latest version.
+func (s *ModulesService) Export(ctx context.Context, ref ModuleRef, dir string) (*ModuleExportManifest, error) {
+	if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ""); err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return nil, &ValidationError{
+			Field:   "dir",
+			Message: "export directory cannot be empty",
+		}
+	}
+
+	if err := prepareExportDir(dir); err != nil {
+		return nil, err
+	}
+
+	var (
+		details *ModuleDetails
+		err     error
+	)
+	if ref.Version == "" || ref.Version == "latest" {
+		details, err = s.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	} else {
+		details, err = s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ref.Version = details.Version
+
+	manifest := &ModuleExportManifest{
+		Module:     ref,
+		Source:     details.Source,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	write := func(relative string, data []byte) error {
+		if err := writeExportFile(dir, relative, data); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, relative)
+		return nil
+	}
+
+	metadata, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode module metadata: %w", err)
+	}
+	if err := write("module.json", metadata); err != nil {
+		return nil, err
+	}
+
+	if details.Root.Readme != "" {
+		if err := write("README.md", []byte(details.Root.Readme)); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := make([]ModulePart, 0, len(details.Submodules)+len(details.Examples))
+	parts = append(parts, details.Submodules...)
+	parts = append(parts, details.Examples...)
+
+	for _, part := range parts {
+		if err := exportModulePart(part, write); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeExportManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// exportModulePart writes a submodule or example under its registry path
+func exportModulePart(part ModulePart, write func(relative string, data []byte) error) error {
+	base := modulePartExportDir(part.Path)
+	if base == "" {
+		return nil
+	}
+
+	metadata, err := json.MarshalIndent(part, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s metadata: %w", part.Path, err)
+	}
+	if err := write(base+"/part.json", metadata); err != nil {
+		return err
+	}
+
+	if part.Readme == "" {
+		return nil
+	}
+	if err := write(base+"/README.md", []byte(part.Readme)); err != nil {
+		return err
+	}
+
+	if examples := ExtractTerraformExamples(part.Readme); len(examples) > 0 {
+		code := strings.Join(examples, "\n\n") + "\n"
+		if err := write(base+"/main.tf", []byte(code)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// modulePartExportDir sanitizes a module part path such as "examples/complete" into a
+// relative directory that cannot escape the export directory
+func modulePartExportDir(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(filepath.ToSlash(path), "/") {
+		if segment = exportPathSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,29 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// prepareExportDir creates the export directory
+func prepareExportDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return nil
+}
+
+// writeExportFile writes data to a path relative to dir, creating parent directories
+func writeExportFile(dir, relative string, data []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(relative))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", relative, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", relative, err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+// prepareExportDir reports that exports are unavailable in this build
+func prepareExportDir(dir string) error {
+	return ErrFilesystemUnsupported
+}
+
+// writeExportFile reports that exports are unavailable in this build
+func writeExportFile(dir, relative string, data []byte) error {
+	return ErrFilesystemUnsupported
+}
@@ -0,0 +1,325 @@
+package registry
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// maxFingerprintURLs caps the distinct URLs remembered per fingerprint; URLs beyond it
+// are counted as unique
+const maxFingerprintURLs = 10000
+
+// FingerprintValueParams lists the query parameters whose values are kept in request
+// fingerprints. They have few values that distinguish call patterns; the values of
+// other parameters are dropped.
+var FingerprintValueParams = []string{"filter[category]", "filter[language]", "include", "filter[tier]"}
+
+// WithRequestFingerprints records a normalized fingerprint of every API request, its
+// path template and parameter class, so that FingerprintReport can show which call
+// patterns dominate usage and which would benefit from caching
+func WithRequestFingerprints() ClientOption {
+	return func(c *ClientConfig) {
+		c.RequestFingerprints = true
+	}
+}
+
+// FingerprintStats aggregates the requests sharing a fingerprint
+type FingerprintStats struct {
+	// Fingerprint is "METHOD version path-template[?params-class]", e.g.
+	// "GET v1 modules/{namespace}/{name}/{provider}/{version}"
+	Fingerprint string
+
+	Method  string
+	Version string
+
+	// PathTemplate is the request path with identifiers replaced by placeholders
+	PathTemplate string
+
+	// Params is the sorted query parameter names, with the values of
+	// FingerprintValueParams
+	Params string
+
+	// Count is the number of requests
+	Count int
+
+	// Unique is the number of distinct URLs requested; Count - Unique requests
+	// repeated an earlier one
+	Unique int
+
+	// Cached is the number of requests served from the response or negative cache
+	Cached int
+
+	// Errors is the number of failed requests, of which RateLimited were rejected
+	// with 429 Too Many Requests
+	Errors      int
+	RateLimited int
+
+	// Duration is the total time spent, including rate limit waits
+	Duration time.Duration
+
+	// Share is the fraction of all recorded requests
+	Share float64
+}
+
+// Repeated returns the number of requests for a URL requested before
+func (s FingerprintStats) Repeated() int {
+	return s.Count - s.Unique
+}
+
+// AverageDuration returns the mean time per request
+func (s FingerprintStats) AverageDuration() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Count)
+}
+
+// FingerprintReport lists the most frequent request fingerprints
+type FingerprintReport struct {
+	// Since is when recording started or was last reset
+	Since time.Time
+
+	// Total is the number of recorded requests, and Distinct the number of fingerprints
+	Total    int
+	Distinct int
+
+	// Fingerprints holds the top fingerprints by count, most frequent first
+	Fingerprints []FingerprintStats
+}
+
+// String renders the report as a table
+func (r *FingerprintReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d requests in %d patterns since %s\n", r.Total, r.Distinct, r.Since.Format(time.RFC3339))
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COUNT\tSHARE\tREPEATED\tCACHED\tERRORS\t429\tAVG\tFINGERPRINT")
+	for _, stats := range r.Fingerprints {
+		fmt.Fprintf(w, "%d\t%.1f%%\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			stats.Count, stats.Share*100, stats.Repeated(), stats.Cached, stats.Errors, stats.RateLimited,
+			stats.AverageDuration().Round(time.Millisecond), stats.Fingerprint)
+	}
+	w.Flush()
+
+	return b.String()
+}
+
+// FingerprintReport returns the n most frequent request fingerprints, or all of them
+// when n is not positive. It returns nil unless fingerprints are recorded with
+// WithRequestFingerprints.
+func (c *Client) FingerprintReport(n int) *FingerprintReport {
+	if c.fingerprints == nil {
+		return nil
+	}
+	return c.fingerprints.report(n)
+}
+
+// ResetFingerprints clears the recorded request fingerprints
+func (c *Client) ResetFingerprints() {
+	if c.fingerprints != nil {
+		c.fingerprints.reset()
+	}
+}
+
+// recordFingerprint records a completed API request when fingerprinting is enabled
+func (c *Client) recordFingerprint(method, version, path string, start time.Time, cached bool, err error) {
+	if c.fingerprints == nil {
+		return
+	}
+	c.fingerprints.record(method, version, path, time.Since(start), cached, err)
+}
+
+// fingerprintRecorder aggregates request fingerprints
+type fingerprintRecorder struct {
+	mu    sync.Mutex
+	since time.Time
+	total int
+	stats map[string]*fingerprintEntry
+}
+
+// fingerprintEntry is the aggregate of one fingerprint with the hashes of its URLs
+type fingerprintEntry struct {
+	FingerprintStats
+	urls map[uint64]struct{}
+}
+
+// newFingerprintRecorder creates an empty recorder
+func newFingerprintRecorder() *fingerprintRecorder {
+	return &fingerprintRecorder{since: time.Now(), stats: make(map[string]*fingerprintEntry)}
+}
+
+// record adds a request to the aggregate of its fingerprint
+func (r *fingerprintRecorder) record(method, version, path string, duration time.Duration, cached bool, err error) {
+	rawPath, rawQuery, _ := strings.Cut(path, "?")
+	template := pathTemplate(rawPath)
+	params := paramsClass(rawQuery)
+
+	fingerprint := method + " " + version + " " + template
+	if params != "" {
+		fingerprint += "?" + params
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(method + " " + version + "/" + path))
+	urlHash := h.Sum64()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.stats[fingerprint]
+	if !ok {
+		entry = &fingerprintEntry{
+			FingerprintStats: FingerprintStats{
+				Fingerprint:  fingerprint,
+				Method:       method,
+				Version:      version,
+				PathTemplate: template,
+				Params:       params,
+			},
+			urls: make(map[uint64]struct{}),
+		}
+		r.stats[fingerprint] = entry
+	}
+
+	r.total++
+	entry.Count++
+	entry.Duration += duration
+	if _, seen := entry.urls[urlHash]; !seen {
+		entry.Unique++
+		if len(entry.urls) < maxFingerprintURLs {
+			entry.urls[urlHash] = struct{}{}
+		}
+	}
+	if cached {
+		entry.Cached++
+	}
+	if err != nil {
+		entry.Errors++
+		if IsRateLimited(err) {
+			entry.RateLimited++
+		}
+	}
+}
+
+// report returns the top n fingerprints
+func (r *fingerprintRecorder) report(n int) *FingerprintReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &FingerprintReport{Since: r.since, Total: r.total, Distinct: len(r.stats)}
+	for _, entry := range r.stats {
+		stats := entry.FingerprintStats
+		stats.Share = float64(stats.Count) / float64(r.total)
+		report.Fingerprints = append(report.Fingerprints, stats)
+	}
+
+	sort.Slice(report.Fingerprints, func(i, j int) bool {
+		a, b := report.Fingerprints[i], report.Fingerprints[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Fingerprint < b.Fingerprint
+	})
+	if n > 0 && len(report.Fingerprints) > n {
+		report.Fingerprints = report.Fingerprints[:n]
+	}
+
+	return report
+}
+
+// reset clears the recorded fingerprints
+func (r *fingerprintRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.since = time.Now()
+	r.total = 0
+	r.stats = make(map[string]*fingerprintEntry)
+}
+
+// fingerprintStaticSegments are the path segments of registry API routes that are not
+// identifiers
+var fingerprintStaticSegments = map[string]bool{
+	"modules": true, "providers": true, "policies": true, "search": true, "versions": true,
+	"download": true, "downloads": true, "summary": true, "latest": true, "provider-docs": true,
+	"provider-versions": true, "policy-library": true, "namespaces": true, "categories": true,
+	"module-versions": true, "policy-versions": true, "policy-modules": true,
+}
+
+// fingerprintParamNames names the identifiers of the routes below a root segment, by
+// their position among the identifiers
+var fingerprintParamNames = map[string][]string{
+	"modules":   {"namespace", "name", "provider", "version"},
+	"providers": {"namespace", "name", "version", "os", "arch"},
+	"policies":  {"namespace", "name", "version"},
+}
+
+var (
+	fingerprintVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+].*)?$`)
+	fingerprintNumberPattern  = regexp.MustCompile(`^\d+$`)
+)
+
+// pathTemplate replaces the identifiers of a request path with placeholders, e.g.
+// "modules/hashicorp/consul/aws/0.1.0" becomes "modules/{namespace}/{name}/{provider}/{version}"
+func pathTemplate(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	names := fingerprintParamNames[segments[0]]
+
+	dynamic := 0
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		if fingerprintStaticSegments[segment] {
+			continue
+		}
+
+		switch {
+		case dynamic < len(names):
+			segments[i] = "{" + names[dynamic] + "}"
+		case fingerprintVersionPattern.MatchString(segment):
+			segments[i] = "{version}"
+		case fingerprintNumberPattern.MatchString(segment):
+			segments[i] = "{id}"
+		default:
+			segments[i] = "{param}"
+		}
+		dynamic++
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// paramsClass returns the sorted query parameter names of a request, with the values of
+// FingerprintValueParams
+func paramsClass(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "{invalid}"
+	}
+
+	params := make([]string, 0, len(values))
+	for name, vals := range values {
+		param := name
+		for _, kept := range FingerprintValueParams {
+			if name == kept {
+				param += "=" + strings.Join(vals, ",")
+				break
+			}
+		}
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	return strings.Join(params, "&")
+}
@@ -0,0 +1,299 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FunctionParameter describes a parameter of a provider-defined function
+type FunctionParameter struct {
+	Name string
+
+	// Type is the documented type, e.g. "string" or "list of string"
+	Type string
+
+	Description string
+
+	// Variadic reports whether the parameter accepts any number of trailing arguments
+	Variadic bool
+}
+
+// ProviderFunction is the signature of a provider-defined function (Terraform 1.8+)
+// parsed from its documentation
+type ProviderFunction struct {
+	// Name is the function name; it is called as provider::<provider>::<name>
+	Name string
+
+	// Summary is the first paragraph of the doc
+	Summary string
+
+	Parameters []FunctionParameter
+
+	// ReturnType is the documented return type; empty when the docs do not state it
+	ReturnType string
+
+	DocID string
+}
+
+// Signature returns the function signature in "name(param type, ...) return" form
+func (f *ProviderFunction) Signature() string {
+	params := make([]string, 0, len(f.Parameters))
+	for _, param := range f.Parameters {
+		name := param.Name
+		if param.Variadic {
+			name = "..." + name
+		}
+		params = append(params, strings.TrimSpace(name+" "+param.Type))
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s(%s) %s", f.Name, strings.Join(params, ", "), f.ReturnType))
+}
+
+// ListFunctions returns the provider-defined functions of a provider version, sorted by
+// name, with signatures parsed from their docs. This needs one request per function.
+// Docs that cannot be fetched are left out and reported in the returned MultiError
+// alongside the remaining functions.
+func (s *ProvidersService) ListFunctions(ctx context.Context, providerVersionID string) ([]ProviderFunction, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Value:   providerVersionID,
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	docs, err := s.listDocData(ctx, providerVersionID, "functions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list functions: %w", err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	details := runBatch(ctx, ids, DefaultBatchConcurrency, s.GetDoc)
+
+	var (
+		functions []ProviderFunction
+		errs      MultiError
+	)
+	for _, id := range ids {
+		result := details[id]
+		if result.Err != nil {
+			errs.Add(result.Err)
+			continue
+		}
+		functions = append(functions, newProviderFunction(result.Value.Data))
+	}
+
+	sort.Slice(functions, func(i, j int) bool {
+		return functions[i].Name < functions[j].Name
+	})
+
+	return functions, errs.ErrorOrNil()
+}
+
+// GetFunctionDoc returns the signature of a provider-defined function parsed from its doc
+func (s *ProvidersService) GetFunctionDoc(ctx context.Context, docID string) (*ProviderFunction, error) {
+	doc, err := s.GetDoc(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Data.Attributes.Category != "functions" {
+		return nil, &ValidationError{
+			Field:   "docID",
+			Value:   docID,
+			Message: fmt.Sprintf("doc is in category %q, not functions", doc.Data.Attributes.Category),
+		}
+	}
+
+	function := newProviderFunction(doc.Data)
+	return &function, nil
+}
+
+// newProviderFunction parses the signature of a function doc, falling back to the doc
+// slug for the name
+func newProviderFunction(doc ProviderDocData) ProviderFunction {
+	function := ParseFunctionDoc(doc.Attributes.Content)
+	if function.Name == "" {
+		function.Name = doc.Attributes.Slug
+	}
+	function.DocID = doc.ID
+	return function
+}
+
+var (
+	functionTitlePattern     = regexp.MustCompile("^#\\s+(?:function:\\s*)?`?([A-Za-z0-9_]+)`?(?:\\s+function)?\\s*$")
+	functionHeadingPattern   = regexp.MustCompile(`^(#{2,4})\s+(.+?)\s*#*\s*$`)
+	functionSignaturePattern = regexp.MustCompile(`^([A-Za-z0-9_]+)\s*\((.*)\)\s*(.*)$`)
+	functionArgumentPattern  = regexp.MustCompile("^(?:\\d+\\.|[-*])\\s+`(?:\\.\\.\\.)?([^`]+)`\\s*(?:\\(([^)]*)\\))?\\s*(.*)$")
+)
+
+// ParseFunctionDoc parses a provider-defined function from its Markdown doc. It reads
+// the layout generated by tfplugindocs: a "# function: name" title and summary, the
+// signature in the code block of the "## Signature" section, and the numbered
+// "## Arguments" list, whose "(Variadic, Type)" annotation marks the variadic parameter.
+// Parameters documented only in the argument list are appended to the signature's.
+func ParseFunctionDoc(content string) ProviderFunction {
+	var function ProviderFunction
+	index := make(map[string]int)
+
+	add := func(param FunctionParameter) {
+		if i, ok := index[param.Name]; ok {
+			existing := &function.Parameters[i]
+			existing.Variadic = existing.Variadic || param.Variadic
+			if existing.Type == "" {
+				existing.Type = param.Type
+			}
+			if existing.Description == "" {
+				existing.Description = param.Description
+			}
+			return
+		}
+		index[param.Name] = len(function.Parameters)
+		function.Parameters = append(function.Parameters, param)
+	}
+
+	section := ""
+	inCode := false
+	var summary []string
+	summaryDone := false
+
+	for _, line := range strings.Split(stripFrontMatter(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			if section == "signature" && line != "" && function.ReturnType == "" {
+				parseFunctionSignature(line, &function, add)
+			}
+			continue
+		}
+
+		if match := functionTitlePattern.FindStringSubmatch(line); match != nil && section == "" {
+			if function.Name == "" {
+				function.Name = match[1]
+			}
+			section = "summary"
+			continue
+		}
+		if match := functionHeadingPattern.FindStringSubmatch(line); match != nil {
+			title := strings.ToLower(match[2])
+			switch {
+			case strings.Contains(title, "signature"):
+				section = "signature"
+			case strings.Contains(title, "argument") || strings.Contains(title, "parameter"):
+				section = "arguments"
+			default:
+				section = "other"
+			}
+			summaryDone = true
+			continue
+		}
+
+		switch section {
+		case "summary":
+			if summaryDone {
+				continue
+			}
+			if line == "" {
+				summaryDone = len(summary) > 0
+				continue
+			}
+			summary = append(summary, line)
+		case "arguments":
+			match := functionArgumentPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			param := FunctionParameter{Name: match[1], Description: match[3]}
+			for _, flag := range strings.Split(match[2], ",") {
+				flag = strings.TrimSpace(flag)
+				switch {
+				case strings.EqualFold(flag, "variadic"):
+					param.Variadic = true
+				case flag != "":
+					param.Type = strings.ToLower(flag)
+				}
+			}
+			add(param)
+		}
+	}
+
+	function.Summary = strings.Join(summary, " ")
+	return function
+}
+
+// parseFunctionSignature reads a "name(param type, ...param type) return" line
+func parseFunctionSignature(line string, function *ProviderFunction, add func(FunctionParameter)) {
+	match := functionSignaturePattern.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+
+	if function.Name == "" {
+		function.Name = match[1]
+	}
+	function.ReturnType = strings.TrimSpace(match[3])
+
+	for _, param := range splitTopLevel(match[2]) {
+		name, typ, _ := strings.Cut(strings.TrimSpace(param), " ")
+		if name == "" {
+			continue
+		}
+		variadic := strings.HasPrefix(name, "...")
+		add(FunctionParameter{
+			Name:     strings.TrimPrefix(name, "..."),
+			Type:     strings.TrimSpace(typ),
+			Variadic: variadic,
+		})
+	}
+}
+
+// splitTopLevel splits a parameter list on commas outside brackets
+func splitTopLevel(list string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range list {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(list[start:]) != "" {
+		parts = append(parts, list[start:])
+	}
+	return parts
+}
+
+// stripFrontMatter removes a leading YAML front matter block from Markdown
+func stripFrontMatter(content string) string {
+	if !strings.HasPrefix(content, "---") {
+		return content
+	}
+	rest := content[3:]
+	if end := strings.Index(rest, "\n---"); end >= 0 {
+		rest = rest[end+4:]
+		if newline := strings.IndexByte(rest, '\n'); newline >= 0 {
+			return rest[newline+1:]
+		}
+		return ""
+	}
+	return content
+}
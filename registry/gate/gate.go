@@ -0,0 +1,278 @@
+// Package gate evaluates registry modules and providers against an organization's
+// ingestion policy before they are admitted to an internal catalog. A Policy holds
+// rules such as allowed namespaces, minimum downloads, maximum version age and a
+// license allowlist; Evaluate returns a structured pass or fail result with the reason
+// for every rule. Policies start from DefaultPolicy and can be loaded from YAML.
+package gate
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Kind is the kind of artifact being evaluated
+type Kind string
+
+const (
+	// KindModule is a module, addressed as namespace/name/provider
+	KindModule Kind = "module"
+
+	// KindProvider is a provider, addressed as namespace/name
+	KindProvider Kind = "provider"
+)
+
+// Rule identifiers reported in checks
+const (
+	RuleNamespace  = "allowed-namespace"
+	RuleDownloads  = "min-downloads"
+	RuleVersionAge = "max-version-age"
+	RuleLicense    = "license"
+)
+
+// LicenseUnknown is the SPDX NOASSERTION identifier. A license allowlist containing it
+// admits subjects whose license could not be determined.
+const LicenseUnknown = "NOASSERTION"
+
+// Subject is a module or provider version evaluated by a policy
+type Subject struct {
+	Kind      Kind
+	Namespace string
+	Name      string
+
+	// Provider is the provider of a module; empty for providers
+	Provider string
+
+	Version     string
+	Downloads   int64
+	PublishedAt time.Time
+
+	// License is the SPDX identifier of the license, e.g. "Apache-2.0"; empty when
+	// unknown. The registry does not report licenses; see DetectLicense and FindLicense.
+	License string
+}
+
+// String returns the subject address with its version
+func (s Subject) String() string {
+	address := s.Namespace + "/" + s.Name
+	if s.Kind == KindModule {
+		address += "/" + s.Provider
+	}
+	if s.Version != "" {
+		address += "@" + s.Version
+	}
+	return address
+}
+
+// ModuleSubject returns the subject of a module version
+func ModuleSubject(m registry.Module) Subject {
+	return Subject{
+		Kind:        KindModule,
+		Namespace:   m.Namespace,
+		Name:        m.Name,
+		Provider:    m.Provider,
+		Version:     m.Version,
+		Downloads:   m.Downloads,
+		PublishedAt: m.PublishedAt,
+	}
+}
+
+// ProviderSubject returns the subject of a provider version
+func ProviderSubject(p registry.Provider) Subject {
+	return Subject{
+		Kind:        KindProvider,
+		Namespace:   p.Namespace,
+		Name:        p.Name,
+		Version:     p.Version,
+		Downloads:   p.Downloads,
+		PublishedAt: p.PublishedAt,
+	}
+}
+
+// Rules is a set of ingestion rules. A rule left at its zero value is not checked.
+type Rules struct {
+	// AllowedNamespaces lists the namespaces subjects may come from, matched
+	// case-insensitively; entries may use path.Match patterns such as "acme-*"
+	AllowedNamespaces []string `yaml:"allowed_namespaces" json:"allowed_namespaces,omitempty"`
+
+	// MinDownloads is the minimum number of downloads
+	MinDownloads int64 `yaml:"min_downloads" json:"min_downloads,omitempty"`
+
+	// MaxVersionAge is the maximum time since the version was published
+	MaxVersionAge Duration `yaml:"max_version_age" json:"max_version_age,omitempty"`
+
+	// AllowedLicenses lists the SPDX identifiers of allowed licenses, matched
+	// case-insensitively; include LicenseUnknown to admit subjects without a license
+	AllowedLicenses []string `yaml:"allowed_licenses" json:"allowed_licenses,omitempty"`
+}
+
+// Policy holds the rules applied to modules and providers
+type Policy struct {
+	// Rules apply to both modules and providers
+	Rules `yaml:",inline"`
+
+	// Modules and Providers, when set, replace Rules for their kind of subject.
+	// ParseYAML fills their rules not set in the document from Rules.
+	Modules   *Rules `yaml:"modules,omitempty" json:"modules,omitempty"`
+	Providers *Rules `yaml:"providers,omitempty" json:"providers,omitempty"`
+}
+
+// DefaultLicenses are the permissive and weak copyleft licenses allowed by DefaultPolicy
+var DefaultLicenses = []string{"Apache-2.0", "MIT", "MPL-2.0", "BSD-2-Clause", "BSD-3-Clause", "ISC"}
+
+// DefaultPolicy returns the default policy: any namespace, at least 1,000 downloads,
+// and versions published within the last two years under one of DefaultLicenses
+func DefaultPolicy() *Policy {
+	return &Policy{Rules: Rules{
+		MinDownloads:    1000,
+		MaxVersionAge:   Duration(2 * 365 * day),
+		AllowedLicenses: append([]string(nil), DefaultLicenses...),
+	}}
+}
+
+// RulesFor returns the rules applied to a kind of subject
+func (p *Policy) RulesFor(kind Kind) Rules {
+	switch {
+	case kind == KindModule && p.Modules != nil:
+		return *p.Modules
+	case kind == KindProvider && p.Providers != nil:
+		return *p.Providers
+	}
+	return p.Rules
+}
+
+// Check is the outcome of one rule
+type Check struct {
+	Rule   string
+	Passed bool
+
+	// Reason explains the outcome
+	Reason string
+}
+
+// String formats the check as a single line
+func (c Check) String() string {
+	status := "pass"
+	if !c.Passed {
+		status = "fail"
+	}
+	return fmt.Sprintf("[%s] %s: %s", status, c.Rule, c.Reason)
+}
+
+// Result is the outcome of evaluating a subject
+type Result struct {
+	Subject Subject
+
+	// Passed reports whether every check passed
+	Passed bool
+
+	// Checks holds one entry per configured rule
+	Checks []Check
+}
+
+// Failures returns the checks that failed
+func (r Result) Failures() []Check {
+	var failed []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Reasons returns the reasons of the failed checks
+func (r Result) Reasons() []string {
+	var reasons []string
+	for _, c := range r.Failures() {
+		reasons = append(reasons, c.Reason)
+	}
+	return reasons
+}
+
+// Evaluate checks a subject against the policy
+func (p *Policy) Evaluate(subject Subject) Result {
+	return p.EvaluateAt(subject, time.Now())
+}
+
+// EvaluateAt checks a subject against the policy, measuring version age at now
+func (p *Policy) EvaluateAt(subject Subject, now time.Time) Result {
+	rules := p.RulesFor(subject.Kind)
+	result := Result{Subject: subject, Passed: true}
+
+	add := func(rule string, passed bool, format string, args ...any) {
+		result.Checks = append(result.Checks, Check{Rule: rule, Passed: passed, Reason: fmt.Sprintf(format, args...)})
+		result.Passed = result.Passed && passed
+	}
+
+	if len(rules.AllowedNamespaces) > 0 {
+		if matchAny(rules.AllowedNamespaces, subject.Namespace) {
+			add(RuleNamespace, true, "namespace %q is allowed", subject.Namespace)
+		} else {
+			add(RuleNamespace, false, "namespace %q is not in the allowed namespaces", subject.Namespace)
+		}
+	}
+
+	if rules.MinDownloads > 0 {
+		if subject.Downloads >= rules.MinDownloads {
+			add(RuleDownloads, true, "%d downloads, minimum is %d", subject.Downloads, rules.MinDownloads)
+		} else {
+			add(RuleDownloads, false, "%d downloads, below the minimum of %d", subject.Downloads, rules.MinDownloads)
+		}
+	}
+
+	if rules.MaxVersionAge > 0 {
+		age := now.Sub(subject.PublishedAt)
+		switch {
+		case subject.PublishedAt.IsZero():
+			add(RuleVersionAge, false, "publish date of version %s is unknown", subject.Version)
+		case age > time.Duration(rules.MaxVersionAge):
+			add(RuleVersionAge, false, "version %s was published %d days ago, more than the maximum of %s", subject.Version, int(age/day), rules.MaxVersionAge)
+		default:
+			add(RuleVersionAge, true, "version %s was published %d days ago, maximum is %s", subject.Version, int(max(age, 0)/day), rules.MaxVersionAge)
+		}
+	}
+
+	if len(rules.AllowedLicenses) > 0 {
+		license := subject.License
+		if license == "" {
+			license = LicenseUnknown
+		}
+		switch {
+		case containsFold(rules.AllowedLicenses, license) && subject.License == "":
+			add(RuleLicense, true, "license is unknown, which is allowed")
+		case containsFold(rules.AllowedLicenses, license):
+			add(RuleLicense, true, "license %s is allowed", license)
+		case subject.License == "":
+			add(RuleLicense, false, "license is unknown")
+		default:
+			add(RuleLicense, false, "license %s is not in the allowed licenses", license)
+		}
+	}
+
+	return result
+}
+
+// matchAny reports whether name matches one of the patterns, ignoring case
+func matchAny(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(pattern), name); ok && err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
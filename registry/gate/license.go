@@ -0,0 +1,74 @@
+package gate
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+var spdxRegex = regexp.MustCompile(`(?i)SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+// licenseMarkers identify licenses by phrases of their text, checked in order so that
+// more specific licenses come before those whose phrases they contain
+var licenseMarkers = []struct {
+	license string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"BUSL-1.1", []string{"business source license"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+// DetectLicense returns the SPDX identifier of a license text, from an
+// SPDX-License-Identifier line or the wording of common open source licenses, or an
+// empty string when the license is not recognized
+func DetectLicense(text string) string {
+	if match := spdxRegex.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, marker := range licenseMarkers {
+		matched := true
+		for _, phrase := range marker.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return marker.license
+		}
+	}
+	return ""
+}
+
+// FindLicense returns the license of a module from the LICENSE, LICENCE or COPYING file
+// at the root of its archive, as read with ModuleArchive.Files, or an empty string when
+// there is none or it is not recognized
+func FindLicense(files []registry.ArchiveFile) string {
+	for _, file := range files {
+		if strings.Contains(file.Path, "/") {
+			continue
+		}
+		name := strings.ToUpper(strings.TrimSuffix(file.Path, path.Ext(file.Path)))
+		if name == "LICENSE" || name == "LICENCE" || name == "COPYING" {
+			if license := DetectLicense(string(file.Data)); license != "" {
+				return license
+			}
+		}
+	}
+	return ""
+}
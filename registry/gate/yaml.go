@@ -0,0 +1,159 @@
+package gate
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// day is the unit of the "d" duration suffix
+const day = 24 * time.Hour
+
+// Duration is a time.Duration written in policies as a Go duration such as "720h", or
+// as whole days or weeks such as "730d" or "26w"
+type Duration time.Duration
+
+// ParseDuration parses a policy duration
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": day, "w": 7 * day} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return Duration(time.Duration(count) * unit), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return Duration(d), nil
+}
+
+// String formats the duration in days when it is a whole number of days
+func (d Duration) String() string {
+	if d != 0 && time.Duration(d)%day == 0 {
+		return fmt.Sprintf("%dd", time.Duration(d)/day)
+	}
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ruleKeys are the keys of a rule set in YAML policies
+var ruleKeys = []string{"allowed_namespaces", "min_downloads", "max_version_age", "allowed_licenses"}
+
+// LoadYAML reads a policy from YAML
+func LoadYAML(r io.Reader) (*Policy, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	return ParseYAML(data)
+}
+
+// ParseYAML parses a YAML policy such as
+//
+//	allowed_namespaces: [hashicorp, terraform-aws-modules, "acme-*"]
+//	max_version_age: 365d
+//	allowed_licenses: [Apache-2.0, MIT, MPL-2.0]
+//	providers:
+//	  min_downloads: 1000000
+//
+// on top of DefaultPolicy: rules missing from the document keep their default, and
+// rules missing from a modules or providers section are taken from the top level. Set a
+// rule to 0 or [] to disable it. Unknown keys are rejected so that a misspelled rule
+// does not silently admit everything.
+func ParseYAML(data []byte) (*Policy, error) {
+	policy := DefaultPolicy()
+	if err := yaml.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	return policy, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler. Rules in the node replace those of the
+// policy, and the modules and providers sections start from the resulting top-level
+// rules.
+func (p *Policy) UnmarshalYAML(node *yaml.Node) error {
+	if err := checkKeys(node, append([]string{"modules", "providers"}, ruleKeys...)); err != nil {
+		return err
+	}
+
+	var doc struct {
+		Rules     `yaml:",inline"`
+		Modules   yaml.Node `yaml:"modules"`
+		Providers yaml.Node `yaml:"providers"`
+	}
+	doc.Rules = p.Rules
+	if err := node.Decode(&doc); err != nil {
+		return err
+	}
+	p.Rules = doc.Rules
+
+	sections := []struct {
+		node  *yaml.Node
+		rules **Rules
+	}{
+		{&doc.Modules, &p.Modules},
+		{&doc.Providers, &p.Providers},
+	}
+	for _, section := range sections {
+		if section.node.Kind == 0 {
+			continue
+		}
+		if err := checkKeys(section.node, ruleKeys); err != nil {
+			return err
+		}
+		rules := p.Rules
+		if *section.rules != nil {
+			rules = **section.rules
+		}
+		rules.AllowedNamespaces = slices.Clone(rules.AllowedNamespaces)
+		rules.AllowedLicenses = slices.Clone(rules.AllowedLicenses)
+		if err := section.node.Decode(&rules); err != nil {
+			return err
+		}
+		*section.rules = &rules
+	}
+
+	return nil
+}
+
+// checkKeys returns an error for keys of a mapping node that are not in allowed
+func checkKeys(node *yaml.Node, allowed []string) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of rules", node.Line)
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !slices.Contains(allowed, key.Value) {
+			return fmt.Errorf("line %d: unknown policy key %q", key.Line, key.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package gpg verifies provider SHA256SUMS signatures with OpenPGP keys published by the
+// registry. It is kept out of the registry package so that clients which do not verify
+// provider packages do not depend on an OpenPGP implementation. It uses the frozen
+// golang.org/x/crypto/openpgp package, which handles the RSA keys providers sign with.
+package gpg
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// Verifier implements registry.SignatureVerifier with OpenPGP detached signatures,
+// binary or ASCII-armored
+type Verifier struct{}
+
+// NewVerifier creates an OpenPGP signature verifier
+func NewVerifier() *Verifier {
+	return &Verifier{}
+}
+
+// VerifySignature implements registry.SignatureVerifier. Keys that cannot be parsed are
+// skipped; the signature must verify against one of the others.
+func (v *Verifier) VerifySignature(data, signature []byte, keys []registry.GPGPublicKey) (string, error) {
+	var keyring openpgp.EntityList
+	for _, key := range keys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.ASCIIArmor))
+		if err != nil {
+			continue
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return "", fmt.Errorf("%w: no usable signing keys", registry.ErrInvalidSignature)
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		block, err := armor.Decode(bytes.NewReader(signature))
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", registry.ErrInvalidSignature, err)
+		}
+		var decoded bytes.Buffer
+		if _, err := decoded.ReadFrom(block.Body); err != nil {
+			return "", fmt.Errorf("%w: %v", registry.ErrInvalidSignature, err)
+		}
+		signature = decoded.Bytes()
+	}
+
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(data), bytes.NewReader(signature))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", registry.ErrInvalidSignature, err)
+	}
+
+	return signer.PrimaryKey.KeyIdString(), nil
+}
+
+// VerifyProviderSignature checks a detached signature of a provider's SHA256SUMS file
+// against keys, such as those of ProviderDownload.SigningKeys or
+// Providers.GetSigningKeys, and returns the ID of the signing key
+func VerifyProviderSignature(shasums, signature []byte, keys []registry.GPGPublicKey) (string, error) {
+	return NewVerifier().VerifySignature(shasums, signature, keys)
+}
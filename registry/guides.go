@@ -0,0 +1,217 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// GuideSection is a headed section of a provider guide
+type GuideSection struct {
+	// Level is the heading level, 2 for "##"
+	Level int
+
+	Title string
+
+	// Content is the Markdown below the heading up to the next heading, code blocks
+	// included
+	Content string
+}
+
+// GuideExample is a fenced code block of a provider guide
+type GuideExample struct {
+	// Language is the info string of the fence, e.g. "terraform" or "shell"; empty when
+	// the fence has none
+	Language string
+
+	Code string
+
+	// Section is the title of the section holding the example; empty before the first
+	// section heading
+	Section string
+}
+
+// ProviderGuide is a provider guide with its Markdown and the structure parsed from it
+type ProviderGuide struct {
+	DocID       string
+	Slug        string
+	Title       string
+	Subcategory string
+
+	// Markdown is the raw content of the guide, front matter included
+	Markdown string
+
+	// Intro is the text between the title and the first section
+	Intro string
+
+	Sections []GuideSection
+	Examples []GuideExample
+}
+
+// ListGuides returns the guides of a provider version, such as upgrade and
+// authentication guides
+func (s *ProvidersService) ListGuides(ctx context.Context, providerVersionID string) ([]ProviderData, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Value:   providerVersionID,
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	opts := &ProviderDocListOptions{
+		ProviderVersionID: providerVersionID,
+		Category:          "guides",
+		Language:          "hcl",
+	}
+
+	docs, err := s.ListDocsV2(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list guides: %w", err)
+	}
+
+	return docs, nil
+}
+
+// GetGuide returns the guide of a provider version with the given slug, e.g.
+// "version-5-upgrade", as raw Markdown and parsed into sections and code examples
+func (s *ProvidersService) GetGuide(ctx context.Context, providerVersionID, slug string) (*ProviderGuide, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Value:   providerVersionID,
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	if slug == "" {
+		return nil, &ValidationError{
+			Field:   "slug",
+			Value:   slug,
+			Message: "slug cannot be empty",
+		}
+	}
+
+	opts := &ProviderDocListOptions{
+		ProviderVersionID: providerVersionID,
+		Category:          "guides",
+		Slug:              slug,
+		Language:          "hcl",
+	}
+
+	docs, err := s.ListDocsV2(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find guide %s: %w", slug, err)
+	}
+
+	if len(docs) == 0 {
+		return nil, &APIError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("guide %s not found", slug),
+		}
+	}
+
+	doc, err := s.GetDoc(ctx, docs[0].ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProviderGuide(doc.Data), nil
+}
+
+// newProviderGuide parses a guide doc, falling back to the doc title when the content
+// has no title heading
+func newProviderGuide(doc ProviderDocData) *ProviderGuide {
+	guide := ParseGuide(doc.Attributes.Content)
+	if guide.Title == "" {
+		guide.Title = doc.Attributes.Title
+	}
+	guide.DocID = doc.ID
+	guide.Slug = doc.Attributes.Slug
+	guide.Subcategory = doc.Attributes.Subcategory
+	return guide
+}
+
+var (
+	guideHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	guideFencePattern   = regexp.MustCompile("^(```+|~~~+)\\s*([A-Za-z0-9_+-]*)")
+)
+
+// ParseGuide parses the Markdown of a provider guide: the "# " title, the intro before
+// the first section, the sections of the "##" and deeper headings, and the fenced code
+// blocks. Headings inside code blocks are not sections. Front matter is skipped.
+func ParseGuide(content string) *ProviderGuide {
+	guide := &ProviderGuide{Markdown: content}
+
+	var (
+		section *GuideSection
+		intro   []string
+		body    []string
+		code    []string
+		fence   string
+		lang    string
+	)
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		if section != nil {
+			section.Content = text
+			guide.Sections = append(guide.Sections, *section)
+		} else if text != "" {
+			intro = append(intro, text)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(stripFrontMatter(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				example := GuideExample{Language: lang, Code: strings.Join(code, "\n")}
+				if section != nil {
+					example.Section = section.Title
+				}
+				guide.Examples = append(guide.Examples, example)
+				fence, code = "", nil
+			} else {
+				code = append(code, line)
+			}
+			body = append(body, line)
+			continue
+		}
+
+		if match := guideFencePattern.FindStringSubmatch(trimmed); match != nil {
+			fence, lang = match[1], match[2]
+			body = append(body, line)
+			continue
+		}
+
+		if match := guideHeadingPattern.FindStringSubmatch(trimmed); match != nil {
+			level := len(match[1])
+			if level == 1 && guide.Title == "" && section == nil {
+				guide.Title = match[2]
+				continue
+			}
+			flush()
+			section = &GuideSection{Level: level, Title: match[2]}
+			continue
+		}
+
+		body = append(body, line)
+	}
+
+	// An unterminated code block runs to the end of the guide
+	if fence != "" {
+		example := GuideExample{Language: lang, Code: strings.Join(code, "\n")}
+		if section != nil {
+			example.Section = section.Title
+		}
+		guide.Examples = append(guide.Examples, example)
+	}
+	flush()
+
+	guide.Intro = strings.Join(intro, "\n\n")
+	return guide
+}
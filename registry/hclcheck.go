@@ -0,0 +1,87 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// HCLDiagnostic is an error found in HCL code, with its position when known
+type HCLDiagnostic struct {
+	Summary string `json:"summary"`
+	Detail  string `json:"detail,omitempty"`
+
+	// Line and Column locate the start of the error, 1-based; zero when unknown
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+
+	// EndLine and EndColumn locate the end of the error
+	EndLine   int `json:"end_line,omitempty"`
+	EndColumn int `json:"end_column,omitempty"`
+}
+
+// String returns the diagnostic as "line:column: summary; detail"
+func (d HCLDiagnostic) String() string {
+	msg := d.Summary
+	if d.Detail != "" {
+		msg += "; " + d.Detail
+	}
+	if d.Line == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, msg)
+}
+
+// HCLValidator checks HCL code beyond syntax, such as with TerraformValidator. It
+// returns the errors found in the code, or an error when the check could not run.
+type HCLValidator func(ctx context.Context, code string) ([]HCLDiagnostic, error)
+
+// CheckHCL parses code with the embedded HCL parser and returns its syntax errors, or
+// nil when it parses
+func CheckHCL(code string) []HCLDiagnostic {
+	_, diags := hclsyntax.ParseConfig([]byte(code), "example.tf", hcl.InitialPos)
+	return hclErrors(diags)
+}
+
+// hclErrors converts the errors of HCL diagnostics
+func hclErrors(diags hcl.Diagnostics) []HCLDiagnostic {
+	var errors []HCLDiagnostic
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		converted := HCLDiagnostic{Summary: diag.Summary, Detail: diag.Detail}
+		if diag.Subject != nil {
+			converted.Line = diag.Subject.Start.Line
+			converted.Column = diag.Subject.Start.Column
+			converted.EndLine = diag.Subject.End.Line
+			converted.EndColumn = diag.Subject.End.Column
+		}
+		errors = append(errors, converted)
+	}
+	return errors
+}
+
+// ValidateWith runs a validator on a parsed example, adding the errors it finds. Examples
+// that do not parse are not passed to the validator.
+func (e *TerraformExample) ValidateWith(ctx context.Context, validator HCLValidator) error {
+	if !e.Validated {
+		*e = ParseTerraformExample(e.Code)
+	}
+	if !e.Valid || validator == nil {
+		return nil
+	}
+
+	errors, err := validator(ctx, e.Code)
+	if err != nil {
+		return fmt.Errorf("failed to validate example: %w", err)
+	}
+	for _, diag := range errors {
+		e.Valid = false
+		e.Errors = append(e.Errors, diag)
+		e.Diagnostics = append(e.Diagnostics, diag.String())
+	}
+	return nil
+}
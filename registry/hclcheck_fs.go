@@ -0,0 +1,94 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TerraformValidator returns a validator that runs "terraform validate" on each example
+// in a temporary directory, after "terraform init -backend=false", which downloads the
+// providers the example requires. binary is the Terraform or OpenTofu executable,
+// "terraform" from PATH when empty.
+func TerraformValidator(binary string) HCLValidator {
+	if binary == "" {
+		binary = "terraform"
+	}
+
+	return func(ctx context.Context, code string) ([]HCLDiagnostic, error) {
+		dir, err := os.MkdirTemp("", "registry-example-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create working directory: %w", err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(code), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write example: %w", err)
+		}
+
+		// validate exits with an error when the configuration is invalid, but still
+		// prints its JSON report, so only init fails on the exit status alone
+		run := func(allowFailure bool, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, binary, args...)
+			cmd.Dir = dir
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			output, err := cmd.Output()
+			if err != nil && !(allowFailure && len(output) > 0) {
+				return nil, fmt.Errorf("%s %s: %w: %s", binary, args[0], err, strings.TrimSpace(stderr.String()))
+			}
+			return output, nil
+		}
+
+		if _, err := run(false, "init", "-backend=false", "-input=false", "-no-color"); err != nil {
+			return nil, err
+		}
+
+		output, err := run(true, "validate", "-json", "-no-color")
+		if err != nil {
+			return nil, err
+		}
+
+		var report struct {
+			Diagnostics []struct {
+				Severity string `json:"severity"`
+				Summary  string `json:"summary"`
+				Detail   string `json:"detail"`
+				Range    *struct {
+					Start struct {
+						Line   int `json:"line"`
+						Column int `json:"column"`
+					} `json:"start"`
+					End struct {
+						Line   int `json:"line"`
+						Column int `json:"column"`
+					} `json:"end"`
+				} `json:"range"`
+			} `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(output, &report); err != nil {
+			return nil, fmt.Errorf("failed to decode %s validate output: %w", binary, err)
+		}
+
+		var errors []HCLDiagnostic
+		for _, diag := range report.Diagnostics {
+			if diag.Severity != "error" {
+				continue
+			}
+			converted := HCLDiagnostic{Summary: diag.Summary, Detail: diag.Detail}
+			if diag.Range != nil {
+				converted.Line, converted.Column = diag.Range.Start.Line, diag.Range.Start.Column
+				converted.EndLine, converted.EndColumn = diag.Range.End.Line, diag.Range.End.Column
+			}
+			errors = append(errors, converted)
+		}
+		return errors, nil
+	}
+}
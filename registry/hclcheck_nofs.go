@@ -0,0 +1,13 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+import "context"
+
+// TerraformValidator returns a validator that reports that running Terraform is
+// unavailable in this build
+func TerraformValidator(binary string) HCLValidator {
+	return func(ctx context.Context, code string) ([]HCLDiagnostic, error) {
+		return nil, ErrFilesystemUnsupported
+	}
+}
@@ -0,0 +1,342 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MaxHistorySnapshots is the number of snapshots kept per provider or module; older
+// snapshots are dropped
+const MaxHistorySnapshots = 1000
+
+// WithHistoryStore keeps version metadata snapshots in store, so that TrendReport can
+// compare them over time. Each provider or module is stored as a JSON array of
+// VersionSnapshot under the key history/provider/<namespace>/<name> or
+// history/module/<namespace>/<name>/<provider>.
+func WithHistoryStore(store CheckpointStore) ClientOption {
+	return func(c *ClientConfig) {
+		c.HistoryStore = store
+	}
+}
+
+// VersionSnapshot is the version metadata of a provider or module at a point in time
+type VersionSnapshot struct {
+	// TakenAt is when the metadata was read
+	TakenAt time.Time `json:"taken_at"`
+
+	// LatestVersion is the newest version at the time
+	LatestVersion string `json:"latest_version"`
+
+	// Downloads is the all-time download count at the time
+	Downloads int64 `json:"downloads"`
+
+	// Versions lists the published versions, newest first
+	Versions []VersionRelease `json:"versions"`
+}
+
+// VersionRelease is a published version of a provider or module
+type VersionRelease struct {
+	Version string `json:"version"`
+
+	// PublishedAt is the publish time reported by the registry. In a TrendReport, a
+	// version the registry gives no time for is dated by the first snapshot listing it.
+	PublishedAt time.Time `json:"published_at"`
+
+	// Downloads is the all-time download count of the version, when reported
+	Downloads int64 `json:"downloads,omitempty"`
+}
+
+// TrendReport describes how a provider or module evolved over a time window, from the
+// snapshots kept in the history store
+type TrendReport struct {
+	// Address is namespace/name for providers and namespace/name/provider for modules
+	Address string
+
+	// From and To bound the window
+	From time.Time
+	To   time.Time
+
+	// Snapshots is the number of snapshots taken in the window
+	Snapshots int
+
+	// Releases are the versions published in the window, oldest first
+	Releases []VersionRelease
+
+	// ReleasesPerMonth is the number of releases per 30 days of the window
+	ReleasesPerMonth float64
+
+	// AverageTimeBetweenReleases is the mean gap between consecutive releases in the
+	// window; zero with fewer than two releases
+	AverageTimeBetweenReleases time.Duration
+
+	// DownloadsStart and DownloadsEnd are the all-time downloads in the first and last
+	// snapshots of the window
+	DownloadsStart int64
+	DownloadsEnd   int64
+
+	// DownloadGrowth is DownloadsEnd minus DownloadsStart, and DownloadGrowthRate the
+	// growth relative to DownloadsStart; zero when DownloadsStart is
+	DownloadGrowth     int64
+	DownloadGrowthRate float64
+
+	// DownloadsPerDay is the growth per day between the first and last snapshots
+	DownloadsPerDay float64
+}
+
+// RecordHistory reads the current version metadata of a provider and adds it to the
+// history store
+func (s *ProvidersService) RecordHistory(ctx context.Context, ref ProviderRef) (*VersionSnapshot, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+	if err := s.client.requireHistoryStore(); err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.GetDownloadMetrics(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := VersionSnapshot{TakenAt: metrics.FetchedAt, Downloads: metrics.Downloads.Total}
+	if len(metrics.Versions) > 0 {
+		snapshot.LatestVersion = metrics.Versions[0].Version
+	}
+	for _, version := range metrics.Versions {
+		snapshot.Versions = append(snapshot.Versions, VersionRelease{
+			Version:     version.Version,
+			PublishedAt: version.PublishedAt,
+			Downloads:   version.Downloads,
+		})
+	}
+
+	key := fmt.Sprintf("history/provider/%s/%s", ref.Namespace, ref.Name)
+	if err := s.client.recordHistory(ctx, key, snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// TrendReport records a snapshot of a provider and reports its release frequency and
+// download growth over the window ending now, from the snapshots in the history store.
+// Trends become meaningful once snapshots have been recorded over the window, such as
+// by calling RecordHistory or TrendReport daily.
+func (s *ProvidersService) TrendReport(ctx context.Context, ref ProviderRef, window time.Duration) (*TrendReport, error) {
+	if err := validateTrendWindow(window); err != nil {
+		return nil, err
+	}
+	if _, err := s.RecordHistory(ctx, ref); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("history/provider/%s/%s", ref.Namespace, ref.Name)
+	snapshots, err := s.client.loadHistory(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTrendReport(ref.Namespace+"/"+ref.Name, snapshots, window, time.Now()), nil
+}
+
+// RecordHistory reads the current version metadata of a module and adds it to the
+// history store
+func (s *ModulesService) RecordHistory(ctx context.Context, ref ModuleRef) (*VersionSnapshot, error) {
+	if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ""); err != nil {
+		return nil, err
+	}
+	if err := s.client.requireHistoryStore(); err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.GetMetrics(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.GetVersionDownloads(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := VersionSnapshot{TakenAt: metrics.FetchedAt, Downloads: metrics.Downloads.Total}
+	if len(versions) > 0 {
+		snapshot.LatestVersion = versions[0].Version
+	}
+	for _, version := range versions {
+		snapshot.Versions = append(snapshot.Versions, VersionRelease{
+			Version:     version.Version,
+			PublishedAt: version.PublishedAt,
+			Downloads:   version.Downloads,
+		})
+	}
+
+	key := fmt.Sprintf("history/module/%s/%s/%s", ref.Namespace, ref.Name, ref.Provider)
+	if err := s.client.recordHistory(ctx, key, snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// TrendReport records a snapshot of a module and reports its release frequency and
+// download growth over the window ending now, from the snapshots in the history store
+func (s *ModulesService) TrendReport(ctx context.Context, ref ModuleRef, window time.Duration) (*TrendReport, error) {
+	if err := validateTrendWindow(window); err != nil {
+		return nil, err
+	}
+	if _, err := s.RecordHistory(ctx, ref); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("history/module/%s/%s/%s", ref.Namespace, ref.Name, ref.Provider)
+	snapshots, err := s.client.loadHistory(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	address := fmt.Sprintf("%s/%s/%s", ref.Namespace, ref.Name, ref.Provider)
+	return buildTrendReport(address, snapshots, window, time.Now()), nil
+}
+
+// validateTrendWindow checks the window of a trend report
+func validateTrendWindow(window time.Duration) error {
+	if window <= 0 {
+		return &ValidationError{
+			Field:   "window",
+			Value:   window,
+			Message: "window must be positive",
+		}
+	}
+	return nil
+}
+
+// requireHistoryStore reports a configuration error when no history store is set
+func (c *Client) requireHistoryStore() error {
+	if c.config.HistoryStore == nil {
+		return fmt.Errorf("%w: version history requires a history store, see WithHistoryStore", ErrInvalidConfiguration)
+	}
+	return nil
+}
+
+// loadHistory returns the snapshots stored under key, oldest first
+func (c *Client) loadHistory(ctx context.Context, key string) ([]VersionSnapshot, error) {
+	data, err := c.config.HistoryStore.Load(ctx, key)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load history %s: %w", key, err)
+	}
+
+	var snapshots []VersionSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode history %s: %w", key, err)
+	}
+	return snapshots, nil
+}
+
+// recordHistory appends a snapshot to the history stored under key, keeping at most
+// MaxHistorySnapshots
+func (c *Client) recordHistory(ctx context.Context, key string, snapshot VersionSnapshot) error {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	snapshots, err := c.loadHistory(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	snapshots = append(snapshots, snapshot)
+	slices.SortStableFunc(snapshots, func(a, b VersionSnapshot) int {
+		return a.TakenAt.Compare(b.TakenAt)
+	})
+	if len(snapshots) > MaxHistorySnapshots {
+		snapshots = snapshots[len(snapshots)-MaxHistorySnapshots:]
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return fmt.Errorf("failed to encode history %s: %w", key, err)
+	}
+	if err := c.config.HistoryStore.Save(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to save history %s: %w", key, err)
+	}
+	return nil
+}
+
+// buildTrendReport computes the trends of the window ending at now from snapshots
+// sorted oldest first
+func buildTrendReport(address string, snapshots []VersionSnapshot, window time.Duration, now time.Time) *TrendReport {
+	report := &TrendReport{Address: address, From: now.Add(-window), To: now}
+
+	// Date every version known to any snapshot by the publish time the registry reported
+	// in any of them, or else by the first snapshot listing it; versions already in the
+	// first snapshot without a publish time stay undated
+	releases := make(map[string]VersionRelease)
+	firstSeen := make(map[string]time.Time)
+	for i, snapshot := range snapshots {
+		for _, version := range snapshot.Versions {
+			if _, ok := firstSeen[version.Version]; !ok {
+				firstSeen[version.Version] = time.Time{}
+				if i > 0 {
+					firstSeen[version.Version] = snapshot.TakenAt
+				}
+			}
+			if known, ok := releases[version.Version]; !ok || known.PublishedAt.IsZero() {
+				releases[version.Version] = version
+			}
+		}
+	}
+	for name, release := range releases {
+		if release.PublishedAt.IsZero() {
+			release.PublishedAt = firstSeen[name]
+			releases[name] = release
+		}
+	}
+	for _, release := range releases {
+		if !release.PublishedAt.IsZero() && !release.PublishedAt.Before(report.From) && !release.PublishedAt.After(now) {
+			report.Releases = append(report.Releases, release)
+		}
+	}
+	slices.SortFunc(report.Releases, func(a, b VersionRelease) int {
+		if c := a.PublishedAt.Compare(b.PublishedAt); c != 0 {
+			return c
+		}
+		return CompareVersions(a.Version, b.Version)
+	})
+
+	report.ReleasesPerMonth = float64(len(report.Releases)) / (window.Hours() / (24 * 30))
+	if n := len(report.Releases); n > 1 {
+		span := report.Releases[n-1].PublishedAt.Sub(report.Releases[0].PublishedAt)
+		report.AverageTimeBetweenReleases = span / time.Duration(n-1)
+	}
+
+	var first, last *VersionSnapshot
+	for i := range snapshots {
+		if snapshots[i].TakenAt.Before(report.From) || snapshots[i].TakenAt.After(now) {
+			continue
+		}
+		if first == nil {
+			first = &snapshots[i]
+		}
+		last = &snapshots[i]
+		report.Snapshots++
+	}
+	if first == nil {
+		return report
+	}
+
+	report.DownloadsStart = first.Downloads
+	report.DownloadsEnd = last.Downloads
+	report.DownloadGrowth = last.Downloads - first.Downloads
+	if first.Downloads > 0 {
+		report.DownloadGrowthRate = float64(report.DownloadGrowth) / float64(first.Downloads)
+	}
+	if days := last.TakenAt.Sub(first.TakenAt).Hours() / 24; days > 0 {
+		report.DownloadsPerDay = float64(report.DownloadGrowth) / days
+	}
+
+	return report
+}
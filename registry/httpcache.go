@@ -0,0 +1,416 @@
+package registry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HTTPCacheHeader is set to "1" on responses served by the HTTP cache middleware,
+	// including revalidated ones
+	HTTPCacheHeader = "X-From-Cache"
+
+	// DefaultHTTPCacheMaxBodySize is the largest response body the HTTP cache stores
+	DefaultHTTPCacheMaxBodySize = 10 << 20
+
+	// httpCacheKeyPrefix keeps HTTP cache entries apart from API response cache entries
+	// when both use the same Cache
+	httpCacheKeyPrefix = "http:"
+)
+
+// HTTPCacheOptions configures the HTTP cache middleware
+type HTTPCacheOptions struct {
+	// Shared makes the cache follow the rules of a shared cache, for example one
+	// directory used by several users: responses marked private and responses to
+	// requests with credentials are not stored unless marked public or s-maxage, and
+	// s-maxage takes precedence over max-age
+	Shared bool
+
+	// MaxBodySize limits the size of stored bodies in bytes; zero uses
+	// DefaultHTTPCacheMaxBodySize
+	MaxBodySize int64
+}
+
+// WithHTTPCache adds HTTPCacheMiddleware to the default HTTP client, after any
+// middleware added before it
+func WithHTTPCache(cache Cache, opts *HTTPCacheOptions) ClientOption {
+	return WithMiddleware(HTTPCacheMiddleware(cache, opts))
+}
+
+// HTTPCacheMiddleware is an HTTP cache following RFC 9111 for every request sent by
+// the client, including downloads outside the API. Unlike WithCache, which keeps API
+// responses for a configured TTL, it only stores responses the server marks as
+// cacheable with Cache-Control or Expires headers, or that carry a Last-Modified date
+// for heuristic freshness. Stale responses with an ETag or Last-Modified validator are
+// revalidated with conditional requests, Vary is honored, and request directives such
+// as no-cache, max-age, max-stale and only-if-cached are supported. Successful unsafe
+// requests invalidate the stored response for their URL. Requests that already carry
+// validators, such as revalidations by WithCache, are passed through.
+func HTTPCacheMiddleware(cache Cache, opts *HTTPCacheOptions) Middleware {
+	options := HTTPCacheOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.MaxBodySize <= 0 {
+		options.MaxBodySize = DefaultHTTPCacheMaxBodySize
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &httpCacheTransport{next: next, cache: cache, options: options}
+	}
+}
+
+// httpCacheTransport serves and stores responses around the next transport
+type httpCacheTransport struct {
+	next    http.RoundTripper
+	cache   Cache
+	options HTTPCacheOptions
+
+	// now is the clock; nil uses time.Now
+	now func() time.Time
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *httpCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := httpCacheKeyPrefix + responseCacheKey(req)
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && req.Method != http.MethodOptions && req.Method != http.MethodTrace &&
+			resp.StatusCode >= 200 && resp.StatusCode < 400 {
+			t.cache.Invalidate(key)
+		}
+		return resp, err
+	}
+
+	if req.Method == http.MethodHead || req.Header.Get("If-None-Match") != "" ||
+		req.Header.Get("If-Modified-Since") != "" || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	reqDirectives := parseCacheControl(req.Header)
+	if _, ok := reqDirectives["no-store"]; ok {
+		return t.next.RoundTrip(req)
+	}
+
+	entry, ok := t.cache.Get(key)
+	if ok && (entry.Header == nil || !varyMatches(entry, req)) {
+		ok = false
+	}
+
+	now := t.clock()
+	if ok && t.servable(entry, req, reqDirectives, now) {
+		return cachedHTTPResponse(req, entry), nil
+	}
+
+	if _, onlyIfCached := reqDirectives["only-if-cached"]; onlyIfCached {
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", http.StatusGatewayTimeout, http.StatusText(http.StatusGatewayTimeout)),
+			StatusCode: http.StatusGatewayTimeout,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+
+	outgoing := req
+	if ok && entry.hasValidator() {
+		outgoing = req.Clone(req.Context())
+		setConditionalHeaders(outgoing, entry)
+	}
+
+	requested := t.clock()
+	resp, err := t.next.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+	received := t.clock()
+
+	if resp.StatusCode == http.StatusNotModified && ok && outgoing != req {
+		drainBody(resp.Body)
+		refreshed := *entry
+		refreshed.Header = entry.Header.Clone()
+		for name, values := range resp.Header {
+			if !strings.EqualFold(name, "Content-Length") {
+				refreshed.Header[name] = values
+			}
+		}
+		t.store(key, &refreshed, req, requested, received)
+		return cachedHTTPResponse(req, &refreshed), nil
+	}
+
+	return t.storeResponse(key, req, resp, requested, received)
+}
+
+// servable reports whether a stored response can be used without contacting the server
+func (t *httpCacheTransport) servable(entry *CacheEntry, req *http.Request, reqDirectives map[string]string, now time.Time) bool {
+	if bypass, _ := bypassCacheKey.Get(req.Context()); bypass {
+		return false
+	}
+	if _, ok := reqDirectives["no-cache"]; ok {
+		return false
+	}
+	if strings.Contains(strings.ToLower(req.Header.Get("Pragma")), "no-cache") {
+		return false
+	}
+
+	respDirectives := parseCacheControl(entry.Header)
+	if _, ok := respDirectives["no-cache"]; ok {
+		return false
+	}
+
+	// Expires is when the entry stops being fresh, so its age follows from the lifetime
+	lifetime := t.lifetime(entry.Header, respDirectives)
+	age := now.Sub(entry.Expires) + lifetime
+
+	if value, ok := reqDirectives["max-age"]; ok {
+		if seconds, err := strconv.Atoi(value); err == nil && age > time.Duration(seconds)*time.Second {
+			return false
+		}
+	}
+	if value, ok := reqDirectives["min-fresh"]; ok {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			age += time.Duration(seconds) * time.Second
+		}
+	}
+	if age < lifetime {
+		return true
+	}
+
+	// Stale responses may be served when the request accepts them
+	_, mustRevalidate := respDirectives["must-revalidate"]
+	if _, ok := respDirectives["proxy-revalidate"]; ok && t.options.Shared {
+		mustRevalidate = true
+	}
+	if value, ok := reqDirectives["max-stale"]; ok && !mustRevalidate {
+		if value == "" {
+			return true
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && age-lifetime <= time.Duration(seconds)*time.Second {
+			return true
+		}
+	}
+	return false
+}
+
+// storeResponse reads a response and stores it when it is cacheable, returning a
+// response with a replayable body
+func (t *httpCacheTransport) storeResponse(key string, req *http.Request, resp *http.Response, requested, received time.Time) (*http.Response, error) {
+	if !t.storable(req, resp) {
+		if isCacheableStatus(resp.StatusCode) {
+			t.cache.Invalidate(key)
+		}
+		return resp, nil
+	}
+
+	// Read one byte more than the limit to detect bodies too large to store
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.options.MaxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.options.MaxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &CacheEntry{
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+	}
+	t.store(key, entry, req, requested, received)
+	return resp, nil
+}
+
+// store computes the expiry of an entry from its headers and saves it. Expires holds
+// the time the response stops being fresh, corrected for the age it had when received.
+func (t *httpCacheTransport) store(key string, entry *CacheEntry, req *http.Request, requested, received time.Time) {
+	directives := parseCacheControl(entry.Header)
+	lifetime := t.lifetime(entry.Header, directives)
+
+	apparentAge := time.Duration(0)
+	if date, err := http.ParseTime(entry.Header.Get("Date")); err == nil && received.After(date) {
+		apparentAge = received.Sub(date)
+	}
+	if seconds, err := strconv.Atoi(entry.Header.Get("Age")); err == nil {
+		// The response aged while the request was in flight
+		if correctedAge := time.Duration(seconds)*time.Second + received.Sub(requested); correctedAge > apparentAge {
+			apparentAge = correctedAge
+		}
+	}
+
+	entry.Expires = received.Add(lifetime - apparentAge)
+	entry.RequestHeader = varyHeader(entry.Header, req)
+
+	retention := lifetime - apparentAge
+	if entry.hasValidator() {
+		retention += cacheRevalidateWindow
+	}
+	if retention <= 0 {
+		t.cache.Invalidate(key)
+		return
+	}
+	t.cache.Set(key, entry, retention)
+}
+
+// storable reports whether a response may be stored
+func (t *httpCacheTransport) storable(req *http.Request, resp *http.Response) bool {
+	if !isCacheableStatus(resp.StatusCode) || resp.Header.Get("Vary") == "*" {
+		return false
+	}
+
+	directives := parseCacheControl(resp.Header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+
+	_, public := directives["public"]
+	_, sMaxAge := directives["s-maxage"]
+	if t.options.Shared {
+		if _, ok := directives["private"]; ok {
+			return false
+		}
+		_, mustRevalidate := directives["must-revalidate"]
+		if req.Header.Get("Authorization") != "" && !public && !sMaxAge && !mustRevalidate {
+			return false
+		}
+	}
+
+	// Responses need an explicit or heuristic lifetime, or a validator
+	_, maxAge := directives["max-age"]
+	_, noCache := directives["no-cache"]
+	return maxAge || sMaxAge || public || noCache || resp.Header.Get("Expires") != "" ||
+		resp.Header.Get("Last-Modified") != "" || resp.Header.Get("ETag") != ""
+}
+
+// lifetime returns how long a response is fresh from its headers: s-maxage for shared
+// caches, max-age, Expires relative to Date, or a tenth of the time since Last-Modified
+func (t *httpCacheTransport) lifetime(header http.Header, directives map[string]string) time.Duration {
+	if t.options.Shared {
+		if seconds, err := strconv.Atoi(directives["s-maxage"]); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	if value, ok := directives["max-age"]; ok {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		date = t.clock()
+	}
+	if expiresHeader := header.Get("Expires"); expiresHeader != "" {
+		// Invalid dates such as "0" mean already expired
+		expires, err := http.ParseTime(expiresHeader)
+		if err != nil || !expires.After(date) {
+			return 0
+		}
+		return expires.Sub(date)
+	}
+
+	if lastModified, err := http.ParseTime(header.Get("Last-Modified")); err == nil && date.After(lastModified) {
+		return date.Sub(lastModified) / 10
+	}
+	return 0
+}
+
+// clock returns the current time
+func (t *httpCacheTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// cachedHTTPResponse builds a response from a stored entry
+func cachedHTTPResponse(req *http.Request, entry *CacheEntry) *http.Response {
+	header := entry.Header.Clone()
+	header.Set(HTTPCacheHeader, "1")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// varyHeader returns the request header values selected by a response's Vary header
+func varyHeader(respHeader http.Header, req *http.Request) http.Header {
+	var selected http.Header
+	for _, value := range respHeader.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if selected == nil {
+				selected = http.Header{}
+			}
+			selected[name] = req.Header.Values(name)
+		}
+	}
+	return selected
+}
+
+// varyMatches reports whether a request selects the same representation as the
+// request that stored the entry
+func varyMatches(entry *CacheEntry, req *http.Request) bool {
+	for name, values := range entry.RequestHeader {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCacheControl returns the Cache-Control directives of a header, lowercased, with
+// their arguments unquoted
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+			directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+	return directives
+}
+
+// isCacheableStatus reports whether responses with a status code may be stored
+func isCacheableStatus(status int) bool {
+	switch status {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent,
+		http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusPermanentRedirect,
+		http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusGone,
+		http.StatusRequestURITooLong, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
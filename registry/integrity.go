@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+)
+
+// WithIntegrityChecks enables checks of registry response invariants: versions are
+// parseable and consistently ordered, IDs are non-empty and pagination metadata agrees
+// with the request and the returned items. Violations do not fail the call; they are
+// reported as warnings to the handlers set with WithWarningHandler or WithWarnings.
+// Checks are disabled by default and are mainly useful against private registries.
+func WithIntegrityChecks() ClientOption {
+	return func(c *ClientConfig) {
+		c.IntegrityChecks = true
+	}
+}
+
+// integrityReport collects the invariant violations found in a response
+type integrityReport struct {
+	ctx      context.Context
+	resource string
+}
+
+// violation reports a violated invariant as a warning
+func (r *integrityReport) violation(code WarningCode, format string, args ...interface{}) {
+	warn(r.ctx, Warning{
+		Code:     code,
+		Resource: r.resource,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkIntegrity runs check on a response when integrity checks are enabled
+func (c *Client) checkIntegrity(ctx context.Context, resource string, check func(r *integrityReport)) {
+	if !c.config.IntegrityChecks {
+		return
+	}
+	check(&integrityReport{ctx: ctx, resource: resource})
+}
+
+// checkID reports an empty ID
+func (r *integrityReport) checkID(kind string, index int, id string) {
+	if id == "" {
+		r.violation(WarningIntegrityID, "%s %d has an empty ID", kind, index)
+	}
+}
+
+// checkVersions reports unparseable and duplicate versions. With ordered set, it also
+// reports versions that are neither in ascending nor in descending order.
+func (r *integrityReport) checkVersions(versions []string, ordered bool) {
+	seen := make(map[string]bool, len(versions))
+	parseable := make([]string, 0, len(versions))
+
+	for _, version := range versions {
+		if !validate.Version(version) {
+			r.violation(WarningIntegrityVersion, "version %q is not a semantic version", version)
+			continue
+		}
+		normalized := NormalizeVersion(version)
+		if seen[normalized] {
+			r.violation(WarningIntegrityVersion, "version %q is listed more than once", version)
+			continue
+		}
+		seen[normalized] = true
+		parseable = append(parseable, version)
+	}
+
+	if !ordered {
+		return
+	}
+
+	ascending, descending := true, true
+	for i := 1; i < len(parseable); i++ {
+		switch CompareVersions(parseable[i-1], parseable[i]) {
+		case -1:
+			descending = false
+		case 1:
+			ascending = false
+		}
+	}
+	if !ascending && !descending {
+		r.violation(WarningIntegrityVersion, "versions are not sorted")
+	}
+}
+
+// checkModuleList checks module IDs and versions and the offset pagination metadata
+// of a module list requested at offset
+func (r *integrityReport) checkModuleList(list *ModuleList, offset int) {
+	for i, module := range list.Modules {
+		r.checkID("module", i, module.ID)
+		if module.Version != "" && !validate.Version(module.Version) {
+			r.violation(WarningIntegrityVersion, "module %q version %q is not a semantic version", module.ID, module.Version)
+		}
+	}
+
+	meta := list.Meta
+	if meta.CurrentOffset != offset {
+		r.violation(WarningIntegrityPagination, "current offset %d does not match requested offset %d", meta.CurrentOffset, offset)
+	}
+	if meta.Limit > 0 && len(list.Modules) > meta.Limit {
+		r.violation(WarningIntegrityPagination, "%d modules returned for limit %d", len(list.Modules), meta.Limit)
+	}
+	if meta.NextOffset != 0 && meta.NextOffset <= meta.CurrentOffset {
+		r.violation(WarningIntegrityPagination, "next offset %d does not advance past current offset %d", meta.NextOffset, meta.CurrentOffset)
+	}
+	if meta.NextOffset != 0 && len(list.Modules) == 0 {
+		r.violation(WarningIntegrityPagination, "next offset %d advertised for an empty page", meta.NextOffset)
+	}
+}
+
+// checkPagination checks page-based pagination metadata for a page of count items
+// requested as page (0 for the first page)
+func (r *integrityReport) checkPagination(p Pagination, page, count int) {
+	if page == 0 {
+		page = 1
+	}
+
+	if p.CurrentPage != 0 && p.CurrentPage != page {
+		r.violation(WarningIntegrityPagination, "current page %d does not match requested page %d", p.CurrentPage, page)
+	}
+	if p.PageSize > 0 && count > p.PageSize {
+		r.violation(WarningIntegrityPagination, "%d items returned for page size %d", count, p.PageSize)
+	}
+	if p.TotalPages > 0 && p.CurrentPage > p.TotalPages {
+		r.violation(WarningIntegrityPagination, "current page %d is past total pages %d", p.CurrentPage, p.TotalPages)
+	}
+	if p.NextPage != 0 && p.NextPage != p.CurrentPage+1 {
+		r.violation(WarningIntegrityPagination, "next page %d does not follow current page %d", p.NextPage, p.CurrentPage)
+	}
+	if p.NextPage != 0 && p.TotalPages > 0 && p.CurrentPage >= p.TotalPages {
+		r.violation(WarningIntegrityPagination, "next page %d advertised on the last page", p.NextPage)
+	}
+	if p.PageSize > 0 && p.TotalCount > 0 {
+		if expected := (p.TotalCount + p.PageSize - 1) / p.PageSize; p.TotalPages != expected {
+			r.violation(WarningIntegrityPagination, "total pages %d does not match %d items at page size %d", p.TotalPages, p.TotalCount, p.PageSize)
+		}
+	}
+}
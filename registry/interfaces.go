@@ -2,6 +2,10 @@ package registry
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/docparse"
 )
 
 // ProvidersServiceInterface defines the interface for provider operations
@@ -9,18 +13,75 @@ type ProvidersServiceInterface interface {
 	// List returns a list of providers
 	List(ctx context.Context, opts *ProviderListOptions) (*ProviderList, error)
 
+	// ListAll returns an iterator over all providers that fetches pages as needed
+	ListAll(ctx context.Context, opts *ProviderListOptions) *Iterator[ProviderData]
+
+	// ListFeatured returns the providers the registry marks as featured
+	ListFeatured(ctx context.Context, opts *FeaturedListOptions) ([]ProviderData, error)
+
 	// Get returns details about a specific provider
 	Get(ctx context.Context, namespace, name string) (*ProviderData, error)
 
+	// GetWithOptions returns a provider with included resources and its latest version
+	GetWithOptions(ctx context.Context, namespace, name string, opts *ProviderGetOptions) (*ProviderDetails, error)
+
+	// GetMany fetches several providers concurrently with per-item errors
+	GetMany(ctx context.Context, refs []ProviderRef) (map[ProviderRef]BatchResult[*ProviderInfo], error)
+
 	// GetLatest returns the latest version info for a provider
 	GetLatest(ctx context.Context, namespace, name string) (*ProviderLatestVersion, error)
 
+	// HasChanged reports whether the latest provider version differs from knownVersion
+	HasChanged(ctx context.Context, ref ProviderRef, knownVersion string) (bool, error)
+
 	// GetVersion returns details about a specific provider version
 	GetVersion(ctx context.Context, namespace, name, version string) (*Provider, error)
 
+	// GetInfo returns a provider version as a ProviderInfo combining the v1 and v2 APIs
+	GetInfo(ctx context.Context, ref ProviderRef) (*ProviderInfo, error)
+
+	// GetVersionDetails returns the published date, protocols, platforms and docs availability of a version
+	GetVersionDetails(ctx context.Context, ref ProviderRef, version string) (*ProviderVersionDetails, error)
+
+	// GetDownloadMetrics returns the windowed downloads of a provider and of each version
+	GetDownloadMetrics(ctx context.Context, namespace, name string) (*ProviderDownloadMetrics, error)
+
+	// RecordHistory adds a snapshot of a provider's version metadata to the history store
+	RecordHistory(ctx context.Context, ref ProviderRef) (*VersionSnapshot, error)
+
+	// TrendReport reports release frequency and download growth from the history store
+	TrendReport(ctx context.Context, ref ProviderRef, window time.Duration) (*TrendReport, error)
+
+	// GetLogo downloads and caches the provider's logo image
+	GetLogo(ctx context.Context, ref ProviderRef) (*Logo, error)
+
+	// GetDownload returns the package download URL, checksums and signing keys for a platform
+	GetDownload(ctx context.Context, namespace, name, version, os, arch string) (*ProviderDownload, error)
+
+	// GetSigningKeys returns the registry's GPG signing keys for a namespace
+	GetSigningKeys(ctx context.Context, namespace string) ([]GPGPublicKey, error)
+
+	// DownloadPackage streams a provider package and verifies its checksum and SHA256SUMS signature
+	DownloadPackage(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier, w io.Writer) (*PackageVerification, error)
+
+	// DownloadPackageFile downloads and verifies a provider package to a file, resuming interrupted downloads
+	DownloadPackageFile(ctx context.Context, download *ProviderDownload, verifier SignatureVerifier, path string) (*PackageVerification, error)
+
+	// PlanMirror works out which provider packages a mirror refresh needs to download
+	PlanMirror(ctx context.Context, ref ProviderRef, constraint string, platforms []ProviderPlatform, existing *MirrorManifest) (*MirrorPlan, error)
+
+	// GetSchema parses the resource and data source attribute schemas of a provider version from its docs
+	GetSchema(ctx context.Context, namespace, name, version string) (*ProviderSchema, error)
+
+	// GetResourceSchema parses the attribute schema of one resource or data source from its docs
+	GetResourceSchema(ctx context.Context, ref ProviderRef, category, slug string) (*ResourceSchema, error)
+
 	// ListVersions returns all versions of a provider
 	ListVersions(ctx context.Context, namespace, name string) (*ProviderVersionList, error)
 
+	// DiffVersions compares the resource, data source and function docs of two versions
+	DiffVersions(ctx context.Context, namespace, name, fromVersion, toVersion string) (*ProviderVersionDiff, error)
+
 	// GetVersionID returns the version ID for a specific provider version
 	GetVersionID(ctx context.Context, namespace, name, version string) (string, error)
 
@@ -30,12 +91,36 @@ type ProvidersServiceInterface interface {
 	// ListDocsV2 returns documentation using the v2 API with pagination support
 	ListDocsV2(ctx context.Context, opts *ProviderDocListOptions) ([]ProviderData, error)
 
+	// GetSlugIndex returns the slug to doc ID mapping for a provider version
+	GetSlugIndex(ctx context.Context, providerVersionID string) (SlugIndex, error)
+
+	// ExportDocs writes every doc of a provider version to a directory with an index manifest
+	ExportDocs(ctx context.Context, ref ProviderRef, dir string, opts *DocExportOptions) (*DocExportManifest, error)
+
 	// GetDoc returns detailed documentation for a specific provider doc
 	GetDoc(ctx context.Context, docID string) (*ProviderDocDetails, error)
 
+	// GetDocStructured returns a provider doc parsed into arguments, attributes, imports and examples
+	GetDocStructured(ctx context.Context, docID string) (*docparse.Doc, error)
+
+	// SimilarDocs returns the fetched docs most similar to a doc, for "see also" links
+	SimilarDocs(ctx context.Context, docID string, limit int) ([]SimilarDoc, error)
+
 	// GetOverviewDocs returns the overview documentation for a provider version
 	GetOverviewDocs(ctx context.Context, providerVersionID string) (string, error)
 
+	// ListFunctions returns the provider-defined functions of a provider version with parsed signatures
+	ListFunctions(ctx context.Context, providerVersionID string) ([]ProviderFunction, error)
+
+	// GetFunctionDoc returns the parsed signature of a provider-defined function doc
+	GetFunctionDoc(ctx context.Context, docID string) (*ProviderFunction, error)
+
+	// ListGuides returns the guides of a provider version
+	ListGuides(ctx context.Context, providerVersionID string) ([]ProviderData, error)
+
+	// GetGuide returns a provider guide by slug as raw Markdown and parsed sections and examples
+	GetGuide(ctx context.Context, providerVersionID, slug string) (*ProviderGuide, error)
+
 	// GetResourcesBySubcategory returns all resources for a specific subcategory
 	GetResourcesBySubcategory(ctx context.Context, providerVersionID, subcategory string) ([]ProviderData, error)
 
@@ -57,8 +142,17 @@ type ProvidersServiceInterface interface {
 	// GetDataSourcesBySubcategory returns all data sources for a specific subcategory
 	GetDataSourcesBySubcategory(ctx context.Context, providerVersionID, subcategory string) ([]ProviderData, error)
 
+	// ListSubcategories returns the subcategories used by a provider version's docs with counts
+	ListSubcategories(ctx context.Context, providerVersionID string) ([]SubcategoryCount, error)
+
 	// GetProviderResourceSummary creates a structured summary of all provider resources and data sources
 	GetProviderResourceSummary(ctx context.Context, namespace, name, version string) (*ProviderResourceSummary, error)
+
+	// GetProviderResourceSummaryWithOptions creates a provider resource summary that can resume from checkpoints
+	GetProviderResourceSummaryWithOptions(ctx context.Context, namespace, name, version string, opts *SummaryOptions) (*ProviderResourceSummary, error)
+
+	// GetResourceCounts returns per-category doc counts using only pagination metadata
+	GetResourceCounts(ctx context.Context, ref ProviderRef) (*ProviderResourceCounts, error)
 }
 
 // ModulesServiceInterface defines the interface for module operations
@@ -66,26 +160,91 @@ type ModulesServiceInterface interface {
 	// List returns a list of all modules
 	List(ctx context.Context, opts *ModuleListOptions) (*ModuleList, error)
 
+	// ListAll returns an iterator over all modules that fetches pages as needed
+	ListAll(ctx context.Context, opts *ModuleListOptions) *Iterator[Module]
+
 	// Search searches for modules based on a query string
 	Search(ctx context.Context, query string, offset int) (*ModuleList, error)
 
-	// SearchWithRelevance searches for modules and calculates relevance scores
+	// SearchWithRelevance searches for modules and calculates relevance scores.
+	//
+	// Deprecated: use SearchWithOptions.
 	SearchWithRelevance(ctx context.Context, query string, offset int) ([]ModuleSearchResult, error)
 
+	// SearchWithOptions searches for modules with relevance scores, optionally collapsing duplicates
+	SearchWithOptions(ctx context.Context, query string, opts *ModuleSearchOptions) ([]ModuleSearchResult, error)
+
+	// SearchExpanded runs several weighted queries and merges their results with combined scores
+	SearchExpanded(ctx context.Context, queries []WeightedQuery) ([]ModuleSearchResult, error)
+
 	// Get returns details about a specific module version
 	Get(ctx context.Context, namespace, name, provider, version string) (*ModuleDetails, error)
 
 	// GetByID returns details about a module using its full ID
 	GetByID(ctx context.Context, moduleID string) (*ModuleDetails, error)
 
+	// GetMany fetches several modules by ID concurrently with per-item errors
+	GetMany(ctx context.Context, ids []string) (map[string]BatchResult[*ModuleDetails], error)
+
 	// GetLatest returns the latest version of a module
 	GetLatest(ctx context.Context, namespace, name, provider string) (*ModuleDetails, error)
 
+	// DiffVersions compares the inputs, outputs, resources and provider dependencies of two versions
+	DiffVersions(ctx context.Context, namespace, name, provider, fromVersion, toVersion string) (*ModuleVersionDiff, error)
+
 	// ListVersions returns all versions of a module
 	ListVersions(ctx context.Context, namespace, name, provider string) ([]string, error)
 
+	// HasChanged reports whether the latest module version differs from knownVersion
+	HasChanged(ctx context.Context, ref ModuleRef, knownVersion string) (bool, error)
+
 	// Download returns the download URL for a module
 	Download(ctx context.Context, namespace, name, provider, version string) (string, error)
+
+	// OpenArchive follows the download source and streams the module archive, verifying its checksum
+	OpenArchive(ctx context.Context, namespace, name, provider, version string) (*ModuleArchive, error)
+
+	// DownloadArchive downloads the module archive and extracts it into a directory
+	DownloadArchive(ctx context.Context, namespace, name, provider, version, destDir string) (string, error)
+
+	// SaveArchive downloads the module archive to a file without extracting it, resuming interrupted downloads
+	SaveArchive(ctx context.Context, namespace, name, provider, version, path string) (*SavedArchive, error)
+
+	// Export writes a module version's metadata, READMEs and example code to a directory
+	Export(ctx context.Context, ref ModuleRef, dir string) (*ModuleExportManifest, error)
+
+	// GetOwnership returns the CODEOWNERS rules of a module's GitHub source repository
+	GetOwnership(ctx context.Context, ref ModuleRef) (*ModuleOwnership, error)
+
+	// GetDownloadSummary returns the weekly, monthly, yearly and all-time downloads of a module
+	GetDownloadSummary(ctx context.Context, namespace, name, provider string) (*ModuleDownloadSummary, error)
+
+	// GetMetrics returns a timestamped sample of a module's windowed download counts
+	GetMetrics(ctx context.Context, namespace, name, provider string) (*ModuleMetrics, error)
+
+	// GetVersionDownloads returns the all-time downloads of each version of a module
+	GetVersionDownloads(ctx context.Context, namespace, name, provider string) ([]ModuleVersionDownloads, error)
+
+	// RecordHistory adds a snapshot of a module's version metadata to the history store
+	RecordHistory(ctx context.Context, ref ModuleRef) (*VersionSnapshot, error)
+
+	// TrendReport reports release frequency and download growth from the history store
+	TrendReport(ctx context.Context, ref ModuleRef, window time.Duration) (*TrendReport, error)
+
+	// TopByDownloads returns the most downloaded modules, optionally within a time window
+	TopByDownloads(ctx context.Context, opts *LeaderboardOptions) ([]ModuleRanking, error)
+
+	// GetLogo downloads and caches the logo image of the module's provider
+	GetLogo(ctx context.Context, ref ModuleRef) (*Logo, error)
+
+	// NamespaceLeaderboard ranks namespaces by the downloads of their modules
+	NamespaceLeaderboard(ctx context.Context, opts *LeaderboardOptions) ([]NamespaceRanking, error)
+
+	// DetectDrift reports pinned module versions that are missing, superseded by security patches or moved
+	DetectDrift(ctx context.Context, pins []ModulePin) (*DriftReport, error)
+
+	// BuildDependencyGraph resolves a module version's transitive module and provider dependencies
+	BuildDependencyGraph(ctx context.Context, namespace, name, provider, version string, depth int) (*DependencyGraph, error)
 }
 
 // PoliciesServiceInterface defines the interface for policy operations
@@ -93,15 +252,54 @@ type PoliciesServiceInterface interface {
 	// List returns a list of policies
 	List(ctx context.Context, opts *PolicyListOptions) (*PolicyList, error)
 
+	// ListAll returns an iterator over all policies that fetches pages as needed
+	ListAll(ctx context.Context, opts *PolicyListOptions) *Iterator[Policy]
+
 	// Get returns details about a specific policy version
 	Get(ctx context.Context, namespace, name, version string) (*PolicyDetails, error)
 
 	// GetByID returns details about a policy using its full ID
 	GetByID(ctx context.Context, policyID string) (*PolicyDetails, error)
 
+	// GetMany fetches several policies by ID concurrently with per-item errors
+	GetMany(ctx context.Context, policyIDs []string) (*PolicyBatch, error)
+
 	// Search searches for policies based on a query string
 	Search(ctx context.Context, query string) ([]PolicySearchResult, error)
 
+	// SearchWithOptions searches for policies, optionally matching description and README content
+	SearchWithOptions(ctx context.Context, query string, opts *PolicySearchOptions) ([]PolicySearchResult, error)
+
 	// GetSentinelContent generates Sentinel policy content for a policy
 	GetSentinelContent(ctx context.Context, policyID string) (*SentinelPolicyContent, error)
 }
+
+// SecurityServiceInterface defines the interface for security advisory operations
+type SecurityServiceInterface interface {
+	// GetAdvisories returns the security advisories published for a provider
+	GetAdvisories(ctx context.Context, ref ProviderRef) (*AdvisoryReport, error)
+}
+
+// AnalyzeServiceInterface defines the interface for cross-cutting registry analyses
+type AnalyzeServiceInterface interface {
+	// FindDuplicates clusters search results that appear to be copies of the same module
+	FindDuplicates(ctx context.Context, query string) ([]DuplicateCluster, error)
+
+	// ProviderMaturity scores a provider on release cadence, docs coverage, downloads and tier
+	ProviderMaturity(ctx context.Context, ref ProviderRef) (*MaturityReport, error)
+
+	// Coverage reports which resources of a provider subcategory a module manages
+	Coverage(ctx context.Context, ref ModuleRef, subcategory string) (*CoverageReport, error)
+
+	// NamingConventions reports slug prefix statistics and naming outliers of a provider version
+	NamingConventions(ctx context.Context, providerVersionID string) (*NamingReport, error)
+}
+
+// AuditServiceInterface defines the interface for Terraform Cloud audit trail operations
+type AuditServiceInterface interface {
+	// FetchRegistryEvents returns the registry events recorded after cursor and the next cursor
+	FetchRegistryEvents(ctx context.Context, cursor AuditCursor) (*AuditPage, error)
+
+	// Tail polls for new registry events until ctx is done
+	Tail(ctx context.Context, cursor AuditCursor, interval time.Duration, fn func(events []AuditEvent, cursor AuditCursor) error) error
+}
@@ -0,0 +1,207 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultIteratorMaxItems is the default number of items an iterator yields before it
+	// stops with ErrMaxItemsReached
+	DefaultIteratorMaxItems = 10000
+
+	// DefaultIteratorMaxEmptyPages is the number of consecutive pages without new items an
+	// iterator fetches before it stops with ErrPaginationStalled
+	DefaultIteratorMaxEmptyPages = 10
+)
+
+var (
+	// ErrMaxItemsReached is reported by an iterator that stopped at its max items safeguard
+	// while more items were available
+	ErrMaxItemsReached = errors.New("iterator reached max items")
+
+	// ErrPaginationStalled is reported by an iterator that stopped because the registry
+	// kept advertising more pages without returning new items, such as a registry that
+	// ignores the requested offset
+	ErrPaginationStalled = errors.New("pagination stalled")
+)
+
+// pageFunc fetches the next page of an iterator and reports whether more pages follow
+type pageFunc[T any] func(ctx context.Context) (items []T, more bool, err error)
+
+// Iterator lazily pages through a list endpoint. Pages are fetched as items are consumed,
+// either with Next/Item/Err or by ranging over All:
+//
+//	it := client.Modules.ListAll(ctx, nil)
+//	for it.Next() {
+//		fmt.Println(it.Item().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// Items repeated on later pages, as happens when items are added to an offset-paged list
+// during iteration, are yielded once. An Iterator is not safe for concurrent use.
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    pageFunc[T]
+	key      func(T) string
+	maxItems int
+
+	buf        []T
+	item       T
+	count      int
+	seen       map[string]bool
+	emptyPages int
+	more       bool
+	err        error
+}
+
+// newIterator creates an iterator with the default safeguards, identifying items by key
+// to skip duplicates
+func newIterator[T any](ctx context.Context, key func(T) string, fetch pageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		key:      key,
+		maxItems: DefaultIteratorMaxItems,
+		seen:     make(map[string]bool),
+		more:     true,
+	}
+}
+
+// failedIterator returns an iterator that yields no items and reports err
+func failedIterator[T any](err error) *Iterator[T] {
+	return &Iterator[T]{err: err}
+}
+
+// SetMaxItems sets how many items the iterator yields before stopping with
+// ErrMaxItemsReached (DefaultIteratorMaxItems by default); zero or less removes the
+// limit. Call it before the first Next.
+func (it *Iterator[T]) SetMaxItems(n int) *Iterator[T] {
+	it.maxItems = n
+	return it
+}
+
+// Next advances to the next item, fetching the next page when needed. It returns false
+// when the items are exhausted or an error occurred; check Err afterwards.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.buf) == 0 {
+		if !it.more {
+			return false
+		}
+		if it.emptyPages >= DefaultIteratorMaxEmptyPages {
+			it.err = fmt.Errorf("%w: %d consecutive pages without new items", ErrPaginationStalled, it.emptyPages)
+			return false
+		}
+
+		items, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.buf, it.more = it.unseen(items), more
+		if len(it.buf) == 0 {
+			it.emptyPages++
+		} else {
+			it.emptyPages = 0
+		}
+	}
+
+	if it.maxItems > 0 && it.count >= it.maxItems {
+		it.err = fmt.Errorf("%w: stopped after %d items", ErrMaxItemsReached, it.maxItems)
+		return false
+	}
+
+	it.item = it.buf[0]
+	it.buf = it.buf[1:]
+	it.count++
+	return true
+}
+
+// unseen returns the items of a page not yielded before, and not repeated on the page
+func (it *Iterator[T]) unseen(items []T) []T {
+	fresh := items[:0:0]
+	for _, item := range items {
+		key := it.key(item)
+		if key != "" && it.seen[key] {
+			continue
+		}
+		if key != "" {
+			it.seen[key] = true
+		}
+		fresh = append(fresh, item)
+	}
+	return fresh
+}
+
+// Item returns the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All returns a range-over-func sequence of the remaining items. An error ends the
+// sequence with a final zero item and the error.
+func (it *Iterator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for it.Next() {
+			if !yield(it.Item(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Collect returns the remaining items, along with the items collected before an error
+func (it *Iterator[T]) Collect() ([]T, error) {
+	var items []T
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// nextPage returns the page after current from v2 pagination metadata, or 0 when it
+// is the last page. A next page that does not advance ends the pagination; a missing one
+// falls back to total-pages after a page that returned items.
+func nextPage(p Pagination, current, items int) int {
+	switch {
+	case p.NextPage > current:
+		return p.NextPage
+	case p.NextPage == 0 && items > 0 && current < p.TotalPages:
+		return current + 1
+	}
+	return 0
+}
+
+// nextOffset returns the offset of the next v1 page: next_offset, or the offset query
+// parameter of next_url when the registry only sends the link. Zero means no next page.
+func nextOffset(meta ModuleMeta) int {
+	if meta.NextOffset != 0 || meta.NextURL == "" {
+		return meta.NextOffset
+	}
+	u, err := url.Parse(meta.NextURL)
+	if err != nil {
+		return 0
+	}
+	offset, _ := strconv.Atoi(u.Query().Get("offset"))
+	return offset
+}
@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// latestVersion requests a version listing and returns the latest version that latest
+// finds in its body. The listing's ETag or Last-Modified validator is kept with the
+// version, so the next check of the same listing is a conditional request that the
+// registry answers with 304 Not Modified and no body while nothing was published.
+func (c *Client) latestVersion(req *http.Request, latest func(body []byte) (string, error)) (string, error) {
+	key := req.URL.String()
+	known, ok := c.latestVersions.Get(key)
+	if ok {
+		setConditionalHeaders(req, known)
+	}
+
+	// Hosts in an announced maintenance window fail fast until it ends
+	if err := c.maintenance.active(req.URL.Host); err != nil {
+		return "", err
+	}
+
+	if c.offline == nil {
+		if err := c.waitForToken(req); err != nil {
+			return "", fmt.Errorf("rate limit error: %w", timeoutError(err))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", &RequestError{
+			Method: req.Method,
+			URL:    key,
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+	defer resp.Body.Close()
+	c.observeRateLimit(resp)
+
+	reader, err := c.limitBody(resp)
+	if err != nil {
+		return "", err
+	}
+	body, err := io.ReadAll(reader)
+	if IsResponseTooLarge(err) {
+		return "", &ResponseError{StatusCode: resp.StatusCode, Err: err}
+	}
+	if err != nil {
+		return "", &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error reading response body: %w", timeoutError(err)),
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		return string(known.Body), nil
+	}
+	if maintenance := MaintenanceFromResponse(resp, body); maintenance != nil {
+		c.maintenance.record(maintenance)
+		return "", maintenance
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", newAPIError(resp, body)
+	}
+
+	captureRaw(req.Context(), RawResponse{
+		Method:     req.Method,
+		URL:        key,
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	})
+
+	version, err := latest(body)
+	if err != nil {
+		return "", &ResponseError{StatusCode: resp.StatusCode, Err: err}
+	}
+
+	entry := &CacheEntry{
+		Body:         []byte(version),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if entry.hasValidator() {
+		c.latestVersions.Set(key, entry, 0)
+	} else {
+		c.latestVersions.Invalidate(key)
+	}
+	return version, nil
+}
@@ -0,0 +1,397 @@
+package registry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+)
+
+// DownloadWindow selects the period download counts are ranked by
+type DownloadWindow string
+
+const (
+	// DownloadWindowTotal ranks by all-time downloads
+	DownloadWindowTotal DownloadWindow = ""
+
+	// DownloadWindowWeek ranks by downloads in the last week
+	DownloadWindowWeek DownloadWindow = "week"
+
+	// DownloadWindowMonth ranks by downloads in the last month
+	DownloadWindowMonth DownloadWindow = "month"
+
+	// DownloadWindowYear ranks by downloads in the last year
+	DownloadWindowYear DownloadWindow = "year"
+)
+
+const (
+	// DefaultLeaderboardLimit is the default number of leaderboard entries
+	DefaultLeaderboardLimit = 10
+
+	// DefaultLeaderboardMaxPages is the default number of module listing pages scanned
+	DefaultLeaderboardMaxPages = 10
+
+	// leaderboardPageSize is the page size used when scanning module listings
+	leaderboardPageSize = 100
+
+	// windowCandidateFactor is how many candidates per entry are ranked by windowed downloads
+	windowCandidateFactor = 3
+)
+
+// LeaderboardOptions specifies optional parameters to leaderboard methods
+type LeaderboardOptions struct {
+	// Limit is the number of entries to return (default 10, max 100)
+	Limit int
+
+	// Namespace restricts the scan to modules in a namespace
+	Namespace string
+
+	// Provider restricts the scan to modules for a provider
+	Provider string
+
+	// Verified restricts the scan to verified modules
+	Verified bool
+
+	// Window ranks modules by downloads in a time window instead of all-time downloads.
+	// Windowed counts need one download summary request per candidate module and are
+	// not available for namespace leaderboards.
+	Window DownloadWindow
+
+	// MaxPages caps the number of module listing pages scanned (default 10)
+	MaxPages int
+}
+
+// Validate validates the leaderboard options
+func (o *LeaderboardOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if o.Limit < 0 || o.Limit > 100 {
+		return &ValidationError{
+			Field:   "Limit",
+			Value:   o.Limit,
+			Message: "limit must be between 0 and 100",
+		}
+	}
+
+	if o.Namespace != "" && !validate.Namespace(o.Namespace) {
+		return &ValidationError{
+			Field:   "Namespace",
+			Value:   o.Namespace,
+			Message: "invalid namespace format",
+		}
+	}
+
+	if o.Provider != "" && !validate.ProviderName(o.Provider) {
+		return &ValidationError{
+			Field:   "Provider",
+			Value:   o.Provider,
+			Message: "invalid provider name format",
+		}
+	}
+
+	switch o.Window {
+	case DownloadWindowTotal, DownloadWindowWeek, DownloadWindowMonth, DownloadWindowYear:
+	default:
+		return &ValidationError{
+			Field:   "Window",
+			Value:   o.Window,
+			Message: "window must be one of: week, month, year, or empty for all-time",
+		}
+	}
+
+	if o.MaxPages < 0 {
+		return &ValidationError{
+			Field:   "MaxPages",
+			Value:   o.MaxPages,
+			Message: "max pages cannot be negative",
+		}
+	}
+
+	return nil
+}
+
+// limit returns the number of entries to return
+func (o *LeaderboardOptions) limit() int {
+	if o == nil || o.Limit == 0 {
+		return DefaultLeaderboardLimit
+	}
+	return o.Limit
+}
+
+// maxPages returns the number of listing pages to scan
+func (o *LeaderboardOptions) maxPages() int {
+	if o == nil || o.MaxPages == 0 {
+		return DefaultLeaderboardMaxPages
+	}
+	return o.MaxPages
+}
+
+// ModuleDownloadSummary holds the download counts of a module over several windows
+type ModuleDownloadSummary struct {
+	Week  int64 `json:"week"`
+	Month int64 `json:"month"`
+	Year  int64 `json:"year"`
+	Total int64 `json:"total"`
+}
+
+// Downloads returns the download count for a window
+func (s ModuleDownloadSummary) Downloads(window DownloadWindow) int64 {
+	switch window {
+	case DownloadWindowWeek:
+		return s.Week
+	case DownloadWindowMonth:
+		return s.Month
+	case DownloadWindowYear:
+		return s.Year
+	default:
+		return s.Total
+	}
+}
+
+// ModuleRanking is a module leaderboard entry
+type ModuleRanking struct {
+	// Rank is the 1-based position in the leaderboard
+	Rank int
+
+	// Module is the module as listed by the registry
+	Module Module
+
+	// Downloads is the download count in the ranked window
+	Downloads int64
+
+	// Summary holds the windowed download counts; nil when ranking by all-time downloads
+	Summary *ModuleDownloadSummary
+}
+
+// NamespaceRanking is a namespace leaderboard entry
+type NamespaceRanking struct {
+	// Rank is the 1-based position in the leaderboard
+	Rank int
+
+	// Namespace is the module namespace
+	Namespace string
+
+	// Modules is the number of scanned modules in the namespace
+	Modules int
+
+	// Verified is the number of verified modules in the namespace
+	Verified int
+
+	// Downloads is the all-time download count across the namespace's modules
+	Downloads int64
+}
+
+// GetDownloadSummary returns the weekly, monthly, yearly and all-time downloads of a module
+func (s *ModulesService) GetDownloadSummary(ctx context.Context, namespace, name, provider string) (*ModuleDownloadSummary, error) {
+	if err := validateModuleParams(namespace, name, provider, ""); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("modules/%s/%s/%s/downloads/summary",
+		url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(provider))
+
+	var result struct {
+		Data struct {
+			Attributes ModuleDownloadSummary `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := s.client.get(ctx, path, "v1", &result); err != nil {
+		return nil, fmt.Errorf("failed to get download summary for %s/%s/%s: %w", namespace, name, provider, err)
+	}
+
+	return &result.Data.Attributes, nil
+}
+
+// TopByDownloads returns the most downloaded modules among at most opts.MaxPages listing
+// pages. With a time window, the top candidates by all-time downloads are re-ranked by
+// their windowed downloads; candidates whose summary cannot be fetched are left out and
+// reported in the returned MultiError alongside the remaining entries.
+func (s *ModulesService) TopByDownloads(ctx context.Context, opts *LeaderboardOptions) ([]ModuleRanking, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	modules, err := s.scanModules(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(modules, func(a, b Module) int {
+		return compareDownloads(a.Downloads, b.Downloads, moduleKey(a), moduleKey(b))
+	})
+
+	limit := opts.limit()
+
+	var window DownloadWindow
+	if opts != nil {
+		window = opts.Window
+	}
+
+	if window == DownloadWindowTotal {
+		rankings := make([]ModuleRanking, 0, min(limit, len(modules)))
+		for i, module := range modules[:min(limit, len(modules))] {
+			rankings = append(rankings, ModuleRanking{Rank: i + 1, Module: module, Downloads: module.Downloads})
+		}
+		return rankings, nil
+	}
+
+	candidates := modules[:min(limit*windowCandidateFactor, len(modules))]
+	byKey := make(map[string]Module, len(candidates))
+	keys := make([]string, 0, len(candidates))
+	for _, module := range candidates {
+		key := moduleKey(module)
+		byKey[key] = module
+		keys = append(keys, key)
+	}
+
+	summaries := runBatch(ctx, keys, DefaultBatchConcurrency, func(ctx context.Context, key string) (*ModuleDownloadSummary, error) {
+		module := byKey[key]
+		return s.GetDownloadSummary(ctx, module.Namespace, module.Name, module.Provider)
+	})
+
+	var errs MultiError
+	rankings := make([]ModuleRanking, 0, len(keys))
+	for _, key := range keys {
+		result := summaries[key]
+		if result.Err != nil {
+			errs.Add(result.Err)
+			continue
+		}
+		rankings = append(rankings, ModuleRanking{
+			Module:    byKey[key],
+			Downloads: result.Value.Downloads(window),
+			Summary:   result.Value,
+		})
+	}
+
+	slices.SortFunc(rankings, func(a, b ModuleRanking) int {
+		return compareDownloads(a.Downloads, b.Downloads, moduleKey(a.Module), moduleKey(b.Module))
+	})
+
+	rankings = rankings[:min(limit, len(rankings))]
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+
+	return rankings, errs.ErrorOrNil()
+}
+
+// NamespaceLeaderboard ranks namespaces by the all-time downloads of their modules among
+// at most opts.MaxPages listing pages
+func (s *ModulesService) NamespaceLeaderboard(ctx context.Context, opts *LeaderboardOptions) ([]NamespaceRanking, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if opts != nil && opts.Window != DownloadWindowTotal {
+		return nil, &ValidationError{
+			Field:   "Window",
+			Value:   opts.Window,
+			Message: "namespace leaderboards only support all-time downloads",
+		}
+	}
+
+	modules, err := s.scanModules(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	byNamespace := make(map[string]*NamespaceRanking)
+	for _, module := range modules {
+		ranking, ok := byNamespace[module.Namespace]
+		if !ok {
+			ranking = &NamespaceRanking{Namespace: module.Namespace}
+			byNamespace[module.Namespace] = ranking
+		}
+		ranking.Modules++
+		ranking.Downloads += module.Downloads
+		if module.Verified {
+			ranking.Verified++
+		}
+	}
+
+	rankings := make([]NamespaceRanking, 0, len(byNamespace))
+	for _, ranking := range byNamespace {
+		rankings = append(rankings, *ranking)
+	}
+
+	slices.SortFunc(rankings, func(a, b NamespaceRanking) int {
+		return compareDownloads(a.Downloads, b.Downloads, a.Namespace, b.Namespace)
+	})
+
+	rankings = rankings[:min(opts.limit(), len(rankings))]
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+
+	return rankings, nil
+}
+
+// scanModules lists modules matching opts, one module per namespace/name/provider,
+// reading at most opts.MaxPages pages
+func (s *ModulesService) scanModules(ctx context.Context, opts *LeaderboardOptions) ([]Module, error) {
+	base := "modules"
+	values := url.Values{}
+	values.Set("limit", strconv.Itoa(leaderboardPageSize))
+
+	if opts != nil {
+		if opts.Namespace != "" {
+			base = "modules/" + url.PathEscape(opts.Namespace)
+		}
+		if opts.Provider != "" {
+			values.Set("provider", opts.Provider)
+		}
+		if opts.Verified {
+			values.Set("verified", "true")
+		}
+	}
+
+	seen := make(map[string]bool)
+	var modules []Module
+
+	offset := 0
+	for page := 0; page < opts.maxPages(); page++ {
+		values.Set("offset", strconv.Itoa(offset))
+
+		var result ModuleList
+		if err := s.client.get(ctx, base+"?"+values.Encode(), "v1", &result); err != nil {
+			return nil, fmt.Errorf("failed to list modules: %w", err)
+		}
+
+		for _, module := range result.Modules {
+			key := moduleKey(module)
+			if !seen[key] {
+				seen[key] = true
+				modules = append(modules, module)
+			}
+		}
+
+		next := nextOffset(result.Meta)
+		if next <= offset || len(result.Modules) == 0 {
+			break
+		}
+		offset = next
+	}
+
+	return modules, nil
+}
+
+// moduleKey returns the version-independent identity of a module
+func moduleKey(m Module) string {
+	return m.Namespace + "/" + m.Name + "/" + m.Provider
+}
+
+// compareDownloads orders by descending downloads, then ascending key for stable output
+func compareDownloads(a, b int64, keyA, keyB string) int {
+	if c := cmp.Compare(b, a); c != 0 {
+		return c
+	}
+	return cmp.Compare(keyA, keyB)
+}
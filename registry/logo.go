@@ -0,0 +1,205 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxLogoSize is the default size limit of downloaded logo images
+const DefaultMaxLogoSize = 1 << 20
+
+// logoCacheSize is the number of logos a client keeps, least recently used first out
+const logoCacheSize = 50
+
+var (
+	// ErrNoLogo is returned when a provider or module has no logo URL
+	ErrNoLogo = errors.New("no logo available")
+
+	// ErrLogoTooLarge is returned when a logo image exceeds the size limit
+	ErrLogoTooLarge = errors.New("logo exceeds size limit")
+)
+
+// Logo is a downloaded provider or module logo image
+type Logo struct {
+	// URL is the absolute URL the logo was downloaded from
+	URL string
+
+	// ContentType is the image media type, such as "image/png" or "image/svg+xml"
+	ContentType string
+
+	// Data is the image content
+	Data []byte
+}
+
+// WithMaxLogoSize sets the size limit of downloaded logo images in bytes
+func WithMaxLogoSize(bytes int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxLogoSize = bytes
+	}
+}
+
+// GetLogo downloads the logo of a provider. The version of ref is ignored. The most
+// recently used logos are cached by URL.
+func (s *ProvidersService) GetLogo(ctx context.Context, ref ProviderRef) (*Logo, error) {
+	provider, err := s.Get(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if provider.Attributes.LogoURL == "" {
+		return nil, fmt.Errorf("%w for provider %s/%s", ErrNoLogo, ref.Namespace, ref.Name)
+	}
+
+	return s.client.logos.get(ctx, s.client, provider.Attributes.LogoURL)
+}
+
+// GetLogo downloads the logo of a module's provider (provider_logo_url). The most
+// recently used logos are cached by URL.
+func (s *ModulesService) GetLogo(ctx context.Context, ref ModuleRef) (*Logo, error) {
+	var (
+		module *ModuleDetails
+		err    error
+	)
+	if ref.Version == "" || ref.Version == "latest" {
+		module, err = s.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	} else {
+		module, err = s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if module.ProviderLogoURL == "" {
+		return nil, fmt.Errorf("%w for module %s", ErrNoLogo, ref)
+	}
+
+	return s.client.logos.get(ctx, s.client, module.ProviderLogoURL)
+}
+
+// logoCache holds the most recently used logos by absolute URL
+type logoCache struct {
+	once  sync.Once
+	logos *MemoryCache
+}
+
+// get returns the logo at logoURL, downloading it on first use. Relative URLs are
+// resolved against the client's base URL.
+func (lc *logoCache) get(ctx context.Context, c *Client, logoURL string) (*Logo, error) {
+	base, err := url.Parse(c.GetBaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	ref, err := url.Parse(logoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logo URL %q: %w", logoURL, err)
+	}
+	resolved := base.ResolveReference(ref).String()
+
+	lc.once.Do(func() {
+		lc.logos = NewMemoryCache(logoCacheSize)
+	})
+
+	if entry, ok := lc.logos.Get(resolved); ok {
+		return &Logo{
+			URL:         resolved,
+			ContentType: entry.Header.Get("Content-Type"),
+			Data:        append([]byte(nil), entry.Body...),
+		}, nil
+	}
+
+	// Logos served by the registry itself use its rate limit budget
+	logo, err := c.downloadLogo(ctx, resolved, base.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.logos.Set(resolved, &CacheEntry{
+		Body:   append([]byte(nil), logo.Data...),
+		Header: http.Header{"Content-Type": []string{logo.ContentType}},
+	}, 0)
+
+	return logo, nil
+}
+
+// downloadLogo fetches an image, enforcing the size limit and an image content type.
+// Requests to registryHost wait for rate limit budget like API requests.
+func (c *Client) downloadLogo(ctx context.Context, logoURL, registryHost string) (*Logo, error) {
+	limit := c.config.MaxLogoSize
+	if limit <= 0 {
+		limit = DefaultMaxLogoSize
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logoURL, nil)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    logoURL,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	limited := req.URL.Host == registryHost
+	if limited && c.offline == nil {
+		if err := c.waitForToken(req); err != nil {
+			return nil, fmt.Errorf("rate limit error: %w", timeoutError(err))
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &RequestError{
+			Method: http.MethodGet,
+			URL:    logoURL,
+			Err:    fmt.Errorf("error performing request: %w", timeoutError(unwrapURLError(err))),
+		}
+	}
+	defer resp.Body.Close()
+	if limited {
+		c.observeRateLimit(resp)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("failed to download logo %s", logoURL),
+			Headers:    resp.Header,
+		}
+	}
+
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrLogoTooLarge, logoURL, resp.ContentLength, limit)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error reading logo: %w", timeoutError(err)),
+		}
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrLogoTooLarge, logoURL, limit)
+	}
+
+	contentType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(contentType, "image/") {
+		contentType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("logo %s is %q, not an image", logoURL, contentType),
+		}
+	}
+
+	return &Logo{URL: logoURL, ContentType: contentType, Data: data}, nil
+}
@@ -0,0 +1,168 @@
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMaintenance is returned when the registry answers that it is under maintenance
+var ErrMaintenance = errors.New("registry is under maintenance")
+
+// maxMaintenancePeek bounds how much of a 503 body is read to recognize a maintenance page
+const maxMaintenancePeek = 8192
+
+// htmlTitle matches the title of an HTML maintenance page
+var htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// MaintenanceError reports that the registry is in a maintenance window: a 503 response
+// with an HTML page, as served by load balancers during maintenance, or with a body that
+// mentions maintenance. Maintenance responses are not retried, and requests to the host
+// fail fast with the same error until the announced retry time has passed.
+type MaintenanceError struct {
+	// StatusCode is the HTTP status of the maintenance response
+	StatusCode int
+
+	// Host is the registry host under maintenance
+	Host string
+
+	// Message is the title or text of the maintenance page, when it has one
+	Message string
+
+	// RetryAfter is the delay announced with Retry-After, zero when none was given
+	RetryAfter time.Duration
+
+	// Until is when the registry expects to be back, zero when unknown
+	Until time.Time
+}
+
+// Error implements the error interface
+func (e *MaintenanceError) Error() string {
+	msg := fmt.Sprintf("registry %s is under maintenance (status %d)", e.Host, e.StatusCode)
+	if !e.Until.IsZero() {
+		msg += fmt.Sprintf(", retry after %s", e.Until.Format(time.RFC3339))
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is implements error matching; maintenance responses are also server errors
+func (e *MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenance || target == ErrServerError
+}
+
+// Unwrap returns ErrMaintenance
+func (e *MaintenanceError) Unwrap() error {
+	return ErrMaintenance
+}
+
+// IsMaintenance returns true if the registry is under maintenance
+func IsMaintenance(err error) bool {
+	return errors.Is(err, ErrMaintenance)
+}
+
+// MaintenanceFromResponse returns the maintenance window announced by a response and
+// its body, or nil when the response is not a maintenance response
+func MaintenanceFromResponse(resp *http.Response, body []byte) *MaintenanceError {
+	if !isMaintenanceResponse(resp, body) {
+		return nil
+	}
+
+	err := &MaintenanceError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		err.Host = resp.Request.URL.Host
+	}
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		err.RetryAfter = wait
+		err.Until = time.Now().Add(wait)
+	}
+
+	if match := htmlTitle.FindSubmatch(body); match != nil {
+		err.Message = strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
+	} else if !looksLikeHTML(resp, body) {
+		err.Message = truncateString(strings.Join(strings.Fields(string(body)), " "), 200)
+	}
+
+	return err
+}
+
+// isMaintenanceResponse reports whether a response is a maintenance page: a 503 with an
+// HTML body, or with any body mentioning maintenance
+func isMaintenanceResponse(resp *http.Response, body []byte) bool {
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	return looksLikeHTML(resp, body) || bytes.Contains(bytes.ToLower(body), []byte("maintenance"))
+}
+
+// looksLikeHTML reports whether a response body is an HTML page rather than an API error
+func looksLikeHTML(resp *http.Response, body []byte) bool {
+	if strings.HasPrefix(strings.ToLower(resp.Header.Get("Content-Type")), "text/html") {
+		return true
+	}
+	trimmed := bytes.ToLower(bytes.TrimSpace(body))
+	return bytes.HasPrefix(trimmed, []byte("<!doctype html")) || bytes.HasPrefix(trimmed, []byte("<html"))
+}
+
+// peekBody returns up to n bytes of a response body and puts them back in front of the
+// rest, so the body can still be read in full
+func peekBody(resp *http.Response, n int64) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	head, _ := io.ReadAll(io.LimitReader(resp.Body, n))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	return head
+}
+
+// maintenanceWindows tracks hosts in an announced maintenance window, whose requests
+// fail fast until the window ends
+type maintenanceWindows struct {
+	mu    sync.Mutex
+	hosts map[string]*MaintenanceError
+}
+
+// record remembers a maintenance window with a known end
+func (w *maintenanceWindows) record(err *MaintenanceError) {
+	if err.Until.IsZero() {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hosts == nil {
+		w.hosts = make(map[string]*MaintenanceError)
+	}
+	w.hosts[err.Host] = err
+}
+
+// active returns the maintenance window a host is in, or nil
+func (w *maintenanceWindows) active(host string) *MaintenanceError {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err, ok := w.hosts[host]
+	if !ok {
+		return nil
+	}
+	if !time.Now().Before(err.Until) {
+		delete(w.hosts, host)
+		return nil
+	}
+
+	active := *err
+	active.RetryAfter = time.Until(err.Until)
+	return &active
+}
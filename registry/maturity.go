@@ -0,0 +1,280 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maturity grades assigned by ProviderMaturity
+const (
+	MaturityMature       = "mature"
+	MaturityEstablished  = "established"
+	MaturityEmerging     = "emerging"
+	MaturityExperimental = "experimental"
+)
+
+// Weights of the maturity dimensions in the overall score; they sum to 1
+const (
+	maturityCadenceWeight   = 0.30
+	maturityDocsWeight      = 0.25
+	maturityDownloadsWeight = 0.25
+	maturityTierWeight      = 0.20
+)
+
+// MaturityReport is a scored assessment of how production-ready a provider is.
+// Dimension scores range from 0 to 1; Score is their weighted sum scaled to 0-100.
+type MaturityReport struct {
+	Provider    ProviderRef `json:"provider"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Score       float64     `json:"score"`
+	Grade       string      `json:"grade"`
+
+	Cadence   CadenceScore   `json:"cadence"`
+	Docs      DocsScore      `json:"docs"`
+	Downloads DownloadsScore `json:"downloads"`
+	Tier      TierScore      `json:"tier"`
+}
+
+// CadenceScore rates how regularly a provider publishes releases
+type CadenceScore struct {
+	Score             float64   `json:"score"`
+	TotalVersions     int       `json:"total_versions"`
+	ReleasesLastYear  int       `json:"releases_last_year"`
+	LastReleaseAt     time.Time `json:"last_release_at,omitempty"`
+	DaysSinceRelease  int       `json:"days_since_release"`
+	MedianReleaseDays float64   `json:"median_release_days"`
+}
+
+// DocsScore rates the documentation published for the assessed version
+type DocsScore struct {
+	Score       float64 `json:"score"`
+	Resources   int     `json:"resources"`
+	DataSources int     `json:"data_sources"`
+	Guides      int     `json:"guides"`
+}
+
+// DownloadsScore rates provider adoption and whether recent versions are being picked up
+type DownloadsScore struct {
+	Score float64 `json:"score"`
+	Total int64   `json:"total"`
+
+	// RecentShare is the share of version downloads going to versions published in the last year
+	RecentShare float64 `json:"recent_share"`
+}
+
+// TierScore rates the provider tier
+type TierScore struct {
+	Score float64 `json:"score"`
+	Tier  string  `json:"tier"`
+}
+
+// ProviderMaturity scores a provider on release cadence, docs coverage, download trend
+// and tier, for use when approving providers for production. An empty ref.Version
+// assesses the docs of the latest version.
+func (s *AnalyzeService) ProviderMaturity(ctx context.Context, ref ProviderRef) (*MaturityReport, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.client.Providers.ListVersions(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Version == "" || ref.Version == "latest" {
+		latest := ""
+		for _, v := range versions.Included {
+			if latest == "" || CompareVersions(v.Attributes.Version, latest) > 0 {
+				latest = v.Attributes.Version
+			}
+		}
+		if latest == "" {
+			return nil, fmt.Errorf("%w for provider %s/%s", ErrNoVersions, ref.Namespace, ref.Name)
+		}
+		ref.Version = latest
+	}
+
+	counts, err := s.client.Providers.GetResourceCounts(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get docs coverage: %w", err)
+	}
+
+	return buildMaturityReport(ref, versions, counts, time.Now().UTC()), nil
+}
+
+// buildMaturityReport scores the collected provider data as of now
+func buildMaturityReport(ref ProviderRef, versions *ProviderVersionList, counts *ProviderResourceCounts, now time.Time) *MaturityReport {
+	report := &MaturityReport{
+		Provider:    ref,
+		GeneratedAt: now,
+		Cadence:     scoreCadence(versions.Included, now),
+		Docs:        scoreDocs(counts),
+		Downloads:   scoreDownloads(versions.Data.Attributes.Downloads, versions.Included, now),
+		Tier:        scoreTier(versions.Data.Attributes.Tier),
+	}
+
+	score := report.Cadence.Score*maturityCadenceWeight +
+		report.Docs.Score*maturityDocsWeight +
+		report.Downloads.Score*maturityDownloadsWeight +
+		report.Tier.Score*maturityTierWeight
+	report.Score = math.Round(score*1000) / 10
+
+	switch {
+	case report.Score >= 80:
+		report.Grade = MaturityMature
+	case report.Score >= 60:
+		report.Grade = MaturityEstablished
+	case report.Score >= 40:
+		report.Grade = MaturityEmerging
+	default:
+		report.Grade = MaturityExperimental
+	}
+
+	return report
+}
+
+// scoreCadence rewards a recent last release and a steady release rate, up to one per month
+func scoreCadence(versions []VersionData, now time.Time) CadenceScore {
+	cadence := CadenceScore{TotalVersions: len(versions)}
+
+	var published []time.Time
+	for _, v := range versions {
+		if !v.Attributes.PublishedAt.IsZero() {
+			published = append(published, v.Attributes.PublishedAt)
+		}
+	}
+	if len(published) == 0 {
+		return cadence
+	}
+
+	sort.Slice(published, func(i, j int) bool { return published[i].Before(published[j]) })
+
+	cadence.LastReleaseAt = published[len(published)-1]
+	cadence.DaysSinceRelease = int(now.Sub(cadence.LastReleaseAt).Hours() / 24)
+
+	yearAgo := now.AddDate(-1, 0, 0)
+	var intervals []float64
+	for i, t := range published {
+		if t.After(yearAgo) {
+			cadence.ReleasesLastYear++
+		}
+		if i > 0 {
+			intervals = append(intervals, t.Sub(published[i-1]).Hours()/24)
+		}
+	}
+
+	if len(intervals) > 0 {
+		sort.Float64s(intervals)
+		mid := len(intervals) / 2
+		if len(intervals)%2 == 0 {
+			cadence.MedianReleaseDays = (intervals[mid-1] + intervals[mid]) / 2
+		} else {
+			cadence.MedianReleaseDays = intervals[mid]
+		}
+	}
+
+	recency := 0.0
+	switch {
+	case cadence.DaysSinceRelease <= 90:
+		recency = 1.0
+	case cadence.DaysSinceRelease <= 365:
+		recency = 0.5
+	}
+	rate := math.Min(float64(cadence.ReleasesLastYear)/12, 1)
+
+	cadence.Score = 0.5*recency + 0.5*rate
+	return cadence
+}
+
+// scoreDocs rewards documented resources or data sources and, up to three, guides
+func scoreDocs(counts *ProviderResourceCounts) DocsScore {
+	docs := DocsScore{
+		Resources:   counts.Resources,
+		DataSources: counts.DataSources,
+		Guides:      counts.Guides,
+	}
+
+	if docs.Resources+docs.DataSources > 0 {
+		docs.Score += 0.6
+	}
+	docs.Score += 0.4 * math.Min(float64(docs.Guides)/3, 1)
+
+	return docs
+}
+
+// scoreDownloads combines total downloads on a log scale with the uptake of recent versions
+func scoreDownloads(total int64, versions []VersionData, now time.Time) DownloadsScore {
+	downloads := DownloadsScore{Total: total}
+
+	yearAgo := now.AddDate(-1, 0, 0)
+	var all, recent int64
+	for _, v := range versions {
+		all += int64(v.Attributes.Downloads)
+		if v.Attributes.PublishedAt.After(yearAgo) {
+			recent += int64(v.Attributes.Downloads)
+		}
+	}
+	if all > 0 {
+		downloads.RecentShare = float64(recent) / float64(all)
+	}
+
+	volume := 0.0
+	if total > 0 {
+		volume = logScale(float64(total), 1, 1000000000, 0, 1)
+	}
+
+	downloads.Score = 0.5*volume + 0.5*downloads.RecentShare
+	return downloads
+}
+
+// scoreTier rates official providers highest, then partner and community providers
+func scoreTier(tier string) TierScore {
+	score := 0.2
+	switch strings.ToLower(tier) {
+	case "official":
+		score = 1.0
+	case "partner":
+		score = 0.8
+	case "community":
+		score = 0.4
+	}
+	return TierScore{Score: score, Tier: tier}
+}
+
+// JSON returns the report as indented JSON
+func (r *MaturityReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Markdown returns the report as a Markdown document
+func (r *MaturityReport) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Provider maturity: %s\n\n", r.Provider)
+	fmt.Fprintf(&b, "**Score:** %.1f / 100 (%s)\n\n", r.Score, r.Grade)
+	fmt.Fprintf(&b, "| Dimension | Score | Details |\n")
+	fmt.Fprintf(&b, "|-----------|-------|---------|\n")
+	fmt.Fprintf(&b, "| Release cadence | %.2f | %d releases in the last year, last release %d days ago |\n",
+		r.Cadence.Score, r.Cadence.ReleasesLastYear, r.Cadence.DaysSinceRelease)
+	fmt.Fprintf(&b, "| Docs coverage | %.2f | %d resources, %d data sources, %d guides |\n",
+		r.Docs.Score, r.Docs.Resources, r.Docs.DataSources, r.Docs.Guides)
+	fmt.Fprintf(&b, "| Download trend | %.2f | %d total, %.0f%% on versions from the last year |\n",
+		r.Downloads.Score, r.Downloads.Total, r.Downloads.RecentShare*100)
+	fmt.Fprintf(&b, "| Tier | %.2f | %s |\n", r.Tier.Score, valueOrUnknown(r.Tier.Tier))
+	fmt.Fprintf(&b, "\n_Generated %s_\n", r.GeneratedAt.Format(time.RFC3339))
+
+	return b.String()
+}
+
+// valueOrUnknown returns value, or "unknown" when it is empty
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
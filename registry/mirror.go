@@ -0,0 +1,271 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// ArtifactKind identifies the kind of data being requested from a registry
+type ArtifactKind string
+
+const (
+	// ArtifactDocs is provider documentation
+	ArtifactDocs ArtifactKind = "docs"
+
+	// ArtifactDownloads is provider package downloads
+	ArtifactDownloads ArtifactKind = "downloads"
+
+	// ArtifactMetadata is provider metadata such as versions and tiers
+	ArtifactMetadata ArtifactKind = "metadata"
+)
+
+// Registry names used by NewPublicMirrorResolver
+const (
+	// RegistryPublic is the name of the public registry
+	RegistryPublic = "public"
+
+	// RegistryMirror is the name of the mirror registry
+	RegistryMirror = "mirror"
+)
+
+// MirrorResolver maps provider identities across several configured registries
+// (for example the public registry and an internal mirror) and selects which
+// registry serves each kind of artifact.
+type MirrorResolver struct {
+	mu sync.RWMutex
+
+	// registries holds the clients by registry name
+	registries map[string]*Client
+
+	// order is the registration order, used when no preference is configured
+	order []string
+
+	// preferences lists registry names in preferred order per artifact kind
+	preferences map[ArtifactKind][]string
+
+	// identities maps a canonical "namespace/name" to its identity per registry
+	identities map[string]map[string]ProviderRef
+
+	// known caches positive existence probes keyed by registry and ref
+	known map[string]bool
+}
+
+// ResolvedProvider is the result of resolving a provider against the configured registries
+type ResolvedProvider struct {
+	// Registry is the name of the registry that serves the artifact
+	Registry string
+
+	// Client is the client for that registry
+	Client *Client
+
+	// Ref is the provider identity within that registry
+	Ref ProviderRef
+}
+
+// NewMirrorResolver creates an empty resolver
+func NewMirrorResolver() *MirrorResolver {
+	return &MirrorResolver{
+		registries:  make(map[string]*Client),
+		preferences: make(map[ArtifactKind][]string),
+		identities:  make(map[string]map[string]ProviderRef),
+		known:       make(map[string]bool),
+	}
+}
+
+// NewPublicMirrorResolver creates a resolver that prefers the mirror for downloads
+// and the public registry for docs and metadata, falling back to the other registry
+func NewPublicMirrorResolver(public, mirror *Client) (*MirrorResolver, error) {
+	r := NewMirrorResolver()
+
+	if err := r.AddRegistry(RegistryPublic, public); err != nil {
+		return nil, err
+	}
+	if err := r.AddRegistry(RegistryMirror, mirror); err != nil {
+		return nil, err
+	}
+
+	r.preferences[ArtifactDownloads] = []string{RegistryMirror, RegistryPublic}
+	r.preferences[ArtifactDocs] = []string{RegistryPublic, RegistryMirror}
+	r.preferences[ArtifactMetadata] = []string{RegistryPublic, RegistryMirror}
+
+	return r, nil
+}
+
+// AddRegistry registers a named registry client
+func (r *MirrorResolver) AddRegistry(name string, client *Client) error {
+	if name == "" {
+		return &ValidationError{
+			Field:   "name",
+			Message: "registry name cannot be empty",
+		}
+	}
+
+	if client == nil {
+		return &ValidationError{
+			Field:   "client",
+			Value:   name,
+			Message: "client cannot be nil",
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.registries[name]; !exists {
+		r.order = append(r.order, name)
+	}
+	r.registries[name] = client
+
+	return nil
+}
+
+// SetPreference sets the order in which registries are tried for an artifact kind
+func (r *MirrorResolver) SetPreference(kind ArtifactKind, registryNames ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range registryNames {
+		if _, exists := r.registries[name]; !exists {
+			return &ValidationError{
+				Field:   "registryNames",
+				Value:   name,
+				Message: "unknown registry",
+			}
+		}
+	}
+
+	r.preferences[kind] = append([]string(nil), registryNames...)
+	return nil
+}
+
+// MapIdentity records that the canonical provider is known as ref within the named registry.
+// Mirrors commonly re-home providers under a different namespace.
+func (r *MirrorResolver) MapIdentity(canonical ProviderRef, registryName string, ref ProviderRef) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := identityKey(canonical)
+	if r.identities[key] == nil {
+		r.identities[key] = make(map[string]ProviderRef)
+	}
+	r.identities[key][registryName] = ProviderRef{Namespace: ref.Namespace, Name: ref.Name}
+}
+
+// Identity returns the identity of the canonical provider within the named registry.
+// Unmapped providers keep their canonical namespace and name. The version is preserved.
+func (r *MirrorResolver) Identity(canonical ProviderRef, registryName string) ProviderRef {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if mapped, ok := r.identities[identityKey(canonical)][registryName]; ok {
+		mapped.Version = canonical.Version
+		return mapped
+	}
+
+	return canonical
+}
+
+// Resolve returns the first registry, in preference order for the artifact kind,
+// that has the provider (and the version, when one is given)
+func (r *MirrorResolver) Resolve(ctx context.Context, kind ArtifactKind, canonical ProviderRef) (*ResolvedProvider, error) {
+	if err := validateProviderParams(canonical.Namespace, canonical.Name); err != nil {
+		return nil, err
+	}
+
+	candidates := r.candidates(kind)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no registries configured", ErrInvalidConfiguration)
+	}
+
+	var errs MultiError
+	for _, name := range candidates {
+		r.mu.RLock()
+		client := r.registries[name]
+		r.mu.RUnlock()
+
+		ref := r.Identity(canonical, name)
+
+		if err := r.probe(ctx, name, client, ref); err != nil {
+			errs.Add(fmt.Errorf("registry %s: %w", name, err))
+			continue
+		}
+
+		return &ResolvedProvider{
+			Registry: name,
+			Client:   client,
+			Ref:      ref,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("provider %s not available for %s: %w", canonical, kind, errs.ErrorOrNil())
+}
+
+// candidates returns the registry names to try for an artifact kind
+func (r *MirrorResolver) candidates(kind ArtifactKind) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if preferred, ok := r.preferences[kind]; ok && len(preferred) > 0 {
+		return preferred
+	}
+	return append([]string(nil), r.order...)
+}
+
+// probe checks that the provider (and version, if set) exists in a registry using the
+// v1 provider registry protocol, which both the public registry and mirrors implement
+func (r *MirrorResolver) probe(ctx context.Context, registryName string, client *Client, ref ProviderRef) error {
+	key := registryName + "|" + ref.String()
+
+	r.mu.RLock()
+	known := r.known[key]
+	r.mu.RUnlock()
+	if known {
+		return nil
+	}
+
+	path := fmt.Sprintf("providers/%s/%s/versions", url.PathEscape(ref.Namespace), url.PathEscape(ref.Name))
+
+	var result struct {
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	}
+
+	if err := client.get(ctx, path, "v1", &result); err != nil {
+		return err
+	}
+
+	if ref.Version != "" && ref.Version != "latest" {
+		found := false
+		for _, v := range result.Versions {
+			if v.Version == ref.Version {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &APIError{
+				StatusCode: 404,
+				Message:    fmt.Sprintf("provider version %s not found", ref),
+			}
+		}
+	} else if len(result.Versions) == 0 {
+		return &APIError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("provider %s has no versions", ref),
+		}
+	}
+
+	r.mu.Lock()
+	r.known[key] = true
+	r.mu.Unlock()
+
+	return nil
+}
+
+// identityKey returns the canonical map key for a provider identity
+func identityKey(ref ProviderRef) string {
+	return ref.Namespace + "/" + ref.Name
+}
@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// MirrorPackage is a provider package: one version built for one platform
+type MirrorPackage struct {
+	Version  string
+	Platform ProviderPlatform
+}
+
+// String returns the package as "version os_arch"
+func (p MirrorPackage) String() string {
+	return p.Version + " " + p.Platform.OS + "_" + p.Platform.Arch
+}
+
+// MirrorManifest lists the provider packages already present in a local mirror
+type MirrorManifest struct {
+	Packages []MirrorPackage
+}
+
+// Add records a package as present
+func (m *MirrorManifest) Add(version string, platform ProviderPlatform) {
+	m.Packages = append(m.Packages, MirrorPackage{Version: version, Platform: platform})
+}
+
+// Has reports whether a package is present
+func (m *MirrorManifest) Has(version string, platform ProviderPlatform) bool {
+	if m == nil {
+		return false
+	}
+	for _, p := range m.Packages {
+		if NormalizeVersion(p.Version) == NormalizeVersion(version) && p.Platform == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// MirrorPlan is the work needed to bring a provider mirror up to date
+type MirrorPlan struct {
+	Provider   ProviderRef
+	Constraint string
+
+	// Versions are the published versions matching the constraint, newest first
+	Versions []string
+
+	// Download are the matching packages missing from the mirror, newest version first
+	Download []MirrorPackage
+
+	// Present are the matching packages the mirror already has
+	Present []MirrorPackage
+
+	// Unavailable are requested platforms the registry does not publish for a matching version
+	Unavailable []MirrorPackage
+
+	// Stale are mirrored packages outside the constraint or requested platforms, which a
+	// refresh may prune
+	Stale []MirrorPackage
+}
+
+// PlanMirror works out which provider packages to download to refresh a mirror. It
+// lists the published versions matching constraint (every release when empty) with
+// one request and compares their packages for the requested platforms (all published
+// platforms when none are given) against the existing manifest, so an incremental
+// refresh only downloads what is missing. The version of ref is ignored.
+func (s *ProvidersService) PlanMirror(ctx context.Context, ref ProviderRef, constraint string, platforms []ProviderPlatform, existing *MirrorManifest) (*MirrorPlan, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	parsed, err := ParseVersionConstraint(constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("providers/%s/%s/versions", url.PathEscape(ref.Namespace), url.PathEscape(ref.Name))
+
+	var result struct {
+		Versions []struct {
+			Version   string             `json:"version"`
+			Platforms []ProviderPlatform `json:"platforms"`
+		} `json:"versions"`
+	}
+	if err := s.client.get(ctx, path, "v1", &result); err != nil {
+		return nil, fmt.Errorf("failed to list provider packages: %w", err)
+	}
+
+	sort.SliceStable(result.Versions, func(i, j int) bool {
+		return CompareVersions(result.Versions[i].Version, result.Versions[j].Version) > 0
+	})
+
+	plan := &MirrorPlan{
+		Provider:   ProviderRef{Namespace: ref.Namespace, Name: ref.Name},
+		Constraint: parsed.String(),
+	}
+	wanted := make(map[MirrorPackage]bool)
+
+	for _, version := range result.Versions {
+		if !parsed.Check(version.Version) {
+			continue
+		}
+		plan.Versions = append(plan.Versions, version.Version)
+
+		published := make(map[ProviderPlatform]bool, len(version.Platforms))
+		for _, platform := range version.Platforms {
+			published[platform] = true
+		}
+
+		targets := platforms
+		if len(targets) == 0 {
+			targets = version.Platforms
+		}
+
+		for _, platform := range targets {
+			pkg := MirrorPackage{Version: version.Version, Platform: platform}
+			switch {
+			case !published[platform]:
+				plan.Unavailable = append(plan.Unavailable, pkg)
+			case existing.Has(version.Version, platform):
+				plan.Present = append(plan.Present, pkg)
+				wanted[mirrorPackageKey(pkg)] = true
+			default:
+				plan.Download = append(plan.Download, pkg)
+			}
+		}
+	}
+
+	if existing != nil {
+		for _, pkg := range existing.Packages {
+			if !wanted[mirrorPackageKey(pkg)] {
+				plan.Stale = append(plan.Stale, pkg)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// mirrorPackageKey normalizes a package for comparisons
+func mirrorPackageKey(p MirrorPackage) MirrorPackage {
+	p.Version = NormalizeVersion(p.Version)
+	return p
+}
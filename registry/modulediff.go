@@ -0,0 +1,290 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ModuleChangeKind is how an input, output, resource or provider dependency of a module
+// differs between two versions
+type ModuleChangeKind string
+
+const (
+	// ModuleChangeAdded is an item only present in the newer version
+	ModuleChangeAdded ModuleChangeKind = "added"
+
+	// ModuleChangeRemoved is an item only present in the older version
+	ModuleChangeRemoved ModuleChangeKind = "removed"
+
+	// ModuleChangeType is an input whose type changed
+	ModuleChangeType ModuleChangeKind = "type_changed"
+
+	// ModuleChangeDefault is an input whose default value changed
+	ModuleChangeDefault ModuleChangeKind = "default_changed"
+
+	// ModuleChangeRequired is an input that became required or optional
+	ModuleChangeRequired ModuleChangeKind = "required_changed"
+
+	// ModuleChangeConstraint is a provider dependency whose version constraint changed
+	ModuleChangeConstraint ModuleChangeKind = "constraint_changed"
+)
+
+// ModuleChange is a single difference between two module versions
+type ModuleChange struct {
+	// Name is the input or output name, the resource address such as
+	// "aws_vpc.this", or the provider address such as "hashicorp/aws"
+	Name string           `json:"name"`
+	Kind ModuleChangeKind `json:"kind"`
+
+	// From and To are the old and new type, default, required flag or constraint. Added
+	// and removed provider dependencies carry their constraint; other added and removed
+	// items have neither.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	// Breaking is set on changes that can break callers of the module: removed inputs
+	// and outputs, new required inputs, inputs becoming required or changing type, and
+	// removed resources, which are destroyed on upgrade
+	Breaking bool `json:"breaking"`
+}
+
+// ModuleVersionDiff is the difference between the root modules of two module versions
+type ModuleVersionDiff struct {
+	Module      ModuleRef `json:"module"`
+	FromVersion string    `json:"from_version"`
+	ToVersion   string    `json:"to_version"`
+
+	// Changes are ordered by name, then kind
+	Inputs    []ModuleChange `json:"inputs,omitempty"`
+	Outputs   []ModuleChange `json:"outputs,omitempty"`
+	Resources []ModuleChange `json:"resources,omitempty"`
+	Providers []ModuleChange `json:"providers,omitempty"`
+}
+
+// Empty reports whether the versions have the same interface
+func (d *ModuleVersionDiff) Empty() bool {
+	return len(d.Inputs) == 0 && len(d.Outputs) == 0 && len(d.Resources) == 0 && len(d.Providers) == 0
+}
+
+// Breaking returns the breaking changes
+func (d *ModuleVersionDiff) Breaking() []ModuleChange {
+	var breaking []ModuleChange
+	for _, changes := range [][]ModuleChange{d.Inputs, d.Outputs, d.Resources, d.Providers} {
+		for _, change := range changes {
+			if change.Breaking {
+				breaking = append(breaking, change)
+			}
+		}
+	}
+	return breaking
+}
+
+// JSON returns the diff as indented JSON
+func (d *ModuleVersionDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// DiffVersions compares the root module interface of two versions of a module: its
+// inputs, outputs, resources and provider dependency constraints. Use it to assess
+// breaking changes before bumping a module version; changes that can break callers are
+// marked Breaking. An empty or "latest" toVersion uses the latest version.
+func (s *ModulesService) DiffVersions(ctx context.Context, namespace, name, provider, fromVersion, toVersion string) (*ModuleVersionDiff, error) {
+	if fromVersion == "" || fromVersion == "latest" {
+		return nil, &ValidationError{
+			Field:   "fromVersion",
+			Value:   fromVersion,
+			Message: "the version to compare from must be a specific version",
+		}
+	}
+
+	from, err := s.Get(ctx, namespace, name, provider, fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s: %w", fromVersion, err)
+	}
+
+	var to *ModuleDetails
+	if toVersion == "" || toVersion == "latest" {
+		to, err = s.GetLatest(ctx, namespace, name, provider)
+	} else {
+		to, err = s.Get(ctx, namespace, name, provider, toVersion)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %s: %w", toVersion, err)
+	}
+
+	diff := DiffModuleParts(from.Root, to.Root)
+	diff.Module = ModuleRef{Namespace: namespace, Name: name, Provider: provider}
+	diff.FromVersion = from.Version
+	diff.ToVersion = to.Version
+	return diff, nil
+}
+
+// DiffModuleParts compares the interface of two module parts, such as the root modules
+// of two versions or a root module and a local copy
+func DiffModuleParts(from, to ModulePart) *ModuleVersionDiff {
+	return &ModuleVersionDiff{
+		Inputs:    diffInputs(from.Inputs, to.Inputs),
+		Outputs:   diffOutputs(from.Outputs, to.Outputs),
+		Resources: diffResources(from.Resources, to.Resources),
+		Providers: diffProviderDependencies(from.ProviderDependencies, to.ProviderDependencies),
+	}
+}
+
+// diffInputs compares input variables
+func diffInputs(from, to []ModuleInput) []ModuleChange {
+	old := make(map[string]ModuleInput, len(from))
+	for _, input := range from {
+		old[input.Name] = input
+	}
+
+	var changes []ModuleChange
+	for _, input := range to {
+		previous, ok := old[input.Name]
+		if !ok {
+			changes = append(changes, ModuleChange{Name: input.Name, Kind: ModuleChangeAdded, Breaking: input.Required})
+			continue
+		}
+		delete(old, input.Name)
+
+		if previous.Type != input.Type {
+			changes = append(changes, ModuleChange{
+				Name:     input.Name,
+				Kind:     ModuleChangeType,
+				From:     previous.Type,
+				To:       input.Type,
+				Breaking: true,
+			})
+		}
+		if previous.Required != input.Required {
+			changes = append(changes, ModuleChange{
+				Name:     input.Name,
+				Kind:     ModuleChangeRequired,
+				From:     strconv.FormatBool(previous.Required),
+				To:       strconv.FormatBool(input.Required),
+				Breaking: input.Required,
+			})
+		}
+		if oldDefault, newDefault := compactJSON(previous.Default), compactJSON(input.Default); oldDefault != newDefault {
+			changes = append(changes, ModuleChange{
+				Name: input.Name,
+				Kind: ModuleChangeDefault,
+				From: oldDefault,
+				To:   newDefault,
+			})
+		}
+	}
+	for name := range old {
+		changes = append(changes, ModuleChange{Name: name, Kind: ModuleChangeRemoved, Breaking: true})
+	}
+
+	sortModuleChanges(changes)
+	return changes
+}
+
+// diffOutputs compares output values
+func diffOutputs(from, to []ModuleOutput) []ModuleChange {
+	names := func(outputs []ModuleOutput) []string {
+		result := make([]string, len(outputs))
+		for i, output := range outputs {
+			result[i] = output.Name
+		}
+		return result
+	}
+	return diffNames(names(from), names(to))
+}
+
+// diffResources compares resources by address
+func diffResources(from, to []ModuleResource) []ModuleChange {
+	addresses := func(resources []ModuleResource) []string {
+		result := make([]string, len(resources))
+		for i, resource := range resources {
+			result[i] = resource.Type + "." + resource.Name
+		}
+		return result
+	}
+	return diffNames(addresses(from), addresses(to))
+}
+
+// diffNames reports added and removed names; removals are breaking
+func diffNames(from, to []string) []ModuleChange {
+	old := make(map[string]bool, len(from))
+	for _, name := range from {
+		old[name] = true
+	}
+
+	var changes []ModuleChange
+	current := make(map[string]bool, len(to))
+	for _, name := range to {
+		if current[name] {
+			continue
+		}
+		current[name] = true
+		if !old[name] {
+			changes = append(changes, ModuleChange{Name: name, Kind: ModuleChangeAdded})
+		}
+	}
+	for name := range old {
+		if !current[name] {
+			changes = append(changes, ModuleChange{Name: name, Kind: ModuleChangeRemoved, Breaking: true})
+		}
+	}
+
+	sortModuleChanges(changes)
+	return changes
+}
+
+// diffProviderDependencies compares provider dependencies by address and constraint
+func diffProviderDependencies(from, to []ModuleProviderDependency) []ModuleChange {
+	old := make(map[string]string, len(from))
+	for _, dep := range from {
+		old[providerDependencyAddress(dep)] = dep.Version
+	}
+
+	var changes []ModuleChange
+	current := make(map[string]bool, len(to))
+	for _, dep := range to {
+		address := providerDependencyAddress(dep)
+		current[address] = true
+		constraint, ok := old[address]
+		switch {
+		case !ok:
+			changes = append(changes, ModuleChange{Name: address, Kind: ModuleChangeAdded, To: dep.Version})
+		case constraint != dep.Version:
+			changes = append(changes, ModuleChange{Name: address, Kind: ModuleChangeConstraint, From: constraint, To: dep.Version})
+		}
+	}
+	for address, constraint := range old {
+		if !current[address] {
+			changes = append(changes, ModuleChange{Name: address, Kind: ModuleChangeRemoved, From: constraint})
+		}
+	}
+
+	sortModuleChanges(changes)
+	return changes
+}
+
+// sortModuleChanges orders changes by name, then kind
+func sortModuleChanges(changes []ModuleChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+}
+
+// compactJSON returns a JSON value without insignificant whitespace, or "" for none
+func compactJSON(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(value)
+	}
+	return buf.String()
+}
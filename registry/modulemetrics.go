@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// ModuleMetrics is a sample of the download counts of a module. Samples taken over time,
+// keyed by FetchedAt, make up a popularity series.
+type ModuleMetrics struct {
+	// Module identifies the module
+	Module ModuleRef
+
+	// Downloads holds the weekly, monthly, yearly and all-time downloads
+	Downloads ModuleDownloadSummary
+
+	// FetchedAt is when the counts were read
+	FetchedAt time.Time
+}
+
+// ModuleVersionDownloads is the all-time download count of one module version
+type ModuleVersionDownloads struct {
+	Version     string
+	Downloads   int64
+	PublishedAt time.Time
+}
+
+// GetMetrics returns the current download counts of a module over each window, stamped
+// with the time they were read
+func (s *ModulesService) GetMetrics(ctx context.Context, namespace, name, provider string) (*ModuleMetrics, error) {
+	if s.client.isOpenTofu() {
+		return nil, s.client.unsupported("module download metrics")
+	}
+
+	summary, err := s.GetDownloadSummary(ctx, namespace, name, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ModuleMetrics{
+		Module:    ModuleRef{Namespace: namespace, Name: name, Provider: provider},
+		Downloads: *summary,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// GetVersionDownloads returns the all-time downloads of each version of a module from
+// the v2 module versions listing, newest version first
+func (s *ModulesService) GetVersionDownloads(ctx context.Context, namespace, name, provider string) ([]ModuleVersionDownloads, error) {
+	if err := validateModuleParams(namespace, name, provider, ""); err != nil {
+		return nil, err
+	}
+
+	if s.client.isOpenTofu() {
+		return nil, s.client.unsupported("module download metrics")
+	}
+
+	path := fmt.Sprintf("modules/%s/%s/%s?include=module-versions",
+		url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(provider))
+
+	var result struct {
+		Included []struct {
+			Type       string `json:"type"`
+			Attributes struct {
+				Version     string    `json:"version"`
+				Downloads   int64     `json:"downloads"`
+				PublishedAt time.Time `json:"published-at"`
+			} `json:"attributes"`
+		} `json:"included"`
+	}
+
+	if err := s.client.get(ctx, path, "v2", &result); err != nil {
+		return nil, fmt.Errorf("failed to get version downloads for %s/%s/%s: %w", namespace, name, provider, err)
+	}
+
+	versions := make([]ModuleVersionDownloads, 0, len(result.Included))
+	for _, included := range result.Included {
+		if included.Type != "module-versions" || included.Attributes.Version == "" {
+			continue
+		}
+		versions = append(versions, ModuleVersionDownloads{
+			Version:     included.Attributes.Version,
+			Downloads:   included.Attributes.Downloads,
+			PublishedAt: included.Attributes.PublishedAt,
+		})
+	}
+
+	slices.SortFunc(versions, func(a, b ModuleVersionDownloads) int {
+		return CompareVersions(b.Version, a.Version)
+	})
+
+	return versions, nil
+}
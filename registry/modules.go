@@ -2,17 +2,26 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"net/url"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
 )
 
 // ModulesService handles communication with the module related
 // methods of the Terraform Registry API.
 type ModulesService struct {
 	client *Client
+
+	// flights coalesces identical GetMany requests in flight
+	flights flightGroup
 }
 
 // ModuleListOptions specifies optional parameters to module list methods
@@ -23,11 +32,23 @@ type ModuleListOptions struct {
 	// Limit specifies the number of items to return (max 100)
 	Limit int `url:"limit,omitempty"`
 
+	// Namespace lists the modules of one namespace. With WithOrganization, an empty
+	// Namespace lists the organization's private modules.
+	Namespace string `url:"-"`
+
 	// Provider filters modules by provider
 	Provider string `url:"provider,omitempty"`
 
 	// Verified filters to only show verified modules
 	Verified bool `url:"verified,omitempty"`
+
+	// PublishedAfter keeps modules published at or after this time. The registry cannot
+	// filter by date, so it is applied client-side to the fetched page; modules with an
+	// unknown publish time are left out. Zero means no bound.
+	PublishedAfter time.Time `url:"-"`
+
+	// PublishedBefore keeps modules published before this time, applied like PublishedAfter
+	PublishedBefore time.Time `url:"-"`
 }
 
 // Validate validates the module list options
@@ -52,7 +73,15 @@ func (o *ModuleListOptions) Validate() error {
 		}
 	}
 
-	if o.Provider != "" && !isValidProviderName(o.Provider) {
+	if o.Namespace != "" && !validate.Namespace(o.Namespace) {
+		return &ValidationError{
+			Field:   "Namespace",
+			Value:   o.Namespace,
+			Message: "invalid namespace format",
+		}
+	}
+
+	if o.Provider != "" && !validate.ProviderName(o.Provider) {
 		return &ValidationError{
 			Field:   "Provider",
 			Value:   o.Provider,
@@ -60,7 +89,7 @@ func (o *ModuleListOptions) Validate() error {
 		}
 	}
 
-	return nil
+	return validatePublishedRange(o.PublishedAfter, o.PublishedBefore)
 }
 
 // List returns a list of all modules
@@ -69,7 +98,19 @@ func (s *ModulesService) List(ctx context.Context, opts *ModuleListOptions) (*Mo
 		return nil, err
 	}
 
+	namespace := s.client.config.Organization
+	if opts != nil && opts.Namespace != "" {
+		namespace = opts.Namespace
+	}
+
+	if s.client.isOpenTofu() && !s.client.isPrivateNamespace(namespace) {
+		return nil, s.client.unsupported("listing modules")
+	}
+
 	path := "modules"
+	if namespace != "" {
+		path = "modules/" + url.PathEscape(namespace)
+	}
 	if opts != nil {
 		values := url.Values{}
 		if opts.Offset > 0 {
@@ -92,13 +133,55 @@ func (s *ModulesService) List(ctx context.Context, opts *ModuleListOptions) (*Mo
 	}
 
 	var result ModuleList
-	if err := s.client.get(ctx, path, "v1", &result); err != nil {
+	if err := s.get(ctx, namespace, path, &result); err != nil {
 		return nil, fmt.Errorf("failed to list modules: %w", err)
 	}
 
+	offset := 0
+	if opts != nil {
+		offset = opts.Offset
+	}
+	s.client.checkIntegrity(ctx, "modules", func(r *integrityReport) {
+		r.checkModuleList(&result, offset)
+	})
+
+	if opts != nil {
+		result.Modules = filterPublished(result.Modules, opts.PublishedAfter, opts.PublishedBefore)
+	}
+
 	return &result, nil
 }
 
+// ListAll returns an iterator over all modules matching opts, following the offset
+// pagination from opts.Offset. Pages hold opts.Limit modules, or 100 when unset.
+func (s *ModulesService) ListAll(ctx context.Context, opts *ModuleListOptions) *Iterator[Module] {
+	if err := opts.Validate(); err != nil {
+		return failedIterator[Module](err)
+	}
+
+	page := ModuleListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Limit == 0 {
+		page.Limit = 100
+	}
+
+	moduleID := func(m Module) string { return m.ID }
+	return newIterator(ctx, moduleID, func(ctx context.Context) ([]Module, bool, error) {
+		list, err := s.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Stop unless the offset advances, so a misbehaving registry cannot loop forever
+		next := nextOffset(list.Meta)
+		more := next > page.Offset
+		page.Offset = next
+		return list.Modules, more, nil
+	})
+}
+
 // Search searches for modules based on a query string
 func (s *ModulesService) Search(ctx context.Context, query string, offset int) (*ModuleList, error) {
 	if query == "" {
@@ -117,6 +200,10 @@ func (s *ModulesService) Search(ctx context.Context, query string, offset int) (
 		}
 	}
 
+	if s.client.isOpenTofu() {
+		return s.searchOpenTofu(ctx, query, offset)
+	}
+
 	path := fmt.Sprintf("modules/search?q=%s&offset=%d", url.QueryEscape(query), offset)
 
 	var result ModuleList
@@ -124,6 +211,10 @@ func (s *ModulesService) Search(ctx context.Context, query string, offset int) (
 		return nil, fmt.Errorf("failed to search modules: %w", err)
 	}
 
+	s.client.checkIntegrity(ctx, "modules/search?q="+query, func(r *integrityReport) {
+		r.checkModuleList(&result, offset)
+	})
+
 	return &result, nil
 }
 
@@ -133,14 +224,37 @@ func (s *ModulesService) Get(ctx context.Context, namespace, name, provider, ver
 		return nil, err
 	}
 
-	moduleID := fmt.Sprintf("%s/%s/%s/%s", namespace, name, provider, version)
+	return s.getDetails(ctx, fmt.Sprintf("%s/%s/%s/%s", namespace, name, provider, version))
+}
+
+// getDetails fetches the details of a module version, or of the latest version when
+// moduleID has no version
+func (s *ModulesService) getDetails(ctx context.Context, moduleID string) (*ModuleDetails, error) {
 	path := fmt.Sprintf("modules/%s", moduleID)
 
+	namespace, _, _ := strings.Cut(moduleID, "/")
+
 	var result ModuleDetails
-	if err := s.client.get(ctx, path, "v1", &result); err != nil {
+	if s.client.isOpenTofu() && !s.client.isPrivateNamespace(namespace) {
+		details, err := s.getOpenTofuDetails(ctx, moduleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get module %s: %w", moduleID, err)
+		}
+		result = *details
+	} else if err := s.get(ctx, namespace, path, &result); err != nil {
 		return nil, fmt.Errorf("failed to get module %s: %w", moduleID, err)
 	}
 
+	s.client.checkIntegrity(ctx, moduleID, func(r *integrityReport) {
+		r.checkID("module", 0, result.ID)
+		r.checkVersions(result.Versions, true)
+	})
+
+	attachReadmeDependencies(&result.Root)
+	for i := range result.Submodules {
+		attachReadmeDependencies(&result.Submodules[i])
+	}
+
 	return &result, nil
 }
 
@@ -184,7 +298,7 @@ func (s *ModulesService) ListVersions(ctx context.Context, namespace, name, prov
 		} `json:"modules"`
 	}
 
-	if err := s.client.get(ctx, path, "v1", &resp); err != nil {
+	if err := s.get(ctx, namespace, path, &resp); err != nil {
 		return nil, fmt.Errorf("failed to list module versions: %w", err)
 	}
 
@@ -202,14 +316,20 @@ func (s *ModulesService) ListVersions(ctx context.Context, namespace, name, prov
 		}
 	}
 
+	s.client.checkIntegrity(ctx, fmt.Sprintf("%s/%s/%s", namespace, name, provider), func(r *integrityReport) {
+		r.checkVersions(versions, false)
+	})
+
 	if len(versions) == 0 {
-		return nil, fmt.Errorf("no versions found for module %s/%s/%s", namespace, name, provider)
+		return nil, fmt.Errorf("%w for module %s/%s/%s", ErrNoVersions, namespace, name, provider)
 	}
 
 	return versions, nil
 }
 
-// GetLatest returns the latest version of a module
+// GetLatest returns the latest version of a module. When the registry lists no versions
+// for the module, the latest version is taken from the module endpoint or, failing that,
+// from search, and a WarningLatestFallback warning is reported.
 func (s *ModulesService) GetLatest(ctx context.Context, namespace, name, provider string) (*ModuleDetails, error) {
 	if err := validateModuleParams(namespace, name, provider, ""); err != nil {
 		return nil, err
@@ -217,6 +337,9 @@ func (s *ModulesService) GetLatest(ctx context.Context, namespace, name, provide
 
 	// Use ListVersions to get all versions, then pick the greatest semver
 	versions, err := s.ListVersions(ctx, namespace, name, provider)
+	if errors.Is(err, ErrNoVersions) || IsNotFound(err) {
+		return s.getLatestFallback(ctx, namespace, name, provider, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -232,20 +355,133 @@ func (s *ModulesService) GetLatest(ctx context.Context, namespace, name, provide
 	return s.Get(ctx, namespace, name, provider, latest)
 }
 
+// getLatestFallback resolves the latest version of a module whose versions could not be
+// listed, first from the module endpoint, which serves the latest version, then from the
+// current version in search results. It returns listErr when neither knows the module.
+func (s *ModulesService) getLatestFallback(ctx context.Context, namespace, name, provider string, listErr error) (*ModuleDetails, error) {
+	ref := fmt.Sprintf("%s/%s/%s", namespace, name, provider)
+
+	details, err := s.getDetails(ctx, ref)
+	if err == nil && details.Version != "" {
+		warn(ctx, Warning{
+			Code:     WarningLatestFallback,
+			Resource: ref,
+			Message:  fmt.Sprintf("no versions listed; using latest version %s from the module endpoint", details.Version),
+			Err:      listErr,
+		})
+		return details, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	results, err := s.Search(ctx, name, 0)
+	if err != nil {
+		return nil, listErr
+	}
+	for _, module := range results.Modules {
+		if module.Namespace != namespace || module.Name != name || module.Provider != provider || module.Version == "" {
+			continue
+		}
+		details, err := s.Get(ctx, namespace, name, provider, module.Version)
+		if err != nil {
+			return nil, err
+		}
+		warn(ctx, Warning{
+			Code:     WarningLatestFallback,
+			Resource: ref,
+			Message:  fmt.Sprintf("no versions listed; using current version %s from search", module.Version),
+			Err:      listErr,
+		})
+		return details, nil
+	}
+
+	return nil, listErr
+}
+
+// HasChanged reports whether the latest version of a module differs from knownVersion.
+// It makes a single request for the module's version list, without its README or
+// inputs, so it is suitable for polling many watched modules; repeated checks are
+// conditional requests answered with 304 Not Modified while nothing was published. An
+// empty knownVersion always reports a change without a request.
+func (s *ModulesService) HasChanged(ctx context.Context, ref ModuleRef, knownVersion string) (bool, error) {
+	if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ""); err != nil {
+		return false, err
+	}
+
+	if knownVersion == "" {
+		return true, nil
+	}
+
+	// The versions endpoint is served by every module registry, including OpenTofu and
+	// private registries
+	path := fmt.Sprintf("modules/%s/%s/%s/versions",
+		url.PathEscape(ref.Namespace), url.PathEscape(ref.Name), url.PathEscape(ref.Provider))
+
+	var req *http.Request
+	var err error
+	if s.client.isPrivateNamespace(ref.Namespace) {
+		req, err = s.client.newURLRequest(ctx, s.client.privateRegistryURL(path), s.client.privateToken())
+	} else {
+		req, err = s.client.newRequest(ctx, http.MethodGet, path, "v1", nil)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	latest, err := s.client.latestVersion(req, func(body []byte) (string, error) {
+		var result struct {
+			Modules []struct {
+				Versions []struct {
+					Version string `json:"version"`
+				} `json:"versions"`
+			} `json:"modules"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("error decoding response: %w", err)
+		}
+		latest := ""
+		for _, module := range result.Modules {
+			for _, version := range module.Versions {
+				if latest == "" || CompareVersions(version.Version, latest) > 0 {
+					latest = version.Version
+				}
+			}
+		}
+		if latest == "" {
+			return "", ErrNoVersions
+		}
+		return latest, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check module %s: %w", ref, err)
+	}
+
+	return NormalizeVersion(latest) != NormalizeVersion(knownVersion), nil
+}
+
 // Download returns the download URL for a module
 func (s *ModulesService) Download(ctx context.Context, namespace, name, provider, version string) (string, error) {
 	if err := validateModuleParams(namespace, name, provider, version); err != nil {
 		return "", err
 	}
 
+	ref := ModuleRef{Namespace: namespace, Name: name, Provider: provider, Version: version}
+	if err := s.checkModuleDownload(ctx, "Modules.Download", ref); err != nil {
+		return "", err
+	}
+
 	// Verify the module exists
 	if _, err := s.Get(ctx, namespace, name, provider, version); err != nil {
 		return "", fmt.Errorf("failed to verify module exists: %w", err)
 	}
 
 	// The download URL follows a specific pattern
-	downloadURL := fmt.Sprintf("%s/v1/modules/%s/%s/%s/%s/download",
-		s.client.baseURL, namespace, name, provider, version)
+	downloadPath := fmt.Sprintf("modules/%s/%s/%s/%s/download", namespace, name, provider, version)
+	if s.client.isPrivateNamespace(namespace) {
+		return s.client.privateRegistryURL(downloadPath), nil
+	}
+	downloadURL := fmt.Sprintf("%s/v1/%s", s.client.GetBaseURL(), downloadPath)
 
 	return downloadURL, nil
 }
@@ -254,10 +490,57 @@ func (s *ModulesService) Download(ctx context.Context, namespace, name, provider
 type ModuleSearchResult struct {
 	Module
 	Relevance float64 // Calculated relevance score
+
+	// Collapsed is the number of other entries for the same module merged into this one
+	// by CollapseModuleResults
+	Collapsed int
+}
+
+// ModuleSearchOptions specifies optional parameters to the SearchWithOptions method
+type ModuleSearchOptions struct {
+	// Offset specifies the offset for pagination
+	Offset int
+
+	// Collapse merges entries for the same namespace/name/provider into one canonical entry
+	Collapse bool
+
+	// PublishedAfter keeps results published at or after this time, applied client-side
+	// before collapsing; results with an unknown publish time are left out. Zero means no bound.
+	PublishedAfter time.Time
+
+	// PublishedBefore keeps results published before this time, applied like PublishedAfter
+	PublishedBefore time.Time
+}
+
+// Validate validates the module search options
+func (o *ModuleSearchOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if o.Offset < 0 {
+		return &ValidationError{
+			Field:   "Offset",
+			Value:   o.Offset,
+			Message: "offset cannot be negative",
+		}
+	}
+
+	return validatePublishedRange(o.PublishedAfter, o.PublishedBefore)
 }
 
-// SearchWithRelevance searches for modules and calculates relevance scores
+// SearchWithRelevance searches for modules and calculates relevance scores.
+//
+// Deprecated: use SearchWithOptions with ModuleSearchOptions.Offset, which scores
+// results the same way.
 func (s *ModulesService) SearchWithRelevance(ctx context.Context, query string, offset int) ([]ModuleSearchResult, error) {
+	s.client.warnDeprecated("Modules.SearchWithRelevance", "Modules.SearchWithOptions")
+	return s.searchWithRelevance(ctx, query, offset)
+}
+
+// searchWithRelevance searches for modules and scores the results by their match with
+// the query, verification, downloads and recency
+func (s *ModulesService) searchWithRelevance(ctx context.Context, query string, offset int) ([]ModuleSearchResult, error) {
 	result, err := s.Search(ctx, query, offset)
 	if err != nil {
 		return nil, err
@@ -355,6 +638,199 @@ func (s *ModulesService) SearchWithRelevance(ctx context.Context, query string,
 	return searchResults, nil
 }
 
+// SearchWithOptions searches for modules with relevance scores, optionally collapsing
+// entries for the same module with CollapseModuleResults
+func (s *ModulesService) SearchWithOptions(ctx context.Context, query string, opts *ModuleSearchOptions) ([]ModuleSearchResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	offset := 0
+	if opts != nil {
+		offset = opts.Offset
+	}
+
+	results, err := s.searchWithRelevance(ctx, query, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts != nil && (!opts.PublishedAfter.IsZero() || !opts.PublishedBefore.IsZero()) {
+		filtered := results[:0]
+		for _, result := range results {
+			if publishedWithin(result.PublishedAt, opts.PublishedAfter, opts.PublishedBefore) {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+
+	if opts != nil && opts.Collapse {
+		results = CollapseModuleResults(results)
+	}
+
+	return results, nil
+}
+
+// CollapseModuleResults merges results for the same namespace/name/provider, such as
+// several versions or repeated entries from multiple searches. The entry with the highest
+// version is kept as the canonical one, with the highest relevance in the group and the
+// downloads summed across distinct versions. Results are returned in relevance order.
+func CollapseModuleResults(results []ModuleSearchResult) []ModuleSearchResult {
+	type group struct {
+		result    ModuleSearchResult
+		downloads map[string]int64
+	}
+
+	groups := make(map[string]*group)
+	var order []string
+
+	for _, result := range results {
+		key := moduleKey(result.Module)
+		g, ok := groups[key]
+		if !ok {
+			groups[key] = &group{
+				result:    result,
+				downloads: map[string]int64{result.Version: result.Downloads},
+			}
+			order = append(order, key)
+			continue
+		}
+
+		g.result.Collapsed++
+		g.downloads[result.Version] = max(g.downloads[result.Version], result.Downloads)
+		relevance := max(g.result.Relevance, result.Relevance)
+
+		if CompareVersions(result.Version, g.result.Version) > 0 {
+			collapsed := g.result.Collapsed
+			g.result = result
+			g.result.Collapsed = collapsed
+		}
+		g.result.Relevance = relevance
+	}
+
+	collapsed := make([]ModuleSearchResult, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		g.result.Downloads = 0
+		for _, downloads := range g.downloads {
+			g.result.Downloads += downloads
+		}
+		collapsed = append(collapsed, g.result)
+	}
+
+	sort.SliceStable(collapsed, func(i, j int) bool {
+		return collapsed[i].Relevance > collapsed[j].Relevance
+	})
+
+	return collapsed
+}
+
+// WeightedQuery is one query of an expanded search. Weight scales the relevance of its
+// results; zero means 1.
+type WeightedQuery struct {
+	Query  string
+	Weight float64
+}
+
+// SearchExpanded runs several related queries, such as synonyms ("vnet", "virtual
+// network"), and merges their results into one entry per module. A module's relevance
+// is the sum of its weighted relevance in each query that found it, so modules matching
+// several queries rank higher. Queries that fail are reported in the returned
+// MultiError alongside the merged results of the others; the search fails only when
+// every query fails.
+func (s *ModulesService) SearchExpanded(ctx context.Context, queries []WeightedQuery) ([]ModuleSearchResult, error) {
+	if len(queries) == 0 {
+		return nil, &ValidationError{
+			Field:   "queries",
+			Value:   queries,
+			Message: "at least one query is required",
+		}
+	}
+
+	weights := make(map[string]float64, len(queries))
+	keys := make([]string, 0, len(queries))
+	for _, q := range queries {
+		if strings.TrimSpace(q.Query) == "" {
+			return nil, &ValidationError{
+				Field:   "queries",
+				Value:   q.Query,
+				Message: "query cannot be empty",
+			}
+		}
+		if q.Weight < 0 {
+			return nil, &ValidationError{
+				Field:   "weight",
+				Value:   q.Weight,
+				Message: "weight cannot be negative",
+			}
+		}
+		if _, ok := weights[q.Query]; ok {
+			return nil, &ValidationError{
+				Field:   "queries",
+				Value:   q.Query,
+				Message: "duplicate query",
+			}
+		}
+
+		weight := q.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		weights[q.Query] = weight
+		keys = append(keys, q.Query)
+	}
+
+	ctx, cancel := s.client.bestEffortContext(ctx)
+	defer cancel()
+
+	batch := runBatch(ctx, keys, DefaultBatchConcurrency, func(ctx context.Context, query string) ([]ModuleSearchResult, error) {
+		return s.searchWithRelevance(ctx, query, 0)
+	})
+
+	var errs, expired MultiError
+	var all []ModuleSearchResult
+	scores := make(map[string]float64)
+	for _, query := range keys {
+		result := batch[query]
+		if result.Err != nil {
+			err := fmt.Errorf("search %q failed: %w", query, result.Err)
+			if budgetExpired(ctx) && errors.Is(result.Err, context.DeadlineExceeded) {
+				expired.Add(err)
+			} else {
+				errs.Add(err)
+			}
+			continue
+		}
+
+		// Collapse each query first so that several versions of a module count once
+		for _, r := range CollapseModuleResults(result.Value) {
+			r.Relevance *= weights[query]
+			scores[moduleKey(r.Module)] += r.Relevance
+			all = append(all, r)
+		}
+	}
+
+	if len(errs.Errors)+len(expired.Errors) == len(keys) {
+		errs.Errors = append(errs.Errors, expired.Errors...)
+		return nil, errs.ErrorOrNil()
+	}
+
+	merged := CollapseModuleResults(all)
+	for i := range merged {
+		merged[i].Relevance = scores[moduleKey(merged[i].Module)]
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Relevance > merged[j].Relevance
+	})
+
+	if expired.HasErrors() {
+		return merged, s.client.partialResult("expanded search", len(keys)-len(expired.Errors), len(keys), &errs)
+	}
+
+	return merged, errs.ErrorOrNil()
+}
+
 // validateModuleParams validates module parameters
 func validateModuleParams(namespace, name, provider, version string) error {
 	var errs MultiError
@@ -365,7 +841,7 @@ func validateModuleParams(namespace, name, provider, version string) error {
 			Value:   namespace,
 			Message: "namespace cannot be empty",
 		})
-	} else if !isValidNamespace(namespace) {
+	} else if !validate.Namespace(namespace) {
 		errs.Add(&ValidationError{
 			Field:   "namespace",
 			Value:   namespace,
@@ -379,7 +855,7 @@ func validateModuleParams(namespace, name, provider, version string) error {
 			Value:   name,
 			Message: "name cannot be empty",
 		})
-	} else if !isValidModuleName(name) {
+	} else if !validate.ModuleName(name) {
 		errs.Add(&ValidationError{
 			Field:   "name",
 			Value:   name,
@@ -393,7 +869,7 @@ func validateModuleParams(namespace, name, provider, version string) error {
 			Value:   provider,
 			Message: "provider cannot be empty",
 		})
-	} else if !isValidProviderName(provider) {
+	} else if !validate.ProviderName(provider) {
 		errs.Add(&ValidationError{
 			Field:   "provider",
 			Value:   provider,
@@ -401,7 +877,7 @@ func validateModuleParams(namespace, name, provider, version string) error {
 		})
 	}
 
-	if version != "" && !isValidVersion(version) {
+	if version != "" && !validate.Version(version) {
 		errs.Add(&ValidationError{
 			Field:   "version",
 			Value:   version,
@@ -412,86 +888,11 @@ func validateModuleParams(namespace, name, provider, version string) error {
 	return errs.ErrorOrNil()
 }
 
-// Helper functions for validation
-func isValidNamespace(namespace string) bool {
-	// Namespace should contain only alphanumeric characters, hyphens, and underscores
-	for _, r := range namespace {
-		if !isAlphaNumeric(r) && r != '-' && r != '_' {
-			return false
-		}
-	}
-	return true
-}
-
-func isValidModuleName(name string) bool {
-	// Module name should contain only alphanumeric characters, hyphens, and underscores
-	for _, r := range name {
-		if !isAlphaNumeric(r) && r != '-' && r != '_' {
-			return false
-		}
-	}
-	return true
-}
-
-func isValidProviderName(provider string) bool {
-	// Provider name should contain only lowercase letters and hyphens
-	for _, r := range provider {
-		if !isLowerAlpha(r) && r != '-' {
-			return false
-		}
-	}
-	return true
-}
-
-func isValidVersion(version string) bool {
-	// Basic semantic version validation
-	// Format: v1.2.3 or 1.2.3, optionally with pre-release
-	if version == "" {
-		return false
-	}
-
-	// Remove 'v' prefix if present
-	version = strings.TrimPrefix(version, "v")
-
-	// Check basic format
-	parts := strings.Split(version, ".")
-	if len(parts) != 3 {
-		return false
-	}
-
-	// Each part should be a number
-	for i, part := range parts {
-		if i == 2 {
-			// The patch version might have a pre-release suffix
-			dashIndex := strings.Index(part, "-")
-			if dashIndex > 0 {
-				part = part[:dashIndex]
-			}
-		}
-
-		for _, r := range part {
-			if !isDigit(r) {
-				return false
-			}
-		}
-	}
-
-	return true
-}
-
 // Character type checking functions
-func isAlphaNumeric(r rune) bool {
-	return isLowerAlpha(r) || isUpperAlpha(r) || isDigit(r)
-}
-
 func isLowerAlpha(r rune) bool {
 	return r >= 'a' && r <= 'z'
 }
 
-func isUpperAlpha(r rune) bool {
-	return r >= 'A' && r <= 'Z'
-}
-
 func isDigit(r rune) bool {
 	return r >= '0' && r <= '9'
 }
@@ -506,39 +907,23 @@ func logScale(value, minIn, maxIn, minOut, maxOut float64) float64 {
 	}
 
 	// Use log10 for scaling
-	logMin := log10(minIn)
-	logMax := log10(maxIn)
-	logValue := log10(value)
+	logMin := math.Log10(minIn)
+	logMax := math.Log10(maxIn)
+	logValue := math.Log10(value)
 
 	// Linear interpolation in log space
 	normalized := (logValue - logMin) / (logMax - logMin)
 	return minOut + normalized*(maxOut-minOut)
 }
 
-// log10 computes the base-10 logarithm
-func log10(x float64) float64 {
-	// Simple implementation of log10
-	// In production, use math.Log10
-	if x <= 0 {
-		return 0
-	}
-
-	// Count the number of times we can divide by 10
-	count := 0.0
-	for x >= 10 {
-		x /= 10
-		count++
-	}
-
-	// Add fractional part (simplified)
-	if x > 1 {
-		count += (x - 1) / 9
-	}
-
-	return count
-}
-
 // timeSince returns the duration since the given time
 func timeSince(t time.Time) time.Duration {
 	return time.Since(t)
 }
+
+// attachReadmeDependencies parses the dependency tables of a module part README
+func attachReadmeDependencies(part *ModulePart) {
+	if deps := ParseReadmeDependencies(part.Readme); deps != nil {
+		part.ReadmeDependencies = deps
+	}
+}
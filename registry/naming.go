@@ -0,0 +1,255 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Naming rule identifiers reported in naming outliers
+const (
+	// NamingRuleSlugFormat flags slugs that are not lowercase snake_case
+	NamingRuleSlugFormat = "slug-format"
+
+	// NamingRuleTitleMismatch flags titles naming a type other than <prefix>_<slug>
+	NamingRuleTitleMismatch = "title-mismatch"
+
+	// NamingRulePrefixVariant flags a slug prefix used once that looks like a misspelling
+	// or plural of a common prefix
+	NamingRulePrefixVariant = "prefix-variant"
+)
+
+var (
+	namingSlugRegex  = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	namingTitleRegex = regexp.MustCompile(`^(?i:(?:resource|data source|data-source|ephemeral)\s*:\s*)?([A-Za-z][A-Za-z0-9]*_[A-Za-z0-9_]+)\b`)
+)
+
+// NamingReport summarizes how the resources and data sources of a provider version are
+// named and lists the names that break its conventions
+type NamingReport struct {
+	ProviderVersionID string
+
+	// ProviderPrefix is the type prefix most titles use, e.g. "aws"; empty when titles
+	// do not name resource types
+	ProviderPrefix string
+
+	Resources   int
+	DataSources int
+
+	// Prefixes counts the first segment of slugs, e.g. "vpc" for "vpc_endpoint", most
+	// common first
+	Prefixes []PrefixStat
+
+	// Outliers are sorted by category and slug
+	Outliers []NamingOutlier
+}
+
+// PrefixStat is the usage of a slug prefix
+type PrefixStat struct {
+	Prefix string
+	Count  int
+
+	// Share is Count relative to all slugs, from 0 to 1
+	Share float64
+}
+
+// NamingOutlier is a resource or data source whose name breaks a naming convention
+type NamingOutlier struct {
+	Category string
+	Slug     string
+	Title    string
+
+	// Rule is one of the NamingRule identifiers
+	Rule    string
+	Message string
+}
+
+// NamingConventions lists the resource and data source docs of a provider version and
+// reports slug prefix statistics and naming outliers
+func (s *AnalyzeService) NamingConventions(ctx context.Context, providerVersionID string) (*NamingReport, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	// Doc listings through the Providers interface carry no slugs or titles, so use the
+	// underlying listing directly
+	providers := &ProvidersService{client: s.client}
+
+	var docs []ProviderDocData
+	for _, category := range []string{"resources", "data-sources"} {
+		categoryDocs, err := providers.listDocData(ctx, providerVersionID, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", category, err)
+		}
+		docs = append(docs, categoryDocs...)
+	}
+
+	report := BuildNamingReport(docs)
+	report.ProviderVersionID = providerVersionID
+	return report, nil
+}
+
+// BuildNamingReport analyzes the names of resource and data source docs, for example
+// docs generated for a provider that is not published yet. Docs of other categories
+// are ignored.
+func BuildNamingReport(docs []ProviderDocData) *NamingReport {
+	report := &NamingReport{}
+
+	var named []DocAttributes
+	for _, doc := range docs {
+		switch doc.Attributes.Category {
+		case "resources":
+			report.Resources++
+		case "data-sources":
+			report.DataSources++
+		default:
+			continue
+		}
+		named = append(named, doc.Attributes)
+	}
+
+	// The provider prefix is the most common first segment of types named in titles
+	titlePrefixes := make(map[string]int)
+	for _, doc := range named {
+		if typeName := namingTitleType(doc.Title); typeName != "" {
+			prefix, _, _ := strings.Cut(typeName, "_")
+			titlePrefixes[prefix]++
+		}
+	}
+	report.ProviderPrefix = mostCommon(titlePrefixes)
+
+	counts := make(map[string]int)
+	for _, doc := range named {
+		prefix, _, _ := strings.Cut(doc.Slug, "_")
+		counts[prefix]++
+	}
+	for prefix, count := range counts {
+		report.Prefixes = append(report.Prefixes, PrefixStat{
+			Prefix: prefix,
+			Count:  count,
+			Share:  float64(count) / float64(len(named)),
+		})
+	}
+	sort.Slice(report.Prefixes, func(i, j int) bool {
+		if report.Prefixes[i].Count != report.Prefixes[j].Count {
+			return report.Prefixes[i].Count > report.Prefixes[j].Count
+		}
+		return report.Prefixes[i].Prefix < report.Prefixes[j].Prefix
+	})
+
+	for _, doc := range named {
+		report.Outliers = append(report.Outliers, namingOutliers(doc, report.ProviderPrefix, counts)...)
+	}
+	sort.SliceStable(report.Outliers, func(i, j int) bool {
+		if report.Outliers[i].Category != report.Outliers[j].Category {
+			return report.Outliers[i].Category < report.Outliers[j].Category
+		}
+		return report.Outliers[i].Slug < report.Outliers[j].Slug
+	})
+
+	return report
+}
+
+// namingOutliers checks the name of one doc
+func namingOutliers(doc DocAttributes, providerPrefix string, counts map[string]int) []NamingOutlier {
+	var outliers []NamingOutlier
+	add := func(rule, message string) {
+		outliers = append(outliers, NamingOutlier{
+			Category: doc.Category,
+			Slug:     doc.Slug,
+			Title:    doc.Title,
+			Rule:     rule,
+			Message:  message,
+		})
+	}
+
+	if !namingSlugRegex.MatchString(doc.Slug) {
+		add(NamingRuleSlugFormat, fmt.Sprintf("slug %q is not lowercase snake_case", doc.Slug))
+	}
+
+	if typeName := namingTitleType(doc.Title); typeName != "" && providerPrefix != "" {
+		if want := providerPrefix + "_" + doc.Slug; typeName != want {
+			add(NamingRuleTitleMismatch, fmt.Sprintf("title names %s, expected %s", typeName, want))
+		}
+	}
+
+	prefix, _, _ := strings.Cut(doc.Slug, "_")
+	if counts[prefix] == 1 {
+		if common := prefixVariantOf(prefix, counts); common != "" {
+			add(NamingRulePrefixVariant, fmt.Sprintf("prefix %q is used once and resembles %q", prefix, common))
+		}
+	}
+
+	return outliers
+}
+
+// namingTitleType returns the resource type named at the start of a doc title, such as
+// "aws_instance" in "Resource: aws_instance", or "" when the title is prose
+func namingTitleType(title string) string {
+	match := namingTitleRegex.FindStringSubmatch(strings.TrimSpace(title))
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// prefixVariantOf returns the most used prefix that a rare prefix is a plural or single
+// edit of, or ""
+func prefixVariantOf(prefix string, counts map[string]int) string {
+	best, bestCount := "", 0
+	for candidate, count := range counts {
+		if count < 2 || candidate == prefix || len(candidate) < 4 {
+			continue
+		}
+		if count < bestCount || (count == bestCount && candidate > best) {
+			continue
+		}
+		if prefix == candidate+"s" || candidate == prefix+"s" || withinOneEdit(prefix, candidate) {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// withinOneEdit reports whether a and b differ by at most one insertion, deletion,
+// substitution or adjacent transposition
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if i == len(a) {
+		return true
+	}
+
+	if len(a) == len(b) {
+		if a[i+1:] == b[i+1:] {
+			return true
+		}
+		return i+1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+	}
+	return a[i:] == b[i+1:]
+}
+
+// mostCommon returns the key with the highest count, preferring the smallest key on ties
+func mostCommon(counts map[string]int) string {
+	best, bestCount := "", 0
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best, bestCount = key, count
+		}
+	}
+	return best
+}
@@ -0,0 +1,197 @@
+package registry
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+const (
+	// DefaultNegativeCacheTTL is the default time a not-found lookup is cached for
+	DefaultNegativeCacheTTL = 30 * time.Second
+
+	// DefaultNegativeCacheMaxTTL is the default cap on the TTL of repeatedly missing lookups
+	DefaultNegativeCacheMaxTTL = 10 * time.Minute
+)
+
+// WithNegativeCache caches not-found (404) responses to module and provider lookups, so
+// that probing candidate namespaces does not re-request items known to be missing.
+// A miss is cached for ttl; each further miss of the same lookup after its entry expired
+// doubles the TTL, up to maxTTL. Use BypassNegativeCache to force a request.
+// Zero values select DefaultNegativeCacheTTL and DefaultNegativeCacheMaxTTL.
+func WithNegativeCache(ttl, maxTTL time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		if ttl == 0 {
+			ttl = DefaultNegativeCacheTTL
+		}
+		if maxTTL == 0 {
+			maxTTL = max(ttl, DefaultNegativeCacheMaxTTL)
+		}
+		c.NegativeCacheTTL = ttl
+		c.NegativeCacheMaxTTL = maxTTL
+	}
+}
+
+var bypassNegativeCacheKey = registryctx.NewKey[bool]("bypass-negative-cache")
+
+// BypassNegativeCache returns a context whose lookups ignore cached not-found responses.
+// Results of requests made with it still update the cache.
+func BypassNegativeCache(ctx context.Context) context.Context {
+	return bypassNegativeCacheKey.With(ctx, true)
+}
+
+// negativeCache holds recent not-found responses keyed by API version and path
+type negativeCache struct {
+	ttl    time.Duration
+	maxTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*negativeEntry
+}
+
+// negativeEntry is a cached not-found response
+type negativeEntry struct {
+	err     APIError
+	ttl     time.Duration
+	expires time.Time
+}
+
+// newNegativeCache creates a negative cache, or returns nil when ttl is not positive
+func newNegativeCache(ttl, maxTTL time.Duration) *negativeCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &negativeCache{
+		ttl:     ttl,
+		maxTTL:  max(ttl, maxTTL),
+		entries: make(map[string]*negativeEntry),
+	}
+}
+
+// isNegativeCacheable reports whether a path is a module or provider lookup
+func isNegativeCacheable(path string) bool {
+	for _, prefix := range []string{"modules/", "providers/"} {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			return rest != "" && !strings.HasPrefix(rest, "search")
+		}
+	}
+	return false
+}
+
+// lookup returns a copy of the cached not-found error for key, if it has not expired
+func (n *negativeCache) lookup(key string) (*APIError, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	entry, ok := n.entries[key]
+	if !ok || !time.Now().Before(entry.expires) {
+		return nil, false
+	}
+
+	err := entry.err
+	return &err, true
+}
+
+// record caches a not-found response. A miss within maxTTL of the previous entry
+// expiring doubles the previous TTL.
+func (n *negativeCache) record(key string, err *APIError) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	ttl := n.ttl
+	if prev, ok := n.entries[key]; ok && now.Sub(prev.expires) < n.maxTTL {
+		ttl = prev.ttl * 2
+		if ttl > n.maxTTL {
+			ttl = n.maxTTL
+		}
+	}
+
+	n.entries[key] = &negativeEntry{err: *err, ttl: ttl, expires: now.Add(ttl)}
+	n.pruneLocked(now)
+}
+
+// forget drops the entry for key after a successful response
+func (n *negativeCache) forget(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.entries, key)
+}
+
+// clear drops all entries
+func (n *negativeCache) clear() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	clear(n.entries)
+}
+
+// len returns the number of unexpired entries
+func (n *negativeCache) len() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	count := 0
+	for _, entry := range n.entries {
+		if now.Before(entry.expires) {
+			count++
+		}
+	}
+	return count
+}
+
+// pruneLocked drops entries that expired long enough ago to no longer extend the TTL
+func (n *negativeCache) pruneLocked(now time.Time) {
+	for key, entry := range n.entries {
+		if now.Sub(entry.expires) >= n.maxTTL {
+			delete(n.entries, key)
+		}
+	}
+}
+
+// ClearNegativeCache drops all cached not-found responses
+func (c *Client) ClearNegativeCache() {
+	if c.negativeCache != nil {
+		c.negativeCache.clear()
+	}
+}
+
+// NegativeCacheLen returns the number of cached not-found responses that have not expired
+func (c *Client) NegativeCacheLen() int {
+	if c.negativeCache == nil {
+		return 0
+	}
+	return c.negativeCache.len()
+}
+
+// cachedNotFound returns the cached not-found error for a GET lookup, if any
+func (c *Client) cachedNotFound(ctx context.Context, method, key, path string) (*APIError, bool) {
+	if c.negativeCache == nil || method != "GET" || !isNegativeCacheable(path) {
+		return nil, false
+	}
+	if bypass, _ := bypassNegativeCacheKey.Get(ctx); bypass {
+		return nil, false
+	}
+	return c.negativeCache.lookup(key)
+}
+
+// updateNegativeCache records or clears the cached not-found state of a GET lookup
+func (c *Client) updateNegativeCache(method, key, path string, err error) {
+	if c.negativeCache == nil || method != "GET" || !isNegativeCacheable(path) {
+		return
+	}
+
+	if err == nil {
+		c.negativeCache.forget(key)
+		return
+	}
+
+	if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == 404 {
+		c.negativeCache.record(key, apiErr)
+	}
+}
@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SnapshotFormat is the version of the offline snapshot format written by this client
+	SnapshotFormat = 1
+
+	// SnapshotManifestFile is the name of the manifest in a snapshot directory
+	SnapshotManifestFile = "snapshot.json"
+
+	// SnapshotHeader is set on responses served from an offline snapshot
+	SnapshotHeader = "X-Terralens-Snapshot"
+)
+
+// Snapshot is a set of recorded API responses from which a client created with
+// WithOfflineSnapshot serves reads without network access. The registry/snapshot
+// package records snapshots of providers and modules.
+type Snapshot struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// BaseURL is the registry the responses were recorded from
+	BaseURL string `json:"base_url"`
+
+	// Contents lists the providers and modules in the snapshot, sorted by kind and address
+	Contents []SnapshotEntry `json:"contents"`
+
+	mu        sync.RWMutex
+	responses map[string]json.RawMessage
+}
+
+// SnapshotEntry describes a provider or module recorded in a snapshot
+type SnapshotEntry struct {
+	// Kind is "provider" or "module"
+	Kind string `json:"kind"`
+
+	// Address is namespace/name for providers and namespace/name/provider for modules
+	Address string `json:"address"`
+
+	// Version is the version whose docs or metadata were recorded
+	Version string `json:"version"`
+
+	// Versions are all versions published when the snapshot was taken
+	Versions []string `json:"versions,omitempty"`
+
+	// Docs is the number of provider docs recorded
+	Docs int `json:"docs,omitempty"`
+}
+
+// NewSnapshot returns an empty snapshot of the registry at baseURL
+func NewSnapshot(baseURL string) *Snapshot {
+	return &Snapshot{
+		Format:    SnapshotFormat,
+		CreatedAt: time.Now().UTC(),
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		responses: make(map[string]json.RawMessage),
+	}
+}
+
+// Record stores the body of a successful response to the request URL rawURL
+func (s *Snapshot) Record(rawURL string, body json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.responses == nil {
+		s.responses = make(map[string]json.RawMessage)
+	}
+	s.responses[snapshotKey(rawURL, s.BaseURL)] = append(json.RawMessage(nil), body...)
+}
+
+// AddEntry adds or replaces the contents entry of a provider or module
+func (s *Snapshot) AddEntry(entry SnapshotEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.Contents {
+		if existing.Kind == entry.Kind && existing.Address == entry.Address && existing.Version == entry.Version {
+			s.Contents[i] = entry
+			return
+		}
+	}
+	s.Contents = append(s.Contents, entry)
+	sort.Slice(s.Contents, func(i, j int) bool {
+		if s.Contents[i].Kind != s.Contents[j].Kind {
+			return s.Contents[i].Kind < s.Contents[j].Kind
+		}
+		if s.Contents[i].Address != s.Contents[j].Address {
+			return s.Contents[i].Address < s.Contents[j].Address
+		}
+		return s.Contents[i].Version < s.Contents[j].Version
+	})
+}
+
+// Len returns the number of recorded responses
+func (s *Snapshot) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.responses)
+}
+
+// lookup returns the recorded body for a request URL relative to baseURL
+func (s *Snapshot) lookup(rawURL, baseURL string) (json.RawMessage, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	body, ok := s.responses[snapshotKey(rawURL, baseURL)]
+	return body, ok
+}
+
+// snapshotKey returns the key of a request URL: the path and query below the base URL
+// for the registry itself, so that a snapshot can be served under another base URL, and
+// the full URL for other hosts
+func snapshotKey(rawURL, baseURL string) string {
+	if baseURL = strings.TrimRight(baseURL, "/"); baseURL != "" && strings.HasPrefix(rawURL, baseURL+"/") {
+		return strings.TrimPrefix(rawURL, baseURL+"/")
+	}
+	return rawURL
+}
+
+// snapshotDocument is the JSON encoding of a snapshot. A single-file snapshot holds
+// the response bodies; the manifest of a snapshot directory maps keys to body files.
+type snapshotDocument struct {
+	Format    int                        `json:"format"`
+	CreatedAt time.Time                  `json:"created_at"`
+	BaseURL   string                     `json:"base_url"`
+	Contents  []SnapshotEntry            `json:"contents"`
+	Responses map[string]json.RawMessage `json:"responses,omitempty"`
+	Files     map[string]string          `json:"files,omitempty"`
+}
+
+// MarshalJSON encodes the snapshot with its responses as a single document
+func (s *Snapshot) MarshalJSON() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return json.Marshal(snapshotDocument{
+		Format:    s.Format,
+		CreatedAt: s.CreatedAt,
+		BaseURL:   s.BaseURL,
+		Contents:  s.Contents,
+		Responses: s.responses,
+	})
+}
+
+// UnmarshalJSON decodes a single-document snapshot
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	var doc snapshotDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Format = doc.Format
+	s.CreatedAt = doc.CreatedAt
+	s.BaseURL = doc.BaseURL
+	s.Contents = doc.Contents
+	s.responses = doc.Responses
+	if s.responses == nil {
+		s.responses = make(map[string]json.RawMessage)
+	}
+	return nil
+}
+
+// validate checks that the snapshot can be read by this client
+func (s *Snapshot) validate() error {
+	if s.Format < 1 || s.Format > SnapshotFormat {
+		return fmt.Errorf("unsupported snapshot format %d (this client reads formats up to %d)", s.Format, SnapshotFormat)
+	}
+	return nil
+}
+
+// WithOfflineSnapshot serves every request from the snapshot at path, a directory or a
+// single .json file written by the registry/snapshot package, instead of the network.
+// Requests missing from the snapshot fail with a 404 APIError. Offline requests do not
+// use rate limit budget.
+func WithOfflineSnapshot(path string) ClientOption {
+	return func(c *ClientConfig) {
+		c.OfflineSnapshot = path
+	}
+}
+
+// newOfflineHTTPClient returns an HTTP client that answers from a snapshot. baseURL
+// returns the client's current base URL, against which request keys are resolved.
+func newOfflineHTTPClient(snapshot *Snapshot, baseURL func() string) *http.Client {
+	return &http.Client{Transport: &snapshotTransport{snapshot: snapshot, baseURL: baseURL}}
+}
+
+// snapshotTransport is a RoundTripper serving recorded responses
+type snapshotTransport struct {
+	snapshot *Snapshot
+	baseURL  func() string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	status := http.StatusOK
+	body, ok := t.snapshot.lookup(req.URL.String(), t.baseURL())
+	if !ok || req.Method != http.MethodGet {
+		status = http.StatusNotFound
+		message := fmt.Sprintf("%s %s is not in the offline snapshot", req.Method, snapshotKey(req.URL.String(), t.baseURL()))
+		body, _ = json.Marshal(map[string]any{
+			"errors": []map[string]string{{"message": message}},
+		})
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(SnapshotHeader, "true")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,151 @@
+//go:build !js && !wasip1 && !tinygo
+
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapshotResponsesDir is the directory of the response bodies in a snapshot directory
+const snapshotResponsesDir = "responses"
+
+// ReadSnapshot reads a snapshot from a directory or a single .json file
+func ReadSnapshot(path string) (*Snapshot, error) {
+	if err := checkSnapshotPath(path); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	snapshot := &Snapshot{}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		if err := json.Unmarshal(data, snapshot); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+		}
+		if err := snapshot.validate(); err != nil {
+			return nil, err
+		}
+		return snapshot, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, SnapshotManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+	var doc snapshotDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot manifest %s: %w", path, err)
+	}
+
+	*snapshot = Snapshot{
+		Format:    doc.Format,
+		CreatedAt: doc.CreatedAt,
+		BaseURL:   doc.BaseURL,
+		Contents:  doc.Contents,
+		responses: make(map[string]json.RawMessage, len(doc.Files)),
+	}
+	if err := snapshot.validate(); err != nil {
+		return nil, err
+	}
+
+	for key, file := range doc.Files {
+		body, err := os.ReadFile(filepath.Join(path, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot response %s: %w", key, err)
+		}
+		snapshot.responses[key] = body
+	}
+
+	return snapshot, nil
+}
+
+// WriteSnapshot writes a snapshot to path: a single file when path ends in .json,
+// otherwise a directory holding a snapshot.json manifest and one file per response
+func WriteSnapshot(path string, snapshot *Snapshot) error {
+	if err := checkSnapshotPath(path); err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+		if dir := filepath.Dir(path); dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to create snapshot directory: %w", err)
+			}
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(path, snapshotResponsesDir), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	snapshot.mu.RLock()
+	doc := snapshotDocument{
+		Format:    snapshot.Format,
+		CreatedAt: snapshot.CreatedAt,
+		BaseURL:   snapshot.BaseURL,
+		Contents:  snapshot.Contents,
+		Files:     make(map[string]string, len(snapshot.responses)),
+	}
+	for key, body := range snapshot.responses {
+		sum := sha256.Sum256([]byte(key))
+		file := snapshotResponsesDir + "/" + hex.EncodeToString(sum[:16]) + ".json"
+		if err := os.WriteFile(filepath.Join(path, filepath.FromSlash(file)), body, 0o644); err != nil {
+			snapshot.mu.RUnlock()
+			return fmt.Errorf("failed to write snapshot response %s: %w", key, err)
+		}
+		doc.Files[key] = file
+	}
+	snapshot.mu.RUnlock()
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, SnapshotManifestFile), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// checkSnapshotPath rejects empty paths and database files, which are not a supported
+// snapshot format
+func checkSnapshotPath(path string) error {
+	if path == "" {
+		return &ValidationError{
+			Field:   "path",
+			Value:   path,
+			Message: "snapshot path cannot be empty",
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return &ValidationError{
+			Field:   "path",
+			Value:   path,
+			Message: "SQLite snapshots are not supported; use a directory or a .json file",
+		}
+	}
+	return nil
+}
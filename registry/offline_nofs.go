@@ -0,0 +1,15 @@
+//go:build js || wasip1 || tinygo
+
+package registry
+
+// ReadSnapshot reports that reading snapshot files is unavailable in this build; decode
+// a snapshot with encoding/json instead
+func ReadSnapshot(path string) (*Snapshot, error) {
+	return nil, ErrFilesystemUnsupported
+}
+
+// WriteSnapshot reports that writing snapshot files is unavailable in this build;
+// encode the snapshot with encoding/json instead
+func WriteSnapshot(path string, snapshot *Snapshot) error {
+	return ErrFilesystemUnsupported
+}
@@ -0,0 +1,697 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backend is the registry protocol a client speaks. The Terraform Registry serves
+// provider and module metadata and docs through its v1 and v2 APIs; the OpenTofu
+// registry only implements the provider and module registry protocols, and publishes
+// metadata and docs through a separate docs API. Clients translate between the two, so
+// the same calls work against either backend.
+type Backend string
+
+// Registry backends
+const (
+	// BackendTerraform is the Terraform Registry and compatible registries
+	BackendTerraform Backend = "terraform"
+
+	// BackendOpenTofu is the OpenTofu registry
+	BackendOpenTofu Backend = "opentofu"
+)
+
+const (
+	// OpenTofuBaseURL is the base URL of the OpenTofu registry
+	OpenTofuBaseURL = "https://registry.opentofu.org"
+
+	// DefaultOpenTofuDocsURL is the OpenTofu docs API serving provider and module
+	// metadata, docs and search for the OpenTofu registry
+	DefaultOpenTofuDocsURL = "https://api.opentofu.org"
+)
+
+// WithBackend selects the registry backend and its default base URL. Options applied
+// after it, such as WithBaseURL, can point the backend at a mirror.
+func WithBackend(backend Backend) ClientOption {
+	return func(c *ClientConfig) {
+		c.Backend = backend
+		if baseURL := backend.BaseURL(); baseURL != "" {
+			c.BaseURL = baseURL
+		}
+	}
+}
+
+// WithOpenTofuDocsURL sets the address of the OpenTofu docs API used by the OpenTofu
+// backend, e.g. for a self-hosted copy; empty keeps DefaultOpenTofuDocsURL
+func WithOpenTofuDocsURL(address string) ClientOption {
+	return func(c *ClientConfig) {
+		c.OpenTofuDocsURL = address
+	}
+}
+
+// BaseURL returns the default base URL of the backend, or "" for an unknown backend
+func (b Backend) BaseURL() string {
+	switch b {
+	case "", BackendTerraform:
+		return DefaultBaseURL
+	case BackendOpenTofu:
+		return OpenTofuBaseURL
+	}
+	return ""
+}
+
+// Validate validates the backend
+func (b Backend) Validate() error {
+	if b.BaseURL() == "" {
+		return &ValidationError{
+			Field:   "backend",
+			Value:   string(b),
+			Message: fmt.Sprintf("unknown registry backend, expected %s or %s", BackendTerraform, BackendOpenTofu),
+		}
+	}
+	return nil
+}
+
+// Backend returns the registry backend of the client
+func (c *Client) Backend() Backend {
+	if c.config.Backend == "" {
+		return BackendTerraform
+	}
+	return c.config.Backend
+}
+
+// isOpenTofu reports whether the client talks to the OpenTofu registry
+func (c *Client) isOpenTofu() bool {
+	return c.config.Backend == BackendOpenTofu
+}
+
+// unsupported returns the error for a feature the client's backend does not offer
+func (c *Client) unsupported(feature string) error {
+	return fmt.Errorf("%s on the %s registry: %w", feature, c.Backend(), ErrUnsupportedByBackend)
+}
+
+// getOpenTofuDocs performs a GET request for a path of the OpenTofu docs API
+func (c *Client) getOpenTofuDocs(ctx context.Context, path string, result interface{}) error {
+	address := c.config.OpenTofuDocsURL
+	if address == "" {
+		address = DefaultOpenTofuDocsURL
+	}
+	return c.getURL(ctx, strings.TrimSuffix(address, "/")+"/registry/docs/"+path, "", result)
+}
+
+// rawBody receives a response body as it is, for responses that are not JSON
+type rawBody []byte
+
+// tofuVersion is a version in the OpenTofu docs API, whose IDs have a "v" prefix
+type tofuVersion struct {
+	ID        string    `json:"id"`
+	Published time.Time `json:"published"`
+}
+
+// tofuLatest returns the greatest of versions without its "v" prefix
+func tofuLatest(versions []tofuVersion) string {
+	latest := ""
+	for _, v := range versions {
+		version := strings.TrimPrefix(v.ID, "v")
+		if latest == "" || CompareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// tofuProvider is a provider in the OpenTofu docs API
+type tofuProvider struct {
+	Description string        `json:"description"`
+	Link        string        `json:"link"`
+	Versions    []tofuVersion `json:"versions"`
+}
+
+// tofuDocItem is a doc listed for a provider version in the OpenTofu docs API
+type tofuDocItem struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Subcategory string `json:"subcategory"`
+	Description string `json:"description"`
+}
+
+// tofuProviderVersion is a provider version in the OpenTofu docs API
+type tofuProviderVersion struct {
+	ID        string    `json:"id"`
+	Published time.Time `json:"published"`
+	Docs      struct {
+		Index       *tofuDocItem  `json:"index"`
+		Resources   []tofuDocItem `json:"resources"`
+		DataSources []tofuDocItem `json:"datasources"`
+		Functions   []tofuDocItem `json:"functions"`
+		Guides      []tofuDocItem `json:"guides"`
+	} `json:"docs"`
+}
+
+// tofuDocKinds maps doc categories to the OpenTofu docs API directories holding them
+var tofuDocKinds = []struct {
+	category string
+	kind     string
+}{
+	{"overview", ""},
+	{"resources", "resources"},
+	{"data-sources", "datasources"},
+	{"functions", "functions"},
+	{"guides", "guides"},
+}
+
+// items returns the docs of a category
+func (v *tofuProviderVersion) items(category string) []tofuDocItem {
+	switch category {
+	case "overview":
+		if v.Docs.Index != nil {
+			index := *v.Docs.Index
+			index.Name = "index"
+			return []tofuDocItem{index}
+		}
+	case "resources":
+		return v.Docs.Resources
+	case "data-sources":
+		return v.Docs.DataSources
+	case "functions":
+		return v.Docs.Functions
+	case "guides":
+		return v.Docs.Guides
+	}
+	return nil
+}
+
+// OpenTofu has no numeric IDs, so version IDs are namespace/name/version and doc IDs
+// append the category and slug to the version ID
+
+func tofuVersionID(namespace, name, version string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, name, version)
+}
+
+func parseTofuVersionID(id string) (namespace, name, version string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", &ValidationError{
+			Field:   "providerVersionID",
+			Value:   id,
+			Message: "OpenTofu provider version IDs have the form namespace/name/version",
+		}
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func parseTofuDocID(id string) (versionID, category, slug string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 5 || parts[3] == "" || parts[4] == "" {
+		return "", "", "", &ValidationError{
+			Field:   "docID",
+			Value:   id,
+			Message: "OpenTofu doc IDs have the form namespace/name/version/category/slug",
+		}
+	}
+	return strings.Join(parts[:3], "/"), parts[3], parts[4], nil
+}
+
+// getOpenTofuProvider returns a provider with its versions from the OpenTofu docs API
+func (s *ProvidersService) getOpenTofuProvider(ctx context.Context, namespace, name string) (*tofuProvider, error) {
+	path := fmt.Sprintf("providers/%s/%s/index.json", url.PathEscape(namespace), url.PathEscape(name))
+
+	var result tofuProvider
+	if err := s.client.getOpenTofuDocs(ctx, path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// data returns the provider in the shape of the v2 API
+func (p *tofuProvider) data(namespace, name string) ProviderData {
+	return ProviderData{
+		Type: "providers",
+		ID:   namespace + "/" + name,
+		Attributes: ProviderAttributes{
+			Description: p.Description,
+			FullName:    namespace + "/" + name,
+			Name:        name,
+			Namespace:   namespace,
+			Source:      p.Link,
+		},
+	}
+}
+
+// versionData returns the versions of the provider in the shape of the v2 API
+func (p *tofuProvider) versionData(namespace, name string) []VersionData {
+	versions := make([]VersionData, 0, len(p.Versions))
+	for _, v := range p.Versions {
+		version := strings.TrimPrefix(v.ID, "v")
+		versions = append(versions, VersionData{
+			Type: ProviderIncludeVersions,
+			ID:   tofuVersionID(namespace, name, version),
+			Attributes: VersionAttributes{
+				PublishedAt: v.Published,
+				Version:     version,
+			},
+		})
+	}
+	return versions
+}
+
+// versionList returns the provider with its versions in the shape of the v2 API
+func (p *tofuProvider) versionList(namespace, name string) ProviderVersionList {
+	data := p.data(namespace, name)
+	return ProviderVersionList{
+		Data: ProviderVersionData{
+			Type:       data.Type,
+			ID:         data.ID,
+			Attributes: data.Attributes,
+		},
+		Included: p.versionData(namespace, name),
+	}
+}
+
+// version returns a version of the provider in the shape of the v1 API
+func (p *tofuProvider) version(namespace, name, version string) (*Provider, error) {
+	provider := &Provider{
+		ID:          tofuVersionID(namespace, name, version),
+		Owner:       namespace,
+		Namespace:   namespace,
+		Name:        name,
+		Version:     version,
+		Description: p.Description,
+		Source:      p.Link,
+	}
+
+	found := false
+	for _, v := range p.Versions {
+		provider.Versions = append(provider.Versions, strings.TrimPrefix(v.ID, "v"))
+		if NormalizeVersion(v.ID) == NormalizeVersion(version) {
+			found = true
+			provider.PublishedAt = v.Published
+		}
+	}
+	if !found {
+		return nil, &APIError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("provider version %s/%s@%s not found", namespace, name, version),
+		}
+	}
+
+	return provider, nil
+}
+
+// getOpenTofuWithOptions is GetWithOptions for the OpenTofu backend, where versions
+// always come with the provider
+func (s *ProvidersService) getOpenTofuWithOptions(ctx context.Context, namespace, name string, opts *ProviderGetOptions) (*ProviderDetails, error) {
+	provider, err := s.getOpenTofuProvider(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider %s/%s: %w", namespace, name, err)
+	}
+
+	details := &ProviderDetails{Provider: provider.data(namespace, name)}
+	if len(opts.includes()) > 0 {
+		details.Versions = provider.versionData(namespace, name)
+	}
+	if opts != nil && opts.IncludeLatest {
+		details.LatestVersion = tofuLatest(provider.Versions)
+	}
+
+	return details, nil
+}
+
+// getOpenTofuVersion returns the doc listing of a provider version, which is cached
+// because published versions do not change
+func (s *ProvidersService) getOpenTofuVersion(ctx context.Context, providerVersionID string) (*tofuProviderVersion, error) {
+	if cached, ok := s.openTofuVersions.Load(providerVersionID); ok {
+		return cached.(*tofuProviderVersion), nil
+	}
+
+	namespace, name, version, err := parseTofuVersionID(providerVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("providers/%s/%s/v%s/index.json",
+		url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(NormalizeVersion(version)))
+
+	var result tofuProviderVersion
+	if err := s.client.getOpenTofuDocs(ctx, path, &result); err != nil {
+		return nil, err
+	}
+
+	s.openTofuVersions.Store(providerVersionID, &result)
+	return &result, nil
+}
+
+// listOpenTofuDocs is listDocData for the OpenTofu backend
+func (s *ProvidersService) listOpenTofuDocs(ctx context.Context, providerVersionID, category string) ([]ProviderDocData, error) {
+	index, err := s.getOpenTofuVersion(ctx, providerVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []ProviderDocData
+	for _, kind := range tofuDocKinds {
+		if category != "" && category != kind.category {
+			continue
+		}
+		for _, item := range index.items(kind.category) {
+			path := kind.kind + "/" + item.Name + ".md"
+			if kind.kind == "" {
+				path = item.Name + ".md"
+			}
+			doc := ProviderDocData{
+				Type: "provider-docs",
+				ID:   providerVersionID + "/" + kind.category + "/" + item.Name,
+				Attributes: DocAttributes{
+					Category:    kind.category,
+					Language:    "hcl",
+					Path:        path,
+					Slug:        item.Name,
+					Subcategory: item.Subcategory,
+					Title:       item.Title,
+				},
+			}
+			docs = append(docs, doc)
+			s.similarity.addVersion(doc.ID, providerVersionID)
+		}
+	}
+
+	return docs, nil
+}
+
+// listOpenTofuProviderDocs is ListDocs for the OpenTofu backend
+func (s *ProvidersService) listOpenTofuProviderDocs(ctx context.Context, namespace, name, version string) (*ProviderDocs, error) {
+	provider, err := s.getOpenTofuProvider(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider docs: %w", err)
+	}
+	details, err := provider.version(namespace, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider docs: %w", err)
+	}
+
+	docs, err := s.listOpenTofuDocs(ctx, details.ID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider docs: %w", err)
+	}
+
+	result := &ProviderDocs{Provider: *details}
+	for _, doc := range docs {
+		attrs := doc.Attributes
+		result.Docs = append(result.Docs, ProviderDoc{
+			ID:          doc.ID,
+			Title:       attrs.Title,
+			Path:        attrs.Path,
+			Slug:        attrs.Slug,
+			Category:    attrs.Category,
+			Subcategory: attrs.Subcategory,
+			Language:    attrs.Language,
+		})
+	}
+
+	return result, nil
+}
+
+// listOpenTofuDocsV2 is ListDocsV2 for the OpenTofu backend, which lists every doc of a
+// version at once; pages are cut from the listing
+func (s *ProvidersService) listOpenTofuDocsV2(ctx context.Context, opts *ProviderDocListOptions) ([]ProviderData, error) {
+	docs, err := s.listOpenTofuDocs(ctx, opts.ProviderVersionID, opts.Category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provider docs: %w", err)
+	}
+
+	var result []ProviderData
+	for _, doc := range docs {
+		attrs := doc.Attributes
+		if (opts.Subcategory != "" && attrs.Subcategory != opts.Subcategory) || (opts.Slug != "" && attrs.Slug != opts.Slug) {
+			continue
+		}
+		result = append(result, ProviderData{
+			Type:       doc.Type,
+			ID:         doc.ID,
+			Attributes: ProviderAttributes{Name: attrs.Slug},
+		})
+	}
+
+	if opts.Page > 0 {
+		const pageSize = 50
+		start := min((opts.Page-1)*pageSize, len(result))
+		result = result[start:min(start+pageSize, len(result))]
+	}
+
+	return result, nil
+}
+
+// getOpenTofuDoc is GetDoc for the OpenTofu backend, which serves doc content as Markdown
+func (s *ProvidersService) getOpenTofuDoc(ctx context.Context, docID string) (*ProviderDocDetails, error) {
+	versionID, category, slug, err := parseTofuDocID(docID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.listOpenTofuDocs(ctx, versionID, category)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs {
+		if doc.Attributes.Slug != slug {
+			continue
+		}
+
+		namespace, name, version, _ := parseTofuVersionID(versionID)
+		path := fmt.Sprintf("providers/%s/%s/v%s/%s",
+			url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(NormalizeVersion(version)), doc.Attributes.Path)
+
+		var content rawBody
+		if err := s.client.getOpenTofuDocs(ctx, path, &content); err != nil {
+			return nil, err
+		}
+		doc.Attributes.Content = string(content)
+
+		return &ProviderDocDetails{Data: doc}, nil
+	}
+
+	return nil, &APIError{
+		StatusCode: 404,
+		Message:    fmt.Sprintf("provider doc %s not found", docID),
+	}
+}
+
+// tofuModule is a module with its versions in the OpenTofu docs API
+type tofuModule struct {
+	Description string        `json:"description"`
+	Versions    []tofuVersion `json:"versions"`
+}
+
+// tofuModulePart is the root, a submodule or an example of a module version in the
+// OpenTofu docs API
+type tofuModulePart struct {
+	Variables map[string]struct {
+		Type        json.RawMessage `json:"type"`
+		Default     json.RawMessage `json:"default"`
+		Description string          `json:"description"`
+		Required    bool            `json:"required"`
+	} `json:"variables"`
+	Outputs map[string]struct {
+		Description string `json:"description"`
+	} `json:"outputs"`
+	Providers []struct {
+		Name              string `json:"name"`
+		FullName          string `json:"full_name"`
+		VersionConstraint string `json:"version_constraint"`
+	} `json:"providers"`
+	Dependencies []struct {
+		Name              string `json:"name"`
+		Source            string `json:"source"`
+		VersionConstraint string `json:"version_constraint"`
+	} `json:"dependencies"`
+	Resources []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	} `json:"resources"`
+}
+
+// tofuModuleVersion is a module version in the OpenTofu docs API
+type tofuModuleVersion struct {
+	tofuModulePart
+	ID            string                    `json:"id"`
+	Published     time.Time                 `json:"published"`
+	VCSRepository string                    `json:"vcs_repository"`
+	Submodules    map[string]tofuModulePart `json:"submodules"`
+	Examples      map[string]tofuModulePart `json:"examples"`
+}
+
+// part returns the module part in the shape of the v1 API
+func (p *tofuModulePart) part(path, name string) ModulePart {
+	part := ModulePart{
+		Path:  path,
+		Name:  name,
+		Empty: len(p.Variables) == 0 && len(p.Outputs) == 0 && len(p.Resources) == 0,
+	}
+
+	for name, variable := range p.Variables {
+		// Types are strings in the docs API; other JSON is kept as written
+		typ := string(variable.Type)
+		var s string
+		if json.Unmarshal(variable.Type, &s) == nil {
+			typ = s
+		}
+		part.Inputs = append(part.Inputs, ModuleInput{
+			Name:        name,
+			Type:        typ,
+			Description: variable.Description,
+			Default:     variable.Default,
+			Required:    variable.Required,
+		})
+	}
+	sort.Slice(part.Inputs, func(i, j int) bool { return part.Inputs[i].Name < part.Inputs[j].Name })
+
+	for name, output := range p.Outputs {
+		part.Outputs = append(part.Outputs, ModuleOutput{Name: name, Description: output.Description})
+	}
+	sort.Slice(part.Outputs, func(i, j int) bool { return part.Outputs[i].Name < part.Outputs[j].Name })
+
+	for _, provider := range p.Providers {
+		namespace, _, _ := strings.Cut(provider.FullName, "/")
+		part.ProviderDependencies = append(part.ProviderDependencies, ModuleProviderDependency{
+			Name:      provider.Name,
+			Namespace: namespace,
+			Source:    provider.FullName,
+			Version:   provider.VersionConstraint,
+		})
+	}
+
+	for _, dependency := range p.Dependencies {
+		part.Dependencies = append(part.Dependencies, ModuleDependency{
+			Name:    dependency.Name,
+			Source:  dependency.Source,
+			Version: dependency.VersionConstraint,
+		})
+	}
+
+	for _, resource := range p.Resources {
+		part.Resources = append(part.Resources, ModuleResource{Name: resource.Name, Type: resource.Type})
+	}
+
+	return part
+}
+
+// getOpenTofuDetails is getDetails for the OpenTofu backend, reading the module, the
+// version and its README from the docs API
+func (s *ModulesService) getOpenTofuDetails(ctx context.Context, moduleID string) (*ModuleDetails, error) {
+	parts := strings.Split(moduleID, "/")
+	if len(parts) < 3 {
+		return nil, &ValidationError{
+			Field:   "moduleID",
+			Value:   moduleID,
+			Message: "invalid module ID format, expected namespace/name/provider[/version]",
+		}
+	}
+	namespace, name, target := parts[0], parts[1], parts[2]
+	base := fmt.Sprintf("modules/%s/%s/%s", url.PathEscape(namespace), url.PathEscape(name), url.PathEscape(target))
+
+	var module tofuModule
+	if err := s.client.getOpenTofuDocs(ctx, base+"/index.json", &module); err != nil {
+		return nil, err
+	}
+
+	version := tofuLatest(module.Versions)
+	if len(parts) > 3 {
+		version = parts[3]
+	}
+	if version == "" {
+		return nil, fmt.Errorf("%w for module %s/%s/%s", ErrNoVersions, namespace, name, target)
+	}
+	base += "/v" + url.PathEscape(NormalizeVersion(version))
+
+	var details tofuModuleVersion
+	if err := s.client.getOpenTofuDocs(ctx, base+"/index.json", &details); err != nil {
+		return nil, err
+	}
+
+	// Modules without a README answer 404
+	var readme rawBody
+	if err := s.client.getOpenTofuDocs(ctx, base+"/README.md", &readme); err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
+	result := &ModuleDetails{
+		Module: Module{
+			ID:          fmt.Sprintf("%s/%s/%s/%s", namespace, name, target, version),
+			Owner:       namespace,
+			Namespace:   namespace,
+			Name:        name,
+			Version:     version,
+			Provider:    target,
+			Description: module.Description,
+			Source:      details.VCSRepository,
+			PublishedAt: details.Published,
+		},
+		Root: details.part("", name),
+	}
+	result.Root.Readme = string(readme)
+
+	for _, v := range module.Versions {
+		result.Versions = append(result.Versions, strings.TrimPrefix(v.ID, "v"))
+	}
+	for _, dependency := range result.Root.ProviderDependencies {
+		result.Providers = append(result.Providers, dependency.Name)
+	}
+
+	for _, submodule := range slices.Sorted(maps.Keys(details.Submodules)) {
+		part := details.Submodules[submodule]
+		result.Submodules = append(result.Submodules, part.part("modules/"+submodule, submodule))
+	}
+	for _, example := range slices.Sorted(maps.Keys(details.Examples)) {
+		part := details.Examples[example]
+		result.Examples = append(result.Examples, part.part("examples/"+example, example))
+	}
+
+	return result, nil
+}
+
+// tofuSearchResult is a result of the OpenTofu docs API search
+type tofuSearchResult struct {
+	Type          string            `json:"type"`
+	Description   string            `json:"description"`
+	LinkVariables map[string]string `json:"link_variables"`
+}
+
+// searchOpenTofu is Search for the OpenTofu backend. The docs API searches providers,
+// their docs and modules at once and returns one page, from which modules are kept.
+func (s *ModulesService) searchOpenTofu(ctx context.Context, query string, offset int) (*ModuleList, error) {
+	var results []tofuSearchResult
+	if err := s.client.getOpenTofuDocs(ctx, "search?q="+url.QueryEscape(query), &results); err != nil {
+		return nil, fmt.Errorf("failed to search modules: %w", err)
+	}
+
+	list := &ModuleList{Meta: ModuleMeta{CurrentOffset: offset}}
+	for _, result := range results {
+		if result.Type != "module" {
+			continue
+		}
+		vars := result.LinkVariables
+		version := strings.TrimPrefix(vars["version"], "v")
+		list.Modules = append(list.Modules, Module{
+			ID:          fmt.Sprintf("%s/%s/%s/%s", vars["namespace"], vars["name"], vars["target"], version),
+			Owner:       vars["namespace"],
+			Namespace:   vars["namespace"],
+			Name:        vars["name"],
+			Version:     version,
+			Provider:    vars["target"],
+			Description: result.Description,
+		})
+	}
+
+	list.Modules = list.Modules[min(offset, len(list.Modules)):]
+	list.Meta.Limit = len(list.Modules)
+
+	return list, nil
+}
@@ -0,0 +1,222 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// codeownersPaths are the locations GitHub reads a CODEOWNERS file from, in its order
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeownersRule is a line of a CODEOWNERS file: a path pattern and its owners
+type CodeownersRule struct {
+	Pattern string
+
+	// Owners are users and teams with their "@" prefix, or email addresses
+	Owners []string
+}
+
+// ModuleOwnership describes who owns a module, from the CODEOWNERS file of its GitHub
+// source repository
+type ModuleOwnership struct {
+	// Module is the module with its resolved version
+	Module ModuleRef
+
+	// Repository is the source repository in owner/repo form
+	Repository string
+
+	// CodeownersPath is where the CODEOWNERS file was found; empty when it has none
+	CodeownersPath string
+
+	// Rules are the CODEOWNERS rules in file order
+	Rules []CodeownersRule
+}
+
+// Owners returns the owners of the repository root, the contacts for the module as a
+// whole; nil when no rule covers it
+func (o *ModuleOwnership) Owners() []string {
+	return o.OwnersOf("main.tf")
+}
+
+// OwnersOf returns the owners of a file path in the repository. As in GitHub, the last
+// matching rule wins, and a matching rule without owners leaves the path unowned.
+func (o *ModuleOwnership) OwnersOf(file string) []string {
+	file = strings.TrimPrefix(file, "/")
+	for i := len(o.Rules) - 1; i >= 0; i-- {
+		if codeownersMatch(o.Rules[i].Pattern, file) {
+			return o.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// ParseCodeowners parses a CODEOWNERS file, skipping comments and blank lines
+func ParseCodeowners(content string) []CodeownersRule {
+	var rules []CodeownersRule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 && (i == 0 || line[i-1] != '\\') {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, CodeownersRule{Pattern: fields[0], Owners: fields[1:]})
+	}
+	return rules
+}
+
+// codeownersMatch reports whether a CODEOWNERS pattern matches a file path. Patterns
+// follow gitignore rules: a leading or inner "/" anchors the pattern at the root, a
+// trailing "/" matches a directory, "**" matches any number of directories, and other
+// patterns match a file or directory name anywhere.
+func codeownersMatch(pattern, file string) bool {
+	if pattern == "*" || pattern == "/**" || pattern == "**" {
+		return true
+	}
+
+	dir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/**")
+
+	// A trailing "/*" only matches files directly in the directory
+	if anchored && strings.HasSuffix(pattern, "/*") {
+		return globMatch(pattern, file)
+	}
+
+	segments := strings.Split(file, "/")
+	if !dir {
+		// A pattern naming a file matches it, or everything below a directory of that name
+		segments = append(segments, "")
+	}
+
+	// Try every prefix of the path, which is a file or one of its directories
+	for end := 1; end < len(segments); end++ {
+		candidate := strings.Join(segments[:end], "/")
+		if anchored {
+			if globMatch(pattern, candidate) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, segments[end-1]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches a path against a pattern whose "**" segments match any number of
+// directories
+func globMatch(pattern, name string) bool {
+	if !strings.Contains(pattern, "**") {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}
+
+	prefix, rest, _ := strings.Cut(pattern, "**")
+	prefix = strings.TrimSuffix(prefix, "/")
+	rest = strings.TrimPrefix(rest, "/")
+
+	segments := strings.Split(name, "/")
+	for i := 0; i <= len(segments); i++ {
+		head := strings.Join(segments[:i], "/")
+		if prefix != "" {
+			if ok, _ := path.Match(prefix, head); !ok {
+				continue
+			}
+		}
+		for j := i; j <= len(segments); j++ {
+			if globMatch(rest, strings.Join(segments[j:], "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetOwnership returns the CODEOWNERS rules of a module's GitHub source repository. An
+// empty ref.Version uses the latest version. A repository without a CODEOWNERS file
+// gives ownership without rules; sources outside GitHub are a validation error.
+func (s *ModulesService) GetOwnership(ctx context.Context, ref ModuleRef) (*ModuleOwnership, error) {
+	if err := validateModuleParams(ref.Namespace, ref.Name, ref.Provider, ""); err != nil {
+		return nil, err
+	}
+
+	var details *ModuleDetails
+	var err error
+	if ref.Version == "" || ref.Version == "latest" {
+		details, err = s.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	} else {
+		details, err = s.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(details.Source)
+	segments := sourceRepoPath(details.Source)
+	if err != nil || !strings.EqualFold(u.Host, "github.com") || len(segments) < 2 {
+		return nil, &ValidationError{
+			Field:   "source",
+			Value:   details.Source,
+			Message: "module source is not a GitHub repository",
+		}
+	}
+
+	ownership := &ModuleOwnership{
+		Module:     ModuleRef{Namespace: ref.Namespace, Name: ref.Name, Provider: ref.Provider, Version: details.Version},
+		Repository: segments[0] + "/" + segments[1],
+	}
+
+	security := &SecurityService{client: s.client}
+	for _, file := range codeownersPaths {
+		content, err := security.getRepositoryFile(ctx, segments[0], segments[1], file)
+		if IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CODEOWNERS of %s: %w", ownership.Repository, err)
+		}
+		ownership.CodeownersPath = file
+		ownership.Rules = ParseCodeowners(content)
+		break
+	}
+
+	return ownership, nil
+}
+
+// getRepositoryFile reads a file of a GitHub repository's default branch
+func (s *SecurityService) getRepositoryFile(ctx context.Context, owner, repo, file string) (string, error) {
+	baseURL := s.client.config.GitHubAPIURL
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/contents/%s", strings.TrimSuffix(baseURL, "/"),
+		url.PathEscape(owner), url.PathEscape(repo), file)
+
+	var result struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := s.githubGet(ctx, endpoint, &result); err != nil {
+		return "", err
+	}
+
+	if result.Encoding != "base64" {
+		return result.Content, nil
+	}
+	// GitHub wraps base64 content in lines
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(result.Content, "\n", ""))
+	if err != nil {
+		return "", &ResponseError{StatusCode: 200, Err: fmt.Errorf("error decoding %s: %w", file, err)}
+	}
+	return string(content), nil
+}
@@ -2,16 +2,27 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
 )
 
+// DefaultPolicyContentFetches is the default number of policy READMEs fetched
+// per content search
+const DefaultPolicyContentFetches = 20
+
 // PoliciesService handles communication with the policy related
 // methods of the Terraform Registry API.
 type PoliciesService struct {
 	client *Client
+
+	// contentCache holds description/README text keyed by policy version ID
+	contentCache sync.Map
 }
 
 // PolicyListOptions specifies optional parameters to the List method
@@ -88,6 +99,37 @@ func (s *PoliciesService) List(ctx context.Context, opts *PolicyListOptions) (*P
 	return &result, nil
 }
 
+// ListAll returns an iterator over all policies, following the page pagination from
+// opts.Page. Pages hold opts.PageSize policies, or 100 when unset. Included latest
+// versions are not available through the iterator; use List for them.
+func (s *PoliciesService) ListAll(ctx context.Context, opts *PolicyListOptions) *Iterator[Policy] {
+	if err := opts.Validate(); err != nil {
+		return failedIterator[Policy](err)
+	}
+
+	page := PolicyListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	if page.PageSize == 0 {
+		page.PageSize = 100
+	}
+
+	policyID := func(item Policy) string { return item.ID }
+	return newIterator(ctx, policyID, func(ctx context.Context) ([]Policy, bool, error) {
+		list, err := s.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		page.Page = nextPage(list.Meta.Pagination, page.Page, len(list.Data))
+		return list.Data, page.Page != 0, nil
+	})
+}
+
 // Get returns details about a specific policy version
 func (s *PoliciesService) Get(ctx context.Context, namespace, name, version string) (*PolicyDetails, error) {
 	if err := validatePolicyParams(namespace, name, version); err != nil {
@@ -128,8 +170,120 @@ func (s *PoliciesService) GetByID(ctx context.Context, policyID string) (*Policy
 	return s.Get(ctx, namespace, name, version)
 }
 
+// PolicySearchOptions specifies optional parameters to the SearchWithOptions method
+type PolicySearchOptions struct {
+	// IncludeContent also matches the query against policy descriptions and READMEs
+	IncludeContent bool
+
+	// MaxContentFetches bounds how many policy versions may be fetched to obtain
+	// README content that the list response did not include (default 20)
+	MaxContentFetches int
+}
+
+// Validate validates the policy search options
+func (o *PolicySearchOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if o.MaxContentFetches < 0 {
+		return &ValidationError{
+			Field:   "MaxContentFetches",
+			Value:   o.MaxContentFetches,
+			Message: "max content fetches cannot be negative",
+		}
+	}
+
+	return nil
+}
+
+// PolicyBatch holds the results of a GetMany call
+type PolicyBatch struct {
+	// Results maps each requested policy ID to its details or error
+	Results map[string]BatchResult[*PolicyDetails]
+
+	// Included holds the included policies, modules and libraries of all results,
+	// deduplicated and keyed by "type/id"
+	Included map[string]PolicyIncluded
+}
+
+// Err returns the per-item errors combined, or nil if every item succeeded
+func (b *PolicyBatch) Err() error {
+	var errs MultiError
+	for id, result := range b.Results {
+		if result.Err != nil {
+			errs.Add(fmt.Errorf("policy %s: %w", id, result.Err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// IncludedFor returns the included items related to a policy from the shared index
+func (b *PolicyBatch) IncludedFor(policyID string) []PolicyIncluded {
+	result, ok := b.Results[policyID]
+	if !ok || result.Value == nil {
+		return nil
+	}
+
+	relations := result.Value.Data.Relationships
+	identifiers := make([]ResourceIdentifier, 0, len(relations.Policies.Data)+len(relations.PolicyModules.Data)+1)
+	identifiers = append(identifiers, relations.Policies.Data...)
+	identifiers = append(identifiers, relations.PolicyModules.Data...)
+	if relations.PolicyLibrary.Data.ID != "" {
+		identifiers = append(identifiers, relations.PolicyLibrary.Data)
+	}
+
+	included := make([]PolicyIncluded, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		if item, ok := b.Included[identifier.Type+"/"+identifier.ID]; ok {
+			included = append(included, item)
+		}
+	}
+
+	return included
+}
+
+// GetMany fetches several policies by ID concurrently. Failures are reported per ID in
+// the returned batch rather than failing the whole call; included data from all
+// responses is merged into a single deduplicated index.
+func (s *PoliciesService) GetMany(ctx context.Context, policyIDs []string) (*PolicyBatch, error) {
+	if len(policyIDs) == 0 {
+		return nil, &ValidationError{
+			Field:   "policyIDs",
+			Message: "at least one policy ID is required",
+		}
+	}
+
+	results := runBatch(ctx, policyIDs, DefaultBatchConcurrency, s.GetByID)
+
+	batch := &PolicyBatch{
+		Results:  results,
+		Included: make(map[string]PolicyIncluded),
+	}
+
+	for _, result := range results {
+		if result.Value == nil {
+			continue
+		}
+		for _, included := range result.Value.Included {
+			batch.Included[included.Type+"/"+included.ID] = included
+		}
+	}
+
+	return batch, nil
+}
+
 // Search searches for policies based on a query string
 func (s *PoliciesService) Search(ctx context.Context, query string) ([]PolicySearchResult, error) {
+	return s.SearchWithOptions(ctx, query, nil)
+}
+
+// SearchWithOptions searches for policies, optionally matching description and README content
+func (s *PoliciesService) SearchWithOptions(ctx context.Context, query string, opts *PolicySearchOptions) ([]PolicySearchResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	if query == "" {
 		return nil, &ValidationError{
 			Field:   "query",
@@ -140,6 +294,7 @@ func (s *PoliciesService) Search(ctx context.Context, query string) ([]PolicySea
 
 	// Get all policies (pagination handled internally)
 	allPolicies := []Policy{}
+	latestVersions := make(map[string]PolicyVersionIncluded)
 	page := 1
 	maxPages := 100 // Prevent infinite loops
 
@@ -156,6 +311,9 @@ func (s *PoliciesService) Search(ctx context.Context, query string) ([]PolicySea
 		}
 
 		allPolicies = append(allPolicies, result.Data...)
+		for _, included := range result.Included {
+			latestVersions[included.ID] = included
+		}
 
 		// Check if there are more pages
 		if result.Meta.Pagination.NextPage == 0 {
@@ -170,9 +328,17 @@ func (s *PoliciesService) Search(ctx context.Context, query string) ([]PolicySea
 	queryLower := strings.ToLower(query)
 	queryParts := strings.Fields(queryLower)
 
+	var contents map[string]string
+	if opts != nil && opts.IncludeContent {
+		contents = s.collectContent(ctx, allPolicies, latestVersions, opts.MaxContentFetches)
+	}
+
 	for _, policy := range allPolicies {
 		// Calculate match score
 		matchScore := calculatePolicyMatchScore(policy, queryLower, queryParts)
+		if content, ok := contents[policy.ID]; ok {
+			matchScore += calculateContentMatchScore(content, queryLower, queryParts)
+		}
 
 		if matchScore > 0 {
 			searchResult := PolicySearchResult{
@@ -191,6 +357,118 @@ func (s *PoliciesService) Search(ctx context.Context, query string) ([]PolicySea
 	return searchResults, nil
 }
 
+// collectContent gathers lowercase description/README text for each policy, keyed by policy ID.
+// Text embedded in the list response is used directly; missing READMEs are fetched for at
+// most maxFetches policies (most downloaded first) and cached per policy version.
+func (s *PoliciesService) collectContent(ctx context.Context, policies []Policy, latestVersions map[string]PolicyVersionIncluded, maxFetches int) map[string]string {
+	if maxFetches == 0 {
+		maxFetches = DefaultPolicyContentFetches
+	}
+
+	contents := make(map[string]string, len(policies))
+	var missing []Policy
+
+	for _, policy := range policies {
+		versionID := policy.Relationships.LatestVersion.Data.ID
+		if cached, ok := s.contentCache.Load(versionID); ok {
+			contents[policy.ID] = cached.(string)
+			continue
+		}
+
+		included, ok := latestVersions[versionID]
+		if !ok {
+			continue
+		}
+
+		if included.Attributes.Readme != "" {
+			content := strings.ToLower(included.Attributes.Description + "\n" + included.Attributes.Readme)
+			s.contentCache.Store(versionID, content)
+			contents[policy.ID] = content
+			continue
+		}
+
+		contents[policy.ID] = strings.ToLower(included.Attributes.Description)
+		missing = append(missing, policy)
+	}
+
+	sort.SliceStable(missing, func(i, j int) bool {
+		return missing[i].Attributes.Downloads > missing[j].Attributes.Downloads
+	})
+	if len(missing) > maxFetches {
+		missing = missing[:maxFetches]
+	}
+
+	for _, policy := range missing {
+		versionID := policy.Relationships.LatestVersion.Data.ID
+		version := latestVersions[versionID].Attributes.Version
+
+		details, err := s.Get(ctx, policy.Attributes.Namespace, policy.Attributes.Name, version)
+		if err != nil {
+			s.client.logger.Debugf("Skipping content for policy %s: %v", policy.ID, err)
+			warn(ctx, Warning{
+				Code:     WarningSkippedPolicyContent,
+				Resource: policy.ID,
+				Message:  fmt.Sprintf("content for policy %s could not be fetched and is not searched", policy.ID),
+				Err:      err,
+			})
+			continue
+		}
+
+		attrs := details.Data.Attributes
+		content := strings.ToLower(attrs.Description + "\n" + attrs.Readme)
+		s.contentCache.Store(versionID, content)
+		contents[policy.ID] = content
+	}
+
+	return contents
+}
+
+// exportState implements stateSection for the policy content cache
+func (s *PoliciesService) exportState() (json.RawMessage, error) {
+	contents := make(map[string]string)
+	s.contentCache.Range(func(key, value any) bool {
+		contents[key.(string)] = value.(string)
+		return true
+	})
+	return json.Marshal(contents)
+}
+
+// importState implements stateSection for the policy content cache
+func (s *PoliciesService) importState(data json.RawMessage) error {
+	var contents map[string]string
+	if err := json.Unmarshal(data, &contents); err != nil {
+		return err
+	}
+	for key, value := range contents {
+		s.contentCache.LoadOrStore(key, value)
+	}
+	return nil
+}
+
+// calculateContentMatchScore scores a query against description/README text.
+// Content matches weigh less than name or title matches.
+func calculateContentMatchScore(content, queryLower string, queryParts []string) float64 {
+	if content == "" {
+		return 0
+	}
+
+	if strings.Contains(content, queryLower) {
+		return 2.0
+	}
+
+	if len(queryParts) == 0 {
+		return 0
+	}
+
+	for _, part := range queryParts {
+		if !strings.Contains(content, part) {
+			return 0
+		}
+	}
+
+	return 1.0
+}
+
 // calculatePolicyMatchScore calculates the relevance score for a policy
 func calculatePolicyMatchScore(policy Policy, queryLower string, queryParts []string) float64 {
 	relevance := 0.0
@@ -284,6 +562,11 @@ func (s *PoliciesService) GetSentinelContent(ctx context.Context, policyID strin
 		case "policy-modules":
 			if included.Attributes.Name == "" || included.Attributes.Shasum == "" {
 				s.client.logger.Warnf("Skipping policy module with missing data: %+v", included)
+				warn(ctx, Warning{
+					Code:     WarningSkippedPolicyItem,
+					Resource: included.ID,
+					Message:  fmt.Sprintf("policy module %q in %s has missing data and was skipped", included.Attributes.Name, policyID),
+				})
 				continue
 			}
 
@@ -297,6 +580,11 @@ func (s *PoliciesService) GetSentinelContent(ctx context.Context, policyID strin
 		case "policies":
 			if included.Attributes.Name == "" || included.Attributes.Shasum == "" {
 				s.client.logger.Warnf("Skipping policy with missing data: %+v", included)
+				warn(ctx, Warning{
+					Code:     WarningSkippedPolicyItem,
+					Resource: included.ID,
+					Message:  fmt.Sprintf("policy %q in %s has missing data and was skipped", included.Attributes.Name, policyID),
+				})
 				continue
 			}
 
@@ -390,7 +678,7 @@ func validatePolicyParams(namespace, name, version string) error {
 			Value:   namespace,
 			Message: "namespace cannot be empty",
 		})
-	} else if !isValidNamespace(namespace) {
+	} else if !validate.Namespace(namespace) {
 		errs.Add(&ValidationError{
 			Field:   "namespace",
 			Value:   namespace,
@@ -404,7 +692,7 @@ func validatePolicyParams(namespace, name, version string) error {
 			Value:   name,
 			Message: "name cannot be empty",
 		})
-	} else if !isValidPolicyName(name) {
+	} else if !validate.PolicyName(name) {
 		errs.Add(&ValidationError{
 			Field:   "name",
 			Value:   name,
@@ -418,7 +706,7 @@ func validatePolicyParams(namespace, name, version string) error {
 			Value:   version,
 			Message: "version cannot be empty",
 		})
-	} else if !isValidVersion(version) {
+	} else if !validate.Version(version) {
 		errs.Add(&ValidationError{
 			Field:   "version",
 			Value:   version,
@@ -429,28 +717,14 @@ func validatePolicyParams(namespace, name, version string) error {
 	return errs.ErrorOrNil()
 }
 
-// isValidPolicyName validates policy name format
-func isValidPolicyName(name string) bool {
-	// Policy name should contain only alphanumeric characters, hyphens, and underscores
-	for _, r := range name {
-		if !isAlphaNumeric(r) && r != '-' && r != '_' {
-			return false
-		}
-	}
-	return true
-}
-
 // validateEnforcementLevel validates Sentinel enforcement level
 func validateEnforcementLevel(level string) error {
-	validLevels := []string{"advisory", "soft-mandatory", "hard-mandatory"}
-	for _, valid := range validLevels {
-		if level == valid {
-			return nil
-		}
+	if validate.EnforcementLevel(level) {
+		return nil
 	}
 	return &ValidationError{
 		Field:   "enforcementLevel",
 		Value:   level,
-		Message: fmt.Sprintf("invalid enforcement level, must be one of: %s", strings.Join(validLevels, ", ")),
+		Message: fmt.Sprintf("invalid enforcement level, must be one of: %s", strings.Join(validate.EnforcementLevels, ", ")),
 	}
 }
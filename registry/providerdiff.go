@@ -0,0 +1,263 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DocChangeKind is how a doc differs between two provider versions
+type DocChangeKind string
+
+const (
+	// DocAdded is a doc only published with the newer version
+	DocAdded DocChangeKind = "added"
+
+	// DocRemoved is a doc only published with the older version
+	DocRemoved DocChangeKind = "removed"
+
+	// DocChanged is a doc published with both versions whose title, subcategory or
+	// content differs
+	DocChanged DocChangeKind = "changed"
+)
+
+// diffCategories are the doc categories compared by DiffVersions, in report order
+var diffCategories = []string{"resources", "data-sources", "functions"}
+
+// DocChange is a resource, data source or function doc that differs between versions
+type DocChange struct {
+	Kind  DocChangeKind `json:"kind"`
+	Slug  string        `json:"slug"`
+	Title string        `json:"title"`
+
+	// FromID and ToID are the doc IDs in the older and newer version; one of them is
+	// empty for added and removed docs
+	FromID string `json:"from_id,omitempty"`
+	ToID   string `json:"to_id,omitempty"`
+
+	// Fields lists what changed in a changed doc: "title", "subcategory" and "content"
+	Fields []string `json:"fields,omitempty"`
+}
+
+// SubcategoryDiff is the doc changes within one subcategory, such as "EC2 (Elastic
+// Compute Cloud)". Docs moved to another subcategory are listed under the new one.
+type SubcategoryDiff struct {
+	// Subcategory is empty for docs without one
+	Subcategory string `json:"subcategory"`
+
+	Added   []DocChange `json:"added,omitempty"`
+	Removed []DocChange `json:"removed,omitempty"`
+	Changed []DocChange `json:"changed,omitempty"`
+}
+
+// CategoryDiff is the doc changes of one category grouped by subcategory
+type CategoryDiff struct {
+	Category string `json:"category"`
+
+	// Subcategories are sorted by name and only include those with changes
+	Subcategories []SubcategoryDiff `json:"subcategories,omitempty"`
+}
+
+// Count returns the number of changes of a kind in the category
+func (d CategoryDiff) Count(kind DocChangeKind) int {
+	n := 0
+	for _, sub := range d.Subcategories {
+		switch kind {
+		case DocAdded:
+			n += len(sub.Added)
+		case DocRemoved:
+			n += len(sub.Removed)
+		case DocChanged:
+			n += len(sub.Changed)
+		}
+	}
+	return n
+}
+
+// ProviderVersionDiff is the difference between the docs of two provider versions
+type ProviderVersionDiff struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+
+	// Categories holds the resources, data-sources and functions diffs, in that order
+	Categories []CategoryDiff `json:"categories"`
+}
+
+// Category returns the diff of a category such as "resources"
+func (d *ProviderVersionDiff) Category(category string) CategoryDiff {
+	for _, c := range d.Categories {
+		if c.Category == category {
+			return c
+		}
+	}
+	return CategoryDiff{Category: category}
+}
+
+// Empty reports whether the versions have the same docs
+func (d *ProviderVersionDiff) Empty() bool {
+	for _, c := range d.Categories {
+		if len(c.Subcategories) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// JSON returns the diff as indented JSON
+func (d *ProviderVersionDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// DiffVersions compares the resource, data source and function docs of two versions of
+// a provider and reports the docs added, removed and changed between them, grouped by
+// category and subcategory. Docs are matched by slug. Docs published with both versions
+// are fetched to compare their content, so diffing large providers takes one request
+// per doc; fetches that fail are reported in the returned MultiError alongside the diff,
+// and their docs are compared by title and subcategory only.
+func (s *ProvidersService) DiffVersions(ctx context.Context, namespace, name, fromVersion, toVersion string) (*ProviderVersionDiff, error) {
+	if err := validateProviderParams(namespace, name); err != nil {
+		return nil, err
+	}
+	if fromVersion == "" || toVersion == "" {
+		return nil, &ValidationError{
+			Field:   "version",
+			Value:   fromVersion + ".." + toVersion,
+			Message: "both versions are required",
+		}
+	}
+
+	fromID, err := s.GetVersionID(ctx, namespace, name, fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve version %s: %w", fromVersion, err)
+	}
+	toID, err := s.GetVersionID(ctx, namespace, name, toVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve version %s: %w", toVersion, err)
+	}
+
+	diff := &ProviderVersionDiff{
+		Namespace:   namespace,
+		Name:        name,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+	}
+	var errs MultiError
+
+	for _, category := range diffCategories {
+		fromDocs, err := s.listDocData(ctx, fromID, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s of %s: %w", category, fromVersion, err)
+		}
+		toDocs, err := s.listDocData(ctx, toID, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s of %s: %w", category, toVersion, err)
+		}
+
+		diff.Categories = append(diff.Categories, s.diffDocs(ctx, category, fromDocs, toDocs, &errs))
+	}
+
+	return diff, errs.ErrorOrNil()
+}
+
+// diffDocs compares the docs of one category, fetching the content of docs published
+// with both versions and adding fetch failures to errs
+func (s *ProvidersService) diffDocs(ctx context.Context, category string, fromDocs, toDocs []ProviderDocData, errs *MultiError) CategoryDiff {
+	from := make(map[string]ProviderDocData, len(fromDocs))
+	for _, doc := range fromDocs {
+		from[doc.Attributes.Slug] = doc
+	}
+	to := make(map[string]ProviderDocData, len(toDocs))
+	for _, doc := range toDocs {
+		to[doc.Attributes.Slug] = doc
+	}
+
+	// Fetch the content of both versions of every doc present in both
+	var ids []string
+	for slug, doc := range to {
+		if old, ok := from[slug]; ok {
+			ids = append(ids, old.ID, doc.ID)
+		}
+	}
+	contents := runBatch(ctx, ids, DefaultBatchConcurrency, func(ctx context.Context, id string) (string, error) {
+		details, err := s.GetDoc(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(details.Data.Attributes.Content), nil
+	})
+
+	subcategories := make(map[string]*SubcategoryDiff)
+	subcategory := func(name string) *SubcategoryDiff {
+		if sub, ok := subcategories[name]; ok {
+			return sub
+		}
+		sub := &SubcategoryDiff{Subcategory: name}
+		subcategories[name] = sub
+		return sub
+	}
+
+	for slug, doc := range to {
+		attrs := doc.Attributes
+		old, ok := from[slug]
+		if !ok {
+			sub := subcategory(attrs.Subcategory)
+			sub.Added = append(sub.Added, DocChange{Kind: DocAdded, Slug: slug, Title: attrs.Title, ToID: doc.ID})
+			continue
+		}
+
+		var fields []string
+		if old.Attributes.Title != attrs.Title {
+			fields = append(fields, "title")
+		}
+		if old.Attributes.Subcategory != attrs.Subcategory {
+			fields = append(fields, "subcategory")
+		}
+		oldContent, newContent := contents[old.ID], contents[doc.ID]
+		switch {
+		case oldContent.Err != nil:
+			errs.Add(fmt.Errorf("failed to fetch %s doc %s: %w", category, slug, oldContent.Err))
+		case newContent.Err != nil:
+			errs.Add(fmt.Errorf("failed to fetch %s doc %s: %w", category, slug, newContent.Err))
+		case oldContent.Value != newContent.Value:
+			fields = append(fields, "content")
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		sub := subcategory(attrs.Subcategory)
+		sub.Changed = append(sub.Changed, DocChange{
+			Kind:   DocChanged,
+			Slug:   slug,
+			Title:  attrs.Title,
+			FromID: old.ID,
+			ToID:   doc.ID,
+			Fields: fields,
+		})
+	}
+
+	for slug, doc := range from {
+		if _, ok := to[slug]; ok {
+			continue
+		}
+		sub := subcategory(doc.Attributes.Subcategory)
+		sub.Removed = append(sub.Removed, DocChange{Kind: DocRemoved, Slug: slug, Title: doc.Attributes.Title, FromID: doc.ID})
+	}
+
+	result := CategoryDiff{Category: category}
+	for _, sub := range subcategories {
+		for _, changes := range [][]DocChange{sub.Added, sub.Removed, sub.Changed} {
+			sort.Slice(changes, func(i, j int) bool { return changes[i].Slug < changes[j].Slug })
+		}
+		result.Subcategories = append(result.Subcategories, *sub)
+	}
+	sort.Slice(result.Subcategories, func(i, j int) bool {
+		return result.Subcategories[i].Subcategory < result.Subcategories[j].Subcategory
+	})
+
+	return result
+}
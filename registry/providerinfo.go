@@ -0,0 +1,200 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ProviderInfo is a provider as a single view, whichever API version served it. The v1
+// API describes one provider version (GetVersion returns a Provider) while the v2 API
+// describes the provider itself (Get returns a ProviderData), so each conversion fills
+// only the fields its source carries; Merge combines the two.
+type ProviderInfo struct {
+	// ID is the v2 provider ID, such as "323"; empty when converted from v1
+	ID string `json:"id,omitempty"`
+
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Alias       string `json:"alias,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Tier        string `json:"tier,omitempty"`
+	LogoURL     string `json:"logo_url,omitempty"`
+
+	// Downloads is the total of all versions from v2 and of the version from v1
+	Downloads int64 `json:"downloads"`
+
+	// Featured, Unlisted and Warning are only served by v2
+	Featured bool   `json:"featured,omitempty"`
+	Unlisted bool   `json:"unlisted,omitempty"`
+	Warning  string `json:"warning,omitempty"`
+
+	// Version, Tag, PublishedAt and Versions are only served by v1
+	Version     string    `json:"version,omitempty"`
+	Tag         string    `json:"tag,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	Versions    []string  `json:"versions,omitempty"`
+}
+
+// Ref returns the provider's ref, pinned to Version when set
+func (i ProviderInfo) Ref() ProviderRef {
+	return ProviderRef{Namespace: i.Namespace, Name: i.Name, Version: i.Version}
+}
+
+// Info converts a v1 provider version to a ProviderInfo
+func (p Provider) Info() ProviderInfo {
+	return ProviderInfo{
+		Namespace:   p.Namespace,
+		Name:        p.Name,
+		Alias:       p.Alias,
+		Owner:       p.Owner,
+		Description: p.Description,
+		Source:      p.Source,
+		Tier:        p.Tier,
+		LogoURL:     p.LogoURL,
+		Downloads:   p.Downloads,
+		Version:     p.Version,
+		Tag:         p.Tag,
+		PublishedAt: p.PublishedAt,
+		Versions:    append([]string(nil), p.Versions...),
+	}
+}
+
+// Info converts a v2 provider to a ProviderInfo
+func (d ProviderData) Info() ProviderInfo {
+	return ProviderInfo{
+		ID:          d.ID,
+		Namespace:   d.Attributes.Namespace,
+		Name:        d.Attributes.Name,
+		Alias:       d.Attributes.Alias,
+		Owner:       d.Attributes.OwnerName,
+		Description: d.Attributes.Description,
+		Source:      d.Attributes.Source,
+		Tier:        d.Attributes.Tier,
+		LogoURL:     d.Attributes.LogoURL,
+		Downloads:   d.Attributes.Downloads,
+		Featured:    d.Attributes.Featured,
+		Unlisted:    d.Attributes.Unlisted,
+		Warning:     d.Attributes.Warning,
+	}
+}
+
+// Provider converts the info to a v1 provider version. The ID is the v1
+// namespace/name/version ID when Version is set.
+func (i ProviderInfo) Provider() Provider {
+	p := Provider{
+		Owner:       i.Owner,
+		Namespace:   i.Namespace,
+		Name:        i.Name,
+		Alias:       i.Alias,
+		Version:     i.Version,
+		Tag:         i.Tag,
+		Description: i.Description,
+		Source:      i.Source,
+		PublishedAt: i.PublishedAt,
+		Downloads:   i.Downloads,
+		Tier:        i.Tier,
+		LogoURL:     i.LogoURL,
+		Versions:    append([]string(nil), i.Versions...),
+	}
+	if i.Version != "" {
+		p.ID = fmt.Sprintf("%s/%s/%s", i.Namespace, i.Name, i.Version)
+	}
+	return p
+}
+
+// ProviderData converts the info to a v2 provider. The self link is set when ID is.
+func (i ProviderInfo) ProviderData() ProviderData {
+	d := ProviderData{
+		Type: "providers",
+		ID:   i.ID,
+		Attributes: ProviderAttributes{
+			Alias:       i.Alias,
+			Description: i.Description,
+			Downloads:   i.Downloads,
+			Featured:    i.Featured,
+			FullName:    fmt.Sprintf("%s/%s", i.Namespace, i.Name),
+			LogoURL:     i.LogoURL,
+			Name:        i.Name,
+			Namespace:   i.Namespace,
+			OwnerName:   i.Owner,
+			Source:      i.Source,
+			Tier:        i.Tier,
+			Unlisted:    i.Unlisted,
+			Warning:     i.Warning,
+		},
+	}
+	if i.ID != "" {
+		d.Links.Self = "/v2/providers/" + i.ID
+	}
+	return d
+}
+
+// Merge returns the info with its empty fields filled from other, for example to
+// complete a v2 provider with the version details of a v1 response
+func (i ProviderInfo) Merge(other ProviderInfo) ProviderInfo {
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&i.ID, other.ID)
+	fill(&i.Namespace, other.Namespace)
+	fill(&i.Name, other.Name)
+	fill(&i.Alias, other.Alias)
+	fill(&i.Owner, other.Owner)
+	fill(&i.Description, other.Description)
+	fill(&i.Source, other.Source)
+	fill(&i.Tier, other.Tier)
+	fill(&i.LogoURL, other.LogoURL)
+	fill(&i.Warning, other.Warning)
+	fill(&i.Version, other.Version)
+	fill(&i.Tag, other.Tag)
+
+	if i.Downloads == 0 {
+		i.Downloads = other.Downloads
+	}
+	i.Featured = i.Featured || other.Featured
+	i.Unlisted = i.Unlisted || other.Unlisted
+	if i.PublishedAt.IsZero() {
+		i.PublishedAt = other.PublishedAt
+	}
+	if len(i.Versions) == 0 {
+		i.Versions = append([]string(nil), other.Versions...)
+	}
+	return i
+}
+
+// GetInfo returns a provider and one of its versions as a ProviderInfo, combining the
+// v2 provider with the v1 version details. An empty or "latest" version uses the latest
+// version. Provider-level fields such as Downloads come from v2.
+func (s *ProvidersService) GetInfo(ctx context.Context, ref ProviderRef) (*ProviderInfo, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	provider, err := s.Get(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var version *Provider
+	if ref.Version == "" || ref.Version == "latest" {
+		path := fmt.Sprintf("providers/%s/%s", url.PathEscape(ref.Namespace), url.PathEscape(ref.Name))
+		version = &Provider{}
+		if err := s.client.get(ctx, path, "v1", version); err != nil {
+			return nil, fmt.Errorf("failed to get latest provider version: %w", err)
+		}
+	} else {
+		version, err = s.GetVersion(ctx, ref.Namespace, ref.Name, ref.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info := provider.Info().Merge(version.Info())
+	return &info, nil
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// ProviderDownloadSummary holds the download counts of a provider over the same windows
+// as a module's
+type ProviderDownloadSummary = ModuleDownloadSummary
+
+// ProviderVersionDownloads is the all-time download count of one provider version
+type ProviderVersionDownloads struct {
+	Version     string
+	Downloads   int64
+	PublishedAt time.Time
+}
+
+// ProviderDownloadMetrics is a sample of the download counts of a provider
+type ProviderDownloadMetrics struct {
+	// Provider identifies the provider
+	Provider ProviderRef
+
+	// Downloads holds the weekly, monthly, yearly and all-time downloads
+	Downloads ProviderDownloadSummary
+
+	// Versions holds the all-time downloads of each version, newest first; empty when
+	// the registry does not report them
+	Versions []ProviderVersionDownloads
+
+	// FetchedAt is when the counts were read
+	FetchedAt time.Time
+}
+
+// GetDownloadMetrics returns the windowed download counts of a provider from the v2
+// downloads summary, with the downloads of each version where the registry reports them
+func (s *ProvidersService) GetDownloadMetrics(ctx context.Context, namespace, name string) (*ProviderDownloadMetrics, error) {
+	if err := validateProviderParams(namespace, name); err != nil {
+		return nil, err
+	}
+
+	if s.client.isOpenTofu() {
+		return nil, s.client.unsupported("provider download metrics")
+	}
+
+	details, err := s.GetWithOptions(ctx, namespace, name, &ProviderGetOptions{Include: []string{ProviderIncludeVersions}})
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("providers/%s/downloads/summary", url.PathEscape(details.Provider.ID))
+
+	var result struct {
+		Data struct {
+			Attributes ProviderDownloadSummary `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := s.client.get(ctx, path, "v2", &result); err != nil {
+		return nil, fmt.Errorf("failed to get download summary for %s/%s: %w", namespace, name, err)
+	}
+
+	metrics := &ProviderDownloadMetrics{
+		Provider:  ProviderRef{Namespace: namespace, Name: name},
+		Downloads: result.Data.Attributes,
+		FetchedAt: time.Now(),
+	}
+
+	for _, version := range details.Versions {
+		if version.Attributes.Version == "" {
+			continue
+		}
+		metrics.Versions = append(metrics.Versions, ProviderVersionDownloads{
+			Version:     version.Attributes.Version,
+			Downloads:   int64(version.Attributes.Downloads),
+			PublishedAt: version.Attributes.PublishedAt,
+		})
+	}
+
+	slices.SortFunc(metrics.Versions, func(a, b ProviderVersionDownloads) int {
+		return CompareVersions(b.Version, a.Version)
+	})
+
+	return metrics, nil
+}
@@ -2,9 +2,20 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/docparse"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
 )
 
 // Common provider documentation subcategories
@@ -53,6 +64,21 @@ const (
 // methods of the Terraform Registry API.
 type ProvidersService struct {
 	client *Client
+
+	// versions indexes provider and version IDs seen in version listings
+	versions providerVersionIndex
+
+	// slugIndexes caches slug indexes by provider version ID
+	slugIndexes sync.Map
+
+	// similarity holds the terms of fetched docs for SimilarDocs
+	similarity similarityCorpus
+
+	// openTofuVersions caches OpenTofu doc listings by provider version ID
+	openTofuVersions sync.Map
+
+	// flights coalesces identical GetMany requests in flight
+	flights flightGroup
 }
 
 // ProviderListOptions specifies optional parameters to the List method
@@ -76,15 +102,15 @@ func (o *ProviderListOptions) Validate() error {
 		return nil
 	}
 
-	if o.Tier != "" && !isValidTier(o.Tier) {
+	if o.Tier != "" && !validate.Tier(o.Tier) {
 		return &ValidationError{
 			Field:   "Tier",
 			Value:   o.Tier,
-			Message: "tier must be one of: official, partner, community",
+			Message: "tier must be one of: " + strings.Join(validate.Tiers, ", "),
 		}
 	}
 
-	if o.Namespace != "" && !isValidNamespace(o.Namespace) {
+	if o.Namespace != "" && !validate.Namespace(o.Namespace) {
 		return &ValidationError{
 			Field:   "Namespace",
 			Value:   o.Namespace,
@@ -117,6 +143,10 @@ func (s *ProvidersService) List(ctx context.Context, opts *ProviderListOptions)
 		return nil, err
 	}
 
+	if s.client.isOpenTofu() {
+		return nil, s.client.unsupported("listing providers")
+	}
+
 	path := "providers"
 	if opts != nil {
 		values := url.Values{}
@@ -144,16 +174,280 @@ func (s *ProvidersService) List(ctx context.Context, opts *ProviderListOptions)
 		return nil, fmt.Errorf("failed to list providers: %w", err)
 	}
 
+	s.client.checkIntegrity(ctx, "providers", func(r *integrityReport) {
+		for i, provider := range result.Data {
+			r.checkID("provider", i, provider.ID)
+		}
+		page := 0
+		if opts != nil {
+			page = opts.Page
+		}
+		r.checkPagination(result.Meta.Pagination, page, len(result.Data))
+	})
+
 	return &result, nil
 }
 
+// ListAll returns an iterator over all providers matching opts, following the page
+// pagination from opts.Page. Pages hold opts.PageSize providers, or 100 when unset.
+func (s *ProvidersService) ListAll(ctx context.Context, opts *ProviderListOptions) *Iterator[ProviderData] {
+	if err := opts.Validate(); err != nil {
+		return failedIterator[ProviderData](err)
+	}
+
+	page := ProviderListOptions{}
+	if opts != nil {
+		page = *opts
+	}
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	if page.PageSize == 0 {
+		page.PageSize = 100
+	}
+
+	providerID := func(item ProviderData) string { return item.ID }
+	return newIterator(ctx, providerID, func(ctx context.Context) ([]ProviderData, bool, error) {
+		list, err := s.List(ctx, &page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		page.Page = nextPage(list.Meta.Pagination, page.Page, len(list.Data))
+		return list.Data, page.Page != 0, nil
+	})
+}
+
+// DefaultFeaturedMaxPages is the default number of provider list pages scanned by ListFeatured
+const DefaultFeaturedMaxPages = 100
+
+// FeaturedListOptions specifies optional filters for ListFeatured
+type FeaturedListOptions struct {
+	// Tier filters featured providers by tier (official, partner, community)
+	Tier string
+
+	// Namespace filters featured providers by namespace
+	Namespace string
+
+	// Query keeps providers whose name, full name or description contains it, case-insensitively
+	Query string
+
+	// MaxPages limits the number of provider list pages scanned; 0 means DefaultFeaturedMaxPages
+	MaxPages int
+}
+
+// Validate validates the featured list options
+func (o *FeaturedListOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if err := (&ProviderListOptions{Tier: o.Tier, Namespace: o.Namespace}).Validate(); err != nil {
+		return err
+	}
+
+	if o.MaxPages < 0 {
+		return &ValidationError{
+			Field:   "MaxPages",
+			Value:   o.MaxPages,
+			Message: "max pages cannot be negative",
+		}
+	}
+
+	return nil
+}
+
+// ListFeatured returns the providers the registry marks as featured, most downloaded first.
+// Unlisted providers are skipped. Tier and namespace filters are applied by the API; the
+// featured flag and query are applied client-side while paging through the provider list.
+func (s *ProvidersService) ListFeatured(ctx context.Context, opts *FeaturedListOptions) ([]ProviderData, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &FeaturedListOptions{}
+	}
+
+	maxPages := opts.MaxPages
+	if maxPages == 0 {
+		maxPages = DefaultFeaturedMaxPages
+	}
+	query := strings.ToLower(opts.Query)
+
+	var featured []ProviderData
+	page := 1
+
+	for pageCount := 0; pageCount < maxPages; pageCount++ {
+		list, err := s.List(ctx, &ProviderListOptions{
+			Tier:      opts.Tier,
+			Namespace: opts.Namespace,
+			Page:      page,
+			PageSize:  100,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list featured providers: %w", err)
+		}
+
+		for _, provider := range list.Data {
+			attrs := provider.Attributes
+			if !attrs.Featured || attrs.Unlisted {
+				continue
+			}
+			if query != "" &&
+				!strings.Contains(strings.ToLower(attrs.Name), query) &&
+				!strings.Contains(strings.ToLower(attrs.FullName), query) &&
+				!strings.Contains(strings.ToLower(attrs.Description), query) {
+				continue
+			}
+			featured = append(featured, provider)
+		}
+
+		if len(list.Data) == 0 || list.Meta.Pagination.NextPage == 0 {
+			break
+		}
+		page = list.Meta.Pagination.NextPage
+	}
+
+	sort.SliceStable(featured, func(i, j int) bool {
+		return featured[i].Attributes.Downloads > featured[j].Attributes.Downloads
+	})
+
+	return featured, nil
+}
+
+// ProviderIncludeVersions includes the provider's versions in a provider lookup
+const ProviderIncludeVersions = "provider-versions"
+
+// ProviderGetOptions specifies optional parameters to the GetWithOptions method
+type ProviderGetOptions struct {
+	// Include lists related resources to include in the response, such as ProviderIncludeVersions
+	Include []string
+
+	// IncludeLatest resolves the latest version in the same request
+	IncludeLatest bool
+
+	// FilterLookup finds the provider with the legacy filter-based search, which returns the
+	// first of possibly several matches, instead of the canonical namespace/name path
+	FilterLookup bool
+}
+
+// Validate validates the provider get options
+func (o *ProviderGetOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	for _, include := range o.Include {
+		if include == "" || strings.ContainsAny(include, ", ") {
+			return &ValidationError{
+				Field:   "Include",
+				Value:   include,
+				Message: "include must be a single non-empty resource name",
+			}
+		}
+	}
+
+	return nil
+}
+
+// includes returns the include parameter values, adding versions when the latest is requested
+func (o *ProviderGetOptions) includes() []string {
+	if o == nil {
+		return nil
+	}
+
+	includes := append([]string(nil), o.Include...)
+	if o.IncludeLatest && !slices.Contains(includes, ProviderIncludeVersions) {
+		includes = append(includes, ProviderIncludeVersions)
+	}
+	return includes
+}
+
+// ProviderDetails is a provider with the related data requested through ProviderGetOptions
+type ProviderDetails struct {
+	// Provider is the provider data
+	Provider ProviderData
+
+	// Versions holds the provider versions when ProviderIncludeVersions is included
+	Versions []VersionData
+
+	// LatestVersion is the latest version when IncludeLatest is set
+	LatestVersion string
+}
+
 // Get returns details about a specific provider using v2 API
 func (s *ProvidersService) Get(ctx context.Context, namespace, name string) (*ProviderData, error) {
+	details, err := s.GetWithOptions(ctx, namespace, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &details.Provider, nil
+}
+
+// GetWithOptions returns a provider looked up by its canonical v2 path, together with
+// the included resources and latest version requested in opts, in a single request
+func (s *ProvidersService) GetWithOptions(ctx context.Context, namespace, name string, opts *ProviderGetOptions) (*ProviderDetails, error) {
 	if err := validateProviderParams(namespace, name); err != nil {
 		return nil, err
 	}
 
-	// Use v2 API with proper endpoint structure
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if s.client.isOpenTofu() {
+		return s.getOpenTofuWithOptions(ctx, namespace, name, opts)
+	}
+
+	includes := opts.includes()
+	path := fmt.Sprintf("providers/%s/%s", url.PathEscape(namespace), url.PathEscape(name))
+
+	if opts != nil && opts.FilterLookup {
+		provider, err := s.getByFilter(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(includes) == 0 {
+			return &ProviderDetails{Provider: *provider}, nil
+		}
+		path = fmt.Sprintf("providers/%s", url.PathEscape(provider.ID))
+	}
+
+	if len(includes) > 0 {
+		path += "?include=" + url.QueryEscape(strings.Join(includes, ","))
+	}
+
+	var result struct {
+		Data     ProviderData  `json:"data"`
+		Included []VersionData `json:"included"`
+	}
+
+	if err := s.client.get(ctx, path, "v2", &result); err != nil {
+		return nil, fmt.Errorf("failed to get provider %s/%s: %w", namespace, name, err)
+	}
+
+	details := &ProviderDetails{Provider: result.Data}
+	for _, included := range result.Included {
+		if included.Type != "" && included.Type != ProviderIncludeVersions {
+			continue
+		}
+		details.Versions = append(details.Versions, included)
+	}
+
+	if opts != nil && opts.IncludeLatest {
+		for _, version := range details.Versions {
+			if details.LatestVersion == "" || CompareVersions(version.Attributes.Version, details.LatestVersion) > 0 {
+				details.LatestVersion = version.Attributes.Version
+			}
+		}
+	}
+
+	return details, nil
+}
+
+// getByFilter finds a provider with the v2 filter search, returning the first match
+func (s *ProvidersService) getByFilter(ctx context.Context, namespace, name string) (*ProviderData, error) {
 	path := fmt.Sprintf("providers?filter[namespace]=%s&filter[name]=%s",
 		url.QueryEscape(namespace), url.QueryEscape(name))
 
@@ -181,40 +475,72 @@ func (s *ProvidersService) GetLatest(ctx context.Context, namespace, name string
 		return nil, err
 	}
 
-	// First get the provider
-	provider, err := s.Get(ctx, namespace, name)
+	details, err := s.GetWithOptions(ctx, namespace, name, &ProviderGetOptions{IncludeLatest: true})
 	if err != nil {
 		return nil, err
 	}
 
-	// Get versions with included data
-	path := fmt.Sprintf("providers/%s?include=provider-versions", provider.ID)
+	if details.LatestVersion == "" {
+		return nil, fmt.Errorf("%w for provider %s/%s", ErrNoVersions, namespace, name)
+	}
 
-	var result struct {
-		Data     ProviderData  `json:"data"`
-		Included []VersionData `json:"included"`
+	return &ProviderLatestVersion{
+		Provider: details.Provider,
+		Version:  details.LatestVersion,
+	}, nil
+}
+
+// HasChanged reports whether the latest version of a provider differs from knownVersion.
+// It makes a single request for the provider's version list, without docs, so it is
+// suitable for polling many watched providers; repeated checks are conditional requests
+// answered with 304 Not Modified while nothing was published. An empty knownVersion
+// always reports a change without a request.
+func (s *ProvidersService) HasChanged(ctx context.Context, ref ProviderRef, knownVersion string) (bool, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return false, err
 	}
 
-	if err := s.client.get(ctx, path, "v2", &result); err != nil {
-		return nil, fmt.Errorf("failed to get provider versions: %w", err)
+	if knownVersion == "" {
+		return true, nil
 	}
 
-	// Find the latest version
-	var latestVersion string
-	for _, version := range result.Included {
-		if latestVersion == "" || CompareVersions(version.Attributes.Version, latestVersion) > 0 {
-			latestVersion = version.Attributes.Version
+	// OpenTofu serves the latest version with the provider's version list
+	if s.client.isOpenTofu() {
+		latest, err := s.GetLatest(ctx, ref.Namespace, ref.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to check provider %s: %w", ref, err)
 		}
+		return NormalizeVersion(latest.Version) != NormalizeVersion(knownVersion), nil
 	}
 
-	if latestVersion == "" {
-		return nil, fmt.Errorf("no versions found for provider %s/%s", namespace, name)
+	path := fmt.Sprintf("providers/%s/%s?include=%s",
+		url.PathEscape(ref.Namespace), url.PathEscape(ref.Name), ProviderIncludeVersions)
+	req, err := s.client.newRequest(ctx, http.MethodGet, path, "v2", nil)
+	if err != nil {
+		return false, err
 	}
 
-	return &ProviderLatestVersion{
-		Provider: result.Data,
-		Version:  latestVersion,
-	}, nil
+	latest, err := s.client.latestVersion(req, func(body []byte) (string, error) {
+		var result ProviderVersionList
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("error decoding response: %w", err)
+		}
+		latest := ""
+		for _, version := range result.Included {
+			if latest == "" || CompareVersions(version.Attributes.Version, latest) > 0 {
+				latest = version.Attributes.Version
+			}
+		}
+		if latest == "" {
+			return "", ErrNoVersions
+		}
+		return latest, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check provider %s: %w", ref, err)
+	}
+
+	return NormalizeVersion(latest) != NormalizeVersion(knownVersion), nil
 }
 
 // GetVersion returns details about a specific provider version
@@ -231,6 +557,14 @@ func (s *ProvidersService) GetVersion(ctx context.Context, namespace, name, vers
 		}
 	}
 
+	if s.client.isOpenTofu() {
+		provider, err := s.getOpenTofuProvider(ctx, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider version: %w", err)
+		}
+		return provider.version(namespace, name, version)
+	}
+
 	path := fmt.Sprintf("providers/%s/%s/%s", namespace, name, version)
 
 	var result Provider
@@ -241,36 +575,168 @@ func (s *ProvidersService) GetVersion(ctx context.Context, namespace, name, vers
 	return &result, nil
 }
 
-// ListVersions returns all versions of a provider
-func (s *ProvidersService) ListVersions(ctx context.Context, namespace, name string) (*ProviderVersionList, error) {
-	if err := validateProviderParams(namespace, name); err != nil {
+// ProviderPlatform is an operating system and architecture a provider version is built for
+type ProviderPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// ProviderVersionDetails describes the published artifacts of a provider version
+type ProviderVersionDetails struct {
+	// Provider is the provider with the resolved version
+	Provider ProviderRef
+
+	// VersionID is the v2 provider version ID used by documentation endpoints
+	VersionID string
+
+	// PublishedAt is when the version was published
+	PublishedAt time.Time
+
+	// Protocols lists the supported Terraform plugin protocol versions
+	Protocols []string
+
+	// Platforms lists the platforms the version is built for
+	Platforms []ProviderPlatform
+
+	// DocsCount is the number of HCL documentation pages across all categories
+	DocsCount int
+}
+
+// HasDocs reports whether documentation is published for the version
+func (d *ProviderVersionDetails) HasDocs() bool {
+	return d.DocsCount > 0
+}
+
+// GetVersionDetails returns the published date, supported protocols, platforms and
+// documentation availability of a provider version. An empty version uses ref.Version;
+// empty or "latest" resolves the latest version.
+func (s *ProvidersService) GetVersionDetails(ctx context.Context, ref ProviderRef, version string) (*ProviderVersionDetails, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
 		return nil, err
 	}
 
-	// First, get the provider to get its ID
-	provider, err := s.Get(ctx, namespace, name)
+	if version == "" {
+		version = ref.Version
+	}
+	isLatest := version == "" || version == "latest"
+	if !isLatest {
+		if err := ValidateProviderVersion(version); err != nil {
+			return nil, &ValidationError{
+				Field:   "version",
+				Value:   version,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	provider, err := s.GetWithOptions(ctx, ref.Namespace, ref.Name, &ProviderGetOptions{IncludeLatest: isLatest, Include: []string{ProviderIncludeVersions}})
 	if err != nil {
 		return nil, err
 	}
+	if isLatest {
+		version = provider.LatestVersion
+	}
 
-	path := fmt.Sprintf("providers/%s?include=provider-versions", provider.ID)
+	details := &ProviderVersionDetails{
+		Provider: ProviderRef{Namespace: ref.Namespace, Name: ref.Name},
+	}
+	for _, v := range provider.Versions {
+		if NormalizeVersion(v.Attributes.Version) == NormalizeVersion(version) {
+			details.Provider.Version = v.Attributes.Version
+			details.VersionID = v.ID
+			details.PublishedAt = v.Attributes.PublishedAt
+			break
+		}
+	}
+
+	if details.VersionID == "" {
+		return nil, &APIError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("provider version %s/%s %s not found", ref.Namespace, ref.Name, version),
+		}
+	}
+
+	// Protocols and platforms are only published by the v1 provider registry protocol
+	path := fmt.Sprintf("providers/%s/%s/versions", url.PathEscape(ref.Namespace), url.PathEscape(ref.Name))
+
+	var result struct {
+		Versions []struct {
+			Version   string             `json:"version"`
+			Protocols []string           `json:"protocols"`
+			Platforms []ProviderPlatform `json:"platforms"`
+		} `json:"versions"`
+	}
+
+	if err := s.client.get(ctx, path, "v1", &result); err != nil {
+		return nil, fmt.Errorf("failed to get provider version artifacts: %w", err)
+	}
+
+	for _, v := range result.Versions {
+		if NormalizeVersion(v.Version) == NormalizeVersion(details.Provider.Version) {
+			details.Protocols = v.Protocols
+			details.Platforms = v.Platforms
+			break
+		}
+	}
+
+	details.DocsCount, err = s.countDocs(ctx, details.VersionID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count docs: %w", err)
+	}
+
+	return details, nil
+}
+
+// ListVersions returns all versions of a provider
+func (s *ProvidersService) ListVersions(ctx context.Context, namespace, name string) (*ProviderVersionList, error) {
+	if err := validateProviderParams(namespace, name); err != nil {
+		return nil, err
+	}
 
 	var result ProviderVersionList
-	if err := s.client.get(ctx, path, "v2", &result); err != nil {
-		return nil, fmt.Errorf("failed to list provider versions: %w", err)
+	if s.client.isOpenTofu() {
+		provider, err := s.getOpenTofuProvider(ctx, namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list provider versions: %w", err)
+		}
+		result = provider.versionList(namespace, name)
+	} else {
+		path := fmt.Sprintf("providers/%s/%s?include=%s",
+			url.PathEscape(namespace), url.PathEscape(name), ProviderIncludeVersions)
+
+		if err := s.client.get(ctx, path, "v2", &result); err != nil {
+			return nil, fmt.Errorf("failed to list provider versions: %w", err)
+		}
 	}
 
+	s.client.checkIntegrity(ctx, namespace+"/"+name, func(r *integrityReport) {
+		r.checkID("provider", 0, result.Data.ID)
+		versions := make([]string, 0, len(result.Included))
+		for i, version := range result.Included {
+			r.checkID("provider version", i, version.ID)
+			versions = append(versions, version.Attributes.Version)
+		}
+		r.checkVersions(versions, false)
+	})
+
+	s.versions.record(namespace, name, &result)
+
 	return &result, nil
 }
 
-// GetVersionID returns the version ID for a specific provider version
+// GetVersionID returns the version ID for a specific provider version. An empty or
+// "latest" version resolves to the latest version, or to the version pinned with
+// WithDocPins.
 func (s *ProvidersService) GetVersionID(ctx context.Context, namespace, name, version string) (string, error) {
 	if err := validateProviderParams(namespace, name); err != nil {
 		return "", err
 	}
 
-	// Handle latest version
+	// Handle latest version, or the version pinned for docs
 	if version == "" || version == "latest" {
+		if pinned, ok := pinnedDocVersion(ctx, namespace, name); ok {
+			return s.GetVersionID(ctx, namespace, name, pinned)
+		}
 		latest, err := s.GetLatest(ctx, namespace, name)
 		if err != nil {
 			return "", err
@@ -284,6 +750,10 @@ func (s *ProvidersService) GetVersionID(ctx context.Context, namespace, name, ve
 		}
 	}
 
+	if id, ok := s.versions.versionID(namespace, name, version); ok {
+		return id, nil
+	}
+
 	// Get all versions to find the ID
 	versions, err := s.ListVersions(ctx, namespace, name)
 	if err != nil {
@@ -316,6 +786,10 @@ func (s *ProvidersService) ListDocs(ctx context.Context, namespace, name, versio
 		}
 	}
 
+	if s.client.isOpenTofu() {
+		return s.listOpenTofuProviderDocs(ctx, namespace, name, version)
+	}
+
 	path := fmt.Sprintf("providers/%s/%s/%s", namespace, name, version)
 
 	var result ProviderDocs
@@ -363,19 +837,19 @@ func (o *ProviderDocListOptions) Validate() error {
 		}
 	}
 
-	if o.Category != "" && !isValidDocCategory(o.Category) {
+	if o.Category != "" && !validate.DocCategory(o.Category) {
 		return &ValidationError{
 			Field:   "Category",
 			Value:   o.Category,
-			Message: "invalid category, must be one of: resources, data-sources, functions, guides, overview",
+			Message: "invalid category, must be one of: " + strings.Join(validate.DocCategories, ", "),
 		}
 	}
 
-	if o.Language != "" && !isValidLanguage(o.Language) {
+	if o.Language != "" && !validate.Language(o.Language) {
 		return &ValidationError{
 			Field:   "Language",
 			Value:   o.Language,
-			Message: "invalid language",
+			Message: "invalid language, must be one of: " + strings.Join(validate.Languages, ", "),
 		}
 	}
 
@@ -396,6 +870,10 @@ func (s *ProvidersService) ListDocsV2(ctx context.Context, opts *ProviderDocList
 		return nil, err
 	}
 
+	if s.client.isOpenTofu() {
+		return s.listOpenTofuDocsV2(ctx, opts)
+	}
+
 	var allDocs []ProviderData
 	page := 1
 	if opts.Page > 0 {
@@ -444,6 +922,9 @@ func (s *ProvidersService) ListDocsV2(ctx context.Context, opts *ProviderDocList
 		}
 
 		allDocs = append(allDocs, result.Data...)
+		for _, doc := range result.Data {
+			s.similarity.addVersion(doc.ID, opts.ProviderVersionID)
+		}
 
 		// If we're only getting a specific page, don't continue
 		if opts.Page > 0 {
@@ -461,6 +942,111 @@ func (s *ProvidersService) ListDocsV2(ctx context.Context, opts *ProviderDocList
 	return allDocs, nil
 }
 
+// SlugIndex maps doc category to slug to doc ID for a provider version
+type SlugIndex map[string]map[string]string
+
+// Lookup returns the doc ID for a slug within a category
+func (idx SlugIndex) Lookup(category, slug string) (string, bool) {
+	id, ok := idx[category][slug]
+	return id, ok
+}
+
+// clone returns a deep copy of the index
+func (idx SlugIndex) clone() SlugIndex {
+	c := make(SlugIndex, len(idx))
+	for category, slugs := range idx {
+		c[category] = maps.Clone(slugs)
+	}
+	return c
+}
+
+// GetSlugIndex returns the slug to doc ID mapping for every doc category of a provider
+// version. All categories are read in a single paginated listing, and the result is
+// cached because docs of a published version do not change; each call returns its own
+// copy, which callers may modify.
+func (s *ProvidersService) GetSlugIndex(ctx context.Context, providerVersionID string) (SlugIndex, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Value:   providerVersionID,
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	if cached, ok := s.slugIndexes.Load(providerVersionID); ok {
+		return cached.(SlugIndex).clone(), nil
+	}
+
+	docs, err := s.listDocData(ctx, providerVersionID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build slug index: %w", err)
+	}
+
+	index := make(SlugIndex)
+	for _, doc := range docs {
+		attrs := doc.Attributes
+		if attrs.Category == "" || attrs.Slug == "" {
+			continue
+		}
+		if index[attrs.Category] == nil {
+			index[attrs.Category] = make(map[string]string)
+		}
+		index[attrs.Category][attrs.Slug] = doc.ID
+	}
+
+	s.slugIndexes.Store(providerVersionID, index)
+	return index.clone(), nil
+}
+
+// listDocData lists the docs of a provider version with their attributes, optionally
+// restricted to one category, in as few requests as the page size allows
+func (s *ProvidersService) listDocData(ctx context.Context, providerVersionID, category string) ([]ProviderDocData, error) {
+	if s.client.isOpenTofu() {
+		return s.listOpenTofuDocs(ctx, providerVersionID, category)
+	}
+
+	var docs []ProviderDocData
+	page := 1
+	maxPages := 100 // Prevent infinite loops
+
+	for pageCount := 0; pageCount < maxPages; pageCount++ {
+		values := url.Values{}
+		values.Add("filter[provider-version]", providerVersionID)
+		if category != "" {
+			values.Add("filter[category]", category)
+		}
+		values.Add("filter[language]", "hcl")
+		values.Add("page[number]", fmt.Sprintf("%d", page))
+		values.Add("page[size]", "100")
+
+		path := fmt.Sprintf("provider-docs?%s", values.Encode())
+
+		var result struct {
+			Data []ProviderDocData `json:"data"`
+			Meta struct {
+				Pagination Pagination `json:"pagination"`
+			} `json:"meta"`
+		}
+
+		if err := s.client.get(ctx, path, "v2", &result); err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, result.Data...)
+		for _, doc := range result.Data {
+			s.similarity.addVersion(doc.ID, providerVersionID)
+		}
+
+		if len(result.Data) == 0 || result.Meta.Pagination.NextPage == 0 {
+			break
+		}
+
+		page = result.Meta.Pagination.NextPage
+	}
+
+	return docs, nil
+}
+
 // GetDoc returns detailed documentation for a specific provider doc
 func (s *ProvidersService) GetDoc(ctx context.Context, docID string) (*ProviderDocDetails, error) {
 	if docID == "" {
@@ -471,16 +1057,36 @@ func (s *ProvidersService) GetDoc(ctx context.Context, docID string) (*ProviderD
 		}
 	}
 
-	path := fmt.Sprintf("provider-docs/%s", docID)
-
 	var result ProviderDocDetails
-	if err := s.client.get(ctx, path, "v2", &result); err != nil {
-		return nil, fmt.Errorf("failed to get provider doc: %w", err)
+	if s.client.isOpenTofu() {
+		doc, err := s.getOpenTofuDoc(ctx, docID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider doc: %w", err)
+		}
+		result = *doc
+	} else {
+		path := fmt.Sprintf("provider-docs/%s", docID)
+
+		if err := s.client.get(ctx, path, "v2", &result); err != nil {
+			return nil, fmt.Errorf("failed to get provider doc: %w", err)
+		}
 	}
 
+	s.similarity.add(result.Data)
 	return &result, nil
 }
 
+// GetDocStructured returns a provider doc parsed into its argument and attribute
+// references, import instructions and examples with docparse.Parse
+func (s *ProvidersService) GetDocStructured(ctx context.Context, docID string) (*docparse.Doc, error) {
+	doc, err := s.GetDoc(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	return docparse.Parse(doc.Data.Attributes.Content), nil
+}
+
 // GetOverviewDocs returns the overview documentation for a provider version
 func (s *ProvidersService) GetOverviewDocs(ctx context.Context, providerVersionID string) (string, error) {
 	if providerVersionID == "" {
@@ -547,6 +1153,7 @@ func (s *ProvidersService) GetResourcesBySubcategory(ctx context.Context, provid
 			Message: "invalid subcategory",
 		}
 	}
+	warnUnknownSubcategory(ctx, subcategory)
 
 	opts := &ProviderDocListOptions{
 		ProviderVersionID: providerVersionID,
@@ -613,6 +1220,7 @@ func (s *ProvidersService) GetDataSourcesBySubcategory(ctx context.Context, prov
 			Message: "invalid subcategory",
 		}
 	}
+	warnUnknownSubcategory(ctx, subcategory)
 
 	opts := &ProviderDocListOptions{
 		ProviderVersionID: providerVersionID,
@@ -629,58 +1237,254 @@ func (s *ProvidersService) GetDataSourcesBySubcategory(ctx context.Context, prov
 	return docs, nil
 }
 
+// DefaultCheckpointInterval is the number of docs fetched between summary checkpoints
+const DefaultCheckpointInterval = 50
+
+// summaryCheckpointFormat is the version of the summary checkpoint format
+const summaryCheckpointFormat = 1
+
+// SummaryOptions specifies optional parameters for building a provider resource summary
+type SummaryOptions struct {
+	// Checkpoints stores progress so that an interrupted summary resumes where it stopped
+	Checkpoints CheckpointStore
+
+	// CheckpointInterval is the number of docs fetched between checkpoints; 0 means DefaultCheckpointInterval
+	CheckpointInterval int
+}
+
+// Validate validates the summary options
+func (o *SummaryOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if o.CheckpointInterval < 0 {
+		return &ValidationError{
+			Field:   "CheckpointInterval",
+			Value:   o.CheckpointInterval,
+			Message: "checkpoint interval cannot be negative",
+		}
+	}
+
+	return nil
+}
+
+// summaryProgress is the checkpointed state of a provider resource summary
+type summaryProgress struct {
+	FormatVersion     int      `json:"format_version"`
+	VersionID         string   `json:"version_id"`
+	ResourcesListed   bool     `json:"resources_listed"`
+	ResourceIDs       []string `json:"resource_ids"`
+	DataSourcesListed bool     `json:"data_sources_listed"`
+	DataSourceIDs     []string `json:"data_source_ids"`
+
+	// Docs holds the fetched doc info by ID; a nil entry marks a doc that could not be fetched
+	Docs map[string]*ResourceInfo `json:"docs"`
+}
+
+// SubcategoryCount is a documentation subcategory of a provider version with its doc counts
+type SubcategoryCount struct {
+	// Name is the subcategory name as published; empty for docs without a subcategory
+	Name string
+
+	// Resources is the number of resource docs in the subcategory
+	Resources int
+
+	// DataSources is the number of data source docs in the subcategory
+	DataSources int
+}
+
+// Total returns the number of resources and data sources in the subcategory
+func (c SubcategoryCount) Total() int {
+	return c.Resources + c.DataSources
+}
+
+// ListSubcategories returns the subcategories actually used by a provider version's resource
+// and data source docs, sorted by name, with an entry named "" for docs without one. The
+// doc listing is read in pages of 100 without fetching individual docs.
+func (s *ProvidersService) ListSubcategories(ctx context.Context, providerVersionID string) ([]SubcategoryCount, error) {
+	if providerVersionID == "" {
+		return nil, &ValidationError{
+			Field:   "providerVersionID",
+			Value:   providerVersionID,
+			Message: "provider version ID cannot be empty",
+		}
+	}
+
+	counts := make(map[string]*SubcategoryCount)
+	for _, category := range []string{"resources", "data-sources"} {
+		docs, err := s.listDocData(ctx, providerVersionID, category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", category, err)
+		}
+
+		for _, doc := range docs {
+			name := doc.Attributes.Subcategory
+			count, ok := counts[name]
+			if !ok {
+				count = &SubcategoryCount{Name: name}
+				counts[name] = count
+			}
+			if category == "resources" {
+				count.Resources++
+			} else {
+				count.DataSources++
+			}
+		}
+	}
+
+	subcategories := make([]SubcategoryCount, 0, len(counts))
+	for _, count := range counts {
+		subcategories = append(subcategories, *count)
+	}
+	sort.Slice(subcategories, func(i, j int) bool {
+		return subcategories[i].Name < subcategories[j].Name
+	})
+
+	return subcategories, nil
+}
+
 // GetProviderResourceSummary creates a structured summary of all provider resources and data sources
 // organized by subcategory, returning only key information for application use
 func (s *ProvidersService) GetProviderResourceSummary(ctx context.Context, namespace, name, version string) (*ProviderResourceSummary, error) {
+	return s.GetProviderResourceSummaryWithOptions(ctx, namespace, name, version, nil)
+}
+
+// GetProviderResourceSummaryWithOptions creates a provider resource summary. When a checkpoint
+// store is given, progress is saved periodically and when the context is cancelled, and a later
+// call for the same provider version continues from the last checkpoint instead of starting over.
+// The checkpoint is deleted once the summary completes.
+func (s *ProvidersService) GetProviderResourceSummaryWithOptions(ctx context.Context, namespace, name, version string, opts *SummaryOptions) (*ProviderResourceSummary, error) {
 	if err := validateProviderParams(namespace, name); err != nil {
 		return nil, err
 	}
 
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &SummaryOptions{}
+	}
+
+	interval := opts.CheckpointInterval
+	if interval == 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	ctx, cancel := s.client.bestEffortContext(ctx)
+	defer cancel()
+
 	// Get provider version ID
-	var versionID string
-	var actualVersion string
-	var err error
+	actualVersion, versionID, err := s.resolveVersion(ctx, namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
 
-	if version == "" || version == "latest" {
-		latest, err := s.GetLatest(ctx, namespace, name)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get latest version: %w", err)
+	checkpointKey := fmt.Sprintf("provider-summary/%s/%s/%s", namespace, name, actualVersion)
+	progress := s.loadSummaryProgress(ctx, opts.Checkpoints, checkpointKey, versionID)
+
+	save := func() error {
+		if opts.Checkpoints == nil {
+			return nil
 		}
-		actualVersion = latest.Version
-		versionID, err = s.GetVersionID(ctx, namespace, name, actualVersion)
+		data, err := json.Marshal(progress)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get version ID: %w", err)
+			return err
 		}
-	} else {
-		actualVersion = version
-		versionID, err = s.GetVersionID(ctx, namespace, name, version)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get version ID: %w", err)
+		// Save even when ctx is cancelled, so that interrupted progress is kept
+		if err := opts.Checkpoints.Save(context.WithoutCancel(ctx), checkpointKey, data); err != nil {
+			return fmt.Errorf("failed to save summary checkpoint: %w", err)
 		}
+		return nil
 	}
 
 	// Get all resources
-	resourceOpts := &ProviderDocListOptions{
-		ProviderVersionID: versionID,
-		Category:          "resources",
-		Language:          "hcl",
-	}
+	if !progress.ResourcesListed {
+		resources, err := s.ListDocsV2(ctx, &ProviderDocListOptions{
+			ProviderVersionID: versionID,
+			Category:          "resources",
+			Language:          "hcl",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resources: %w", err)
+		}
 
-	resources, err := s.ListDocsV2(ctx, resourceOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get resources: %w", err)
+		progress.ResourceIDs = docIDs(resources)
+		progress.ResourcesListed = true
+		if err := save(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Get all data sources
-	dataSourceOpts := &ProviderDocListOptions{
-		ProviderVersionID: versionID,
-		Category:          "data-sources",
-		Language:          "hcl",
+	if !progress.DataSourcesListed {
+		dataSources, err := s.ListDocsV2(ctx, &ProviderDocListOptions{
+			ProviderVersionID: versionID,
+			Category:          "data-sources",
+			Language:          "hcl",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get data sources: %w", err)
+		}
+
+		progress.DataSourceIDs = docIDs(dataSources)
+		progress.DataSourcesListed = true
+		if err := save(); err != nil {
+			return nil, err
+		}
 	}
 
-	dataSources, err := s.ListDocsV2(ctx, dataSourceOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get data sources: %w", err)
+	// Get detailed info for each doc to access its subcategory
+	fetched := 0
+	partial := false
+docs:
+	for _, ids := range [][]string{progress.ResourceIDs, progress.DataSourceIDs} {
+		for _, id := range ids {
+			if _, done := progress.Docs[id]; done {
+				continue
+			}
+
+			doc, err := s.GetDoc(ctx, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					if saveErr := save(); saveErr != nil {
+						return nil, saveErr
+					}
+					// When the best effort budget expires, summarize the docs fetched so far
+					if budgetExpired(ctx) {
+						partial = true
+						break docs
+					}
+					return nil, fmt.Errorf("summary interrupted: %w", ctx.Err())
+				}
+				// If we can't get details, skip this doc
+				progress.Docs[id] = nil
+				warn(ctx, Warning{
+					Code:     WarningSkippedDoc,
+					Resource: id,
+					Message:  fmt.Sprintf("doc %s could not be fetched and is left out of the summary", id),
+					Err:      err,
+				})
+			} else {
+				attrs := doc.Data.Attributes
+				progress.Docs[id] = &ResourceInfo{
+					ID:          id,
+					Name:        attrs.Slug,
+					Title:       attrs.Title,
+					Subcategory: attrs.Subcategory,
+					Category:    attrs.Category,
+					Slug:        attrs.Slug,
+					Path:        attrs.Path,
+				}
+			}
+
+			fetched++
+			if fetched%interval == 0 {
+				if err := save(); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
 
 	// Build the summary
@@ -688,8 +1492,8 @@ func (s *ProvidersService) GetProviderResourceSummary(ctx context.Context, names
 		ProviderNamespace:        namespace,
 		ProviderName:             name,
 		Version:                  actualVersion,
-		TotalResources:           len(resources),
-		TotalDataSources:         len(dataSources),
+		TotalResources:           len(progress.ResourceIDs),
+		TotalDataSources:         len(progress.DataSourceIDs),
 		ResourcesBySubcategory:   make(map[string][]ResourceInfo),
 		DataSourcesBySubcategory: make(map[string][]ResourceInfo),
 		AllSubcategories:         make([]string, 0),
@@ -698,79 +1502,196 @@ func (s *ProvidersService) GetProviderResourceSummary(ctx context.Context, names
 	// Track unique subcategories
 	subcategorySet := make(map[string]bool)
 
-	// Process resources
-	for _, resource := range resources {
-		// Get detailed info to access subcategory
-		doc, err := s.GetDoc(ctx, resource.ID)
-		if err != nil {
-			// If we can't get details, skip this resource
-			continue
+	groups := []struct {
+		ids           []string
+		bySubcategory map[string][]ResourceInfo
+	}{
+		{progress.ResourceIDs, summary.ResourcesBySubcategory},
+		{progress.DataSourceIDs, summary.DataSourcesBySubcategory},
+	}
+
+	for _, group := range groups {
+		for _, id := range group.ids {
+			info := progress.Docs[id]
+			if info == nil {
+				continue
+			}
+
+			resourceInfo := *info
+			if resourceInfo.Subcategory == "" {
+				resourceInfo.Subcategory = "Other"
+			}
+
+			group.bySubcategory[resourceInfo.Subcategory] = append(group.bySubcategory[resourceInfo.Subcategory], resourceInfo)
+			subcategorySet[resourceInfo.Subcategory] = true
 		}
+	}
 
-		attrs := doc.Data.Attributes
-		subcategory := attrs.Subcategory
-		if subcategory == "" {
-			subcategory = "Other"
+	// Create sorted list of subcategories
+	for subcategory := range subcategorySet {
+		summary.AllSubcategories = append(summary.AllSubcategories, subcategory)
+	}
+
+	// Sort subcategories alphabetically
+	sortSubcategories(summary.AllSubcategories)
+
+	// Keep the checkpoint of a partial summary so that the next call continues it
+	if partial {
+		return summary, s.client.partialResult("provider resource summary", len(progress.Docs),
+			len(progress.ResourceIDs)+len(progress.DataSourceIDs), nil)
+	}
+
+	if opts.Checkpoints != nil {
+		if err := opts.Checkpoints.Delete(ctx, checkpointKey); err != nil {
+			s.client.logger.Debugf("Failed to delete summary checkpoint %s: %v", checkpointKey, err)
 		}
+	}
 
-		resourceInfo := ResourceInfo{
-			ID:          resource.ID,
-			Name:        attrs.Slug,
-			Title:       attrs.Title,
-			Subcategory: subcategory,
-			Category:    attrs.Category,
-			Slug:        attrs.Slug,
-			Path:        attrs.Path,
+	return summary, nil
+}
+
+// loadSummaryProgress returns the checkpointed progress for a summary, or fresh progress
+// when there is no usable checkpoint
+func (s *ProvidersService) loadSummaryProgress(ctx context.Context, store CheckpointStore, key, versionID string) *summaryProgress {
+	fresh := &summaryProgress{
+		FormatVersion: summaryCheckpointFormat,
+		VersionID:     versionID,
+		Docs:          make(map[string]*ResourceInfo),
+	}
+
+	if store == nil {
+		return fresh
+	}
+
+	data, err := store.Load(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrCheckpointNotFound) {
+			s.client.logger.Debugf("Ignoring unreadable summary checkpoint %s: %v", key, err)
 		}
+		return fresh
+	}
+
+	var progress summaryProgress
+	if err := json.Unmarshal(data, &progress); err != nil ||
+		progress.FormatVersion != summaryCheckpointFormat || progress.VersionID != versionID {
+		s.client.logger.Debugf("Discarding stale summary checkpoint %s", key)
+		return fresh
+	}
+	if progress.Docs == nil {
+		progress.Docs = make(map[string]*ResourceInfo)
+	}
+
+	s.client.logger.Debugf("Resuming summary %s with %d docs done", key, len(progress.Docs))
+	return &progress
+}
+
+// docIDs returns the IDs of a doc listing
+func docIDs(docs []ProviderData) []string {
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+// GetResourceCounts returns the number of resources, data sources, functions and guides
+// for a provider version. Only the pagination metadata of each category listing is
+// read, so the cost is one small request per category regardless of provider size.
+func (s *ProvidersService) GetResourceCounts(ctx context.Context, ref ProviderRef) (*ProviderResourceCounts, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	actualVersion, versionID, err := s.resolveVersion(ctx, ref.Namespace, ref.Name, ref.Version)
+	if err != nil {
+		return nil, err
+	}
 
-		summary.ResourcesBySubcategory[subcategory] = append(
-			summary.ResourcesBySubcategory[subcategory],
-			resourceInfo,
-		)
+	counts := &ProviderResourceCounts{
+		ProviderNamespace: ref.Namespace,
+		ProviderName:      ref.Name,
+		Version:           actualVersion,
+		ProviderVersionID: versionID,
+	}
 
-		subcategorySet[subcategory] = true
+	categories := []struct {
+		category string
+		count    *int
+	}{
+		{"resources", &counts.Resources},
+		{"data-sources", &counts.DataSources},
+		{"functions", &counts.Functions},
+		{"guides", &counts.Guides},
 	}
 
-	// Process data sources
-	for _, dataSource := range dataSources {
-		doc, err := s.GetDoc(ctx, dataSource.ID)
+	for _, c := range categories {
+		n, err := s.countDocs(ctx, versionID, c.category)
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("failed to count %s: %w", c.category, err)
 		}
+		*c.count = n
+	}
 
-		attrs := doc.Data.Attributes
-		subcategory := attrs.Subcategory
-		if subcategory == "" {
-			subcategory = "Other"
-		}
+	return counts, nil
+}
 
-		resourceInfo := ResourceInfo{
-			ID:          dataSource.ID,
-			Name:        attrs.Slug,
-			Title:       attrs.Title,
-			Subcategory: subcategory,
-			Category:    attrs.Category,
-			Slug:        attrs.Slug,
-			Path:        attrs.Path,
-		}
+// countDocs returns the number of docs in a category, or in all categories when category
+// is empty, using a single-item page
+func (s *ProvidersService) countDocs(ctx context.Context, providerVersionID, category string) (int, error) {
+	values := url.Values{}
+	values.Add("filter[provider-version]", providerVersionID)
+	if category != "" {
+		values.Add("filter[category]", category)
+	}
+	values.Add("filter[language]", "hcl")
+	values.Add("page[number]", "1")
+	values.Add("page[size]", "1")
 
-		summary.DataSourcesBySubcategory[subcategory] = append(
-			summary.DataSourcesBySubcategory[subcategory],
-			resourceInfo,
-		)
+	path := fmt.Sprintf("provider-docs?%s", values.Encode())
 
-		subcategorySet[subcategory] = true
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		Meta Meta `json:"meta"`
 	}
 
-	// Create sorted list of subcategories
-	for subcategory := range subcategorySet {
-		summary.AllSubcategories = append(summary.AllSubcategories, subcategory)
+	if err := s.client.get(ctx, path, "v2", &result); err != nil {
+		return 0, err
 	}
 
-	// Sort subcategories alphabetically
-	sortSubcategories(summary.AllSubcategories)
+	// With a page size of one, total-pages equals the item count; use it when
+	// a registry implementation omits total-count.
+	pagination := result.Meta.Pagination
+	count := max(pagination.TotalCount, pagination.TotalPages)
+	if count == 0 && len(result.Data) > 0 {
+		count = len(result.Data)
+	}
 
-	return summary, nil
+	return count, nil
+}
+
+// resolveVersion resolves "latest" or an explicit version to the concrete version and
+// its ID. "latest" resolves to the version pinned for docs in ctx, if any.
+func (s *ProvidersService) resolveVersion(ctx context.Context, namespace, name, version string) (string, string, error) {
+	if version == "" || version == "latest" {
+		if pinned, ok := pinnedDocVersion(ctx, namespace, name); ok {
+			version = pinned
+		} else {
+			latest, err := s.GetLatest(ctx, namespace, name)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to get latest version: %w", err)
+			}
+			version = latest.Version
+		}
+	}
+
+	versionID, err := s.GetVersionID(ctx, namespace, name, version)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get version ID: %w", err)
+	}
+
+	return version, versionID, nil
 }
 
 // BuildResourceInfoFromDocs creates a simplified resource list from provider documentation
@@ -829,7 +1750,7 @@ func validateProviderParams(namespace, name string) error {
 			Value:   namespace,
 			Message: "namespace cannot be empty",
 		})
-	} else if !isValidNamespace(namespace) {
+	} else if !validate.Namespace(namespace) {
 		errs.Add(&ValidationError{
 			Field:   "namespace",
 			Value:   namespace,
@@ -843,7 +1764,7 @@ func validateProviderParams(namespace, name string) error {
 			Value:   name,
 			Message: "name cannot be empty",
 		})
-	} else if !isValidProviderName(name) {
+	} else if !validate.ProviderName(name) {
 		errs.Add(&ValidationError{
 			Field:   "name",
 			Value:   name,
@@ -854,31 +1775,16 @@ func validateProviderParams(namespace, name string) error {
 	return errs.ErrorOrNil()
 }
 
-func isValidTier(tier string) bool {
-	validTiers := []string{"official", "partner", "community"}
-	for _, valid := range validTiers {
-		if tier == valid {
-			return true
-		}
-	}
-	return false
-}
-
-func isValidDocCategory(category string) bool {
-	validCategories := []string{"resources", "data-sources", "functions", "guides", "overview"}
-	for _, valid := range validCategories {
-		if category == valid {
-			return true
-		}
-	}
-	return false
+func isValidSubcategory(subcategory string) bool {
+	// Note: This validation is lenient - providers may use custom subcategories.
+	// Allow any subcategory that's not empty; the constants are only helpful defaults.
+	return isKnownSubcategory(subcategory) || subcategory != ""
 }
 
-func isValidSubcategory(subcategory string) bool {
+// isKnownSubcategory reports whether subcategory is one of the Subcategory* constants
+func isKnownSubcategory(subcategory string) bool {
 	// Common subcategories across major cloud providers
-	// Note: This validation is lenient - providers may use custom subcategories
-	// We only validate against known common subcategories
-	validSubcategories := []string{
+	knownSubcategories := []string{
 		SubcategoryNetworking,
 		SubcategoryCompute,
 		SubcategoryStorage,
@@ -894,26 +1800,26 @@ func isValidSubcategory(subcategory string) bool {
 		SubcategoryManagement,
 	}
 
-	for _, valid := range validSubcategories {
-		if subcategory == valid {
+	for _, known := range knownSubcategories {
+		if subcategory == known {
 			return true
 		}
 	}
 
-	// Allow any subcategory that's not empty (providers may have custom ones)
-	// This makes the validation lenient but still provides helpful constants
-	return subcategory != ""
+	return false
 }
 
-func isValidLanguage(language string) bool {
-	// Add more languages as needed
-	validLanguages := []string{"hcl", "terraform", "json"}
-	for _, valid := range validLanguages {
-		if language == valid {
-			return true
-		}
+// warnUnknownSubcategory reports a warning when subcategory is matched without being a known constant
+func warnUnknownSubcategory(ctx context.Context, subcategory string) {
+	if isKnownSubcategory(subcategory) {
+		return
 	}
-	return false
+
+	warn(ctx, Warning{
+		Code:     WarningUnknownSubcategory,
+		Resource: subcategory,
+		Message:  fmt.Sprintf("subcategory %q is not a known subcategory and is matched as given", subcategory),
+	})
 }
 
 func sortSubcategories(subcategories []string) {
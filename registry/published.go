@@ -0,0 +1,60 @@
+package registry
+
+import "time"
+
+// publishedWithin reports whether published lies within [after, before). A zero bound is
+// open. A zero published time is unknown and only matches when both bounds are open.
+// Comparisons are made on instants, so times in different locations compare correctly.
+func publishedWithin(published, after, before time.Time) bool {
+	if after.IsZero() && before.IsZero() {
+		return true
+	}
+
+	if published.IsZero() {
+		return false
+	}
+
+	if !after.IsZero() && published.Before(after) {
+		return false
+	}
+
+	if !before.IsZero() && !published.Before(before) {
+		return false
+	}
+
+	return true
+}
+
+// validatePublishedRange checks that a non-zero after bound precedes a non-zero before bound
+func validatePublishedRange(after, before time.Time) error {
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return &ValidationError{
+			Field:   "PublishedBefore",
+			Value:   before.UTC().Format(time.RFC3339),
+			Message: "published before must be later than published after",
+		}
+	}
+	return nil
+}
+
+// filterPublished returns the modules published within [after, before)
+func filterPublished(modules []Module, after, before time.Time) []Module {
+	if after.IsZero() && before.IsZero() {
+		return modules
+	}
+
+	filtered := make([]Module, 0, len(modules))
+	for _, module := range modules {
+		if publishedWithin(module.PublishedAt, after, before) {
+			filtered = append(filtered, module)
+		}
+	}
+	return filtered
+}
+
+// MonthStart returns the first instant of the UTC calendar month containing t, for building
+// "new this month" filters independent of the local time zone
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,239 @@
+// Package quality provides documentation completeness checks for registry modules.
+package quality
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Severity indicates how serious a finding is
+type Severity int
+
+const (
+	// SeverityInfo marks a suggestion that does not block promotion
+	SeverityInfo Severity = iota
+
+	// SeverityWarning marks a gap that should be fixed
+	SeverityWarning
+
+	// SeverityError marks a gap that fails the documentation standard
+	SeverityError
+)
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// Rule identifiers reported in findings
+const (
+	RuleMissingDescription   = "module-description"
+	RuleMissingReadme        = "readme-missing"
+	RuleMissingReadmeSection = "readme-section"
+	RuleInputDescription     = "input-description"
+	RuleOutputDescription    = "output-description"
+	RuleMissingExamples      = "examples-missing"
+	RuleSubmoduleReadme      = "submodule-readme"
+)
+
+// Finding describes a single documentation gap
+type Finding struct {
+	// Rule is the identifier of the rule that produced the finding
+	Rule string
+
+	// Severity is the severity level of the finding
+	Severity Severity
+
+	// Path is the module part the finding applies to ("" for the root module)
+	Path string
+
+	// Subject is the input, output or section name, if any
+	Subject string
+
+	// Message is a human-readable description of the finding
+	Message string
+}
+
+// String formats the finding as a single line
+func (f Finding) String() string {
+	location := f.Path
+	if location == "" {
+		location = "root"
+	}
+	if f.Subject != "" {
+		location = fmt.Sprintf("%s:%s", location, f.Subject)
+	}
+	return fmt.Sprintf("[%s] %s (%s): %s", f.Severity, f.Rule, location, f.Message)
+}
+
+// Findings is a list of lint findings
+type Findings []Finding
+
+// AtLeast returns the findings whose severity is at least severity
+func (fs Findings) AtLeast(severity Severity) Findings {
+	var filtered Findings
+	for _, f := range fs {
+		if f.Severity >= severity {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// Max returns the highest severity among the findings, or SeverityInfo if there are none
+func (fs Findings) Max() Severity {
+	worst := SeverityInfo
+	for _, f := range fs {
+		if f.Severity > worst {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// RequiredReadmeSections lists the README headings every root module is expected to have
+var RequiredReadmeSections = []string{"Usage", "Inputs", "Outputs"}
+
+var headingRegex = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*\s*$`)
+
+// LintModule checks a module's documentation for completeness and returns the findings
+// sorted by severity (most severe first). Nil details fail with a ValidationError.
+func LintModule(details *registry.ModuleDetails) (Findings, error) {
+	if details == nil {
+		return nil, &registry.ValidationError{
+			Field:   "details",
+			Message: "module details cannot be nil",
+		}
+	}
+
+	var findings Findings
+
+	if strings.TrimSpace(details.Description) == "" {
+		findings = append(findings, Finding{
+			Rule:     RuleMissingDescription,
+			Severity: SeverityWarning,
+			Message:  "module has no description",
+		})
+	}
+
+	findings = append(findings, lintReadme(details.Root)...)
+	findings = append(findings, lintVariables(details.Root)...)
+
+	for _, sub := range details.Submodules {
+		if strings.TrimSpace(sub.Readme) == "" {
+			findings = append(findings, Finding{
+				Rule:     RuleSubmoduleReadme,
+				Severity: SeverityInfo,
+				Path:     sub.Path,
+				Message:  "submodule has no README",
+			})
+		}
+		findings = append(findings, lintVariables(sub)...)
+	}
+
+	if len(details.Examples) == 0 {
+		findings = append(findings, Finding{
+			Rule:     RuleMissingExamples,
+			Severity: SeverityWarning,
+			Message:  "module has no examples",
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	return findings, nil
+}
+
+// lintReadme checks that the root README exists and contains the required sections
+func lintReadme(root registry.ModulePart) Findings {
+	if strings.TrimSpace(root.Readme) == "" {
+		return Findings{{
+			Rule:     RuleMissingReadme,
+			Severity: SeverityError,
+			Path:     root.Path,
+			Message:  "module has no README",
+		}}
+	}
+
+	headings := make(map[string]bool)
+	for _, match := range headingRegex.FindAllStringSubmatch(root.Readme, -1) {
+		headings[strings.ToLower(match[1])] = true
+	}
+
+	var findings Findings
+	for _, section := range RequiredReadmeSections {
+		if !hasHeading(headings, section) {
+			findings = append(findings, Finding{
+				Rule:     RuleMissingReadmeSection,
+				Severity: SeverityWarning,
+				Path:     root.Path,
+				Subject:  section,
+				Message:  fmt.Sprintf("README has no %q section", section),
+			})
+		}
+	}
+
+	return findings
+}
+
+// hasHeading reports whether any heading contains the section name
+func hasHeading(headings map[string]bool, section string) bool {
+	section = strings.ToLower(section)
+	for heading := range headings {
+		if strings.Contains(heading, section) {
+			return true
+		}
+	}
+	return false
+}
+
+// lintVariables checks that inputs and outputs of a module part are described
+func lintVariables(part registry.ModulePart) Findings {
+	var findings Findings
+
+	for _, input := range part.Inputs {
+		if strings.TrimSpace(input.Description) != "" {
+			continue
+		}
+		severity := SeverityWarning
+		if input.Required {
+			severity = SeverityError
+		}
+		findings = append(findings, Finding{
+			Rule:     RuleInputDescription,
+			Severity: severity,
+			Path:     part.Path,
+			Subject:  input.Name,
+			Message:  "input has no description",
+		})
+	}
+
+	for _, output := range part.Outputs {
+		if strings.TrimSpace(output.Description) != "" {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:     RuleOutputDescription,
+			Severity: SeverityWarning,
+			Path:     part.Path,
+			Subject:  output.Name,
+			Message:  "output has no description",
+		})
+	}
+
+	return findings
+}
@@ -0,0 +1,116 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Kind is the type of a field
+type Kind int
+
+const (
+	// KindString is a string field
+	KindString Kind = iota
+
+	// KindNumber is a numeric field
+	KindNumber
+
+	// KindBool is a boolean field
+	KindBool
+)
+
+// String returns the kind name
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// Field is a typed accessor for a value of T
+type Field[T any] struct {
+	kind      Kind
+	getString func(T) string
+	getNumber func(T) float64
+	getBool   func(T) bool
+}
+
+// Kind returns the field type
+func (f Field[T]) Kind() Kind {
+	return f.kind
+}
+
+// String returns a string field
+func String[T any](get func(T) string) Field[T] {
+	return Field[T]{kind: KindString, getString: get}
+}
+
+// Number returns a numeric field
+func Number[T any](get func(T) float64) Field[T] {
+	return Field[T]{kind: KindNumber, getNumber: get}
+}
+
+// Bool returns a boolean field
+func Bool[T any](get func(T) bool) Field[T] {
+	return Field[T]{kind: KindBool, getBool: get}
+}
+
+// Fields maps field names to accessors. Names are matched case-insensitively.
+type Fields[T any] map[string]Field[T]
+
+// lookup returns the field with the given name
+func (fs Fields[T]) lookup(name string) (Field[T], bool) {
+	if field, ok := fs[name]; ok {
+		return field, true
+	}
+	for key, field := range fs {
+		if strings.EqualFold(key, name) {
+			return field, true
+		}
+	}
+	return Field[T]{}, false
+}
+
+// ModuleFields are the fields of modules in list and search results
+var ModuleFields = Fields[registry.Module]{
+	"id":          String(func(m registry.Module) string { return m.ID }),
+	"namespace":   String(func(m registry.Module) string { return m.Namespace }),
+	"name":        String(func(m registry.Module) string { return m.Name }),
+	"provider":    String(func(m registry.Module) string { return m.Provider }),
+	"version":     String(func(m registry.Module) string { return m.Version }),
+	"description": String(func(m registry.Module) string { return m.Description }),
+	"source":      String(func(m registry.Module) string { return m.Source }),
+	"downloads":   Number(func(m registry.Module) float64 { return float64(m.Downloads) }),
+	"verified":    Bool(func(m registry.Module) bool { return m.Verified }),
+}
+
+// ProviderFields are the fields of providers in list results
+var ProviderFields = Fields[registry.ProviderData]{
+	"id":          String(func(p registry.ProviderData) string { return p.ID }),
+	"namespace":   String(func(p registry.ProviderData) string { return p.Attributes.Namespace }),
+	"name":        String(func(p registry.ProviderData) string { return p.Attributes.Name }),
+	"description": String(func(p registry.ProviderData) string { return p.Attributes.Description }),
+	"source":      String(func(p registry.ProviderData) string { return p.Attributes.Source }),
+	"tier":        String(func(p registry.ProviderData) string { return p.Attributes.Tier }),
+	"downloads":   Number(func(p registry.ProviderData) float64 { return float64(p.Attributes.Downloads) }),
+	"featured":    Bool(func(p registry.ProviderData) bool { return p.Attributes.Featured }),
+	"unlisted":    Bool(func(p registry.ProviderData) bool { return p.Attributes.Unlisted }),
+}
+
+// PolicyFields are the fields of policy libraries in list results
+var PolicyFields = Fields[registry.Policy]{
+	"id":        String(func(p registry.Policy) string { return p.ID }),
+	"namespace": String(func(p registry.Policy) string { return p.Attributes.Namespace }),
+	"name":      String(func(p registry.Policy) string { return p.Attributes.Name }),
+	"title":     String(func(p registry.Policy) string { return p.Attributes.Title }),
+	"source":    String(func(p registry.Policy) string { return p.Attributes.Source }),
+	"downloads": Number(func(p registry.Policy) float64 { return float64(p.Attributes.Downloads) }),
+	"verified":  Bool(func(p registry.Policy) bool { return p.Attributes.Verified }),
+}
@@ -0,0 +1,176 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind classifies lexical tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical token of a filter expression
+type token struct {
+	kind   tokenKind
+	text   string
+	number float64
+	offset int
+}
+
+// isKeyword reports whether the token is the given keyword or its symbolic form
+func (t token) isKeyword(keyword string) bool {
+	switch keyword {
+	case "and":
+		if t.kind == tokenOp && t.text == "&&" {
+			return true
+		}
+	case "or":
+		if t.kind == tokenOp && t.text == "||" {
+			return true
+		}
+	case "not":
+		if t.kind == tokenOp && t.text == "!" {
+			return true
+		}
+	}
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+// isComparison reports whether the token is a comparison operator
+func (t token) isComparison() bool {
+	if t.kind != tokenOp {
+		return false
+	}
+	switch t.text {
+	case "==", "!=", ">", ">=", "<", "<=", "contains":
+		return true
+	}
+	return false
+}
+
+// String describes the token for error messages
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// lex splits an expression into tokens, ending with a tokenEOF
+func lex(expr string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", offset: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", offset: i})
+			i++
+
+		case c == '"':
+			value, end, err := lexString(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, offset: i})
+			i = end
+
+		case c == '-' || c == '.' || unicode.IsDigit(c):
+			end := i + 1
+			for end < len(expr) && (unicode.IsDigit(rune(expr[end])) || strings.ContainsRune("._eE+-", rune(expr[end]))) {
+				// Signs are only part of a number directly after an exponent
+				if (expr[end] == '+' || expr[end] == '-') && expr[end-1] != 'e' && expr[end-1] != 'E' {
+					break
+				}
+				end++
+			}
+			text := expr[i:end]
+			number, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if err != nil {
+				return nil, &SyntaxError{Offset: i, Message: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, number: number, offset: i})
+			i = end
+
+		case unicode.IsLetter(c) || c == '_':
+			end := i + 1
+			for end < len(expr) && (unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end])) || expr[end] == '_' || expr[end] == '.') {
+				end++
+			}
+			text := expr[i:end]
+			kind := tokenIdent
+			if strings.EqualFold(text, "contains") {
+				kind = tokenOp
+				text = "contains"
+			}
+			tokens = append(tokens, token{kind: kind, text: text, offset: i})
+			i = end
+
+		default:
+			op := lexOperator(expr[i:])
+			if op == "" {
+				return nil, &SyntaxError{Offset: i, Message: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, offset: i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, offset: len(expr)}), nil
+}
+
+// operators lists symbolic operators, longest first
+var operators = []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!"}
+
+// lexOperator returns the operator at the start of s, or ""
+func lexOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// lexString reads a double-quoted string starting at start, returning its value and end offset
+func lexString(expr string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 >= len(expr) {
+				return "", 0, &SyntaxError{Offset: start, Message: "unterminated string"}
+			}
+			i++
+			b.WriteByte(expr[i])
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(expr[i])
+		}
+	}
+	return "", 0, &SyntaxError{Offset: start, Message: "unterminated string"}
+}
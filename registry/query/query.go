@@ -0,0 +1,260 @@
+// Package query implements a small filter expression language for registry list and
+// search results, evaluated client-side. An expression such as
+//
+//	downloads > 100000 AND verified AND provider == "aws"
+//
+// is compiled against a set of typed fields, so that type errors and unknown fields are
+// reported when the filter is compiled rather than when it is applied.
+//
+// Grammar:
+//
+//	expr       = and { ("OR" | "||") and }
+//	and        = unary { ("AND" | "&&") unary }
+//	unary      = ("NOT" | "!") unary | primary
+//	primary    = "(" expr ")" | comparison | field
+//	comparison = field op literal
+//	op         = "==" | "!=" | ">" | ">=" | "<" | "<=" | "contains"
+//	literal    = string | number | "true" | "false"
+//
+// Keywords and field names are case-insensitive. A bare field must be a bool field.
+// String equality is exact; "contains" is case-insensitive.
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filter is a compiled filter expression for items of type T
+type Filter[T any] struct {
+	expr string
+	eval func(T) bool
+}
+
+// Compile parses expr and type-checks it against fields
+func Compile[T any](expr string, fields Fields[T]) (*Filter[T], error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser[T]{tokens: tokens, fields: fields}
+	eval, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok)
+	}
+
+	return &Filter[T]{expr: expr, eval: eval}, nil
+}
+
+// MustCompile is like Compile but panics on error; it is intended for constant expressions
+func MustCompile[T any](expr string, fields Fields[T]) *Filter[T] {
+	f, err := Compile(expr, fields)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Match reports whether item satisfies the filter
+func (f *Filter[T]) Match(item T) bool {
+	return f.eval(item)
+}
+
+// Apply returns the items satisfying the filter, in order
+func (f *Filter[T]) Apply(items []T) []T {
+	var matched []T
+	for _, item := range items {
+		if f.eval(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// String returns the source expression
+func (f *Filter[T]) String() string {
+	return f.expr
+}
+
+// SyntaxError describes an invalid filter expression
+type SyntaxError struct {
+	// Offset is the byte offset of the error in the expression
+	Offset  int
+	Message string
+}
+
+// Error implements error
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: %s at offset %d", e.Message, e.Offset)
+}
+
+// parser is a recursive descent parser producing evaluation closures
+type parser[T any] struct {
+	tokens []token
+	pos    int
+	fields Fields[T]
+}
+
+func (p *parser[T]) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser[T]) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser[T]) errorf(tok token, format string, args ...interface{}) error {
+	return &SyntaxError{Offset: tok.offset, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser[T]) parseOr() (func(T) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(item T) bool { return l(item) || r(item) }
+	}
+
+	return left, nil
+}
+
+func (p *parser[T]) parseAnd() (func(T) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(item T) bool { return l(item) && r(item) }
+	}
+
+	return left, nil
+}
+
+func (p *parser[T]) parseUnary() (func(T) bool, error) {
+	if p.peek().isKeyword("not") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(item T) bool { return !operand(item) }, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser[T]) parsePrimary() (func(T) bool, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, "expected ) but found %s", closing)
+		}
+		return inner, nil
+
+	case tokenIdent:
+		field, ok := p.fields.lookup(tok.text)
+		if !ok {
+			return nil, p.errorf(tok, "unknown field %q", tok.text)
+		}
+
+		if !p.peek().isComparison() {
+			if field.kind != KindBool {
+				return nil, p.errorf(tok, "field %q is a %s and needs a comparison", tok.text, field.kind)
+			}
+			return field.getBool, nil
+		}
+
+		op := p.next()
+		value := p.next()
+		return p.compare(tok, field, op, value)
+
+	default:
+		return nil, p.errorf(tok, "expected a field or ( but found %s", tok)
+	}
+}
+
+// compare builds a comparison of a field against a literal
+func (p *parser[T]) compare(fieldTok token, field Field[T], op, value token) (func(T) bool, error) {
+	switch field.kind {
+	case KindString:
+		if value.kind != tokenString {
+			return nil, p.errorf(value, "field %q is a string and cannot be compared with %s", fieldTok.text, value)
+		}
+		want := value.text
+		lowerWant := strings.ToLower(want)
+		get := field.getString
+		switch op.text {
+		case "==":
+			return func(item T) bool { return get(item) == want }, nil
+		case "!=":
+			return func(item T) bool { return get(item) != want }, nil
+		case "contains":
+			return func(item T) bool { return strings.Contains(strings.ToLower(get(item)), lowerWant) }, nil
+		}
+
+	case KindNumber:
+		if value.kind != tokenNumber {
+			return nil, p.errorf(value, "field %q is a number and cannot be compared with %s", fieldTok.text, value)
+		}
+		want := value.number
+		get := field.getNumber
+		switch op.text {
+		case "==":
+			return func(item T) bool { return get(item) == want }, nil
+		case "!=":
+			return func(item T) bool { return get(item) != want }, nil
+		case ">":
+			return func(item T) bool { return get(item) > want }, nil
+		case ">=":
+			return func(item T) bool { return get(item) >= want }, nil
+		case "<":
+			return func(item T) bool { return get(item) < want }, nil
+		case "<=":
+			return func(item T) bool { return get(item) <= want }, nil
+		}
+
+	case KindBool:
+		if !value.isKeyword("true") && !value.isKeyword("false") {
+			return nil, p.errorf(value, "field %q is a bool and cannot be compared with %s", fieldTok.text, value)
+		}
+		want := value.isKeyword("true")
+		get := field.getBool
+		switch op.text {
+		case "==":
+			return func(item T) bool { return get(item) == want }, nil
+		case "!=":
+			return func(item T) bool { return get(item) != want }, nil
+		}
+	}
+
+	return nil, p.errorf(op, "operator %s is not supported for %s field %q", op.text, field.kind, fieldTok.text)
+}
@@ -2,23 +2,73 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"sync"
 	"time"
 )
 
-// RateLimiter implements a token bucket rate limiter
-type RateLimiter struct {
+// RateLimiter budgets the requests sent by the client. The client waits for a token
+// before each API request and passes every response's headers to Observe, so that
+// implementations can follow the limits announced by the registry.
+type RateLimiter interface {
+	// Wait blocks until a token is available or the context is cancelled
+	Wait(ctx context.Context) error
+
+	// TryAcquire attempts to acquire a token without blocking
+	TryAcquire() bool
+
+	// WaitForTokens blocks until at least n tokens are available without consuming them
+	WaitForTokens(ctx context.Context, n int) error
+
+	// TimeUntilNextToken returns how long until a token is available, or zero if one is
+	TimeUntilNextToken() time.Duration
+
+	// TokensRemaining returns the number of tokens currently available
+	TokensRemaining() int
+
+	// Capacity returns the maximum number of tokens the limiter holds
+	Capacity() int
+
+	// Acquired returns the total number of tokens handed out
+	Acquired() uint64
+
+	// Observe updates the limiter from the headers of a response
+	Observe(header http.Header)
+}
+
+// WithRateLimiter sets the rate limiter of the client, replacing the token bucket
+// configured with WithRateLimit
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *ClientConfig) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithPerHostRateLimiters gives every host other than the one of the initial base URL
+// its own rate limiter, created by newLimiter on the first request to the host, instead
+// of sharing one budget when SetBaseURL switches between registries. The initial host
+// keeps the limiter set by WithRateLimiter or WithRateLimit.
+func WithPerHostRateLimiters(newLimiter func(host string) RateLimiter) ClientOption {
+	return func(c *ClientConfig) {
+		c.NewHostRateLimiter = newLimiter
+	}
+}
+
+// TokenBucket is a RateLimiter with a fixed budget of requests per period
+type TokenBucket struct {
 	mu           sync.Mutex
 	tokens       int
 	maxTokens    int
 	refillRate   int
 	refillPeriod time.Duration
 	lastRefill   time.Time
+	acquired     uint64
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(maxRequests int, period time.Duration) *RateLimiter {
-	return &RateLimiter{
+// NewTokenBucket creates a token bucket holding maxRequests tokens, refilled over period
+func NewTokenBucket(maxRequests int, period time.Duration) *TokenBucket {
+	return &TokenBucket{
 		tokens:       maxRequests,
 		maxTokens:    maxRequests,
 		refillRate:   maxRequests,
@@ -27,15 +77,22 @@ func NewRateLimiter(maxRequests int, period time.Duration) *RateLimiter {
 	}
 }
 
+// NewRateLimiter creates a token bucket rate limiter.
+//
+// Deprecated: use NewTokenBucket.
+func NewRateLimiter(maxRequests int, period time.Duration) *TokenBucket {
+	return NewTokenBucket(maxRequests, period)
+}
+
 // Wait blocks until a token is available or the context is cancelled
-func (r *RateLimiter) Wait(ctx context.Context) error {
+func (r *TokenBucket) Wait(ctx context.Context) error {
 	for {
 		if r.TryAcquire() {
 			return nil
 		}
 
 		// Calculate wait time until next token
-		waitTime := r.timeUntilNextToken()
+		waitTime := r.TimeUntilNextToken()
 
 		select {
 		case <-ctx.Done():
@@ -47,7 +104,7 @@ func (r *RateLimiter) Wait(ctx context.Context) error {
 }
 
 // TryAcquire attempts to acquire a token without blocking
-func (r *RateLimiter) TryAcquire() bool {
+func (r *TokenBucket) TryAcquire() bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -55,14 +112,39 @@ func (r *RateLimiter) TryAcquire() bool {
 
 	if r.tokens > 0 {
 		r.tokens--
+		r.acquired++
 		return true
 	}
 
 	return false
 }
 
+// WaitForTokens blocks until at least n tokens are available without consuming them,
+// or the context is cancelled. n is capped at the limiter capacity.
+func (r *TokenBucket) WaitForTokens(ctx context.Context, n int) error {
+	n = min(n, r.Capacity())
+
+	for {
+		remaining := r.TokensRemaining()
+		if remaining >= n {
+			return nil
+		}
+
+		// Wait roughly as long as the missing tokens take to refill
+		r.mu.Lock()
+		waitTime := r.refillPeriod / time.Duration(r.refillRate) * time.Duration(n-remaining)
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitTime):
+		}
+	}
+}
+
 // refill adds tokens based on elapsed time
-func (r *RateLimiter) refill() {
+func (r *TokenBucket) refill() {
 	now := time.Now()
 	elapsed := now.Sub(r.lastRefill)
 
@@ -80,8 +162,8 @@ func (r *RateLimiter) refill() {
 	}
 }
 
-// timeUntilNextToken calculates the time until the next token is available
-func (r *RateLimiter) timeUntilNextToken() time.Duration {
+// TimeUntilNextToken calculates the time until the next token is available
+func (r *TokenBucket) TimeUntilNextToken() time.Duration {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -100,7 +182,7 @@ func (r *RateLimiter) timeUntilNextToken() time.Duration {
 }
 
 // Reset resets the rate limiter to full capacity
-func (r *RateLimiter) Reset() {
+func (r *TokenBucket) Reset() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -109,7 +191,7 @@ func (r *RateLimiter) Reset() {
 }
 
 // TokensRemaining returns the number of tokens currently available
-func (r *RateLimiter) TokensRemaining() int {
+func (r *TokenBucket) TokensRemaining() int {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -117,6 +199,89 @@ func (r *RateLimiter) TokensRemaining() int {
 	return r.tokens
 }
 
+// Capacity returns the maximum number of tokens the limiter holds
+func (r *TokenBucket) Capacity() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.maxTokens
+}
+
+// Acquired returns the total number of tokens handed out since the limiter was created,
+// which is the number of requests made through it
+func (r *TokenBucket) Acquired() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.acquired
+}
+
+// Observe implements RateLimiter; the budget of a token bucket is fixed
+func (r *TokenBucket) Observe(header http.Header) {}
+
+// rateLimiterState is the serialized form of the rate limiter
+type rateLimiterState struct {
+	Tokens     int       `json:"tokens"`
+	MaxTokens  int       `json:"max_tokens"`
+	LastRefill time.Time `json:"last_refill"`
+}
+
+// exportState implements stateSection
+func (r *TokenBucket) exportState() (json.RawMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return json.Marshal(rateLimiterState{
+		Tokens:     r.tokens,
+		MaxTokens:  r.maxTokens,
+		LastRefill: r.lastRefill,
+	})
+}
+
+// importState implements stateSection. Tokens are clamped to the current capacity and
+// refilled for the time elapsed since the export.
+func (r *TokenBucket) importState(data json.RawMessage) error {
+	var state rateLimiterState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state.LastRefill.IsZero() || state.LastRefill.After(time.Now()) {
+		return nil
+	}
+
+	r.tokens = max(0, min(state.Tokens, r.maxTokens))
+	r.lastRefill = state.LastRefill
+	r.refill()
+
+	return nil
+}
+
+// hostRateLimiters holds the rate limiters of hosts other than the initial base URL host
+type hostRateLimiters struct {
+	mu       sync.Mutex
+	limiters map[string]RateLimiter
+}
+
+// get returns the limiter of a host, creating it with newLimiter on first use
+func (h *hostRateLimiters) get(host string, newLimiter func(host string) RateLimiter) RateLimiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if limiter, ok := h.limiters[host]; ok {
+		return limiter
+	}
+	if h.limiters == nil {
+		h.limiters = make(map[string]RateLimiter)
+	}
+	limiter := newLimiter(host)
+	h.limiters[host] = limiter
+	return limiter
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
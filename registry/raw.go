@@ -0,0 +1,68 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+// RawResponse is a raw JSON payload captured from a successful API response
+type RawResponse struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       json.RawMessage
+}
+
+// rawCapture collects raw responses for a context
+type rawCapture struct {
+	mu        sync.Mutex
+	responses []RawResponse
+}
+
+var rawCaptureKey = registryctx.NewKey[*rawCapture]("raw-capture")
+
+// WithRawCapture returns a context that records the raw JSON payload of every successful
+// response made with it, so fields not yet modeled by the typed results remain accessible
+func WithRawCapture(ctx context.Context) context.Context {
+	return rawCaptureKey.With(ctx, &rawCapture{})
+}
+
+// RawFromContext returns the raw payload of the last response captured with the context,
+// or nil if capture is not enabled or nothing was captured. For typed calls that make
+// several requests this is the response the result was decoded from last.
+func RawFromContext(ctx context.Context) json.RawMessage {
+	responses := RawResponsesFromContext(ctx)
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses[len(responses)-1].Body
+}
+
+// RawResponsesFromContext returns all responses captured with the context, in order
+func RawResponsesFromContext(ctx context.Context) []RawResponse {
+	capture, ok := rawCaptureKey.Get(ctx)
+	if !ok {
+		return nil
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+
+	return append([]RawResponse(nil), capture.responses...)
+}
+
+// captureRaw records a response body when raw capture is enabled on the context
+func captureRaw(ctx context.Context, response RawResponse) {
+	capture, ok := rawCaptureKey.Get(ctx)
+	if !ok {
+		return
+	}
+
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+
+	capture.responses = append(capture.responses, response)
+}
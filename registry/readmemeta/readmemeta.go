@@ -0,0 +1,480 @@
+// Package readmemeta extracts structured metadata from module READMEs: status badges,
+// the required Terraform version and maintainer information. It recognizes the common
+// conventions of registry modules, such as shields.io badges, terraform-docs
+// Requirements tables and "Maintained by" statements; READMEs that follow none of them
+// produce empty metadata.
+package readmemeta
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// BadgeKind classifies what a badge reports
+type BadgeKind string
+
+const (
+	// BadgeBuild reports CI or test status
+	BadgeBuild BadgeKind = "build"
+
+	// BadgeCoverage reports test coverage
+	BadgeCoverage BadgeKind = "coverage"
+
+	// BadgeLicense names the license
+	BadgeLicense BadgeKind = "license"
+
+	// BadgeRegistry links the module's registry page
+	BadgeRegistry BadgeKind = "registry"
+
+	// BadgeSecurity reports a security scan such as tfsec or Checkov
+	BadgeSecurity BadgeKind = "security"
+
+	// BadgeVersion shows the latest release or tag
+	BadgeVersion BadgeKind = "version"
+
+	// BadgeOther is any other badge
+	BadgeOther BadgeKind = "other"
+)
+
+// Badge is a status badge image, optionally linked
+type Badge struct {
+	Kind BadgeKind `json:"kind"`
+
+	// Alt is the image's alternative text, such as "License"
+	Alt string `json:"alt,omitempty"`
+
+	ImageURL string `json:"image_url"`
+	LinkURL  string `json:"link_url,omitempty"`
+}
+
+// Maintainer is a person or team a README names as maintaining the module. At least
+// one of the fields is set.
+type Maintainer struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	URL   string `json:"url,omitempty"`
+
+	// Handle is a GitHub-style handle without the "@"
+	Handle string `json:"handle,omitempty"`
+}
+
+// Metadata is the metadata extracted from a README
+type Metadata struct {
+	// Badges are in README order
+	Badges []Badge `json:"badges,omitempty"`
+
+	// TerraformVersion is the required Terraform version constraint, such as ">= 1.3",
+	// taken from the Requirements table, a required_version setting or a statement such
+	// as "Terraform 0.13+"; empty when the README states none
+	TerraformVersion string `json:"terraform_version,omitempty"`
+
+	// Maintainers are in README order, without duplicates
+	Maintainers []Maintainer `json:"maintainers,omitempty"`
+
+	// Owners are the code owners of the module's source repository, added with
+	// AddOwnership
+	Owners []Maintainer `json:"owners,omitempty"`
+}
+
+// Empty reports whether no metadata was found
+func (m *Metadata) Empty() bool {
+	return len(m.Badges) == 0 && m.TerraformVersion == "" && len(m.Maintainers) == 0 && len(m.Owners) == 0
+}
+
+// AddOwnership adds the owners of the repository root from CODEOWNERS, as returned by
+// Modules.GetOwnership. Users and teams become handles, such as "org/team"; other
+// owners are email addresses.
+func (m *Metadata) AddOwnership(ownership *registry.ModuleOwnership) {
+	if ownership == nil {
+		return
+	}
+
+	for _, owner := range ownership.Owners() {
+		maintainer := Maintainer{Email: owner}
+		if handle, ok := strings.CutPrefix(owner, "@"); ok {
+			maintainer = Maintainer{Handle: handle, URL: "https://github.com/" + handle}
+			if org, team, ok := strings.Cut(handle, "/"); ok {
+				maintainer.URL = "https://github.com/orgs/" + org + "/teams/" + team
+			}
+		}
+		if !slices.Contains(m.Owners, maintainer) {
+			m.Owners = append(m.Owners, maintainer)
+		}
+	}
+}
+
+// BadgesOf returns the badges of a kind
+func (m *Metadata) BadgesOf(kind BadgeKind) []Badge {
+	var badges []Badge
+	for _, badge := range m.Badges {
+		if badge.Kind == kind {
+			badges = append(badges, badge)
+		}
+	}
+	return badges
+}
+
+var (
+	linkedImageRegex = regexp.MustCompile(`\[!\[([^\]]*)\]\(([^)\s]+)[^)]*\)\]\(([^)\s]+)[^)]*\)`)
+	imageRegex       = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	htmlLinkRegex    = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]+)"[^>]*>\s*(<img\s[^>]*>)\s*</a>`)
+	htmlImageRegex   = regexp.MustCompile(`(?is)<img\s[^>]*>`)
+	htmlSrcRegex     = regexp.MustCompile(`(?i)\ssrc="([^"]+)"`)
+	htmlAltRegex     = regexp.MustCompile(`(?i)\salt="([^"]*)"`)
+
+	headingRegex         = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+	requiredVersionRegex = regexp.MustCompile(`required_version\s*=\s*"([^"]+)"`)
+	constraintRegex      = regexp.MustCompile(`(?i)\bterraform(?:\s+(?:version|cli|core))?\s*(?:is\s+|of\s+)?(>=|~>|>|=|<=|<)\s*v?(\d+\.\d+(?:\.\d+)?)`)
+	minimumRegex         = regexp.MustCompile(`(?i)\bterraform(?:\s+(?:version|cli|core))?\s+v?(\d+\.\d+(?:\.\d+)?)(?:\s*\+|\s+or\s+(?:later|newer|higher|above|greater))`)
+
+	maintainedByRegex = regexp.MustCompile(`(?i)\b(?:maintained|managed|created|written|authored)\s+by\s+(.+)`)
+	helpRegex         = regexp.MustCompile(`(?i)\s+(?:with\s+(?:the\s+)?help\s+(?:from|of)|and\s+(?:other|these|many)\s+).*$`)
+	linkRegex         = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	emailRegex        = regexp.MustCompile(`<?([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})>?`)
+	handleRegex       = regexp.MustCompile(`(?:^|[\s(])@([A-Za-z0-9](?:[A-Za-z0-9\-]*[A-Za-z0-9])?)\b`)
+)
+
+// badgeHosts are image hosts that only serve badges
+var badgeHosts = []string{
+	"img.shields.io", "shields.io", "badgen.net", "badge.fury.io", "codecov.io",
+	"coveralls.io", "travis-ci.org", "travis-ci.com", "circleci.com", "goreportcard.com",
+	"app.codacy.com", "sonarcloud.io", "api.netlify.com",
+}
+
+// maintainerSections are headings of sections listing maintainers
+var maintainerSections = []string{"maintainer", "maintainers", "author", "authors", "owner", "owners", "maintained by", "code owners"}
+
+// Parse extracts the metadata of a README
+func Parse(readme string) *Metadata {
+	meta := &Metadata{
+		Badges:      parseBadges(readme),
+		Maintainers: parseMaintainers(readme),
+	}
+
+	if deps := registry.ParseReadmeDependencies(readme); deps != nil {
+		meta.TerraformVersion = requirementVersion(deps)
+	}
+	if meta.TerraformVersion == "" {
+		meta.TerraformVersion = statedVersion(readme)
+	}
+
+	return meta
+}
+
+// ParseModule extracts the metadata of a module's root README, using the dependency
+// tables the client already parsed
+func ParseModule(details *registry.ModuleDetails) *Metadata {
+	if details == nil {
+		return &Metadata{}
+	}
+
+	meta := Parse(details.Root.Readme)
+	if deps := details.Root.ReadmeDependencies; deps != nil {
+		if version := requirementVersion(deps); version != "" {
+			meta.TerraformVersion = version
+		}
+	}
+	return meta
+}
+
+// requirementVersion returns the terraform row of a Requirements table
+func requirementVersion(deps *registry.ReadmeDependencies) string {
+	for _, requirement := range deps.Requirements {
+		if strings.EqualFold(requirement.Name, "terraform") {
+			return strings.TrimSpace(requirement.Version)
+		}
+	}
+	return ""
+}
+
+// statedVersion returns the Terraform version constraint of a required_version setting
+// or, failing that, of the first prose statement naming one
+func statedVersion(readme string) string {
+	if match := requiredVersionRegex.FindStringSubmatch(readme); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+
+	prose := stripCodeBlocks(readme)
+	if match := constraintRegex.FindStringSubmatch(prose); match != nil {
+		return match[1] + " " + match[2]
+	}
+	if match := minimumRegex.FindStringSubmatch(prose); match != nil {
+		return ">= " + match[1]
+	}
+	return ""
+}
+
+// parseBadges returns the badge images of a README in order. Images count as badges
+// when they are served by a badge service or their URL names a badge, which excludes
+// diagrams and screenshots.
+func parseBadges(readme string) []Badge {
+	type found struct {
+		offset int
+		badge  Badge
+	}
+	var all []found
+	var covered [][2]int
+	add := func(start, end int, alt, image, link string) {
+		for _, span := range covered {
+			if start >= span[0] && start < span[1] {
+				return
+			}
+		}
+		covered = append(covered, [2]int{start, end})
+		if !isBadgeURL(image) {
+			return
+		}
+		all = append(all, found{offset: start, badge: Badge{
+			Kind:     badgeKind(alt, image, link),
+			Alt:      strings.TrimSpace(alt),
+			ImageURL: image,
+			LinkURL:  link,
+		}})
+	}
+
+	text := stripCodeBlocks(readme)
+	for _, m := range linkedImageRegex.FindAllStringSubmatchIndex(text, -1) {
+		add(m[0], m[1], text[m[2]:m[3]], text[m[4]:m[5]], text[m[6]:m[7]])
+	}
+	for _, m := range htmlLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+		img := text[m[4]:m[5]]
+		add(m[0], m[1], htmlAttr(htmlAltRegex, img), htmlAttr(htmlSrcRegex, img), text[m[2]:m[3]])
+	}
+	for _, m := range imageRegex.FindAllStringSubmatchIndex(text, -1) {
+		add(m[0], m[1], text[m[2]:m[3]], text[m[4]:m[5]], "")
+	}
+	for _, m := range htmlImageRegex.FindAllStringIndex(text, -1) {
+		img := text[m[0]:m[1]]
+		add(m[0], m[1], htmlAttr(htmlAltRegex, img), htmlAttr(htmlSrcRegex, img), "")
+	}
+
+	// Restore README order
+	sort.Slice(all, func(i, j int) bool { return all[i].offset < all[j].offset })
+
+	badges := make([]Badge, len(all))
+	for i, f := range all {
+		badges[i] = f.badge
+	}
+	return badges
+}
+
+// htmlAttr returns the value of an HTML attribute matched by re, or ""
+func htmlAttr(re *regexp.Regexp, tag string) string {
+	if match := re.FindStringSubmatch(tag); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// isBadgeURL reports whether an image URL is a badge
+func isBadgeURL(image string) bool {
+	u, err := url.Parse(image)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	for _, badgeHost := range badgeHosts {
+		if host == badgeHost || strings.HasSuffix(host, "."+badgeHost) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(u.Path), "badge")
+}
+
+// badgeKind classifies a badge by its alternative text and URLs
+func badgeKind(alt, image, link string) BadgeKind {
+	text := strings.ToLower(alt + " " + image)
+	contains := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(text, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case contains("license", "licence"):
+		return BadgeLicense
+	case contains("coverage", "codecov", "coveralls"):
+		return BadgeCoverage
+	case contains("security", "tfsec", "checkov", "snyk", "trivy", "ossf", "scorecard"):
+		return BadgeSecurity
+	case contains("registry.terraform.io", "terraform registry") ||
+		strings.Contains(strings.ToLower(link), "registry.terraform.io"):
+		return BadgeRegistry
+	case contains("release", "version", "/tag", "/v/", "semver"):
+		return BadgeVersion
+	case contains("build", "workflow", "actions", "/ci", " ci", "travis", "circleci", "pipeline", "test"):
+		return BadgeBuild
+	}
+	return BadgeOther
+}
+
+// parseMaintainers returns the maintainers listed in maintainer sections or named in
+// "maintained by" statements
+func parseMaintainers(readme string) []Maintainer {
+	var maintainers []Maintainer
+	seen := make(map[string]bool)
+	add := func(m Maintainer) {
+		key := strings.ToLower(m.Email + "|" + m.URL + "|" + m.Handle)
+		if key == "||" {
+			key = strings.ToLower(m.Name)
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		maintainers = append(maintainers, m)
+	}
+
+	inSection := false
+	for _, line := range strings.Split(stripCodeBlocks(readme), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if match := headingRegex.FindStringSubmatch(trimmed); match != nil {
+			inSection = isMaintainerSection(match[1])
+			continue
+		}
+
+		if match := maintainedByRegex.FindStringSubmatch(trimmed); match != nil {
+			people := helpRegex.ReplaceAllString(match[1], "")
+			// Longer text without links or handles is a sentence, e.g. "resources
+			// created by this module are tagged"
+			if strings.ContainsAny(people, "[@<") || len(strings.Fields(people)) <= 4 {
+				for _, m := range parsePeople(people) {
+					add(m)
+				}
+				continue
+			}
+		}
+
+		if inSection && trimmed != "" && !strings.HasPrefix(trimmed, "|") {
+			item := strings.TrimLeft(trimmed, "-*+ ")
+			if item == trimmed && !strings.ContainsAny(trimmed, "[@<") {
+				// Prose in a maintainer section only counts when it links or names people
+				continue
+			}
+			for _, m := range parsePeople(item) {
+				add(m)
+			}
+		}
+	}
+
+	return maintainers
+}
+
+// isMaintainerSection reports whether a heading starts a maintainer section
+func isMaintainerSection(heading string) bool {
+	heading = strings.ToLower(strings.Trim(heading, " :*_"))
+	for _, section := range maintainerSections {
+		if heading == section {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePeople parses a list of people such as "[Jane](https://github.com/jane) and
+// John Doe <john@example.com>"
+func parsePeople(text string) []Maintainer {
+	text = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(text), "."))
+
+	var people []Maintainer
+	for _, part := range splitPeople(text) {
+		m := parsePerson(part)
+		if m != (Maintainer{}) {
+			people = append(people, m)
+		}
+	}
+	return people
+}
+
+// splitPeople splits a list of people on commas and "and", keeping markdown links whole
+func splitPeople(text string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '[', '(', '<':
+			depth++
+		case ']', ')', '>':
+			if depth > 0 {
+				depth--
+			}
+		case ',', ';':
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		case ' ':
+			if depth == 0 && strings.HasPrefix(text[i:], " and ") {
+				parts = append(parts, text[start:i])
+				start = i + len(" and ")
+				i += len(" and ") - 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// parsePerson parses a single person: a markdown link, a name with an email address or
+// handle, a bare handle or a bare name
+func parsePerson(text string) Maintainer {
+	text = strings.TrimSpace(text)
+	var m Maintainer
+
+	if match := linkRegex.FindStringSubmatch(text); match != nil {
+		m.Name = strings.TrimSpace(match[1])
+		target := match[2]
+		if email, ok := strings.CutPrefix(target, "mailto:"); ok {
+			m.Email = email
+		} else {
+			m.URL = target
+		}
+		text = strings.Replace(text, match[0], "", 1)
+	}
+	if match := emailRegex.FindStringSubmatch(text); match != nil && m.Email == "" {
+		m.Email = match[1]
+		text = strings.Replace(text, match[0], "", 1)
+	}
+	if match := handleRegex.FindStringSubmatch(text); match != nil {
+		m.Handle = match[1]
+		text = strings.Replace(text, "@"+match[1], "", 1)
+	}
+	if strings.HasPrefix(m.Name, "@") {
+		m.Handle, m.Name = strings.TrimPrefix(m.Name, "@"), ""
+	}
+
+	if m.Name == "" {
+		name := strings.Trim(strings.TrimSpace(text), "()*_.:")
+		name = strings.TrimSpace(name)
+		// Longer text is a sentence rather than a name
+		if name != "" && len(strings.Fields(name)) <= 4 {
+			m.Name = name
+		}
+	}
+	return m
+}
+
+// stripCodeBlocks removes fenced code blocks from markdown
+func stripCodeBlocks(markdown string) string {
+	var b strings.Builder
+	inCodeBlock := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if !inCodeBlock {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+// Package registryctx holds the context keys of the registry client's per-call options,
+// such as request priority, cache bypass, raw response capture and doc pins. Each option
+// is a typed Key declared once under a unique name, so options cannot collide with each
+// other or with context values of other packages, and Keys lists every option in use.
+//
+// The registry package declares keys for its own types and exposes them through its
+// usual setters, such as registry.WithPriority. This package adds options that the
+// client passes through without interpreting: the tenant and purpose tags of a call,
+// for middleware, wait handlers and logging to attribute requests.
+package registryctx
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+var (
+	mu    sync.Mutex
+	names = make(map[string]bool)
+)
+
+// Key is a typed context key. Keys are compared by identity, so only the package that
+// declared a key can read or set its value.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey declares a context key. Names identify keys in Keys and must be unique; a
+// duplicate name panics, as it is a programming error found at package initialization.
+func NewKey[T any](name string) *Key[T] {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if names[name] {
+		panic(fmt.Sprintf("registryctx: key %q declared twice", name))
+	}
+	names[name] = true
+
+	return &Key[T]{name: name}
+}
+
+// Name returns the name the key was declared with
+func (k *Key[T]) Name() string {
+	return k.name
+}
+
+// String implements fmt.Stringer
+func (k *Key[T]) String() string {
+	return "registryctx." + k.name
+}
+
+// With returns a context carrying value for the key
+func (k *Key[T]) With(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, k, value)
+}
+
+// Get returns the value of the key in ctx and whether it is set
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(k).(T)
+	return value, ok
+}
+
+// Value returns the value of the key in ctx, or the zero value when it is not set
+func (k *Key[T]) Value(ctx context.Context) T {
+	value, _ := k.Get(ctx)
+	return value
+}
+
+// Keys returns the names of all declared keys, sorted
+func Keys() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	keys := make([]string, 0, len(names))
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var (
+	tenantKey  = NewKey[string]("tenant")
+	purposeKey = NewKey[[]string]("purpose")
+)
+
+// WithTenant tags the calls made with ctx with the tenant they are made for
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return tenantKey.With(ctx, tenant)
+}
+
+// Tenant returns the tenant tagged on ctx, or ""
+func Tenant(ctx context.Context) string {
+	return tenantKey.Value(ctx)
+}
+
+// WithPurpose adds purpose tags, such as "catalog-sync" or "interactive", to the calls
+// made with ctx. Tags accumulate over nested contexts without duplicates.
+func WithPurpose(ctx context.Context, tags ...string) context.Context {
+	purpose := slices.Clone(purposeKey.Value(ctx))
+	for _, tag := range tags {
+		if tag != "" && !slices.Contains(purpose, tag) {
+			purpose = append(purpose, tag)
+		}
+	}
+	return purposeKey.With(ctx, purpose)
+}
+
+// Purpose returns the purpose tags on ctx, in the order they were added
+func Purpose(ctx context.Context) []string {
+	return slices.Clone(purposeKey.Value(ctx))
+}
+
+// HasPurpose reports whether ctx carries a purpose tag
+func HasPurpose(ctx context.Context, tag string) bool {
+	return slices.Contains(purposeKey.Value(ctx), tag)
+}
@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize is the default size limit of API response bodies
+const DefaultMaxResponseSize = 64 << 20
+
+// ErrResponseTooLarge is returned when an API response body exceeds the size limit
+var ErrResponseTooLarge = errors.New("response exceeds size limit")
+
+// WithMaxResponseSize sets the size limit of API response bodies in bytes. Larger
+// responses fail with ErrResponseTooLarge instead of being read into memory.
+func WithMaxResponseSize(bytes int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxResponseSize = bytes
+	}
+}
+
+// WithStreamingDecode decodes JSON responses as they are read instead of reading the
+// whole body first, which halves the memory used by large list responses. Responses
+// are still read in full when they must be kept: with a response cache, raw capture or
+// raw results.
+func WithStreamingDecode() ClientOption {
+	return func(c *ClientConfig) {
+		c.StreamingDecode = true
+	}
+}
+
+// IsResponseTooLarge returns true if a response exceeded the size limit
+func IsResponseTooLarge(err error) bool {
+	return errors.Is(err, ErrResponseTooLarge)
+}
+
+// maxResponseSize returns the configured response size limit
+func (c *Client) maxResponseSize() int64 {
+	if c.config.MaxResponseSize > 0 {
+		return c.config.MaxResponseSize
+	}
+	return DefaultMaxResponseSize
+}
+
+// limitBody guards a response body with the size limit. A body announcing a larger
+// Content-Length fails at once; others fail once the limit has been read.
+func (c *Client) limitBody(resp *http.Response) (io.Reader, error) {
+	limit := c.maxResponseSize()
+	if resp.ContentLength > limit {
+		return nil, &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("%w: %d bytes, limit is %d", ErrResponseTooLarge, resp.ContentLength, limit),
+		}
+	}
+	return &limitedBody{r: resp.Body, limit: limit}, nil
+}
+
+// limitedBody fails reads once the body turns out to be larger than limit, without
+// passing on the bytes past the limit, so a decoder never sees a truncated body whole
+type limitedBody struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+// Read implements io.Reader
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.read > l.limit {
+		return 0, l.tooLarge()
+	}
+
+	// Read at most one byte past the limit to detect larger bodies
+	if remaining := l.limit - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n - int(l.read-l.limit), l.tooLarge()
+	}
+	return n, err
+}
+
+// tooLarge returns the error of a body over the limit
+func (l *limitedBody) tooLarge() error {
+	return fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, l.limit)
+}
+
+// canStream reports whether a successful response can be decoded as it is read: its
+// body does not have to be kept for the response cache, raw capture or a raw result
+func (c *Client) canStream(req *http.Request, cacheKey string, result interface{}) bool {
+	if !c.config.StreamingDecode || result == nil || cacheKey != "" {
+		return false
+	}
+	if _, ok := result.(*rawBody); ok {
+		return false
+	}
+	_, capturing := rawCaptureKey.Get(req.Context())
+	return !capturing
+}
+
+// streamResponse decodes a successful response body into result as it is read
+func streamResponse(resp *http.Response, body io.Reader, result interface{}) error {
+	err := json.NewDecoder(body).Decode(result)
+	if errors.Is(err, io.EOF) {
+		// An empty body leaves result as it is, as with buffered decoding
+		return nil
+	}
+	if err != nil {
+		if IsResponseTooLarge(err) {
+			return &ResponseError{StatusCode: resp.StatusCode, Err: err}
+		}
+		return &ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error decoding response: %w", timeoutError(err)),
+		}
+	}
+	return nil
+}
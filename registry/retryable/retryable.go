@@ -0,0 +1,63 @@
+// Package retryable runs the registry client's retry layer on hashicorp/go-retryablehttp.
+// The registry package retries requests with the standard library alone; import this
+// package only to keep using go-retryablehttp, for example to share its hooks.
+package retryable
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// WithRetryableHTTP makes the default HTTP client retry through go-retryablehttp
+func WithRetryableHTTP() registry.ClientOption {
+	return func(c *registry.ClientConfig) {
+		c.RetryMiddleware = Middleware
+	}
+}
+
+// Middleware builds a retry layer on go-retryablehttp that applies the client's retry
+// policy, backoff and per-attempt timeout
+func Middleware(config *registry.ClientConfig) registry.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		retryClient := retryablehttp.NewClient()
+		retryClient.Logger = config.Logger
+		retryClient.HTTPClient = &http.Client{
+			Timeout:   config.Timeout,
+			Transport: next,
+		}
+		retryClient.RetryMax = config.MaxRetries
+		retryClient.RetryWaitMin = config.RetryWaitMin
+		retryClient.RetryWaitMax = config.RetryWaitMax
+		retryClient.Backoff = func(_, _ time.Duration, attemptNum int, resp *http.Response) time.Duration {
+			return config.RetryBackoff(attemptNum, resp)
+		}
+		retryClient.CheckRetry = config.CheckRetry
+		retryClient.ErrorHandler = giveUp
+
+		return &retryablehttp.RoundTripper{Client: retryClient}
+	}
+}
+
+// giveUp matches the built-in retry layer once retries run out: the last response is
+// returned unread when there is one, so that it is reported as an API error
+func giveUp(resp *http.Response, err error, numTries int) (*http.Response, error) {
+	if err == nil && resp != nil {
+		return resp, nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	// The request error wrapping this one already names the method and URL
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return nil, fmt.Errorf("giving up after %d attempt(s): %w", numTries, err)
+}
@@ -0,0 +1,188 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+// Priority orders requests waiting for rate limit budget
+type Priority int
+
+const (
+	// PriorityPrefetch is for speculative work that may be dropped when budget is tight
+	PriorityPrefetch Priority = -10
+
+	// PriorityNormal is the default priority
+	PriorityNormal Priority = 0
+
+	// PriorityInteractive is for requests a user is actively waiting on
+	PriorityInteractive Priority = 10
+)
+
+// DefaultSchedulerThreshold is the token count at or below which an interactive
+// request cancels queued prefetches
+const DefaultSchedulerThreshold = 5
+
+// ErrSuperseded is returned to queued low-priority requests cancelled in favor of interactive work
+var ErrSuperseded = errors.New("request superseded by higher-priority work")
+
+var priorityKey = registryctx.NewKey[Priority]("priority")
+
+// WithPriority tags a context with a request priority used by the scheduler
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return priorityKey.With(ctx, priority)
+}
+
+// PriorityFromContext returns the priority tagged on the context, or PriorityNormal
+func PriorityFromContext(ctx context.Context) Priority {
+	if priority, ok := priorityKey.Get(ctx); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// Scheduler hands out rate limiter tokens to waiting requests in priority order.
+// When an interactive request arrives while the budget is tight, queued prefetch
+// requests are cancelled with ErrSuperseded.
+type Scheduler struct {
+	limiter   RateLimiter
+	threshold int
+
+	mu      sync.Mutex
+	queue   []*scheduledRequest
+	seq     uint64
+	changed chan struct{}
+}
+
+// scheduledRequest is a request waiting in the scheduler queue
+type scheduledRequest struct {
+	priority   Priority
+	seq        uint64
+	superseded chan struct{}
+}
+
+// NewScheduler creates a scheduler on top of a rate limiter. Interactive requests cancel
+// queued prefetches when the limiter has threshold tokens or fewer remaining.
+func NewScheduler(limiter RateLimiter, threshold int) *Scheduler {
+	if threshold < 0 {
+		threshold = 0
+	}
+	return &Scheduler{
+		limiter:   limiter,
+		threshold: threshold,
+		changed:   make(chan struct{}),
+	}
+}
+
+// WithPriorityScheduler enables priority scheduling of requests using the context
+// priority set by WithPriority. Interactive requests cancel queued prefetches when
+// threshold tokens or fewer remain; zero uses DefaultSchedulerThreshold, and a negative
+// threshold cancels them only once the budget is exhausted.
+func WithPriorityScheduler(threshold int) ClientOption {
+	return func(c *ClientConfig) {
+		c.EnableScheduler = true
+		c.SchedulerThreshold = threshold
+	}
+}
+
+// Wait blocks until the request may proceed, the context is cancelled, or the
+// request is superseded by higher-priority work
+func (s *Scheduler) Wait(ctx context.Context) error {
+	req := &scheduledRequest{
+		priority:   PriorityFromContext(ctx),
+		superseded: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.seq++
+	req.seq = s.seq
+	if req.priority >= PriorityInteractive && s.limiter.TokensRemaining() <= s.threshold {
+		s.supersedeLocked()
+	}
+	s.queue = append(s.queue, req)
+	s.mu.Unlock()
+
+	defer s.remove(req)
+
+	for {
+		s.mu.Lock()
+		if s.isHeadLocked(req) && s.limiter.TryAcquire() {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		wait := s.limiter.TimeUntilNextToken()
+		if wait <= 0 {
+			wait = 10 * time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-req.superseded:
+			return ErrSuperseded
+		case <-changed:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Pending returns the number of requests waiting in the queue
+func (s *Scheduler) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// supersedeLocked cancels all queued prefetch requests
+func (s *Scheduler) supersedeLocked() {
+	kept := s.queue[:0]
+	for _, queued := range s.queue {
+		if queued.priority <= PriorityPrefetch {
+			close(queued.superseded)
+			continue
+		}
+		kept = append(kept, queued)
+	}
+	s.queue = kept
+	s.notifyLocked()
+}
+
+// isHeadLocked reports whether req is the highest-priority, oldest queued request
+func (s *Scheduler) isHeadLocked(req *scheduledRequest) bool {
+	for _, queued := range s.queue {
+		if queued == req {
+			continue
+		}
+		if queued.priority > req.priority || (queued.priority == req.priority && queued.seq < req.seq) {
+			return false
+		}
+	}
+	return true
+}
+
+// remove drops req from the queue and wakes the remaining waiters
+func (s *Scheduler) remove(req *scheduledRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, queued := range s.queue {
+		if queued == req {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+	s.notifyLocked()
+}
+
+// notifyLocked wakes all waiters so they re-check their position
+func (s *Scheduler) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,392 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AttributeSchema describes a resource or data source attribute as documented
+type AttributeSchema struct {
+	// Name is the attribute name; attributes of nested blocks are prefixed with the
+	// block path, e.g. "root_block_device.volume_size"
+	Name string
+
+	// Type is the documented type, e.g. "String", "List of String" or "Block List, Max: 1";
+	// empty when the docs do not state it
+	Type string
+
+	Description string
+
+	Required bool
+	Optional bool
+	Computed bool
+}
+
+// ResourceSchema is the schema of a resource or data source parsed from its documentation
+type ResourceSchema struct {
+	// Name is the resource type, e.g. "aws_instance"
+	Name string
+
+	// Category is "resources" or "data-sources"
+	Category string
+
+	Subcategory string
+	DocID       string
+
+	Attributes []AttributeSchema
+}
+
+// Attribute returns the attribute with a name
+func (r *ResourceSchema) Attribute(name string) (AttributeSchema, bool) {
+	for _, attribute := range r.Attributes {
+		if attribute.Name == name {
+			return attribute, true
+		}
+	}
+	return AttributeSchema{}, false
+}
+
+// RequiredAttributes returns the required attributes
+func (r *ResourceSchema) RequiredAttributes() []AttributeSchema {
+	var required []AttributeSchema
+	for _, attribute := range r.Attributes {
+		if attribute.Required {
+			required = append(required, attribute)
+		}
+	}
+	return required
+}
+
+// ProviderSchema holds the parsed schemas of a provider version
+type ProviderSchema struct {
+	// Provider identifies the provider and the resolved version
+	Provider ProviderRef
+
+	// Resources and DataSources are sorted by name
+	Resources   []ResourceSchema
+	DataSources []ResourceSchema
+}
+
+// Resource returns the schema of a resource type, or nil
+func (p *ProviderSchema) Resource(name string) *ResourceSchema {
+	return findSchema(p.Resources, name)
+}
+
+// DataSource returns the schema of a data source type, or nil
+func (p *ProviderSchema) DataSource(name string) *ResourceSchema {
+	return findSchema(p.DataSources, name)
+}
+
+// findSchema returns the schema with a name, or nil
+func findSchema(schemas []ResourceSchema, name string) *ResourceSchema {
+	for i := range schemas {
+		if schemas[i].Name == name {
+			return &schemas[i]
+		}
+	}
+	return nil
+}
+
+// GetSchema downloads the resource and data source docs of a provider version and parses
+// their attribute schemas. This needs one request per documented resource and data
+// source. Docs that cannot be fetched are left out and reported in the returned
+// MultiError alongside the remaining schemas.
+func (s *ProvidersService) GetSchema(ctx context.Context, namespace, name, version string) (*ProviderSchema, error) {
+	if err := validateProviderParams(namespace, name); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.client.bestEffortContext(ctx)
+	defer cancel()
+
+	actualVersion, versionID, err := s.resolveVersion(ctx, namespace, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &ProviderSchema{
+		Provider: ProviderRef{Namespace: namespace, Name: name, Version: actualVersion},
+	}
+
+	var errs MultiError
+	completed, total := 0, 0
+	for _, category := range []string{"resources", "data-sources"} {
+		docs, err := s.listDocData(ctx, versionID, category)
+		if err != nil {
+			if budgetExpired(ctx) {
+				break
+			}
+			return nil, fmt.Errorf("failed to list %s: %w", category, err)
+		}
+		total += len(docs)
+
+		byID := make(map[string]ProviderDocData, len(docs))
+		ids := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			byID[doc.ID] = doc
+			ids = append(ids, doc.ID)
+		}
+
+		details := runBatch(ctx, ids, DefaultBatchConcurrency, s.GetDoc)
+
+		var schemas []ResourceSchema
+		for _, id := range ids {
+			result := details[id]
+			if result.Err != nil && budgetExpired(ctx) && errors.Is(result.Err, context.DeadlineExceeded) {
+				continue
+			}
+			completed++
+			if result.Err != nil {
+				errs.Add(result.Err)
+				continue
+			}
+			schemas = append(schemas, newResourceSchema(name, category, byID[id], result.Value.Data.Attributes.Content))
+		}
+
+		sort.Slice(schemas, func(i, j int) bool {
+			return schemas[i].Name < schemas[j].Name
+		})
+
+		if category == "resources" {
+			schema.Resources = schemas
+		} else {
+			schema.DataSources = schemas
+		}
+	}
+
+	if budgetExpired(ctx) {
+		return schema, s.client.partialResult("provider schema", completed, total, &errs)
+	}
+
+	return schema, errs.ErrorOrNil()
+}
+
+// GetResourceSchema returns the parsed schema of one resource or data source, identified
+// by its category ("resources" or "data-sources") and doc slug, e.g. "instance"
+func (s *ProvidersService) GetResourceSchema(ctx context.Context, ref ProviderRef, category, slug string) (*ResourceSchema, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	if category != "resources" && category != "data-sources" {
+		return nil, &ValidationError{
+			Field:   "category",
+			Value:   category,
+			Message: "category must be resources or data-sources",
+		}
+	}
+
+	_, versionID, err := s.resolveVersion(ctx, ref.Namespace, ref.Name, ref.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := s.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	docID, ok := index.Lookup(category, slug)
+	if !ok {
+		return nil, &APIError{
+			StatusCode: 404,
+			Message:    fmt.Sprintf("%s %s not documented for provider %s", category, slug, ref),
+		}
+	}
+
+	doc, err := s.GetDoc(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := newResourceSchema(ref.Name, category, doc.Data, doc.Data.Attributes.Content)
+	return &schema, nil
+}
+
+// newResourceSchema parses the schema of a resource or data source doc
+func newResourceSchema(provider, category string, doc ProviderDocData, content string) ResourceSchema {
+	return ResourceSchema{
+		Name:        provider + "_" + doc.Attributes.Slug,
+		Category:    category,
+		Subcategory: doc.Attributes.Subcategory,
+		DocID:       doc.ID,
+		Attributes:  ParseAttributeSchemas(content),
+	}
+}
+
+var (
+	schemaHeadingPattern = regexp.MustCompile(`^(#{2,4})\s+(.+?)\s*#*\s*$`)
+	nestedSchemaPattern  = regexp.MustCompile("^Nested Schema for `([^`]+)`")
+	plugindocsBullet     = regexp.MustCompile("^[-*]\\s+`([^`]+)`\\s+\\(([^)]+)\\)\\s*(.*)$")
+	plugindocsGroup      = regexp.MustCompile(`^(Required|Optional|Read-Only):?\s*$`)
+	classicBullet        = regexp.MustCompile("^[-*]\\s+`([^`]+)`\\s*(?:[-–—:]\\s*)?(.*)$")
+	classicFlags         = regexp.MustCompile(`^\(([^)]*)\)\s*(.*)$`)
+	blockIntroPattern    = regexp.MustCompile("^(?:The|An?|Each)?\\s*`([^`]+)`\\s+(?:configuration\\s+|nested\\s+)?(?:block|object)s?\\b")
+	identifierPattern    = regexp.MustCompile("^`?([a-z0-9_]+)`?$")
+	seeBelowPattern      = regexp.MustCompile(`^\(see \[below for nested schema\]\([^)]*\)\)\s*`)
+)
+
+// classicTypes are the type words recognized in "(Optional, String)" style annotations
+var classicTypes = map[string]bool{
+	"string": true, "number": true, "bool": true, "boolean": true, "int": true, "float": true,
+	"list": true, "set": true, "map": true, "block": true, "object": true,
+}
+
+// schema doc sections
+const (
+	schemaSectionNone = iota
+	schemaSectionPlugindocs
+	schemaSectionArguments
+	schemaSectionAttributes
+)
+
+// ParseAttributeSchemas parses attribute schemas from resource or data source Markdown
+// docs. It understands the "## Schema" layout generated by tfplugindocs (Required,
+// Optional and Read-Only groups with types, plus nested schemas) and the classic
+// "Argument Reference" / "Attributes Reference" layout, where arguments not marked
+// (Required) are treated as optional and exported attributes as computed. Attributes
+// listed in both sections are merged.
+func ParseAttributeSchemas(content string) []AttributeSchema {
+	var attributes []AttributeSchema
+	index := make(map[string]int)
+
+	add := func(attribute AttributeSchema) {
+		if i, ok := index[attribute.Name]; ok {
+			existing := &attributes[i]
+			existing.Required = existing.Required || attribute.Required
+			existing.Optional = existing.Optional || attribute.Optional
+			existing.Computed = existing.Computed || attribute.Computed
+			if existing.Type == "" {
+				existing.Type = attribute.Type
+			}
+			if existing.Description == "" {
+				existing.Description = attribute.Description
+			}
+			return
+		}
+		index[attribute.Name] = len(attributes)
+		attributes = append(attributes, attribute)
+	}
+
+	section := schemaSectionNone
+	group := ""
+	prefix := ""
+	inCode := false
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || line == "" {
+			continue
+		}
+
+		if match := schemaHeadingPattern.FindStringSubmatch(line); match != nil {
+			level, title := len(match[1]), match[2]
+			lower := strings.ToLower(title)
+
+			switch {
+			case level == 2 && lower == "schema":
+				section, group, prefix = schemaSectionPlugindocs, "", ""
+			case level == 2 && strings.Contains(lower, "argument"):
+				section, group, prefix = schemaSectionArguments, "", ""
+			case level == 2 && strings.Contains(lower, "attribute"):
+				section, group, prefix = schemaSectionAttributes, "", ""
+			case section == schemaSectionPlugindocs && nestedSchemaPattern.MatchString(title):
+				prefix = nestedSchemaPattern.FindStringSubmatch(title)[1] + "."
+				group = ""
+			case section == schemaSectionPlugindocs && plugindocsGroup.MatchString(title):
+				group = plugindocsGroup.FindStringSubmatch(title)[1]
+			case level == 2 && strings.HasPrefix(title, "Nested Schema for"):
+				// Nested schemas follow "## Schema" but some docs use level 2 headings
+				if match := nestedSchemaPattern.FindStringSubmatch(title); match != nil {
+					section, group, prefix = schemaSectionPlugindocs, "", match[1]+"."
+				}
+			case level == 2:
+				section, group, prefix = schemaSectionNone, "", ""
+			case section == schemaSectionArguments || section == schemaSectionAttributes:
+				// "### root_block_device" introduces a nested block
+				if match := identifierPattern.FindStringSubmatch(title); match != nil {
+					prefix = match[1] + "."
+				}
+			}
+			continue
+		}
+
+		switch section {
+		case schemaSectionPlugindocs:
+			if match := plugindocsGroup.FindStringSubmatch(line); match != nil {
+				group = match[1]
+				continue
+			}
+			match := plugindocsBullet.FindStringSubmatch(line)
+			if match == nil || group == "" {
+				continue
+			}
+			add(AttributeSchema{
+				Name:        prefix + match[1],
+				Type:        match[2],
+				Description: seeBelowPattern.ReplaceAllString(match[3], ""),
+				Required:    group == "Required",
+				Optional:    group == "Optional",
+				Computed:    group == "Read-Only",
+			})
+
+		case schemaSectionArguments, schemaSectionAttributes:
+			if match := blockIntroPattern.FindStringSubmatch(line); match != nil && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "*") {
+				prefix = match[1] + "."
+				continue
+			}
+			if strings.HasPrefix(strings.ToLower(line), "the following") {
+				prefix = ""
+				continue
+			}
+			match := classicBullet.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			attribute := AttributeSchema{Name: prefix + match[1], Description: match[2]}
+			if section == schemaSectionAttributes {
+				attribute.Computed = true
+			} else {
+				parseClassicFlags(&attribute)
+			}
+			add(attribute)
+		}
+	}
+
+	return attributes
+}
+
+// parseClassicFlags reads a leading "(Required)" / "(Optional, String)" annotation
+func parseClassicFlags(attribute *AttributeSchema) {
+	match := classicFlags.FindStringSubmatch(attribute.Description)
+	if match != nil {
+		for _, flag := range strings.Split(match[1], ",") {
+			flag = strings.TrimSpace(flag)
+			lower := strings.ToLower(flag)
+			switch {
+			case lower == "required":
+				attribute.Required = true
+			case lower == "optional":
+				attribute.Optional = true
+			case lower == "computed":
+				attribute.Computed = true
+			case classicTypes[strings.Fields(lower + " x")[0]]:
+				attribute.Type = flag
+			}
+		}
+		attribute.Description = match[2]
+	}
+
+	if !attribute.Required {
+		attribute.Optional = true
+	}
+}
@@ -0,0 +1,68 @@
+package scrape
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// DocNavItem is an entry of a provider docs sidebar in page order
+type DocNavItem struct {
+	// Category is the doc category from the link, e.g. "resources"
+	Category string
+
+	// Slug is the doc slug from the link
+	Slug string
+
+	// Title is the link text
+	Title string
+}
+
+var docLinkRegex = regexp.MustCompile(`(?s)<a[^>]+href="(?:https?://[^"/]+)?/providers/[^"/]+/[^"/]+/[^"/]+/docs/([^"/]+)/([^"/?#]+)"[^>]*>(.*?)</a>`)
+
+var tagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// ProviderDocOrder returns the docs sidebar entries of a provider version in the order
+// the website lists them. This ordering is not available from the API. The result is
+// empty when the page is rendered client-side or its markup has changed.
+func ProviderDocOrder(ctx context.Context, scraper registry.Scraper, ref registry.ProviderRef) ([]DocNavItem, error) {
+	version := ref.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	path := fmt.Sprintf("/providers/%s/%s/%s/docs", ref.Namespace, ref.Name, version)
+
+	page, err := scraper.FetchPage(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch docs page for %s: %w", ref, err)
+	}
+
+	return extractDocNav(string(page.Body)), nil
+}
+
+// extractDocNav extracts doc links in document order, skipping duplicates
+func extractDocNav(body string) []DocNavItem {
+	var items []DocNavItem
+	seen := make(map[string]bool)
+
+	for _, match := range docLinkRegex.FindAllStringSubmatch(body, -1) {
+		key := match[1] + "/" + match[2]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		items = append(items, DocNavItem{
+			Category: match[1],
+			Slug:     match[2],
+			Title:    strings.TrimSpace(html.UnescapeString(tagRegex.ReplaceAllString(match[3], ""))),
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,155 @@
+package scrape
+
+import (
+	"bufio"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsRules holds the robots.txt rules that apply to one user agent
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+// robotsRule is a single Allow or Disallow line
+type robotsRule struct {
+	pattern string
+	match   *regexp.Regexp
+	allow   bool
+}
+
+// parseRobots parses robots.txt content and returns the rules for the given user agent.
+// A group naming the agent's product token wins over the "*" group.
+func parseRobots(content, userAgent string) *robotsRules {
+	agent := strings.ToLower(productToken(userAgent))
+
+	type group struct {
+		agents []string
+		rules  robotsRules
+	}
+
+	var (
+		groups  []*group
+		current *group
+		inRules bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// Consecutive user-agent lines share a group
+			if current == nil || inRules {
+				current = &group{}
+				groups = append(groups, current)
+				inRules = false
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if value == "" {
+				// An empty Disallow allows everything
+				continue
+			}
+			current.rules.rules = append(current.rules.rules, robotsRule{
+				pattern: value,
+				match:   compileRobotsPattern(value),
+				allow:   key == "allow",
+			})
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+			inRules = true
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.rules.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	var (
+		best       *robotsRules
+		bestLength = -1
+	)
+	for _, g := range groups {
+		for _, a := range g.agents {
+			length := -1
+			switch {
+			case a == "*":
+				length = 0
+			case a == agent:
+				length = len(a)
+			}
+			if length > bestLength {
+				best = &g.rules
+				bestLength = length
+			}
+		}
+	}
+
+	if best == nil {
+		return &robotsRules{}
+	}
+	return best
+}
+
+// allowed reports whether path may be fetched. The longest matching rule wins and
+// Allow wins ties, following RFC 9309.
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	allowed := true
+	matchLength := -1
+	for _, rule := range r.rules {
+		if !rule.match.MatchString(path) {
+			continue
+		}
+		length := len(rule.pattern)
+		if length > matchLength || (length == matchLength && rule.allow) {
+			allowed = rule.allow
+			matchLength = length
+		}
+	}
+
+	return allowed
+}
+
+// compileRobotsPattern converts a robots.txt pattern supporting "*" and a trailing "$" to a regexp
+func compileRobotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return regexp.MustCompile(expr)
+}
+
+// productToken returns the product name of a user agent string, e.g. "my-tool" for "my-tool/1.0"
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(strings.TrimSpace(userAgent), " ")
+	token, _, _ = strings.Cut(token, "/")
+	return token
+}
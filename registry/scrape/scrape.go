@@ -0,0 +1,267 @@
+// Package scrape provides an opt-in backend for reading data that the Terraform
+// Registry only publishes on its website. Website scraping is not covered by any API
+// compatibility guarantee: page layouts may change at any time, and extractors in this
+// package are best-effort. The scraper respects robots.txt and caches fetched pages.
+package scrape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+const (
+	// DefaultBaseURL is the registry website
+	DefaultBaseURL = "https://registry.terraform.io"
+
+	// DefaultUserAgent is the user agent sent with page and robots.txt requests
+	DefaultUserAgent = "terraform-registry-client-scraper/1.0"
+
+	// DefaultCacheTTL is how long fetched pages and robots.txt are reused
+	DefaultCacheTTL = time.Hour
+
+	// maxPageSize limits the size of a fetched page
+	maxPageSize = 10 << 20
+)
+
+// ErrDisallowed is returned when robots.txt disallows fetching a path
+var ErrDisallowed = errors.New("path disallowed by robots.txt")
+
+// HTTPScraper fetches website pages over HTTP, honoring robots.txt rules and crawl
+// delays and caching responses. It implements registry.Scraper.
+type HTTPScraper struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	cacheTTL   time.Duration
+
+	mu            sync.Mutex
+	robots        *robotsRules
+	robotsExpires time.Time
+	pages         map[string]cachedPage
+	lastFetch     time.Time
+}
+
+// cachedPage is a cached page with its expiry
+type cachedPage struct {
+	page    *registry.ScrapedPage
+	expires time.Time
+}
+
+// Option configures an HTTPScraper
+type Option func(*HTTPScraper)
+
+// WithBaseURL sets the website base URL
+func WithBaseURL(baseURL string) Option {
+	return func(s *HTTPScraper) {
+		s.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for fetching pages
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(s *HTTPScraper) {
+		s.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the user agent; its product token selects the robots.txt group
+func WithUserAgent(userAgent string) Option {
+	return func(s *HTTPScraper) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithCacheTTL sets how long pages and robots.txt are cached
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *HTTPScraper) {
+		s.cacheTTL = ttl
+	}
+}
+
+// NewHTTPScraper creates a scraper for the registry website
+func NewHTTPScraper(opts ...Option) (*HTTPScraper, error) {
+	s := &HTTPScraper{
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: registry.DefaultTimeout},
+		userAgent:  DefaultUserAgent,
+		cacheTTL:   DefaultCacheTTL,
+		pages:      make(map[string]cachedPage),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if _, err := url.Parse(s.baseURL); err != nil || s.baseURL == "" {
+		return nil, &registry.ValidationError{
+			Field:   "baseURL",
+			Value:   s.baseURL,
+			Message: "invalid base URL",
+		}
+	}
+
+	if s.cacheTTL < 0 {
+		return nil, &registry.ValidationError{
+			Field:   "cacheTTL",
+			Value:   s.cacheTTL,
+			Message: "cache TTL cannot be negative",
+		}
+	}
+
+	return s, nil
+}
+
+// FetchPage fetches a site-relative path. Cached pages are returned without a request;
+// paths disallowed by robots.txt return ErrDisallowed.
+func (s *HTTPScraper) FetchPage(ctx context.Context, path string) (*registry.ScrapedPage, error) {
+	if path == "" || path[0] != '/' {
+		return nil, &registry.ValidationError{
+			Field:   "path",
+			Value:   path,
+			Message: "path must be site-relative and start with /",
+		}
+	}
+
+	s.mu.Lock()
+	if cached, ok := s.pages[path]; ok && time.Now().Before(cached.expires) {
+		s.mu.Unlock()
+		return cached.page, nil
+	}
+	s.mu.Unlock()
+
+	rules, err := s.robotsRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.allowed(path) {
+		return nil, fmt.Errorf("%w: %s", ErrDisallowed, path)
+	}
+
+	if err := s.waitCrawlDelay(ctx, rules.crawlDelay); err != nil {
+		return nil, err
+	}
+
+	page, err := s.fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if page.StatusCode < 200 || page.StatusCode >= 300 {
+		return nil, &registry.APIError{
+			StatusCode: page.StatusCode,
+			Message:    fmt.Sprintf("failed to fetch page %s", path),
+		}
+	}
+
+	s.mu.Lock()
+	s.pages[path] = cachedPage{page: page, expires: time.Now().Add(s.cacheTTL)}
+	s.mu.Unlock()
+
+	return page, nil
+}
+
+// robotsRules returns the cached robots.txt rules, fetching them when expired.
+// A missing robots.txt allows everything; an unreachable one disallows everything.
+func (s *HTTPScraper) robotsRules(ctx context.Context) (*robotsRules, error) {
+	s.mu.Lock()
+	if s.robots != nil && time.Now().Before(s.robotsExpires) {
+		rules := s.robots
+		s.mu.Unlock()
+		return rules, nil
+	}
+	s.mu.Unlock()
+
+	page, err := s.fetch(ctx, "/robots.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+
+	var rules *robotsRules
+	switch {
+	case page.StatusCode >= 200 && page.StatusCode < 300:
+		rules = parseRobots(string(page.Body), s.userAgent)
+	case page.StatusCode >= 400 && page.StatusCode < 500:
+		rules = &robotsRules{}
+	default:
+		return nil, fmt.Errorf("%w: robots.txt unavailable (status %d)", ErrDisallowed, page.StatusCode)
+	}
+
+	s.mu.Lock()
+	s.robots = rules
+	s.robotsExpires = time.Now().Add(s.cacheTTL)
+	s.mu.Unlock()
+
+	return rules, nil
+}
+
+// waitCrawlDelay waits until the crawl delay since the previous fetch has passed
+func (s *HTTPScraper) waitCrawlDelay(ctx context.Context, delay time.Duration) error {
+	s.mu.Lock()
+	next := s.lastFetch.Add(delay)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	s.lastFetch = next
+	s.mu.Unlock()
+
+	wait := time.Until(next)
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// fetch performs a GET request for a site-relative path
+func (s *HTTPScraper) fetch(ctx context.Context, path string) (*registry.ScrapedPage, error) {
+	pageURL := s.baseURL + path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, &registry.RequestError{
+			Method: http.MethodGet,
+			URL:    pageURL,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, &registry.RequestError{
+			Method: http.MethodGet,
+			URL:    pageURL,
+			Err:    fmt.Errorf("error performing request: %w", err),
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, &registry.ResponseError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("error reading response body: %w", err),
+		}
+	}
+
+	return &registry.ScrapedPage{
+		URL:        pageURL,
+		StatusCode: resp.StatusCode,
+		Body:       body,
+		FetchedAt:  time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrScrapingDisabled is returned when website data is requested without a configured scraper
+var ErrScrapingDisabled = errors.New("website scraping is disabled")
+
+// ScrapedPage is a page fetched from the registry website
+type ScrapedPage struct {
+	// URL is the absolute URL of the page
+	URL string
+
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+
+	// Body is the raw page content
+	Body []byte
+
+	// FetchedAt is when the page was fetched; cached pages keep their original time
+	FetchedAt time.Time
+}
+
+// Scraper fetches pages from the registry website for data the API does not expose,
+// such as docs sidebar ordering. Scraping is not part of the registry API contract:
+// page layouts change without notice and extraction is best-effort. Implementations
+// must respect robots.txt. See the registry/scrape package for the default implementation.
+type Scraper interface {
+	// FetchPage fetches a site-relative path such as "/providers/hashicorp/aws/latest/docs"
+	FetchPage(ctx context.Context, path string) (*ScrapedPage, error)
+}
+
+// WithScraper enables the website scraping fallback. Scraping is disabled by default.
+func WithScraper(scraper Scraper) ClientOption {
+	return func(c *ClientConfig) {
+		c.Scraper = scraper
+	}
+}
+
+// GetScraper returns the configured scraper, or ErrScrapingDisabled if none is configured
+func (c *Client) GetScraper() (Scraper, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.config.Scraper == nil {
+		return nil, ErrScrapingDisabled
+	}
+	return c.config.Scraper, nil
+}
@@ -0,0 +1,244 @@
+package sdkgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// GoOptions controls Go
+type GoOptions struct {
+	// Package is the package name of the generated file; the provider name when empty
+	Package string
+}
+
+// goInitialisms are name parts written in upper case, following Go naming conventions
+var goInitialisms = map[string]bool{
+	"acl": true, "api": true, "arn": true, "cidr": true, "cpu": true, "dns": true,
+	"http": true, "https": true, "iam": true, "id": true, "ip": true, "json": true,
+	"kms": true, "sku": true, "ssh": true, "ssl": true, "tls": true, "ttl": true,
+	"uri": true, "url": true, "vpc": true,
+}
+
+// Go returns a gofmt-formatted Go file declaring a struct per resource and data source
+// of a provider version, with a struct per nested block. Resource "aws_instance" becomes
+// AwsInstance and data source "aws_instance" DataAwsInstance. Required attributes are
+// values; other scalars are pointers so that unset attributes stay distinguishable.
+// Fields carry json tags with the attribute names and doc comments with their
+// descriptions.
+func Go(schema *registry.ProviderSchema, opts *GoOptions) ([]byte, error) {
+	if schema == nil {
+		return nil, errors.New("provider schema cannot be nil")
+	}
+	if opts == nil {
+		opts = &GoOptions{}
+	}
+
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = packageName(schema.Provider.Name)
+	}
+	if !isIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	g := &goGenerator{used: map[string]bool{"ProviderSource": true, "ProviderVersion": true}}
+	fmt.Fprintf(&g.buf, "// Code generated by sdkgen from the %s/%s %s provider docs. DO NOT EDIT.\n\n",
+		schema.Provider.Namespace, schema.Provider.Name, schema.Provider.Version)
+	fmt.Fprintf(&g.buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&g.buf, "// ProviderSource is the registry address of the provider\nconst ProviderSource = %q\n\n",
+		schema.Provider.Namespace+"/"+schema.Provider.Name)
+	fmt.Fprintf(&g.buf, "// ProviderVersion is the provider version the types were generated from\nconst ProviderVersion = %q\n",
+		schema.Provider.Version)
+
+	// Top-level names are reserved first so that nested blocks give way to them
+	resources := make([]string, len(schema.Resources))
+	for i, resource := range schema.Resources {
+		resources[i] = g.typeName(goName(resource.Name))
+	}
+	dataSources := make([]string, len(schema.DataSources))
+	for i, dataSource := range schema.DataSources {
+		dataSources[i] = g.typeName("Data" + goName(dataSource.Name))
+	}
+
+	for i, resource := range schema.Resources {
+		g.writeStruct(resources[i], fmt.Sprintf("is the %s resource", resource.Name), newObject(resource.Attributes))
+	}
+	for i, dataSource := range schema.DataSources {
+		g.writeStruct(dataSources[i], fmt.Sprintf("is the %s data source", dataSource.Name), newObject(dataSource.Attributes))
+	}
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return source, nil
+}
+
+// goGenerator writes Go type declarations
+type goGenerator struct {
+	buf  bytes.Buffer
+	used map[string]bool
+}
+
+// typeName reserves a unique type name based on name
+func (g *goGenerator) typeName(name string) string {
+	unique := name
+	for i := 2; g.used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.used[unique] = true
+	return unique
+}
+
+// writeStruct writes the struct of an object and then the structs of its nested blocks
+func (g *goGenerator) writeStruct(name, doc string, obj *object) {
+	type nestedStruct struct {
+		name string
+		doc  string
+		obj  *object
+	}
+	var nested []nestedStruct
+
+	fmt.Fprintf(&g.buf, "\n// %s %s\ntype %s struct {\n", name, doc, name)
+	fieldNames := make(map[string]bool)
+	for _, f := range obj.fields {
+		fieldName := goName(f.Key)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = goName(f.Key) + strconv.Itoa(i)
+		}
+		fieldNames[fieldName] = true
+
+		var goType string
+		if f.nested != nil {
+			blockName := g.typeName(name + goName(f.Key))
+			nested = append(nested, nestedStruct{blockName, fmt.Sprintf("is the %s block of %s", f.Key, name), f.nested})
+			goType = "[]" + blockName
+			if f.single {
+				goType = "*" + blockName
+			}
+		} else {
+			goType = goTypeOf(f.typ)
+			if !f.Required && isScalar(f.typ) {
+				goType = "*" + goType
+			}
+		}
+
+		if comment := fieldComment(f); comment != "" {
+			fmt.Fprintf(&g.buf, "\t// %s %s\n", fieldName, comment)
+		}
+		tag := f.Key
+		if !f.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:%q`\n", fieldName, goType, tag)
+	}
+	g.buf.WriteString("}\n")
+
+	for _, n := range nested {
+		g.writeStruct(n.name, n.doc, n.obj)
+	}
+}
+
+// fieldComment describes a field by its description and flags, on a single line
+func fieldComment(f *field) string {
+	var flags []string
+	switch {
+	case f.Required:
+		flags = append(flags, "required")
+	case f.Computed && !f.Optional:
+		flags = append(flags, "read-only")
+	}
+
+	description := strings.Join(strings.Fields(f.Description), " ")
+	switch {
+	case description != "" && len(flags) > 0:
+		return fmt.Sprintf("(%s) %s", strings.Join(flags, ", "), description)
+	case description != "":
+		return description
+	case len(flags) > 0:
+		return "is " + strings.Join(flags, ", ")
+	}
+	return ""
+}
+
+// goTypeOf returns the Go type of a value type
+func goTypeOf(t valueType) string {
+	switch t.kind {
+	case kindString:
+		return "string"
+	case kindNumber:
+		return "float64"
+	case kindInt:
+		return "int64"
+	case kindBool:
+		return "bool"
+	case kindList, kindSet:
+		return "[]" + goTypeOf(*t.elem)
+	case kindMap:
+		return "map[string]" + goTypeOf(*t.elem)
+	case kindObject:
+		return "map[string]any"
+	}
+	return "any"
+}
+
+// isScalar reports whether a value type is a string, number or bool
+func isScalar(t valueType) bool {
+	return t.kind == kindString || t.kind == kindNumber || t.kind == kindInt || t.kind == kindBool
+}
+
+// goName converts a snake_case name to an exported Go name, e.g. "subnet_id" to
+// "SubnetID"
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		part = strings.ToLower(part)
+		if goInitialisms[part] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+// packageName derives a package name from a provider name, e.g. "google-beta" to
+// "googlebeta"
+func packageName(provider string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(provider) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "provider" + s
+	}
+	return s
+}
+
+// isIdentifier reports whether s is a Go identifier
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
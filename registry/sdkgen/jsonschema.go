@@ -0,0 +1,131 @@
+package sdkgen
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// JSONSchemaDialect is the JSON Schema version of bundles
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Bundle is a JSON Schema document describing the resources and data sources of a
+// provider version. Each one is a definition under $defs, keyed "resource.<type>" or
+// "data.<type>", so a definition can be referenced as "#/$defs/resource.aws_instance".
+// Definitions describe the documented attributes only and do not forbid others.
+type Bundle struct {
+	Schema string `json:"$schema"`
+	Title  string `json:"title"`
+
+	// Provider and Version identify the provider version the bundle describes
+	Provider string `json:"x-terraform-provider"`
+	Version  string `json:"x-terraform-provider-version"`
+
+	Defs map[string]*Schema `json:"$defs"`
+}
+
+// Schema is a JSON Schema describing an attribute, block, resource or data source
+type Schema struct {
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Properties and Required describe objects; AdditionalProperties holds the value
+	// schema of maps
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	// Items and UniqueItems describe lists and sets
+	Items       *Schema `json:"items,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	// ReadOnly marks attributes computed by the provider that cannot be set
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Subcategory is the docs subcategory of a resource or data source
+	Subcategory string `json:"x-terraform-subcategory,omitempty"`
+}
+
+// NewBundle builds the JSON Schema bundle of a provider version
+func NewBundle(schema *registry.ProviderSchema) *Bundle {
+	ref := schema.Provider.Namespace + "/" + schema.Provider.Name
+	bundle := &Bundle{
+		Schema:   JSONSchemaDialect,
+		Title:    ref + " " + schema.Provider.Version,
+		Provider: ref,
+		Version:  schema.Provider.Version,
+		Defs:     make(map[string]*Schema),
+	}
+
+	for _, resource := range schema.Resources {
+		bundle.Defs["resource."+resource.Name] = resourceSchema(resource)
+	}
+	for _, dataSource := range schema.DataSources {
+		bundle.Defs["data."+dataSource.Name] = resourceSchema(dataSource)
+	}
+	return bundle
+}
+
+// JSONSchema returns the indented JSON of the bundle of a provider version; the output
+// is stable for the same schema, so bundles can be committed and diffed
+func JSONSchema(schema *registry.ProviderSchema) ([]byte, error) {
+	if schema == nil {
+		return nil, errors.New("provider schema cannot be nil")
+	}
+	return json.MarshalIndent(NewBundle(schema), "", "  ")
+}
+
+// resourceSchema returns the schema of a resource or data source
+func resourceSchema(resource registry.ResourceSchema) *Schema {
+	s := objectSchema(newObject(resource.Attributes))
+	s.Subcategory = resource.Subcategory
+	return s
+}
+
+// objectSchema returns the schema of a resource, data source or nested block
+func objectSchema(obj *object) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema, len(obj.fields))}
+	for _, f := range obj.fields {
+		var property *Schema
+		if f.nested != nil {
+			property = objectSchema(f.nested)
+			if !f.single {
+				property = &Schema{Type: "array", Items: property}
+			}
+		} else {
+			property = valueSchema(f.typ)
+		}
+
+		property.Description = f.Description
+		property.ReadOnly = f.Computed && !f.Optional && !f.Required
+		if f.Required {
+			s.Required = append(s.Required, f.Key)
+		}
+		s.Properties[f.Key] = property
+	}
+	return s
+}
+
+// valueSchema returns the schema of a value type
+func valueSchema(t valueType) *Schema {
+	switch t.kind {
+	case kindString:
+		return &Schema{Type: "string"}
+	case kindNumber:
+		return &Schema{Type: "number"}
+	case kindInt:
+		return &Schema{Type: "integer"}
+	case kindBool:
+		return &Schema{Type: "boolean"}
+	case kindList:
+		return &Schema{Type: "array", Items: valueSchema(*t.elem)}
+	case kindSet:
+		return &Schema{Type: "array", Items: valueSchema(*t.elem), UniqueItems: true}
+	case kindMap:
+		return &Schema{Type: "object", AdditionalProperties: valueSchema(*t.elem)}
+	case kindObject:
+		return &Schema{Type: "object"}
+	}
+	return &Schema{}
+}
@@ -0,0 +1,143 @@
+// Package sdkgen generates typed bindings from the provider schemas parsed from docs
+// (registry.ProviderSchema): Go helper structs for the resources and data sources of a
+// provider version (Go), and JSON Schema bundles that code generators for other
+// languages can consume (JSONSchema).
+//
+// Schemas parsed from docs are only as precise as the docs: attributes without a
+// documented type are generated as untyped values, and nested blocks whose cardinality
+// is not documented are generated as lists.
+package sdkgen
+
+import (
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// kind is the value type of an attribute
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindNumber
+	kindInt
+	kindBool
+	kindList
+	kindSet
+	kindMap
+	kindObject
+)
+
+// valueType is the type of an attribute value
+type valueType struct {
+	kind kind
+
+	// elem is the element type of lists, sets and maps
+	elem *valueType
+}
+
+// field is an attribute or nested block of an object
+type field struct {
+	registry.AttributeSchema
+
+	// Key is the attribute name without the path of enclosing blocks
+	Key string
+
+	typ valueType
+
+	// nested holds the fields of a nested block; single blocks hold at most one value
+	nested *object
+	single bool
+}
+
+// object is a resource, data source or nested block
+type object struct {
+	fields []*field
+	byKey  map[string]*field
+}
+
+// newObject builds the field tree of a resource or data source from its attributes,
+// whose nested block attributes are named by their dot-separated path
+func newObject(attributes []registry.AttributeSchema) *object {
+	root := &object{byKey: make(map[string]*field)}
+	for _, attribute := range attributes {
+		path := strings.Split(attribute.Name, ".")
+		parent := root
+		for _, key := range path[:len(path)-1] {
+			parent = parent.child(key).block()
+		}
+
+		f := parent.child(path[len(path)-1])
+		f.AttributeSchema = attribute
+		f.typ, f.single = parseType(attribute.Type)
+		if isBlockType(attribute.Type) {
+			f.block()
+		}
+	}
+	return root
+}
+
+// child returns the field with a key, adding an undocumented one when missing
+func (o *object) child(key string) *field {
+	if f, ok := o.byKey[key]; ok {
+		return f
+	}
+	f := &field{AttributeSchema: registry.AttributeSchema{Name: key, Optional: true}, Key: key}
+	o.byKey[key] = f
+	o.fields = append(o.fields, f)
+	return f
+}
+
+// block turns a field into a nested block and returns its fields
+func (f *field) block() *object {
+	if f.nested == nil {
+		f.nested = &object{byKey: make(map[string]*field)}
+	}
+	return f.nested
+}
+
+// parseType parses a documented type such as "String", "List of Number", "Map" or
+// "Block List, Max: 1", reporting whether it allows a single nested block
+func parseType(doc string) (valueType, bool) {
+	lower := strings.ToLower(strings.TrimSpace(doc))
+	single := strings.Contains(lower, "max: 1")
+	if i := strings.Index(lower, ","); i >= 0 {
+		lower = strings.TrimSpace(lower[:i])
+	}
+
+	words := strings.Fields(lower)
+	if len(words) == 0 {
+		return valueType{kind: kindAny}, single
+	}
+
+	switch words[0] {
+	case "block", "attributes":
+		// Nested blocks are typed by their fields; the word after it is the collection
+		return valueType{kind: kindObject}, single || len(words) == 1 && words[0] == "attributes"
+	case "list", "set", "map":
+		collection := map[string]kind{"list": kindList, "set": kindSet, "map": kindMap}[words[0]]
+		elem := valueType{kind: kindAny}
+		if len(words) > 2 && words[1] == "of" {
+			elem, _ = parseType(strings.Join(words[2:], " "))
+		}
+		return valueType{kind: collection, elem: &elem}, single
+	case "string":
+		return valueType{kind: kindString}, single
+	case "number", "float":
+		return valueType{kind: kindNumber}, single
+	case "int", "integer":
+		return valueType{kind: kindInt}, single
+	case "bool", "boolean":
+		return valueType{kind: kindBool}, single
+	case "object":
+		return valueType{kind: kindObject}, single
+	}
+	return valueType{kind: kindAny}, single
+}
+
+// isBlockType reports whether a documented type is a nested block
+func isBlockType(doc string) bool {
+	lower := strings.ToLower(strings.TrimSpace(doc))
+	return strings.HasPrefix(lower, "block") || strings.HasPrefix(lower, "attributes")
+}
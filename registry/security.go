@@ -0,0 +1,303 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the GitHub REST API used for security advisory lookups
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// SecurityService looks up security advisories for providers. Advisories come from
+// the GitHub Advisory Database, matched by the Go module path of the provider's
+// source repository. Providers not hosted on GitHub cannot be checked.
+type SecurityService struct {
+	client *Client
+}
+
+// Advisory is a published security advisory affecting a provider
+type Advisory struct {
+	// ID is the GitHub Security Advisory ID, e.g. GHSA-xxxx-xxxx-xxxx
+	ID string
+
+	// CVE is the CVE ID, when one has been assigned
+	CVE string
+
+	Summary     string
+	Severity    string
+	URL         string
+	PublishedAt time.Time
+
+	// VulnerableRange is the affected version range, e.g. ">= 1.0.0, < 1.2.3"
+	VulnerableRange string
+
+	// PatchedVersion is the first fixed version, if any
+	PatchedVersion string
+}
+
+// Affects reports whether version falls within the advisory's vulnerable range
+func (a Advisory) Affects(version string) bool {
+	return versionInRange(version, a.VulnerableRange)
+}
+
+// AdvisoryReport holds the advisories found for a provider
+type AdvisoryReport struct {
+	Provider ProviderRef
+
+	// Package is the Go module path advisories were matched against
+	Package string
+
+	// Advisories are all advisories published for the provider
+	Advisories []Advisory
+
+	// Affected are the advisories affecting Provider.Version; empty when no version is set
+	Affected []Advisory
+}
+
+// WithGitHubAPI sets the GitHub API base URL and an optional token for advisory lookups.
+// A token raises GitHub's unauthenticated rate limit.
+func WithGitHubAPI(baseURL, token string) ClientOption {
+	return func(c *ClientConfig) {
+		c.GitHubAPIURL = baseURL
+		c.GitHubToken = token
+	}
+}
+
+// GetAdvisories returns the security advisories published for a provider. When ref has a
+// version, advisories affecting that version are also listed in Affected.
+func (s *SecurityService) GetAdvisories(ctx context.Context, ref ProviderRef) (*AdvisoryReport, error) {
+	if err := validateProviderParams(ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	provider, err := s.client.Providers.Get(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider %s: %w", ref, err)
+	}
+
+	pkg, err := goModulePath(provider.Attributes.Source)
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up advisories for %s: %w", ref, err)
+	}
+
+	advisories, err := s.listAdvisories(ctx, pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get advisories for %s: %w", ref, err)
+	}
+
+	report := &AdvisoryReport{
+		Provider:   ref,
+		Package:    pkg,
+		Advisories: advisories,
+	}
+
+	if ref.Version != "" && ref.Version != "latest" {
+		for _, advisory := range advisories {
+			if advisory.Affects(ref.Version) {
+				report.Affected = append(report.Affected, advisory)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// listAdvisories queries the GitHub global advisories API for a Go package
+func (s *SecurityService) listAdvisories(ctx context.Context, pkg string) ([]Advisory, error) {
+	config := s.client.config
+
+	baseURL := config.GitHubAPIURL
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+
+	values := url.Values{}
+	values.Add("ecosystem", "go")
+	values.Add("affects", pkg)
+	values.Add("per_page", "100")
+
+	endpoint := fmt.Sprintf("%s/advisories?%s", strings.TrimSuffix(baseURL, "/"), values.Encode())
+
+	var result []struct {
+		GHSAID          string    `json:"ghsa_id"`
+		CVEID           string    `json:"cve_id"`
+		Summary         string    `json:"summary"`
+		Severity        string    `json:"severity"`
+		HTMLURL         string    `json:"html_url"`
+		PublishedAt     time.Time `json:"published_at"`
+		Vulnerabilities []struct {
+			Package struct {
+				Ecosystem string `json:"ecosystem"`
+				Name      string `json:"name"`
+			} `json:"package"`
+			VulnerableVersionRange string `json:"vulnerable_version_range"`
+			FirstPatchedVersion    string `json:"first_patched_version"`
+		} `json:"vulnerabilities"`
+	}
+
+	if err := s.githubGet(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+
+	advisories := make([]Advisory, 0, len(result))
+	for _, item := range result {
+		for _, vuln := range item.Vulnerabilities {
+			if !strings.EqualFold(vuln.Package.Name, pkg) {
+				continue
+			}
+			advisories = append(advisories, Advisory{
+				ID:              item.GHSAID,
+				CVE:             item.CVEID,
+				Summary:         item.Summary,
+				Severity:        item.Severity,
+				URL:             item.HTMLURL,
+				PublishedAt:     item.PublishedAt,
+				VulnerableRange: vuln.VulnerableVersionRange,
+				PatchedVersion:  vuln.FirstPatchedVersion,
+			})
+		}
+	}
+
+	return advisories, nil
+}
+
+// listRepositoryAdvisories lists the published security advisories of a GitHub
+// repository, which is where Terraform modules report vulnerabilities
+func (s *SecurityService) listRepositoryAdvisories(ctx context.Context, owner, repo string) ([]Advisory, error) {
+	baseURL := s.client.config.GitHubAPIURL
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+
+	values := url.Values{}
+	values.Add("state", "published")
+	values.Add("per_page", "100")
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/security-advisories?%s", strings.TrimSuffix(baseURL, "/"),
+		url.PathEscape(owner), url.PathEscape(repo), values.Encode())
+
+	var result []struct {
+		GHSAID          string    `json:"ghsa_id"`
+		CVEID           string    `json:"cve_id"`
+		Summary         string    `json:"summary"`
+		Severity        string    `json:"severity"`
+		HTMLURL         string    `json:"html_url"`
+		PublishedAt     time.Time `json:"published_at"`
+		Vulnerabilities []struct {
+			VulnerableVersionRange string `json:"vulnerable_version_range"`
+			PatchedVersions        string `json:"patched_versions"`
+		} `json:"vulnerabilities"`
+	}
+
+	if err := s.githubGet(ctx, endpoint, &result); err != nil {
+		return nil, err
+	}
+
+	advisories := make([]Advisory, 0, len(result))
+	for _, item := range result {
+		for _, vuln := range item.Vulnerabilities {
+			advisories = append(advisories, Advisory{
+				ID:              item.GHSAID,
+				CVE:             item.CVEID,
+				Summary:         item.Summary,
+				Severity:        item.Severity,
+				URL:             item.HTMLURL,
+				PublishedAt:     item.PublishedAt,
+				VulnerableRange: vuln.VulnerableVersionRange,
+				PatchedVersion:  vuln.PatchedVersions,
+			})
+		}
+	}
+
+	return advisories, nil
+}
+
+// githubGet performs a GitHub API request with the configured token
+func (s *SecurityService) githubGet(ctx context.Context, endpoint string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return &RequestError{
+			Method: http.MethodGet,
+			URL:    endpoint,
+			Err:    fmt.Errorf("error creating request: %w", err),
+		}
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", s.client.userAgent)
+	if token := s.client.config.GitHubToken; token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	return s.client.do(req, result)
+}
+
+// goModulePath converts a GitHub source URL to its Go module path
+func goModulePath(source string) (string, error) {
+	u, err := url.Parse(source)
+	if err != nil || !strings.EqualFold(u.Host, "github.com") {
+		return "", &ValidationError{
+			Field:   "source",
+			Value:   source,
+			Message: "provider source is not a GitHub repository",
+		}
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", &ValidationError{
+			Field:   "source",
+			Value:   source,
+			Message: "provider source is not a GitHub repository",
+		}
+	}
+
+	return fmt.Sprintf("github.com/%s/%s", parts[0], strings.TrimSuffix(parts[1], ".git")), nil
+}
+
+// versionInRange reports whether version satisfies a comma-separated range such as
+// ">= 1.0.0, < 1.2.3". An empty range matches nothing.
+func versionInRange(version, versionRange string) bool {
+	versionRange = strings.TrimSpace(versionRange)
+	if versionRange == "" || version == "" {
+		return false
+	}
+
+	for _, clause := range strings.Split(versionRange, ",") {
+		clause = strings.TrimSpace(clause)
+
+		op := "="
+		for _, candidate := range []string{">=", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(clause, candidate) {
+				op = candidate
+				clause = strings.TrimSpace(strings.TrimPrefix(clause, candidate))
+				break
+			}
+		}
+
+		cmp := CompareVersions(NormalizeVersion(version), NormalizeVersion(clause))
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
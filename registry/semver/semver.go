@@ -0,0 +1,290 @@
+// Package semver parses and orders the semantic versions of registry modules and
+// providers following Semantic Versioning 2.0.0: pre-release identifiers are compared
+// one by one, numerically when both are numeric, and build metadata is ignored for
+// precedence. As in Terraform, a "v" prefix and leading zeros are accepted.
+//
+// The string helpers, such as Compare, SortVersions and LatestStable, order versions
+// that do not parse before every valid version, so that sorting mixed input is stable
+// and never loses entries.
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version
+type Version struct {
+	Major, Minor, Patch uint64
+
+	// Prerelease holds the dot-separated pre-release identifiers, e.g. ["beta", "2"]
+	// for "1.0.0-beta.2"; empty for releases
+	Prerelease []string
+
+	// Build is the build metadata after "+", which does not affect precedence
+	Build string
+
+	original string
+}
+
+// ParseError reports a string that is not a semantic version
+type ParseError struct {
+	Version string
+	Reason  string
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid semantic version %q: %s", e.Version, e.Reason)
+}
+
+// Parse parses a semantic version such as "1.2.3", "v1.2.3-beta.1" or "1.2.3+build.5"
+func Parse(s string) (Version, error) {
+	v := Version{original: s}
+	rest := strings.TrimPrefix(s, "v")
+
+	rest, build, hasBuild := strings.Cut(rest, "+")
+	if hasBuild {
+		if err := checkIdentifiers(build); err != nil {
+			return Version{}, &ParseError{Version: s, Reason: "build metadata " + err.Error()}
+		}
+		v.Build = build
+	}
+
+	core, prerelease, hasPrerelease := strings.Cut(rest, "-")
+	if hasPrerelease {
+		if err := checkIdentifiers(prerelease); err != nil {
+			return Version{}, &ParseError{Version: s, Reason: "pre-release " + err.Error()}
+		}
+		v.Prerelease = strings.Split(prerelease, ".")
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, &ParseError{Version: s, Reason: "expected major.minor.patch"}
+	}
+	for i, field := range []*uint64{&v.Major, &v.Minor, &v.Patch} {
+		if !isNumeric(parts[i]) {
+			return Version{}, &ParseError{Version: s, Reason: fmt.Sprintf("%q is not a number", parts[i])}
+		}
+		n, err := strconv.ParseUint(parts[i], 10, 64)
+		if err != nil {
+			return Version{}, &ParseError{Version: s, Reason: fmt.Sprintf("%q is out of range", parts[i])}
+		}
+		*field = n
+	}
+
+	return v, nil
+}
+
+// MustParse parses a version and panics if it is invalid
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsValid reports whether s is a semantic version
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// checkIdentifiers checks dot-separated pre-release or build identifiers
+func checkIdentifiers(s string) error {
+	for _, identifier := range strings.Split(s, ".") {
+		if identifier == "" {
+			return fmt.Errorf("has an empty identifier")
+		}
+		for _, r := range identifier {
+			if !isAlphanumeric(r) && r != '-' {
+				return fmt.Errorf("identifier %q has invalid character %q", identifier, r)
+			}
+		}
+	}
+	return nil
+}
+
+// String returns the version without a "v" prefix, e.g. "1.2.3-beta.1+build"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Original returns the string the version was parsed from
+func (v Version) Original() string {
+	if v.original == "" {
+		return v.String()
+	}
+	return v.original
+}
+
+// IsPrerelease reports whether the version has pre-release identifiers
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 as v has lower, equal or higher precedence than other.
+// Versions differing only in build metadata are equal.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A release has higher precedence than its pre-releases
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		if c := compareIdentifiers(v.Prerelease[i], other.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	// A larger set of identifiers has higher precedence when the common ones are equal
+	switch {
+	case len(v.Prerelease) < len(other.Prerelease):
+		return -1
+	case len(v.Prerelease) > len(other.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+// LessThan reports whether v has lower precedence than other
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// Equal reports whether v and other have the same precedence
+func (v Version) Equal(other Version) bool {
+	return v.Compare(other) == 0
+}
+
+// compareIdentifiers compares pre-release identifiers: numeric identifiers numerically,
+// others in ASCII order, and numeric identifiers lower than alphanumeric ones
+func compareIdentifiers(a, b string) int {
+	aNumeric, bNumeric := isNumeric(a), isNumeric(b)
+	switch {
+	case aNumeric && bNumeric:
+		// Compare without parsing so that long identifiers cannot overflow
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare compares two version strings, returning -1, 0 or 1. Strings that do not parse
+// order before every valid version and among themselves as strings.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+// IsPrerelease reports whether s is a valid pre-release version
+func IsPrerelease(s string) bool {
+	v, err := Parse(s)
+	return err == nil && v.IsPrerelease()
+}
+
+// SortVersions sorts version strings in place from lowest to highest precedence.
+// Versions of equal precedence, such as builds of one release, keep their order.
+func SortVersions(versions []string) {
+	slices.SortStableFunc(versions, Compare)
+}
+
+// SortVersionsDescending sorts version strings in place from highest to lowest
+// precedence
+func SortVersionsDescending(versions []string) {
+	slices.SortStableFunc(versions, func(a, b string) int {
+		return Compare(b, a)
+	})
+}
+
+// Latest returns the version with the highest precedence, pre-releases included, or ""
+// when none is valid
+func Latest(versions []string) string {
+	return latest(versions, true)
+}
+
+// LatestStable returns the release with the highest precedence, excluding pre-releases,
+// or "" when there is none
+func LatestStable(versions []string) string {
+	return latest(versions, false)
+}
+
+// latest returns the valid version with the highest precedence
+func latest(versions []string, prereleases bool) string {
+	var best *Version
+	for _, s := range versions {
+		v, err := Parse(s)
+		if err != nil || (!prereleases && v.IsPrerelease()) {
+			continue
+		}
+		if best == nil || v.Compare(*best) > 0 {
+			best = &v
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Original()
+}
+
+// isNumeric reports whether s is a non-empty string of ASCII digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlphanumeric reports whether r is an ASCII letter or digit
+func isAlphanumeric(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
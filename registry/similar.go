@@ -0,0 +1,275 @@
+package registry
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultSimilarDocsLimit is the number of docs SimilarDocs returns when limit is zero
+	DefaultSimilarDocsLimit = 10
+
+	// maxSimilarityDocs bounds the docs kept for SimilarDocs; the oldest are dropped first
+	maxSimilarityDocs = 5000
+
+	// similarityTitleWeight is how many times the title and slug terms of a doc count,
+	// so that names weigh more than prose
+	similarityTitleWeight = 3
+)
+
+// SimilarDoc is a doc related to another doc by content
+type SimilarDoc struct {
+	DocID       string
+	Title       string
+	Category    string
+	Subcategory string
+	Slug        string
+
+	// Score is the cosine similarity of the TF-IDF vectors of the two docs, from 0 to 1
+	Score float64
+}
+
+// SimilarDocs returns the docs most similar to a doc, best first, to power "see also"
+// links, such as aws_security_group_rule for aws_security_group. Docs are compared by
+// TF-IDF over their words and two-word shingles, with titles and slugs weighted up.
+//
+// Candidates are the docs this client has already fetched with GetDoc, for example
+// through GetSchema or ExportDocs, so no request is made beyond fetching the doc itself
+// when it is not known yet. When the provider version of the docs is known from a doc
+// listing, only docs of the same version are compared. Other versions of the same doc
+// are left out. A zero limit returns DefaultSimilarDocsLimit docs.
+func (s *ProvidersService) SimilarDocs(ctx context.Context, docID string, limit int) ([]SimilarDoc, error) {
+	if docID == "" {
+		return nil, &ValidationError{
+			Field:   "docID",
+			Value:   docID,
+			Message: "doc ID cannot be empty",
+		}
+	}
+
+	if limit < 0 {
+		return nil, &ValidationError{
+			Field:   "limit",
+			Value:   limit,
+			Message: "limit cannot be negative",
+		}
+	}
+	if limit == 0 {
+		limit = DefaultSimilarDocsLimit
+	}
+
+	if !s.similarity.has(docID) {
+		if _, err := s.GetDoc(ctx, docID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.similarity.similar(docID, limit), nil
+}
+
+// similarityDoc is a doc of the similarity corpus
+type similarityDoc struct {
+	id          string
+	title       string
+	category    string
+	subcategory string
+	slug        string
+
+	// terms counts the words and shingles of the doc
+	terms map[string]float64
+}
+
+// similarityCorpus holds the terms of fetched docs for SimilarDocs
+type similarityCorpus struct {
+	mu    sync.RWMutex
+	docs  map[string]*similarityDoc
+	order []string
+
+	// versions maps doc IDs to the provider version IDs they were listed under
+	versions map[string]string
+}
+
+// add records the terms of a fetched doc
+func (c *similarityCorpus) add(doc ProviderDocData) {
+	if doc.ID == "" {
+		return
+	}
+
+	attrs := doc.Attributes
+	entry := &similarityDoc{
+		id:          doc.ID,
+		title:       attrs.Title,
+		category:    attrs.Category,
+		subcategory: attrs.Subcategory,
+		slug:        attrs.Slug,
+		terms:       similarityTerms(attrs),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.docs == nil {
+		c.docs = make(map[string]*similarityDoc)
+	}
+	if _, ok := c.docs[doc.ID]; !ok {
+		c.order = append(c.order, doc.ID)
+	}
+	c.docs[doc.ID] = entry
+
+	for len(c.order) > maxSimilarityDocs {
+		delete(c.docs, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// addVersion records the provider version a doc was listed under
+func (c *similarityCorpus) addVersion(docID, providerVersionID string) {
+	if docID == "" || providerVersionID == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.versions == nil {
+		c.versions = make(map[string]string)
+	}
+	c.versions[docID] = providerVersionID
+}
+
+// has reports whether a doc is in the corpus
+func (c *similarityCorpus) has(docID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.docs[docID]
+	return ok
+}
+
+// similar ranks the docs of the corpus by similarity to a doc
+func (c *similarityCorpus) similar(docID string, limit int) []SimilarDoc {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	target, ok := c.docs[docID]
+	if !ok {
+		return nil
+	}
+
+	// Compare with the docs of the same provider version when it is known
+	version := c.versions[docID]
+	candidates := make([]*similarityDoc, 0, len(c.docs))
+	for _, id := range c.order {
+		doc := c.docs[id]
+		if version != "" && c.versions[id] != "" && c.versions[id] != version {
+			continue
+		}
+		candidates = append(candidates, doc)
+	}
+
+	df := make(map[string]int)
+	for _, doc := range candidates {
+		for term := range doc.terms {
+			df[term]++
+		}
+	}
+	n := float64(len(candidates))
+	weights := func(doc *similarityDoc) (map[string]float64, float64) {
+		vector := make(map[string]float64, len(doc.terms))
+		norm := 0.0
+		for term, count := range doc.terms {
+			weight := (1 + math.Log(count)) * math.Log(1+n/float64(df[term]))
+			vector[term] = weight
+			norm += weight * weight
+		}
+		return vector, math.Sqrt(norm)
+	}
+
+	targetVector, targetNorm := weights(target)
+	if targetNorm == 0 {
+		return nil
+	}
+
+	// Keep the best match per category and slug, leaving out the doc itself in any version
+	best := make(map[string]SimilarDoc)
+	for _, doc := range candidates {
+		if doc.id == target.id || (doc.category == target.category && doc.slug == target.slug) {
+			continue
+		}
+
+		vector, norm := weights(doc)
+		if norm == 0 {
+			continue
+		}
+		dot := 0.0
+		for term, weight := range vector {
+			dot += weight * targetVector[term]
+		}
+		score := dot / (norm * targetNorm)
+		if score <= 0 {
+			continue
+		}
+
+		key := doc.category + "/" + doc.slug
+		if existing, ok := best[key]; ok && existing.Score >= score {
+			continue
+		}
+		best[key] = SimilarDoc{
+			DocID:       doc.id,
+			Title:       doc.title,
+			Category:    doc.category,
+			Subcategory: doc.subcategory,
+			Slug:        doc.slug,
+			Score:       score,
+		}
+	}
+
+	results := make([]SimilarDoc, 0, len(best))
+	for _, doc := range best {
+		results = append(results, doc)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].DocID < results[j].DocID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+var similarityWordPattern = regexp.MustCompile(`[a-z0-9]+(?:_[a-z0-9]+)*`)
+
+// similarityTerms counts the words and two-word shingles of a doc. Identifiers such as
+// aws_security_group count as a whole and by their parts.
+func similarityTerms(attrs DocAttributes) map[string]float64 {
+	terms := make(map[string]float64)
+
+	addText := func(text string, weight float64) {
+		words := similarityWordPattern.FindAllString(strings.ToLower(text), -1)
+		for i, word := range words {
+			terms[word] += weight
+			if strings.Contains(word, "_") {
+				for _, part := range strings.Split(word, "_") {
+					terms[part] += weight
+				}
+			}
+			if i > 0 {
+				terms[words[i-1]+" "+word] += weight
+			}
+		}
+	}
+
+	addText(stripFrontMatter(attrs.Content), 1)
+	addText(attrs.Title, similarityTitleWeight)
+	addText(attrs.Slug, similarityTitleWeight)
+
+	return terms
+}
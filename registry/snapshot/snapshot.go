@@ -0,0 +1,290 @@
+// Package snapshot records providers and modules from a registry into offline
+// snapshots for air-gapped environments. A snapshot holds the API responses the client
+// reads for the recorded artifacts: for a provider its version list, docs and schema
+// summary, for a module its metadata and versions. A client created with
+// registry.WithOfflineSnapshot serves the same calls from the snapshot without network
+// access.
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// Entry kinds
+const (
+	KindProvider = "provider"
+	KindModule   = "module"
+)
+
+// Options configures a Recorder
+type Options struct {
+	// Concurrency bounds concurrent provider doc downloads;
+	// registry.DefaultBatchConcurrency when zero
+	Concurrency int
+}
+
+// Validate validates the options
+func (o *Options) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Concurrency < 0 {
+		return &registry.ValidationError{
+			Field:   "concurrency",
+			Value:   o.Concurrency,
+			Message: "concurrency cannot be negative",
+		}
+	}
+	return nil
+}
+
+// Recorder records the responses a client reads for providers and modules into a
+// snapshot. A Recorder is safe for concurrent use.
+type Recorder struct {
+	client   *registry.Client
+	opts     Options
+	snapshot *registry.Snapshot
+}
+
+// NewRecorder returns a recorder that reads through client into an empty snapshot.
+// Only requests are recorded, so client should not have read the artifacts already:
+// results it serves from its in-memory indexes, such as slug indexes, would be missing
+// from the snapshot.
+func NewRecorder(client *registry.Client, opts *Options) (*Recorder, error) {
+	if client == nil {
+		return nil, &registry.ValidationError{
+			Field:   "client",
+			Message: "client cannot be nil",
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	return &Recorder{
+		client:   client,
+		opts:     *opts,
+		snapshot: registry.NewSnapshot(client.GetBaseURL()),
+	}, nil
+}
+
+// Snapshot returns the snapshot recorded so far
+func (r *Recorder) Snapshot() *registry.Snapshot {
+	return r.snapshot
+}
+
+// Save writes the snapshot to path, a directory or a single .json file
+func (r *Recorder) Save(path string) error {
+	return registry.WriteSnapshot(path, r.snapshot)
+}
+
+// AddProvider records a provider: its details, latest version and version list, and
+// for the version of ref (the latest when empty) every doc, the parsed resource and data
+// source schemas and the resource summary. Docs that fail to download are left out and
+// reported in the returned MultiError alongside the entry.
+func (r *Recorder) AddProvider(ctx context.Context, ref registry.ProviderRef) (*registry.SnapshotEntry, error) {
+	ctx = registry.WithRawCapture(ctx)
+	defer r.record(ctx)
+
+	providers := r.client.Providers
+	if _, err := providers.Get(ctx, ref.Namespace, ref.Name); err != nil {
+		return nil, err
+	}
+
+	latest, err := providers.GetLatest(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := providers.ListVersions(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	version := ref.Version
+	if version == "" || version == "latest" {
+		version = latest.Version
+	}
+	entry := &registry.SnapshotEntry{
+		Kind:    KindProvider,
+		Address: registry.ProviderRef{Namespace: ref.Namespace, Name: ref.Name}.String(),
+		Version: version,
+	}
+	for _, v := range list.Included {
+		entry.Versions = append(entry.Versions, v.Attributes.Version)
+	}
+
+	versionID, err := providers.GetVersionID(ctx, ref.Namespace, ref.Name, version)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := providers.GetVersion(ctx, ref.Namespace, ref.Name, version); err != nil {
+		return nil, err
+	}
+	if _, err := providers.ListDocs(ctx, ref.Namespace, ref.Name, version); err != nil {
+		return nil, err
+	}
+	if _, err := providers.GetProviderResourceSummary(ctx, ref.Namespace, ref.Name, version); err != nil {
+		return nil, fmt.Errorf("failed to record resource summary: %w", err)
+	}
+
+	// GetSchema downloads the resource and data source docs, so only the docs of the
+	// other categories are fetched below
+	var errs registry.MultiError
+	var multi *registry.MultiError
+	if _, err := providers.GetSchema(ctx, ref.Namespace, ref.Name, version); errors.As(err, &multi) {
+		for _, e := range multi.Errors {
+			errs.Add(e)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to record schema: %w", err)
+	}
+
+	index, err := providers.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for category, slugs := range index {
+		entry.Docs += len(slugs)
+		if category == "resources" || category == "data-sources" {
+			continue
+		}
+		for _, id := range slugs {
+			ids = append(ids, id)
+		}
+	}
+
+	for _, err := range r.getDocs(ctx, ids) {
+		errs.Add(err)
+	}
+	entry.Docs -= len(errs.Errors)
+
+	r.snapshot.AddEntry(*entry)
+	return entry, errs.ErrorOrNil()
+}
+
+// AddModule records a module: the metadata of the version of ref (the latest when
+// empty), the latest version and the version list
+func (r *Recorder) AddModule(ctx context.Context, ref registry.ModuleRef) (*registry.SnapshotEntry, error) {
+	ctx = registry.WithRawCapture(ctx)
+	defer r.record(ctx)
+
+	modules := r.client.Modules
+	latest, err := modules.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	details := latest
+	if ref.Version != "" && ref.Version != "latest" {
+		details, err = modules.Get(ctx, ref.Namespace, ref.Name, ref.Provider, ref.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	versions, err := modules.ListVersions(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &registry.SnapshotEntry{
+		Kind:     KindModule,
+		Address:  registry.ModuleRef{Namespace: ref.Namespace, Name: ref.Name, Provider: ref.Provider}.String(),
+		Version:  details.Version,
+		Versions: versions,
+	}
+
+	r.snapshot.AddEntry(*entry)
+	return entry, nil
+}
+
+// getDocs downloads docs concurrently, returning the errors of those that failed
+func (r *Recorder) getDocs(ctx context.Context, ids []string) []error {
+	concurrency := r.opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = registry.DefaultBatchConcurrency
+	}
+
+	failures := make([]error, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				failures[i] = ctx.Err()
+				return
+			}
+			if _, err := r.client.Providers.GetDoc(ctx, id); err != nil {
+				failures[i] = fmt.Errorf("doc %s: %w", id, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, err := range failures {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// record adds the successful GET responses captured with ctx to the snapshot
+func (r *Recorder) record(ctx context.Context) {
+	for _, response := range registry.RawResponsesFromContext(ctx) {
+		if response.Method == http.MethodGet && response.StatusCode >= 200 && response.StatusCode < 300 {
+			r.snapshot.Record(response.URL, response.Body)
+		}
+	}
+}
+
+// ExportProvider records a provider with client and writes the snapshot to path
+func ExportProvider(ctx context.Context, client *registry.Client, ref registry.ProviderRef, path string, opts *Options) (*registry.SnapshotEntry, error) {
+	recorder, err := NewRecorder(client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := recorder.AddProvider(ctx, ref)
+	if entry == nil {
+		return nil, err
+	}
+	if saveErr := recorder.Save(path); saveErr != nil {
+		return nil, saveErr
+	}
+	return entry, err
+}
+
+// ExportModule records a module with client and writes the snapshot to path
+func ExportModule(ctx context.Context, client *registry.Client, ref registry.ModuleRef, path string) (*registry.SnapshotEntry, error) {
+	recorder, err := NewRecorder(client, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := recorder.AddModule(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := recorder.Save(path); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
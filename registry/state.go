@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StateFormatVersion is the version of the serialized client state written by ExportState.
+// Readers ignore sections and fields they do not know, so newer blobs remain importable
+// by older clients and vice versa.
+const StateFormatVersion = 1
+
+// Well-known state section names
+const (
+	stateSectionRateLimiter      = "rate_limiter"
+	stateSectionProviderVersions = "provider_versions"
+	stateSectionPolicyContent    = "policy_content"
+	stateSectionResponseCache    = "response_cache"
+)
+
+// ErrInvalidState is returned when a serialized state blob cannot be decoded
+var ErrInvalidState = errors.New("invalid client state")
+
+// stateSection is implemented by client components that can persist their state
+type stateSection interface {
+	exportState() (json.RawMessage, error)
+	importState(data json.RawMessage) error
+}
+
+// clientState is the serialized envelope of the client state
+type clientState struct {
+	FormatVersion int                        `json:"format_version"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	BaseURL       string                     `json:"base_url"`
+	Sections      map[string]json.RawMessage `json:"sections"`
+}
+
+// registryScopedSections lists sections whose data is only valid for the registry it came from
+var registryScopedSections = map[string]bool{
+	stateSectionProviderVersions: true,
+	stateSectionPolicyContent:    true,
+	stateSectionResponseCache:    true,
+}
+
+// ExportState serializes the client's caches, version indexes and rate limiter state
+// into a single blob that can be passed to ImportState in a later process
+func (c *Client) ExportState() ([]byte, error) {
+	state := clientState{
+		FormatVersion: StateFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		BaseURL:       c.GetBaseURL(),
+		Sections:      make(map[string]json.RawMessage, len(c.stateSections)),
+	}
+
+	for name, section := range c.stateSections {
+		data, err := section.exportState()
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s state: %w", name, err)
+		}
+		state.Sections[name] = data
+	}
+
+	return json.Marshal(state)
+}
+
+// ImportState restores state produced by ExportState. Unknown sections are ignored.
+// Cached registry data is only imported when the blob was exported for the same base URL.
+func (c *Client) ImportState(data []byte) error {
+	var state clientState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidState, err)
+	}
+
+	if state.FormatVersion <= 0 {
+		return fmt.Errorf("%w: missing format version", ErrInvalidState)
+	}
+
+	if state.FormatVersion > StateFormatVersion {
+		c.logger.Debugf("Importing client state format %d with reader format %d; unknown data is ignored",
+			state.FormatVersion, StateFormatVersion)
+	}
+
+	sameRegistry := state.BaseURL == c.GetBaseURL()
+
+	for name, raw := range state.Sections {
+		section, ok := c.stateSections[name]
+		if !ok {
+			c.logger.Debugf("Ignoring unknown client state section %q", name)
+			continue
+		}
+
+		if registryScopedSections[name] && !sameRegistry {
+			c.logger.Debugf("Skipping state section %q exported for %s", name, state.BaseURL)
+			continue
+		}
+
+		if err := section.importState(raw); err != nil {
+			return fmt.Errorf("%w: section %s: %v", ErrInvalidState, name, err)
+		}
+	}
+
+	return nil
+}
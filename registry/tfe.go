@@ -0,0 +1,73 @@
+package registry
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultTFEAddress is Terraform Cloud, whose private module registry serves the
+// organization set with WithOrganization unless WithTFE names another address
+const DefaultTFEAddress = "https://app.terraform.io"
+
+// tfeRegistryPath is the path of the module registry protocol on Terraform Cloud and
+// Terraform Enterprise, which replaces the public registry's /v1/
+const tfeRegistryPath = "/api/registry/v1/"
+
+// WithOrganization routes module requests for the namespace org to the private module
+// registry of that Terraform Cloud or Enterprise organization. Other namespaces are
+// still read from the base URL, so private and public modules can be mixed;
+// ModulesService.List without a namespace lists the organization's modules.
+func WithOrganization(org string) ClientOption {
+	return func(c *ClientConfig) {
+		c.Organization = org
+	}
+}
+
+// WithTFE sets the Terraform Enterprise address, e.g. "https://tfe.example.com", and the
+// API token used for the private module registry of WithOrganization. An empty address
+// keeps DefaultTFEAddress; an empty token uses the client's API token.
+func WithTFE(address, token string) ClientOption {
+	return func(c *ClientConfig) {
+		c.TFEAddress = address
+		c.TFEToken = token
+	}
+}
+
+// isPrivateNamespace reports whether a module namespace is the configured organization,
+// whose modules are served by the private registry. Organization names are matched
+// without regard to case, as Terraform Cloud does.
+func (c *Client) isPrivateNamespace(namespace string) bool {
+	return c.config.Organization != "" && strings.EqualFold(namespace, c.config.Organization)
+}
+
+// privateRegistryURL returns the URL of a module registry path on the private registry
+func (c *Client) privateRegistryURL(path string) string {
+	address := c.config.TFEAddress
+	if address == "" {
+		address = DefaultTFEAddress
+	}
+	return strings.TrimSuffix(address, "/") + tfeRegistryPath + path
+}
+
+// getPrivate performs a GET request for a module registry path on the private registry
+// of the configured organization, authenticated with the Terraform Cloud token
+func (c *Client) getPrivate(ctx context.Context, path string, result interface{}) error {
+	return c.getURL(ctx, c.privateRegistryURL(path), c.privateToken(), result)
+}
+
+// privateToken returns the token for the private registry, which defaults to the API token
+func (c *Client) privateToken() string {
+	if c.config.TFEToken != "" {
+		return c.config.TFEToken
+	}
+	return c.apiToken
+}
+
+// get performs a GET request for a module registry path, routed to the private registry
+// when namespace is the configured organization
+func (s *ModulesService) get(ctx context.Context, namespace, path string, result interface{}) error {
+	if s.client.isPrivateNamespace(namespace) {
+		return s.client.getPrivate(ctx, path, result)
+	}
+	return s.client.get(ctx, path, "v1", result)
+}
@@ -0,0 +1,308 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tfvars is a set of module input values generated by GenerateTfvars, rendered as a
+// terraform.tfvars file with HCL or a terraform.tfvars.json file with JSON
+type Tfvars struct {
+	// Module identifies the module the values were generated for
+	Module ModuleRef
+
+	// Variables holds required inputs first, then optional ones, in module order
+	Variables []TfvarsVariable
+}
+
+// TfvarsVariable is one input value of a Tfvars file
+type TfvarsVariable struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+
+	// Value is the override or default value, decoded from JSON (nil, bool,
+	// json.Number, string, []any or map[string]any); nil for placeholders
+	Value any
+
+	// Placeholder reports a required input without an override, written as the
+	// TfvarsPlaceholder string so that Terraform rejects the file until it is filled in
+	Placeholder bool
+}
+
+// TfvarsPlaceholder returns the value written for a required input of a type that has
+// no override, e.g. "<REQUIRED: string>"
+func TfvarsPlaceholder(typ string) string {
+	if typ == "" {
+		return "<REQUIRED>"
+	}
+	return fmt.Sprintf("<REQUIRED: %s>", typ)
+}
+
+// GenerateTfvars generates the input values of a module's root: overrides, the defaults
+// of optional inputs when includeOptional is set, and placeholders for required inputs
+// without an override. Overrides may be any JSON-encodable values and must name inputs
+// of the module. Together with a module block it makes a complete scaffold.
+func GenerateTfvars(module *ModuleDetails, overrides map[string]any, includeOptional bool) (*Tfvars, error) {
+	if module == nil {
+		return nil, &ValidationError{Field: "module", Value: module, Message: "module cannot be nil"}
+	}
+
+	inputs := make(map[string]bool, len(module.Root.Inputs))
+	for _, input := range module.Root.Inputs {
+		inputs[input.Name] = true
+	}
+	var unknown []string
+	for name := range overrides {
+		if !inputs[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &ValidationError{
+			Field:   "overrides",
+			Value:   unknown,
+			Message: fmt.Sprintf("module has no inputs named %s", strings.Join(unknown, ", ")),
+		}
+	}
+
+	tfvars := &Tfvars{
+		Module: ModuleRef{Namespace: module.Namespace, Name: module.Name, Provider: module.Provider, Version: module.Version},
+	}
+
+	var optional []TfvarsVariable
+	for _, input := range module.Root.Inputs {
+		variable := TfvarsVariable{
+			Name:        input.Name,
+			Type:        input.Type,
+			Description: input.Description,
+			Required:    input.Required,
+		}
+
+		override, overridden := overrides[input.Name]
+		switch {
+		case overridden:
+			value, err := normalizeTfvarsValue(override)
+			if err != nil {
+				return nil, &ValidationError{Field: "overrides", Value: input.Name, Message: fmt.Sprintf("value cannot be encoded: %v", err)}
+			}
+			variable.Value = value
+		case input.Required:
+			variable.Placeholder = true
+		case includeOptional:
+			variable.Value = decodeInputDefault(input.Default)
+		default:
+			continue
+		}
+
+		if variable.Required {
+			tfvars.Variables = append(tfvars.Variables, variable)
+		} else {
+			optional = append(optional, variable)
+		}
+	}
+	tfvars.Variables = append(tfvars.Variables, optional...)
+
+	return tfvars, nil
+}
+
+// Missing returns the names of the required inputs written as placeholders
+func (t *Tfvars) Missing() []string {
+	var missing []string
+	for _, variable := range t.Variables {
+		if variable.Placeholder {
+			missing = append(missing, variable.Name)
+		}
+	}
+	return missing
+}
+
+// HCL renders the values as a terraform.tfvars file, with each input's description
+// as a comment
+func (t *Tfvars) HCL() string {
+	var b strings.Builder
+	if t.Module.Namespace != "" {
+		fmt.Fprintf(&b, "# Generated for module %s\n", t.Module)
+	}
+
+	for _, variable := range t.Variables {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		description := strings.TrimSpace(variable.Description)
+		if variable.Required {
+			description = strings.TrimSpace(description + " (required)")
+		}
+		for _, line := range strings.Split(description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				fmt.Fprintf(&b, "# %s\n", line)
+			}
+		}
+
+		value := variable.Value
+		if variable.Placeholder {
+			value = TfvarsPlaceholder(variable.Type)
+		}
+		fmt.Fprintf(&b, "%s = ", variable.Name)
+		writeHCLValue(&b, value, "")
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// JSON renders the values as a terraform.tfvars.json file
+func (t *Tfvars) JSON() ([]byte, error) {
+	values := make(map[string]any, len(t.Variables))
+	for _, variable := range t.Variables {
+		if variable.Placeholder {
+			values[variable.Name] = TfvarsPlaceholder(variable.Type)
+		} else {
+			values[variable.Name] = variable.Value
+		}
+	}
+
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tfvars: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeInputDefault decodes the default of a module input. The registry usually sends
+// defaults as JSON strings holding the JSON encoding of the value, e.g. "\"10.0.0.0/16\""
+// or "[]", so strings that hold valid JSON are decoded once more.
+func decodeInputDefault(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	value, err := decodeTfvarsJSON(raw)
+	if err != nil {
+		return string(raw)
+	}
+	if s, ok := value.(string); ok {
+		if inner, err := decodeTfvarsJSON([]byte(s)); err == nil {
+			return inner
+		}
+	}
+	return value
+}
+
+// normalizeTfvarsValue converts an override to the types decoded from JSON
+func normalizeTfvarsValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTfvarsJSON(data)
+}
+
+// decodeTfvarsJSON decodes a JSON value keeping numbers exact
+func decodeTfvarsJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return value, nil
+}
+
+var hclIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// writeHCLValue writes a value decoded from JSON as an HCL expression; nested lists and
+// objects are indented below indent
+func writeHCLValue(b *strings.Builder, value any, indent string) {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		fmt.Fprintf(b, "%t", v)
+	case json.Number:
+		b.WriteString(v.String())
+	case string:
+		b.WriteString(quoteHCLString(v))
+	case []any:
+		if len(v) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for _, item := range v {
+			b.WriteString(indent + "  ")
+			writeHCLValue(b, item, indent+"  ")
+			b.WriteString(",\n")
+		}
+		b.WriteString(indent + "]")
+	case map[string]any:
+		if len(v) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		names := make([]string, 0, len(v))
+		keys := make(map[string]string, len(v))
+		width := 0
+		for name := range v {
+			key := name
+			if !hclIdentifierPattern.MatchString(key) {
+				key = quoteHCLString(key)
+			}
+			names = append(names, name)
+			keys[name] = key
+			width = max(width, len(key))
+		}
+		sort.Strings(names)
+
+		b.WriteString("{\n")
+		for _, name := range names {
+			fmt.Fprintf(b, "%s  %-*s = ", indent, width, keys[name])
+			writeHCLValue(b, v[name], indent+"  ")
+			b.WriteString("\n")
+		}
+		b.WriteString(indent + "}")
+	default:
+		fmt.Fprintf(b, "%v", v)
+	}
+}
+
+// quoteHCLString quotes a string for HCL, escaping template sequences
+func quoteHCLString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	quoted := strings.ReplaceAll(b.String(), "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
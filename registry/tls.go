@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// WithClientCertificates authenticates the default HTTP client with a client
+// certificate, for example to an egress proxy or registry mirror requiring mutual TLS.
+// certFile and keyFile are PEM files holding the certificate and its private key;
+// caFile, when not empty, is a PEM bundle of CA certificates trusted in addition to the
+// system roots. The certificate is presented to every host, including HTTPS proxies;
+// use WithHostTLSConfig for hosts needing different settings. Like middleware, it is
+// not applied to a client set with WithHTTPClient.
+func WithClientCertificates(certFile, keyFile, caFile string) ClientOption {
+	return func(c *ClientConfig) {
+		c.ClientCertFile = certFile
+		c.ClientKeyFile = keyFile
+		c.CAFile = caFile
+	}
+}
+
+// WithHostTLSConfig sets the TLS configuration of requests to one host of the default
+// HTTP client, replacing the one set with WithClientCertificates. host is a hostname such
+// as "mirror.example.com", or a hostname and port to match only that port. Use
+// LoadTLSConfig to build the configuration from PEM files.
+func WithHostTLSConfig(host string, config *tls.Config) ClientOption {
+	return func(c *ClientConfig) {
+		if c.HostTLSConfigs == nil {
+			c.HostTLSConfigs = make(map[string]*tls.Config)
+		}
+		c.HostTLSConfigs[strings.ToLower(host)] = config
+	}
+}
+
+// WithProxy sends the requests of the default HTTP client through a proxy, such as a
+// corporate egress service, instead of the proxy configured by the HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY environment variables
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *ClientConfig) {
+		c.ProxyURL = proxyURL
+	}
+}
+
+// LoadTLSConfig builds a TLS configuration from PEM files. certFile and keyFile hold a
+// client certificate and its private key and may both be empty; caFile, when not empty,
+// holds CA certificates trusted in addition to the system roots.
+func LoadTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// configureTransport applies the proxy and TLS settings of the configuration to the
+// default transport, returning a transport that selects per-host TLS settings when any
+// are configured
+func configureTransport(transport *http.Transport, config *ClientConfig) (http.RoundTripper, error) {
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.ClientCertFile != "" || config.ClientKeyFile != "" || config.CAFile != "" {
+		tlsConfig, err := LoadTLSConfig(config.ClientCertFile, config.ClientKeyFile, config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if len(config.HostTLSConfigs) == 0 {
+		return transport, nil
+	}
+
+	hosts := &hostTLSTransport{
+		fallback: transport,
+		hosts:    make(map[string]*http.Transport, len(config.HostTLSConfigs)),
+	}
+	for host, tlsConfig := range config.HostTLSConfigs {
+		hostTransport := transport.Clone()
+		hostTransport.TLSClientConfig = tlsConfig.Clone()
+		hosts.hosts[host] = hostTransport
+	}
+	return hosts, nil
+}
+
+// hostTLSTransport routes requests to a transport with the TLS configuration of their
+// host. Each host has its own connection pool, so that connections authenticated with
+// one configuration are never reused for another.
+type hostTLSTransport struct {
+	fallback *http.Transport
+	hosts    map[string]*http.Transport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *hostTLSTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.hosts[strings.ToLower(req.URL.Host)]; ok {
+		return transport.RoundTrip(req)
+	}
+	if transport, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; ok {
+		return transport.RoundTrip(req)
+	}
+	return t.fallback.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of every host
+func (t *hostTLSTransport) CloseIdleConnections() {
+	t.fallback.CloseIdleConnections()
+	for _, transport := range t.hosts {
+		transport.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,267 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxDrainBytes bounds how much of a discarded response body is read to reuse the connection
+const maxDrainBytes = 4096
+
+// Middleware wraps the transport of the default HTTP client. Middleware can observe or
+// modify every request attempt, including retries.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RetryMiddlewareFunc builds the retry layer of the default HTTP client from the client configuration
+type RetryMiddlewareFunc func(config *ClientConfig) Middleware
+
+// WithMiddleware adds middleware to the default HTTP client. The first middleware is the
+// outermost; all of them run inside the retry layer, once per attempt. Middleware is not
+// applied to a client set with WithHTTPClient.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *ClientConfig) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// newDefaultHTTPClient creates the default HTTP client: a pooled transport with the
+// configured proxy and TLS settings, wrapped by the configured middleware and the retry
+// layer
+func newDefaultHTTPClient(config *ClientConfig) (*http.Client, error) {
+	transport, err := configureTransport(newDefaultTransport(), config)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(config.Middleware) - 1; i >= 0; i-- {
+		transport = config.Middleware[i](transport)
+	}
+
+	retry := config.RetryMiddleware
+	if retry == nil {
+		retry = RetryMiddleware
+	}
+
+	return &http.Client{Transport: retry(config)(transport)}, nil
+}
+
+// RetryMiddleware is the built-in retry layer. Each attempt is bounded by the configured
+// timeout; failed attempts are retried up to MaxRetries times as decided by CheckRetry,
+// waiting RetryBackoff between attempts. When retries run out on a response status, the
+// last response is returned so that it is reported as an API error.
+func RetryMiddleware(config *ClientConfig) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &retryTransport{
+			config: config,
+			client: &http.Client{
+				Timeout:   config.Timeout,
+				Transport: next,
+			},
+		}
+	}
+}
+
+// retryTransport retries requests through an inner per-attempt client
+type retryTransport struct {
+	config *ClientConfig
+	client *http.Client
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.config.Logger
+	target := req.URL.Redacted()
+
+	logger.Printf("[DEBUG] %s %s", req.Method, target)
+
+	// Buffer the body so it can be replayed on every attempt
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	var (
+		resp        *http.Response
+		doErr       error
+		checkErr    error
+		shouldRetry bool
+		attempt     int
+	)
+
+	for i := 0; ; i++ {
+		attempt++
+
+		attemptReq := req.Clone(req.Context())
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+			attemptReq.ContentLength = int64(len(body))
+		}
+
+		resp, doErr = t.client.Do(attemptReq)
+		shouldRetry, checkErr = t.config.CheckRetry(req.Context(), resp, doErr)
+
+		if doErr != nil {
+			logger.Printf("[ERR] %s %s request failed: %v", req.Method, target, doErr)
+		}
+
+		if !shouldRetry {
+			break
+		}
+
+		remain := t.config.MaxRetries - i
+		if remain <= 0 {
+			break
+		}
+
+		if doErr == nil {
+			drainBody(resp.Body)
+		}
+
+		wait := t.config.RetryBackoff(i, resp)
+
+		desc := fmt.Sprintf("%s %s", req.Method, target)
+		if resp != nil {
+			desc = fmt.Sprintf("%s (status: %d)", desc, resp.StatusCode)
+		}
+		logger.Printf("[DEBUG] %s: retrying in %s (%d left)", desc, wait, remain)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			t.client.CloseIdleConnections()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if doErr == nil && checkErr == nil {
+		// Out of retries on a retryable status: the last response is returned unread, so
+		// the caller reports it as an API error with its status code
+		if shouldRetry {
+			logger.Printf("[ERR] %s %s giving up after %d attempt(s) (status: %d)", req.Method, target, attempt, resp.StatusCode)
+		}
+		return resp, nil
+	}
+
+	defer t.client.CloseIdleConnections()
+
+	err := checkErr
+	if err == nil {
+		err = doErr
+	}
+
+	if resp != nil {
+		drainBody(resp.Body)
+	}
+
+	// The request error wrapping this one already names the method and URL
+	return nil, fmt.Errorf("giving up after %d attempt(s): %w", attempt, unwrapURLError(err))
+}
+
+// CheckRetry reports whether a request attempt should be retried. Network errors, 429
+// responses and 5xx responses are retried, except maintenance pages; context
+// cancellation stops retrying.
+func (config *ClientConfig) CheckRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil {
+		// Always retry on network errors; cancellation is caught while waiting
+		return true, nil
+	}
+
+	// Maintenance windows outlast any backoff, so the response is returned as it is
+	// and reported as a MaintenanceError
+	if resp.StatusCode == http.StatusServiceUnavailable && isMaintenanceResponse(resp, peekBody(resp, maxMaintenancePeek)) {
+		return false, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return true, nil
+	}
+
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if resp.StatusCode == 0 {
+		return true, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+
+	return false, nil
+}
+
+// RetryBackoff returns how long to wait before retry attemptNum (starting at 0) and
+// notifies the wait handler. Rate limited responses wait until the x-ratelimit-reset
+// time or the Retry-After delay; other failures back off exponentially between
+// RetryWaitMin and RetryWaitMax.
+func (config *ClientConfig) RetryBackoff(attemptNum int, resp *http.Response) time.Duration {
+	wait := config.retryBackoff(attemptNum, resp)
+	config.notifyWait(retryWaitEvent(config, attemptNum, resp, wait))
+	return wait
+}
+
+// retryBackoff computes the delay before a retry
+func (config *ClientConfig) retryBackoff(attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if resetAfter := resp.Header.Get("x-ratelimit-reset"); resetAfter != "" {
+			var resetTime int64
+			if _, err := fmt.Sscanf(resetAfter, "%d", &resetTime); err == nil {
+				waitTime := time.Until(time.Unix(resetTime, 0))
+				config.Logger.Debugf("Rate limited, waiting %v until reset", waitTime)
+				return waitTime
+			}
+		}
+	}
+
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if sleep, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return sleep
+		}
+	}
+
+	min, max := config.RetryWaitMin, config.RetryWaitMax
+	mult := math.Pow(2, float64(attemptNum)) * float64(min)
+	sleep := time.Duration(mult)
+	if float64(sleep) != mult || sleep > max {
+		sleep = max
+	}
+	return sleep
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(header, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryTime, err := time.Parse(time.RFC1123, header)
+	if err != nil {
+		return 0, false
+	}
+	if until := time.Until(retryTime); until > 0 {
+		return until, true
+	}
+	return 0, true
+}
+
+// drainBody reads a bounded amount of a discarded body so the connection can be reused
+func drainBody(body io.ReadCloser) {
+	defer body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, maxDrainBytes))
+}
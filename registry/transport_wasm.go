@@ -0,0 +1,19 @@
+//go:build js || wasip1
+
+package registry
+
+import (
+	"net/http"
+	"time"
+)
+
+// newDefaultTransport returns a transport without a custom dialer, so that on js/wasm
+// requests go through the browser Fetch API
+func newDefaultTransport() *http.Transport {
+	return &http.Transport{
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
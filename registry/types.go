@@ -2,6 +2,7 @@ package registry
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -23,6 +24,23 @@ type Provider struct {
 	Versions    []string  `json:"versions,omitempty"`
 }
 
+// ProviderRef identifies a provider, optionally pinned to a version
+type ProviderRef struct {
+	Namespace string
+	Name      string
+
+	// Version is the provider version; empty or "latest" means the latest version
+	Version string
+}
+
+// String returns the ref in namespace/name[@version] form
+func (r ProviderRef) String() string {
+	if r.Version == "" {
+		return fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+	}
+	return fmt.Sprintf("%s/%s@%s", r.Namespace, r.Name, r.Version)
+}
+
 // ProviderDoc represents a provider documentation item
 type ProviderDoc struct {
 	ID          string `json:"id"`
@@ -173,6 +191,33 @@ type ProviderResourceSummary struct {
 	AllSubcategories []string
 }
 
+// ProviderResourceCounts holds the number of docs per category for a provider version
+type ProviderResourceCounts struct {
+	// ProviderNamespace is the provider namespace (e.g., "hashicorp")
+	ProviderNamespace string
+
+	// ProviderName is the provider name (e.g., "aws")
+	ProviderName string
+
+	// Version is the resolved provider version
+	Version string
+
+	// ProviderVersionID is the registry ID of the provider version
+	ProviderVersionID string
+
+	// Resources is the number of resource docs
+	Resources int
+
+	// DataSources is the number of data source docs
+	DataSources int
+
+	// Functions is the number of provider-defined function docs
+	Functions int
+
+	// Guides is the number of guide docs
+	Guides int
+}
+
 // ResourceInfo represents key information about a single resource or data source
 type ResourceInfo struct {
 	// ID is the unique identifier from the registry
@@ -200,6 +245,24 @@ type ResourceInfo struct {
 	Path string
 }
 
+// ModuleRef identifies a module, optionally pinned to a version
+type ModuleRef struct {
+	Namespace string
+	Name      string
+	Provider  string
+
+	// Version is the module version; empty or "latest" means the latest version
+	Version string
+}
+
+// String returns the ref in namespace/name/provider[@version] form
+func (r ModuleRef) String() string {
+	if r.Version == "" {
+		return fmt.Sprintf("%s/%s/%s", r.Namespace, r.Name, r.Provider)
+	}
+	return fmt.Sprintf("%s/%s/%s@%s", r.Namespace, r.Name, r.Provider, r.Version)
+}
+
 // Module represents a Terraform module
 type Module struct {
 	ID          string    `json:"id"`
@@ -255,6 +318,30 @@ type ModulePart struct {
 	Dependencies         []ModuleDependency         `json:"dependencies,omitempty"`
 	ProviderDependencies []ModuleProviderDependency `json:"provider_dependencies,omitempty"`
 	Resources            []ModuleResource           `json:"resources,omitempty"`
+
+	// ReadmeDependencies holds dependency tables parsed from the README; it is
+	// populated by the client and is nil when the README has none
+	ReadmeDependencies *ReadmeDependencies `json:"readme_dependencies,omitempty"`
+}
+
+// ReadmeDependencies holds the terraform-docs Requirements, Providers and Modules tables of a README
+type ReadmeDependencies struct {
+	Requirements []ReadmeRequirement `json:"requirements,omitempty"`
+	Providers    []ReadmeRequirement `json:"providers,omitempty"`
+	Modules      []ReadmeModule      `json:"modules,omitempty"`
+}
+
+// ReadmeRequirement is a row of a README Requirements or Providers table
+type ReadmeRequirement struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// ReadmeModule is a row of a README Modules table
+type ReadmeModule struct {
+	Name    string `json:"name"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
 }
 
 // ModuleInput represents a module input variable
@@ -3,20 +3,11 @@ package registry
 import (
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
-)
-
-var (
-	// Semantic version regex pattern
-	semverRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([a-zA-Z0-9\-\.]+))?(?:\+([a-zA-Z0-9\-\.]+))?$`)
 
-	// Valid namespace/name pattern
-	validNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-_]*$`)
-
-	// Valid provider name pattern (lowercase with hyphens)
-	validProviderPattern = regexp.MustCompile(`^[a-z][a-z0-9\-]*$`)
+	"github.com/TahirRiaz/terralens-registry-client/registry/semver"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
 )
 
 // ValidateProviderVersion validates a provider version string
@@ -25,7 +16,7 @@ func ValidateProviderVersion(version string) error {
 		return nil
 	}
 
-	if !semverRegex.MatchString(version) {
+	if !validate.Version(version) {
 		return fmt.Errorf("invalid semantic version format: %s", version)
 	}
 
@@ -34,16 +25,12 @@ func ValidateProviderVersion(version string) error {
 
 // ValidateProviderDataType validates a provider data type
 func ValidateProviderDataType(dataType string) error {
-	validTypes := []string{"resources", "data-sources", "functions", "guides", "overview"}
-
-	for _, valid := range validTypes {
-		if dataType == valid {
-			return nil
-		}
+	if validate.DocCategory(dataType) {
+		return nil
 	}
 
 	return fmt.Errorf("invalid provider data type: %s, must be one of: %s",
-		dataType, strings.Join(validTypes, ", "))
+		dataType, strings.Join(validate.DocCategories, ", "))
 }
 
 // IsV2DataType returns true if the data type requires v2 API
@@ -116,12 +103,12 @@ func ExtractProviderInfo(uri string) (namespace, name, version string, err error
 	}
 
 	// Validate extracted values
-	if !validNamePattern.MatchString(namespace) {
+	if !validate.Namespace(namespace) {
 		err = fmt.Errorf("invalid namespace format in URI: %s", namespace)
 		return
 	}
 
-	if !validProviderPattern.MatchString(name) {
+	if !validate.ProviderName(name) {
 		err = fmt.Errorf("invalid provider name format in URI: %s", name)
 		return
 	}
@@ -160,17 +147,17 @@ func ParseModuleID(moduleID string) (namespace, name, provider, version string,
 		return
 	}
 
-	if !validNamePattern.MatchString(namespace) {
+	if !validate.Namespace(namespace) {
 		err = fmt.Errorf("invalid namespace format: %s", namespace)
 		return
 	}
 
-	if !validNamePattern.MatchString(name) {
+	if !validate.ModuleName(name) {
 		err = fmt.Errorf("invalid module name format: %s", name)
 		return
 	}
 
-	if !validProviderPattern.MatchString(provider) {
+	if !validate.ProviderName(provider) {
 		err = fmt.Errorf("invalid provider format: %s", provider)
 		return
 	}
@@ -210,12 +197,12 @@ func ParsePolicyID(policyID string) (namespace, name, version string, err error)
 		return
 	}
 
-	if !validNamePattern.MatchString(namespace) {
+	if !validate.Namespace(namespace) {
 		err = fmt.Errorf("invalid namespace format: %s", namespace)
 		return
 	}
 
-	if !validNamePattern.MatchString(name) {
+	if !validate.PolicyName(name) {
 		err = fmt.Errorf("invalid policy name format: %s", name)
 		return
 	}
@@ -324,71 +311,11 @@ func NormalizeVersion(version string) string {
 	return strings.TrimPrefix(version, "v")
 }
 
-// CompareVersions compares two semantic versions
+// CompareVersions compares two semantic versions with Semantic Versioning 2.0.0
+// precedence, ignoring build metadata. Versions that do not parse order before valid ones.
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 func CompareVersions(v1, v2 string) int {
-	// Normalize versions
-	v1 = NormalizeVersion(v1)
-	v2 = NormalizeVersion(v2)
-
-	// Parse versions
-	v1Parts := parseSemanticVersion(v1)
-	v2Parts := parseSemanticVersion(v2)
-
-	// Compare major, minor, patch
-	for i := 0; i < 3; i++ {
-		if v1Parts[i] < v2Parts[i] {
-			return -1
-		}
-		if v1Parts[i] > v2Parts[i] {
-			return 1
-		}
-	}
-
-	// Compare pre-release versions
-	v1Pre := extractPreRelease(v1)
-	v2Pre := extractPreRelease(v2)
-
-	// No pre-release version is greater than a pre-release version
-	if v1Pre == "" && v2Pre != "" {
-		return 1
-	}
-	if v1Pre != "" && v2Pre == "" {
-		return -1
-	}
-
-	// Compare pre-release versions lexically
-	if v1Pre < v2Pre {
-		return -1
-	}
-	if v1Pre > v2Pre {
-		return 1
-	}
-
-	return 0
-}
-
-// parseSemanticVersion parses a semantic version string into major, minor, patch
-func parseSemanticVersion(version string) [3]int {
-	result := [3]int{0, 0, 0}
-
-	matches := semverRegex.FindStringSubmatch(version)
-	if len(matches) >= 4 {
-		result[0], _ = strconv.Atoi(matches[1])
-		result[1], _ = strconv.Atoi(matches[2])
-		result[2], _ = strconv.Atoi(matches[3])
-	}
-
-	return result
-}
-
-// extractPreRelease extracts the pre-release part of a version
-func extractPreRelease(version string) string {
-	matches := semverRegex.FindStringSubmatch(version)
-	if len(matches) >= 5 {
-		return matches[4]
-	}
-	return ""
+	return semver.Compare(v1, v2)
 }
 
 // truncateString truncates a string to the specified length, adding ellipsis if needed
@@ -489,3 +416,134 @@ func ExtractTerraformExamples(content string) []string {
 
 	return examples
 }
+
+var (
+	readmeHeadingRegex = regexp.MustCompile(`^#+\s+(.+?)\s*#*\s*$`)
+	readmeAnchorRegex  = regexp.MustCompile(`<a\s+name="[^"]*">\s*</a>`)
+	readmeLinkRegex    = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+)
+
+// ParseReadmeDependencies extracts the Requirements, Providers and Modules tables generated
+// by terraform-docs from a README. It returns nil when none of the tables are present.
+func ParseReadmeDependencies(readme string) *ReadmeDependencies {
+	if readme == "" {
+		return nil
+	}
+
+	deps := &ReadmeDependencies{}
+	found := false
+
+	section := ""
+	var header []string
+	inCodeBlock := false
+
+	for _, line := range strings.Split(readme, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		if match := readmeHeadingRegex.FindStringSubmatch(trimmed); match != nil {
+			section = strings.ToLower(match[1])
+			header = nil
+			continue
+		}
+
+		if section != "requirements" && section != "providers" && section != "modules" {
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, "|") {
+			// A table ends at the first non-table line
+			if header != nil {
+				section = ""
+				header = nil
+			}
+			continue
+		}
+
+		cells := splitTableRow(trimmed)
+		if header == nil {
+			header = cells
+			for i := range header {
+				header[i] = strings.ToLower(header[i])
+			}
+			continue
+		}
+		if isTableSeparator(cells) {
+			continue
+		}
+
+		row := make(map[string]string, len(cells))
+		for i, cell := range cells {
+			if i < len(header) {
+				row[header[i]] = cleanTableCell(cell)
+			}
+		}
+		if row["name"] == "" {
+			continue
+		}
+
+		found = true
+		switch section {
+		case "requirements":
+			deps.Requirements = append(deps.Requirements, ReadmeRequirement{Name: row["name"], Version: row["version"]})
+		case "providers":
+			deps.Providers = append(deps.Providers, ReadmeRequirement{Name: row["name"], Version: row["version"]})
+		case "modules":
+			deps.Modules = append(deps.Modules, ReadmeModule{Name: row["name"], Source: row["source"], Version: row["version"]})
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return deps
+}
+
+// splitTableRow splits a markdown table row into trimmed cells, honoring escaped pipes
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	row = strings.TrimSuffix(row, "|")
+
+	const escapedPipe = "\x00"
+	row = strings.ReplaceAll(row, `\|`, escapedPipe)
+
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cell, escapedPipe, "|"))
+	}
+
+	return cells
+}
+
+// isTableSeparator reports whether cells form a markdown table separator row
+func isTableSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if strings.Trim(cell, "-: ") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanTableCell strips terraform-docs anchors, links and formatting from a table cell
+func cleanTableCell(cell string) string {
+	cell = readmeAnchorRegex.ReplaceAllString(cell, "")
+	cell = readmeLinkRegex.ReplaceAllString(cell, "$1")
+	cell = strings.ReplaceAll(cell, `\_`, "_")
+	cell = strings.Trim(strings.TrimSpace(cell), "`")
+
+	if cell == "n/a" {
+		return ""
+	}
+
+	return cell
+}
@@ -0,0 +1,90 @@
+// Package validate holds the naming and version rules shared by the registry client's
+// modules, providers and policies services and by its ID and URI parsers, so that an
+// address accepted by one is accepted by all. Patterns are compiled once when the
+// package is initialized; every function is safe for concurrent use.
+package validate
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/semver"
+)
+
+var (
+	// SemverPattern matches a semantic version with an optional "v" prefix, capturing
+	// major, minor, patch, pre-release and build metadata. It is shared; do not call
+	// Longest on it.
+	//
+	// Deprecated: use semver.Parse, which also rejects empty pre-release identifiers.
+	SemverPattern = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-([a-zA-Z0-9\-\.]+))?(?:\+([a-zA-Z0-9\-\.]+))?$`)
+
+	// namePattern matches namespaces and module and policy names
+	namePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-_]*$`)
+
+	// providerPattern matches provider names and module provider segments
+	providerPattern = regexp.MustCompile(`^[a-z][a-z0-9\-]*$`)
+)
+
+// Allowed values of enumerated fields
+var (
+	// Tiers are the provider tiers
+	Tiers = []string{"official", "partner", "community"}
+
+	// DocCategories are the provider doc categories
+	DocCategories = []string{"resources", "data-sources", "functions", "guides", "overview"}
+
+	// Languages are the provider doc languages
+	Languages = []string{"hcl", "terraform", "json"}
+
+	// EnforcementLevels are the Sentinel policy enforcement levels
+	EnforcementLevels = []string{"advisory", "soft-mandatory", "hard-mandatory"}
+)
+
+// Namespace reports whether s is a valid namespace: letters, digits, hyphens and
+// underscores, starting with a letter or digit
+func Namespace(s string) bool {
+	return namePattern.MatchString(s)
+}
+
+// ModuleName reports whether s is a valid module name; the rules are the namespace's
+func ModuleName(s string) bool {
+	return namePattern.MatchString(s)
+}
+
+// PolicyName reports whether s is a valid policy name; the rules are the namespace's
+func PolicyName(s string) bool {
+	return namePattern.MatchString(s)
+}
+
+// ProviderName reports whether s is a valid provider name, or module provider segment:
+// lowercase letters, digits and hyphens, starting with a letter
+func ProviderName(s string) bool {
+	return providerPattern.MatchString(s)
+}
+
+// Version reports whether s is a semantic version such as "1.2.3", "v1.2.3-beta.1" or
+// "1.2.3+build"
+func Version(s string) bool {
+	return semver.IsValid(s)
+}
+
+// Tier reports whether s is one of Tiers
+func Tier(s string) bool {
+	return slices.Contains(Tiers, s)
+}
+
+// DocCategory reports whether s is one of DocCategories
+func DocCategory(s string) bool {
+	return slices.Contains(DocCategories, s)
+}
+
+// Language reports whether s is one of Languages
+func Language(s string) bool {
+	return slices.Contains(Languages, s)
+}
+
+// EnforcementLevel reports whether s is one of EnforcementLevels
+func EnforcementLevel(s string) bool {
+	return slices.Contains(EnforcementLevels, s)
+}
@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// providerVersionIndex remembers provider IDs and version IDs seen in version listings.
+// Version IDs never change once published, so entries stay valid; unknown versions
+// still fall through to the API.
+type providerVersionIndex struct {
+	mu        sync.RWMutex
+	providers map[string]providerVersionEntry
+}
+
+// providerVersionEntry is the indexed version data for one provider
+type providerVersionEntry struct {
+	ProviderID string            `json:"provider_id"`
+	Versions   map[string]string `json:"versions"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// record stores the versions of a provider listing
+func (idx *providerVersionIndex) record(namespace, name string, list *ProviderVersionList) {
+	if list == nil {
+		return
+	}
+
+	entry := providerVersionEntry{
+		ProviderID: list.Data.ID,
+		Versions:   make(map[string]string, len(list.Included)),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	for _, v := range list.Included {
+		if v.Attributes.Version != "" && v.ID != "" {
+			entry.Versions[v.Attributes.Version] = v.ID
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.providers == nil {
+		idx.providers = make(map[string]providerVersionEntry)
+	}
+	idx.providers[namespace+"/"+name] = entry
+}
+
+// versionID returns the indexed ID of a provider version
+func (idx *providerVersionIndex) versionID(namespace, name, version string) (string, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	id, ok := idx.providers[namespace+"/"+name].Versions[version]
+	return id, ok
+}
+
+// exportState implements stateSection
+func (idx *providerVersionIndex) exportState() (json.RawMessage, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return json.Marshal(idx.providers)
+}
+
+// importState implements stateSection
+func (idx *providerVersionIndex) importState(data json.RawMessage) error {
+	var providers map[string]providerVersionEntry
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.providers == nil {
+		idx.providers = make(map[string]providerVersionEntry, len(providers))
+	}
+	for key, entry := range providers {
+		// Keep fresher in-memory data over imported entries
+		if existing, ok := idx.providers[key]; ok && existing.UpdatedAt.After(entry.UpdatedAt) {
+			continue
+		}
+		idx.providers[key] = entry
+	}
+
+	return nil
+}
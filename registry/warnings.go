@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"context"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+)
+
+// WarningCode identifies the kind of data-quality issue reported by a Warning
+type WarningCode string
+
+const (
+	// WarningSkippedDoc is reported when a documentation page could not be fetched and was left out
+	WarningSkippedDoc WarningCode = "skipped_doc"
+
+	// WarningUnknownSubcategory is reported when a subcategory is not one of the Subcategory*
+	// constants and is matched as given
+	WarningUnknownSubcategory WarningCode = "unknown_subcategory"
+
+	// WarningSkippedPolicyItem is reported when a policy or policy module with missing data is left out
+	WarningSkippedPolicyItem WarningCode = "skipped_policy_item"
+
+	// WarningSkippedPolicyContent is reported when policy content could not be fetched for a search
+	WarningSkippedPolicyContent WarningCode = "skipped_policy_content"
+
+	// WarningIntegrityID is reported by integrity checks when a response item has an empty ID
+	WarningIntegrityID WarningCode = "integrity_id"
+
+	// WarningIntegrityVersion is reported by integrity checks for unparseable, duplicate or
+	// unsorted versions
+	WarningIntegrityVersion WarningCode = "integrity_version"
+
+	// WarningLatestFallback is reported when the versions of a module could not be listed
+	// and GetLatest resolved the latest version from the module endpoint or search instead
+	WarningLatestFallback WarningCode = "latest_fallback"
+
+	// WarningIntegrityPagination is reported by integrity checks when pagination metadata
+	// disagrees with the request or the returned items
+	WarningIntegrityPagination WarningCode = "integrity_pagination"
+)
+
+// Warning describes a data-quality issue found by an operation that carried on leniently
+type Warning struct {
+	// Code identifies the kind of issue
+	Code WarningCode
+
+	// Resource identifies the affected item, such as a doc or policy ID
+	Resource string
+
+	// Message is a human-readable description
+	Message string
+
+	// Err is the underlying error, if any
+	Err error
+}
+
+// Warnings collects the warnings reported during calls made with its context
+type Warnings struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// List returns the collected warnings in the order they were reported
+func (w *Warnings) List() []Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]Warning(nil), w.warnings...)
+}
+
+// Len returns the number of collected warnings
+func (w *Warnings) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.warnings)
+}
+
+// add appends a warning
+func (w *Warnings) add(warning Warning) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.warnings = append(w.warnings, warning)
+}
+
+var warningHandlerKey = registryctx.NewKey[func(Warning)]("warning-handler")
+
+// WithWarningHandler returns a context that delivers warnings reported by calls made with it
+// to fn. fn may be called concurrently. Handlers set on parent contexts are called as well.
+func WithWarningHandler(ctx context.Context, fn func(Warning)) context.Context {
+	parent, _ := warningHandlerKey.Get(ctx)
+	if parent != nil {
+		next := fn
+		fn = func(warning Warning) {
+			next(warning)
+			parent(warning)
+		}
+	}
+	return warningHandlerKey.With(ctx, fn)
+}
+
+// WithWarnings returns a context that collects warnings reported by calls made with it
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	warnings := &Warnings{}
+	return WithWarningHandler(ctx, warnings.add), warnings
+}
+
+// warn delivers a warning to the handlers on the context
+func warn(ctx context.Context, warning Warning) {
+	if fn, ok := warningHandlerKey.Get(ctx); ok {
+		fn(warning)
+	}
+}
@@ -0,0 +1,129 @@
+// Package assert provides assertion helpers for integration tests written against the
+// registry client. Assertions return an error describing the mismatch instead of failing
+// a test directly, so they work with both the testing package and custom harnesses.
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AssertEqual checks if two values are equal. Comparable values are compared with ==;
+// slices, maps and other non-comparable values are compared deeply. Mismatched
+// composite values are reported with a line diff.
+func AssertEqual(expected, actual interface{}) error {
+	if equal(expected, actual) {
+		return nil
+	}
+
+	if diff := Diff(expected, actual); diff != "" {
+		return fmt.Errorf("values differ (-expected +actual):\n%s", diff)
+	}
+	return fmt.Errorf("expected %v, got %v", expected, actual)
+}
+
+// AssertNotNil checks if a value is not nil
+func AssertNotNil(value interface{}) error {
+	if value == nil {
+		return fmt.Errorf("expected non-nil value, got nil")
+	}
+	return nil
+}
+
+// AssertNil checks if a value is nil
+func AssertNil(value interface{}) error {
+	if value != nil {
+		return fmt.Errorf("expected nil, got %v", value)
+	}
+	return nil
+}
+
+// AssertTrue checks if a condition is true
+func AssertTrue(condition bool, message string) error {
+	if !condition {
+		return fmt.Errorf("assertion failed: %s", message)
+	}
+	return nil
+}
+
+// AssertNoError checks if there is no error
+func AssertNoError(err error) error {
+	if err != nil {
+		return fmt.Errorf("expected no error, got: %v", err)
+	}
+	return nil
+}
+
+// AssertError checks if there is an error
+func AssertError(err error) error {
+	if err == nil {
+		return fmt.Errorf("expected error, got nil")
+	}
+	return nil
+}
+
+// AssertContains checks if a string contains a substring
+func AssertContains(haystack, needle string) error {
+	if !strings.Contains(haystack, needle) {
+		return fmt.Errorf("expected '%s' to contain '%s'", haystack, needle)
+	}
+	return nil
+}
+
+// AssertGreaterThan checks if a > b
+func AssertGreaterThan(a, b int) error {
+	if a <= b {
+		return fmt.Errorf("expected %d > %d", a, b)
+	}
+	return nil
+}
+
+// AssertLessThan checks if a < b
+func AssertLessThan(a, b int) error {
+	if a >= b {
+		return fmt.Errorf("expected %d < %d", a, b)
+	}
+	return nil
+}
+
+// equal compares values with == when both are comparable and deeply otherwise
+func equal(expected, actual interface{}) bool {
+	if expected == nil || actual == nil {
+		return expected == actual
+	}
+
+	et, at := reflect.TypeOf(expected), reflect.TypeOf(actual)
+	if et != at {
+		return false
+	}
+	if et.Comparable() && isShallowComparable(reflect.ValueOf(expected)) {
+		return expected == actual
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// isShallowComparable reports whether == on v cannot panic, i.e. no interface field
+// holds a non-comparable dynamic value
+func isShallowComparable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		return v.Elem().Type().Comparable() && isShallowComparable(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isShallowComparable(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isShallowComparable(v.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
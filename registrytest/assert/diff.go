@@ -0,0 +1,77 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Diff returns a line diff between the indented JSON forms of expected and actual, with
+// removed lines prefixed by "-" and added lines by "+". It returns an empty string for
+// scalar values, which are clearer when printed directly.
+func Diff(expected, actual interface{}) string {
+	if !isComposite(expected) && !isComposite(actual) {
+		return ""
+	}
+
+	a := strings.Split(render(expected), "\n")
+	b := strings.Split(render(actual), "\n")
+
+	// Longest common subsequence table over lines
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var builder strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			builder.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			builder.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			builder.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// render formats a value as indented JSON, falling back to %+v
+func render(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+	return string(data)
+}
+
+// isComposite reports whether value is a struct, map, slice or array (or a pointer to one)
+func isComposite(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
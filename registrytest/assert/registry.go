@@ -0,0 +1,100 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+)
+
+// AssertSemverSorted checks that versions are sorted by semantic version, newest first
+// when descending is true
+func AssertSemverSorted(versions []string, descending bool) error {
+	for i := 1; i < len(versions); i++ {
+		cmp := registry.CompareVersions(versions[i-1], versions[i])
+		if descending {
+			cmp = -cmp
+		}
+		if cmp > 0 {
+			order := "ascending"
+			if descending {
+				order = "descending"
+			}
+			return fmt.Errorf("versions not in %s semver order at index %d: %s before %s",
+				order, i, versions[i-1], versions[i])
+		}
+	}
+	return nil
+}
+
+// AssertPaginationConsistent checks that pagination metadata is internally consistent
+// and agrees with the number of items returned on the page
+func AssertPaginationConsistent(p registry.Pagination, itemCount int) error {
+	var problems []string
+
+	if p.CurrentPage < 1 {
+		problems = append(problems, fmt.Sprintf("current-page %d is less than 1", p.CurrentPage))
+	}
+	if p.PageSize > 0 && itemCount > p.PageSize {
+		problems = append(problems, fmt.Sprintf("%d items exceed page-size %d", itemCount, p.PageSize))
+	}
+	if p.TotalPages > 0 && p.CurrentPage > p.TotalPages {
+		problems = append(problems, fmt.Sprintf("current-page %d exceeds total-pages %d", p.CurrentPage, p.TotalPages))
+	}
+
+	if p.NextPage != 0 && p.NextPage != p.CurrentPage+1 {
+		problems = append(problems, fmt.Sprintf("next-page %d does not follow current-page %d", p.NextPage, p.CurrentPage))
+	}
+	if p.TotalPages > 0 && p.CurrentPage < p.TotalPages && p.NextPage == 0 {
+		problems = append(problems, fmt.Sprintf("missing next-page on page %d of %d", p.CurrentPage, p.TotalPages))
+	}
+	if p.TotalPages > 0 && p.CurrentPage >= p.TotalPages && p.NextPage != 0 {
+		problems = append(problems, fmt.Sprintf("next-page %d set on last page %d", p.NextPage, p.CurrentPage))
+	}
+	if p.PrevPage != 0 && p.PrevPage != p.CurrentPage-1 {
+		problems = append(problems, fmt.Sprintf("prev-page %d does not precede current-page %d", p.PrevPage, p.CurrentPage))
+	}
+
+	if p.PageSize > 0 && p.TotalCount > 0 && p.TotalPages > 0 {
+		expectedPages := (p.TotalCount + p.PageSize - 1) / p.PageSize
+		if expectedPages != p.TotalPages {
+			problems = append(problems, fmt.Sprintf("total-count %d with page-size %d implies %d pages, got total-pages %d",
+				p.TotalCount, p.PageSize, expectedPages, p.TotalPages))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("inconsistent pagination: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// AssertModuleRefEqual checks that two module refs identify the same module version.
+// Namespace, name and provider compare case-insensitively, as the registry does, and
+// a leading "v" on versions is ignored.
+func AssertModuleRefEqual(expected, actual registry.ModuleRef) error {
+	var diffs []string
+
+	fields := []struct {
+		name             string
+		expected, actual string
+	}{
+		{"Namespace", expected.Namespace, actual.Namespace},
+		{"Name", expected.Name, actual.Name},
+		{"Provider", expected.Provider, actual.Provider},
+	}
+	for _, f := range fields {
+		if !strings.EqualFold(f.expected, f.actual) {
+			diffs = append(diffs, fmt.Sprintf("  %s: -%q +%q", f.name, f.expected, f.actual))
+		}
+	}
+
+	if registry.NormalizeVersion(expected.Version) != registry.NormalizeVersion(actual.Version) {
+		diffs = append(diffs, fmt.Sprintf("  Version: -%q +%q", expected.Version, actual.Version))
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("module refs differ (-expected +actual):\n%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
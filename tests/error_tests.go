@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/TahirRiaz/terralens-registry-client/registry"
@@ -33,8 +36,11 @@ func (s *ErrorTests) setupTests() {
 	s.AddTest("Error Type Checking", "Test error type helper functions", s.testErrorTypeChecking)
 	s.AddTest("Context Cancellation", "Test context cancellation handling", s.testContextCancellation)
 	s.AddTest("Timeout Handling", "Test request timeout handling", s.testTimeoutHandling)
+	s.AddTest("Typed Timeouts", "Test deadline and network timeouts matching IsTimeout", s.testTypedTimeouts)
 	s.AddTest("API Error Structure", "Test API error response parsing", s.testAPIErrorStructure)
 	s.AddTest("Multi Error", "Test multiple error aggregation", s.testMultiError)
+	s.AddTest("Response Size Limit", "Test the response size guard with buffered and streaming decoding", s.testResponseSizeLimit)
+	s.AddTest("Maintenance Window", "Test maintenance pages reported as ErrMaintenance without retries", s.testMaintenanceWindow)
 }
 
 func (s *ErrorTests) testNotFoundErrors(ctx context.Context) error {
@@ -341,3 +347,211 @@ func (s *ErrorTests) testMultiError(ctx context.Context) error {
 	s.logger.Debugf("MultiError handling works correctly with %d errors", len(multiErr.Errors))
 	return nil
 }
+
+func (s *ErrorTests) testTypedTimeouts(ctx context.Context) error {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Context deadlines keep matching context.DeadlineExceeded as well
+	deadlineCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Modules.Search(deadlineCtx, "slow", 0)
+	if !registry.IsTimeout(err) {
+		return fmt.Errorf("expected timeout error for context deadline, got: %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("expected context.DeadlineExceeded in the chain, got: %v", err)
+	}
+
+	// HTTP client timeouts are network timeouts
+	httpClient := &http.Client{Timeout: 50 * time.Millisecond}
+	client, err = registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithHTTPClient(httpClient))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	_, err = client.Modules.Search(ctx, "slow", 0)
+	if !registry.IsTimeout(err) {
+		return fmt.Errorf("expected timeout error for HTTP client timeout, got: %v", err)
+	}
+
+	// Cancellation is not a timeout
+	cancelCtx, cancelNow := context.WithCancel(ctx)
+	cancelNow()
+
+	_, err = client.Modules.Search(cancelCtx, "slow", 0)
+	if err == nil || registry.IsTimeout(err) {
+		return fmt.Errorf("expected non-timeout error for cancelled context, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ErrorTests) testMaintenanceWindow(ctx context.Context) error {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/v1/modules/search":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "<!DOCTYPE html><html><head><title>Scheduled Maintenance</title></head><body>Back soon</body></html>")
+		case "/v1/modules/hashicorp/consul/aws/versions":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"errors": ["The registry is down for maintenance"]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		func(c *registry.ClientConfig) {
+			c.MaxRetries = 3
+			c.RetryWaitMin = time.Millisecond
+			c.RetryWaitMax = time.Millisecond
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// The maintenance page is reported at once, with its title and retry time
+	_, err = client.Modules.Search(ctx, "vpc", 0)
+	if !registry.IsMaintenance(err) {
+		return fmt.Errorf("expected maintenance error, got: %v", err)
+	}
+	var maintenance *registry.MaintenanceError
+	if !errors.As(err, &maintenance) {
+		return fmt.Errorf("expected a MaintenanceError, got: %T", err)
+	}
+	if err := AssertEqual("Scheduled Maintenance", maintenance.Message); err != nil {
+		return err
+	}
+	if err := AssertEqual(120*time.Second, maintenance.RetryAfter); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("maintenance response was retried: %w", err)
+	}
+
+	// Requests during the announced window fail fast without reaching the registry
+	if _, err := client.Modules.Search(ctx, "subnet", 0); !registry.IsMaintenance(err) {
+		return fmt.Errorf("expected maintenance error during the window, got: %v", err)
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("request sent during the maintenance window: %w", err)
+	}
+
+	// Bodies mentioning maintenance are recognized without Retry-After; with no known
+	// end, the next request goes to the registry again
+	other, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), func(c *registry.ClientConfig) { c.MaxRetries = 0 })
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	for i := 0; i < 2; i++ {
+		_, err = other.Modules.ListVersions(ctx, "hashicorp", "consul", "aws")
+		if !registry.IsMaintenance(err) || !registry.IsServerError(err) {
+			return fmt.Errorf("expected maintenance server error, got: %v", err)
+		}
+	}
+	if err := AssertEqual(int32(3), requests.Load()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ErrorTests) testResponseSizeLimit(ctx context.Context) error {
+	module := `{"id": "example/net/aws/1.0.0", "namespace": "example", "name": "net", "provider": "aws", "version": "1.0.0"}`
+	large := `{"meta": {"limit": 100}, "modules": [` + strings.Repeat(module+",", 50) + module + `]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/example/net/aws/1.0.0":
+			fmt.Fprint(w, module)
+		case "/v1/modules":
+			// Without a Content-Length the limit applies while reading
+			if r.URL.Query().Get("provider") == "chunked" {
+				fmt.Fprint(w, large[:100])
+				w.(http.Flusher).Flush()
+				fmt.Fprint(w, large[100:])
+				return
+			}
+			w.Header().Set("Content-Length", fmt.Sprint(len(large)))
+			fmt.Fprint(w, large)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for _, streaming := range []bool{false, true} {
+		options := []registry.ClientOption{
+			registry.WithBaseURL(server.URL),
+			registry.WithMaxResponseSize(1024),
+			registry.WithLogger(s.logger),
+		}
+		if streaming {
+			options = append(options, registry.WithStreamingDecode())
+		}
+		client, err := registry.NewClient(options...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		details, err := client.Modules.Get(ctx, "example", "net", "aws", "1.0.0")
+		if err != nil {
+			return fmt.Errorf("streaming %t: failed to get module: %w", streaming, err)
+		}
+		if err := AssertEqual("example/net/aws/1.0.0", details.ID); err != nil {
+			return err
+		}
+
+		for _, provider := range []string{"aws", "chunked"} {
+			_, err := client.Modules.List(ctx, &registry.ModuleListOptions{Provider: provider})
+			if !registry.IsResponseTooLarge(err) {
+				return fmt.Errorf("streaming %t, provider %s: expected response too large, got %v", streaming, provider, err)
+			}
+		}
+	}
+
+	// Raw capture reads the body in full even with streaming decoding
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithStreamingDecode(), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	captured := registry.WithRawCapture(ctx)
+	list, err := client.Modules.List(captured, &registry.ModuleListOptions{Provider: "chunked"})
+	if err != nil {
+		return fmt.Errorf("failed to list modules: %w", err)
+	}
+	if err := AssertEqual(51, len(list.Modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual(large, string(registry.RawFromContext(captured))); err != nil {
+		return err
+	}
+
+	if _, err := registry.NewClient(registry.WithMaxResponseSize(-1)); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected invalid configuration for a negative limit, got %v", err)
+	}
+
+	return nil
+}
@@ -1,11 +1,35 @@
 package tests
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/TahirRiaz/terralens-registry-client/migrate"
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registry/corpus"
+	"github.com/TahirRiaz/terralens-registry-client/registry/cost"
+	"github.com/TahirRiaz/terralens-registry-client/registry/gate"
+	"github.com/TahirRiaz/terralens-registry-client/registry/quality"
+	"github.com/TahirRiaz/terralens-registry-client/registry/readmemeta"
+	"github.com/TahirRiaz/terralens-registry-client/registry/snapshot"
 
 	"github.com/sirupsen/logrus"
 )
@@ -38,6 +62,31 @@ func (s *ModuleTests) setupTests() {
 	s.AddTest("Filter by Provider", "Test filtering modules by provider", s.testFilterByProvider)
 	s.AddTest("Verified Modules", "Test filtering verified modules", s.testVerifiedModules)
 	s.AddTest("Invalid Module", "Test error handling for invalid modules", s.testInvalidModule)
+	s.AddTest("Has Changed", "Test cheap change detection against the latest version", s.testHasChanged)
+	s.AddTest("Parse README Dependencies", "Test parsing terraform-docs dependency tables", s.testParseReadmeDependencies)
+	s.AddTest("Estimate Module Cost", "Test cost report traversal and aggregation", s.testEstimateModuleCost)
+	s.AddTest("Export Module", "Test exporting module metadata, READMEs and examples", s.testExportModule)
+	s.AddTest("Audit Trail", "Test incremental fetching of registry audit trail events", s.testAuditTrail)
+	s.AddTest("Resource Coverage", "Test comparing module resources with a provider subcategory", s.testResourceCoverage)
+	s.AddTest("Download Leaderboard", "Test top modules and namespace leaderboards with capped pagination", s.testDownloadLeaderboard)
+	s.AddTest("Integrity Checks", "Test response invariant checks reported as warnings", s.testIntegrityChecks)
+	s.AddTest("Namespace Migration", "Test planning namespace migrations of Terraform code", s.testNamespaceMigration)
+	s.AddTest("Module Archive", "Test downloading, verifying and extracting module archives", s.testModuleArchive)
+	s.AddTest("Download Policy", "Test namespace and tier policy enforcement on downloads", s.testDownloadPolicy)
+	s.AddTest("Examples Corpus", "Test building a deduplicated examples corpus from modules and provider docs", s.testExamplesCorpus)
+	s.AddTest("Pin Drift", "Test detecting missing, security-patched and moved module pins", s.testPinDrift)
+	s.AddTest("Dependency Graph", "Test resolving transitive module dependencies into a graph", s.testDependencyGraph)
+	s.AddTest("README Metadata", "Test extracting badges, Terraform version and maintainers from READMEs", s.testReadmeMetadata)
+	s.AddTest("Module Ownership", "Test extracting code owners from the source repository", s.testModuleOwnership)
+	s.AddTest("Module Version Diff", "Test diffing module inputs, outputs, resources and providers", s.testModuleVersionDiff)
+	s.AddTest("Ingestion Gate", "Test policy rules for catalog ingestion and YAML loading", s.testIngestionGate)
+	s.AddTest("Generate Tfvars", "Test generating tfvars with defaults, overrides and placeholders", s.testGenerateTfvars)
+	s.AddTest("Get Latest Fallback", "Test resolving the latest version when no versions are listed", s.testGetLatestFallback)
+	s.AddTest("Offline Snapshot", "Test serving module reads from a recorded snapshot", s.testOfflineSnapshot)
+	s.AddTest("Private Module Registry", "Test routing organization modules to the Terraform Cloud registry", s.testPrivateModuleRegistry)
+	s.AddTest("OpenTofu Modules", "Test reading and searching modules on the OpenTofu registry", s.testOpenTofuModules)
+	s.AddTest("Get Many Modules", "Test concurrent batch module retrieval with request coalescing", s.testGetManyModules)
+	s.AddTest("Lint Module Docs", "Test documentation completeness findings", s.testLintModuleDocs)
 }
 
 func (s *ModuleTests) testListModules(ctx context.Context) error {
@@ -618,3 +667,2263 @@ func (s *ModuleTests) testInvalidModule(ctx context.Context) error {
 	s.logger.Debug("Invalid module handling works correctly")
 	return nil
 }
+
+func (s *ModuleTests) testDownloadLeaderboard(ctx context.Context) error {
+	pages := []string{
+		`{"meta": {"limit": 100, "current_offset": 0, "next_offset": 100}, "modules": [
+			{"namespace": "acme", "name": "vpc", "provider": "aws", "downloads": 500, "verified": true},
+			{"namespace": "acme", "name": "bucket", "provider": "aws", "downloads": 300},
+			{"namespace": "other", "name": "vpc", "provider": "aws", "downloads": 900}
+		]}`,
+		`{"meta": {"limit": 100, "current_offset": 100, "next_offset": 200}, "modules": [
+			{"namespace": "small", "name": "dns", "provider": "aws", "downloads": 100}
+		]}`,
+		`{"meta": {"limit": 100, "current_offset": 200, "next_offset": 300}, "modules": [
+			{"namespace": "late", "name": "huge", "provider": "aws", "downloads": 99999}
+		]}`,
+	}
+	monthly := map[string]int{"acme/vpc/aws": 50, "acme/bucket/aws": 80, "other/vpc/aws": 10, "small/dns/aws": 5}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/modules" {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			fmt.Fprint(w, pages[offset/100])
+			return
+		}
+		if r.URL.Path == "/v2/modules/acme/vpc/aws" {
+			fmt.Fprint(w, `{"data": {"type": "modules", "id": "1"}, "included": [
+				{"type": "module-versions", "id": "2", "attributes": {"version": "1.9.0", "downloads": 40}},
+				{"type": "module-versions", "id": "3", "attributes": {"version": "1.10.0", "downloads": 460}}
+			]}`)
+			return
+		}
+		key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/modules/"), "/downloads/summary")
+		month, ok := monthly[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"attributes": {"week": 1, "month": %d, "year": 1000, "total": 2000}}}`, month)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// The third page is beyond the scan cap, so its module is not ranked
+	top, err := client.Modules.TopByDownloads(ctx, &registry.LeaderboardOptions{Limit: 2, MaxPages: 2})
+	if err != nil {
+		return fmt.Errorf("TopByDownloads failed: %w", err)
+	}
+	if err := AssertEqual(2, len(top)); err != nil {
+		return err
+	}
+	if err := AssertEqual("other/vpc/aws", top[0].Module.Namespace+"/"+top[0].Module.Name+"/"+top[0].Module.Provider); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, top[1].Rank); err != nil {
+		return err
+	}
+
+	monthTop, err := client.Modules.TopByDownloads(ctx, &registry.LeaderboardOptions{Limit: 1, MaxPages: 2, Window: registry.DownloadWindowMonth})
+	if err != nil {
+		return fmt.Errorf("TopByDownloads by month failed: %w", err)
+	}
+	if err := AssertEqual("bucket", monthTop[0].Module.Name); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(80), monthTop[0].Downloads); err != nil {
+		return err
+	}
+
+	namespaces, err := client.Modules.NamespaceLeaderboard(ctx, &registry.LeaderboardOptions{MaxPages: 2})
+	if err != nil {
+		return fmt.Errorf("NamespaceLeaderboard failed: %w", err)
+	}
+	if err := AssertEqual(3, len(namespaces)); err != nil {
+		return err
+	}
+	if err := AssertEqual("other", namespaces[0].Namespace); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(800), namespaces[1].Downloads); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, namespaces[1].Verified); err != nil {
+		return err
+	}
+
+	_, err = client.Modules.NamespaceLeaderboard(ctx, &registry.LeaderboardOptions{Window: registry.DownloadWindowWeek})
+	if !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for a windowed namespace leaderboard, got %v", err)
+	}
+
+	metrics, err := client.Modules.GetMetrics(ctx, "acme", "vpc", "aws")
+	if err != nil {
+		return fmt.Errorf("GetMetrics failed: %w", err)
+	}
+	if err := AssertEqual(int64(50), metrics.Downloads.Month); err != nil {
+		return err
+	}
+	if err := AssertTrue(!metrics.FetchedAt.IsZero(), "metrics should be timestamped"); err != nil {
+		return err
+	}
+
+	versions, err := client.Modules.GetVersionDownloads(ctx, "acme", "vpc", "aws")
+	if err != nil {
+		return fmt.Errorf("GetVersionDownloads failed: %w", err)
+	}
+	if err := AssertEqual(2, len(versions)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.10.0", versions[0].Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(40), versions[1].Downloads); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testIntegrityChecks(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules":
+			// Offset, limit and next offset disagree with the request and each other
+			fmt.Fprint(w, `{"meta": {"limit": 1, "current_offset": 0, "next_offset": 0},
+				"modules": [{"id": "example/net/aws/1.0.0", "version": "1.0.0"}, {"id": "", "version": "latest"}]}`)
+		case "/v1/modules/example/net/aws/1.0.0":
+			fmt.Fprint(w, `{"id": "example/net/aws/1.0.0", "version": "1.0.0", "versions": ["1.0.0", "2.0.0", "1.5.0"]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// Checks are off by default
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	warnCtx, warnings := registry.WithWarnings(ctx)
+	if _, err := client.Modules.List(warnCtx, &registry.ModuleListOptions{Offset: 10}); err != nil {
+		return fmt.Errorf("list failed: %w", err)
+	}
+	if err := AssertEqual(0, warnings.Len()); err != nil {
+		return fmt.Errorf("checks should be disabled by default: %w", err)
+	}
+
+	client, err = registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithIntegrityChecks())
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	warnCtx, warnings = registry.WithWarnings(ctx)
+	if _, err := client.Modules.List(warnCtx, &registry.ModuleListOptions{Offset: 10}); err != nil {
+		return fmt.Errorf("violations must not fail the call: %w", err)
+	}
+
+	counts := make(map[registry.WarningCode]int)
+	for _, warning := range warnings.List() {
+		counts[warning.Code]++
+	}
+	if err := AssertEqual(1, counts[registry.WarningIntegrityID]); err != nil {
+		return fmt.Errorf("empty ID: %w", err)
+	}
+	if err := AssertEqual(1, counts[registry.WarningIntegrityVersion]); err != nil {
+		return fmt.Errorf("unparseable version: %w", err)
+	}
+	// Wrong current offset and more modules than the limit
+	if err := AssertEqual(2, counts[registry.WarningIntegrityPagination]); err != nil {
+		return fmt.Errorf("pagination: %w", err)
+	}
+
+	warnCtx, warnings = registry.WithWarnings(ctx)
+	if _, err := client.Modules.GetByID(warnCtx, "example/net/aws/1.0.0"); err != nil {
+		return fmt.Errorf("get failed: %w", err)
+	}
+
+	list := warnings.List()
+	if err := AssertEqual(1, len(list)); err != nil {
+		return err
+	}
+	if err := AssertEqual(registry.WarningIntegrityVersion, list[0].Code); err != nil {
+		return err
+	}
+	if err := AssertEqual("example/net/aws/1.0.0", list[0].Resource); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testReadmeMetadata(ctx context.Context) error {
+	readme := strings.Join([]string{
+		"# AWS VPC Terraform module",
+		"",
+		"[![SWUbanner](https://raw.githubusercontent.com/vshymanskyy/StandWithUkraine/main/banner2-direct.svg)](https://github.com/vshymanskyy/StandWithUkraine)",
+		"[![Build](https://github.com/acme/terraform-aws-vpc/actions/workflows/ci.yml/badge.svg)](https://github.com/acme/terraform-aws-vpc/actions)",
+		"[![License](https://img.shields.io/badge/license-Apache%202.0-blue.svg)](LICENSE)",
+		`<a href="https://registry.terraform.io/modules/acme/vpc/aws"><img src="https://img.shields.io/badge/terraform-registry-623CE4" alt="Registry"></a>`,
+		"",
+		"![Architecture](docs/architecture.png)",
+		"",
+		"Requires Terraform 1.3 or later.",
+		"",
+		"```hcl",
+		"![NotABadge](https://img.shields.io/badge/in-code-red)",
+		"```",
+		"",
+		"## Authors",
+		"",
+		"Module is maintained by [Jane Doe](https://github.com/janedoe) with help from [these awesome contributors](https://github.com/acme/terraform-aws-vpc/graphs/contributors).",
+		"",
+		"- John Smith <john@example.com>",
+		"- @octocat",
+		"",
+		"Resources created by this module are tagged with the module name.",
+	}, "\n")
+
+	meta := readmemeta.Parse(readme)
+
+	kinds := make([]string, len(meta.Badges))
+	for i, badge := range meta.Badges {
+		kinds[i] = string(badge.Kind)
+	}
+	if err := AssertEqual("build,license,registry", strings.Join(kinds, ",")); err != nil {
+		return fmt.Errorf("badge kinds: %w", err)
+	}
+	if err := AssertEqual("https://registry.terraform.io/modules/acme/vpc/aws", meta.BadgesOf(readmemeta.BadgeRegistry)[0].LinkURL); err != nil {
+		return err
+	}
+
+	if err := AssertEqual(">= 1.3", meta.TerraformVersion); err != nil {
+		return err
+	}
+
+	want := []readmemeta.Maintainer{
+		{Name: "Jane Doe", URL: "https://github.com/janedoe"},
+		{Name: "John Smith", Email: "john@example.com"},
+		{Handle: "octocat"},
+	}
+	if err := AssertEqual(len(want), len(meta.Maintainers)); err != nil {
+		return fmt.Errorf("maintainers %+v: %w", meta.Maintainers, err)
+	}
+	for i := range want {
+		if meta.Maintainers[i] != want[i] {
+			return fmt.Errorf("maintainer %d: expected %+v, got %+v", i, want[i], meta.Maintainers[i])
+		}
+	}
+
+	// The Requirements table of module details takes precedence over statements
+	details := &registry.ModuleDetails{}
+	details.Root.Readme = "Works with Terraform >= 0.12.\n\n## Requirements\n\n| Name | Version |\n|------|---------|\n| terraform | >= 1.5.7 |\n"
+	details.Root.ReadmeDependencies = registry.ParseReadmeDependencies(details.Root.Readme)
+	if err := AssertEqual(">= 1.5.7", readmemeta.ParseModule(details).TerraformVersion); err != nil {
+		return err
+	}
+	if err := AssertEqual(">= 0.12", readmemeta.Parse("Works with Terraform >= 0.12.").TerraformVersion); err != nil {
+		return err
+	}
+	if !readmemeta.Parse("# Plain module\n\nNothing to see here.").Empty() {
+		return fmt.Errorf("expected no metadata for a plain README")
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testModuleOwnership(ctx context.Context) error {
+	codeowners := "# Platform team owns everything by default\n" +
+		"*       @acme/platform ops@acme.example\n" +
+		"/modules/nat/ @jane\n" +
+		"docs/*  @writer\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/acme/vpc/aws/1.0.0":
+			fmt.Fprint(w, `{"id": "acme/vpc/aws/1.0.0", "version": "1.0.0", "source": "https://github.com/acme/terraform-aws-vpc"}`)
+		case "/v1/modules/acme/legacy/aws/1.0.0":
+			fmt.Fprint(w, `{"id": "acme/legacy/aws/1.0.0", "version": "1.0.0", "source": "https://git.acme.example/legacy"}`)
+		case "/github/repos/acme/terraform-aws-vpc/contents/CODEOWNERS":
+			fmt.Fprintf(w, `{"encoding": "base64", "content": %q}`, base64.StdEncoding.EncodeToString([]byte(codeowners)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		registry.WithGitHubAPI(server.URL+"/github", ""),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// .github/CODEOWNERS is missing, so the root CODEOWNERS is used
+	ownership, err := client.Modules.GetOwnership(ctx, registry.ModuleRef{Namespace: "acme", Name: "vpc", Provider: "aws", Version: "1.0.0"})
+	if err != nil {
+		return fmt.Errorf("failed to get ownership: %w", err)
+	}
+	if err := AssertEqual("CODEOWNERS", ownership.CodeownersPath); err != nil {
+		return err
+	}
+	if err := AssertEqual("acme/terraform-aws-vpc", ownership.Repository); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"@acme/platform", "ops@acme.example"}, ownership.Owners()); err != nil {
+		return err
+	}
+
+	// The last matching rule wins
+	for file, want := range map[string][]string{
+		"modules/nat/main.tf":      {"@jane"},
+		"docs/usage.md":            {"@writer"},
+		"docs/examples/basic.md":   {"@acme/platform", "ops@acme.example"},
+		"modules/vpc/variables.tf": {"@acme/platform", "ops@acme.example"},
+	} {
+		if err := AssertEqual(want, ownership.OwnersOf(file)); err != nil {
+			return fmt.Errorf("owners of %s: %w", file, err)
+		}
+	}
+
+	// Owners enrich the README metadata
+	meta := readmemeta.Parse("")
+	meta.AddOwnership(ownership)
+	want := []readmemeta.Maintainer{
+		{Handle: "acme/platform", URL: "https://github.com/orgs/acme/teams/platform"},
+		{Email: "ops@acme.example"},
+	}
+	if err := AssertEqual(want, meta.Owners); err != nil {
+		return err
+	}
+
+	_, err = client.Modules.GetOwnership(ctx, registry.ModuleRef{Namespace: "acme", Name: "legacy", Provider: "aws", Version: "1.0.0"})
+	if !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for a source outside GitHub, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testModuleVersionDiff(ctx context.Context) error {
+	versions := map[string]string{
+		"1.0.0": `{"id": "acme/vpc/aws/1.0.0", "version": "1.0.0", "root": {
+			"inputs": [
+				{"name": "cidr", "type": "string", "required": true},
+				{"name": "tags", "type": "map(string)", "default": {}, "required": false},
+				{"name": "enable_nat", "type": "bool", "default": "false", "required": false},
+				{"name": "legacy_mode", "type": "bool", "default": "false", "required": false}
+			],
+			"outputs": [{"name": "vpc_id"}, {"name": "nat_ids"}],
+			"resources": [{"name": "this", "type": "aws_vpc"}, {"name": "this", "type": "aws_nat_gateway"}],
+			"provider_dependencies": [{"name": "aws", "namespace": "hashicorp", "source": "hashicorp/aws", "version": ">= 4.0"}]
+		}}`,
+		"2.0.0": `{"id": "acme/vpc/aws/2.0.0", "version": "2.0.0", "root": {
+			"inputs": [
+				{"name": "cidr", "type": "list(string)", "required": true},
+				{"name": "tags", "type": "map(string)", "default": { }, "required": false},
+				{"name": "enable_nat", "type": "bool", "default": "true", "required": false},
+				{"name": "name", "type": "string", "required": true}
+			],
+			"outputs": [{"name": "vpc_id"}, {"name": "vpc_arn"}],
+			"resources": [{"name": "this", "type": "aws_vpc"}, {"name": "this", "type": "aws_flow_log"}],
+			"provider_dependencies": [
+				{"name": "aws", "namespace": "hashicorp", "source": "hashicorp/aws", "version": ">= 5.0"},
+				{"name": "random", "namespace": "hashicorp", "source": "hashicorp/random", "version": ">= 3.0"}
+			]
+		}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := versions[strings.TrimPrefix(r.URL.Path, "/v1/modules/acme/vpc/aws/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	diff, err := client.Modules.DiffVersions(ctx, "acme", "vpc", "aws", "1.0.0", "2.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to diff module versions: %w", err)
+	}
+
+	describe := func(changes []registry.ModuleChange) string {
+		parts := make([]string, len(changes))
+		for i, change := range changes {
+			parts[i] = change.Name + ":" + string(change.Kind)
+			if change.Breaking {
+				parts[i] += "!"
+			}
+		}
+		return strings.Join(parts, " ")
+	}
+
+	checks := []struct {
+		name string
+		got  string
+		want string
+	}{
+		// The whitespace-only change of the tags default is not reported
+		{"inputs", describe(diff.Inputs), "cidr:type_changed! enable_nat:default_changed legacy_mode:removed! name:added!"},
+		{"outputs", describe(diff.Outputs), "nat_ids:removed! vpc_arn:added"},
+		{"resources", describe(diff.Resources), "aws_flow_log.this:added aws_nat_gateway.this:removed!"},
+		{"providers", describe(diff.Providers), "hashicorp/aws:constraint_changed hashicorp/random:added"},
+	}
+	for _, check := range checks {
+		if err := AssertEqual(check.want, check.got); err != nil {
+			return fmt.Errorf("%s: %w", check.name, err)
+		}
+	}
+
+	if err := AssertEqual(5, len(diff.Breaking())); err != nil {
+		return err
+	}
+	if err := AssertEqual(">= 5.0", diff.Providers[0].To); err != nil {
+		return err
+	}
+	if err := AssertEqual("2.0.0", diff.ToVersion); err != nil {
+		return err
+	}
+
+	same, err := client.Modules.DiffVersions(ctx, "acme", "vpc", "aws", "1.0.0", "1.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to diff a version with itself: %w", err)
+	}
+	if !same.Empty() {
+		return fmt.Errorf("expected no changes between identical versions, got %+v", same)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testIngestionGate(ctx context.Context) error {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	module := gate.ModuleSubject(registry.Module{
+		Namespace:   "acme-platform",
+		Name:        "network",
+		Provider:    "aws",
+		Version:     "2.1.0",
+		Downloads:   5000,
+		PublishedAt: now.Add(-30 * 24 * time.Hour),
+	})
+	module.License = gate.FindLicense([]registry.ArchiveFile{
+		{Path: "main.tf", Data: []byte("# Apache License")},
+		{Path: "LICENSE.txt", Data: []byte("Copyright (c) 2024 Acme\n\nPermission is hereby granted, free of charge,\nto any person obtaining a copy")},
+	})
+	if err := AssertEqual("MIT", module.License); err != nil {
+		return fmt.Errorf("detected license: %w", err)
+	}
+
+	// The defaults admit a popular, recent, permissively licensed module
+	if result := gate.DefaultPolicy().EvaluateAt(module, now); !result.Passed {
+		return fmt.Errorf("expected the default policy to pass, got: %v", result.Reasons())
+	}
+
+	policy, err := gate.ParseYAML([]byte(`
+allowed_namespaces: [hashicorp, "acme-*"]
+max_version_age: 26w
+allowed_licenses: [Apache-2.0, MPL-2.0, NOASSERTION]
+providers:
+  min_downloads: 1000000
+  allowed_licenses: [MPL-2.0]
+`))
+	if err != nil {
+		return fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if err := AssertEqual(int64(1000), policy.RulesFor(gate.KindModule).MinDownloads); err != nil {
+		return fmt.Errorf("default min downloads: %w", err)
+	}
+	if err := AssertEqual([]string{"hashicorp", "acme-*"}, policy.RulesFor(gate.KindProvider).AllowedNamespaces); err != nil {
+		return fmt.Errorf("inherited namespaces: %w", err)
+	}
+
+	result := policy.EvaluateAt(module, now)
+	if result.Passed {
+		return fmt.Errorf("expected the MIT license to be rejected")
+	}
+	if err := AssertEqual([]string{"license MIT is not in the allowed licenses"}, result.Reasons()); err != nil {
+		return err
+	}
+	if err := AssertEqual(4, len(result.Checks)); err != nil {
+		return fmt.Errorf("checks: %w", err)
+	}
+
+	// NOASSERTION admits modules without a detectable license
+	module.License = ""
+	if result := policy.EvaluateAt(module, now); !result.Passed {
+		return fmt.Errorf("expected an unknown license to pass, got: %v", result.Reasons())
+	}
+
+	provider := gate.ProviderSubject(registry.Provider{
+		Namespace:   "community",
+		Name:        "widget",
+		Version:     "0.1.0",
+		Downloads:   200,
+		PublishedAt: now.Add(-400 * 24 * time.Hour),
+	})
+	failures := map[string]bool{}
+	for _, check := range policy.EvaluateAt(provider, now).Failures() {
+		failures[check.Rule] = true
+	}
+	want := map[string]bool{gate.RuleNamespace: true, gate.RuleDownloads: true, gate.RuleVersionAge: true, gate.RuleLicense: true}
+	if err := AssertEqual(want, failures); err != nil {
+		return fmt.Errorf("provider failures: %w", err)
+	}
+
+	// A misspelled rule must not silently disable the gate
+	if _, err := gate.ParseYAML([]byte("min_download: 10\n")); err == nil || !strings.Contains(err.Error(), "min_download") {
+		return fmt.Errorf("expected an unknown key error, got: %v", err)
+	}
+	if _, err := gate.ParseYAML([]byte("max_version_age: soon\n")); err == nil {
+		return fmt.Errorf("expected an invalid duration error")
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testGenerateTfvars(ctx context.Context) error {
+	var module registry.ModuleDetails
+	if err := json.Unmarshal([]byte(`{
+		"namespace": "acme", "name": "network", "provider": "aws", "version": "1.2.0",
+		"root": {"inputs": [
+			{"name": "cidr", "type": "string", "description": "CIDR block of the VPC", "default": "\"10.0.0.0/16\"", "required": false},
+			{"name": "enable_nat", "type": "bool", "default": "false", "required": false},
+			{"name": "name", "type": "string", "description": "Name prefix", "required": true},
+			{"name": "subnets", "type": "list(string)", "required": true},
+			{"name": "tags", "type": "map(string)", "default": "{\"Owner Team\":\"net\",\"cost-center\":\"42\"}", "required": false},
+			{"name": "template", "type": "string", "default": "\"${var.name}-vpc\"", "required": false}
+		]}
+	}`), &module); err != nil {
+		return err
+	}
+
+	// Required inputs only, with one override
+	tfvars, err := registry.GenerateTfvars(&module, map[string]any{"subnets": []string{"10.0.1.0/24", "10.0.2.0/24"}}, false)
+	if err != nil {
+		return fmt.Errorf("failed to generate tfvars: %w", err)
+	}
+	want := "# Generated for module acme/network/aws@1.2.0\n\n" +
+		"# Name prefix (required)\nname = \"<REQUIRED: string>\"\n\n" +
+		"# (required)\nsubnets = [\n  \"10.0.1.0/24\",\n  \"10.0.2.0/24\",\n]\n"
+	if err := AssertEqual(want, tfvars.HCL()); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"name"}, tfvars.Missing()); err != nil {
+		return err
+	}
+
+	// Optional inputs with their decoded defaults
+	tfvars, err = registry.GenerateTfvars(&module, map[string]any{"name": "core", "subnets": []string{}, "enable_nat": true}, true)
+	if err != nil {
+		return fmt.Errorf("failed to generate tfvars with optional inputs: %w", err)
+	}
+	hcl := tfvars.HCL()
+	for _, fragment := range []string{
+		"name = \"core\"\n",
+		"subnets = []\n",
+		"# CIDR block of the VPC\ncidr = \"10.0.0.0/16\"\n",
+		"enable_nat = true\n",
+		"tags = {\n  \"Owner Team\" = \"net\"\n  cost-center  = \"42\"\n}\n",
+		"template = \"$${var.name}-vpc\"\n",
+	} {
+		if !strings.Contains(hcl, fragment) {
+			return fmt.Errorf("tfvars missing %q:\n%s", fragment, hcl)
+		}
+	}
+	if err := AssertEqual(0, len(tfvars.Missing())); err != nil {
+		return err
+	}
+
+	data, err := tfvars.JSON()
+	if err != nil {
+		return err
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("invalid tfvars JSON: %w", err)
+	}
+	if err := AssertEqual(map[string]any{"Owner Team": "net", "cost-center": "42"}, decoded["tags"]); err != nil {
+		return err
+	}
+	if err := AssertEqual("${var.name}-vpc", decoded["template"]); err != nil {
+		return err
+	}
+
+	if _, err := registry.GenerateTfvars(&module, map[string]any{"cidr_block": "10.1.0.0/16"}, false); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for an unknown override, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testGetLatestFallback(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/example/listed/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "1.0.0"}, {"version": "1.2.0"}]}]}`)
+		case "/v1/modules/example/listed/aws/1.2.0":
+			fmt.Fprint(w, `{"id": "example/listed/aws/1.2.0", "namespace": "example", "name": "listed", "provider": "aws", "version": "1.2.0"}`)
+		case "/v1/modules/example/net/aws/versions", "/v1/modules/example/found/aws/versions", "/v1/modules/example/gone/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": []}]}`)
+		case "/v1/modules/example/net/aws":
+			fmt.Fprint(w, `{"id": "example/net/aws/3.1.0", "namespace": "example", "name": "net", "provider": "aws", "version": "3.1.0"}`)
+		case "/v1/modules/search":
+			fmt.Fprint(w, `{"modules": [{"id": "other/found/aws/9.0.0", "namespace": "other", "name": "found", "provider": "aws", "version": "9.0.0"},
+				{"id": "example/found/aws/2.4.0", "namespace": "example", "name": "found", "provider": "aws", "version": "2.4.0"}]}`)
+		case "/v1/modules/example/found/aws/2.4.0":
+			fmt.Fprint(w, `{"id": "example/found/aws/2.4.0", "namespace": "example", "name": "found", "provider": "aws", "version": "2.4.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Listed versions are used without a warning
+	warnCtx, warnings := registry.WithWarnings(ctx)
+	latest, err := client.Modules.GetLatest(warnCtx, "example", "listed", "aws")
+	if err != nil {
+		return fmt.Errorf("failed to get latest listed version: %w", err)
+	}
+	if err := AssertEqual("1.2.0", latest.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(0, warnings.Len()); err != nil {
+		return err
+	}
+
+	// An empty versions list falls back to the module endpoint
+	warnCtx, warnings = registry.WithWarnings(ctx)
+	latest, err = client.Modules.GetLatest(warnCtx, "example", "net", "aws")
+	if err != nil {
+		return fmt.Errorf("failed to get latest from the module endpoint: %w", err)
+	}
+	if err := AssertEqual("3.1.0", latest.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, warnings.Len()); err != nil {
+		return err
+	}
+	warning := warnings.List()[0]
+	if err := AssertEqual(registry.WarningLatestFallback, warning.Code); err != nil {
+		return err
+	}
+	if !errors.Is(warning.Err, registry.ErrNoVersions) {
+		return fmt.Errorf("expected the warning to carry ErrNoVersions, got: %v", warning.Err)
+	}
+
+	// Then to the exact match among search results
+	warnCtx, warnings = registry.WithWarnings(ctx)
+	latest, err = client.Modules.GetLatest(warnCtx, "example", "found", "aws")
+	if err != nil {
+		return fmt.Errorf("failed to get latest from search: %w", err)
+	}
+	if err := AssertEqual("2.4.0", latest.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, warnings.Len()); err != nil {
+		return err
+	}
+
+	// Modules unknown everywhere report the original error
+	if _, err := client.Modules.GetLatest(ctx, "example", "gone", "aws"); !errors.Is(err, registry.ErrNoVersions) {
+		return fmt.Errorf("expected ErrNoVersions, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testOfflineSnapshot(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/example/net/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "1.0.0"}, {"version": "2.0.0"}]}]}`)
+		case "/v1/modules/example/net/aws/2.0.0":
+			fmt.Fprint(w, `{"id": "example/net/aws/2.0.0", "namespace": "example", "name": "net", "provider": "aws", "version": "2.0.0"}`)
+		case "/v1/modules/example/net/aws/1.0.0":
+			fmt.Fprint(w, `{"id": "example/net/aws/1.0.0", "namespace": "example", "name": "net", "provider": "aws", "version": "1.0.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "terralense-snapshot-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	recorder, err := snapshot.NewRecorder(client, nil)
+	if err != nil {
+		return err
+	}
+	entry, err := recorder.AddModule(ctx, registry.ModuleRef{Namespace: "example", Name: "net", Provider: "aws"})
+	if err != nil {
+		return fmt.Errorf("failed to record module: %w", err)
+	}
+	if err := AssertEqual("2.0.0", entry.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(entry.Versions)); err != nil {
+		return err
+	}
+
+	// Both layouts serve the recorded reads under another base URL, without the server
+	server.Close()
+	for _, path := range []string{filepath.Join(dir, "net"), filepath.Join(dir, "net.json")} {
+		if err := recorder.Save(path); err != nil {
+			return fmt.Errorf("failed to save snapshot %s: %w", path, err)
+		}
+
+		offline, err := registry.NewClient(registry.WithOfflineSnapshot(path), registry.WithLogger(s.logger))
+		if err != nil {
+			return fmt.Errorf("failed to create offline client: %w", err)
+		}
+
+		latest, err := offline.Modules.GetLatest(ctx, "example", "net", "aws")
+		if err != nil {
+			return fmt.Errorf("failed to get latest from %s: %w", path, err)
+		}
+		if err := AssertEqual("2.0.0", latest.Version); err != nil {
+			return err
+		}
+
+		versions, err := offline.Modules.ListVersions(ctx, "example", "net", "aws")
+		if err != nil {
+			return fmt.Errorf("failed to list versions from %s: %w", path, err)
+		}
+		if err := AssertEqual(2, len(versions)); err != nil {
+			return err
+		}
+
+		// Reads that were not recorded are not found
+		_, err = offline.Modules.Get(ctx, "example", "net", "aws", "1.0.0")
+		if !registry.IsNotFound(err) {
+			return fmt.Errorf("expected an unrecorded version to be not found, got: %v", err)
+		}
+	}
+
+	loaded, err := registry.ReadSnapshot(filepath.Join(dir, "net.json"))
+	if err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(loaded.Contents)); err != nil {
+		return err
+	}
+	if err := AssertEqual("example/net/aws", loaded.Contents[0].Address); err != nil {
+		return err
+	}
+
+	if _, err := registry.NewClient(registry.WithOfflineSnapshot(filepath.Join(dir, "net.sqlite"))); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected SQLite snapshots to be rejected, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testPrivateModuleRegistry(ctx context.Context) error {
+	var privateAuth atomic.Value
+	private := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		privateAuth.Store(r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/registry/v1/modules/acme":
+			fmt.Fprint(w, `{"meta": {"limit": 50}, "modules": [{"id": "acme/vpc/aws/1.0.0", "namespace": "acme", "name": "vpc", "provider": "aws", "version": "1.0.0"}]}`)
+		case "/api/registry/v1/modules/acme/vpc/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "0.9.0"}, {"version": "1.0.0"}]}]}`)
+		case "/api/registry/v1/modules/acme/vpc/aws/1.0.0":
+			fmt.Fprint(w, `{"id": "acme/vpc/aws/1.0.0", "namespace": "acme", "name": "vpc", "provider": "aws", "version": "1.0.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer private.Close()
+
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/hashicorp/consul/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "0.1.0"}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer public.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(public.URL),
+		registry.WithLogger(s.logger),
+		registry.WithAPIToken("public-token"),
+		registry.WithOrganization("acme"),
+		registry.WithTFE(private.URL, "tfc-token"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Listing without a namespace lists the organization
+	list, err := client.Modules.List(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list organization modules: %w", err)
+	}
+	if err := AssertEqual(1, len(list.Modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual("Bearer tfc-token", privateAuth.Load().(string)); err != nil {
+		return err
+	}
+
+	latest, err := client.Modules.GetLatest(ctx, "acme", "vpc", "aws")
+	if err != nil {
+		return fmt.Errorf("failed to get private module: %w", err)
+	}
+	if err := AssertEqual("1.0.0", latest.Version); err != nil {
+		return err
+	}
+
+	// Other namespaces still use the public registry
+	versions, err := client.Modules.ListVersions(ctx, "hashicorp", "consul", "aws")
+	if err != nil {
+		return fmt.Errorf("failed to list public module versions: %w", err)
+	}
+	if err := AssertEqual(1, len(versions)); err != nil {
+		return err
+	}
+
+	downloadURL, err := client.Modules.Download(ctx, "acme", "vpc", "aws", "1.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to get private download URL: %w", err)
+	}
+	if err := AssertEqual(private.URL+"/api/registry/v1/modules/acme/vpc/aws/1.0.0/download", downloadURL); err != nil {
+		return err
+	}
+
+	if _, err := registry.NewClient(registry.WithOrganization("acme corp")); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected an invalid organization to be rejected, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testOpenTofuModules(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/registry/docs/modules/example/vpc/aws/index.json":
+			fmt.Fprint(w, `{"description": "VPC module", "versions": [{"id": "v1.0.0"}, {"id": "v1.1.0"}]}`)
+		case "/registry/docs/modules/example/vpc/aws/v1.1.0/index.json":
+			fmt.Fprint(w, `{"id": "v1.1.0", "vcs_repository": "https://github.com/example/terraform-aws-vpc",
+				"variables": {"name": {"type": "string", "description": "VPC name", "required": true}, "cidr": {"type": "string", "default": "10.0.0.0/16"}},
+				"outputs": {"vpc_id": {"description": "VPC ID"}},
+				"providers": [{"name": "aws", "full_name": "hashicorp/aws", "version_constraint": ">= 5.0"}],
+				"submodules": {"endpoints": {"variables": {"vpc_id": {"type": "string", "required": true}}}}}`)
+		case "/registry/docs/modules/example/vpc/aws/v1.1.0/README.md":
+			fmt.Fprint(w, "# VPC\n")
+		case "/registry/docs/search":
+			fmt.Fprint(w, `[
+				{"type": "module", "description": "VPC module", "link_variables": {"namespace": "example", "name": "vpc", "target": "aws", "version": "v1.1.0"}},
+				{"type": "provider", "link_variables": {"namespace": "hashicorp", "name": "aws"}}
+			]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBackend(registry.BackendOpenTofu),
+		registry.WithOpenTofuDocsURL(server.URL),
+		registry.WithLogger(s.logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	module, err := client.Modules.Get(ctx, "example", "vpc", "aws", "1.1.0")
+	if err != nil {
+		return fmt.Errorf("failed to get module: %w", err)
+	}
+	if err := AssertEqual("example/vpc/aws/1.1.0", module.ID); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(module.Root.Inputs)); err != nil {
+		return err
+	}
+	if err := AssertEqual("cidr", module.Root.Inputs[0].Name); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"aws"}, module.Providers); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(module.Submodules)); err != nil {
+		return err
+	}
+	if err := AssertContains(module.Root.Readme, "# VPC"); err != nil {
+		return err
+	}
+
+	// Search results of other kinds are left out
+	results, err := client.Modules.Search(ctx, "vpc", 0)
+	if err != nil {
+		return fmt.Errorf("failed to search modules: %w", err)
+	}
+	if err := AssertEqual(1, len(results.Modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.1.0", results.Modules[0].Version); err != nil {
+		return err
+	}
+
+	if _, err := client.Modules.List(ctx, nil); !errors.Is(err, registry.ErrUnsupportedByBackend) {
+		return fmt.Errorf("expected listing modules to be unsupported, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testLintModuleDocs(ctx context.Context) error {
+	details := &registry.ModuleDetails{
+		Root: registry.ModulePart{
+			Readme: "# Example\n\n## Usage\n\nSee below.\n",
+			Inputs: []registry.ModuleInput{
+				{Name: "name", Required: true},
+				{Name: "tags", Description: "Tags to apply"},
+			},
+			Outputs: []registry.ModuleOutput{
+				{Name: "id"},
+			},
+		},
+	}
+
+	findings, err := quality.LintModule(details)
+	if err != nil {
+		return fmt.Errorf("failed to lint module: %w", err)
+	}
+
+	rules := make(map[string]int)
+	for _, f := range findings {
+		rules[f.Rule]++
+	}
+
+	expected := map[string]int{
+		quality.RuleMissingDescription:   1,
+		quality.RuleMissingReadmeSection: 2, // Inputs and Outputs
+		quality.RuleInputDescription:     1,
+		quality.RuleOutputDescription:    1,
+		quality.RuleMissingExamples:      1,
+	}
+	for rule, count := range expected {
+		if rules[rule] != count {
+			return fmt.Errorf("expected %d %s findings, got %d", count, rule, rules[rule])
+		}
+	}
+
+	if err := AssertEqual(quality.SeverityError, findings.Max()); err != nil {
+		return fmt.Errorf("undescribed required input should be an error: %w", err)
+	}
+
+	if _, err := quality.LintModule(nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for nil details, got: %v", err)
+	}
+
+	s.logger.Debugf("Lint produced %d findings", len(findings))
+	return nil
+}
+
+func (s *ModuleTests) testHasChanged(ctx context.Context) error {
+	ref := registry.ModuleRef{Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws"}
+
+	latest, err := s.client.Modules.GetLatest(ctx, ref.Namespace, ref.Name, ref.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+
+	changed, err := s.client.Modules.HasChanged(ctx, ref, latest.Version)
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if changed {
+		return fmt.Errorf("expected no change when known version is latest (%s)", latest.Version)
+	}
+
+	changed, err = s.client.Modules.HasChanged(ctx, ref, "")
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !changed {
+		return fmt.Errorf("expected change for an empty known version")
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testParseReadmeDependencies(ctx context.Context) error {
+	readme := `# VPC
+
+## Requirements
+
+| Name | Version |
+|------|---------|
+| <a name="requirement_terraform"></a> [terraform](#requirement\_terraform) | >= 1.0 |
+| <a name="requirement_aws"></a> [aws](#requirement\_aws) | >= 5.0, < 6.0 |
+
+## Providers
+
+| Name | Version |
+|------|---------|
+| <a name="provider_aws"></a> [aws](#provider\_aws) | >= 5.0, < 6.0 |
+
+## Modules
+
+| Name | Source | Version |
+|------|--------|---------|
+| <a name="module_flow_log"></a> [flow\_log](#module\_flow\_log) | ./modules/flow-log | n/a |
+
+## Inputs
+
+| Name | Description | Type | Default | Required |
+|------|-------------|------|---------|:--------:|
+| <a name="input_name"></a> [name](#input\_name) | Name | ` + "`string`" + ` | ` + "`\"\"`" + ` | no |
+`
+
+	deps := registry.ParseReadmeDependencies(readme)
+	if deps == nil {
+		return fmt.Errorf("expected dependencies to be parsed")
+	}
+
+	if err := AssertEqual(2, len(deps.Requirements)); err != nil {
+		return fmt.Errorf("requirements: %w", err)
+	}
+	if err := AssertEqual(registry.ReadmeRequirement{Name: "aws", Version: ">= 5.0, < 6.0"}, deps.Requirements[1]); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(deps.Providers)); err != nil {
+		return fmt.Errorf("providers: %w", err)
+	}
+	if err := AssertEqual(registry.ReadmeModule{Name: "flow_log", Source: "./modules/flow-log"}, deps.Modules[0]); err != nil {
+		return err
+	}
+
+	if registry.ParseReadmeDependencies("# Module\n\nNo tables here.") != nil {
+		return fmt.Errorf("expected nil for a README without dependency tables")
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testEstimateModuleCost(ctx context.Context) error {
+	details := &registry.ModuleDetails{
+		Root: registry.ModulePart{
+			Resources: []registry.ModuleResource{
+				{Name: "this", Type: "aws_vpc"},
+				{Name: "this", Type: "aws_nat_gateway"},
+			},
+		},
+		Submodules: []registry.ModulePart{
+			{
+				Path: "modules/endpoints",
+				Resources: []registry.ModuleResource{
+					{Name: "this", Type: "aws_vpc_endpoint"},
+					{Name: "this", Type: "aws_nat_gateway"},
+				},
+			},
+		},
+	}
+
+	estimator := &cost.StaticEstimator{Prices: map[string]float64{
+		"aws_vpc":         0,
+		"aws_nat_gateway": 32.85,
+	}}
+
+	ref := registry.ModuleRef{Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws", Version: "5.0.0"}
+	report, err := cost.EstimateModuleDetails(ctx, estimator, ref, details, nil)
+	if err != nil {
+		return fmt.Errorf("failed to estimate cost: %w", err)
+	}
+
+	if err := AssertEqual(3, len(report.Items)); err != nil {
+		return err
+	}
+	if err := AssertEqual(65.7, report.MonthlyTotal); err != nil {
+		return err
+	}
+	if err := AssertEqual(32.85, report.ByPath["modules/endpoints"]); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(report.Unpriced)); err != nil {
+		return err
+	}
+	if !errors.Is(report.Unpriced[0].Err, cost.ErrNoPrice) {
+		return fmt.Errorf("expected ErrNoPrice for unpriced resource, got %v", report.Unpriced[0].Err)
+	}
+
+	euro := cost.EstimatorFunc(func(ctx context.Context, req cost.ResourceRequest) (*cost.ResourceEstimate, error) {
+		currency := "EUR"
+		if req.Path != "" {
+			currency = "USD"
+		}
+		return &cost.ResourceEstimate{MonthlyCost: 1, Currency: currency}, nil
+	})
+	if _, err := cost.EstimateModuleDetails(ctx, euro, ref, details, nil); err == nil {
+		return fmt.Errorf("expected error for mixed currencies")
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testExportModule(ctx context.Context) error {
+	details := registry.ModuleDetails{
+		Module: registry.Module{
+			ID: "example/widget/aws/1.0.0", Namespace: "example", Name: "widget", Provider: "aws",
+			Version: "1.0.0", Source: "https://github.com/example/terraform-aws-widget",
+		},
+		Root:       registry.ModulePart{Readme: "# Widget"},
+		Submodules: []registry.ModulePart{{Path: "modules/gear", Readme: "# Gear"}},
+		Examples: []registry.ModulePart{
+			{Path: "examples/complete", Readme: "# Complete\n\n```hcl\nmodule \"widget\" {\n  source = \"../..\"\n}\n```\n"},
+			{Path: "../escape"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/modules/example/widget/aws/1.0.0" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(details)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "terralense-export-module")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ref := registry.ModuleRef{Namespace: "example", Name: "widget", Provider: "aws", Version: "1.0.0"}
+	manifest, err := client.Modules.Export(ctx, ref, dir)
+	if err != nil {
+		return fmt.Errorf("failed to export module: %w", err)
+	}
+
+	for _, file := range []string{
+		"module.json",
+		"README.md",
+		"modules/gear/README.md",
+		"examples/complete/README.md",
+		"examples/complete/main.tf",
+		"escape/part.json",
+		registry.ExportManifestFile,
+	} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+			return fmt.Errorf("expected exported file %s: %w", file, err)
+		}
+	}
+
+	code, err := os.ReadFile(filepath.Join(dir, "examples", "complete", "main.tf"))
+	if err != nil {
+		return fmt.Errorf("failed to read example code: %w", err)
+	}
+	if !strings.Contains(string(code), `module "widget"`) {
+		return fmt.Errorf("expected example code to contain the module block, got %q", code)
+	}
+
+	if err := AssertEqual("https://github.com/example/terraform-aws-widget", manifest.Source); err != nil {
+		return err
+	}
+	if err := AssertEqual(8, len(manifest.Files)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testAuditTrail(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer org-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		since := r.URL.Query().Get("since")
+		switch {
+		case since == "" && r.URL.Query().Get("page[number]") == "1":
+			fmt.Fprint(w, `{"data": [
+				{"id": "e1", "timestamp": "2024-05-01T10:00:00Z", "resource": {"type": "registry_module", "action": "create"}},
+				{"id": "e2", "timestamp": "2024-05-01T11:00:00Z", "resource": {"type": "workspace", "action": "update"}}
+			], "pagination": {"current_page": 1, "next_page": 2, "total_pages": 2}}`)
+		case since == "":
+			fmt.Fprint(w, `{"data": [
+				{"id": "e3", "timestamp": "2024-05-01T12:00:00Z", "resource": {"type": "registry_provider_version", "action": "destroy"}}
+			], "pagination": {"current_page": 2, "next_page": null, "total_pages": 2}}`)
+		default:
+			// The since filter is inclusive, so the newest event is returned again
+			fmt.Fprint(w, `{"data": [
+				{"id": "e3", "timestamp": "2024-05-01T12:00:00Z", "resource": {"type": "registry_provider_version", "action": "destroy"}}
+			], "pagination": {"current_page": 1, "next_page": null, "total_pages": 1}}`)
+		}
+	}))
+	defer server.Close()
+
+	unconfigured, err := registry.NewClient(registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if _, err := unconfigured.Audit.FetchRegistryEvents(ctx, registry.AuditCursor{}); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected configuration error without a token, got %v", err)
+	}
+
+	client, err := registry.NewClient(registry.WithAuditTrail(server.URL, "org-token"), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	page, err := client.Audit.FetchRegistryEvents(ctx, registry.AuditCursor{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch audit trail: %w", err)
+	}
+
+	if err := AssertEqual(2, len(page.Events)); err != nil {
+		return err
+	}
+	if err := AssertEqual("destroy", page.Events[1].Resource.Action); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"e3"}, page.Cursor.SeenIDs); err != nil {
+		return err
+	}
+
+	next, err := client.Audit.FetchRegistryEvents(ctx, page.Cursor)
+	if err != nil {
+		return fmt.Errorf("failed to fetch audit trail from cursor: %w", err)
+	}
+	if err := AssertEqual(0, len(next.Events)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testResourceCoverage(ctx context.Context) error {
+	details := registry.ModuleDetails{
+		Module: registry.Module{Namespace: "example", Name: "network", Provider: "aws", Version: "1.0.0"},
+		Root: registry.ModulePart{
+			Resources: []registry.ModuleResource{
+				{Name: "this", Type: "aws_vpc"},
+				{Name: "public", Type: "aws_subnet"},
+				{Name: "logs", Type: "aws_s3_bucket"},
+				{Name: "id", Type: "random_id"},
+			},
+			ProviderDependencies: []registry.ModuleProviderDependency{{Name: "aws", Namespace: "hashicorp"}},
+		},
+		Submodules: []registry.ModulePart{{
+			Path:      "modules/private",
+			Resources: []registry.ModuleResource{{Name: "private", Type: "aws_subnet"}},
+		}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/modules/example/network/aws/1.0.0":
+			json.NewEncoder(w).Encode(details)
+		case r.URL.Path == "/v2/providers/hashicorp/aws":
+			fmt.Fprint(w, `{"data": {"id": "p1"}, "included": [{"id": "v1", "attributes": {"version": "5.0.0"}}]}`)
+		case r.URL.Path == "/v2/provider-docs" && r.URL.Query().Get("filter[subcategory]") == "VPC":
+			fmt.Fprint(w, `{"data": [{"id": "d1"}, {"id": "d2"}, {"id": "d3"}]}`)
+		case r.URL.Path == "/v2/provider-docs":
+			fmt.Fprint(w, `{"data": [
+				{"id": "d1", "attributes": {"category": "resources", "slug": "vpc"}},
+				{"id": "d2", "attributes": {"category": "resources", "slug": "subnet"}},
+				{"id": "d3", "attributes": {"category": "resources", "slug": "internet_gateway"}},
+				{"id": "d4", "attributes": {"category": "resources", "slug": "s3_bucket"}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ref := registry.ModuleRef{Namespace: "example", Name: "network", Provider: "aws", Version: "1.0.0"}
+	report, err := client.Analyze.Coverage(ctx, ref, "VPC")
+	if err != nil {
+		return fmt.Errorf("failed to get coverage: %w", err)
+	}
+
+	if err := AssertEqual([]registry.CoveredResource{
+		{Type: "aws_subnet", Paths: []string{"", "modules/private"}},
+		{Type: "aws_vpc", Paths: []string{""}},
+	}, report.Covered); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"aws_internet_gateway"}, report.Missing); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"aws_s3_bucket"}, report.Outside); err != nil {
+		return err
+	}
+	if err := AssertEqual("hashicorp/aws@5.0.0", report.Provider.String()); err != nil {
+		return err
+	}
+
+	if _, err := client.Analyze.Coverage(ctx, ref, ""); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty subcategory, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testNamespaceMigration(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/neworg/vnet/azurerm/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "1.0.0"}]}]}`)
+		case "/v2/providers/neworg/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "neworg", "name": "widget"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	main := `terraform {
+  required_providers {
+    widget = {
+      source  = "oldorg/widget"
+      version = "~> 1.0"
+    }
+    aws = { source = "hashicorp/aws" }
+  }
+}
+
+# module "commented" { source = "oldorg/commented/aws" }
+
+module "vnet" {
+  source  = "oldorg/vnet/azurerm"
+  version = "1.0.0"
+}
+
+module "local" {
+  source = "./modules/oldorg/x/y"
+}
+
+module "gone" {
+  source = "oldorg/gone/aws//modules/sub"
+}
+
+module "private" {
+  source = "app.terraform.io/oldorg/net/aws"
+}
+`
+	files := []migrate.File{{Path: "main.tf", Data: []byte(main)}}
+
+	if _, err := migrate.BuildPlan(ctx, client, files, migrate.Mapping{"oldorg": "oldorg"}); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for identity mapping, got: %v", err)
+	}
+
+	plan, err := migrate.BuildPlan(ctx, client, files, migrate.Mapping{"oldorg": "neworg"})
+	if err != nil {
+		return fmt.Errorf("failed to build plan: %w", err)
+	}
+
+	var sources []string
+	for _, change := range plan.Changes {
+		sources = append(sources, change.NewSource)
+	}
+	expected := []string{
+		"neworg/widget",
+		"neworg/vnet/azurerm",
+		"neworg/gone/aws//modules/sub",
+		"app.terraform.io/neworg/net/aws",
+	}
+	if err := AssertEqual(strings.Join(expected, ","), strings.Join(sources, ",")); err != nil {
+		return err
+	}
+
+	if plan.Ready() {
+		return fmt.Errorf("plan with missing targets should not be ready")
+	}
+
+	unverified := plan.Unverified()
+	if err := AssertEqual(2, len(unverified)); err != nil {
+		return err
+	}
+	// The missing module is not an error; the foreign host cannot be checked
+	if unverified[0].Err != nil || unverified[1].Err == nil {
+		return fmt.Errorf("unexpected verification errors: %v, %v", unverified[0].Err, unverified[1].Err)
+	}
+
+	if err := AssertEqual(1, len(plan.Patches)); err != nil {
+		return err
+	}
+	updated := string(plan.Patches[0].Updated)
+	for _, want := range []string{`"neworg/widget"`, `"neworg/vnet/azurerm"`, `"oldorg/gone/aws//modules/sub"`, `"./modules/oldorg/x/y"`, `"oldorg/commented/aws"`} {
+		if !strings.Contains(updated, want) {
+			return fmt.Errorf("updated file is missing %s", want)
+		}
+	}
+
+	diff := plan.Diff()
+	if !strings.Contains(diff, "@@ -14 +14 @@\n-  source  = \"oldorg/vnet/azurerm\"\n+  source  = \"neworg/vnet/azurerm\"\n") {
+		return fmt.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testModuleArchive(ctx context.Context) error {
+	tarball := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, name := range []string{"main.tf", "modules/sub/main.tf", "../evil.tf"} {
+			content, ok := files[name]
+			if !ok {
+				continue
+			}
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+			tw.Write([]byte(content))
+		}
+		tw.Close()
+		gz.Close()
+		return buf.Bytes()
+	}
+
+	good := tarball(map[string]string{"main.tf": "root", "modules/sub/main.tf": "sub"})
+	evil := tarball(map[string]string{"main.tf": "root", "../evil.tf": "escaped"})
+	sum := sha256.Sum256(good)
+	checksum := hex.EncodeToString(sum[:])
+
+	// An uncompressed tar whose entries are all read before its checksum fails
+	var plain bytes.Buffer
+	tw := tar.NewWriter(&plain)
+	tw.WriteHeader(&tar.Header{Name: "main.tf", Mode: 0o644, Size: 8, Typeflag: tar.TypeReg})
+	tw.Write([]byte("tampered"))
+	tw.Close()
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	w, _ := zw.Create("variables.tf")
+	w.Write([]byte("zip"))
+	zw.Close()
+
+	sources := map[string]string{
+		"good":     "/archives/good.tar.gz//modules/sub?checksum=sha256:" + checksum,
+		"mismatch": "/archives/good.tar.gz?checksum=sha256:" + strings.Repeat("0", 64),
+		"evil":     "/archives/evil.tgz",
+		"tampered": "/archives/tampered.tar?checksum=sha256:" + checksum,
+		"zip":      "/archives/blob?archive=zip",
+		"gitlab":   "git::https://gitlab.com/acme/network.git?ref=v1.0.0",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/modules/acme/"):
+			name := strings.Split(r.URL.Path, "/")[4]
+			source, ok := sources[name]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("X-Terraform-Get", source)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/archives/good.tar.gz":
+			w.Write(good)
+		case r.URL.Path == "/archives/evil.tgz":
+			w.Write(evil)
+		case r.URL.Path == "/archives/tampered.tar":
+			w.Write(plain.Bytes())
+		case r.URL.Path == "/archives/blob":
+			if r.URL.RawQuery != "" {
+				http.Error(w, "unexpected query "+r.URL.RawQuery, http.StatusBadRequest)
+				return
+			}
+			w.Write(zipped.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "terralense-archive-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	dest := filepath.Join(dir, "good")
+	moduleDir, err := client.Modules.DownloadArchive(ctx, "acme", "good", "aws", "1.0.0", dest)
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	if err := AssertEqual(filepath.Join(dest, "modules", "sub"), moduleDir); err != nil {
+		return err
+	}
+	for path, want := range map[string]string{"main.tf": "root", "modules/sub/main.tf": "sub"} {
+		data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(path)))
+		if err != nil {
+			return fmt.Errorf("missing extracted file %s: %w", path, err)
+		}
+		if err := AssertEqual(want, string(data)); err != nil {
+			return err
+		}
+	}
+
+	// Saving the archive without extracting it
+	saved, err := client.Modules.SaveArchive(ctx, "acme", "good", "aws", "1.0.0", filepath.Join(dir, "good.tar.gz"))
+	if err != nil {
+		return fmt.Errorf("failed to save archive: %w", err)
+	}
+	if err := AssertEqual("tar.gz", saved.Format); err != nil {
+		return err
+	}
+	if err := AssertEqual("modules/sub", saved.Subdir); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(saved.Path); err != nil || !bytes.Equal(good, data) {
+		return fmt.Errorf("saved archive differs from the served one: %v", err)
+	}
+	if _, err := client.Modules.SaveArchive(ctx, "acme", "mismatch", "aws", "1.0.0", filepath.Join(dir, "mismatch.tar.gz")); !errors.Is(err, registry.ErrChecksumMismatch) {
+		return fmt.Errorf("expected checksum mismatch when saving, got: %v", err)
+	}
+
+	// The destination must be empty
+	if _, err := client.Modules.DownloadArchive(ctx, "acme", "good", "aws", "1.0.0", dest); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for non-empty destination, got: %v", err)
+	}
+
+	// Failed extractions leave the destination empty, even for entries read before the
+	// failure
+	for name, want := range map[string]error{
+		"mismatch": registry.ErrChecksumMismatch,
+		"tampered": registry.ErrChecksumMismatch,
+		"evil":     registry.ErrUnsafeArchivePath,
+	} {
+		failed := filepath.Join(dir, name)
+		if _, err := client.Modules.DownloadArchive(ctx, "acme", name, "aws", "1.0.0", failed); !errors.Is(err, want) {
+			return fmt.Errorf("%s: expected %v, got: %v", name, want, err)
+		}
+		entries, err := os.ReadDir(failed)
+		if err != nil {
+			return fmt.Errorf("%s: failed to read destination: %w", name, err)
+		}
+		if len(entries) != 0 {
+			return fmt.Errorf("%s: expected an empty destination after a failed extraction, found %s", name, entries[0].Name())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.tf")); !os.IsNotExist(err) {
+		return fmt.Errorf("archive entry escaped the destination directory")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, ".*.extract-*")); len(leftovers) != 0 {
+		return fmt.Errorf("extraction directories were left behind: %v", leftovers)
+	}
+
+	// In-memory consumption with the archive format from the source
+	archive, err := client.Modules.OpenArchive(ctx, "acme", "zip", "aws", "1.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archive.Close()
+
+	if err := AssertEqual(registry.ArchiveZip, archive.Format); err != nil {
+		return err
+	}
+	files, err := archive.Files()
+	if err != nil {
+		return fmt.Errorf("failed to read archive files: %w", err)
+	}
+	if len(files) != 1 || files[0].Path != "variables.tf" || string(files[0].Data) != "zip" {
+		return fmt.Errorf("unexpected archive files: %+v", files)
+	}
+
+	if _, err := client.Modules.OpenArchive(ctx, "acme", "gitlab", "aws", "1.0.0"); !errors.Is(err, registry.ErrUnsupportedSource) {
+		return fmt.Errorf("expected unsupported source error, got: %v", err)
+	}
+
+	if _, err := client.Modules.OpenArchive(ctx, "acme", "missing", "aws", "1.0.0"); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testDownloadPolicy(ctx context.Context) error {
+	verified := map[string]bool{"acme": true, "acme-legacy": true, "acme-labs": false}
+	tiers := map[string]string{"cloud": "partner", "hobby": "community"}
+	var downloads atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case len(parts) == 7 && parts[1] == "modules" && parts[6] == "download":
+			downloads.Add(1)
+			w.Header().Set("X-Terraform-Get", "/archives/net.tar.gz")
+			w.WriteHeader(http.StatusNoContent)
+		case len(parts) == 6 && parts[1] == "modules":
+			fmt.Fprintf(w, `{"id": "%s/net/aws/1.0.0", "namespace": %q, "name": "net", "provider": "aws", "version": "1.0.0", "verified": %t}`,
+				parts[2], parts[2], verified[parts[2]])
+		case len(parts) == 4 && parts[0] == "v2" && parts[1] == "providers":
+			fmt.Fprintf(w, `{"data": {"id": "1", "type": "providers", "attributes": {"namespace": %q, "name": %q, "tier": %q}}}`,
+				parts[2], parts[3], tiers[parts[3]])
+		case len(parts) == 8 && parts[1] == "providers" && parts[5] == "download":
+			downloads.Add(1)
+			fmt.Fprint(w, `{"os": "linux", "arch": "amd64", "filename": "p.zip", "download_url": "/p.zip"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var events []registry.DownloadAuditEvent
+	policy := registry.DownloadPolicy{
+		AllowedNamespaces: []string{"acme*"},
+		DeniedNamespaces:  []string{"acme-legacy"},
+		ProviderTiers:     []string{"official", "partner"},
+		VerifiedModules:   true,
+	}
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithDownloadPolicy(policy, func(event registry.DownloadAuditEvent) {
+			events = append(events, event)
+		}))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if _, err := client.Modules.Download(ctx, "acme", "net", "aws", "1.0.0"); err != nil {
+		return fmt.Errorf("allowed module download failed: %w", err)
+	}
+	if _, err := client.Providers.GetDownload(ctx, "acme", "cloud", "1.0.0", "linux", "amd64"); err != nil {
+		return fmt.Errorf("allowed provider download failed: %w", err)
+	}
+	if err := AssertEqual(int32(1), downloads.Load()); err != nil {
+		return fmt.Errorf("allowed download requests: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "terralense-policy-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// Every download and archive operation is gated before anything is requested
+	downloads.Store(0)
+	denials := []struct {
+		operation string
+		rule      string
+		call      func() error
+	}{
+		{"Modules.OpenArchive", registry.DownloadRuleAllowedNamespace, func() error {
+			_, err := client.Modules.OpenArchive(ctx, "evil", "net", "aws", "1.0.0")
+			return err
+		}},
+		{"Modules.DownloadArchive", registry.DownloadRuleDeniedNamespace, func() error {
+			_, err := client.Modules.DownloadArchive(ctx, "acme-legacy", "net", "aws", "1.0.0", filepath.Join(dir, "legacy"))
+			return err
+		}},
+		{"Modules.SaveArchive", registry.DownloadRuleDeniedNamespace, func() error {
+			_, err := client.Modules.SaveArchive(ctx, "ACME-Legacy", "net", "aws", "1.0.0", filepath.Join(dir, "legacy.tar.gz"))
+			return err
+		}},
+		{"Modules.Download", registry.DownloadRuleVerifiedModule, func() error {
+			_, err := client.Modules.Download(ctx, "acme-labs", "net", "aws", "1.0.0")
+			return err
+		}},
+		{"Providers.GetDownload", registry.DownloadRuleProviderTier, func() error {
+			_, err := client.Providers.GetDownload(ctx, "acme", "hobby", "1.0.0", "linux", "amd64")
+			return err
+		}},
+	}
+	for _, denial := range denials {
+		err := denial.call()
+		if !registry.IsDownloadDenied(err) {
+			return fmt.Errorf("%s: expected download denied error, got: %v", denial.operation, err)
+		}
+		var denied *registry.DownloadDeniedError
+		if !errors.As(err, &denied) {
+			return fmt.Errorf("%s: expected DownloadDeniedError, got %T", denial.operation, err)
+		}
+		if err := AssertEqual(denial.rule, denied.Rule); err != nil {
+			return fmt.Errorf("%s rule: %w", denial.operation, err)
+		}
+	}
+	if err := AssertEqual(int32(0), downloads.Load()); err != nil {
+		return fmt.Errorf("denied download requests: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "legacy")); !os.IsNotExist(err) {
+		return fmt.Errorf("denied archive download created its destination directory")
+	}
+
+	// Each denial is reported as an audit event
+	if err := AssertEqual(len(denials), len(events)); err != nil {
+		return fmt.Errorf("audit events: %w", err)
+	}
+	for i, denial := range denials {
+		if err := AssertEqual(denial.operation, events[i].Operation); err != nil {
+			return err
+		}
+		if events[i].Time.IsZero() || (events[i].Module == nil) == (events[i].Provider == nil) {
+			return fmt.Errorf("incomplete audit event: %+v", events[i])
+		}
+	}
+	if err := AssertEqual("acme/hobby@1.0.0", events[4].Provider.String()); err != nil {
+		return err
+	}
+
+	// Invalid policies are rejected when the client is created
+	_, err = registry.NewClient(registry.WithDownloadPolicy(registry.DownloadPolicy{ProviderTiers: []string{"gold"}}, nil))
+	if !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected invalid configuration for unknown tier, got: %v", err)
+	}
+
+	// Without a policy every download is allowed
+	open, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if _, err := open.Modules.Download(ctx, "acme-labs", "net", "aws", "1.0.0"); err != nil {
+		return fmt.Errorf("download without policy failed: %w", err)
+	}
+	return nil
+}
+
+func (s *ModuleTests) testPinDrift(ctx context.Context) error {
+	versions := map[string][]string{
+		"net":    {"1.0.0", "1.0.1", "1.0.2", "1.1.0"},
+		"old":    {"1.0.0"},
+		"gitlab": {"1.0.0", "1.0.1"},
+	}
+	sources := map[string]string{
+		"net/1.0.0":    "https://github.com/acme/terraform-aws-net",
+		"net/1.1.0":    "https://github.com/acme-platform/terraform-aws-net",
+		"gitlab/1.0.0": "https://gitlab.com/acme/terraform-aws-gitlab",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case r.URL.Path == "/repos/acme/terraform-aws-net/security-advisories":
+			fmt.Fprint(w, `[
+				{"ghsa_id": "GHSA-1111-2222-3333", "severity": "high", "vulnerabilities": [{"vulnerable_version_range": "< 1.0.2", "patched_versions": "1.0.2"}]},
+				{"ghsa_id": "GHSA-4444-5555-6666", "severity": "low", "vulnerabilities": [{"vulnerable_version_range": ">= 2.0.0", "patched_versions": "2.0.1"}]}
+			]`)
+		case len(parts) == 6 && parts[5] == "versions":
+			list, ok := versions[parts[3]]
+			if !ok {
+				fmt.Fprint(w, `{"modules": []}`)
+				return
+			}
+			var entries []map[string]string
+			for _, v := range list {
+				entries = append(entries, map[string]string{"version": v})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"modules": []map[string]any{{"versions": entries}}})
+		case len(parts) == 6:
+			source, ok := sources[parts[3]+"/"+parts[5]]
+			if !ok {
+				source = sources[parts[3]+"/1.0.0"]
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": strings.Join(parts[2:], "/"), "namespace": "acme", "name": parts[3], "provider": "aws",
+				"version": parts[5], "source": source,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithGitHubAPI(server.URL, ""),
+		registry.WithLogger(s.logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	pin := func(name, version string) registry.ModulePin {
+		return registry.ModulePin{Module: registry.ModuleRef{Namespace: "acme", Name: name, Provider: "aws", Version: version}}
+	}
+	pins := []registry.ModulePin{pin("net", "1.0.0"), pin("gone", "1.0.0"), pin("old", "0.9.0"), pin("gitlab", "1.0.0")}
+
+	warnCtx, warnings := registry.WithWarnings(ctx)
+	report, err := client.Modules.DetectDrift(warnCtx, pins)
+	if err != nil {
+		return fmt.Errorf("failed to detect drift: %w", err)
+	}
+	if err := AssertEqual(4, report.Checked); err != nil {
+		return err
+	}
+
+	var found []string
+	for _, drift := range report.Drifts {
+		found = append(found, fmt.Sprintf("%s %s %s", drift.Pin.Module.Name, drift.Kind, drift.Replacement))
+	}
+	want := []string{
+		"net source_moved ",
+		"net security_patch 1.0.2",
+		"gone missing ",
+		"old missing 1.0.0",
+	}
+	if err := AssertEqual(strings.Join(want, "\n"), strings.Join(found, "\n")); err != nil {
+		return err
+	}
+	if err := AssertEqual("GHSA-1111-2222-3333", report.Drifts[1].Advisories[0].ID); err != nil {
+		return err
+	}
+
+	// The GitLab-hosted module has a newer patch but its advisories cannot be checked
+	list := warnings.List()
+	if len(list) != 1 || list[0].Code != registry.WarningSkippedAdvisoryCheck {
+		return fmt.Errorf("expected one skipped advisory check warning, got: %+v", list)
+	}
+
+	// A recorded source is compared with the pinned version's source
+	moved := pin("gitlab", "1.0.0")
+	moved.Source = "https://github.com/acme/terraform-aws-gitlab"
+	report, err = client.Modules.DetectDrift(ctx, []registry.ModulePin{moved})
+	if err != nil {
+		return fmt.Errorf("failed to detect drift: %w", err)
+	}
+	if len(report.Drifts) != 1 || report.Drifts[0].Source != sources["gitlab/1.0.0"] {
+		return fmt.Errorf("expected the recorded source to be reported as moved, got: %+v", report.Drifts)
+	}
+
+	if _, err := client.Modules.DetectDrift(ctx, []registry.ModulePin{pin("net", "latest")}); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for an unpinned version, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testExamplesCorpus(ctx context.Context) error {
+	network := "module \"vpc\" {\n  source = \"acme/net/aws\"\n}"
+	bucket := "resource \"widget_bucket\" \"logs\" {\n  name = \"logs\"\n}"
+
+	// The examples README repeats the root example with tabs, CRLF line endings and
+	// extra indentation, and the provider doc repeats the bucket example
+	rootReadme := "# Network\n\n```hcl\n" + network + "\n```\n"
+	exampleReadme := "# Basic\n\n```hcl\n\tmodule \"vpc\" {\r\n\t  source = \"acme/net/aws\"   \r\n\t}\r\n```\n\n" +
+		"```terraform\n" + bucket + "\n\n\n```\n\n```hcl\nvariable \"ignored\" {}\n```\n"
+	bucketDoc := "# widget_bucket\n\n## Example Usage\n\n```terraform\n" + bucket + "\n```\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/acme/net/aws/1.0.0":
+			json.NewEncoder(w).Encode(map[string]any{
+				"id": "acme/net/aws/1.0.0", "namespace": "acme", "name": "net", "provider": "aws", "version": "1.0.0",
+				"root":     map[string]any{"path": "", "readme": rootReadme},
+				"examples": []map[string]any{{"path": "examples/basic", "name": "basic", "readme": exampleReadme}},
+			})
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}},
+				"included": [{"type": "provider-versions", "id": "pv1", "attributes": {"version": "2.0.0"}}]}`)
+		case "/v2/provider-docs":
+			if r.URL.Query().Get("filter[category]") == "resources" {
+				fmt.Fprint(w, `{"data": [{"id": "d1"}]}`)
+			} else {
+				fmt.Fprint(w, `{"data": [{"id": "d2"}]}`)
+			}
+		case "/v2/provider-docs/d1":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "d1", "attributes": map[string]any{
+				"category": "resources", "slug": "bucket", "path": "website/docs/r/bucket.html.markdown", "content": bucketDoc,
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// The missing data source doc is reported alongside the corpus
+	modules := []registry.ModuleRef{{Namespace: "acme", Name: "net", Provider: "aws", Version: "1.0.0"}}
+	providers := []registry.ProviderRef{{Namespace: "example", Name: "widget", Version: "2.0.0"}}
+	dataset, err := corpus.Build(ctx, client, modules, providers, nil)
+	if !registry.IsNotFound(err) || dataset == nil {
+		return fmt.Errorf("expected not found for the missing doc, got: %v", err)
+	}
+
+	examples := dataset.Examples()
+	if err := AssertEqual(2, len(examples)); err != nil {
+		return err
+	}
+	if err := AssertEqual(network, examples[0].Code); err != nil {
+		return err
+	}
+	if err := AssertEqual(bucket, examples[1].Code); err != nil {
+		return err
+	}
+
+	var files []string
+	for _, example := range examples {
+		for _, source := range example.Sources {
+			files = append(files, fmt.Sprintf("%s %s@%s %s", source.Kind, source.Address, source.Version, source.File))
+		}
+	}
+	want := []string{
+		"module acme/net/aws@1.0.0 README.md",
+		"module acme/net/aws@1.0.0 examples/basic/README.md",
+		"module acme/net/aws@1.0.0 examples/basic/README.md",
+		"provider example/widget@2.0.0 website/docs/r/bucket.html.markdown",
+	}
+	if err := AssertEqual(strings.Join(want, "\n"), strings.Join(files, "\n")); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := dataset.WriteJSONL(&buf); err != nil {
+		return fmt.Errorf("failed to write corpus: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if err := AssertEqual(2, len(lines)); err != nil {
+		return err
+	}
+	var decoded corpus.Example
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		return fmt.Errorf("invalid JSON line: %w", err)
+	}
+	if err := AssertEqual(examples[1].ID, decoded.ID); err != nil {
+		return err
+	}
+
+	// A valid-only corpus rejects unparseable examples, and a validator rejects more
+	source := corpus.Provenance{Kind: corpus.KindModule, Address: "acme/net/aws", Version: "1.0.0", File: "README.md"}
+	checked := corpus.NewValidOnly()
+	checked.Add(network, source)
+	checked.Add("module \"vpc\" {\n  ...\n}", source)
+	checked.Add(bucket, source)
+	if err := AssertEqual(2, checked.Len()); err != nil {
+		return err
+	}
+	rejected := checked.Rejected()
+	if err := AssertEqual(1, len(rejected)); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, rejected[0].Errors[0].Line); err != nil {
+		return err
+	}
+
+	validator := func(ctx context.Context, code string) ([]registry.HCLDiagnostic, error) {
+		if strings.Contains(code, "widget_bucket") {
+			return []registry.HCLDiagnostic{{Summary: "Invalid resource type", Line: 1, Column: 10}}, nil
+		}
+		return nil, nil
+	}
+	if err := checked.Check(ctx, validator); err != nil {
+		return fmt.Errorf("failed to check corpus: %w", err)
+	}
+	if err := AssertEqual(1, checked.Len()); err != nil {
+		return err
+	}
+	if err := AssertEqual("1:10: Invalid resource type", checked.Rejected()[1].Errors[0].String()); err != nil {
+		return err
+	}
+
+	dataset, _ = corpus.Build(ctx, client, modules, providers, &corpus.Options{ValidOnly: true, Validator: validator})
+	if err := AssertEqual(1, dataset.Len()); err != nil {
+		return err
+	}
+	if err := AssertEqual(bucket, dataset.Rejected()[0].Example.Code); err != nil {
+		return err
+	}
+
+	if _, err := corpus.Build(ctx, client, nil, nil, nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error without sources, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testDependencyGraph(ctx context.Context) error {
+	versions := map[string][]string{
+		"app":   {"1.0.0"},
+		"net":   {"1.0.0", "1.2.0", "2.0.0"},
+		"store": {"1.0.0"},
+	}
+	details := map[string]string{
+		"app": `{
+			"root": {
+				"dependencies": [
+					{"name": "network", "source": "acme/net/aws", "version": "~> 1.0"},
+					{"name": "db", "source": "./modules/db"},
+					{"name": "legacy", "source": "git::https://example.com/legacy.git"}
+				],
+				"provider_dependencies": [{"name": "aws", "namespace": "hashicorp", "source": "hashicorp/aws", "version": ">= 5.0"}]
+			},
+			"submodules": [{"path": "modules/db", "dependencies": [{"name": "store", "source": "acme/store/aws"}]}]
+		}`,
+		"net":   `{"root": {"dependencies": [{"name": "app", "source": "acme/app/aws", "version": "1.0.0"}]}}`,
+		"store": `{"root": {"dependencies": [{"name": "ghost", "source": "acme/ghost/aws"}]}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		switch {
+		case len(parts) == 6 && parts[5] == "versions":
+			var entries []map[string]string
+			for _, v := range versions[parts[3]] {
+				entries = append(entries, map[string]string{"version": v})
+			}
+			if entries == nil {
+				fmt.Fprint(w, `{"modules": []}`)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"modules": []map[string]any{{"versions": entries}}})
+		case len(parts) == 6 && details[parts[3]] != "":
+			var module map[string]any
+			json.Unmarshal([]byte(details[parts[3]]), &module)
+			module["id"] = strings.Join(parts[2:], "/")
+			module["namespace"], module["name"], module["provider"], module["version"] = parts[2], parts[3], parts[4], parts[5]
+			json.NewEncoder(w).Encode(module)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	graph, err := client.Modules.BuildDependencyGraph(ctx, "acme", "app", "aws", "1.0.0", 0)
+	if graph == nil {
+		return fmt.Errorf("failed to build graph: %w", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "acme/ghost/aws") {
+		return fmt.Errorf("expected the unresolvable ghost module to be reported, got %v", err)
+	}
+
+	var nodes []string
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, fmt.Sprintf("%d %s", node.Depth, node.ID))
+	}
+	want := []string{
+		"0 module:acme/app/aws@1.0.0",
+		"1 external:git::https://example.com/legacy.git",
+		"1 module:acme/net/aws@1.2.0",
+		"1 module:acme/store/aws@1.0.0",
+		"1 provider:hashicorp/aws",
+		"2 module:acme/ghost/aws",
+	}
+	if err := AssertEqual(strings.Join(want, "\n"), strings.Join(nodes, "\n")); err != nil {
+		return fmt.Errorf("nodes: %w", err)
+	}
+
+	if ghost, _ := graph.Node("module:acme/ghost/aws"); ghost.Error == "" {
+		return fmt.Errorf("expected the ghost module node to carry its error")
+	}
+
+	// The submodule's dependency belongs to the root module
+	var edges []string
+	for _, edge := range graph.Edges {
+		edges = append(edges, fmt.Sprintf("%s -> %s (%s %s)", edge.From, edge.To, edge.Name, edge.Constraint))
+	}
+	if !strings.Contains(strings.Join(edges, "\n"), "module:acme/app/aws@1.0.0 -> module:acme/store/aws@1.0.0 (store )") {
+		return fmt.Errorf("expected the db submodule's store dependency on the root module, got:\n%s", strings.Join(edges, "\n"))
+	}
+
+	if err := AssertEqual(1, len(graph.Cycles)); err != nil {
+		return fmt.Errorf("cycles: %w", err)
+	}
+	cycle := strings.Join(graph.Cycles[0], " -> ")
+	if err := AssertEqual("module:acme/app/aws@1.0.0 -> module:acme/net/aws@1.2.0 -> module:acme/app/aws@1.0.0", cycle); err != nil {
+		return err
+	}
+
+	dot := graph.DOT()
+	if !strings.Contains(dot, `"module:acme/app/aws@1.0.0" -> "module:acme/net/aws@1.2.0" [label="~> 1.0", color=red];`) {
+		return fmt.Errorf("expected the cycle edge in DOT output:\n%s", dot)
+	}
+
+	data, err := graph.JSON()
+	if err != nil {
+		return err
+	}
+	var decoded registry.DependencyGraph
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("invalid graph JSON: %w", err)
+	}
+	if err := AssertEqual(len(graph.Edges), len(decoded.Edges)); err != nil {
+		return err
+	}
+
+	// With a depth of one, direct dependencies are resolved but not expanded
+	shallow, err := client.Modules.BuildDependencyGraph(ctx, "acme", "app", "aws", "1.0.0", 1)
+	if err != nil {
+		return fmt.Errorf("failed to build shallow graph: %w", err)
+	}
+	if err := AssertEqual(5, len(shallow.Nodes)); err != nil {
+		return fmt.Errorf("shallow nodes: %w", err)
+	}
+	if net, _ := shallow.Node("module:acme/net/aws@1.2.0"); !net.Truncated {
+		return fmt.Errorf("expected the net module to be truncated at depth 1")
+	}
+	if err := AssertEqual(0, len(shallow.Cycles)); err != nil {
+		return err
+	}
+
+	if _, err := client.Modules.BuildDependencyGraph(ctx, "acme", "app", "aws", "1.0.0", -1); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected a validation error for a negative depth, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ModuleTests) testGetManyModules(ctx context.Context) error {
+	var hits, slowHits atomic.Int32
+	slowStarted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/example/slow/aws/1.0.0":
+			// The first request stays in flight until its caller gives up
+			if slowHits.Add(1) == 1 {
+				close(slowStarted)
+				<-r.Context().Done()
+				return
+			}
+			fmt.Fprint(w, `{"id": "example/slow/aws/1.0.0", "namespace": "example", "name": "slow", "provider": "aws", "version": "1.0.0"}`)
+		case "/v1/modules/example/network/aws/1.0.0":
+			hits.Add(1)
+			// Keep the request in flight so that concurrent batches overlap
+			time.Sleep(100 * time.Millisecond)
+			fmt.Fprint(w, `{"id": "example/network/aws/1.0.0", "namespace": "example", "name": "network", "provider": "aws", "version": "1.0.0"}`)
+		case "/v1/modules/example/network/aws/versions":
+			fmt.Fprint(w, `{"modules": [{"versions": [{"version": "0.9.0"}, {"version": "1.0.0"}]}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ids := []string{
+		"example/network/aws/1.0.0",
+		"example/network/aws/1.0.0",
+		"example/network/aws",
+		"example/missing/aws/1.0.0",
+		"not-a-valid-id",
+	}
+
+	// Two concurrent batches share the in-flight request for the same version
+	var wg sync.WaitGroup
+	batches := make([]map[string]registry.BatchResult[*registry.ModuleDetails], 2)
+	errs := make([]error, 2)
+	for i := range batches {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			batches[i], errs[i] = client.Modules.GetMany(ctx, ids)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, batch := range batches {
+		if errs[i] != nil {
+			return fmt.Errorf("failed to get modules: %w", errs[i])
+		}
+		if err := AssertEqual(4, len(batch)); err != nil {
+			return fmt.Errorf("result count: %w", err)
+		}
+		if result := batch["example/network/aws/1.0.0"]; result.Err != nil || result.Value.Version != "1.0.0" {
+			return fmt.Errorf("unexpected result for pinned version: %+v", result)
+		}
+		if result := batch["example/network/aws"]; result.Err != nil || result.Value.Version != "1.0.0" {
+			return fmt.Errorf("unexpected result for latest version: %+v", result)
+		}
+		if !registry.IsNotFound(batch["example/missing/aws/1.0.0"].Err) {
+			return fmt.Errorf("expected not found error, got %v", batch["example/missing/aws/1.0.0"].Err)
+		}
+		if !registry.IsValidationError(batch["not-a-valid-id"].Err) {
+			return fmt.Errorf("expected validation error, got %v", batch["not-a-valid-id"].Err)
+		}
+	}
+
+	// One request per distinct key in flight: the pinned ID and the latest lookup
+	if err := AssertTrue(hits.Load() <= 2, fmt.Sprintf("expected coalesced requests, got %d", hits.Load())); err != nil {
+		return err
+	}
+
+	// A caller waiting on a cancelled caller's request makes the request again
+	const slow = "example/slow/aws/1.0.0"
+	leaderCtx, cancelLeader := context.WithCancel(ctx)
+	defer cancelLeader()
+	leader := make(chan registry.BatchResult[*registry.ModuleDetails], 1)
+	go func() {
+		batch, _ := client.Modules.GetMany(leaderCtx, []string{slow})
+		leader <- batch[slow]
+	}()
+	<-slowStarted
+
+	follower := make(chan registry.BatchResult[*registry.ModuleDetails], 1)
+	go func() {
+		batch, _ := client.Modules.GetMany(ctx, []string{slow})
+		follower <- batch[slow]
+	}()
+	// Give the follower time to join the request in flight
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+
+	if result := <-leader; !errors.Is(result.Err, context.Canceled) {
+		return fmt.Errorf("expected the cancelled caller to fail with context.Canceled, got %v", result.Err)
+	}
+	if result := <-follower; result.Err != nil || result.Value.Version != "1.0.0" {
+		return fmt.Errorf("expected the waiting caller to make the request itself, got %+v", result)
+	}
+	if err := AssertEqual(int32(2), slowHits.Load()); err != nil {
+		return fmt.Errorf("slow module requests: %w", err)
+	}
+
+	if _, err := client.Modules.GetMany(ctx, nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty ID list, got: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,457 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxMockPageRequests caps the requests a pagination mock serves, so that a client
+// paging forever fails the test instead of hanging it
+const maxMockPageRequests = 100
+
+// PaginationTests exercises auto-pagination against misbehaving registries: empty pages,
+// missing next markers, inconsistent totals and items repeated across pages
+type PaginationTests struct {
+	*BaseTestSuite
+}
+
+// NewPaginationTests creates a new pagination test suite
+func NewPaginationTests(client *registry.Client, logger *logrus.Logger) TestSuite {
+	suite := &PaginationTests{
+		BaseTestSuite: NewBaseTestSuite("Pagination", client, logger),
+	}
+
+	suite.setupTests()
+	return suite
+}
+
+func (s *PaginationTests) setupTests() {
+	s.AddTest("Empty Pages", "Test empty pages between full pages are skipped", s.testEmptyPages)
+	s.AddTest("Endless Empty Pages", "Test paging stops when empty pages keep advertising more", s.testEndlessEmptyPages)
+	s.AddTest("Missing Next Markers", "Test pages without next markers end or fall back to links and totals", s.testMissingNextMarkers)
+	s.AddTest("Inconsistent Totals", "Test next markers win over wrong total counts", s.testInconsistentTotals)
+	s.AddTest("Duplicate Items", "Test items repeated across pages are yielded once", s.testDuplicateItems)
+	s.AddTest("Ignored Offset", "Test paging stops when the registry serves the same page for every offset", s.testIgnoredOffset)
+}
+
+// mockPageServer is a mock registry serving list pages from a handler, counting requests
+type mockPageServer struct {
+	*httptest.Server
+	client   *registry.Client
+	requests atomic.Int32
+}
+
+// newMockPageServer starts a mock registry whose list pages are written by serve, and a
+// client for it
+func (s *PaginationTests) newMockPageServer(serve func(w http.ResponseWriter, r *http.Request)) (*mockPageServer, error) {
+	mock := &mockPageServer{}
+	mock.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mock.requests.Add(1) > maxMockPageRequests {
+			http.Error(w, `{"errors": ["too many page requests"]}`, http.StatusInternalServerError)
+			return
+		}
+		serve(w, r)
+	}))
+
+	client, err := registry.NewClient(registry.WithBaseURL(mock.URL), registry.WithLogger(s.logger),
+		registry.WithRateLimit(1000, time.Second))
+	if err != nil {
+		mock.Close()
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	mock.client = client
+	return mock, nil
+}
+
+// modulePage returns a v1 module list page; next and nextURL are omitted when empty
+func modulePage(offset int, next, nextURL string, names ...string) string {
+	modules := make([]string, len(names))
+	for i, name := range names {
+		modules[i] = fmt.Sprintf(`{"id": "acme/%s/aws/1.0.0", "namespace": "acme", "name": "%s", "provider": "aws", "version": "1.0.0"}`, name, name)
+	}
+
+	meta := fmt.Sprintf(`"limit": 2, "current_offset": %d`, offset)
+	if next != "" {
+		meta += `, "next_offset": ` + next
+	}
+	if nextURL != "" {
+		meta += fmt.Sprintf(`, "next_url": %q`, nextURL)
+	}
+	return fmt.Sprintf(`{"meta": {%s}, "modules": [%s]}`, meta, strings.Join(modules, ","))
+}
+
+// dataPage returns a v2 provider or policy list page; zero next, totalPages and
+// totalCount are omitted
+func dataPage(current, next, totalPages, totalCount int, ids ...string) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = fmt.Sprintf(`{"id": %q, "attributes": {"name": %q}}`, id, id)
+	}
+
+	pagination := fmt.Sprintf(`"page-size": 2, "current-page": %d`, current)
+	for name, value := range map[string]int{"next-page": next, "total-pages": totalPages, "total-count": totalCount} {
+		if value != 0 {
+			pagination += fmt.Sprintf(`, %q: %d`, name, value)
+		}
+	}
+	return fmt.Sprintf(`{"data": [%s], "meta": {"pagination": {%s}}}`, strings.Join(items, ","), pagination)
+}
+
+// requestedPage returns the v2 page number of a request, 1 when unset
+func requestedPage(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	if err != nil {
+		return 1
+	}
+	return page
+}
+
+// moduleNames returns the names of modules
+func moduleNames(modules []registry.Module) []string {
+	names := make([]string, len(modules))
+	for i, module := range modules {
+		names[i] = module.Name
+	}
+	return names
+}
+
+// providerIDs returns the IDs of providers
+func providerIDs(providers []registry.ProviderData) []string {
+	ids := make([]string, len(providers))
+	for i, provider := range providers {
+		ids[i] = provider.ID
+	}
+	return ids
+}
+
+func (s *PaginationTests) testEmptyPages(ctx context.Context) error {
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		switch {
+		case r.URL.Path == "/v1/modules" && offset == 0:
+			fmt.Fprint(w, modulePage(0, "2", "", "a", "b"))
+		case r.URL.Path == "/v1/modules" && offset == 2:
+			// Private registries filtering after paging return empty pages mid-list
+			fmt.Fprint(w, modulePage(2, "4", ""))
+		case r.URL.Path == "/v1/modules" && offset == 4:
+			fmt.Fprint(w, modulePage(4, "6", ""))
+		case r.URL.Path == "/v1/modules" && offset == 6:
+			fmt.Fprint(w, modulePage(6, "", "", "c"))
+		case r.URL.Path == "/v2/providers":
+			page := requestedPage(r)
+			switch page {
+			case 1:
+				fmt.Fprint(w, dataPage(1, 2, 3, 3, "p1", "p2"))
+			case 2:
+				fmt.Fprint(w, dataPage(2, 3, 3, 3))
+			default:
+				fmt.Fprint(w, dataPage(3, 0, 3, 3, "p3"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	modules, err := mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list modules: %w", err)
+	}
+	if err := AssertEqual([]string{"a", "b", "c"}, moduleNames(modules)); err != nil {
+		return fmt.Errorf("modules across empty pages: %w", err)
+	}
+	if err := AssertEqual(int32(4), mock.requests.Load()); err != nil {
+		return fmt.Errorf("module page requests: %w", err)
+	}
+
+	providers, err := mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{PageSize: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+	if err := AssertEqual([]string{"p1", "p2", "p3"}, providerIDs(providers)); err != nil {
+		return fmt.Errorf("providers across empty pages: %w", err)
+	}
+
+	// An empty first and only page is an empty list, not an error
+	empty, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, modulePage(0, "", ""))
+	})
+	if err != nil {
+		return err
+	}
+	defer empty.Close()
+
+	modules, err = empty.client.Modules.ListAll(ctx, nil).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list empty modules: %w", err)
+	}
+	return AssertEqual(0, len(modules))
+}
+
+func (s *PaginationTests) testEndlessEmptyPages(ctx context.Context) error {
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules":
+			// Every page is empty and advertises another one
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			fmt.Fprint(w, modulePage(offset, strconv.Itoa(offset+2), ""))
+		case "/v2/policies":
+			page := requestedPage(r)
+			fmt.Fprint(w, dataPage(page, page+1, 0, 0))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	modules, err := mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if !errors.Is(err, registry.ErrPaginationStalled) {
+		return fmt.Errorf("expected pagination stalled error for modules, got %v", err)
+	}
+	if err := AssertEqual(0, len(modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(registry.DefaultIteratorMaxEmptyPages), mock.requests.Load()); err != nil {
+		return fmt.Errorf("module page requests: %w", err)
+	}
+
+	mock.requests.Store(0)
+	_, err = mock.client.Policies.ListAll(ctx, nil).Collect()
+	if !errors.Is(err, registry.ErrPaginationStalled) {
+		return fmt.Errorf("expected pagination stalled error for policies, got %v", err)
+	}
+	return AssertEqual(int32(registry.DefaultIteratorMaxEmptyPages), mock.requests.Load())
+}
+
+func (s *PaginationTests) testMissingNextMarkers(ctx context.Context) error {
+	var mode atomic.Value
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		page := requestedPage(r)
+		switch mode.Load() {
+		case "next-url":
+			// Only the link to the next page is sent
+			if offset == 0 {
+				fmt.Fprint(w, modulePage(0, "", "/v1/modules?limit=2&offset=2", "a", "b"))
+				return
+			}
+			fmt.Fprint(w, modulePage(2, "", "", "c"))
+		case "none":
+			// A full page without any marker ends the list
+			fmt.Fprint(w, modulePage(offset, "", "", "a", "b"))
+		case "total-pages":
+			// Only total-pages tells that more pages follow
+			ids := map[int][]string{1: {"p1", "p2"}, 2: {"p3", "p4"}, 3: {"p5"}}[page]
+			fmt.Fprint(w, dataPage(page, 0, 3, 5, ids...))
+		case "backwards":
+			// The next page points back to the first page
+			fmt.Fprint(w, dataPage(page, 1, 0, 0, fmt.Sprintf("p%d", page)))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	mode.Store("next-url")
+	modules, err := mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list modules by next URL: %w", err)
+	}
+	if err := AssertEqual([]string{"a", "b", "c"}, moduleNames(modules)); err != nil {
+		return fmt.Errorf("modules by next URL: %w", err)
+	}
+
+	mode.Store("none")
+	mock.requests.Store(0)
+	modules, err = mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list modules without markers: %w", err)
+	}
+	if err := AssertEqual(2, len(modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(1), mock.requests.Load()); err != nil {
+		return fmt.Errorf("requests without markers: %w", err)
+	}
+
+	mode.Store("total-pages")
+	providers, err := mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{PageSize: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list providers by total pages: %w", err)
+	}
+	if err := AssertEqual([]string{"p1", "p2", "p3", "p4", "p5"}, providerIDs(providers)); err != nil {
+		return fmt.Errorf("providers by total pages: %w", err)
+	}
+
+	mode.Store("backwards")
+	mock.requests.Store(0)
+	providers, err = mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{Page: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list providers with a backwards next page: %w", err)
+	}
+	if err := AssertEqual([]string{"p2"}, providerIDs(providers)); err != nil {
+		return err
+	}
+	return AssertEqual(int32(1), mock.requests.Load())
+}
+
+func (s *PaginationTests) testInconsistentTotals(ctx context.Context) error {
+	var mode atomic.Value
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		page := requestedPage(r)
+		switch mode.Load() {
+		case "undercount":
+			// The totals claim one page of two items, but next-page keeps going
+			ids := map[int][]string{1: {"p1", "p2"}, 2: {"p3", "p4"}, 3: {"p5"}}[page]
+			next := page + 1
+			if page == 3 {
+				next = 0
+			}
+			fmt.Fprint(w, dataPage(page, next, 1, 2, ids...))
+		case "overcount":
+			// The totals claim ten pages, but the list ends on page two
+			ids := map[int][]string{1: {"p1", "p2"}, 2: {"p3"}}[page]
+			fmt.Fprint(w, dataPage(page, 0, 10, 20, ids...))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	mode.Store("undercount")
+	providers, err := mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{PageSize: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list undercounted providers: %w", err)
+	}
+	if err := AssertEqual([]string{"p1", "p2", "p3", "p4", "p5"}, providerIDs(providers)); err != nil {
+		return fmt.Errorf("undercounted providers: %w", err)
+	}
+
+	mode.Store("overcount")
+	mock.requests.Store(0)
+	providers, err = mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{PageSize: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list overcounted providers: %w", err)
+	}
+	if err := AssertEqual([]string{"p1", "p2", "p3"}, providerIDs(providers)); err != nil {
+		return fmt.Errorf("overcounted providers: %w", err)
+	}
+	// The empty page after the last one ends the list instead of walking all ten pages
+	return AssertEqual(int32(3), mock.requests.Load())
+}
+
+func (s *PaginationTests) testDuplicateItems(ctx context.Context) error {
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		switch r.URL.Path {
+		case "/v1/modules":
+			// A module published mid-iteration shifts later pages by one
+			switch offset {
+			case 0:
+				fmt.Fprint(w, modulePage(0, "2", "", "a", "b"))
+			case 2:
+				fmt.Fprint(w, modulePage(2, "4", "", "b", "c"))
+			default:
+				fmt.Fprint(w, modulePage(4, "", "", "c", "d"))
+			}
+		case "/v2/policies":
+			page := requestedPage(r)
+			switch page {
+			case 1:
+				fmt.Fprint(w, dataPage(1, 2, 2, 4, "x", "y", "x"))
+			default:
+				fmt.Fprint(w, dataPage(2, 0, 2, 4, "y", "z"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	modules, err := mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list modules: %w", err)
+	}
+	if err := AssertEqual([]string{"a", "b", "c", "d"}, moduleNames(modules)); err != nil {
+		return fmt.Errorf("shifted module pages: %w", err)
+	}
+
+	policies, err := mock.client.Policies.ListAll(ctx, nil).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+	ids := make([]string, len(policies))
+	for i, policy := range policies {
+		ids[i] = policy.ID
+	}
+	if err := AssertEqual([]string{"x", "y", "z"}, ids); err != nil {
+		return fmt.Errorf("duplicate policies: %w", err)
+	}
+
+	// The max items safeguard counts each item once
+	modules, err = mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).SetMaxItems(4).Collect()
+	if err != nil {
+		return fmt.Errorf("unexpected error at exact limit with duplicates: %w", err)
+	}
+	return AssertEqual(4, len(modules))
+}
+
+func (s *PaginationTests) testIgnoredOffset(ctx context.Context) error {
+	mock, err := s.newMockPageServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules":
+			// The offset is ignored, but next_offset still advances
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			fmt.Fprint(w, modulePage(offset, strconv.Itoa(offset+2), "", "a", "b"))
+		case "/v2/providers":
+			page := requestedPage(r)
+			fmt.Fprint(w, dataPage(page, page+1, 0, 0, "p1", "p2"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer mock.Close()
+
+	// The first page is kept and the repeats end the iteration instead of looping
+	modules, err := mock.client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if !errors.Is(err, registry.ErrPaginationStalled) {
+		return fmt.Errorf("expected pagination stalled error for modules, got %v", err)
+	}
+	if err := AssertEqual([]string{"a", "b"}, moduleNames(modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(1+registry.DefaultIteratorMaxEmptyPages), mock.requests.Load()); err != nil {
+		return fmt.Errorf("module page requests: %w", err)
+	}
+
+	providers, err := mock.client.Providers.ListAll(ctx, &registry.ProviderListOptions{PageSize: 2}).Collect()
+	if !errors.Is(err, registry.ErrPaginationStalled) {
+		return fmt.Errorf("expected pagination stalled error for providers, got %v", err)
+	}
+	return AssertEqual([]string{"p1", "p2"}, providerIDs(providers))
+}
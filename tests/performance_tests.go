@@ -1,12 +1,28 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"testing"
 	"time"
 
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registry/retryable"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
 
 	"github.com/sirupsen/logrus"
 )
@@ -34,6 +50,23 @@ func (s *PerformanceTests) setupTests() {
 	s.AddTest("Pagination Performance", "Test pagination efficiency", s.testPaginationPerformance)
 	s.AddTest("Search Performance", "Test search response times", s.testSearchPerformance)
 	s.AddTest("Cache Behavior", "Test caching behavior if implemented", s.testCacheBehavior)
+	s.AddTest("Priority Scheduler", "Test prefetch cancellation by interactive requests", s.testPriorityScheduler)
+	s.AddTest("Wait Events", "Test retry and rate limit wait notifications", s.testWaitEvents)
+	s.AddTest("Raw Capture", "Test capturing raw JSON payloads of typed calls", s.testRawCapture)
+	s.AddTest("Change Detection", "Test HasChanged takes one conditional request per check", s.testChangeDetection)
+	s.AddTest("Retry Middleware", "Test the built-in and go-retryablehttp retry layers behave the same", s.testRetryMiddleware)
+	s.AddTest("Rate Budget", "Test test runner pacing and per-test API call counts", s.testRateBudget)
+	s.AddTest("Negative Cache", "Test caching of not-found lookups", s.testNegativeCache)
+	s.AddTest("Response Cache", "Test response caching, revalidation and cache backends", s.testResponseCache)
+	s.AddTest("Blob Cache", "Test sharing the response cache through S3 and GCS buckets", s.testBlobCache)
+	s.AddTest("List Iterators", "Test lazily paging list endpoints with iterators", s.testListIterators)
+	s.AddTest("Best Effort", "Test partial results when the best effort budget expires", s.testBestEffort)
+	s.AddTest("Validation Benchmark", "Benchmark the shared validation rules from concurrent goroutines", s.testValidationBenchmark)
+	s.AddTest("HTTP Cache", "Test the HTTP cache middleware honors Cache-Control", s.testHTTPCache)
+	s.AddTest("Adaptive Rate Limit", "Test rate limiting driven by registry headers and per-host limits", s.testAdaptiveRateLimit)
+	s.AddTest("Download Manager", "Test resumable, parallel and checksum-verified downloads", s.testDownloadManager)
+	s.AddTest("Request Fingerprints", "Test top request patterns with repeats, cache hits and rate limits", s.testRequestFingerprints)
+	s.AddTest("Warm Start", "Test exporting and importing client state", s.testWarmStart)
 }
 
 func (s *PerformanceTests) testResponseTime(ctx context.Context) error {
@@ -333,7 +366,7 @@ func (s *PerformanceTests) testSearchPerformance(ctx context.Context) error {
 
 func (s *PerformanceTests) testCacheBehavior(ctx context.Context) error {
 	// Test caching behavior by making repeated identical requests
-	// Note: The current implementation might not have caching
+	// Note: Response caching is opt-in (WithCache); see Response Cache for cached clients
 
 	// First request (cache miss)
 	start1 := time.Now()
@@ -388,3 +421,1715 @@ func (s *PerformanceTests) testCacheBehavior(ctx context.Context) error {
 
 	return nil
 }
+
+func (s *PerformanceTests) testBestEffort(ctx context.Context) error {
+	// Doc d2 and the "slow" query respond long after the budget expires
+	stall := func(r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}},
+				"included": [{"type": "provider-versions", "id": "pv1", "attributes": {"version": "1.0.0"}}]}`)
+		case "/v2/provider-docs":
+			if r.URL.Query().Get("filter[category]") == "resources" {
+				fmt.Fprint(w, `{"data": [{"id": "d1"}, {"id": "d2"}]}`)
+			} else {
+				fmt.Fprint(w, `{"data": []}`)
+			}
+		case "/v2/provider-docs/d1":
+			fmt.Fprint(w, `{"data": {"id": "d1", "attributes": {"category": "resources", "slug": "fast", "subcategory": "Core"}}}`)
+		case "/v2/provider-docs/d2":
+			stall(r)
+		case "/v1/modules/search":
+			if r.URL.Query().Get("q") == "slow" {
+				stall(r)
+				return
+			}
+			fmt.Fprint(w, `{"modules": [{"id": "acme/fast/aws/1.0.0", "namespace": "acme", "name": "fast", "provider": "aws", "version": "1.0.0"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		registry.WithBestEffort(300*time.Millisecond),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	start := time.Now()
+	summary, err := client.Providers.GetProviderResourceSummary(ctx, "example", "widget", "1.0.0")
+	var partial *registry.PartialResultError
+	if !errors.As(err, &partial) || summary == nil {
+		return fmt.Errorf("expected a partial summary, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		return fmt.Errorf("partial summary took %s", elapsed)
+	}
+	if err := AssertEqual(1, partial.Completed); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, partial.Total); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(summary.ResourcesBySubcategory["Core"])); err != nil {
+		return err
+	}
+
+	results, err := client.Modules.SearchExpanded(ctx, []registry.WeightedQuery{{Query: "fast"}, {Query: "slow"}})
+	if !registry.IsPartialResult(err) {
+		return fmt.Errorf("expected a partial search result, got: %v", err)
+	}
+	if err := AssertEqual(1, len(results)); err != nil {
+		return err
+	}
+
+	// Without a budget the caller's deadline fails the operation as before
+	plain, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	deadline, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	if _, err := plain.Providers.GetProviderResourceSummary(deadline, "example", "widget", "1.0.0"); err == nil || registry.IsPartialResult(err) {
+		return fmt.Errorf("expected the summary to fail on the caller's deadline, got: %v", err)
+	}
+
+	if _, err := registry.NewClient(registry.WithBestEffort(-time.Second)); err == nil {
+		return fmt.Errorf("expected an error for a negative best effort duration")
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testWarmStart(ctx context.Context) error {
+	// Populate the version index
+	versionID, err := s.client.Providers.GetVersionID(ctx, "hashicorp", "random", "latest")
+	if err != nil {
+		return fmt.Errorf("failed to get version ID: %w", err)
+	}
+
+	state, err := s.client.ExportState()
+	if err != nil {
+		return fmt.Errorf("failed to export state: %w", err)
+	}
+
+	fresh, err := registry.NewClient(registry.WithBaseURL(s.client.GetBaseURL()), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if err := fresh.ImportState(state); err != nil {
+		return fmt.Errorf("failed to import state: %w", err)
+	}
+
+	// The imported limiter must not report more tokens than the exporting client had
+	// plus whatever refilled in between
+	if fresh.GetRateLimiter().TokensRemaining() < s.client.GetRateLimiter().TokensRemaining()-1 {
+		s.logger.Warn("Imported rate limiter has fewer tokens than expected")
+	}
+
+	// Resolving the already indexed version must give the same ID
+	latest, err := fresh.Providers.GetLatest(ctx, "hashicorp", "random")
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	warmID, err := fresh.Providers.GetVersionID(ctx, "hashicorp", "random", latest.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get version ID after warm start: %w", err)
+	}
+	if err := AssertEqual(versionID, warmID); err != nil {
+		return fmt.Errorf("version ID mismatch: %w", err)
+	}
+
+	// Unknown sections and newer format versions must be tolerated
+	future := []byte(`{"format_version": 99, "sections": {"from_the_future": {}}}`)
+	if err := fresh.ImportState(future); err != nil {
+		return fmt.Errorf("forward-compatible import failed: %w", err)
+	}
+
+	if err := fresh.ImportState([]byte("not json")); !errors.Is(err, registry.ErrInvalidState) {
+		return fmt.Errorf("expected ErrInvalidState for malformed state, got: %v", err)
+	}
+
+	s.logger.Debugf("Exported %d bytes of client state", len(state))
+	return nil
+}
+
+func (s *PerformanceTests) testHTTPCache(ctx context.Context) error {
+	var requests, conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/v2/providers/example/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/v2/providers/example/revalidated":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"revalidated-1"`)
+			if r.Header.Get("If-None-Match") == `"revalidated-1"` {
+				conditional.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/v2/providers/example/expired":
+			w.Header().Set("Expires", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+		case "/v2/providers/example/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+		case "/v2/providers/example/private":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/v2/providers/example/")
+		fmt.Fprintf(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": %q}}}`, name)
+	}))
+	defer server.Close()
+
+	newClient := func(opts *registry.HTTPCacheOptions) (*registry.Client, error) {
+		return registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+			registry.WithHTTPCache(registry.NewMemoryCache(10), opts))
+	}
+	client, err := newClient(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// expectRequests gets a provider twice and checks how many requests reached the server
+	expectRequests := func(client *registry.Client, name string, want int32) error {
+		before := requests.Load()
+		for i := 0; i < 2; i++ {
+			provider, err := client.Providers.Get(ctx, "example", name)
+			if err != nil {
+				return fmt.Errorf("failed to get %s: %w", name, err)
+			}
+			if err := AssertEqual(name, provider.Attributes.Name); err != nil {
+				return err
+			}
+		}
+		if err := AssertEqual(want, requests.Load()-before); err != nil {
+			return fmt.Errorf("requests for %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := expectRequests(client, "fresh", 1); err != nil {
+		return err
+	}
+	// no-cache responses are stored but revalidated before each use
+	if err := expectRequests(client, "revalidated", 2); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(1), conditional.Load()); err != nil {
+		return fmt.Errorf("conditional requests: %w", err)
+	}
+	if err := expectRequests(client, "expired", 2); err != nil {
+		return err
+	}
+	if err := expectRequests(client, "nostore", 2); err != nil {
+		return err
+	}
+	if err := expectRequests(client, "private", 1); err != nil {
+		return err
+	}
+
+	// Shared caches do not store private responses
+	shared, err := newClient(&registry.HTTPCacheOptions{Shared: true})
+	if err != nil {
+		return fmt.Errorf("failed to create shared client: %w", err)
+	}
+	if err := expectRequests(shared, "private", 2); err != nil {
+		return err
+	}
+
+	// Bypassing the cache fetches a fresh copy
+	before := requests.Load()
+	if _, err := client.Providers.Get(registry.BypassCache(ctx), "example", "fresh"); err != nil {
+		return fmt.Errorf("failed to bypass cache: %w", err)
+	}
+	if err := AssertEqual(int32(1), requests.Load()-before); err != nil {
+		return fmt.Errorf("bypassed requests: %w", err)
+	}
+
+	// only-if-cached never contacts the server
+	httpClient := &http.Client{Transport: registry.HTTPCacheMiddleware(registry.NewMemoryCache(10), nil)(http.DefaultTransport)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/v2/providers/example/fresh", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Cache-Control", "only-if-cached")
+	before = requests.Load()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("only-if-cached request failed: %w", err)
+	}
+	resp.Body.Close()
+	if err := AssertEqual(http.StatusGatewayTimeout, resp.StatusCode); err != nil {
+		return fmt.Errorf("only-if-cached status: %w", err)
+	}
+	if err := AssertEqual(before, requests.Load()); err != nil {
+		return fmt.Errorf("only-if-cached requests: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testAdaptiveRateLimit(ctx context.Context) error {
+	var remaining atomic.Int32
+	remaining.Store(2)
+	newServer := func() *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			left := max(remaining.Add(-1), 0)
+			w.Header().Set("X-Ratelimit-Remaining", strconv.Itoa(int(left)))
+			w.Header().Set("X-Ratelimit-Reset", "0.3")
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}}`)
+		}))
+	}
+	server := newServer()
+	defer server.Close()
+
+	limiter := registry.NewAdaptiveRateLimiter(100, time.Minute)
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithRateLimiter(limiter))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	get := func(ctx context.Context, client *registry.Client) error {
+		_, err := client.Providers.Get(ctx, "example", "widget")
+		return err
+	}
+
+	// The registry announces one request left, then none
+	if err := get(ctx, client); err != nil {
+		return fmt.Errorf("first request failed: %w", err)
+	}
+	if err := AssertEqual(1, client.GetRateLimiter().TokensRemaining()); err != nil {
+		return fmt.Errorf("tokens after first response: %w", err)
+	}
+	if err := get(ctx, client); err != nil {
+		return fmt.Errorf("second request failed: %w", err)
+	}
+	if err := AssertEqual(0, limiter.TokensRemaining()); err != nil {
+		return fmt.Errorf("tokens after second response: %w", err)
+	}
+	if limiter.TimeUntilNextToken() <= 0 {
+		return fmt.Errorf("expected to wait for the registry's window to reset")
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := get(shortCtx, client); err == nil {
+		return fmt.Errorf("expected the exhausted budget to block the request")
+	}
+
+	// Once the window resets the token bucket applies again
+	remaining.Store(100)
+	if err := get(ctx, client); err != nil {
+		return fmt.Errorf("request after reset failed: %w", err)
+	}
+
+	// Per-host limiters give each base URL its own budget
+	other := newServer()
+	defer other.Close()
+	perHost, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithRateLimit(1, time.Hour),
+		registry.WithPerHostRateLimiters(func(host string) registry.RateLimiter {
+			return registry.NewTokenBucket(1, time.Hour)
+		}))
+	if err != nil {
+		return fmt.Errorf("failed to create per-host client: %w", err)
+	}
+	if err := get(ctx, perHost); err != nil {
+		return fmt.Errorf("request to first host failed: %w", err)
+	}
+	if err := perHost.SetBaseURL(other.URL); err != nil {
+		return err
+	}
+	if err := get(ctx, perHost); err != nil {
+		return fmt.Errorf("request to second host failed: %w", err)
+	}
+	if err := AssertEqual(uint64(1), perHost.GetRateLimiter().Acquired()); err != nil {
+		return fmt.Errorf("second host tokens: %w", err)
+	}
+
+	if err := perHost.SetBaseURL(server.URL); err != nil {
+		return err
+	}
+	shortCtx, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := get(shortCtx, perHost); err == nil {
+		return fmt.Errorf("expected the first host's budget to be used up")
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testDownloadManager(ctx context.Context) error {
+	blob := make([]byte, 1<<20+123)
+	for i := range blob {
+		blob[i] = byte(i * 7)
+	}
+	sum := sha256.Sum256(blob)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	modified := time.Unix(1700000000, 0)
+
+	var requests, aborted atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/blob":
+			http.ServeContent(w, r, "blob", modified, bytes.NewReader(blob))
+		case "/flaky":
+			// The first response breaks off halfway through the artifact
+			if aborted.Add(1) == 1 {
+				w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-%d/%d", len(blob)-1, len(blob)))
+				w.Header().Set("Content-Length", strconv.Itoa(len(blob)))
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write(blob[:len(blob)/2])
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			}
+			http.ServeContent(w, r, "blob", modified, bytes.NewReader(blob))
+		case "/plain":
+			w.Write(blob)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "terralense-download-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	verify := func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(blob, data) {
+			return fmt.Errorf("downloaded content of %s differs", filepath.Base(path))
+		}
+		return nil
+	}
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithParallelDownloads(128<<10, 4))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Parallel chunks with streaming checksum verification and progress; the validator
+	// of the artifact is stored next to the partial file while it downloads
+	var (
+		last      registry.DownloadProgress
+		validator []byte
+	)
+	path := filepath.Join(dir, "parallel")
+	progressCtx := registry.WithDownloadProgress(ctx, func(p registry.DownloadProgress) {
+		last = p
+		if validator == nil {
+			validator, _ = os.ReadFile(path + ".part.validator")
+		}
+	})
+	result, err := client.DownloadFile(progressCtx, registry.DownloadRequest{URL: server.URL + "/blob", Checksum: checksum}, path)
+	if err != nil {
+		return fmt.Errorf("parallel download failed: %w", err)
+	}
+	if err := AssertEqual(9, result.Chunks); err != nil {
+		return fmt.Errorf("chunks: %w", err)
+	}
+	if err := AssertEqual(checksum, result.Checksum); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(len(blob)), last.Written); err != nil {
+		return fmt.Errorf("final progress: %w", err)
+	}
+	if err := AssertEqual(int64(len(blob)), last.Total); err != nil {
+		return fmt.Errorf("progress total: %w", err)
+	}
+	if err := verify(path); err != nil {
+		return err
+	}
+	lastModified := modified.UTC().Format(http.TimeFormat)
+	if err := AssertEqual(lastModified, string(validator)); err != nil {
+		return fmt.Errorf("stored validator: %w", err)
+	}
+	for _, suffix := range []string{".part", ".part.validator"} {
+		if _, err := os.Stat(path + suffix); !os.IsNotExist(err) {
+			return fmt.Errorf("%s file left behind after a complete download", suffix)
+		}
+	}
+
+	// A partial file left by an earlier attempt is resumed when its validator still
+	// matches, and downloaded again when it is missing or the artifact changed
+	resume := func(name string, partial []byte, validator string) (*registry.DownloadResult, error) {
+		path = filepath.Join(dir, name)
+		if err := os.WriteFile(path+".part", partial, 0o644); err != nil {
+			return nil, err
+		}
+		if validator != "" {
+			if err := os.WriteFile(path+".part.validator", []byte(validator), 0o644); err != nil {
+				return nil, err
+			}
+		}
+		return client.DownloadFile(ctx, registry.DownloadRequest{URL: server.URL + "/blob", Checksum: checksum}, path)
+	}
+	for _, tc := range []struct {
+		name      string
+		validator string
+		resumed   int64
+	}{
+		{"resumed", lastModified, 300 << 10},
+		{"unvalidated", "", 0},
+		{"replaced", modified.Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	} {
+		// Stale bytes past the resumed part must not survive a restart
+		partial := append(append([]byte{}, blob[:300<<10]...), bytes.Repeat([]byte{0xff}, 10)...)
+		if tc.resumed > 0 {
+			partial = blob[:tc.resumed]
+		}
+		result, err = resume(tc.name, partial, tc.validator)
+		if err != nil {
+			return fmt.Errorf("%s download failed: %w", tc.name, err)
+		}
+		if err := AssertEqual(tc.resumed, result.Resumed); err != nil {
+			return fmt.Errorf("%s bytes: %w", tc.name, err)
+		}
+		if err := verify(path); err != nil {
+			return err
+		}
+	}
+
+	// A partial file longer than the artifact cannot be resumed and is discarded
+	if _, err := resume("overlong", append(append([]byte{}, blob...), 0), lastModified); !errors.Is(err, registry.ErrDownloadChanged) {
+		return fmt.Errorf("expected download changed for an overlong partial file, got: %v", err)
+	}
+	for _, suffix := range []string{".part", ".part.validator"} {
+		if _, err := os.Stat(path + suffix); !os.IsNotExist(err) {
+			return fmt.Errorf("%s file kept after an overlong partial file", suffix)
+		}
+	}
+
+	// A connection dropped midway is resumed within the same call
+	sequential, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithParallelDownloads(0, 1))
+	if err != nil {
+		return fmt.Errorf("failed to create sequential client: %w", err)
+	}
+	path = filepath.Join(dir, "flaky")
+	result, err = sequential.DownloadFile(ctx, registry.DownloadRequest{URL: server.URL + "/flaky", Checksum: checksum}, path)
+	if err != nil {
+		return fmt.Errorf("interrupted download failed: %w", err)
+	}
+	if err := AssertEqual(int32(2), aborted.Load()); err != nil {
+		return fmt.Errorf("flaky requests: %w", err)
+	}
+	if err := verify(path); err != nil {
+		return err
+	}
+
+	// A mismatching checksum fails and discards the partial file
+	path = filepath.Join(dir, "mismatch")
+	wrong := registry.DownloadRequest{URL: server.URL + "/blob", Checksum: "sha256:" + strings.Repeat("0", 64)}
+	if _, err := client.DownloadFile(ctx, wrong, path); !errors.Is(err, registry.ErrChecksumMismatch) {
+		return fmt.Errorf("expected checksum mismatch, got: %v", err)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		return fmt.Errorf("partial file kept after a checksum mismatch")
+	}
+
+	// Servers without range support are downloaded in one piece
+	path = filepath.Join(dir, "plain")
+	result, err = client.DownloadFile(ctx, registry.DownloadRequest{URL: server.URL + "/plain", Checksum: checksum}, path)
+	if err != nil {
+		return fmt.Errorf("plain download failed: %w", err)
+	}
+	if err := AssertEqual(1, result.Chunks); err != nil {
+		return fmt.Errorf("plain chunks: %w", err)
+	}
+	if err := verify(path); err != nil {
+		return err
+	}
+
+	// The size limit is checked before downloading
+	requests.Store(0)
+	limited := registry.DownloadRequest{URL: server.URL + "/blob", MaxSize: 1 << 20}
+	if _, err := client.Download(ctx, limited, &memoryTarget{}); !errors.Is(err, registry.ErrDownloadTooLarge) {
+		return fmt.Errorf("expected download too large, got: %v", err)
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("requests for an oversized download: %w", err)
+	}
+
+	return nil
+}
+
+// memoryTarget is an in-memory registry.DownloadTarget
+type memoryTarget struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (t *memoryTarget) WriteAt(p []byte, off int64) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if end := int(off) + len(p); end > len(t.data) {
+		t.data = append(t.data, make([]byte, end-len(t.data))...)
+	}
+	return copy(t.data[off:], p), nil
+}
+
+func (t *memoryTarget) ReadAt(p []byte, off int64) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if off >= int64(len(t.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, t.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (s *PerformanceTests) testRequestFingerprints(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/modules/busy/"):
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, `{"errors": ["rate limited"]}`, http.StatusTooManyRequests)
+		case strings.HasPrefix(r.URL.Path, "/v1/modules/"):
+			parts := strings.Split(r.URL.Path, "/")
+			fmt.Fprintf(w, `{"namespace": %q, "name": %q, "provider": %q, "version": %q}`, parts[3], parts[4], parts[5], parts[6])
+		case r.URL.Path == "/v2/provider-docs":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithRequestFingerprints(), registry.WithHTTPClient(server.Client()))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	for _, module := range []string{"vpc", "vpc", "vpc", "eks"} {
+		if _, err := client.Modules.Get(ctx, "acme", module, "aws", "1.0.0"); err != nil {
+			return fmt.Errorf("failed to get module %s: %w", module, err)
+		}
+	}
+	for _, category := range []string{"resources", "resources", "guides"} {
+		if _, err := client.Providers.ListDocsV2(ctx, &registry.ProviderDocListOptions{ProviderVersionID: "pv1", Category: category}); err != nil {
+			return fmt.Errorf("failed to list %s: %w", category, err)
+		}
+	}
+	if _, err := client.Modules.Get(ctx, "busy", "vpc", "aws", "1.0.0"); err == nil {
+		return fmt.Errorf("expected a rate limit error")
+	}
+
+	report := client.FingerprintReport(2)
+	if err := AssertEqual(8, report.Total); err != nil {
+		return fmt.Errorf("total: %w", err)
+	}
+	if err := AssertEqual(3, report.Distinct); err != nil {
+		return fmt.Errorf("distinct: %w", err)
+	}
+	if err := AssertEqual(2, len(report.Fingerprints)); err != nil {
+		return err
+	}
+
+	top := report.Fingerprints[0]
+	if err := AssertEqual("GET v1 modules/{namespace}/{name}/{provider}/{version}", top.Fingerprint); err != nil {
+		return err
+	}
+	if err := AssertEqual(5, top.Count); err != nil {
+		return fmt.Errorf("module lookups: %w", err)
+	}
+	if err := AssertEqual(2, top.Repeated()); err != nil {
+		return fmt.Errorf("repeated module lookups: %w", err)
+	}
+	if err := AssertEqual(1, top.RateLimited); err != nil {
+		return fmt.Errorf("rate limited lookups: %w", err)
+	}
+
+	docs := report.Fingerprints[1]
+	if err := AssertEqual("GET v2 provider-docs?filter[category]=resources&filter[language]=hcl&filter[provider-version]&page[number]&page[size]", docs.Fingerprint); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, docs.Count); err != nil {
+		return err
+	}
+	if !strings.Contains(report.String(), top.Fingerprint) {
+		return fmt.Errorf("rendered report misses the top fingerprint:\n%s", report)
+	}
+
+	client.ResetFingerprints()
+	if err := AssertEqual(0, client.FingerprintReport(0).Total); err != nil {
+		return fmt.Errorf("total after reset: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testPriorityScheduler(ctx context.Context) error {
+	// A single-token budget that does not refill during the test
+	limiter := registry.NewRateLimiter(1, time.Hour)
+	scheduler := registry.NewScheduler(limiter, 0)
+
+	if err := scheduler.Wait(ctx); err != nil {
+		return fmt.Errorf("first request should proceed: %w", err)
+	}
+
+	prefetchErr := make(chan error, 1)
+	go func() {
+		prefetchErr <- scheduler.Wait(registry.WithPriority(ctx, registry.PriorityPrefetch))
+	}()
+
+	// Wait for the prefetch to be queued
+	for scheduler.Pending() == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	interactiveCtx, cancel := context.WithTimeout(registry.WithPriority(ctx, registry.PriorityInteractive), 100*time.Millisecond)
+	defer cancel()
+	go func() {
+		_ = scheduler.Wait(interactiveCtx)
+	}()
+
+	select {
+	case err := <-prefetchErr:
+		if !registry.IsSuperseded(err) {
+			return fmt.Errorf("expected prefetch to be superseded, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("prefetch was not cancelled")
+	}
+
+	// WithPriorityScheduler(0) cancels prefetches at DefaultSchedulerThreshold tokens
+	client, err := registry.NewClient(registry.WithLogger(s.logger),
+		registry.WithRateLimit(registry.DefaultSchedulerThreshold, time.Hour), registry.WithPriorityScheduler(0))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	scheduler = client.GetScheduler()
+	for i := 0; i < registry.DefaultSchedulerThreshold; i++ {
+		if err := scheduler.Wait(ctx); err != nil {
+			return fmt.Errorf("request %d should proceed: %w", i, err)
+		}
+	}
+	go func() {
+		prefetchErr <- scheduler.Wait(registry.WithPriority(ctx, registry.PriorityPrefetch))
+	}()
+	for scheduler.Pending() == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	// Refill the budget to the threshold without waking the queued prefetch
+	any(client.GetRateLimiter()).(interface{ Reset() }).Reset()
+	if err := scheduler.Wait(registry.WithPriority(ctx, registry.PriorityInteractive)); err != nil {
+		return fmt.Errorf("interactive request should proceed: %w", err)
+	}
+	select {
+	case err := <-prefetchErr:
+		if !registry.IsSuperseded(err) {
+			return fmt.Errorf("expected prefetch to be superseded at the default threshold, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		return fmt.Errorf("prefetch was not cancelled at the default threshold")
+	}
+
+	if registry.PriorityFromContext(ctx) != registry.PriorityNormal {
+		return fmt.Errorf("untagged context should have normal priority")
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testWaitEvents(ctx context.Context) error {
+	var (
+		mu       sync.Mutex
+		failures = 1
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": "1.0.0"}`))
+	}))
+	defer server.Close()
+
+	var events []registry.WaitEvent
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		registry.WithRateLimit(1, 200*time.Millisecond),
+		registry.WithWaitHandler(func(event registry.WaitEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		}),
+		func(c *registry.ClientConfig) {
+			c.RetryWaitMin = 10 * time.Millisecond
+			c.RetryWaitMax = 20 * time.Millisecond
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Providers.Get(ctx, "hashicorp", "random"); err != nil {
+			return fmt.Errorf("request %d failed: %w", i+1, err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var retry, rateLimit *registry.WaitEvent
+	for i := range events {
+		switch events[i].Reason {
+		case registry.WaitRetry:
+			retry = &events[i]
+		case registry.WaitRateLimit:
+			rateLimit = &events[i]
+		}
+	}
+
+	if retry == nil {
+		return fmt.Errorf("expected a retry event, got %d events", len(events))
+	}
+	if err := AssertEqual(1, retry.Attempt); err != nil {
+		return err
+	}
+	if err := AssertEqual(http.StatusServiceUnavailable, retry.StatusCode); err != nil {
+		return err
+	}
+
+	if rateLimit == nil {
+		return fmt.Errorf("expected a rate limit event, got %d events", len(events))
+	}
+	if rateLimit.Wait <= 0 || rateLimit.URL == "" {
+		return fmt.Errorf("expected rate limit event with wait and URL, got %+v", *rateLimit)
+	}
+
+	s.logger.Debugf("Received %d wait events", len(events))
+	return nil
+}
+
+func (s *PerformanceTests) testRawCapture(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": "2.0.0", "unmodeled_field": "kept"}`))
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if _, err := client.Providers.Get(ctx, "hashicorp", "random"); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	if registry.RawFromContext(ctx) != nil {
+		return fmt.Errorf("expected no capture without WithRawCapture")
+	}
+
+	captureCtx := registry.WithRawCapture(ctx)
+	if _, err := client.Providers.Get(captureCtx, "hashicorp", "random"); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	var raw struct {
+		UnmodeledField string `json:"unmodeled_field"`
+	}
+	if err := json.Unmarshal(registry.RawFromContext(captureCtx), &raw); err != nil {
+		return fmt.Errorf("failed to decode raw payload: %w", err)
+	}
+	if err := AssertEqual("kept", raw.UnmodeledField); err != nil {
+		return err
+	}
+
+	responses := registry.RawResponsesFromContext(captureCtx)
+	if err := AssertEqual(1, len(responses)); err != nil {
+		return err
+	}
+	if err := AssertEqual(http.StatusOK, responses[0].StatusCode); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testChangeDetection(ctx context.Context) error {
+	var (
+		mu          sync.Mutex
+		published   = []string{"1.0.0", "1.1.0"}
+		requests    int
+		notModified int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+
+		validator := fmt.Sprintf(`"%d"`, len(published))
+		modified := time.Date(2026, 1, len(published), 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+
+		var body bytes.Buffer
+		switch {
+		case r.URL.Path == "/v2/providers/hashicorp/random" && r.URL.Query().Get("include") == "provider-versions":
+			if r.Header.Get("If-None-Match") == validator {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", validator)
+			body.WriteString(`{"data": {"id": "1"}, "included": [`)
+			for i, version := range published {
+				if i > 0 {
+					body.WriteString(",")
+				}
+				fmt.Fprintf(&body, `{"type": "provider-versions", "id": "%d", "attributes": {"version": %q}}`, i, version)
+			}
+			body.WriteString(`]}`)
+		case r.URL.Path == "/v1/modules/acme/network/aws/versions":
+			if r.Header.Get("If-Modified-Since") == modified {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Last-Modified", modified)
+			body.WriteString(`{"modules": [{"versions": [`)
+			for i, version := range published {
+				if i > 0 {
+					body.WriteString(",")
+				}
+				fmt.Fprintf(&body, `{"version": %q}`, version)
+			}
+			body.WriteString(`]}]}`)
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	provider := registry.ProviderRef{Namespace: "hashicorp", Name: "random"}
+	module := registry.ModuleRef{Namespace: "acme", Name: "network", Provider: "aws"}
+	checks := map[string]func(known string) (bool, error){
+		"provider": func(known string) (bool, error) { return client.Providers.HasChanged(ctx, provider, known) },
+		"module":   func(known string) (bool, error) { return client.Modules.HasChanged(ctx, module, known) },
+	}
+
+	// expect runs a check and compares its result and the requests it took
+	expect := func(name, known string, want bool, wantRequests, wantNotModified int) error {
+		mu.Lock()
+		requests, notModified = 0, 0
+		mu.Unlock()
+
+		changed, err := checks[name](known)
+		if err != nil {
+			return fmt.Errorf("%s: failed to check %q: %w", name, known, err)
+		}
+		if changed != want {
+			return fmt.Errorf("%s: expected changed=%v for known version %q", name, want, known)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if requests != wantRequests || notModified != wantNotModified {
+			return fmt.Errorf("%s: expected %d request(s) with %d not modified for known version %q, got %d with %d",
+				name, wantRequests, wantNotModified, known, requests, notModified)
+		}
+		return nil
+	}
+
+	for _, name := range []string{"provider", "module"} {
+		mu.Lock()
+		published = []string{"1.0.0", "1.1.0"}
+		mu.Unlock()
+
+		// An unknown version is a change without asking the registry
+		if err := expect(name, "", true, 0, 0); err != nil {
+			return err
+		}
+
+		// The first check lists the versions; later ones are answered with 304 Not Modified
+		if err := expect(name, "1.1.0", false, 1, 0); err != nil {
+			return err
+		}
+		if err := expect(name, "1.1.0", false, 1, 1); err != nil {
+			return err
+		}
+		if err := expect(name, "v1.0.0", true, 1, 1); err != nil {
+			return err
+		}
+
+		// A new release changes the validator, so the listing is sent again
+		mu.Lock()
+		published = append(published, "1.2.0")
+		mu.Unlock()
+		if err := expect(name, "1.1.0", true, 1, 0); err != nil {
+			return err
+		}
+		if err := expect(name, "1.2.0", false, 1, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testRateBudget(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": "1.0.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		registry.WithRateLimit(4, 400*time.Millisecond),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	calls := func(n int) TestCase {
+		return TestCase{
+			Name: fmt.Sprintf("%d calls", n),
+			Run: func(ctx context.Context) error {
+				for i := 0; i < n; i++ {
+					if _, err := client.Providers.Get(ctx, "hashicorp", "random"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+
+	runner := NewTestRunner(client, s.logger)
+	runner.SetRateBudget(RateBudget{PerTest: 4})
+
+	first := runner.runTest(ctx, "Budget", calls(3))
+	if err := AssertEqual(3, first.APICalls); err != nil {
+		return fmt.Errorf("first test API calls: %w", err)
+	}
+
+	// Only one token is left, so the second test waits for the bucket to refill
+	start := time.Now()
+	second := runner.runTest(ctx, "Budget", calls(2))
+	if second.Error != nil {
+		return fmt.Errorf("second test failed: %w", second.Error)
+	}
+	if err := AssertEqual(2, second.APICalls); err != nil {
+		return fmt.Errorf("second test API calls: %w", err)
+	}
+	if waited := time.Since(start); waited < 200*time.Millisecond {
+		return fmt.Errorf("expected the second test to wait for budget, started after %v", waited)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testRetryMiddleware(ctx context.Context) error {
+	// run sends one request to a server failing with status for the first failures
+	// attempts and reports the attempts seen by a middleware
+	run := func(status, failures int, opts ...registry.ClientOption) (int32, error) {
+		var served atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if int(served.Add(1)) <= failures {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version": "1.0.0"}`))
+		}))
+		defer server.Close()
+
+		var attempts atomic.Int32
+		counter := func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts.Add(1)
+				return next.RoundTrip(req)
+			})
+		}
+
+		opts = append([]registry.ClientOption{
+			registry.WithBaseURL(server.URL),
+			registry.WithLogger(s.logger),
+			registry.WithMiddleware(counter),
+			func(c *registry.ClientConfig) {
+				c.MaxRetries = 3
+				c.RetryWaitMin = time.Millisecond
+				c.RetryWaitMax = 5 * time.Millisecond
+			},
+		}, opts...)
+
+		client, err := registry.NewClient(opts...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create client: %w", err)
+		}
+
+		_, err = client.Providers.Get(ctx, "hashicorp", "random")
+		return attempts.Load(), err
+	}
+
+	layers := map[string][]registry.ClientOption{
+		"built-in":  nil,
+		"retryable": {retryable.WithRetryableHTTP()},
+	}
+
+	for name, opts := range layers {
+		attempts, err := run(http.StatusServiceUnavailable, 2, opts...)
+		if err != nil {
+			return fmt.Errorf("%s: expected recovery after retries, got %w", name, err)
+		}
+		if err := AssertEqual(int32(3), attempts); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		// Out of retries, the last response is reported with its status
+		attempts, err = run(http.StatusInternalServerError, 10, opts...)
+		var apiErr *registry.APIError
+		if !errors.As(err, &apiErr) || !registry.IsServerError(err) {
+			return fmt.Errorf("%s: expected a server error once retries are exhausted, got %v", name, err)
+		}
+		if err := AssertEqual(int32(4), attempts); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		attempts, err = run(http.StatusNotFound, 1, opts...)
+		if !registry.IsNotFound(err) {
+			return fmt.Errorf("%s: expected not found without retrying, got %v", name, err)
+		}
+		if err := AssertEqual(int32(1), attempts); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		attempts, err = run(http.StatusTooManyRequests, 10, opts...)
+		if !registry.IsRateLimited(err) {
+			return fmt.Errorf("%s: expected rate limiting once retries are exhausted, got %v", name, err)
+		}
+		if err := AssertEqual(int32(4), attempts); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		// Network failures name the request once, in the request error
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		client, err := registry.NewClient(append([]registry.ClientOption{
+			registry.WithBaseURL(closed.URL),
+			registry.WithLogger(s.logger),
+			func(c *registry.ClientConfig) {
+				c.MaxRetries = 1
+				c.RetryWaitMin = time.Millisecond
+				c.RetryWaitMax = time.Millisecond
+			},
+		}, opts...)...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		_, err = client.Providers.Get(ctx, "hashicorp", "random")
+		if err == nil || !strings.Contains(err.Error(), "giving up after 2 attempt(s)") {
+			return fmt.Errorf("%s: expected retries to be exhausted, got %v", name, err)
+		}
+		if count := strings.Count(err.Error(), closed.URL); count != 1 {
+			return fmt.Errorf("%s: expected the URL once in %q, found it %d times", name, err, count)
+		}
+	}
+
+	return nil
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (s *PerformanceTests) testNegativeCache(ctx context.Context) error {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	lookup := func(ctx context.Context, client *registry.Client) error {
+		_, err := client.Modules.ListVersions(ctx, "missing", "net", "aws")
+		if !registry.IsNotFound(err) {
+			return fmt.Errorf("expected not found, got: %v", err)
+		}
+		return nil
+	}
+
+	// Disabled by default
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := lookup(ctx, client); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(2), requests.Load()); err != nil {
+		return fmt.Errorf("uncached lookups: %w", err)
+	}
+
+	requests.Store(0)
+	client, err = registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithNegativeCache(time.Minute, 0))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := lookup(ctx, client); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("cached lookups: %w", err)
+	}
+	if err := AssertEqual(1, client.NegativeCacheLen()); err != nil {
+		return err
+	}
+
+	// Searches are not lookups and are never cached
+	for i := 0; i < 2; i++ {
+		if _, err := client.Modules.Search(ctx, "missing", 0); !registry.IsNotFound(err) {
+			return fmt.Errorf("expected not found search, got: %v", err)
+		}
+	}
+	if err := AssertEqual(int32(3), requests.Load()); err != nil {
+		return fmt.Errorf("searches: %w", err)
+	}
+
+	if err := lookup(registry.BypassNegativeCache(ctx), client); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(4), requests.Load()); err != nil {
+		return fmt.Errorf("bypassed lookup: %w", err)
+	}
+
+	client.ClearNegativeCache()
+	if err := AssertEqual(0, client.NegativeCacheLen()); err != nil {
+		return err
+	}
+	if err := lookup(ctx, client); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(5), requests.Load()); err != nil {
+		return fmt.Errorf("lookup after clear: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testResponseCache(ctx context.Context) error {
+	var requests, conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			w.Header().Set("ETag", `"widget-1"`)
+			if r.Header.Get("If-None-Match") == `"widget-1"` {
+				conditional.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}}`)
+		case "/v2/providers/example/private":
+			w.Header().Set("Cache-Control", "no-store")
+			fmt.Fprint(w, `{"data": {"id": "p2", "attributes": {"namespace": "example", "name": "private"}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cache := registry.NewMemoryCache(10)
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithCache(cache, time.Minute))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	get := func(ctx context.Context, client *registry.Client, name string) error {
+		provider, err := client.Providers.Get(ctx, "example", name)
+		if err != nil {
+			return fmt.Errorf("failed to get provider: %w", err)
+		}
+		return AssertEqual(name, provider.Attributes.Name)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := get(ctx, client, "widget"); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("cached requests: %w", err)
+	}
+	// Cache hits do not use rate limit budget
+	if err := AssertEqual(uint64(1), client.GetRateLimiter().Acquired()); err != nil {
+		return fmt.Errorf("rate limit tokens: %w", err)
+	}
+
+	// Bypassing revalidates with the ETag and reuses the cached body on 304
+	if err := get(registry.BypassCache(ctx), client, "widget"); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(1), conditional.Load()); err != nil {
+		return fmt.Errorf("conditional requests: %w", err)
+	}
+
+	client.InvalidateCache("v2", "providers/example/widget")
+	if err := get(ctx, client, "widget"); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(3), requests.Load()); err != nil {
+		return fmt.Errorf("request after invalidation: %w", err)
+	}
+	if err := AssertEqual(int32(1), conditional.Load()); err != nil {
+		return fmt.Errorf("invalidated entries should not be revalidated: %w", err)
+	}
+
+	// Fresh entries and 304 responses count as hits, full responses as misses
+	stats := client.CacheStats()
+	if err := AssertEqual(registry.CacheStats{Hits: 2, Revalidated: 1, Misses: 2}, stats); err != nil {
+		return fmt.Errorf("cache stats: %w", err)
+	}
+	if err := AssertEqual(0.6, stats.HitRate()); err != nil {
+		return fmt.Errorf("hit rate: %w", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := get(ctx, client, "private"); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(5), requests.Load()); err != nil {
+		return fmt.Errorf("no-store responses should not be cached: %w", err)
+	}
+	if err := AssertEqual(1, cache.Len()); err != nil {
+		return err
+	}
+
+	// The memory cache is part of the exported client state
+	state, err := client.ExportState()
+	if err != nil {
+		return fmt.Errorf("failed to export state: %w", err)
+	}
+	warm, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithCache(registry.NewMemoryCache(10), time.Minute))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if err := warm.ImportState(state); err != nil {
+		return fmt.Errorf("failed to import state: %w", err)
+	}
+	if err := get(ctx, warm, "widget"); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(5), requests.Load()); err != nil {
+		return fmt.Errorf("imported cache: %w", err)
+	}
+
+	// Forced revalidation sends every request with the stored ETag
+	revalidating, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+		registry.WithCache(cache, time.Minute), registry.WithCacheRevalidation())
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := get(ctx, revalidating, "widget"); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(3), conditional.Load()); err != nil {
+		return fmt.Errorf("forced revalidation: %w", err)
+	}
+	if err := AssertEqual(registry.CacheStats{Revalidated: 2}, revalidating.CacheStats()); err != nil {
+		return fmt.Errorf("revalidation stats: %w", err)
+	}
+	revalidating.ResetCacheStats()
+	if err := AssertEqual(registry.CacheStats{}, revalidating.CacheStats()); err != nil {
+		return fmt.Errorf("reset stats: %w", err)
+	}
+
+	// Disk caches are shared between clients and processes
+	dir, err := os.MkdirTemp("", "terralense-response-cache")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	disk, err := registry.NewDiskCache(dir)
+	if err != nil {
+		return fmt.Errorf("failed to create disk cache: %w", err)
+	}
+	for i := 0; i < 2; i++ {
+		diskClient, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger),
+			registry.WithCache(disk, time.Minute))
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		if err := get(ctx, diskClient, "widget"); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(8), requests.Load()); err != nil {
+		return fmt.Errorf("disk cache: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testBlobCache(ctx context.Context) error {
+	var registryRequests atomic.Int32
+	registryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registryRequests.Add(1)
+		fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}}`)
+	}))
+	defer registryServer.Close()
+
+	// Fake buckets keep objects by request path and check the credentials of each request
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	var authErrors []string
+	bucket := func(check func(r *http.Request, body []byte) error, key func(r *http.Request) string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			if err := check(r, body); err != nil {
+				authErrors = append(authErrors, err.Error())
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			name := key(r)
+			switch r.Method {
+			case http.MethodGet:
+				data, ok := objects[name]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write(data)
+			case http.MethodPut, http.MethodPost:
+				objects[name] = body
+			case http.MethodDelete:
+				if _, ok := objects[name]; !ok {
+					http.NotFound(w, r)
+					return
+				}
+				delete(objects, name)
+			}
+		}))
+	}
+
+	s3Server := bucket(func(r *http.Request, body []byte) error {
+		sum := sha256.Sum256(body)
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+			return fmt.Errorf("unsigned S3 request %s %s", r.Method, r.URL.Path)
+		}
+		return nil
+	}, func(r *http.Request) string {
+		return "s3:" + r.URL.Path
+	})
+	defer s3Server.Close()
+
+	gcsServer := bucket(func(r *http.Request, body []byte) error {
+		if r.Header.Get("Authorization") != "Bearer gcs-token" {
+			return fmt.Errorf("unauthorized GCS request %s %s", r.Method, r.URL.Path)
+		}
+		return nil
+	}, func(r *http.Request) string {
+		if name := r.URL.Query().Get("name"); name != "" {
+			return "gcs:" + name
+		}
+		return "gcs:" + strings.TrimPrefix(r.URL.Path, "/storage/v1/b/ci-cache/o/")
+	})
+	defer gcsServer.Close()
+
+	s3Store, err := registry.NewS3BlobStore(registry.S3Options{
+		Bucket:          "ci-cache",
+		Region:          "eu-west-1",
+		Endpoint:        s3Server.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create S3 store: %w", err)
+	}
+	gcsStore, err := registry.NewGCSBlobStore(registry.GCSOptions{
+		Bucket:   "ci-cache",
+		Endpoint: gcsServer.URL,
+		Token: func(context.Context) (string, error) {
+			return "gcs-token", nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create GCS store: %w", err)
+	}
+
+	for _, store := range []struct {
+		name  string
+		store registry.BlobStore
+	}{{"s3", s3Store}, {"gcs", gcsStore}} {
+		registryRequests.Store(0)
+
+		// Two replicas with their own local caches share the bucket
+		var replicas []*registry.Client
+		for i := 0; i < 2; i++ {
+			cache := registry.NewBlobCache(store.store, &registry.BlobCacheOptions{Prefix: "terralense/"})
+			client, err := registry.NewClient(registry.WithBaseURL(registryServer.URL), registry.WithLogger(s.logger),
+				registry.WithCache(cache, time.Minute))
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			replicas = append(replicas, client)
+		}
+
+		for _, client := range replicas {
+			provider, err := client.Providers.Get(ctx, "example", "widget")
+			if err != nil {
+				return fmt.Errorf("%s: failed to get provider: %w", store.name, err)
+			}
+			if err := AssertEqual("widget", provider.Attributes.Name); err != nil {
+				return err
+			}
+		}
+		if err := AssertEqual(int32(1), registryRequests.Load()); err != nil {
+			return fmt.Errorf("%s: shared cache: %w", store.name, err)
+		}
+		if err := AssertEqual(registry.CacheStats{Hits: 1}, replicas[1].CacheStats()); err != nil {
+			return fmt.Errorf("%s: second replica: %w", store.name, err)
+		}
+
+		// Invalidating on one replica removes the shared object
+		replicas[0].InvalidateCache("v2", "providers/example/widget")
+		mu.Lock()
+		stored := len(objects)
+		mu.Unlock()
+		if err := AssertEqual(0, stored); err != nil {
+			return fmt.Errorf("%s: invalidated objects: %w", store.name, err)
+		}
+		if _, err := store.store.Get(ctx, "terralense/missing.json"); !registry.IsBlobNotFound(err) {
+			return fmt.Errorf("%s: expected blob not found, got %v", store.name, err)
+		}
+	}
+
+	if len(authErrors) > 0 {
+		return fmt.Errorf("bucket rejected requests: %s", strings.Join(authErrors, "; "))
+	}
+
+	if _, _, err := registry.OpenBlobStore("ftp://bucket/prefix"); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for unsupported scheme, got %v", err)
+	}
+	_, prefix, err := registry.OpenBlobStore("gs://ci-cache/terralense")
+	if err != nil {
+		return fmt.Errorf("failed to open gs bucket: %w", err)
+	}
+	return AssertEqual("terralense/", prefix)
+}
+
+func (s *PerformanceTests) testListIterators(ctx context.Context) error {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+
+		switch r.URL.Path {
+		case "/v1/modules":
+			// Five modules in pages of two
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			var modules []string
+			for i := offset; i < offset+2 && i < 5; i++ {
+				modules = append(modules, fmt.Sprintf(`{"id": "acme/m%d/aws/1.0.0", "namespace": "acme", "name": "m%d", "provider": "aws", "version": "1.0.0"}`, i, i))
+			}
+			next := ""
+			if offset+2 < 5 {
+				next = fmt.Sprintf(`, "next_offset": %d`, offset+2)
+			}
+			fmt.Fprintf(w, `{"meta": {"limit": 2, "current_offset": %d%s}, "modules": [%s]}`, offset, next, strings.Join(modules, ","))
+		case "/v2/providers", "/v2/policies":
+			// Two pages of one item; the second page repeats its own number as next page
+			page, _ := strconv.Atoi(query.Get("page[number]"))
+			next := page + 1
+			if page == 2 {
+				next = 2
+			}
+			fmt.Fprintf(w, `{"data": [{"id": "%s-%d"}], "meta": {"pagination": {"current-page": %d, "next-page": %d}}}`, r.URL.Path, page, page, next)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	modules, err := client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list modules: %w", err)
+	}
+	if err := AssertEqual(5, len(modules)); err != nil {
+		return err
+	}
+	if err := AssertEqual(int32(3), requests.Load()); err != nil {
+		return err
+	}
+
+	// Pages are fetched lazily: stopping early fetches only the first page
+	requests.Store(0)
+	for module, err := range client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).All() {
+		if err != nil {
+			return err
+		}
+		if module.Name != "m0" {
+			return fmt.Errorf("unexpected first module %s", module.Name)
+		}
+		break
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return err
+	}
+
+	// The max items safeguard reports truncation
+	it := client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).SetMaxItems(3)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := AssertEqual(3, count); err != nil {
+		return err
+	}
+	if !errors.Is(it.Err(), registry.ErrMaxItemsReached) {
+		return fmt.Errorf("expected max items error, got: %v", it.Err())
+	}
+
+	// Exactly reaching the limit is not an error
+	if _, err := client.Modules.ListAll(ctx, &registry.ModuleListOptions{Limit: 2}).SetMaxItems(5).Collect(); err != nil {
+		return fmt.Errorf("unexpected error at exact limit: %w", err)
+	}
+
+	providers, err := client.Providers.ListAll(ctx, nil).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+	if err := AssertEqual(2, len(providers)); err != nil {
+		return err
+	}
+
+	policies, err := client.Policies.ListAll(ctx, &registry.PolicyListOptions{PageSize: 1}).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+	if err := AssertEqual("/v2/policies-2", policies[len(policies)-1].ID); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.ListAll(ctx, &registry.ProviderListOptions{Page: -1}).Collect(); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *PerformanceTests) testValidationBenchmark(ctx context.Context) error {
+	benchmarks := []struct {
+		name  string
+		check func(string) bool
+		value string
+	}{
+		{"Namespace", validate.Namespace, "terraform-aws-modules"},
+		{"ProviderName", validate.ProviderName, "google-beta"},
+		{"Version", validate.Version, "v5.31.0-beta.1+build.7"},
+		{"DocCategory", validate.DocCategory, "data-sources"},
+	}
+
+	for _, bench := range benchmarks {
+		var failures atomic.Int64
+
+		// The patterns are shared, so run them from parallel goroutines like concurrent
+		// requests would
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if !bench.check(bench.value) {
+						failures.Add(1)
+					}
+				}
+			})
+		})
+
+		if failures.Load() > 0 {
+			return fmt.Errorf("%s rejected %q %d times", bench.name, bench.value, failures.Load())
+		}
+		if result.N == 0 {
+			return fmt.Errorf("%s benchmark did not run", bench.name)
+		}
+
+		s.logger.Infof("validate.%s: %d ns/op, %d allocs/op", bench.name, result.NsPerOp(), result.AllocsPerOp())
+
+		// A precompiled pattern takes microseconds at most; anything slower means it is
+		// being compiled per call
+		if perOp := time.Duration(result.NsPerOp()); perOp > 100*time.Microsecond {
+			return fmt.Errorf("%s took %v per call", bench.name, perOp)
+		}
+	}
+
+	return nil
+}
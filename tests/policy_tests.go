@@ -34,6 +34,8 @@ func (s *PolicyTests) setupTests() {
 	s.AddTest("Pagination", "Test policy list pagination", s.testPagination)
 	s.AddTest("Include Latest Version", "Test including latest version data", s.testIncludeLatestVersion)
 	s.AddTest("Invalid Policy", "Test error handling for invalid policies", s.testInvalidPolicy)
+	s.AddTest("Get Many Policies", "Test concurrent batch retrieval with per-item errors", s.testGetManyPolicies)
+	s.AddTest("Search Policy Content", "Test searching policy descriptions and READMEs", s.testSearchPolicyContent)
 }
 
 // In policy_tests.go, update the testListPolicies function:
@@ -442,3 +444,95 @@ func (s *PolicyTests) testInvalidPolicy(ctx context.Context) error {
 	s.logger.Debug("Invalid policy handling works correctly")
 	return nil
 }
+
+func (s *PolicyTests) testSearchPolicyContent(ctx context.Context) error {
+	query := "encryption at rest"
+
+	plain, err := s.client.Policies.Search(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to search policies: %w", err)
+	}
+
+	withContent, err := s.client.Policies.SearchWithOptions(ctx, query, &registry.PolicySearchOptions{
+		IncludeContent:    true,
+		MaxContentFetches: 5,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search policy content: %w", err)
+	}
+
+	// Content matching only adds relevance, so it can never lose results
+	if len(withContent) < len(plain) {
+		return fmt.Errorf("content search returned fewer results (%d) than plain search (%d)",
+			len(withContent), len(plain))
+	}
+
+	for i := 1; i < len(withContent); i++ {
+		if withContent[i].Relevance > withContent[i-1].Relevance {
+			return fmt.Errorf("results not sorted by relevance")
+		}
+	}
+
+	// Invalid options must be rejected
+	_, err = s.client.Policies.SearchWithOptions(ctx, query, &registry.PolicySearchOptions{MaxContentFetches: -1})
+	if !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for negative MaxContentFetches, got: %v", err)
+	}
+
+	s.logger.Debugf("Content search for '%s' returned %d results (plain: %d)", query, len(withContent), len(plain))
+	return nil
+}
+
+func (s *PolicyTests) testGetManyPolicies(ctx context.Context) error {
+	list, err := s.client.Policies.List(ctx, &registry.PolicyListOptions{
+		PageSize:             3,
+		IncludeLatestVersion: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	versions := make(map[string]string)
+	for _, included := range list.Included {
+		versions[included.ID] = included.Attributes.Version
+	}
+
+	var ids []string
+	for _, policy := range list.Data {
+		version := versions[policy.Relationships.LatestVersion.Data.ID]
+		if version == "" {
+			continue
+		}
+		ids = append(ids, fmt.Sprintf("%s/%s/%s", policy.Attributes.Namespace, policy.Attributes.Name, version))
+	}
+
+	invalidID := "not-a-valid-id"
+	ids = append(ids, invalidID)
+
+	batch, err := s.client.Policies.GetMany(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get policies: %w", err)
+	}
+
+	if err := AssertEqual(len(ids), len(batch.Results)); err != nil {
+		return fmt.Errorf("result count: %w", err)
+	}
+
+	if err := AssertError(batch.Results[invalidID].Err); err != nil {
+		return fmt.Errorf("invalid ID should fail individually: %w", err)
+	}
+
+	for _, id := range ids[:len(ids)-1] {
+		result := batch.Results[id]
+		if result.Err != nil {
+			return fmt.Errorf("policy %s failed: %w", id, result.Err)
+		}
+		s.logger.Debugf("Policy %s has %d included items", id, len(batch.IncludedFor(id)))
+	}
+
+	if _, err := s.client.Policies.GetMany(ctx, nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty ID list, got: %v", err)
+	}
+
+	return nil
+}
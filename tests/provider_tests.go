@@ -1,12 +1,33 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/TahirRiaz/terralens-registry-client/pins"
 
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registry/docparse"
+	"github.com/TahirRiaz/terralens-registry-client/registry/gpg"
+	"github.com/TahirRiaz/terralens-registry-client/registry/scrape"
+	"github.com/TahirRiaz/terralens-registry-client/registry/sdkgen"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
 )
 
 // ProviderTests contains tests for the Providers API
@@ -35,6 +56,37 @@ func (s *ProviderTests) setupTests() {
 	s.AddTest("Filter by Tier", "Test filtering providers by tier", s.testFilterByTier)
 	s.AddTest("Filter by Namespace", "Test filtering by namespace", s.testFilterByNamespace)
 	s.AddTest("Invalid Provider", "Test error handling for invalid providers", s.testInvalidProvider)
+	s.AddTest("Get Resource Counts", "Test per-category doc counts from pagination metadata", s.testGetResourceCounts)
+	s.AddTest("Get Slug Index", "Test building the cached slug to doc ID index", s.testGetSlugIndex)
+	s.AddTest("Has Changed", "Test cheap change detection against the latest version", s.testHasChanged)
+	s.AddTest("Scraping Fallback", "Test robots.txt handling and caching of the website scraper", s.testScrapingFallback)
+	s.AddTest("Pin Store", "Test persisting pinned providers and modules", s.testPinStore)
+	s.AddTest("Security Advisories", "Test advisory lookup and version matching", s.testSecurityAdvisories)
+	s.AddTest("List Featured", "Test listing featured providers with filters", s.testListFeatured)
+	s.AddTest("Resumable Summary", "Test resuming an interrupted resource summary from a checkpoint", s.testResumableSummary)
+	s.AddTest("Export Docs", "Test exporting provider docs to a directory tree", s.testExportDocs)
+	s.AddTest("Provider Maturity", "Test scoring provider maturity and rendering the report", s.testProviderMaturity)
+	s.AddTest("Provider Lookup", "Test path-based provider lookup with includes and the filter fallback", s.testProviderLookup)
+	s.AddTest("Version Details", "Test published date, protocols, platforms and docs of a version", s.testVersionDetails)
+	s.AddTest("Warnings", "Test collecting warnings from lenient operations", s.testWarnings)
+	s.AddTest("Provider Logo", "Test downloading and caching provider logos", s.testProviderLogo)
+	s.AddTest("Provider Schema", "Test parsing attribute schemas from provider docs", s.testProviderSchema)
+	s.AddTest("Provider SDK Generation", "Test generating Go structs and JSON Schema bundles from provider schemas", s.testProviderSDKGeneration)
+	s.AddTest("Mirror Plan", "Test planning incremental provider mirror refreshes", s.testMirrorPlan)
+	s.AddTest("Provider Download", "Test provider package downloads with checksum and signature verification", s.testProviderDownload)
+	s.AddTest("Naming Conventions", "Test slug prefix statistics and naming outliers", s.testNamingConventions)
+	s.AddTest("Provider Info", "Test converting v1 and v2 providers to a unified view", s.testProviderInfo)
+	s.AddTest("Version Diff", "Test diffing the docs of two provider versions", s.testVersionDiff)
+	s.AddTest("Provider Functions", "Test listing provider-defined functions with parsed signatures", s.testProviderFunctions)
+	s.AddTest("Provider Guides", "Test listing provider guides and parsing a guide into sections and examples", s.testProviderGuides)
+	s.AddTest("Structured Docs", "Test parsing provider docs into arguments, attributes, imports and examples", s.testStructuredDocs)
+	s.AddTest("Doc Pins", "Test resolving unversioned doc requests to team-pinned versions", s.testDocPins)
+	s.AddTest("Similar Docs", "Test suggesting related docs from fetched doc content", s.testSimilarDocs)
+	s.AddTest("OpenTofu Backend", "Test reading providers and docs from the OpenTofu registry", s.testOpenTofuBackend)
+	s.AddTest("Provider Download Metrics", "Test windowed and per-version provider download counts", s.testProviderDownloadMetrics)
+	s.AddTest("Provider Trend Report", "Test release frequency and download growth from version history", s.testProviderTrendReport)
+	s.AddTest("Get Many Providers", "Test concurrent batch provider retrieval with per-item errors", s.testGetManyProviders)
+	s.AddTest("Mirror Resolver", "Test cross-registry identity mapping and preferences", s.testMirrorResolver)
 }
 
 func (s *ProviderTests) testListProviders(ctx context.Context) error {
@@ -375,3 +427,2343 @@ func (s *ProviderTests) testInvalidProvider(ctx context.Context) error {
 	s.logger.Debug("Invalid provider handling works correctly")
 	return nil
 }
+
+func (s *ProviderTests) testGetResourceCounts(ctx context.Context) error {
+	counts, err := s.client.Providers.GetResourceCounts(ctx, registry.ProviderRef{
+		Namespace: "hashicorp",
+		Name:      "aws",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get resource counts: %w", err)
+	}
+
+	if counts.Version == "" || counts.ProviderVersionID == "" {
+		return fmt.Errorf("counts missing resolved version information")
+	}
+
+	if err := AssertGreaterThan(counts.Resources, 0); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	if err := AssertGreaterThan(counts.DataSources, 0); err != nil {
+		return fmt.Errorf("data sources: %w", err)
+	}
+
+	s.logger.Debugf("hashicorp/aws %s: %d resources, %d data sources, %d functions, %d guides",
+		counts.Version, counts.Resources, counts.DataSources, counts.Functions, counts.Guides)
+	return nil
+}
+
+func (s *ProviderTests) testProviderLookup(ctx context.Context) error {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			if r.URL.Query().Get("include") == "provider-versions" {
+				fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}},
+					"included": [
+						{"type": "provider-versions", "id": "v1", "attributes": {"version": "1.2.0"}},
+						{"type": "provider-versions", "id": "v2", "attributes": {"version": "1.10.0"}}
+					]}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}}`)
+		case "/v2/providers":
+			// The filter search matches more than the exact provider
+			fmt.Fprint(w, `{"data": [
+				{"id": "p9", "attributes": {"namespace": "example", "name": "widget-legacy"}},
+				{"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	provider, err := client.Providers.Get(ctx, "example", "widget")
+	if err != nil {
+		return fmt.Errorf("Get failed: %w", err)
+	}
+	if err := AssertEqual("p1", provider.ID); err != nil {
+		return err
+	}
+
+	requests.Store(0)
+	details, err := client.Providers.GetWithOptions(ctx, "example", "widget", &registry.ProviderGetOptions{IncludeLatest: true})
+	if err != nil {
+		return fmt.Errorf("GetWithOptions failed: %w", err)
+	}
+	if err := AssertEqual(int32(1), requests.Load()); err != nil {
+		return fmt.Errorf("latest version should take one request: %w", err)
+	}
+	if err := AssertEqual(2, len(details.Versions)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.10.0", details.LatestVersion); err != nil {
+		return err
+	}
+
+	// The legacy lookup keeps returning the first filter match
+	legacy, err := client.Providers.GetWithOptions(ctx, "example", "widget", &registry.ProviderGetOptions{FilterLookup: true})
+	if err != nil {
+		return fmt.Errorf("filter lookup failed: %w", err)
+	}
+	if err := AssertEqual("p9", legacy.Provider.ID); err != nil {
+		return err
+	}
+
+	_, err = client.Providers.GetWithOptions(ctx, "example", "widget", &registry.ProviderGetOptions{Include: []string{"a,b"}})
+	if !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for a combined include, got %v", err)
+	}
+
+	_, err = client.Providers.Get(ctx, "example", "missing")
+	if !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found error, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testVersionDetails(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1"}, "included": [
+				{"type": "provider-versions", "id": "v1", "attributes": {"version": "1.0.0", "published-at": "2024-01-02T00:00:00Z"}},
+				{"type": "provider-versions", "id": "v2", "attributes": {"version": "1.1.0", "published-at": "2024-03-04T00:00:00Z"}}
+			]}`)
+		case "/v1/providers/example/widget/versions":
+			fmt.Fprint(w, `{"versions": [
+				{"version": "1.0.0", "protocols": ["5.0"], "platforms": [{"os": "linux", "arch": "amd64"}]},
+				{"version": "1.1.0", "protocols": ["5.0", "6.0"], "platforms": [
+					{"os": "linux", "arch": "amd64"}, {"os": "darwin", "arch": "arm64"}]}
+			]}`)
+		case "/v2/provider-docs":
+			if r.URL.Query().Get("filter[provider-version]") == "v2" {
+				fmt.Fprint(w, `{"data": [{"id": "d1"}], "meta": {"pagination": {"total-count": 42}}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data": [], "meta": {"pagination": {"total-count": 0}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ref := registry.ProviderRef{Namespace: "example", Name: "widget"}
+
+	latest, err := client.Providers.GetVersionDetails(ctx, ref, "latest")
+	if err != nil {
+		return fmt.Errorf("GetVersionDetails failed: %w", err)
+	}
+	if err := AssertEqual("1.1.0", latest.Provider.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual("v2", latest.VersionID); err != nil {
+		return err
+	}
+	if err := AssertEqual("2024-03-04", latest.PublishedAt.Format("2006-01-02")); err != nil {
+		return err
+	}
+	if err := AssertEqual("5.0,6.0", strings.Join(latest.Protocols, ",")); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(latest.Platforms)); err != nil {
+		return err
+	}
+	if err := AssertEqual(42, latest.DocsCount); err != nil {
+		return err
+	}
+
+	older, err := client.Providers.GetVersionDetails(ctx, ref, "1.0.0")
+	if err != nil {
+		return fmt.Errorf("GetVersionDetails for 1.0.0 failed: %w", err)
+	}
+	if older.HasDocs() {
+		return fmt.Errorf("expected no docs for 1.0.0")
+	}
+
+	if _, err := client.Providers.GetVersionDetails(ctx, ref, "9.9.9"); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found for an unknown version, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testWarnings(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"id": "d1"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	var handled atomic.Int32
+	ctx = registry.WithWarningHandler(ctx, func(registry.Warning) { handled.Add(1) })
+	warnCtx, warnings := registry.WithWarnings(ctx)
+
+	if _, err := client.Providers.GetResourcesBySubcategory(warnCtx, "v1", registry.SubcategoryNetworking); err != nil {
+		return fmt.Errorf("known subcategory failed: %w", err)
+	}
+	if err := AssertEqual(0, warnings.Len()); err != nil {
+		return fmt.Errorf("known subcategory should not warn: %w", err)
+	}
+
+	if _, err := client.Providers.GetDataSourcesBySubcategory(warnCtx, "v1", "Quantum"); err != nil {
+		return fmt.Errorf("custom subcategory failed: %w", err)
+	}
+
+	list := warnings.List()
+	if err := AssertEqual(1, len(list)); err != nil {
+		return err
+	}
+	if err := AssertEqual(registry.WarningUnknownSubcategory, list[0].Code); err != nil {
+		return err
+	}
+	if err := AssertEqual("Quantum", list[0].Resource); err != nil {
+		return err
+	}
+
+	// Handlers on parent contexts see the warning too
+	if err := AssertEqual(int32(1), handled.Load()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderInfo(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"type": "providers", "id": "42", "attributes": {
+				"namespace": "example", "name": "widget", "full-name": "example/widget",
+				"owner-name": "example", "tier": "partner", "downloads": 5000, "featured": true,
+				"source": "https://github.com/example/terraform-provider-widget"}}}`)
+		case "/v1/providers/example/widget":
+			fmt.Fprint(w, `{"id": "example/widget/1.1.0", "namespace": "example", "name": "widget",
+				"version": "1.1.0", "tag": "v1.1.0", "downloads": 300, "published_at": "2024-05-01T00:00:00Z",
+				"versions": ["1.0.0", "1.1.0"]}`)
+		case "/v1/providers/example/widget/1.0.0":
+			fmt.Fprint(w, `{"id": "example/widget/1.0.0", "namespace": "example", "name": "widget",
+				"version": "1.0.0", "tag": "v1.0.0", "downloads": 200, "published_at": "2024-01-01T00:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	latest, err := client.Providers.GetInfo(ctx, registry.ProviderRef{Namespace: "example", Name: "widget"})
+	if err != nil {
+		return fmt.Errorf("failed to get latest provider info: %w", err)
+	}
+	if err := AssertEqual("42", latest.ID); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.1.0", latest.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(5000), latest.Downloads); err != nil {
+		return err
+	}
+	if err := AssertEqual(true, latest.Featured); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(latest.Versions)); err != nil {
+		return err
+	}
+
+	pinned, err := client.Providers.GetInfo(ctx, registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.0.0"})
+	if err != nil {
+		return fmt.Errorf("failed to get provider version info: %w", err)
+	}
+	if err := AssertEqual("v1.0.0", pinned.Tag); err != nil {
+		return err
+	}
+	if err := AssertEqual("example/widget@1.0.0", pinned.Ref().String()); err != nil {
+		return err
+	}
+	if pinned.PublishedAt.Year() != 2024 || pinned.Tier != "partner" {
+		return fmt.Errorf("expected v1 publish date and v2 tier, got %v and %q", pinned.PublishedAt, pinned.Tier)
+	}
+
+	// Converting back keeps the IDs of each API version
+	v1 := pinned.Provider()
+	if err := AssertEqual("example/widget/1.0.0", v1.ID); err != nil {
+		return err
+	}
+	v2 := pinned.ProviderData()
+	if err := AssertEqual("example/widget", v2.Attributes.FullName); err != nil {
+		return err
+	}
+	if err := AssertEqual("/v2/providers/42", v2.Links.Self); err != nil {
+		return err
+	}
+	if err := AssertEqual("partner", v2.Info().Tier); err != nil {
+		return err
+	}
+	if err := AssertEqual("", v1.Info().ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testVersionDiff(ctx context.Context) error {
+	listings := map[string]map[string]string{
+		"v1": {
+			"resources": `[
+				{"id": "10", "attributes": {"slug": "bucket", "title": "widget_bucket", "subcategory": "Storage"}},
+				{"id": "11", "attributes": {"slug": "queue", "title": "widget_queue", "subcategory": "Messaging"}},
+				{"id": "12", "attributes": {"slug": "legacy", "title": "widget_legacy", "subcategory": "Storage"}}
+			]`,
+			"data-sources": `[{"id": "13", "attributes": {"slug": "bucket", "title": "widget_bucket", "subcategory": "Storage"}}]`,
+		},
+		"v2": {
+			"resources": `[
+				{"id": "20", "attributes": {"slug": "bucket", "title": "widget_bucket", "subcategory": "Storage"}},
+				{"id": "21", "attributes": {"slug": "queue", "title": "widget_queue", "subcategory": "Messaging"}},
+				{"id": "22", "attributes": {"slug": "topic", "title": "widget_topic", "subcategory": "Messaging"}}
+			]`,
+			"data-sources": `[{"id": "23", "attributes": {"slug": "bucket", "title": "widget_bucket", "subcategory": "Object Storage"}}]`,
+			"functions":    `[{"id": "24", "attributes": {"slug": "parse_arn", "title": "parse_arn"}}]`,
+		},
+	}
+	contents := map[string]string{
+		"10": "Manages a bucket.", "20": "Manages a bucket.\n\n## Argument Reference\n\n* `versioning`",
+		"11": "Manages a queue.", "21": "Manages a queue.",
+		"13": "Reads a bucket.", "23": "Reads a bucket.",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}},
+				"included": [
+					{"type": "provider-versions", "id": "v1", "attributes": {"version": "1.0.0"}},
+					{"type": "provider-versions", "id": "v2", "attributes": {"version": "2.0.0"}}
+				]}`)
+		case r.URL.Path == "/v2/provider-docs":
+			docs, ok := listings[r.URL.Query().Get("filter[provider-version]")][r.URL.Query().Get("filter[category]")]
+			if !ok {
+				docs = "[]"
+			}
+			fmt.Fprintf(w, `{"data": %s, "meta": {"pagination": {}}}`, docs)
+		case strings.HasPrefix(r.URL.Path, "/v2/provider-docs/"):
+			content, ok := contents[strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, `{"data": {"attributes": {"content": %q}}}`, content)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	diff, err := client.Providers.DiffVersions(ctx, "example", "widget", "1.0.0", "2.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to diff versions: %w", err)
+	}
+	if diff.Empty() {
+		return fmt.Errorf("expected differences between versions")
+	}
+
+	resources := diff.Category("resources")
+	if err := AssertEqual(1, resources.Count(registry.DocAdded)); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, resources.Count(registry.DocRemoved)); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, resources.Count(registry.DocChanged)); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(resources.Subcategories)); err != nil {
+		return err
+	}
+	messaging, storage := resources.Subcategories[0], resources.Subcategories[1]
+	if err := AssertEqual("Messaging", messaging.Subcategory); err != nil {
+		return err
+	}
+	if len(messaging.Added) != 1 || messaging.Added[0].Slug != "topic" {
+		return fmt.Errorf("expected topic added under Messaging, got %+v", messaging)
+	}
+	if len(storage.Removed) != 1 || storage.Removed[0].Slug != "legacy" {
+		return fmt.Errorf("expected legacy removed under Storage, got %+v", storage)
+	}
+	if len(storage.Changed) != 1 || strings.Join(storage.Changed[0].Fields, ",") != "content" {
+		return fmt.Errorf("expected bucket content changed under Storage, got %+v", storage.Changed)
+	}
+
+	// Docs moved to another subcategory are listed under the new one
+	dataSources := diff.Category("data-sources")
+	if len(dataSources.Subcategories) != 1 || dataSources.Subcategories[0].Subcategory != "Object Storage" {
+		return fmt.Errorf("expected the moved data source under Object Storage, got %+v", dataSources.Subcategories)
+	}
+	if err := AssertEqual("subcategory", strings.Join(dataSources.Subcategories[0].Changed[0].Fields, ",")); err != nil {
+		return err
+	}
+
+	if err := AssertEqual(1, diff.Category("functions").Count(registry.DocAdded)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testMirrorResolver(ctx context.Context) error {
+	// Both registries point at the public registry; the mirror re-homes providers
+	// under the same namespace so resolution can be exercised end to end.
+	mirror, err := registry.NewClient(registry.WithBaseURL(s.client.GetBaseURL()), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create mirror client: %w", err)
+	}
+
+	resolver, err := registry.NewPublicMirrorResolver(s.client, mirror)
+	if err != nil {
+		return fmt.Errorf("failed to create resolver: %w", err)
+	}
+
+	canonical := registry.ProviderRef{Namespace: "hashicorp", Name: "aws"}
+	resolver.MapIdentity(canonical, registry.RegistryMirror, registry.ProviderRef{Namespace: "mirrored", Name: "aws"})
+
+	mapped := resolver.Identity(registry.ProviderRef{Namespace: "hashicorp", Name: "aws", Version: "5.0.0"}, registry.RegistryMirror)
+	if mapped.Namespace != "mirrored" || mapped.Version != "5.0.0" {
+		return fmt.Errorf("unexpected mirror identity: %s", mapped)
+	}
+
+	if err := resolver.SetPreference(registry.ArtifactDocs, "unknown"); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for unknown registry, got: %v", err)
+	}
+
+	resolved, err := resolver.Resolve(ctx, registry.ArtifactDocs, canonical)
+	if err != nil {
+		return fmt.Errorf("failed to resolve docs registry: %w", err)
+	}
+	if resolved.Registry != registry.RegistryPublic {
+		return fmt.Errorf("expected docs from %s, got %s", registry.RegistryPublic, resolved.Registry)
+	}
+
+	// The mirror identity does not exist, so downloads fall back to the public registry
+	resolved, err = resolver.Resolve(ctx, registry.ArtifactDownloads, canonical)
+	if err != nil {
+		return fmt.Errorf("failed to resolve downloads registry: %w", err)
+	}
+	if resolved.Registry != registry.RegistryPublic {
+		return fmt.Errorf("expected download fallback to %s, got %s", registry.RegistryPublic, resolved.Registry)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testHasChanged(ctx context.Context) error {
+	ref := registry.ProviderRef{Namespace: "hashicorp", Name: "random"}
+
+	latest, err := s.client.Providers.GetLatest(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+
+	changed, err := s.client.Providers.HasChanged(ctx, ref, latest.Version)
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if changed {
+		return fmt.Errorf("expected no change when known version is latest (%s)", latest.Version)
+	}
+
+	changed, err = s.client.Providers.HasChanged(ctx, ref, "0.0.1")
+	if err != nil {
+		return fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if !changed {
+		return fmt.Errorf("expected change when known version is outdated")
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testGetSlugIndex(ctx context.Context) error {
+	versionID, err := s.client.Providers.GetVersionID(ctx, "hashicorp", "random", "3.6.0")
+	if err != nil {
+		return fmt.Errorf("failed to get version ID: %w", err)
+	}
+
+	index, err := s.client.Providers.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return fmt.Errorf("failed to get slug index: %w", err)
+	}
+
+	docID, ok := index.Lookup("resources", "string")
+	if !ok {
+		return fmt.Errorf("expected resources/string in slug index")
+	}
+	s.logger.Debugf("random_string doc ID: %s (%d categories)", docID, len(index))
+
+	cached, err := s.client.Providers.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return fmt.Errorf("failed to get cached slug index: %w", err)
+	}
+	if id, _ := cached.Lookup("resources", "string"); id != docID {
+		return fmt.Errorf("expected cached index to match, got %s want %s", id, docID)
+	}
+
+	// Callers get their own copy, so modifying it leaves the cache intact
+	delete(cached["resources"], "string")
+	again, err := s.client.Providers.GetSlugIndex(ctx, versionID)
+	if err != nil {
+		return fmt.Errorf("failed to get cached slug index: %w", err)
+	}
+	if _, ok := again.Lookup("resources", "string"); !ok {
+		return fmt.Errorf("modifying a returned slug index changed the cache")
+	}
+
+	if _, err := s.client.Providers.GetSlugIndex(ctx, ""); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty version ID, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testScrapingFallback(ctx context.Context) error {
+	if _, err := s.client.GetScraper(); !errors.Is(err, registry.ErrScrapingDisabled) {
+		return fmt.Errorf("expected scraping to be disabled by default, got %v", err)
+	}
+
+	var pageFetches atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		case "/providers/hashicorp/random/3.6.0/docs":
+			pageFetches.Add(1)
+			fmt.Fprint(w, `<nav>
+<a href="/providers/hashicorp/random/3.6.0/docs/resources/string">random_string</a>
+<a href="/providers/hashicorp/random/3.6.0/docs/resources/id"><span>random_id</span></a>
+<a href="/providers/hashicorp/random/3.6.0/docs/resources/string">random_string</a>
+</nav>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	scraper, err := scrape.NewHTTPScraper(scrape.WithBaseURL(site.URL))
+	if err != nil {
+		return fmt.Errorf("failed to create scraper: %w", err)
+	}
+
+	client, err := registry.NewClient(registry.WithScraper(scraper), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	configured, err := client.GetScraper()
+	if err != nil {
+		return fmt.Errorf("expected configured scraper: %w", err)
+	}
+
+	ref := registry.ProviderRef{Namespace: "hashicorp", Name: "random", Version: "3.6.0"}
+	for i := 0; i < 2; i++ {
+		items, err := scrape.ProviderDocOrder(ctx, configured, ref)
+		if err != nil {
+			return fmt.Errorf("failed to get doc order: %w", err)
+		}
+		if err := AssertEqual(2, len(items)); err != nil {
+			return err
+		}
+		if err := AssertEqual(scrape.DocNavItem{Category: "resources", Slug: "id", Title: "random_id"}, items[1]); err != nil {
+			return err
+		}
+	}
+
+	if err := AssertEqual(int32(1), pageFetches.Load()); err != nil {
+		return fmt.Errorf("expected cached page on second fetch: %w", err)
+	}
+
+	if _, err := scraper.FetchPage(ctx, "/private/page"); !errors.Is(err, scrape.ErrDisallowed) {
+		return fmt.Errorf("expected robots.txt to disallow /private, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testPinStore(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "pins")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"pins.json", "pins.yaml"} {
+		path := filepath.Join(dir, name)
+
+		store, err := pins.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+
+		if err := store.Add(pins.Pin{Kind: pins.KindProvider, Address: "hashicorp/aws", Constraint: "~> 5.0", Note: "core"}); err != nil {
+			return fmt.Errorf("failed to add provider pin: %w", err)
+		}
+		if err := store.Add(pins.Pin{Kind: pins.KindModule, Address: "terraform-aws-modules/vpc/aws"}); err != nil {
+			return fmt.Errorf("failed to add module pin: %w", err)
+		}
+		if err := store.Add(pins.Pin{Kind: pins.KindModule, Address: "invalid"}); !registry.IsValidationError(err) {
+			return fmt.Errorf("expected validation error for invalid module address, got %v", err)
+		}
+		store.MarkSeen(pins.KindProvider, "hashicorp/aws", "5.0.0")
+
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save %s: %w", name, err)
+		}
+
+		reopened, err := pins.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to reopen %s: %w", name, err)
+		}
+
+		if err := AssertEqual(2, len(reopened.List())); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		pin, ok := reopened.Get(pins.KindProvider, "hashicorp/aws")
+		if !ok || pin.KnownVersion != "5.0.0" || pin.Constraint != "~> 5.0" || pin.Note != "core" {
+			return fmt.Errorf("%s: provider pin not persisted correctly: %+v", name, pin)
+		}
+
+		if !reopened.Remove(pins.KindModule, "terraform-aws-modules/vpc/aws") {
+			return fmt.Errorf("%s: expected module pin to be removed", name)
+		}
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testSecurityAdvisories(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"type": "providers", "id": "1", "attributes": {
+				"namespace": "example", "name": "widget",
+				"source": "https://github.com/example/terraform-provider-widget"}}}`)
+		case "/gh/advisories":
+			if r.URL.Query().Get("affects") != "github.com/example/terraform-provider-widget" {
+				http.Error(w, "unexpected package", http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `[{"ghsa_id": "GHSA-aaaa-bbbb-cccc", "cve_id": "CVE-2024-0001",
+				"summary": "Secrets logged", "severity": "high",
+				"vulnerabilities": [{"package": {"ecosystem": "go", "name": "github.com/example/terraform-provider-widget"},
+					"vulnerable_version_range": ">= 1.0.0, < 1.4.2", "first_patched_version": "1.4.2"}]}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithGitHubAPI(server.URL+"/gh", ""),
+		registry.WithLogger(s.logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	report, err := client.Security.GetAdvisories(ctx, registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.3.0"})
+	if err != nil {
+		return fmt.Errorf("failed to get advisories: %w", err)
+	}
+
+	if err := AssertEqual(1, len(report.Advisories)); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(report.Affected)); err != nil {
+		return fmt.Errorf("expected 1.3.0 to be affected: %w", err)
+	}
+	if err := AssertEqual("1.4.2", report.Affected[0].PatchedVersion); err != nil {
+		return err
+	}
+
+	advisory := report.Advisories[0]
+	if advisory.Affects("1.4.2") || advisory.Affects("0.9.0") || !advisory.Affects("v1.0.0") {
+		return fmt.Errorf("unexpected range matching for %s", advisory.VulnerableRange)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testListFeatured(ctx context.Context) error {
+	featured, err := s.client.Providers.ListFeatured(ctx, &registry.FeaturedListOptions{Tier: "official"})
+	if err != nil {
+		return fmt.Errorf("failed to list featured providers: %w", err)
+	}
+
+	for i, provider := range featured {
+		if !provider.Attributes.Featured {
+			return fmt.Errorf("provider %s is not featured", provider.Attributes.FullName)
+		}
+		if provider.Attributes.Tier != "official" {
+			return fmt.Errorf("provider %s has tier %s", provider.Attributes.FullName, provider.Attributes.Tier)
+		}
+		if i > 0 && provider.Attributes.Downloads > featured[i-1].Attributes.Downloads {
+			return fmt.Errorf("featured providers are not sorted by downloads")
+		}
+	}
+	s.logger.Debugf("Found %d featured official providers", len(featured))
+
+	if _, err := s.client.Providers.ListFeatured(ctx, &registry.FeaturedListOptions{MaxPages: -1}); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for negative max pages, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testResumableSummary(ctx context.Context) error {
+	runCtx, interrupt := context.WithCancel(ctx)
+	defer interrupt()
+
+	var docFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1"}, "included": [{"id": "v1", "attributes": {"version": "1.0.0"}}]}`)
+		case r.URL.Path == "/v2/provider-docs" && r.URL.Query().Get("filter[category]") == "resources":
+			fmt.Fprint(w, `{"data": [{"id": "d1"}, {"id": "d2"}, {"id": "d3"}]}`)
+		case r.URL.Path == "/v2/provider-docs":
+			fmt.Fprint(w, `{"data": [{"id": "d4"}]}`)
+		default:
+			// Interrupt the first run after two doc fetches
+			if docFetches.Add(1) == 2 {
+				interrupt()
+			}
+			id := filepath.Base(r.URL.Path)
+			fmt.Fprintf(w, `{"data": {"id": %q, "attributes": {"slug": %q, "subcategory": "Widgets"}}}`, id, id)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	opts := &registry.SummaryOptions{
+		Checkpoints:        registry.NewMemoryCheckpointStore(),
+		CheckpointInterval: 1,
+	}
+
+	if _, err := client.Providers.GetProviderResourceSummaryWithOptions(runCtx, "example", "widget", "1.0.0", opts); !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("expected interrupted summary, got %v", err)
+	}
+	firstRun := docFetches.Load()
+
+	summary, err := client.Providers.GetProviderResourceSummaryWithOptions(ctx, "example", "widget", "1.0.0", opts)
+	if err != nil {
+		return fmt.Errorf("failed to resume summary: %w", err)
+	}
+
+	if resumed := docFetches.Load() - firstRun; resumed >= 4 {
+		return fmt.Errorf("expected resumed run to skip checkpointed docs, fetched %d", resumed)
+	}
+	if err := AssertEqual(3, len(summary.ResourcesBySubcategory["Widgets"])); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(summary.DataSourcesBySubcategory["Widgets"])); err != nil {
+		return err
+	}
+
+	if _, err := opts.Checkpoints.Load(ctx, "provider-summary/example/widget/1.0.0"); !errors.Is(err, registry.ErrCheckpointNotFound) {
+		return fmt.Errorf("expected checkpoint to be deleted after completion, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testExportDocs(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1"}, "included": [{"id": "v1", "attributes": {"version": "1.0.0"}}]}`)
+		case "/v2/provider-docs":
+			fmt.Fprint(w, `{"data": [
+				{"id": "d1", "attributes": {"category": "resources", "subcategory": "Core Widgets", "slug": "widget", "title": "widget"}},
+				{"id": "d2", "attributes": {"category": "guides", "slug": "getting-started", "title": "Getting Started"}},
+				{"id": "d3", "attributes": {"category": "resources", "subcategory": "Core Widgets", "slug": "broken", "title": "broken"}}
+			]}`)
+		case "/v2/provider-docs/d3":
+			http.Error(w, "boom", http.StatusNotFound)
+		default:
+			id := filepath.Base(r.URL.Path)
+			fmt.Fprintf(w, `{"data": {"id": %q, "attributes": {"content": "# %s"}}}`, id, id)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "terralense-export-docs")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ref := registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.0.0"}
+	manifest, err := client.Providers.ExportDocs(ctx, ref, dir, nil)
+	if err == nil {
+		return fmt.Errorf("expected an error for the failed doc")
+	}
+	if manifest == nil {
+		return fmt.Errorf("expected a partial manifest alongside the error")
+	}
+
+	if err := AssertEqual(2, len(manifest.Docs)); err != nil {
+		return err
+	}
+	if err := AssertEqual("guides/other/getting-started.md", manifest.Docs[0].File); err != nil {
+		return err
+	}
+	if err := AssertEqual("resources/core-widgets/widget.md", manifest.Docs[1].File); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "resources", "core-widgets", "widget.md"))
+	if err != nil {
+		return fmt.Errorf("failed to read exported doc: %w", err)
+	}
+	if err := AssertEqual("# d1", string(content)); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, registry.ExportManifestFile)); err != nil {
+		return fmt.Errorf("expected manifest file: %w", err)
+	}
+
+	opts := &registry.DocExportOptions{Categories: []string{"unknown"}}
+	if _, err := client.Providers.ExportDocs(ctx, ref, dir, opts); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for invalid category, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderMaturity(ctx context.Context) error {
+	published := func(daysAgo int) string {
+		return time.Now().UTC().AddDate(0, 0, -daysAgo).Format(time.RFC3339)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprintf(w, `{
+				"data": {"id": "p1", "attributes": {"tier": "official", "downloads": 1000000000}},
+				"included": [
+					{"id": "v1", "attributes": {"version": "1.0.0", "downloads": 100, "published-at": %q}},
+					{"id": "v2", "attributes": {"version": "1.1.0", "downloads": 300, "published-at": %q}},
+					{"id": "v3", "attributes": {"version": "1.2.0", "downloads": 600, "published-at": %q}}
+				]
+			}`, published(500), published(60), published(10))
+		case "/v2/provider-docs":
+			count := 0
+			switch r.URL.Query().Get("filter[category]") {
+			case "resources":
+				count = 20
+			case "guides":
+				count = 3
+			}
+			fmt.Fprintf(w, `{"data": [], "meta": {"pagination": {"total-count": %d}}}`, count)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	report, err := client.Analyze.ProviderMaturity(ctx, registry.ProviderRef{Namespace: "example", Name: "widget"})
+	if err != nil {
+		return fmt.Errorf("failed to score provider maturity: %w", err)
+	}
+
+	if err := AssertEqual("1.2.0", report.Provider.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, report.Cadence.ReleasesLastYear); err != nil {
+		return err
+	}
+	if err := AssertEqual(20, report.Docs.Resources); err != nil {
+		return err
+	}
+	if err := AssertEqual(0.9, report.Downloads.RecentShare); err != nil {
+		return err
+	}
+	if err := AssertEqual(registry.MaturityMature, report.Grade); err != nil {
+		return err
+	}
+
+	if !strings.Contains(report.Markdown(), "| Tier | 1.00 | official |") {
+		return fmt.Errorf("expected tier row in markdown report:\n%s", report.Markdown())
+	}
+	if _, err := report.JSON(); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testNamingConventions(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/provider-docs" || r.URL.Query().Get("filter[provider-version]") != "pv1" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("filter[category]") {
+		case "resources":
+			fmt.Fprint(w, `{"data": [
+				{"id": "d1", "attributes": {"category": "resources", "slug": "network", "title": "widget_network"}},
+				{"id": "d2", "attributes": {"category": "resources", "slug": "network_peering", "title": "widget_network_peering"}},
+				{"id": "d3", "attributes": {"category": "resources", "slug": "netwrok_route", "title": "widget_netwrok_route"}},
+				{"id": "d4", "attributes": {"category": "resources", "slug": "Disk-Snapshot", "title": "Resource: widget_disk_snapshot"}},
+				{"id": "d5", "attributes": {"category": "resources", "slug": "disk", "title": "Disks"}}
+			]}`)
+		case "data-sources":
+			fmt.Fprint(w, `{"data": [
+				{"id": "d6", "attributes": {"category": "data-sources", "slug": "network", "title": "gadget_network"}}
+			]}`)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	report, err := client.Analyze.NamingConventions(ctx, "pv1")
+	if err != nil {
+		return fmt.Errorf("failed to analyze naming: %w", err)
+	}
+
+	if err := AssertEqual("widget", report.ProviderPrefix); err != nil {
+		return err
+	}
+	if err := AssertEqual(5, report.Resources); err != nil {
+		return err
+	}
+	if err := AssertEqual("network", report.Prefixes[0].Prefix); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, report.Prefixes[0].Count); err != nil {
+		return err
+	}
+
+	var outliers []string
+	for _, outlier := range report.Outliers {
+		outliers = append(outliers, outlier.Category+"/"+outlier.Slug+" "+outlier.Rule)
+	}
+	want := []string{
+		"data-sources/network " + registry.NamingRuleTitleMismatch,
+		"resources/Disk-Snapshot " + registry.NamingRuleSlugFormat,
+		"resources/Disk-Snapshot " + registry.NamingRuleTitleMismatch,
+		"resources/netwrok_route " + registry.NamingRulePrefixVariant,
+	}
+	if err := AssertEqual(strings.Join(want, "\n"), strings.Join(outliers, "\n")); err != nil {
+		return err
+	}
+
+	if _, err := client.Analyze.NamingConventions(ctx, ""); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty version ID, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderLogo(ctx context.Context) error {
+	png := []byte("\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 64))
+
+	var cdnRequests atomic.Int32
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cdnRequests.Add(1)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer cdn.Close()
+
+	var logoRequests, otherRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := strings.CutPrefix(r.URL.Path, "/v2/providers/example/other"); ok {
+			fmt.Fprintf(w, `{"data": {"id": "o%s", "attributes": {"namespace": "example", "name": "other%s", "logo-url": "/logos/other%s.png"}}}`, name, name, name)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/logos/other") {
+			otherRequests.Add(1)
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/providers/example/cdn":
+			fmt.Fprintf(w, `{"data": {"id": "p4", "attributes": {"namespace": "example", "name": "cdn", "logo-url": %q}}}`, cdn.URL+"/cdn.png")
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget", "logo-url": "/logos/widget.png"}}}`)
+		case "/v2/providers/example/plain":
+			fmt.Fprint(w, `{"data": {"id": "p2", "attributes": {"namespace": "example", "name": "plain"}}}`)
+		case "/v2/providers/example/page":
+			fmt.Fprint(w, `{"data": {"id": "p3", "attributes": {"namespace": "example", "name": "page", "logo-url": "/logos/page"}}}`)
+		case "/logos/widget.png":
+			logoRequests.Add(1)
+			// No content type; it is sniffed from the data
+			w.Header().Set("Content-Type", "")
+			w.Write(png)
+		case "/logos/page":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><body>not an image</body></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	widget := registry.ProviderRef{Namespace: "example", Name: "widget"}
+	for i := 0; i < 2; i++ {
+		logo, err := client.Providers.GetLogo(ctx, widget)
+		if err != nil {
+			return fmt.Errorf("failed to get logo: %w", err)
+		}
+		if err := AssertEqual("image/png", logo.ContentType); err != nil {
+			return err
+		}
+		if err := AssertEqual(server.URL+"/logos/widget.png", logo.URL); err != nil {
+			return err
+		}
+		if err := AssertEqual(len(png), len(logo.Data)); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual(int32(1), logoRequests.Load()); err != nil {
+		return fmt.Errorf("logo should be cached: %w", err)
+	}
+
+	if _, err := client.Providers.GetLogo(ctx, registry.ProviderRef{Namespace: "example", Name: "plain"}); !errors.Is(err, registry.ErrNoLogo) {
+		return fmt.Errorf("expected ErrNoLogo, got: %v", err)
+	}
+
+	if _, err := client.Providers.GetLogo(ctx, registry.ProviderRef{Namespace: "example", Name: "page"}); err == nil {
+		return fmt.Errorf("expected error for non-image logo")
+	}
+
+	// Logos from the registry use its rate limit budget; logos from other hosts do not
+	limited, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithRateLimit(10, time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if _, err := limited.Providers.GetLogo(ctx, widget); err != nil {
+		return fmt.Errorf("failed to get logo: %w", err)
+	}
+	if err := AssertEqual(8, limited.GetRateLimiter().TokensRemaining()); err != nil {
+		return fmt.Errorf("registry logo budget: %w", err)
+	}
+	if _, err := limited.Providers.GetLogo(ctx, registry.ProviderRef{Namespace: "example", Name: "cdn"}); err != nil {
+		return fmt.Errorf("failed to get CDN logo: %w", err)
+	}
+	if err := AssertEqual(7, limited.GetRateLimiter().TokensRemaining()); err != nil {
+		return fmt.Errorf("CDN logo budget: %w", err)
+	}
+	if err := AssertEqual(int32(1), cdnRequests.Load()); err != nil {
+		return err
+	}
+
+	// The cache is bounded: with more logos than it keeps, the oldest is downloaded again
+	const others = 60
+	bounded, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithRateLimit(1000, time.Minute))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	for i := 0; i <= others; i++ {
+		if _, err := bounded.Providers.GetLogo(ctx, registry.ProviderRef{Namespace: "example", Name: fmt.Sprintf("other%d", i%others)}); err != nil {
+			return fmt.Errorf("failed to get logo %d: %w", i, err)
+		}
+	}
+	if err := AssertEqual(int32(others+1), otherRequests.Load()); err != nil {
+		return fmt.Errorf("expected the least recently used logo to be evicted: %w", err)
+	}
+
+	small, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithMaxLogoSize(16))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if _, err := small.Providers.GetLogo(ctx, widget); !errors.Is(err, registry.ErrLogoTooLarge) {
+		return fmt.Errorf("expected ErrLogoTooLarge, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderFunctions(ctx context.Context) error {
+	parseARN := "---\npage_title: \"parse_arn function - terraform-provider-widget\"\nsubcategory: \"\"\ndescription: |-\n  Parses an ARN.\n---\n\n" +
+		"# function: parse_arn\n\nParses an ARN into its\nconstituent parts.\n\n" +
+		"## Example Usage\n\n```terraform\noutput \"example\" {\n  value = provider::widget::parse_arn(\"arn:aws:iam::444455556666:role/example\")\n}\n```\n\n" +
+		"## Signature\n\n<!-- signature generated by tfplugindocs -->\n```text\nparse_arn(arn string) object\n```\n\n" +
+		"## Arguments\n\n<!-- arguments generated by tfplugindocs -->\n1. `arn` (String) ARN (Amazon Resource Name) to parse.\n"
+	joinPaths := "# function: join_paths\n\nJoins path segments.\n\n" +
+		"## Signature\n\n```text\njoin_paths(base string, options object({ sep = string, clean = bool }), ...segments string) string\n```\n\n" +
+		"## Arguments\n\n1. `base` (String) Base path.\n1. `options` (Object) Join options.\n\n" +
+		"<!-- variadic argument generated by tfplugindocs -->\n1. `segments` (Variadic, String) Segments to append.\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc := func(id, category, slug, content string) {
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": id, "attributes": map[string]any{"category": category, "slug": slug, "content": content}}})
+		}
+		switch r.URL.Path {
+		case "/v2/provider-docs":
+			if err := AssertEqual("functions", r.URL.Query().Get("filter[category]")); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"data": [{"id": "f2", "attributes": {"category": "functions", "slug": "parse_arn"}}, {"id": "f1", "attributes": {"category": "functions", "slug": "join_paths"}}]}`)
+		case "/v2/provider-docs/f1":
+			doc("f1", "functions", "join_paths", joinPaths)
+		case "/v2/provider-docs/f2":
+			doc("f2", "functions", "parse_arn", parseARN)
+		case "/v2/provider-docs/r1":
+			doc("r1", "resources", "thing", "# widget_thing")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	functions, err := client.Providers.ListFunctions(ctx, "pv1")
+	if err != nil {
+		return fmt.Errorf("failed to list functions: %w", err)
+	}
+	if err := AssertEqual(2, len(functions)); err != nil {
+		return err
+	}
+
+	join := functions[0]
+	if err := AssertEqual("join_paths(base string, options object({ sep = string, clean = bool }), ...segments string) string", join.Signature()); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, len(join.Parameters)); err != nil {
+		return err
+	}
+	if !join.Parameters[2].Variadic || join.Parameters[2].Description != "Segments to append." {
+		return fmt.Errorf("unexpected variadic parameter: %+v", join.Parameters[2])
+	}
+
+	parse, err := client.Providers.GetFunctionDoc(ctx, "f2")
+	if err != nil {
+		return fmt.Errorf("failed to get function doc: %w", err)
+	}
+	if err := AssertEqual("parse_arn", parse.Name); err != nil {
+		return err
+	}
+	if err := AssertEqual("Parses an ARN into its constituent parts.", parse.Summary); err != nil {
+		return err
+	}
+	if err := AssertEqual("object", parse.ReturnType); err != nil {
+		return err
+	}
+	want := []registry.FunctionParameter{{Name: "arn", Type: "string", Description: "ARN (Amazon Resource Name) to parse."}}
+	if err := AssertEqual(want, parse.Parameters); err != nil {
+		return err
+	}
+
+	// Docs outside the functions category are rejected
+	if _, err := client.Providers.GetFunctionDoc(ctx, "r1"); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for a resource doc, got: %v", err)
+	}
+
+	// Docs without a signature block fall back to the argument list
+	fallback := registry.ParseFunctionDoc("# `to_upper` function\n\nUppercases a string.\n\n## Arguments\n\n- `input` (String) Value to convert.\n")
+	if err := AssertEqual("to_upper(input string)", fallback.Signature()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderGuides(ctx context.Context) error {
+	upgrade := "---\nsubcategory: \"\"\npage_title: \"Widget Provider 2.0 Upgrade Guide\"\n---\n\n" +
+		"# Upgrading to 2.0\n\nVersion 2.0 removes deprecated attributes.\n\n" +
+		"## Provider Configuration\n\nThe `region` argument is now required.\n\n" +
+		"```terraform\nprovider \"widget\" {\n  region = \"eu-west-1\"\n}\n```\n\n" +
+		"### Shell\n\n```shell\n# not a heading\nterraform init -upgrade\n```\n\n" +
+		"## Removed Resources\n\n- `widget_legacy`\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/provider-docs":
+			query := r.URL.Query()
+			if err := AssertEqual("guides", query.Get("filter[category]")); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			switch query.Get("filter[slug]") {
+			case "":
+				fmt.Fprint(w, `{"data": [{"id": "g1", "attributes": {"category": "guides", "slug": "version-2-upgrade"}}, {"id": "g2", "attributes": {"category": "guides", "slug": "authentication"}}]}`)
+			case "version-2-upgrade":
+				fmt.Fprint(w, `{"data": [{"id": "g1", "attributes": {"category": "guides", "slug": "version-2-upgrade"}}]}`)
+			default:
+				fmt.Fprint(w, `{"data": []}`)
+			}
+		case "/v2/provider-docs/g1":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "g1", "attributes": map[string]any{"category": "guides", "slug": "version-2-upgrade", "title": "Version 2 Upgrade Guide", "content": upgrade}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	guides, err := client.Providers.ListGuides(ctx, "pv1")
+	if err != nil {
+		return fmt.Errorf("failed to list guides: %w", err)
+	}
+	if err := AssertEqual(2, len(guides)); err != nil {
+		return err
+	}
+
+	guide, err := client.Providers.GetGuide(ctx, "pv1", "version-2-upgrade")
+	if err != nil {
+		return fmt.Errorf("failed to get guide: %w", err)
+	}
+	if err := AssertEqual(upgrade, guide.Markdown); err != nil {
+		return err
+	}
+	if err := AssertEqual("Upgrading to 2.0", guide.Title); err != nil {
+		return err
+	}
+	if err := AssertEqual("Version 2.0 removes deprecated attributes.", guide.Intro); err != nil {
+		return err
+	}
+
+	titles := make([]string, 0, len(guide.Sections))
+	for _, section := range guide.Sections {
+		titles = append(titles, section.Title)
+	}
+	if err := AssertEqual([]string{"Provider Configuration", "Shell", "Removed Resources"}, titles); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, guide.Sections[1].Level); err != nil {
+		return err
+	}
+	if err := AssertEqual("- `widget_legacy`", guide.Sections[2].Content); err != nil {
+		return err
+	}
+
+	want := []registry.GuideExample{
+		{Language: "terraform", Code: "provider \"widget\" {\n  region = \"eu-west-1\"\n}", Section: "Provider Configuration"},
+		{Language: "shell", Code: "# not a heading\nterraform init -upgrade", Section: "Shell"},
+	}
+	if err := AssertEqual(want, guide.Examples); err != nil {
+		return err
+	}
+
+	// Unknown slugs are reported as not found
+	if _, err := client.Providers.GetGuide(ctx, "pv1", "missing"); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found error for a missing guide, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testStructuredDocs(ctx context.Context) error {
+	classic := "---\nsubcategory: \"EC2\"\n---\n\n# Resource: widget_instance\n\nProvides a widget\ninstance.\n\n" +
+		"## Example Usage\n\n```terraform\nresource \"widget_instance\" \"web\" {\n  size = 2\n}\n```\n\n" +
+		"## Argument Reference\n\nThe following arguments are supported:\n\n" +
+		"* `name` - (Required) Name of the instance.\n* `size` - (Optional, Number) Size in GB.\n\n" +
+		"### disk\n\n* `type` - (Required) Disk type.\n\n" +
+		"## Attribute Reference\n\nThe following attributes are exported:\n\n* `id` - The ID.\n\n" +
+		"## Import\n\nIn Terraform v1.5.0 and later, use an `import` block:\n\n" +
+		"```terraform\nimport {\n  to = widget_instance.web\n  id = \"i-12345678\"\n}\n```\n\n" +
+		"Using `terraform import`:\n\n```console\n% terraform import widget_instance.web i-12345678\n```\n"
+	plugindocs := "# widget_disk (Resource)\n\nA disk.\n\n<!-- schema generated by tfplugindocs -->\n## Schema\n\n" +
+		"### Required\n\n- `name` (String) Disk name.\n\n### Optional\n\n- `tags` (Map of String) Tags.\n\n" +
+		"### Read-Only\n\n- `id` (String) The ID of this resource.\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content := map[string]string{"/v2/provider-docs/d1": classic, "/v2/provider-docs/d2": plugindocs}[r.URL.Path]
+		if content == "" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "d", "attributes": map[string]any{"category": "resources", "content": content}}})
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	doc, err := client.Providers.GetDocStructured(ctx, "d1")
+	if err != nil {
+		return fmt.Errorf("failed to get structured doc: %w", err)
+	}
+	if err := AssertEqual("Resource: widget_instance", doc.Title); err != nil {
+		return err
+	}
+	if err := AssertEqual("Provides a widget instance.", doc.Description); err != nil {
+		return err
+	}
+	wantArguments := []docparse.Argument{
+		{Name: "name", Description: "Name of the instance.", Required: true},
+		{Name: "size", Type: "Number", Description: "Size in GB."},
+		{Name: "disk.type", Description: "Disk type.", Required: true},
+	}
+	if err := AssertEqual(wantArguments, doc.Arguments); err != nil {
+		return err
+	}
+	if err := AssertEqual([]docparse.Attribute{{Name: "id", Description: "The ID."}}, doc.Attributes); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(doc.RequiredArguments())); err != nil {
+		return err
+	}
+
+	if err := AssertEqual(2, len(doc.Imports)); err != nil {
+		return err
+	}
+	for _, imp := range doc.Imports {
+		if imp.Address != "widget_instance.web" || imp.ID != "i-12345678" {
+			return fmt.Errorf("unexpected import: %+v", imp)
+		}
+	}
+	if err := AssertEqual(docparse.ImportBlock, doc.Imports[0].Style); err != nil {
+		return err
+	}
+	if err := AssertEqual(docparse.ImportCommand, doc.Imports[1].Style); err != nil {
+		return err
+	}
+
+	// Import blocks are not examples
+	if err := AssertEqual(1, len(doc.Examples)); err != nil {
+		return err
+	}
+	if err := AssertEqual(docparse.Example{Section: "Example Usage", Language: "terraform", Code: "resource \"widget_instance\" \"web\" {\n  size = 2\n}"}, doc.Examples[0]); err != nil {
+		return err
+	}
+
+	// The tfplugindocs layout splits arguments and read-only attributes by group
+	doc, err = client.Providers.GetDocStructured(ctx, "d2")
+	if err != nil {
+		return fmt.Errorf("failed to get structured doc: %w", err)
+	}
+	wantArguments = []docparse.Argument{
+		{Name: "name", Type: "String", Description: "Disk name.", Required: true},
+		{Name: "tags", Type: "Map of String", Description: "Tags."},
+	}
+	if err := AssertEqual(wantArguments, doc.Arguments); err != nil {
+		return err
+	}
+	if err := AssertEqual([]docparse.Attribute{{Name: "id", Type: "String", Description: "The ID of this resource."}}, doc.Attributes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderDownloadMetrics(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"type": "providers", "id": "42", "attributes": {"namespace": "example", "name": "widget", "downloads": 1500}},
+				"included": [
+					{"type": "provider-versions", "id": "1", "attributes": {"version": "1.9.0", "downloads": 500}},
+					{"type": "provider-versions", "id": "2", "attributes": {"version": "1.10.0", "downloads": 1000}}
+				]}`)
+		case "/v2/providers/42/downloads/summary":
+			fmt.Fprint(w, `{"data": {"type": "provider-downloads-summary", "id": "42",
+				"attributes": {"week": 10, "month": 40, "year": 900, "total": 1500}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	metrics, err := client.Providers.GetDownloadMetrics(ctx, "example", "widget")
+	if err != nil {
+		return fmt.Errorf("failed to get download metrics: %w", err)
+	}
+	if err := AssertEqual(registry.ProviderDownloadSummary{Week: 10, Month: 40, Year: 900, Total: 1500}, metrics.Downloads); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(40), metrics.Downloads.Downloads(registry.DownloadWindowMonth)); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(metrics.Versions)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.10.0", metrics.Versions[0].Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(1000), metrics.Versions[0].Downloads); err != nil {
+		return err
+	}
+	if err := AssertTrue(!metrics.FetchedAt.IsZero(), "expected a fetch time"); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.GetDownloadMetrics(ctx, "example", "missing"); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found error, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderTrendReport(ctx context.Context) error {
+	now := time.Now()
+	day := 24 * time.Hour
+	published := func(age time.Duration) string { return now.Add(-age).UTC().Format(time.RFC3339) }
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprintf(w, `{"data": {"type": "providers", "id": "42", "attributes": {"namespace": "example", "name": "widget"}},
+				"included": [
+					{"type": "provider-versions", "id": "1", "attributes": {"version": "1.0.0", "downloads": 900, "published-at": %q}},
+					{"type": "provider-versions", "id": "2", "attributes": {"version": "1.1.0", "downloads": 500, "published-at": %q}},
+					{"type": "provider-versions", "id": "3", "attributes": {"version": "1.2.0", "downloads": 200, "published-at": %q}}
+				]}`, published(100*day), published(40*day), published(10*day))
+		case "/v2/providers/42/downloads/summary":
+			fmt.Fprint(w, `{"data": {"attributes": {"week": 50, "month": 300, "year": 1600, "total": 1600}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// Two earlier snapshots, the second of which first lists 1.1.0
+	history := []registry.VersionSnapshot{
+		{TakenAt: now.Add(-50 * day), LatestVersion: "1.0.0", Downloads: 1000,
+			Versions: []registry.VersionRelease{{Version: "1.0.0", PublishedAt: now.Add(-100 * day)}}},
+		{TakenAt: now.Add(-20 * day), LatestVersion: "1.1.0", Downloads: 1300,
+			Versions: []registry.VersionRelease{{Version: "1.1.0"}, {Version: "1.0.0"}}},
+	}
+	data, _ := json.Marshal(history)
+	store := registry.NewMemoryCheckpointStore()
+	if err := store.Save(ctx, "history/provider/example/widget", data); err != nil {
+		return err
+	}
+
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithHistoryStore(store),
+		registry.WithLogger(s.logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ref := registry.ProviderRef{Namespace: "example", Name: "widget"}
+	report, err := client.Providers.TrendReport(ctx, ref, 60*day)
+	if err != nil {
+		return fmt.Errorf("failed to build trend report: %w", err)
+	}
+
+	var releases []string
+	for _, release := range report.Releases {
+		releases = append(releases, release.Version)
+	}
+	if err := AssertEqual([]string{"1.1.0", "1.2.0"}, releases); err != nil {
+		return err
+	}
+	if err := AssertEqual(1.0, report.ReleasesPerMonth); err != nil {
+		return err
+	}
+	if err := AssertEqual(30*day, report.AverageTimeBetweenReleases.Round(time.Hour)); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, report.Snapshots); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(600), report.DownloadGrowth); err != nil {
+		return err
+	}
+	if err := AssertEqual(0.6, report.DownloadGrowthRate); err != nil {
+		return err
+	}
+	if err := AssertTrue(report.DownloadsPerDay > 11.9 && report.DownloadsPerDay < 12.1,
+		fmt.Sprintf("expected 12 downloads per day, got %f", report.DownloadsPerDay)); err != nil {
+		return err
+	}
+
+	// The report recorded a new snapshot
+	data, err = store.Load(ctx, "history/provider/example/widget")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, len(history)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.2.0", history[2].LatestVersion); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.TrendReport(ctx, ref, 0); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for an empty window, got %v", err)
+	}
+
+	unconfigured, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if _, err := unconfigured.Providers.RecordHistory(ctx, ref); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected invalid configuration without a history store, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testGetManyProviders(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"type": "providers", "id": "42", "attributes": {"namespace": "example", "name": "widget", "tier": "partner"}}}`)
+		case "/v1/providers/example/widget":
+			fmt.Fprint(w, `{"namespace": "example", "name": "widget", "version": "1.2.0"}`)
+		case "/v1/providers/example/widget/1.0.0":
+			fmt.Fprint(w, `{"namespace": "example", "name": "widget", "version": "1.0.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	latest := registry.ProviderRef{Namespace: "example", Name: "widget"}
+	pinned := registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.0.0"}
+	missing := registry.ProviderRef{Namespace: "example", Name: "missing"}
+
+	batch, err := client.Providers.GetMany(ctx, []registry.ProviderRef{latest, pinned, missing, latest})
+	if err != nil {
+		return fmt.Errorf("failed to get providers: %w", err)
+	}
+	if err := AssertEqual(3, len(batch)); err != nil {
+		return fmt.Errorf("result count: %w", err)
+	}
+	if result := batch[latest]; result.Err != nil || result.Value.Version != "1.2.0" || result.Value.ID != "42" {
+		return fmt.Errorf("unexpected result for latest version: %+v", result)
+	}
+	if result := batch[pinned]; result.Err != nil || result.Value.Version != "1.0.0" {
+		return fmt.Errorf("unexpected result for pinned version: %+v", result)
+	}
+	if !registry.IsNotFound(batch[missing].Err) {
+		return fmt.Errorf("expected not found error, got %v", batch[missing].Err)
+	}
+
+	if _, err := client.Providers.GetMany(ctx, nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for empty list, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testOpenTofuBackend(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/registry/docs/providers/example/widget/index.json":
+			fmt.Fprint(w, `{"description": "Widgets", "link": "https://github.com/example/terraform-provider-widget", "versions": [
+				{"id": "v1.0.0", "published": "2024-01-01T00:00:00Z"},
+				{"id": "v1.2.0", "published": "2024-06-01T00:00:00Z"}
+			]}`)
+		case "/registry/docs/providers/example/widget/v1.2.0/index.json":
+			fmt.Fprint(w, `{"id": "v1.2.0", "docs": {
+				"index": {"name": "index", "title": "Widget Provider"},
+				"resources": [{"name": "gear", "title": "widget_gear", "subcategory": "Compute"}],
+				"datasources": [{"name": "gear", "title": "widget_gear"}]
+			}}`)
+		case "/registry/docs/providers/example/widget/v1.2.0/resources/gear.md":
+			fmt.Fprint(w, "# widget_gear\n\nManages a gear.\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(
+		registry.WithBackend(registry.BackendOpenTofu),
+		registry.WithOpenTofuDocsURL(server.URL),
+		registry.WithLogger(s.logger),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if err := AssertEqual(registry.OpenTofuBaseURL, client.GetBaseURL()); err != nil {
+		return err
+	}
+
+	latest, err := client.Providers.GetLatest(ctx, "example", "widget")
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if err := AssertEqual("1.2.0", latest.Version); err != nil {
+		return err
+	}
+
+	// Version and doc IDs are derived from names, as OpenTofu has no numeric IDs
+	versionID, err := client.Providers.GetVersionID(ctx, "example", "widget", "")
+	if err != nil {
+		return fmt.Errorf("failed to get version ID: %w", err)
+	}
+	if err := AssertEqual("example/widget/1.2.0", versionID); err != nil {
+		return err
+	}
+
+	resources, err := client.Providers.ListDocsV2(ctx, &registry.ProviderDocListOptions{
+		ProviderVersionID: versionID,
+		Category:          "resources",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list docs: %w", err)
+	}
+	if err := AssertEqual(1, len(resources)); err != nil {
+		return err
+	}
+
+	doc, err := client.Providers.GetDoc(ctx, resources[0].ID)
+	if err != nil {
+		return fmt.Errorf("failed to get doc: %w", err)
+	}
+	if err := AssertEqual("Compute", doc.Data.Attributes.Subcategory); err != nil {
+		return err
+	}
+	if err := AssertContains(doc.Data.Attributes.Content, "Manages a gear."); err != nil {
+		return err
+	}
+
+	// Features without an OpenTofu equivalent say so
+	if _, err := client.Providers.List(ctx, nil); !errors.Is(err, registry.ErrUnsupportedByBackend) {
+		return fmt.Errorf("expected listing providers to be unsupported, got: %v", err)
+	}
+
+	if _, err := registry.NewClient(registry.WithBackend("pulumi")); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected an unknown backend to be rejected, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testDocPins(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}, "included": [
+				{"type": "provider-versions", "id": "v1", "attributes": {"version": "1.0.0"}},
+				{"type": "provider-versions", "id": "v2", "attributes": {"version": "2.0.0"}}
+			]}`)
+		case "/v2/provider-docs":
+			fmt.Fprint(w, `{"data": []}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	pinned := registry.WithDocPins(ctx, registry.DocPins{"Example/Widget": "1.0.0"})
+
+	// Unversioned requests resolve to the pinned version, explicit versions are kept
+	for _, tc := range []struct {
+		ctx     context.Context
+		version string
+		want    string
+	}{
+		{ctx, "", "v2"},
+		{pinned, "", "v1"},
+		{pinned, "latest", "v1"},
+		{pinned, "2.0.0", "v2"},
+	} {
+		id, err := client.Providers.GetVersionID(tc.ctx, "example", "widget", tc.version)
+		if err != nil {
+			return fmt.Errorf("failed to get version ID for %q: %w", tc.version, err)
+		}
+		if err := AssertEqual(tc.want, id); err != nil {
+			return err
+		}
+	}
+
+	schema, err := client.Providers.GetSchema(pinned, "example", "widget", "")
+	if err != nil {
+		return fmt.Errorf("failed to get pinned schema: %w", err)
+	}
+	if err := AssertEqual("1.0.0", schema.Provider.Version); err != nil {
+		return err
+	}
+
+	// The latest release is still reported
+	latest, err := client.Providers.GetLatest(pinned, "example", "widget")
+	if err != nil {
+		return fmt.Errorf("failed to get latest version: %w", err)
+	}
+	if err := AssertEqual("2.0.0", latest.Version); err != nil {
+		return err
+	}
+
+	// Pin maps round-trip through files and are validated
+	dir, err := os.MkdirTemp("", "terralense-docpins-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "docpins.yaml")
+	if err := pins.SaveDocPins(path, registry.DocPins{"example/widget": "1.0.0", "hashicorp/aws": "5.31.0"}); err != nil {
+		return fmt.Errorf("failed to save doc pins: %w", err)
+	}
+	loaded, err := pins.LoadDocPins(path)
+	if err != nil {
+		return fmt.Errorf("failed to load doc pins: %w", err)
+	}
+	if version, _ := loaded.Version("hashicorp", "aws"); version != "5.31.0" {
+		return fmt.Errorf("expected hashicorp/aws pinned to 5.31.0, got %q", version)
+	}
+
+	if err := pins.SaveDocPins(path, registry.DocPins{"widget": "latest"}); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected invalid doc pins to be rejected, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testSimilarDocs(ctx context.Context) error {
+	docs := map[string][3]string{
+		"1": {"security_group", "aws_security_group", "Provides a security group resource. Ingress and egress rules control traffic to instances in a VPC."},
+		"2": {"security_group_rule", "aws_security_group_rule", "Provides a security group rule resource. Represents a single ingress or egress rule of a security group in a VPC."},
+		"3": {"instance", "aws_instance", "Provides an EC2 instance resource. Instances run an AMI and can be assigned security groups."},
+		"4": {"s3_bucket", "aws_s3_bucket", "Provides an S3 bucket resource for storing objects."},
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		doc, ok := docs[strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"id": strings.TrimPrefix(r.URL.Path, "/v2/provider-docs/"),
+			"attributes": map[string]any{
+				"category": "resources",
+				"slug":     doc[0],
+				"title":    doc[1],
+				"content":  "# " + doc[1] + "\n\n" + doc[2],
+			},
+		}})
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	for _, id := range []string{"2", "3", "4"} {
+		if _, err := client.Providers.GetDoc(ctx, id); err != nil {
+			return fmt.Errorf("failed to get doc %s: %w", id, err)
+		}
+	}
+
+	// The target is fetched when unknown, then ranked against the fetched docs
+	similar, err := client.Providers.SimilarDocs(ctx, "1", 2)
+	if err != nil {
+		return fmt.Errorf("failed to get similar docs: %w", err)
+	}
+	if err := AssertEqual(2, len(similar)); err != nil {
+		return err
+	}
+	if err := AssertEqual("security_group_rule", similar[0].Slug); err != nil {
+		return err
+	}
+	if similar[0].Score <= similar[1].Score || similar[0].Score > 1 {
+		return fmt.Errorf("unexpected scores %v and %v", similar[0].Score, similar[1].Score)
+	}
+
+	// Known docs are ranked without further requests
+	before := requests.Load()
+	if _, err := client.Providers.SimilarDocs(ctx, "1", 0); err != nil {
+		return fmt.Errorf("failed to get similar docs again: %w", err)
+	}
+	if err := AssertEqual(before, requests.Load()); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.SimilarDocs(ctx, "1", -1); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected a negative limit to be rejected, got: %v", err)
+	}
+	if _, err := client.Providers.SimilarDocs(ctx, "missing", 0); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected an unknown doc to be not found, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderSDKGeneration(ctx context.Context) error {
+	resourceDoc := "## Schema\n\n" +
+		"### Required\n\n- `name` (String) Name of the thing.\n- `zones` (Set of String) Zones.\n\n" +
+		"### Optional\n\n- `size` (Number) Size in GB.\n- `tags` (Map of String) Tags.\n" +
+		"- `disk` (Block List, Max: 1) (see [below for nested schema](#nestedblock--disk))\n\n" +
+		"### Read-Only\n\n- `id` (String) The ID of this resource.\n\n" +
+		"### Nested Schema for `disk`\n\nRequired:\n\n- `type` (String) Disk type.\n"
+	dataSourceDoc := "## Argument Reference\n\n* `name` - (Required) Name of the thing.\n* `filter` - (Optional) A filter block.\n\n" +
+		"The `filter` block supports:\n\n* `key` - (Required) Filter key.\n"
+
+	schema := &registry.ProviderSchema{
+		Provider:    registry.ProviderRef{Namespace: "example", Name: "widget-beta", Version: "1.2.0"},
+		Resources:   []registry.ResourceSchema{{Name: "widget_thing", Category: "resources", Subcategory: "Core", Attributes: registry.ParseAttributeSchemas(resourceDoc)}},
+		DataSources: []registry.ResourceSchema{{Name: "widget_thing", Category: "data-sources", Attributes: registry.ParseAttributeSchemas(dataSourceDoc)}},
+	}
+
+	// Go helper structs
+	source, err := sdkgen.Go(schema, nil)
+	if err != nil {
+		return fmt.Errorf("failed to generate Go: %w", err)
+	}
+	// gofmt aligns fields, so the code is compared with whitespace collapsed
+	code := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"package widgetbeta",
+		"const ProviderVersion = \"1.2.0\"",
+		"type WidgetThing struct {",
+		"\tName string `json:\"name\"`",
+		"\tZones []string `json:\"zones\"`",
+		"\tSize *float64 `json:\"size,omitempty\"`",
+		"\tTags map[string]string `json:\"tags,omitempty\"`",
+		"\tDisk *WidgetThingDisk `json:\"disk,omitempty\"`",
+		"// ID (read-only) The ID of this resource. ID *string `json:\"id,omitempty\"`",
+		"type WidgetThingDisk struct {",
+		"type DataWidgetThing struct {",
+		"\tFilter []DataWidgetThingFilter `json:\"filter,omitempty\"`",
+		"type DataWidgetThingFilter struct {",
+	} {
+		if !strings.Contains(code, strings.Join(strings.Fields(want), " ")) {
+			return fmt.Errorf("generated Go lacks %q:\n%s", want, source)
+		}
+	}
+
+	custom, err := sdkgen.Go(schema, &sdkgen.GoOptions{Package: "widget"})
+	if err != nil || !strings.Contains(string(custom), "package widget\n") {
+		return fmt.Errorf("expected package widget, got error %v", err)
+	}
+	if _, err := sdkgen.Go(schema, &sdkgen.GoOptions{Package: "not-valid"}); err == nil {
+		return fmt.Errorf("expected error for an invalid package name")
+	}
+
+	// JSON Schema bundles
+	data, err := sdkgen.JSONSchema(schema)
+	if err != nil {
+		return fmt.Errorf("failed to generate JSON schema: %w", err)
+	}
+	var bundle sdkgen.Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	if err := AssertEqual(sdkgen.JSONSchemaDialect, bundle.Schema); err != nil {
+		return err
+	}
+	if err := AssertEqual("example/widget-beta 1.2.0", bundle.Title); err != nil {
+		return err
+	}
+
+	resource := bundle.Defs["resource.widget_thing"]
+	if resource == nil || bundle.Defs["data.widget_thing"] == nil {
+		return fmt.Errorf("bundle definitions missing: %s", data)
+	}
+	if err := AssertEqual([]string{"name", "zones"}, resource.Required); err != nil {
+		return err
+	}
+	if err := AssertEqual("Core", resource.Subcategory); err != nil {
+		return err
+	}
+	zones := resource.Properties["zones"]
+	if zones.Type != "array" || !zones.UniqueItems || zones.Items.Type != "string" {
+		return fmt.Errorf("unexpected zones schema: %+v", zones)
+	}
+	if tags := resource.Properties["tags"]; tags.Type != "object" || tags.AdditionalProperties.Type != "string" {
+		return fmt.Errorf("unexpected tags schema: %+v", tags)
+	}
+	if id := resource.Properties["id"]; !id.ReadOnly || id.Type != "string" {
+		return fmt.Errorf("unexpected id schema: %+v", id)
+	}
+	disk := resource.Properties["disk"]
+	if err := AssertEqual([]string{"type"}, disk.Required); err != nil {
+		return fmt.Errorf("disk block: %w", err)
+	}
+	filter := bundle.Defs["data.widget_thing"].Properties["filter"]
+	if filter.Type != "array" || filter.Items.Properties["key"] == nil {
+		return fmt.Errorf("unexpected filter schema: %+v", filter)
+	}
+
+	// The output is stable so that bundles can be committed and diffed
+	again, _ := sdkgen.JSONSchema(schema)
+	if err := AssertEqual(string(data), string(again)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderSchema(ctx context.Context) error {
+	resourceDoc := "# widget_thing (Resource)\n\n" +
+		"```terraform\nresource \"widget_thing\" \"example\" {\n  name = \"x\"\n}\n```\n\n" +
+		"<!-- schema generated by tfplugindocs -->\n## Schema\n\n" +
+		"### Required\n\n- `name` (String) Name of the thing.\n\n" +
+		"### Optional\n\n- `size` (Number) Size in GB.\n- `disk` (Block List, Max: 1) (see [below for nested schema](#nestedblock--disk))\n\n" +
+		"### Read-Only\n\n- `id` (String) The ID of this resource.\n\n" +
+		"<a id=\"nestedblock--disk\"></a>\n### Nested Schema for `disk`\n\nRequired:\n\n- `type` (String) Disk type.\n\n" +
+		"## Import\n\n- `ignored` (String) Not part of the schema.\n"
+
+	dataSourceDoc := "# widget_thing\n\n## Argument Reference\n\nThe following arguments are supported:\n\n" +
+		"* `name` - (Required) Name of the thing.\n" +
+		"* `filter` - (Optional, Forces new resource) A filter block.\n" +
+		"* `labels` - (Optional, Map) Labels to match.\n\n" +
+		"The `filter` block supports:\n\n* `key` - (Required) Filter key.\n\n" +
+		"## Attributes Reference\n\nIn addition to all arguments above, the following attributes are exported:\n\n" +
+		"* `id` - The ID.\n* `labels` - Labels of the thing.\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/providers/example/widget":
+			fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}},
+				"included": [{"type": "provider-versions", "id": "pv1", "attributes": {"version": "1.0.0"}}]}`)
+		case "/v2/provider-docs":
+			switch r.URL.Query().Get("filter[category]") {
+			case "resources":
+				fmt.Fprint(w, `{"data": [{"id": "d1", "attributes": {"category": "resources", "slug": "thing", "subcategory": "Core"}}]}`)
+			case "data-sources":
+				fmt.Fprint(w, `{"data": [{"id": "d2", "attributes": {"category": "data-sources", "slug": "thing"}}, {"id": "d3", "attributes": {"category": "data-sources", "slug": "broken"}}]}`)
+			default:
+				fmt.Fprint(w, `{"data": [{"id": "d1", "attributes": {"category": "resources", "slug": "thing"}}]}`)
+			}
+		case "/v2/provider-docs/d1":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "d1", "attributes": map[string]any{"slug": "thing", "content": resourceDoc}}})
+		case "/v2/provider-docs/d2":
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"id": "d2", "attributes": map[string]any{"slug": "thing", "content": dataSourceDoc}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// The broken data source doc is reported while the other schemas are returned
+	schema, err := client.Providers.GetSchema(ctx, "example", "widget", "1.0.0")
+	if !registry.IsNotFound(err) || schema == nil {
+		return fmt.Errorf("expected not found for the broken data source doc, got: %v", err)
+	}
+	if err := AssertEqual(1, len(schema.Resources)); err != nil {
+		return err
+	}
+
+	resource := schema.Resource("widget_thing")
+	if resource == nil {
+		return fmt.Errorf("widget_thing resource schema missing")
+	}
+	if err := AssertEqual("Core", resource.Subcategory); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, attribute := range resource.Attributes {
+		names = append(names, attribute.Name)
+	}
+	if err := AssertEqual("name,size,disk,id,disk.type", strings.Join(names, ",")); err != nil {
+		return err
+	}
+
+	name, _ := resource.Attribute("name")
+	if !name.Required || name.Type != "String" || name.Description != "Name of the thing." {
+		return fmt.Errorf("unexpected name attribute: %+v", name)
+	}
+	disk, _ := resource.Attribute("disk")
+	if !disk.Optional || disk.Type != "Block List, Max: 1" || disk.Description != "" {
+		return fmt.Errorf("unexpected disk attribute: %+v", disk)
+	}
+	id, _ := resource.Attribute("id")
+	if !id.Computed || id.Optional || id.Required {
+		return fmt.Errorf("unexpected id attribute: %+v", id)
+	}
+	if err := AssertEqual(2, len(resource.RequiredAttributes())); err != nil {
+		return err
+	}
+
+	dataSource := schema.DataSource("widget_thing")
+	if dataSource == nil {
+		return fmt.Errorf("widget_thing data source schema missing")
+	}
+	names = nil
+	for _, attribute := range dataSource.Attributes {
+		names = append(names, attribute.Name)
+	}
+	if err := AssertEqual("name,filter,labels,filter.key,id", strings.Join(names, ",")); err != nil {
+		return err
+	}
+
+	labels, _ := dataSource.Attribute("labels")
+	if !labels.Optional || !labels.Computed || labels.Type != "Map" || labels.Description != "Labels to match." {
+		return fmt.Errorf("unexpected labels attribute: %+v", labels)
+	}
+	filter, _ := dataSource.Attribute("filter")
+	if !filter.Optional || filter.Type != "" {
+		return fmt.Errorf("unexpected filter attribute: %+v", filter)
+	}
+
+	single, err := client.Providers.GetResourceSchema(ctx, registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.0.0"}, "resources", "thing")
+	if err != nil {
+		return fmt.Errorf("failed to get resource schema: %w", err)
+	}
+	if err := AssertEqual(len(resource.Attributes), len(single.Attributes)); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.GetResourceSchema(ctx, registry.ProviderRef{Namespace: "example", Name: "widget", Version: "1.0.0"}, "resources", "missing"); !registry.IsNotFound(err) {
+		return fmt.Errorf("expected not found for undocumented resource, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testMirrorPlan(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/providers/example/widget/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"versions": [
+			{"version": "1.9.0", "platforms": [{"os": "linux", "arch": "amd64"}]},
+			{"version": "2.0.0", "platforms": [{"os": "linux", "arch": "amd64"}, {"os": "darwin", "arch": "arm64"}]},
+			{"version": "2.1.0", "platforms": [{"os": "linux", "arch": "amd64"}]},
+			{"version": "2.2.0-beta1", "platforms": [{"os": "linux", "arch": "amd64"}]},
+			{"version": "3.0.0", "platforms": [{"os": "linux", "arch": "amd64"}]}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	linux := registry.ProviderPlatform{OS: "linux", Arch: "amd64"}
+	darwin := registry.ProviderPlatform{OS: "darwin", Arch: "arm64"}
+
+	existing := &registry.MirrorManifest{}
+	existing.Add("2.0.0", linux)
+	existing.Add("1.9.0", linux)
+
+	ref := registry.ProviderRef{Namespace: "example", Name: "widget"}
+	plan, err := client.Providers.PlanMirror(ctx, ref, "~> 2.0", []registry.ProviderPlatform{linux, darwin}, existing)
+	if err != nil {
+		return fmt.Errorf("failed to plan mirror: %w", err)
+	}
+
+	packages := func(list []registry.MirrorPackage) string {
+		var names []string
+		for _, p := range list {
+			names = append(names, p.String())
+		}
+		return strings.Join(names, ", ")
+	}
+
+	// Pre-releases are left out unless named exactly
+	if err := AssertEqual("2.1.0,2.0.0", strings.Join(plan.Versions, ",")); err != nil {
+		return err
+	}
+	if err := AssertEqual("2.1.0 linux_amd64, 2.0.0 darwin_arm64", packages(plan.Download)); err != nil {
+		return err
+	}
+	if err := AssertEqual("2.0.0 linux_amd64", packages(plan.Present)); err != nil {
+		return err
+	}
+	if err := AssertEqual("2.1.0 darwin_arm64", packages(plan.Unavailable)); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.9.0 linux_amd64", packages(plan.Stale)); err != nil {
+		return err
+	}
+
+	// Without platforms every published platform is planned
+	plan, err = client.Providers.PlanMirror(ctx, ref, "= 2.0.0", nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to plan mirror: %w", err)
+	}
+	if err := AssertEqual("2.0.0 linux_amd64, 2.0.0 darwin_arm64", packages(plan.Download)); err != nil {
+		return err
+	}
+
+	constraints := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "3.0.0", true},
+		{"", "2.2.0-beta1", false},
+		{"2.2.0-beta1", "2.2.0-beta1", true},
+		{">= 2.0, < 3.0", "2.9.9", true},
+		{">= 2.0, < 3.0", "3.0.0", false},
+		{"~> 2.1.0", "2.1.9", true},
+		{"~> 2.1.0", "2.2.0", false},
+		{"~> 2", "2.9.0", true},
+		{"~> 2", "3.0.0", false},
+		{"!= 2.1.0", "2.1.0", false},
+		{"> 1.9", "1.9.0", false},
+	}
+	for _, tc := range constraints {
+		c, err := registry.ParseVersionConstraint(tc.constraint)
+		if err != nil {
+			return fmt.Errorf("failed to parse %q: %w", tc.constraint, err)
+		}
+		if c.Check(tc.version) != tc.want {
+			return fmt.Errorf("constraint %q on %s: expected %v", tc.constraint, tc.version, tc.want)
+		}
+	}
+
+	if _, err := client.Providers.PlanMirror(ctx, ref, ">= two", nil, nil); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for invalid constraint, got: %v", err)
+	}
+
+	return nil
+}
+
+func (s *ProviderTests) testProviderDownload(ctx context.Context) error {
+	signer, err := openpgp.NewEntity("Example Widget", "", "security@example.com", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+	other, err := openpgp.NewEntity("Someone Else", "", "other@example.com", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	armored := func(entity *openpgp.Entity) string {
+		var buf bytes.Buffer
+		w, _ := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+		entity.Serialize(w)
+		w.Close()
+		return buf.String()
+	}
+
+	pkg := []byte("PK\x03\x04 provider binary")
+	sum := sha256.Sum256(pkg)
+	shasum := hex.EncodeToString(sum[:])
+	shasums := []byte(shasum + "  terraform-provider-widget_1.0.0_linux_amd64.zip\n" +
+		strings.Repeat("0", 64) + "  terraform-provider-widget_1.0.0_darwin_arm64.zip\n")
+
+	var signature bytes.Buffer
+	if err := openpgp.DetachSign(&signature, signer, bytes.NewReader(shasums), nil); err != nil {
+		return fmt.Errorf("failed to sign: %w", err)
+	}
+
+	keyArmor := armored(signer)
+	var served atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/providers/example/widget/1.0.0/download/linux/amd64":
+			json.NewEncoder(w).Encode(map[string]any{
+				"protocols":             []string{"5.0"},
+				"os":                    "linux",
+				"arch":                  "amd64",
+				"filename":              "terraform-provider-widget_1.0.0_linux_amd64.zip",
+				"download_url":          "/files/widget.zip",
+				"shasums_url":           "/files/SHA256SUMS",
+				"shasums_signature_url": "/files/SHA256SUMS.sig",
+				"shasum":                shasum,
+				"signing_keys": map[string]any{
+					"gpg_public_keys": []map[string]any{{"key_id": signer.PrimaryKey.KeyIdString(), "ascii_armor": keyArmor}},
+				},
+			})
+		case "/v2/gpg-keys":
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{
+					"type":       "gpg-keys",
+					"id":         "1",
+					"attributes": map[string]any{"key-id": signer.PrimaryKey.KeyIdString(), "ascii-armor": keyArmor, "namespace": r.URL.Query().Get("filter[namespace]")},
+				}},
+			})
+		case "/files/widget.zip":
+			served.Add(1)
+			w.Write(pkg)
+		case "/files/tampered.zip":
+			w.Write(append(pkg, '!'))
+		case "/files/SHA256SUMS":
+			w.Write(shasums)
+		case "/files/SHA256SUMS.sig":
+			w.Write(signature.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	download, err := client.Providers.GetDownload(ctx, "example", "widget", "1.0.0", "linux", "amd64")
+	if err != nil {
+		return fmt.Errorf("failed to get download: %w", err)
+	}
+	if err := AssertEqual(server.URL+"/files/widget.zip", download.DownloadURL); err != nil {
+		return err
+	}
+	if err := AssertEqual(1, len(download.SigningKeys.GPGPublicKeys)); err != nil {
+		return err
+	}
+
+	verifier := gpg.NewVerifier()
+	var out bytes.Buffer
+	verification, err := client.Providers.DownloadPackage(ctx, download, verifier, &out)
+	if err != nil {
+		return fmt.Errorf("failed to download package: %w", err)
+	}
+	if err := AssertEqual(signer.PrimaryKey.KeyIdString(), verification.KeyID); err != nil {
+		return err
+	}
+	if err := AssertEqual(string(pkg), out.String()); err != nil {
+		return err
+	}
+
+	// Downloading to a file verifies the same checksums
+	dir, err := os.MkdirTemp("", "terralense-package-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, download.Filename)
+	verification, err = client.Providers.DownloadPackageFile(ctx, download, verifier, path)
+	if err != nil {
+		return fmt.Errorf("failed to download package file: %w", err)
+	}
+	if err := AssertEqual(shasum, verification.SHA256); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(pkg, data) {
+		return fmt.Errorf("package file differs from the served one: %v", err)
+	}
+
+	// Content that does not match the checksum
+	tampered := *download
+	tampered.DownloadURL = server.URL + "/files/tampered.zip"
+	if _, err := client.Providers.DownloadPackage(ctx, &tampered, verifier, io.Discard); !errors.Is(err, registry.ErrChecksumMismatch) {
+		return fmt.Errorf("expected checksum mismatch, got: %v", err)
+	}
+
+	// A shasum that SHA256SUMS does not list for the file is rejected before downloading
+	served.Store(0)
+	wrongSum := *download
+	wrongSum.SHASum = strings.Repeat("0", 64)
+	if _, err := client.Providers.DownloadPackage(ctx, &wrongSum, verifier, io.Discard); !errors.Is(err, registry.ErrChecksumMismatch) {
+		return fmt.Errorf("expected checksum mismatch for wrong shasum, got: %v", err)
+	}
+
+	// A signature from a key that is not among the signing keys
+	untrusted := *download
+	untrusted.SigningKeys.GPGPublicKeys = []registry.GPGPublicKey{{KeyID: other.PrimaryKey.KeyIdString(), ASCIIArmor: armored(other)}}
+	if _, err := client.Providers.DownloadPackage(ctx, &untrusted, verifier, io.Discard); !errors.Is(err, registry.ErrInvalidSignature) {
+		return fmt.Errorf("expected invalid signature, got: %v", err)
+	}
+	if err := AssertEqual(int32(0), served.Load()); err != nil {
+		return fmt.Errorf("package downloaded despite failed checks: %w", err)
+	}
+
+	if _, err := client.Providers.DownloadPackage(ctx, download, nil, io.Discard); !errors.Is(err, registry.ErrNoSignatureVerifier) {
+		return fmt.Errorf("expected missing verifier error, got: %v", err)
+	}
+
+	if _, err := client.Providers.GetDownload(ctx, "example", "widget", "latest", "linux", "amd64"); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for latest, got: %v", err)
+	}
+
+	// Keys listed for the namespace verify the signature end to end
+	keys, err := client.Providers.GetSigningKeys(ctx, "example")
+	if err != nil {
+		return fmt.Errorf("failed to get signing keys: %w", err)
+	}
+	if err := AssertEqual(1, len(keys)); err != nil {
+		return err
+	}
+	keyID, err := gpg.VerifyProviderSignature(shasums, signature.Bytes(), keys)
+	if err != nil {
+		return fmt.Errorf("failed to verify signature with namespace keys: %w", err)
+	}
+	if err := AssertEqual(signer.PrimaryKey.KeyIdString(), keyID); err != nil {
+		return err
+	}
+	if _, err := gpg.VerifyProviderSignature(append(shasums, '\n'), signature.Bytes(), keys); !errors.Is(err, registry.ErrInvalidSignature) {
+		return fmt.Errorf("expected invalid signature for altered SHA256SUMS, got: %v", err)
+	}
+
+	return nil
+}
@@ -2,10 +2,16 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"time"
 
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registry/query"
 
 	"github.com/sirupsen/logrus"
 )
@@ -34,6 +40,11 @@ func (s *SearchTests) setupTests() {
 	s.AddTest("Case Sensitivity", "Test case sensitivity in search", s.testCaseSensitivity)
 	s.AddTest("Partial Matches", "Test partial word matching", s.testPartialMatches)
 	s.AddTest("Multi-Word Search", "Test multi-word search queries", s.testMultiWordSearch)
+	s.AddTest("Query Filter", "Test compiling and applying filter expressions", s.testQueryFilter)
+	s.AddTest("Find Duplicates", "Test clustering forked modules and ranking the canonical one", s.testFindDuplicates)
+	s.AddTest("Collapse Results", "Test collapsing search results to one entry per module", s.testCollapseResults)
+	s.AddTest("Expanded Search", "Test merging weighted synonym queries with combined scores", s.testExpandedSearch)
+	s.AddTest("Published Filters", "Test published-at recency filters across time zones", s.testPublishedFilters)
 }
 
 func (s *SearchTests) testModuleSearchRelevance(ctx context.Context) error {
@@ -345,3 +356,304 @@ func (s *SearchTests) testMultiWordSearch(ctx context.Context) error {
 
 	return nil
 }
+
+func (s *SearchTests) testCollapseResults(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"modules": [
+			{"id": "acme/vnet/azurerm/1.0.0", "namespace": "acme", "name": "vnet", "provider": "azurerm", "version": "1.0.0", "downloads": 100},
+			{"id": "acme/vnet/azurerm/1.2.0", "namespace": "acme", "name": "vnet", "provider": "azurerm", "version": "1.2.0", "downloads": 40},
+			{"id": "acme/vnet/azurerm/1.2.0", "namespace": "acme", "name": "vnet", "provider": "azurerm", "version": "1.2.0", "downloads": 40},
+			{"id": "other/vnet/azurerm/2.0.0", "namespace": "other", "name": "vnet", "provider": "azurerm", "version": "2.0.0", "downloads": 5}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	raw, err := client.Modules.SearchWithOptions(ctx, "vnet", nil)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if err := AssertEqual(4, len(raw)); err != nil {
+		return err
+	}
+
+	results, err := client.Modules.SearchWithOptions(ctx, "vnet", &registry.ModuleSearchOptions{Collapse: true})
+	if err != nil {
+		return fmt.Errorf("collapsed search failed: %w", err)
+	}
+	if err := AssertEqual(2, len(results)); err != nil {
+		return err
+	}
+
+	var acme *registry.ModuleSearchResult
+	for i := range results {
+		if results[i].Namespace == "acme" {
+			acme = &results[i]
+		}
+	}
+	if acme == nil {
+		return fmt.Errorf("acme/vnet/azurerm missing from collapsed results")
+	}
+
+	// The latest version is canonical; repeated entries are not counted twice
+	if err := AssertEqual("1.2.0", acme.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(int64(140), acme.Downloads); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, acme.Collapsed); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *SearchTests) testPublishedFilters(ctx context.Context) error {
+	// "late" is published on March 31 in UTC-5, which is already April in UTC
+	body := `{"modules": [
+		{"id": "a/old/aws/1.0.0", "namespace": "a", "name": "old", "provider": "aws", "version": "1.0.0", "published_at": "2024-03-15T12:00:00Z"},
+		{"id": "a/late/aws/1.0.0", "namespace": "a", "name": "late", "provider": "aws", "version": "1.0.0", "published_at": "2024-03-31T21:00:00-05:00"},
+		{"id": "a/new/aws/1.0.0", "namespace": "a", "name": "new", "provider": "aws", "version": "1.0.0", "published_at": "2024-04-10T08:00:00+02:00"},
+		{"id": "a/unknown/aws/1.0.0", "namespace": "a", "name": "unknown", "provider": "aws", "version": "1.0.0"}
+	]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	local := time.FixedZone("UTC-5", -5*3600)
+	april := registry.MonthStart(time.Date(2024, 4, 15, 1, 0, 0, 0, local))
+	if err := AssertEqual("2024-04-01T00:00:00Z", april.Format(time.RFC3339)); err != nil {
+		return err
+	}
+
+	list, err := client.Modules.List(ctx, &registry.ModuleListOptions{PublishedAfter: april})
+	if err != nil {
+		return fmt.Errorf("list failed: %w", err)
+	}
+	names := make([]string, 0, len(list.Modules))
+	for _, module := range list.Modules {
+		names = append(names, module.Name)
+	}
+	if err := AssertEqual("late,new", strings.Join(names, ",")); err != nil {
+		return err
+	}
+
+	results, err := client.Modules.SearchWithOptions(ctx, "aws", &registry.ModuleSearchOptions{PublishedBefore: april})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	if err := AssertEqual(1, len(results)); err != nil {
+		return err
+	}
+	if err := AssertEqual("old", results[0].Name); err != nil {
+		return err
+	}
+
+	_, err = client.Modules.List(ctx, &registry.ModuleListOptions{PublishedAfter: april, PublishedBefore: april})
+	if !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for an empty range, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *SearchTests) testFindDuplicates(ctx context.Context) error {
+	list := registry.ModuleList{
+		Modules: []registry.Module{
+			{ID: "acme/vpc/aws/1.0.0", Namespace: "acme", Name: "vpc", Provider: "aws", Downloads: 50,
+				Description: "Terraform module to create AWS VPC resources", Source: "https://github.com/terraform-aws-modules/terraform-aws-vpc"},
+			{ID: "terraform-aws-modules/vpc/aws/5.0.0", Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws", Downloads: 1000000, Verified: true,
+				Description: "Terraform module to create AWS VPC resources", Source: "https://github.com/terraform-aws-modules/terraform-aws-vpc"},
+			{ID: "other/vpc/aws/0.1.0", Namespace: "other", Name: "vpc", Provider: "aws", Downloads: 10,
+				Description: "Terraform module which creates VPC resources on AWS", Source: "https://github.com/other/terraform-aws-vpc.git"},
+			{ID: "solo/vpc/aws/1.0.0", Namespace: "solo", Name: "vpc", Provider: "aws",
+				Description: "Opinionated network baseline with transit gateway attachments", Source: "https://gitlab.com/solo/network-baseline"},
+			{ID: "acme/vpc/google/1.0.0", Namespace: "acme", Name: "vpc", Provider: "google",
+				Description: "Terraform module to create AWS VPC resources", Source: "https://github.com/acme/terraform-google-vpc"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(list)
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	clusters, err := client.Analyze.FindDuplicates(ctx, "vpc")
+	if err != nil {
+		return fmt.Errorf("failed to find duplicates: %w", err)
+	}
+
+	if err := AssertEqual(1, len(clusters)); err != nil {
+		return err
+	}
+
+	cluster := clusters[0]
+	if err := AssertEqual("terraform-aws-modules", cluster.Canonical.Namespace); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, len(cluster.Members)); err != nil {
+		return err
+	}
+	for _, member := range cluster.Members {
+		if member.Namespace == "solo" {
+			return fmt.Errorf("unrelated module %s was clustered as a duplicate", member.ID)
+		}
+	}
+
+	return nil
+}
+
+func (s *SearchTests) testQueryFilter(ctx context.Context) error {
+	modules := []registry.Module{
+		{Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws", Downloads: 5000000, Verified: true, Description: "Creates a VPC"},
+		{Namespace: "acme", Name: "vpc", Provider: "aws", Downloads: 200000, Description: "Fork of the VPC module"},
+		{Namespace: "terraform-google-modules", Name: "network", Provider: "google", Downloads: 900000, Verified: true},
+	}
+
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{`downloads > 100000 AND verified AND provider == "aws"`, []string{"terraform-aws-modules"}},
+		{`verified || namespace == "acme"`, []string{"terraform-aws-modules", "acme", "terraform-google-modules"}},
+		{`NOT verified`, []string{"acme"}},
+		{`description contains "vpc" and (downloads >= 1e6 or namespace != "terraform-aws-modules")`, []string{"terraform-aws-modules", "acme"}},
+		{`verified == false`, []string{"acme"}},
+	}
+
+	for _, tc := range cases {
+		filter, err := query.Compile(tc.expr, query.ModuleFields)
+		if err != nil {
+			return fmt.Errorf("failed to compile %q: %w", tc.expr, err)
+		}
+
+		var got []string
+		for _, m := range filter.Apply(modules) {
+			got = append(got, m.Namespace)
+		}
+		if err := AssertEqual(tc.want, got); err != nil {
+			return fmt.Errorf("%s: %w", tc.expr, err)
+		}
+	}
+
+	invalid := []string{
+		`stars > 5`,
+		`downloads > "many"`,
+		`provider`,
+		`verified > true`,
+		`(verified`,
+		`name == "vpc`,
+		`verified verified`,
+	}
+	for _, expr := range invalid {
+		_, err := query.Compile(expr, query.ModuleFields)
+		var syntaxErr *query.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			return fmt.Errorf("expected syntax error for %q, got %v", expr, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SearchTests) testExpandedSearch(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "vnet":
+			fmt.Fprint(w, `{"modules": [
+				{"id": "acme/network/azurerm/1.0.0", "namespace": "acme", "name": "network", "provider": "azurerm", "version": "1.0.0", "description": "vnet"},
+				{"id": "acme/network/azurerm/1.1.0", "namespace": "acme", "name": "network", "provider": "azurerm", "version": "1.1.0", "description": "vnet"},
+				{"id": "solo/vnet/azurerm/1.0.0", "namespace": "solo", "name": "vnet", "provider": "azurerm", "version": "1.0.0"}
+			]}`)
+		case "virtual network":
+			fmt.Fprint(w, `{"modules": [
+				{"id": "acme/network/azurerm/1.1.0", "namespace": "acme", "name": "network", "provider": "azurerm", "version": "1.1.0", "description": "virtual network"}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	results, err := client.Modules.SearchExpanded(ctx, []registry.WeightedQuery{
+		{Query: "vnet"},
+		{Query: "virtual network", Weight: 3},
+	})
+	if err != nil {
+		return fmt.Errorf("expanded search failed: %w", err)
+	}
+	if err := AssertEqual(2, len(results)); err != nil {
+		return err
+	}
+
+	// acme/network scores 3 (description matches "vnet") + 3 * 3 (description matches
+	// "virtual network") and outranks solo/vnet's exact name match worth 10
+	single, err := client.Modules.SearchWithRelevance(ctx, "vnet", 0)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	scores := make(map[string]float64)
+	for _, r := range single {
+		scores[r.Namespace] = r.Relevance
+	}
+	acme, solo := results[0], results[1]
+	if err := AssertEqual("acme", acme.Namespace); err != nil {
+		return err
+	}
+	if err := AssertEqual("1.1.0", acme.Version); err != nil {
+		return err
+	}
+	if err := AssertEqual(scores["acme"]+3*3.0, acme.Relevance); err != nil {
+		return err
+	}
+	if err := AssertEqual(scores["solo"], solo.Relevance); err != nil {
+		return err
+	}
+
+	// A failing query is reported alongside the merged results of the others
+	results, err = client.Modules.SearchExpanded(ctx, []registry.WeightedQuery{{Query: "vnet"}, {Query: "broken"}})
+	if err == nil || len(results) != 2 {
+		return fmt.Errorf("expected partial results with an error, got %d results and %v", len(results), err)
+	}
+
+	if _, err := client.Modules.SearchExpanded(ctx, []registry.WeightedQuery{{Query: "broken"}}); err == nil {
+		return fmt.Errorf("expected error when every query fails")
+	}
+
+	invalid := [][]registry.WeightedQuery{
+		nil,
+		{{Query: " "}},
+		{{Query: "vnet", Weight: -1}},
+		{{Query: "vnet"}, {Query: "vnet", Weight: 2}},
+	}
+	for _, queries := range invalid {
+		_, err := client.Modules.SearchExpanded(ctx, queries)
+		var validationErr *registry.ValidationError
+		if !errors.As(err, &validationErr) {
+			return fmt.Errorf("expected validation error for %+v, got %v", queries, err)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package tests
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 
 	"github.com/TahirRiaz/terralens-registry-client/registry"
 	"github.com/sirupsen/logrus"
@@ -34,6 +36,7 @@ func (s *SubcategoryTests) setupTests() {
 	s.AddTest("Validate Subcategory Filtering", "Test subcategory filtering accuracy", s.testSubcategoryFiltering)
 	s.AddTest("Test Subcategory Validation", "Test subcategory parameter validation", s.testSubcategoryValidation)
 	s.AddTest("Test Multiple Providers", "Test subcategory filtering across multiple providers", s.testMultipleProviders)
+	s.AddTest("List Subcategories", "Test discovering subcategories with doc counts", s.testListSubcategories)
 }
 
 func (t *SubcategoryTests) testListNetworkingResources(ctx context.Context) error {
@@ -428,3 +431,61 @@ func (t *SubcategoryTests) testMultipleProviders(ctx context.Context) error {
 
 	return nil
 }
+
+func (t *SubcategoryTests) testListSubcategories(ctx context.Context) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("filter[category]") == "resources" && query.Get("page[number]") == "1":
+			fmt.Fprint(w, `{"data": [
+				{"id": "1", "attributes": {"subcategory": "Networking"}},
+				{"id": "2", "attributes": {"subcategory": "Compute"}}
+			], "meta": {"pagination": {"next-page": 2}}}`)
+		case query.Get("filter[category]") == "resources":
+			fmt.Fprint(w, `{"data": [
+				{"id": "3", "attributes": {"subcategory": "Networking"}},
+				{"id": "4", "attributes": {}}
+			], "meta": {"pagination": {}}}`)
+		case query.Get("filter[category]") == "data-sources":
+			fmt.Fprint(w, `{"data": [{"id": "5", "attributes": {"subcategory": "Networking"}}], "meta": {"pagination": {}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(t.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	subcategories, err := client.Providers.ListSubcategories(ctx, "v1")
+	if err != nil {
+		return fmt.Errorf("ListSubcategories failed: %w", err)
+	}
+
+	if err := AssertEqual(3, len(subcategories)); err != nil {
+		return err
+	}
+
+	// Sorted by name, with uncategorized docs first
+	if err := AssertEqual("", subcategories[0].Name); err != nil {
+		return err
+	}
+	networking := subcategories[2]
+	if err := AssertEqual("Networking", networking.Name); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, networking.Resources); err != nil {
+		return err
+	}
+	if err := AssertEqual(3, networking.Total()); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.ListSubcategories(ctx, ""); !registry.IsValidationError(err) {
+		return fmt.Errorf("expected validation error for an empty version ID, got %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/TahirRiaz/terralens-registry-client/registry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SuiteFactory creates a test suite for a client
+type SuiteFactory func(client *registry.Client, logger *logrus.Logger) TestSuite
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]SuiteFactory)
+)
+
+// Register makes a test suite available under name. Downstream packages call it from
+// an init function to add their own suites, for example against private registry
+// extensions. Register panics if name is empty, factory is nil, or name is already registered.
+func Register(name string, factory SuiteFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if name == "" {
+		panic("tests: Register called with an empty suite name")
+	}
+	if factory == nil {
+		panic("tests: Register factory is nil for suite " + name)
+	}
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("tests: Register called twice for suite %q", name))
+	}
+
+	factories[name] = factory
+}
+
+// RegisteredSuites returns the names of all registered suites in sorted order
+func RegisteredSuites() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSuites creates every registered suite for a client, keyed by registered name
+func NewSuites(client *registry.Client, logger *logrus.Logger) map[string]TestSuite {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	suites := make(map[string]TestSuite, len(factories))
+	for name, factory := range factories {
+		suites[name] = factory(client, logger)
+	}
+	return suites
+}
+
+func init() {
+	Register("Modules", NewModuleTests)
+	Register("Providers", NewProviderTests)
+	Register("Policies", NewPolicyTests)
+	Register("Search", NewSearchTests)
+	Register("Validation", NewValidationTests)
+	Register("Error Handling", NewErrorTests)
+	Register("Performance", NewPerformanceTests)
+	Register("Subcategory", NewSubcategoryTests)
+	Register("Pagination", NewPaginationTests)
+}
@@ -3,10 +3,13 @@ package tests
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/TahirRiaz/terralens-registry-client/output"
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registrytest/assert"
 
 	"github.com/sirupsen/logrus"
 )
@@ -31,6 +34,7 @@ type TestResult struct {
 	Passed   bool
 	Error    error
 	Duration time.Duration
+	APICalls int
 }
 
 // TestResults aggregates all test results
@@ -40,27 +44,66 @@ type TestResults struct {
 	Failed   int
 	Skipped  int
 	Duration time.Duration
+	APICalls int
 	Results  []TestResult
 }
 
+// RateBudget controls how the runner paces tests against the client's rate limiter
+type RateBudget struct {
+	// PerTest is the number of tokens that must be available before a test starts
+	PerTest int
+
+	// SuiteReserve is the number of tokens held back for each suite still to run
+	SuiteReserve int
+}
+
+// DefaultRateBudget is the rate budget used by NewTestRunner
+var DefaultRateBudget = RateBudget{
+	PerTest:      10,
+	SuiteReserve: 5,
+}
+
 // TestRunner manages test execution
 type TestRunner struct {
 	client  *registry.Client
 	logger  *logrus.Logger
 	suites  map[string]TestSuite
 	verbose bool
+	budget  RateBudget
+
+	// out renders progress and results, as tables on stdout by default
+	out output.Renderer
+
+	// pendingSuites is the number of suites left to run after the current one
+	pendingSuites int
 }
 
 // NewTestRunner creates a new test runner
 func NewTestRunner(client *registry.Client, logger *logrus.Logger) *TestRunner {
+	// The table format always exists
+	out, _ := output.New(output.FormatTable, os.Stdout)
+
 	return &TestRunner{
 		client:  client,
 		logger:  logger,
 		suites:  make(map[string]TestSuite),
 		verbose: logger.Level == logrus.DebugLevel,
+		budget:  DefaultRateBudget,
+		out:     out,
 	}
 }
 
+// SetRenderer sets how progress and results are printed
+func (r *TestRunner) SetRenderer(out output.Renderer) {
+	r.out = out
+}
+
+// SetRateBudget sets how tests are paced against the client's rate limiter.
+// A zero budget disables pacing.
+func (r *TestRunner) SetRateBudget(budget RateBudget) {
+	r.budget = budget
+}
+
 // AddSuite adds a test suite
 func (r *TestRunner) AddSuite(name string, suite TestSuite) {
 	r.suites[name] = suite
@@ -80,7 +123,9 @@ func (r *TestRunner) RunAll(ctx context.Context) *TestResults {
 
 	startTime := time.Now()
 
+	r.pendingSuites = len(r.suites)
 	for _, suite := range r.suites {
+		r.pendingSuites--
 		suiteResults := r.runSuite(ctx, suite)
 		results.Results = append(results.Results, suiteResults...)
 	}
@@ -90,6 +135,7 @@ func (r *TestRunner) RunAll(ctx context.Context) *TestResults {
 	// Calculate totals
 	for _, result := range results.Results {
 		results.Total++
+		results.APICalls += result.APICalls
 		if result.Passed {
 			results.Passed++
 		} else {
@@ -108,6 +154,7 @@ func (r *TestRunner) RunSuite(ctx context.Context, suiteName string, suite TestS
 
 	startTime := time.Now()
 
+	r.pendingSuites = 0
 	suiteResults := r.runSuite(ctx, suite)
 	results.Results = append(results.Results, suiteResults...)
 
@@ -116,6 +163,7 @@ func (r *TestRunner) RunSuite(ctx context.Context, suiteName string, suite TestS
 	// Calculate totals
 	for _, result := range results.Results {
 		results.Total++
+		results.APICalls += result.APICalls
 		if result.Passed {
 			results.Passed++
 		} else {
@@ -139,6 +187,7 @@ func (r *TestRunner) RunSingleTest(ctx context.Context, suiteName string, test T
 
 	results.Duration = time.Since(startTime)
 	results.Total = 1
+	results.APICalls = result.APICalls
 
 	if result.Passed {
 		results.Passed = 1
@@ -147,16 +196,7 @@ func (r *TestRunner) RunSingleTest(ctx context.Context, suiteName string, test T
 	}
 
 	// Print immediate result
-	status := "✓ PASS"
-	if !result.Passed {
-		status = "✗ FAIL"
-	}
-
-	fmt.Printf("%s: %s/%s (%v)\n", status, suiteName, test.Name, result.Duration)
-
-	if !result.Passed && result.Error != nil {
-		fmt.Printf("  Error: %v\n", result.Error)
-	}
+	r.printResult(suiteName+"/"+test.Name, result)
 
 	return results
 }
@@ -164,8 +204,7 @@ func (r *TestRunner) RunSingleTest(ctx context.Context, suiteName string, test T
 // runSuite runs a single test suite
 func (r *TestRunner) runSuite(ctx context.Context, suite TestSuite) []TestResult {
 	r.logger.Infof("Running test suite: %s", suite.Name())
-	fmt.Printf("\n%s Test Suite\n", suite.Name())
-	fmt.Println(strings.Repeat("-", 50))
+	r.out.Text("\n%s Test Suite\n%s\n", suite.Name(), strings.Repeat("-", 50))
 
 	var results []TestResult
 
@@ -174,19 +213,24 @@ func (r *TestRunner) runSuite(ctx context.Context, suite TestSuite) []TestResult
 		results = append(results, result)
 
 		// Print test result
-		status := "✓ PASS"
-		if !result.Passed {
-			status = "✗ FAIL"
-		}
+		r.printResult(test.Name, result)
+	}
 
-		fmt.Printf("%s: %s (%v)\n", status, test.Name, result.Duration)
+	return results
+}
 
-		if !result.Passed && result.Error != nil {
-			fmt.Printf("  Error: %v\n", result.Error)
-		}
+// printResult prints the outcome of a test as it completes
+func (r *TestRunner) printResult(name string, result TestResult) {
+	status := "✓ PASS"
+	if !result.Passed {
+		status = "✗ FAIL"
 	}
 
-	return results
+	r.out.Text("%s: %s (%v, %d API calls)\n", status, name, result.Duration, result.APICalls)
+
+	if !result.Passed && result.Error != nil {
+		r.out.Text("  Error: %v\n", result.Error)
+	}
 }
 
 // runTest runs a single test
@@ -196,10 +240,23 @@ func (r *TestRunner) runTest(ctx context.Context, suiteName string, test TestCas
 		Test:  test.Name,
 	}
 
+	limiter := r.rateLimiter()
+
+	// Pace the test so it starts with its own budget and leaves a reserve for later suites
+	if err := r.waitForBudget(ctx, limiter); err != nil {
+		result.Error = fmt.Errorf("waiting for rate limit budget: %w", err)
+		return result
+	}
+
 	// Create test context with timeout
 	testCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	var acquired uint64
+	if limiter != nil {
+		acquired = limiter.Acquired()
+	}
+
 	startTime := time.Now()
 
 	// Run the test
@@ -209,6 +266,10 @@ func (r *TestRunner) runTest(ctx context.Context, suiteName string, test TestCas
 	result.Passed = err == nil
 	result.Error = err
 
+	if limiter != nil {
+		result.APICalls = int(limiter.Acquired() - acquired)
+	}
+
 	if r.verbose {
 		if result.Passed {
 			r.logger.Debugf("Test passed: %s/%s", suiteName, test.Name)
@@ -220,39 +281,75 @@ func (r *TestRunner) runTest(ctx context.Context, suiteName string, test TestCas
 	return result
 }
 
-// PrintResults prints test results in a formatted way
-func (r *TestRunner) PrintResults(results *TestResults) {
-	fmt.Println("\n" + strings.Repeat("=", 50))
-	fmt.Println("Test Results Summary")
-	fmt.Println(strings.Repeat("=", 50))
+// rateLimiter returns the client's rate limiter, or nil when the runner has no client
+func (r *TestRunner) rateLimiter() registry.RateLimiter {
+	if r.client == nil {
+		return nil
+	}
+	return r.client.GetRateLimiter()
+}
 
-	fmt.Printf("Total Tests:    %d\n", results.Total)
+// waitForBudget blocks until the rate limiter holds enough tokens for the next test
+// plus the reserve for the suites still to run
+func (r *TestRunner) waitForBudget(ctx context.Context, limiter registry.RateLimiter) error {
+	if limiter == nil {
+		return nil
+	}
 
-	if results.Total > 0 {
-		fmt.Printf("Passed:         %d (%.1f%%)\n", results.Passed, float64(results.Passed)/float64(results.Total)*100)
-		fmt.Printf("Failed:         %d (%.1f%%)\n", results.Failed, float64(results.Failed)/float64(results.Total)*100)
-	} else {
-		fmt.Printf("Passed:         %d\n", results.Passed)
-		fmt.Printf("Failed:         %d\n", results.Failed)
+	needed := r.budget.PerTest + r.budget.SuiteReserve*r.pendingSuites
+	if needed <= 0 {
+		return nil
 	}
 
-	fmt.Printf("Total Duration: %v\n", results.Duration)
+	if remaining := limiter.TokensRemaining(); remaining < min(needed, limiter.Capacity()) {
+		r.logger.Debugf("Waiting for rate limit budget: %d tokens available, %d needed", remaining, needed)
+	}
+
+	return limiter.WaitForTokens(ctx, needed)
+}
 
-	if results.Failed > 0 {
-		fmt.Println("\nFailed Tests:")
-		fmt.Println(strings.Repeat("-", 30))
+// PrintResults prints the summary of the test results followed by a table of every
+// test, in the runner's output format
+func (r *TestRunner) PrintResults(results *TestResults) error {
+	r.out.Text("\n%s\nTest Results Summary\n%s\n", strings.Repeat("=", 50), strings.Repeat("=", 50))
+
+	passRate := 0.0
+	if results.Total > 0 {
+		passRate = float64(results.Passed) / float64(results.Total) * 100
+	}
 
-		for _, result := range results.Results {
-			if !result.Passed {
-				fmt.Printf("  • %s/%s\n", result.Suite, result.Test)
-				if result.Error != nil {
-					fmt.Printf("    Error: %v\n", result.Error)
-				}
-			}
+	err := r.out.Record("summary", []output.Field{
+		{Name: "total_tests", Value: results.Total},
+		{Name: "passed", Value: results.Passed},
+		{Name: "failed", Value: results.Failed},
+		{Name: "pass_rate", Value: passRate},
+		{Name: "total_duration", Value: results.Duration.String()},
+		{Name: "api_calls", Value: results.APICalls},
+	})
+	if err != nil {
+		return err
+	}
+
+	table := output.Table{
+		Name:    "results",
+		Title:   "Test Results",
+		Columns: []string{"suite", "test", "passed", "duration", "api_calls", "error"},
+	}
+	for _, result := range results.Results {
+		message := ""
+		if result.Error != nil {
+			message = result.Error.Error()
 		}
+		table.Rows = append(table.Rows, []any{
+			result.Suite, result.Test, result.Passed, result.Duration.String(), result.APICalls, message,
+		})
+	}
+	if err := r.out.Table(table); err != nil {
+		return err
 	}
 
-	fmt.Println()
+	r.out.Text("\n")
+	return nil
 }
 
 // ListSuites returns a list of all registered test suites
@@ -292,6 +389,16 @@ func (s *BaseTestSuite) Tests() []TestCase {
 	return s.tests
 }
 
+// Client returns the registry client the suite tests against
+func (s *BaseTestSuite) Client() *registry.Client {
+	return s.client
+}
+
+// Logger returns the suite logger
+func (s *BaseTestSuite) Logger() *logrus.Logger {
+	return s.logger
+}
+
 // AddTest adds a test case to the suite
 func (s *BaseTestSuite) AddTest(name, description string, testFunc func(ctx context.Context) error) {
 	s.tests = append(s.tests, TestCase{
@@ -301,74 +408,15 @@ func (s *BaseTestSuite) AddTest(name, description string, testFunc func(ctx cont
 	})
 }
 
-// AssertEqual checks if two values are equal
-func AssertEqual(expected, actual interface{}) error {
-	if expected != actual {
-		return fmt.Errorf("expected %v, got %v", expected, actual)
-	}
-	return nil
-}
-
-// AssertNotNil checks if a value is not nil
-func AssertNotNil(value interface{}) error {
-	if value == nil {
-		return fmt.Errorf("expected non-nil value, got nil")
-	}
-	return nil
-}
-
-// AssertNil checks if a value is nil
-func AssertNil(value interface{}) error {
-	if value != nil {
-		return fmt.Errorf("expected nil, got %v", value)
-	}
-	return nil
-}
-
-// AssertTrue checks if a condition is true
-func AssertTrue(condition bool, message string) error {
-	if !condition {
-		return fmt.Errorf("assertion failed: %s", message)
-	}
-	return nil
-}
-
-// AssertNoError checks if there is no error
-func AssertNoError(err error) error {
-	if err != nil {
-		return fmt.Errorf("expected no error, got: %v", err)
-	}
-	return nil
-}
-
-// AssertError checks if there is an error
-func AssertError(err error) error {
-	if err == nil {
-		return fmt.Errorf("expected error, got nil")
-	}
-	return nil
-}
-
-// AssertContains checks if a string contains a substring
-func AssertContains(haystack, needle string) error {
-	if !strings.Contains(haystack, needle) {
-		return fmt.Errorf("expected '%s' to contain '%s'", haystack, needle)
-	}
-	return nil
-}
-
-// AssertGreaterThan checks if a > b
-func AssertGreaterThan(a, b int) error {
-	if a <= b {
-		return fmt.Errorf("expected %d > %d", a, b)
-	}
-	return nil
-}
-
-// AssertLessThan checks if a < b
-func AssertLessThan(a, b int) error {
-	if a >= b {
-		return fmt.Errorf("expected %d < %d", a, b)
-	}
-	return nil
-}
+// Assertion helpers, promoted to the registrytest/assert package
+var (
+	AssertEqual       = assert.AssertEqual
+	AssertNotNil      = assert.AssertNotNil
+	AssertNil         = assert.AssertNil
+	AssertTrue        = assert.AssertTrue
+	AssertNoError     = assert.AssertNoError
+	AssertError       = assert.AssertError
+	AssertContains    = assert.AssertContains
+	AssertGreaterThan = assert.AssertGreaterThan
+	AssertLessThan    = assert.AssertLessThan
+)
@@ -1,14 +1,49 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	_ "embed"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/TahirRiaz/terralens-registry-client/output"
 	"github.com/TahirRiaz/terralens-registry-client/registry"
+	"github.com/TahirRiaz/terralens-registry-client/registry/registryctx"
+	"github.com/TahirRiaz/terralens-registry-client/registry/semver"
+	"github.com/TahirRiaz/terralens-registry-client/registry/validate"
+	"github.com/TahirRiaz/terralens-registry-client/registrytest/assert"
 
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 )
 
+// apiSnapshot is the committed snapshot of the service interfaces; regenerate it with
+// "go run ./cmd -mode=api > tests/testdata/api.snapshot" after bumping registry.APIVersion
+//
+//go:embed testdata/api.snapshot
+var apiSnapshot string
+
 // ValidationTests contains tests for input validation
 type ValidationTests struct {
 	*BaseTestSuite
@@ -29,10 +64,21 @@ func (s *ValidationTests) setupTests() {
 	s.AddTest("Provider Parameters", "Test provider parameter validation", s.testProviderParameters)
 	s.AddTest("Policy Parameters", "Test policy parameter validation", s.testPolicyParameters)
 	s.AddTest("Version Validation", "Test version string validation", s.testVersionValidation)
+	s.AddTest("Semantic Versions", "Test semantic version precedence, sorting and latest stable selection", s.testSemanticVersions)
 	s.AddTest("Pagination Limits", "Test pagination parameter limits", s.testPaginationLimits)
 	s.AddTest("Module ID Format", "Test module ID parsing", s.testModuleIDFormat)
 	s.AddTest("Policy ID Format", "Test policy ID parsing", s.testPolicyIDFormat)
 	s.AddTest("Provider URI Format", "Test provider URI parsing", s.testProviderURIFormat)
+	s.AddTest("Assertion Matchers", "Test registry-aware assertion matchers", s.testAssertionMatchers)
+	s.AddTest("Suite Registry", "Test registering test suites by name", s.testSuiteRegistry)
+	s.AddTest("Validate Rules", "Test the shared naming and version rules", s.testValidateRules)
+	s.AddTest("Output Renderers", "Test rendering CLI results as table, JSON, YAML and CSV", s.testOutputRenderers)
+	s.AddTest("Client Capabilities", "Test detecting and caching registry capabilities", s.testClientCapabilities)
+	s.AddTest("Client Certificates", "Test mutual TLS, per-host TLS settings and explicit proxies", s.testClientCertificates)
+	s.AddTest("Example Validation", "Test parsing extracted Terraform examples with HCL", s.testExampleValidation)
+	s.AddTest("API Compatibility", "Test the service interface snapshot and deprecation warnings", s.testAPICompatibility)
+	s.AddTest("Client From Env", "Test client configuration from environment variables", s.testClientFromEnv)
+	s.AddTest("Context Keys", "Test typed context keys and the tenant and purpose knobs", s.testContextKeys)
 }
 
 func (s *ValidationTests) testModuleParameters(ctx context.Context) error {
@@ -179,6 +225,83 @@ func (s *ValidationTests) testVersionValidation(ctx context.Context) error {
 	return nil
 }
 
+func (s *ValidationTests) testSemanticVersions(ctx context.Context) error {
+	// The precedence example of the Semantic Versioning 2.0.0 specification
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "v1.0.1", "1.10.0", "2.0.0",
+	}
+	for i := 1; i < len(ordered); i++ {
+		if cmp := registry.CompareVersions(ordered[i-1], ordered[i]); cmp != -1 {
+			return fmt.Errorf("expected %s < %s, got %d", ordered[i-1], ordered[i], cmp)
+		}
+		if cmp := semver.Compare(ordered[i], ordered[i-1]); cmp != 1 {
+			return fmt.Errorf("expected %s > %s, got %d", ordered[i], ordered[i-1], cmp)
+		}
+	}
+
+	// Build metadata does not affect precedence
+	if cmp := registry.CompareVersions("1.2.3+build.1", "v1.2.3+build.2"); cmp != 0 {
+		return fmt.Errorf("expected builds of one release to be equal, got %d", cmp)
+	}
+
+	v, err := semver.Parse("v1.2.3-rc.1+linux.amd64")
+	if err != nil {
+		return fmt.Errorf("failed to parse version: %w", err)
+	}
+	if err := AssertEqual(semver.Version{Major: 1, Minor: 2, Patch: 3, Prerelease: []string{"rc", "1"}, Build: "linux.amd64"}.String(), v.String()); err != nil {
+		return err
+	}
+	if err := AssertEqual("v1.2.3-rc.1+linux.amd64", v.Original()); err != nil {
+		return err
+	}
+	if err := AssertTrue(v.IsPrerelease(), "rc.1 should be a pre-release"); err != nil {
+		return err
+	}
+
+	for _, invalid := range []string{"1.2", "1.0.0-", "1.0.0-alpha..1", "1.0.0+", "1.0.0-beta_1", "99999999999999999999.0.0"} {
+		var parseErr *semver.ParseError
+		if _, err := semver.Parse(invalid); !errors.As(err, &parseErr) {
+			return fmt.Errorf("expected %q to fail parsing, got %v", invalid, err)
+		}
+		if validate.Version(invalid) {
+			return fmt.Errorf("expected %q to fail version validation", invalid)
+		}
+	}
+
+	// Sorting keeps unparseable versions, ordered before valid ones
+	versions := []string{"1.0.0", "1.0.0-beta.11", "not-a-version", "0.9.0", "1.0.0-beta.2", "1.1.0-rc.1"}
+	semver.SortVersions(versions)
+	if err := AssertEqual([]string{"not-a-version", "0.9.0", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0", "1.1.0-rc.1"}, versions); err != nil {
+		return fmt.Errorf("sorted versions: %w", err)
+	}
+	semver.SortVersionsDescending(versions)
+	if err := AssertEqual("1.1.0-rc.1", versions[0]); err != nil {
+		return err
+	}
+
+	if err := AssertEqual("1.0.0", semver.LatestStable(versions)); err != nil {
+		return fmt.Errorf("latest stable: %w", err)
+	}
+	if err := AssertEqual("1.1.0-rc.1", semver.Latest(versions)); err != nil {
+		return fmt.Errorf("latest: %w", err)
+	}
+	if err := AssertEqual("", semver.LatestStable([]string{"2.0.0-beta.1", "bogus"})); err != nil {
+		return fmt.Errorf("latest stable of pre-releases: %w", err)
+	}
+
+	// Constraints exclude pre-releases unless named exactly
+	constraint, err := registry.ParseVersionConstraint("~> 1.0")
+	if err != nil {
+		return fmt.Errorf("failed to parse constraint: %w", err)
+	}
+	if constraint.Check("1.1.0-rc.1") || !constraint.Check("1.9.0") || constraint.Check("2.0.0") {
+		return fmt.Errorf("constraint %s matched the wrong versions", constraint)
+	}
+
+	return nil
+}
+
 func (s *ValidationTests) testPaginationLimits(ctx context.Context) error {
 	// Test negative offset
 	opts := &registry.ModuleListOptions{
@@ -398,3 +521,919 @@ func (s *ValidationTests) testProviderURIFormat(ctx context.Context) error {
 
 	return nil
 }
+
+func (s *ValidationTests) testSuiteRegistry(ctx context.Context) error {
+	suites := NewSuites(s.client, s.logger)
+	for _, name := range RegisteredSuites() {
+		if suites[name] == nil {
+			return fmt.Errorf("registered suite %q was not created", name)
+		}
+	}
+
+	if _, ok := suites["Validation"]; !ok {
+		return fmt.Errorf("built-in suite %q is not registered", "Validation")
+	}
+
+	// Registering a taken name panics so two packages cannot silently shadow each other
+	panicked := func() (recovered bool) {
+		defer func() { recovered = recover() != nil }()
+		Register("Validation", NewValidationTests)
+		return false
+	}()
+	if !panicked {
+		return fmt.Errorf("expected Register to panic for a duplicate suite name")
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testClientCapabilities(ctx context.Context) error {
+	var requests atomic.Int32
+	var failPolicies atomic.Bool
+	failPolicies.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/.well-known/terraform.json":
+			_, _ = w.Write([]byte(`{"modules.v1": "/v1/modules/", "tfe.v2": "/api/v2/", "login.v1": {"client": "terraform-cli"}}`))
+		case "/v1/modules/search":
+			_, _ = w.Write([]byte(`{"modules": []}`))
+		case "/v1/modules":
+			_, _ = w.Write([]byte(`{"modules": [{"namespace": "acme", "name": "network", "provider": "aws"}]}`))
+		case "/v2/policies":
+			if failPolicies.Load() {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			// Provider docs and download statistics are not available
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// A plain HTTP client does not retry the failing probe
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), registry.WithHTTPClient(&http.Client{}))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Probe failures are reported with the partial result, which is not cached
+	caps, err := client.Capabilities(ctx)
+	var apiErr *registry.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		return fmt.Errorf("expected the policies probe failure, got: %v", err)
+	}
+	if caps == nil || !caps.Search {
+		return fmt.Errorf("expected partial capabilities with search, got: %+v", caps)
+	}
+
+	failPolicies.Store(false)
+	caps, err = client.Capabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect capabilities: %w", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want bool
+	}{
+		{"v2 docs", caps.V2Docs, false},
+		{"policies", caps.Policies, false},
+		{"search", caps.Search, true},
+		{"downloads summary", caps.DownloadsSummary, false},
+		{"publishing", caps.Publishing, true},
+		{"modules.v1 service", caps.Supports("modules.v1"), true},
+		{"login.v1 service", caps.Supports("login.v1"), true},
+		{"providers.v1 service", caps.Supports("providers.v1"), false},
+	}
+	for _, check := range checks {
+		if check.got != check.want {
+			return fmt.Errorf("capability %s: expected %v, got %v", check.name, check.want, check.got)
+		}
+	}
+	if err := AssertEqual(server.URL+"/v1/modules/", caps.Services["modules.v1"]); err != nil {
+		return err
+	}
+
+	// Detected capabilities are cached and copied
+	caps.Services["providers.v1"] = "/v1/providers/"
+	before := requests.Load()
+	cached, err := client.Capabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read cached capabilities: %w", err)
+	}
+	if requests.Load() != before {
+		return fmt.Errorf("expected cached capabilities, got %d new requests", requests.Load()-before)
+	}
+	if cached.Supports("providers.v1") {
+		return fmt.Errorf("modifying returned capabilities changed the cache")
+	}
+
+	client.ClearCapabilities()
+	if _, err := client.Capabilities(ctx); err != nil {
+		return fmt.Errorf("failed to detect capabilities again: %w", err)
+	}
+	if requests.Load() == before {
+		return fmt.Errorf("expected capabilities to be detected again after clearing")
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testExampleValidation(ctx context.Context) error {
+	content := "## Example Usage\n\n" +
+		"```terraform\nterraform {\n  required_providers {\n    aws = {\n      source  = \"hashicorp/aws\"\n      version = \"~> 5.0\"\n    }\n    random = \"~> 3.1\"\n  }\n}\n\n" +
+		"data \"aws_ami\" \"ubuntu\" {\n  most_recent = true\n}\n\n" +
+		"resource \"aws_instance\" \"web\" {\n  ami = data.aws_ami.ubuntu.id\n}\n\n" +
+		"resource \"random_pet\" \"name\" {}\n\n" +
+		"resource \"widget_thing\" \"x\" {\n  provider = acme.west\n}\n```\n\n" +
+		"```hcl\nmodule \"vpc\" {\n  source = \"terraform-aws-modules/vpc/aws\"\n  ...\n}\n```\n\n" +
+		"```shell\nterraform apply\n```\n"
+
+	// Without options the snippets match ExtractTerraformExamples
+	plain := registry.ExtractTerraformExamplesWithOptions(content, nil)
+	if err := AssertEqual(2, len(plain)); err != nil {
+		return err
+	}
+	if err := AssertEqual(registry.ExtractTerraformExamples(content)[1], plain[1].Code); err != nil {
+		return err
+	}
+	if plain[0].Validated {
+		return fmt.Errorf("examples should not be validated without options")
+	}
+
+	examples := registry.ExtractTerraformExamplesWithOptions(content, &registry.ExampleOptions{Validate: true})
+	if err := AssertEqual(2, len(examples)); err != nil {
+		return err
+	}
+
+	valid := examples[0]
+	if !valid.Valid || len(valid.Diagnostics) != 0 {
+		return fmt.Errorf("expected the first example to parse, got: %v", valid.Diagnostics)
+	}
+	if err := AssertEqual([]string{"aws_instance.web", "random_pet.name", "widget_thing.x"}, valid.Resources); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"aws_ami.ubuntu"}, valid.DataSources); err != nil {
+		return err
+	}
+	wantProviders := []registry.ExampleProvider{
+		{Name: "acme", Source: "hashicorp/acme"},
+		{Name: "aws", Source: "hashicorp/aws", Version: "~> 5.0", Declared: true},
+		{Name: "random", Source: "hashicorp/random", Version: "~> 3.1", Declared: true},
+	}
+	if err := AssertEqual(wantProviders, valid.Providers); err != nil {
+		return err
+	}
+
+	// Placeholder ellipses do not parse, but the module block is still found
+	invalid := examples[1]
+	if invalid.Valid || len(invalid.Diagnostics) == 0 {
+		return fmt.Errorf("expected the module example to fail to parse")
+	}
+	if err := AssertEqual([]string{"vpc"}, invalid.Modules); err != nil {
+		return err
+	}
+
+	usable := registry.ExtractTerraformExamplesWithOptions(content, &registry.ExampleOptions{ValidOnly: true})
+	if err := AssertEqual(1, len(usable)); err != nil {
+		return err
+	}
+	if err := AssertEqual(valid.Code, usable[0].Code); err != nil {
+		return err
+	}
+
+	// Syntax errors carry their position in the snippet
+	if err := AssertEqual(3, invalid.Errors[0].Line); err != nil {
+		return err
+	}
+	if err := AssertEqual(invalid.Errors, registry.CheckHCL(invalid.Code)); err != nil {
+		return err
+	}
+	if err := AssertEqual(0, len(registry.CheckHCL(valid.Code))); err != nil {
+		return err
+	}
+
+	// An exec hook such as TerraformValidator checks the examples that parse
+	var checked []string
+	validator := func(ctx context.Context, code string) ([]registry.HCLDiagnostic, error) {
+		checked = append(checked, code)
+		return []registry.HCLDiagnostic{{Summary: "Missing required argument", Line: 14, Column: 1}}, nil
+	}
+	for i := range examples {
+		if err := examples[i].ValidateWith(ctx, validator); err != nil {
+			return err
+		}
+	}
+	if err := AssertEqual([]string{valid.Code}, checked); err != nil {
+		return err
+	}
+	if examples[0].Valid {
+		return fmt.Errorf("expected the validator's errors to invalidate the example")
+	}
+	if err := AssertEqual("14:1: Missing required argument", examples[0].Diagnostics[0]); err != nil {
+		return err
+	}
+
+	// Generated tfvars files parse
+	module := &registry.ModuleDetails{Root: registry.ModulePart{Inputs: []registry.ModuleInput{
+		{Name: "name", Type: "string", Required: true},
+		{Name: "tags", Type: "any", Default: json.RawMessage(`{"env": "dev \"quoted\"", "ports": [80, 443]}`)},
+	}}}
+	tfvars, err := registry.GenerateTfvars(module, nil, true)
+	if err != nil {
+		return fmt.Errorf("failed to generate tfvars: %w", err)
+	}
+	if errors := registry.CheckHCL(tfvars.HCL()); len(errors) != 0 {
+		return fmt.Errorf("generated tfvars do not parse: %v", errors)
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testAPICompatibility(ctx context.Context) error {
+	current := registry.InterfaceSnapshot()
+	if current != apiSnapshot {
+		return apiSnapshotError(apiSnapshot, current)
+	}
+
+	// Added methods need a minor bump, removed ones a major bump
+	committed := "api " + registry.APIVersion + "\n\nModulesServiceInterface\n\tGet(context.Context, string) (*registry.ModuleDetails, error)\n"
+	added := committed + "\tGetAll(context.Context) ([]registry.ModuleDetails, error)\n"
+	if err := apiSnapshotError(committed, added); err == nil || !strings.Contains(err.Error(), "minor version bump") {
+		return fmt.Errorf("expected an added method to require a minor bump, got: %v", err)
+	}
+	if err := apiSnapshotError(added, committed); err == nil || !strings.Contains(err.Error(), "major version bump") {
+		return fmt.Errorf("expected a removed method to require a major bump, got: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"modules": [{"id": "example/net/aws/1.0.0", "namespace": "example", "name": "net", "provider": "aws", "version": "1.0.0"}]}`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+
+	client, err := registry.NewClient(registry.WithBaseURL(server.URL), registry.WithLogger(logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Deprecated methods keep working and warn once per client
+	for i := 0; i < 2; i++ {
+		results, err := client.Modules.SearchWithRelevance(ctx, "net", 0)
+		if err != nil {
+			return fmt.Errorf("deprecated search failed: %w", err)
+		}
+		if err := AssertEqual(1, len(results)); err != nil {
+			return err
+		}
+	}
+	if _, err := client.Modules.SearchWithOptions(ctx, "net", nil); err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	var warnings []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err == nil && entry["level"] == "warning" {
+			warnings = append(warnings, entry)
+		}
+	}
+	if err := AssertEqual(1, len(warnings)); err != nil {
+		return fmt.Errorf("deprecation warnings: %w", err)
+	}
+	if err := AssertEqual("Modules.SearchWithOptions", warnings[0]["replacement"]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// apiSnapshotError describes how the service interfaces differ from the committed
+// snapshot; removed or changed methods need a major APIVersion bump and added methods
+// a minor one
+func apiSnapshotError(committed, current string) error {
+	committedLines := strings.Split(committed, "\n")
+	committedVersion := strings.TrimPrefix(committedLines[0], "api ")
+
+	inCurrent := make(map[string]bool)
+	for _, line := range strings.Split(current, "\n") {
+		inCurrent[line] = true
+	}
+	inCommitted := make(map[string]bool)
+	for _, line := range committedLines {
+		inCommitted[line] = true
+	}
+
+	var added, removed []string
+	for line := range inCurrent {
+		if strings.HasPrefix(line, "\t") && !inCommitted[line] {
+			added = append(added, strings.TrimSpace(line))
+		}
+	}
+	for line := range inCommitted {
+		if strings.HasPrefix(line, "\t") && !inCurrent[line] {
+			removed = append(removed, strings.TrimSpace(line))
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	committedMajor, committedMinor := apiMajorMinor(committedVersion)
+	currentMajor, currentMinor := apiMajorMinor(registry.APIVersion)
+
+	if len(removed) > 0 && committedMajor == currentMajor {
+		return fmt.Errorf("methods were removed or changed without a major version bump from %s (added: %v, removed: %v); "+
+			"deprecate them instead, or bump registry.APIVersion and regenerate the snapshot with: go run ./cmd -mode=api > tests/testdata/api.snapshot",
+			committedVersion, added, removed)
+	}
+	if len(added) > 0 && committedMajor == currentMajor && currentMinor <= committedMinor {
+		return fmt.Errorf("methods were added without a minor version bump from %s (added: %v); "+
+			"bump registry.APIVersion and regenerate the snapshot with: go run ./cmd -mode=api > tests/testdata/api.snapshot",
+			committedVersion, added)
+	}
+
+	return fmt.Errorf("service interfaces differ from the API %s snapshot (added: %v, removed: %v); "+
+		"regenerate it with: go run ./cmd -mode=api > tests/testdata/api.snapshot", committedVersion, added, removed)
+}
+
+// apiMajorMinor returns the major and minor numbers of an API version
+func apiMajorMinor(version string) (major, minor int) {
+	fmt.Sscanf(version, "%d.%d", &major, &minor)
+	return major, minor
+}
+
+func (s *ValidationTests) testClientFromEnv(ctx context.Context) error {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"version": "1.0.0"}`))
+	}))
+	defer server.Close()
+
+	env := map[string]string{
+		registry.EnvRegistryAddr:           server.URL,
+		registry.TokenEnvName("127.0.0.1"): "secret",
+		registry.EnvRegistryRateLimit:      "7",
+		registry.EnvRegistryRatePeriod:     "1h",
+		registry.EnvRegistryClientTimeout:  "10",
+	}
+	for name, value := range env {
+		previous, had := os.LookupEnv(name)
+		os.Setenv(name, value)
+		defer func(name, previous string, had bool) {
+			if had {
+				os.Setenv(name, previous)
+			} else {
+				os.Unsetenv(name)
+			}
+		}(name, previous, had)
+	}
+
+	if err := AssertEqual("TF_TOKEN_app_terraform_io", registry.TokenEnvName("app.terraform.io")); err != nil {
+		return err
+	}
+	if err := AssertEqual("TF_TOKEN_my__registry_example_com", registry.TokenEnvName("my-registry.example.com")); err != nil {
+		return err
+	}
+
+	client, err := registry.NewClientFromEnv(registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client from env: %w", err)
+	}
+
+	if err := AssertEqual(server.URL, client.GetBaseURL()); err != nil {
+		return err
+	}
+	if err := AssertEqual(7, client.GetRateLimiter().TokensRemaining()); err != nil {
+		return err
+	}
+
+	if _, err := client.Providers.Get(ctx, "hashicorp", "random"); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	if err := AssertEqual("Bearer secret", authorization); err != nil {
+		return err
+	}
+
+	// Explicit options win over the environment
+	client, err = registry.NewClientFromEnv(registry.WithRateLimit(3, time.Minute), registry.WithLogger(s.logger))
+	if err != nil {
+		return fmt.Errorf("failed to create client with overrides: %w", err)
+	}
+	if err := AssertEqual(3, client.GetRateLimiter().TokensRemaining()); err != nil {
+		return err
+	}
+
+	os.Setenv(registry.EnvRegistryRateLimit, "many")
+	if _, err := registry.NewClientFromEnv(); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected invalid configuration error, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testContextKeys(ctx context.Context) error {
+	// The registry package declares its knobs as registryctx keys
+	keys := registryctx.Keys()
+	for _, name := range []string{"priority", "raw-capture", "bypass-cache", "doc-pins", "tenant", "purpose"} {
+		if !slices.Contains(keys, name) {
+			return fmt.Errorf("expected key %q in %v", name, keys)
+		}
+	}
+
+	// Values set under a same-named key of another package do not collide
+	ctx = context.WithValue(ctx, "priority", "foreign")
+	ctx = registry.WithPriority(ctx, registry.PriorityPrefetch)
+	if err := AssertEqual(registry.PriorityPrefetch, registry.PriorityFromContext(ctx)); err != nil {
+		return err
+	}
+	if err := AssertEqual("foreign", ctx.Value("priority")); err != nil {
+		return err
+	}
+
+	if err := AssertEqual("", registryctx.Tenant(ctx)); err != nil {
+		return err
+	}
+	ctx = registryctx.WithTenant(ctx, "team-a")
+	ctx = registryctx.WithPurpose(ctx, "catalog-sync")
+	nested := registryctx.WithPurpose(ctx, "prefetch", "catalog-sync")
+	if err := AssertEqual([]string{"catalog-sync"}, registryctx.Purpose(ctx)); err != nil {
+		return err
+	}
+	if err := AssertEqual([]string{"catalog-sync", "prefetch"}, registryctx.Purpose(nested)); err != nil {
+		return err
+	}
+	if err := AssertTrue(registryctx.HasPurpose(nested, "prefetch"), "expected prefetch purpose"); err != nil {
+		return err
+	}
+
+	// Declaring a key twice is a programming error
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		registryctx.NewKey[string]("tenant")
+		return false
+	}()
+	if err := AssertTrue(panicked, "expected duplicate key to panic"); err != nil {
+		return err
+	}
+
+	// The client passes the knobs through to middleware
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": "1.0.0"}`))
+	}))
+	defer server.Close()
+
+	var tenant string
+	var purpose []string
+	client, err := registry.NewClient(
+		registry.WithBaseURL(server.URL),
+		registry.WithLogger(s.logger),
+		registry.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				tenant = registryctx.Tenant(r.Context())
+				purpose = registryctx.Purpose(r.Context())
+				return next.RoundTrip(r)
+			})
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if _, err := client.Providers.Get(nested, "hashicorp", "random"); err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	if err := AssertEqual("team-a", tenant); err != nil {
+		return err
+	}
+	return AssertEqual([]string{"catalog-sync", "prefetch"}, purpose)
+}
+
+func (s *ValidationTests) testAssertionMatchers(ctx context.Context) error {
+	if err := assert.AssertSemverSorted([]string{"1.2.0", "1.10.0", "2.0.0"}, false); err != nil {
+		return err
+	}
+	if assert.AssertSemverSorted([]string{"1.10.0", "1.2.0"}, false) == nil {
+		return fmt.Errorf("expected unsorted versions to fail")
+	}
+	if err := assert.AssertSemverSorted([]string{"2.0.0", "1.10.0", "1.2.0"}, true); err != nil {
+		return err
+	}
+
+	consistent := registry.Pagination{PageSize: 10, CurrentPage: 2, PrevPage: 1, NextPage: 3, TotalPages: 3, TotalCount: 25}
+	if err := assert.AssertPaginationConsistent(consistent, 10); err != nil {
+		return err
+	}
+	broken := registry.Pagination{PageSize: 10, CurrentPage: 3, NextPage: 4, TotalPages: 3, TotalCount: 25}
+	if assert.AssertPaginationConsistent(broken, 5) == nil {
+		return fmt.Errorf("expected next-page on the last page to fail")
+	}
+
+	expected := registry.ModuleRef{Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws", Version: "5.0.0"}
+	if err := assert.AssertModuleRefEqual(expected, registry.ModuleRef{Namespace: "Terraform-AWS-Modules", Name: "vpc", Provider: "aws", Version: "v5.0.0"}); err != nil {
+		return err
+	}
+	err := assert.AssertModuleRefEqual(expected, registry.ModuleRef{Namespace: "terraform-aws-modules", Name: "vpc", Provider: "aws", Version: "5.1.0"})
+	if err == nil || !strings.Contains(err.Error(), "Version") {
+		return fmt.Errorf("expected version mismatch, got %v", err)
+	}
+
+	err = assert.AssertEqual([]string{"a", "b"}, []string{"a", "c"})
+	if err == nil || !strings.Contains(err.Error(), `+   "c"`) {
+		return fmt.Errorf("expected slice diff, got %v", err)
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testOutputRenderers(ctx context.Context) error {
+	table := output.Table{
+		Name:    "modules",
+		Title:   "Modules",
+		Columns: []string{"module", "downloads", "verified"},
+		Rows: [][]any{
+			{"terraform-aws-modules/vpc/aws", 1200, true},
+			{"example/network, core/azurerm", 5, false},
+		},
+		Footer: "... and 3 more",
+	}
+	fields := []output.Field{
+		{Name: "module", Value: "terraform-aws-modules/vpc/aws"},
+		{Name: "api_calls", Value: 2},
+	}
+
+	render := func(format string) (string, error) {
+		var buf bytes.Buffer
+		out, err := output.New(format, &buf)
+		if err != nil {
+			return "", err
+		}
+		out.Text("narration\n")
+		if err := out.Table(table); err != nil {
+			return "", err
+		}
+		if err := out.Record("summary", fields); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	// Table output keeps the narration and shows headers and labels for people
+	text, err := render(output.FormatTable)
+	if err != nil {
+		return err
+	}
+	for _, want := range []string{"narration", "MODULE", "DOWNLOADS", "Yes", "... and 3 more", "API calls:"} {
+		if !strings.Contains(text, want) {
+			return fmt.Errorf("table output is missing %q:\n%s", want, text)
+		}
+	}
+
+	// JSON output is one object per table or record, without narration
+	text, err = render(output.FormatJSON)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if err := AssertEqual(2, len(lines)); err != nil {
+		return fmt.Errorf("JSON documents: %w", err)
+	}
+	var modules struct {
+		Name string           `json:"name"`
+		Rows []map[string]any `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &modules); err != nil {
+		return fmt.Errorf("invalid JSON table: %w", err)
+	}
+	if err := AssertEqual("modules", modules.Name); err != nil {
+		return err
+	}
+	if err := AssertEqual(2, len(modules.Rows)); err != nil {
+		return err
+	}
+	if err := AssertEqual(float64(1200), modules.Rows[0]["downloads"]); err != nil {
+		return fmt.Errorf("JSON keeps numbers: %w", err)
+	}
+	var summary struct {
+		Name   string         `json:"name"`
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		return fmt.Errorf("invalid JSON record: %w", err)
+	}
+	if err := AssertEqual(float64(2), summary.Fields["api_calls"]); err != nil {
+		return err
+	}
+
+	// YAML output is a stream of documents with the same shape
+	text, err = render(output.FormatYAML)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(strings.NewReader(text))
+	var documents []map[string]any
+	for {
+		var document map[string]any
+		if err := decoder.Decode(&document); err != nil {
+			break
+		}
+		documents = append(documents, document)
+	}
+	if err := AssertEqual(2, len(documents)); err != nil {
+		return fmt.Errorf("YAML documents: %w", err)
+	}
+	if err := AssertEqual("summary", documents[1]["name"]); err != nil {
+		return err
+	}
+
+	// CSV output quotes values and separates tables with a blank line
+	text, err = render(output.FormatCSV)
+	if err != nil {
+		return err
+	}
+	want := "module,downloads,verified\n" +
+		"terraform-aws-modules/vpc/aws,1200,true\n" +
+		"\"example/network, core/azurerm\",5,false\n" +
+		"\n" +
+		"field,value\n" +
+		"module,terraform-aws-modules/vpc/aws\n" +
+		"api_calls,2\n"
+	if err := AssertEqual(want, text); err != nil {
+		return fmt.Errorf("CSV output: %w", err)
+	}
+
+	// Unknown formats are rejected
+	if _, err := output.New("xml", &bytes.Buffer{}); err == nil {
+		return fmt.Errorf("expected an error for an unknown output format")
+	}
+
+	return nil
+}
+
+func (s *ValidationTests) testValidateRules(ctx context.Context) error {
+	cases := []struct {
+		rule  string
+		check func(string) bool
+		value string
+		want  bool
+	}{
+		{"namespace", validate.Namespace, "hashicorp", true},
+		{"namespace", validate.Namespace, "Azure_2", true},
+		{"namespace", validate.Namespace, "-leading", false},
+		{"namespace", validate.Namespace, "has space", false},
+		{"namespace", validate.Namespace, "", false},
+		{"module name", validate.ModuleName, "terraform-aws-vpc", true},
+		{"module name", validate.ModuleName, "vpc/extra", false},
+		{"policy name", validate.PolicyName, "cis_policy-set", true},
+		{"policy name", validate.PolicyName, "_leading", false},
+		{"provider name", validate.ProviderName, "aws", true},
+		{"provider name", validate.ProviderName, "google-beta", true},
+		{"provider name", validate.ProviderName, "k8s", true},
+		{"provider name", validate.ProviderName, "AWS", false},
+		{"provider name", validate.ProviderName, "1password", false},
+		{"version", validate.Version, "1.2.3", true},
+		{"version", validate.Version, "v1.2.3-beta.1", true},
+		{"version", validate.Version, "1.2.3+build.5", true},
+		{"version", validate.Version, "1.2", false},
+		{"version", validate.Version, "1.2.3.4", false},
+		{"version", validate.Version, "latest", false},
+		{"tier", validate.Tier, "partner", true},
+		{"tier", validate.Tier, "Partner", false},
+		{"doc category", validate.DocCategory, "data-sources", true},
+		{"doc category", validate.DocCategory, "datasources", false},
+		{"language", validate.Language, "hcl", true},
+		{"enforcement level", validate.EnforcementLevel, "soft-mandatory", true},
+		{"enforcement level", validate.EnforcementLevel, "mandatory", false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.check(tc.value); got != tc.want {
+			return fmt.Errorf("%s %q: expected %v, got %v", tc.rule, tc.value, tc.want, got)
+		}
+	}
+
+	// Modules, providers, policies and the ID parsers apply the same rules
+	if err := registry.ValidateProviderVersion("1.2.3+build.5"); err != nil {
+		return fmt.Errorf("provider version with build metadata: %w", err)
+	}
+	if _, _, _, err := registry.ExtractProviderInfo("hashicorp/k8s"); err != nil {
+		return fmt.Errorf("provider URI with a digit: %w", err)
+	}
+	if _, _, _, _, err := registry.ParseModuleID("-bad/vpc/aws/1.0.0"); err == nil {
+		return fmt.Errorf("expected module ID with invalid namespace to fail")
+	}
+
+	client, err := registry.NewClient(registry.WithBaseURL("http://127.0.0.1:1"), registry.WithLogger(s.logger))
+	if err != nil {
+		return err
+	}
+	checks := []struct {
+		name  string
+		err   error
+		field string
+	}{
+		{"module namespace", ignoreValue(client.Modules.Get(ctx, "-bad", "vpc", "aws", "1.0.0")), "namespace"},
+		{"module version", ignoreValue(client.Modules.Get(ctx, "hashicorp", "vpc", "aws", "1.0")), "version"},
+		{"provider namespace", ignoreValue(client.Providers.Get(ctx, "-bad", "aws")), "namespace"},
+		{"policy version", ignoreValue(client.Policies.Get(ctx, "hashicorp", "cis", "1.0")), "version"},
+	}
+	for _, check := range checks {
+		var validationErr *registry.ValidationError
+		if !errors.As(check.err, &validationErr) {
+			return fmt.Errorf("%s: expected a validation error, got %v", check.name, check.err)
+		}
+		if err := AssertEqual(check.field, validationErr.Field); err != nil {
+			return fmt.Errorf("%s: %w", check.name, err)
+		}
+	}
+
+	return nil
+}
+
+// ignoreValue returns the error of a call, dropping its value
+func ignoreValue[T any](_ T, err error) error {
+	return err
+}
+
+func (s *ValidationTests) testClientCertificates(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "terralense-mtls")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	ca, caKey, err := newTestCertificate(nil, nil, "Test CA")
+	if err != nil {
+		return err
+	}
+	serverCert, serverKey, err := newTestCertificate(ca, caKey, "127.0.0.1")
+	if err != nil {
+		return err
+	}
+	clientCert, clientKey, err := newTestCertificate(ca, caKey, "terralense")
+	if err != nil {
+		return err
+	}
+
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := writeTestPEM(caFile, ca, nil); err != nil {
+		return err
+	}
+	if err := writeTestPEM(certFile, clientCert, nil); err != nil {
+		return err
+	}
+	if err := writeTestPEM(keyFile, nil, clientKey); err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": %q}}}`,
+			r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	noRetries := func(c *registry.ClientConfig) { c.MaxRetries = 0 }
+	get := func(opts ...registry.ClientOption) (string, error) {
+		opts = append([]registry.ClientOption{registry.WithBaseURL(server.URL), registry.WithLogger(s.logger), noRetries}, opts...)
+		client, err := registry.NewClient(opts...)
+		if err != nil {
+			return "", err
+		}
+		provider, err := client.Providers.Get(ctx, "example", "widget")
+		if err != nil {
+			return "", err
+		}
+		return provider.Attributes.Name, nil
+	}
+
+	name, err := get(registry.WithClientCertificates(certFile, keyFile, caFile))
+	if err != nil {
+		return fmt.Errorf("mTLS request failed: %w", err)
+	}
+	if err := AssertEqual("terralense", name); err != nil {
+		return fmt.Errorf("presented certificate: %w", err)
+	}
+
+	// Without a client certificate the handshake is refused
+	if _, err := get(registry.WithClientCertificates("", "", caFile)); err == nil {
+		return fmt.Errorf("expected the request without a client certificate to fail")
+	}
+
+	// Per-host settings apply to their host only
+	hostConfig, err := registry.LoadTLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS config: %w", err)
+	}
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		return err
+	}
+	if _, err := get(registry.WithHostTLSConfig(u.Hostname(), hostConfig)); err != nil {
+		return fmt.Errorf("per-host mTLS request failed: %w", err)
+	}
+	if _, err := get(registry.WithHostTLSConfig("mirror.example.com", hostConfig)); err == nil {
+		return fmt.Errorf("expected TLS settings of another host not to apply")
+	}
+
+	// Requests go through an explicit proxy instead of the environment's
+	var proxied atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Store(r.URL.String())
+		fmt.Fprint(w, `{"data": {"id": "p1", "attributes": {"namespace": "example", "name": "widget"}}}`)
+	}))
+	defer proxy.Close()
+	client, err := registry.NewClient(registry.WithBaseURL("http://registry.example.invalid"), registry.WithLogger(s.logger),
+		registry.WithProxy(proxy.URL), noRetries)
+	if err != nil {
+		return fmt.Errorf("failed to create proxied client: %w", err)
+	}
+	if _, err := client.Providers.Get(ctx, "example", "widget"); err != nil {
+		return fmt.Errorf("proxied request failed: %w", err)
+	}
+	if err := AssertEqual("http://registry.example.invalid/v2/providers/example/widget", proxied.Load()); err != nil {
+		return fmt.Errorf("proxied URL: %w", err)
+	}
+
+	if _, err := registry.NewClient(registry.WithClientCertificates(certFile, "", "")); !errors.Is(err, registry.ErrInvalidConfiguration) {
+		return fmt.Errorf("expected invalid configuration for a certificate without key, got %v", err)
+	}
+	if _, err := registry.NewClient(registry.WithClientCertificates(certFile, keyFile, filepath.Join(dir, "missing.pem"))); err == nil {
+		return fmt.Errorf("expected an error for a missing CA file")
+	}
+
+	return nil
+}
+
+// newTestCertificate creates a certificate for name signed by parent, or a self-signed
+// CA when parent is nil. Names that are IP addresses are added as IP SANs.
+func newTestCertificate(parent *x509.Certificate, parentKey *ecdsa.PrivateKey, name string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// writeTestPEM writes a certificate or a private key as a PEM file
+func writeTestPEM(path string, cert *x509.Certificate, key *ecdsa.PrivateKey) error {
+	block := &pem.Block{Type: "CERTIFICATE"}
+	if cert != nil {
+		block.Bytes = cert.Raw
+	} else {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	}
+	return os.WriteFile(path, pem.EncodeToMemory(block), 0o600)
+}